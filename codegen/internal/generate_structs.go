@@ -105,6 +105,14 @@ var typePrefixesByPackage = map[string]string{
 	"debrid":                     "Debrid_",
 	"debrid_client":              "DebridClient_",
 	"report":                     "Report_",
+	"trackpreference":            "TrackPreference_",
+	"skipsegments":               "SkipSegments_",
+	"trickplay":                  "Trickplay_",
+	"seadex":                     "SeaDex_",
+	"downloadmanager":            "DownloadManager_",
+	"scheduler":                  "Scheduler_",
+	"notifier":                   "Notifier_",
+	"eventstream":                "EventStream_",
 }
 
 func getTypePrefix(packageName string) string {
@@ -122,7 +130,11 @@ func ExtractStructs(dir string, outDir string) {
 		if err != nil {
 			return err
 		}
-		if !info.IsDir() && strings.HasSuffix(info.Name(), ".go") {
+		// Skip test helper packages, e.g. debridtest
+		if info.IsDir() && isTestHelperPackage(info.Name()) {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() && strings.HasSuffix(info.Name(), ".go") && !strings.HasSuffix(info.Name(), "_test.go") {
 			res, err := getGoStructsFromFile(path, info)
 			if err != nil {
 				return err
@@ -155,6 +167,12 @@ func ExtractStructs(dir string, outDir string) {
 	fmt.Println("Public structs extracted and saved to public_structs.json")
 }
 
+// isTestHelperPackage returns true if the directory is a package providing test fakes, named after the package it fakes.
+// e.g. debridtest, like net/http/httptest
+func isTestHelperPackage(dirName string) bool {
+	return strings.HasSuffix(dirName, "test") && !strings.Contains(dirName, "_")
+}
+
 func getGoStructsFromFile(path string, info os.FileInfo) (structs []*GoStruct, err error) {
 
 	// Parse the Go file
@@ -417,6 +435,13 @@ func goStructFromAlias(path string, info os.FileInfo, genDecl *ast.GenDecl, type
 			valueSpecType := fieldTypeString(valueSpec.Type)
 			if len(valueSpec.Names) == 1 && valueSpec.Names[0].IsExported() && valueSpecType == typeSpec.Name.Name {
 				for _, value := range valueSpec.Values {
+					// Negative numbers, e.g. -1
+					if unary, ok := value.(*ast.UnaryExpr); ok && unary.Op == token.SUB {
+						if name, ok := unary.X.(*ast.BasicLit); ok {
+							goStruct.AliasOf.DeclaredValues = append(goStruct.AliasOf.DeclaredValues, "-"+name.Value)
+						}
+						continue
+					}
 					name, ok := value.(*ast.BasicLit)
 					if !ok {
 						continue
@@ -4,6 +4,8 @@ import (
 	"seanime/internal/api/anilist"
 	"seanime/internal/api/tvdb"
 	"seanime/internal/util/result"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -132,6 +134,26 @@ func (m *AnimeMetadata) GetOffset() int {
 	return firstEp.AbsoluteEpisodeNumber - 1
 }
 
+// GetEpisodesWithPrefix returns the AniDB episodes starting with the prefix, ordered by number.
+// e.g. "S" -> ["S1", "S2", ...], "C" -> ["C1", "C2", ...]
+func (m *AnimeMetadata) GetEpisodesWithPrefix(prefix string) []string {
+	ret := make([]string, 0)
+	if m == nil || m.Episodes == nil {
+		return ret
+	}
+	for ep := range m.Episodes {
+		if n, err := strconv.Atoi(strings.TrimPrefix(ep, prefix)); err == nil && n > 0 && strings.HasPrefix(ep, prefix) {
+			ret = append(ret, ep)
+		}
+	}
+	slices.SortFunc(ret, func(a, b string) int {
+		na, _ := strconv.Atoi(strings.TrimPrefix(a, prefix))
+		nb, _ := strconv.Atoi(strings.TrimPrefix(b, prefix))
+		return na - nb
+	})
+	return ret
+}
+
 func (e *EpisodeMetadata) GetTitle() string {
 	if e == nil {
 		return ""
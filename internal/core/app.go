@@ -12,6 +12,7 @@ import (
 	"seanime/internal/database/models"
 	debrid_client "seanime/internal/debrid/client"
 	discordrpc_presence "seanime/internal/discordrpc/presence"
+	"seanime/internal/downloadmanager"
 	"seanime/internal/events"
 	"seanime/internal/extension_playground"
 	"seanime/internal/extension_repo"
@@ -28,15 +29,18 @@ import (
 	"seanime/internal/mediaplayers/mpv"
 	"seanime/internal/mediaplayers/vlc"
 	"seanime/internal/mediastream"
+	"seanime/internal/mediastream/skipsegments"
 	"seanime/internal/onlinestream"
 	"seanime/internal/platforms/anilist_platform"
 	"seanime/internal/platforms/local_platform"
 	"seanime/internal/platforms/platform"
 	"seanime/internal/report"
+	"seanime/internal/scheduler"
 	sync2 "seanime/internal/sync"
 	"seanime/internal/torrent_clients/torrent_client"
 	"seanime/internal/torrents/torrent"
 	"seanime/internal/torrentstream"
+	"seanime/internal/trackpreference"
 	"seanime/internal/updater"
 	"seanime/internal/util"
 	"seanime/internal/util/filecache"
@@ -68,25 +72,31 @@ type (
 			MpcHc *mpchc.MpcHc
 			Mpv   *mpv.Mpv
 		}
-		MediaPlayerRepository   *mediaplayer.Repository
-		Version                 string
-		Updater                 *updater.Updater
-		Settings                *models.Settings
-		AutoScanner             *autoscanner.AutoScanner
-		PlaybackManager         *playbackmanager.PlaybackManager
-		FileCacher              *filecache.Cacher
-		OnlinestreamRepository  *onlinestream.Repository
-		MangaRepository         *manga.Repository
-		MetadataProvider        metadata.Provider
-		DiscordPresence         *discordrpc_presence.Presence
-		MangaDownloader         *manga.Downloader
-		ContinuityManager       *continuity.Manager
-		Cleanups                []func()
-		OnFlushLogs             func()
-		MediastreamRepository   *mediastream.Repository
-		TorrentstreamRepository *torrentstream.Repository
-		FeatureFlags            FeatureFlags
-		SecondarySettings       struct {
+		MediaPlayerRepository     *mediaplayer.Repository
+		Version                   string
+		Updater                   *updater.Updater
+		Settings                  *models.Settings
+		AutoScanner               *autoscanner.AutoScanner
+		ScanCanceller             *scanner.ScanCanceller // Shared by the scans of the handlers and the auto scanner
+		PlaybackManager           *playbackmanager.PlaybackManager
+		FileCacher                *filecache.Cacher
+		OnlinestreamRepository    *onlinestream.Repository
+		MangaRepository           *manga.Repository
+		MetadataProvider          metadata.Provider
+		DiscordPresence           *discordrpc_presence.Presence
+		MangaDownloader           *manga.Downloader
+		OnlinestreamDownloader    *onlinestream.Downloader
+		ContinuityManager         *continuity.Manager
+		Cleanups                  []func()
+		OnFlushLogs               func()
+		MediastreamRepository     *mediastream.Repository
+		TorrentstreamRepository   *torrentstream.Repository
+		TrackPreferenceRepository *trackpreference.Repository
+		SkipSegmentsManager       *skipsegments.Manager
+		DownloadManager           *downloadmanager.Manager
+		Scheduler                 *scheduler.Scheduler
+		FeatureFlags              FeatureFlags
+		SecondarySettings         struct {
 			Mediastream   *models.MediastreamSettings
 			Torrentstream *models.TorrentstreamSettings
 			Debrid        *models.DebridSettings
@@ -233,6 +243,13 @@ func NewApp(configOpts *ConfigOptions, selfupdater *updater.SelfUpdater) *App {
 		activePlatform = localPlatform
 	}
 
+	// Track Preferences
+	trackPreferenceRepository := trackpreference.NewRepository(&trackpreference.NewRepositoryOptions{
+		Logger:     logger,
+		Database:   database,
+		FileCacher: fileCacher,
+	})
+
 	// Online Stream
 	onlinestreamRepository := onlinestream.NewRepository(&onlinestream.NewRepositoryOptions{
 		Logger:           logger,
@@ -240,6 +257,7 @@ func NewApp(configOpts *ConfigOptions, selfupdater *updater.SelfUpdater) *App {
 		MetadataProvider: activeMetadataProvider,
 		Platform:         activePlatform,
 		Database:         database,
+		TrackPreferences: trackPreferenceRepository,
 	})
 
 	// Extension Repository
@@ -267,6 +285,7 @@ func NewApp(configOpts *ConfigOptions, selfupdater *updater.SelfUpdater) *App {
 		Updater:                       updater.New(constants.Version, logger, wsEventManager),
 		FileCacher:                    fileCacher,
 		OnlinestreamRepository:        onlinestreamRepository,
+		TrackPreferenceRepository:     trackPreferenceRepository,
 		MetadataProvider:              activeMetadataProvider,
 		MangaRepository:               mangaRepository,
 		ExtensionRepository:           extensionRepository,
@@ -275,10 +294,15 @@ func NewApp(configOpts *ConfigOptions, selfupdater *updater.SelfUpdater) *App {
 		TorrentRepository:             nil, // Initialized in App.initModulesOnce
 		FillerManager:                 nil, // Initialized in App.initModulesOnce
 		MangaDownloader:               nil, // Initialized in App.initModulesOnce
+		OnlinestreamDownloader:        nil, // Initialized in App.initModulesOnce
 		PlaybackManager:               nil, // Initialized in App.initModulesOnce
 		AutoDownloader:                nil, // Initialized in App.initModulesOnce
 		AutoScanner:                   nil, // Initialized in App.initModulesOnce
+		ScanCanceller:                 scanner.NewScanCanceller(),
 		MediastreamRepository:         nil, // Initialized in App.initModulesOnce
+		SkipSegmentsManager:           nil, // Initialized in App.initModulesOnce
+		DownloadManager:               nil, // Initialized in App.initModulesOnce
+		Scheduler:                     nil, // Initialized in App.initModulesOnce
 		TorrentstreamRepository:       nil, // Initialized in App.initModulesOnce
 		ContinuityManager:             nil, // Initialized in App.initModulesOnce
 		DebridClientRepository:        nil, // Initialized in App.initModulesOnce
@@ -43,17 +43,15 @@ func TrimLogEntries(dir string, logger *zerolog.Logger) {
 	}
 
 	var serverLogFiles []os.FileInfo
-	var scanLogFiles []os.FileInfo
 
+	// Scan logs are not purged by age, the number of scan logs kept is set by the user (see scanner.CleanUpScanLogs)
 	for _, file := range files {
 		if strings.HasPrefix(file.Name(), "seanime-") {
 			serverLogFiles = append(serverLogFiles, file)
-		} else if strings.Contains(file.Name(), "-scan") {
-			scanLogFiles = append(scanLogFiles, file)
 		}
 	}
 
-	for _, _files := range [][]os.FileInfo{serverLogFiles, scanLogFiles} {
+	for _, _files := range [][]os.FileInfo{serverLogFiles} {
 		files := _files
 		if len(files) <= 1 {
 			continue
@@ -1,14 +1,20 @@
 package core
 
 import (
+	"path/filepath"
 	"runtime"
 	"seanime/internal/api/anilist"
 	"seanime/internal/continuity"
+	"seanime/internal/database/db_bridge"
 	"seanime/internal/database/models"
 	debrid_client "seanime/internal/debrid/client"
 	discordrpc_presence "seanime/internal/discordrpc/presence"
+	"seanime/internal/downloadmanager"
+	"seanime/internal/eventstream"
+	"seanime/internal/library/anime"
 	"seanime/internal/library/autodownloader"
 	"seanime/internal/library/autoscanner"
+	"seanime/internal/library/filesystem"
 	"seanime/internal/library/fillermanager"
 	"seanime/internal/library/playbackmanager"
 	"seanime/internal/manga"
@@ -17,7 +23,10 @@ import (
 	"seanime/internal/mediaplayers/mpv"
 	"seanime/internal/mediaplayers/vlc"
 	"seanime/internal/mediastream"
+	"seanime/internal/mediastream/skipsegments"
 	"seanime/internal/notifier"
+	"seanime/internal/onlinestream"
+	"seanime/internal/scheduler"
 	"seanime/internal/torrent_clients/qbittorrent"
 	"seanime/internal/torrent_clients/torrent_client"
 	"seanime/internal/torrent_clients/transmission"
@@ -37,6 +46,13 @@ func (a *App) initModulesOnce() {
 		_, _ = a.RefreshMangaCollection()
 	})
 
+	// +---------------------+
+	// |    Notifications    |
+	// +---------------------+
+
+	notifier.GlobalDispatcher.SetDatabase(a.Database, a.Logger)
+	eventstream.GlobalBroker.SetDatabase(a.Database, a.Logger)
+
 	// +---------------------+
 	// |     Discord RPC     |
 	// +---------------------+
@@ -138,6 +154,8 @@ func (a *App) initModulesOnce() {
 		AutoDownloader:   a.AutoDownloader,
 		MetadataProvider: a.MetadataProvider,
 		LogsDir:          a.Config.Logs.Dir,
+		OnScanned:        a.OnLibraryScanned,
+		ScanCanceller:    a.ScanCanceller,
 	})
 
 	// This is run in a goroutine
@@ -161,19 +179,47 @@ func (a *App) initModulesOnce() {
 	}
 
 	// +---------------------+
-	// |    Media Stream     |
+	// |  Online Downloader  |
 	// +---------------------+
 
-	a.MediastreamRepository = mediastream.NewRepository(&mediastream.NewRepositoryOptions{
+	a.OnlinestreamDownloader = onlinestream.NewDownloader(&onlinestream.NewDownloaderOptions{
 		Logger:         a.Logger,
 		WSEventManager: a.WSEventManager,
-		FileCacher:     a.FileCacher,
+		Database:       a.Database,
+		Repository:     a.OnlinestreamRepository,
+		TempDir:        filepath.Join(a.Config.Cache.Dir, "onlinestream-downloads"),
+		OnEpisodeDownloaded: func(path string) {
+			a.AutoScanner.RunTargeted([]string{path})
+		},
+	})
+
+	if !a.IsOffline() {
+		// This is run in a goroutine
+		a.OnlinestreamDownloader.Start()
+	}
+
+	// +---------------------+
+	// |    Media Stream     |
+	// +---------------------+
+
+	a.MediastreamRepository = mediastream.NewRepository(&mediastream.NewRepositoryOptions{
+		Logger:           a.Logger,
+		WSEventManager:   a.WSEventManager,
+		FileCacher:       a.FileCacher,
+		TrackPreferences: a.TrackPreferenceRepository,
 	})
 
 	a.AddCleanupFunction(func() {
 		a.MediastreamRepository.OnCleanup()
 	})
 
+	a.SkipSegmentsManager = skipsegments.NewManager(&skipsegments.NewManagerOptions{
+		Logger:         a.Logger,
+		Database:       a.Database,
+		FileCacher:     a.FileCacher,
+		WSEventManager: a.WSEventManager,
+	})
+
 	// +---------------------+
 	// |   Torrent Stream    |
 	// +---------------------+
@@ -188,6 +234,52 @@ func (a *App) initModulesOnce() {
 		PlaybackManager:    a.PlaybackManager,
 		WSEventManager:     a.WSEventManager,
 		Database:           a.Database,
+		ScanPathsFunc: func(paths []string) {
+			a.AutoScanner.RunTargeted(paths)
+		},
+	})
+
+	// +---------------------+
+	// |  Download Manager   |
+	// +---------------------+
+
+	a.DownloadManager = downloadmanager.NewManager(&downloadmanager.NewManagerOptions{
+		Logger:         a.Logger,
+		WSEventManager: a.WSEventManager,
+		Database:       a.Database,
+		Sources: []downloadmanager.Source{
+			// The torrent client repository is recreated when the settings change
+			downloadmanager.NewTorrentClientSource(func() *torrent_client.Repository {
+				return a.TorrentClientRepository
+			}),
+			downloadmanager.NewDebridSource(a.DebridClientRepository),
+			downloadmanager.NewTorrentstreamSource(a.TorrentstreamRepository),
+			downloadmanager.NewMangaSource(a.MangaDownloader, a.Database),
+			downloadmanager.NewOnlinestreamSource(a.OnlinestreamDownloader),
+		},
+	})
+
+	if !a.IsOffline() {
+		a.DownloadManager.Start()
+	}
+
+	a.AddCleanupFunction(func() {
+		a.DownloadManager.Shutdown()
+	})
+
+	// +---------------------+
+	// |      Scheduler      |
+	// +---------------------+
+
+	// The jobs are registered in cron.RunJobs
+	a.Scheduler = scheduler.NewScheduler(&scheduler.NewSchedulerOptions{
+		Logger:         a.Logger,
+		Database:       a.Database,
+		WSEventManager: a.WSEventManager,
+	})
+
+	a.AddCleanupFunction(func() {
+		a.Scheduler.Stop()
 	})
 
 }
@@ -272,6 +364,8 @@ func (a *App) InitOrRefreshModules() {
 			Mpv:               a.MediaPlayer.Mpv, // Socket
 			WSEventManager:    a.WSEventManager,
 			ContinuityManager: a.ContinuityManager,
+			TrackPreferences:  a.TrackPreferenceRepository,
+			SkipSegments:      a.SkipSegmentsManager,
 		})
 
 		a.PlaybackManager.SetMediaPlayerRepository(a.MediaPlayerRepository)
@@ -360,7 +454,12 @@ func (a *App) InitOrRefreshModules() {
 	// Initialize library watcher
 	if settings.Library != nil && len(settings.Library.LibraryPath) > 0 {
 		go func() {
-			a.initLibraryWatcher(settings.Library.GetLibraryPaths())
+			// Disabled and offline library directories are not watched
+			var localFilePaths []string
+			if lfs, _, err := db_bridge.GetLocalFiles(a.Database); err == nil {
+				localFilePaths = anime.GetLocalFilePaths(lfs)
+			}
+			a.initLibraryWatcher(filesystem.GetOnlineLibraryPaths(settings.Library, localFilePaths))
 		}()
 	}
 
@@ -417,8 +516,8 @@ func (a *App) InitOrRefreshMediastreamSettings() {
 
 	// Cleanup cache
 	go func() {
-		if settings.TranscodeEnabled {
-			// If transcoding is enabled, trim files
+		if settings.TranscodeEnabled || settings.TrickplayEnabled {
+			// If transcoding or seek previews are enabled, trim files
 			_ = a.FileCacher.TrimMediastreamVideoFiles()
 		} else {
 			// If transcoding is disabled, clear all files
@@ -593,3 +692,18 @@ func (a *App) performActionsOnce() {
 	}()
 
 }
+
+// OnLibraryScanned is called after a scan has saved the local files.
+// It notifies the user and publishes the scan to the event stream.
+// Seek preview thumbnails are not generated for the new files, they are generated when a file is played.
+func (a *App) OnLibraryScanned(previous []*anime.LocalFile, current []*anime.LocalFile) {
+	unmatched := len(anime.NewLocalFileWrapper(current).GetUnmatchedLocalFiles())
+	notifier.GlobalDispatcher.Dispatch(notifier.EventScanFinished, map[string]interface{}{
+		"files":     len(current),
+		"unmatched": unmatched,
+	})
+	eventstream.GlobalBroker.Publish(eventstream.TypeLibraryScanCompleted, &eventstream.LibraryScanCompletedPayload{
+		Files:     len(current),
+		Unmatched: unmatched,
+	})
+}
@@ -52,9 +52,9 @@ func (a *App) initLibraryWatcher(paths []string) {
 
 	// Start watching
 	a.Watcher.StartWatching(
-		func() {
+		func(action *scanner.FileAction) {
 			// Notify the auto scanner when a file action occurs
-			a.AutoScanner.Notify()
+			a.AutoScanner.NotifyFileAction(action)
 		})
 
 }
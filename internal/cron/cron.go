@@ -1,53 +1,136 @@
 package cron
 
 import (
+	"context"
+	"fmt"
 	"seanime/internal/core"
-	"time"
+	"seanime/internal/database/models"
+	"seanime/internal/scheduler"
+)
+
+const (
+	JobRefreshAnilist  = "refresh-anilist"
+	JobSyncLocalData   = "sync-local-data"
+	JobCheckForUpdates = "check-for-updates"
+	JobAutoDownloader  = "auto-downloader"
+	JobLibraryRescan   = "library-rescan"
+	JobTrimCache       = "trim-cache"
 )
 
 type JobCtx struct {
 	App *core.App
+	// nextAiringEpisodes maps media IDs to the next airing episode seen during the last refresh.
+	nextAiringEpisodes map[int]int
+	// notifiedVersion is the last version an update notification was sent for.
+	notifiedVersion string
 }
 
+// RunJobs registers the jobs and starts the scheduler.
+// Jobs that need a connection are skipped while the app is offline.
 func RunJobs(app *core.App) {
 
-	// Run the jobs only if the server is online
-	if !app.IsOffline() {
+	ctx := &JobCtx{
+		App: app,
+	}
+
+	jobs := []*scheduler.Job{
+		{
+			Name:            JobRefreshAnilist,
+			Description:     "Refresh the AniList anime and manga collections",
+			DefaultSchedule: "@every 10m",
+			DefaultEnabled:  true,
+			Run:             ctx.wrap(RefreshAnilistDataJob),
+		},
+		{
+			Name:            JobSyncLocalData,
+			Description:     "Synchronize the offline data with AniList",
+			DefaultSchedule: "@every 30m",
+			DefaultEnabled:  true,
+			Run:             ctx.wrap(SyncLocalDataJob),
+		},
+		{
+			Name:            JobCheckForUpdates,
+			Description:     "Check for new Seanime releases",
+			DefaultSchedule: "@every 1h",
+			DefaultEnabled:  true,
+			Run:             ctx.wrap(CheckForUpdatesJob),
+		},
+		{
+			Name:            JobAutoDownloader,
+			Description:     "Look for new episodes matching the Auto Downloader rules",
+			DefaultSchedule: AutoDownloaderSchedule(ctx.autoDownloaderInterval()),
+			DefaultEnabled:  true,
+			Run:             ctx.wrap(AutoDownloaderJob),
+		},
+		{
+			Name:            JobLibraryRescan,
+			Description:     "Rescan the whole library",
+			DefaultSchedule: "0 4 * * *",
+			DefaultEnabled:  false,
+			Run:             ctx.wrap(LibraryRescanJob),
+		},
+		{
+			Name:            JobTrimCache,
+			Description:     "Remove expired cache entries, old video file caches and old history entries",
+			DefaultSchedule: "0 3 * * *",
+			DefaultEnabled:  true,
+			Run:             ctx.wrap(TrimCacheJob),
+		},
+	}
 
-		ctx := &JobCtx{
-			App: app,
+	for _, job := range jobs {
+		if err := app.Scheduler.Register(job); err != nil {
+			app.Logger.Error().Err(err).Msg("cron: Failed to register job")
 		}
+	}
+
+	app.Scheduler.Start()
+}
+
+// AutoDownloaderSchedule returns the schedule matching the Auto Downloader interval (minutes).
+func AutoDownloaderSchedule(interval int) string {
+	return fmt.Sprintf("@every %dm", interval)
+}
+
+// AutoDownloaderInterval returns the interval of the Auto Downloader (minutes).
+// The default interval is used if the user-defined one is less than 15.
+func AutoDownloaderInterval(settings *models.AutoDownloaderSettings) int {
+	interval := 20
+	if settings != nil && settings.Interval >= 15 {
+		interval = settings.Interval
+	}
+	return interval
+}
+
+// UpdateAutoDownloaderSchedule changes the schedule of the Auto Downloader job after its interval changed.
+// The schedule is kept if the user changed it, i.e. if it's no longer the one derived from the previous interval.
+func UpdateAutoDownloaderSchedule(s *scheduler.Scheduler, prevInterval int, interval int) error {
+	job, err := s.GetJob(JobAutoDownloader)
+	if err != nil {
+		return err
+	}
+	if job.Schedule != "" && job.Schedule != AutoDownloaderSchedule(prevInterval) {
+		return nil
+	}
+	return s.SetJobSchedule(JobAutoDownloader, AutoDownloaderSchedule(interval))
+}
 
-		refreshAnilistTicker := time.NewTicker(10 * time.Minute)
-		refreshLocalDataTicker := time.NewTicker(30 * time.Minute)
-		refetchReleaseTicker := time.NewTicker(1 * time.Hour)
-
-		go func() {
-			for {
-				select {
-				case <-refreshAnilistTicker.C:
-					RefreshAnilistDataJob(ctx)
-				}
-			}
-		}()
-
-		go func() {
-			for {
-				select {
-				case <-refreshLocalDataTicker.C:
-					SyncLocalDataJob(ctx)
-				}
-			}
-		}()
-
-		go func() {
-			for {
-				select {
-				case <-refetchReleaseTicker.C:
-					app.Updater.ShouldRefetchReleases()
-				}
-			}
-		}()
+func (c *JobCtx) wrap(f func(c *JobCtx) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return f(c)
+	}
+}
+
+func (c *JobCtx) autoDownloaderInterval() int {
+	if c.App.Settings == nil {
+		return AutoDownloaderInterval(nil)
+	}
+	return AutoDownloaderInterval(c.App.Settings.AutoDownloader)
+}
 
+func (c *JobCtx) requireOnline() error {
+	if c.App.IsOffline() {
+		return fmt.Errorf("offline mode: %w", scheduler.ErrSkipped)
 	}
+	return nil
 }
@@ -0,0 +1,47 @@
+package cron
+
+import (
+	"fmt"
+	"seanime/internal/scheduler"
+)
+
+func AutoDownloaderJob(c *JobCtx) error {
+	if err := c.requireOnline(); err != nil {
+		return err
+	}
+
+	if c.App.Settings == nil || c.App.Settings.AutoDownloader == nil || !c.App.Settings.AutoDownloader.Enabled {
+		return fmt.Errorf("auto downloader is disabled: %w", scheduler.ErrDisabled)
+	}
+
+	c.App.AutoDownloader.CheckForNewEpisodes()
+	return nil
+}
+
+func LibraryRescanJob(c *JobCtx) error {
+	if c.App.Settings == nil || c.App.Settings.Library == nil || c.App.Settings.Library.LibraryPath == "" {
+		return fmt.Errorf("library path is not set: %w", scheduler.ErrSkipped)
+	}
+
+	c.App.AutoScanner.RunNow()
+	return nil
+}
+
+func TrimCacheJob(c *JobCtx) error {
+	removed, err := c.App.FileCacher.RemoveExpired()
+	if err != nil {
+		return err
+	}
+	c.App.Logger.Debug().Int("removed", removed).Msg("cron: Removed expired cache entries")
+
+	if settings := c.App.SecondarySettings.Mediastream; settings != nil && (settings.TranscodeEnabled || settings.TrickplayEnabled) {
+		if err := c.App.FileCacher.TrimMediastreamVideoFiles(); err != nil {
+			return err
+		}
+	}
+
+	c.App.Database.TrimScanSummaryEntries()
+	c.App.Database.TrimTorrentstreamHistory()
+
+	return nil
+}
@@ -1,39 +1,115 @@
 package cron
 
 import (
+	"fmt"
+	"seanime/internal/api/anilist"
 	"seanime/internal/events"
+	"seanime/internal/notifier"
+	"seanime/internal/scheduler"
 )
 
-func RefreshAnilistDataJob(c *JobCtx) {
-	defer func() {
-		if r := recover(); r != nil {
-		}
-	}()
+func RefreshAnilistDataJob(c *JobCtx) error {
+	if err := c.requireOnline(); err != nil {
+		return err
+	}
 
 	if c.App.Settings == nil || c.App.Settings.Library == nil {
-		return
+		return fmt.Errorf("settings not initialized: %w", scheduler.ErrSkipped)
 	}
 
 	// Refresh the Anilist Collection
-	animeCollection, _ := c.App.RefreshAnimeCollection()
+	animeCollection, err := c.App.RefreshAnimeCollection()
+	if err != nil {
+		return err
+	}
 
 	if c.App.Settings.Library.EnableManga {
-		mangaCollection, _ := c.App.RefreshMangaCollection()
+		mangaCollection, err := c.App.RefreshMangaCollection()
+		if err != nil {
+			return err
+		}
 		c.App.WSEventManager.SendEvent(events.RefreshedAnilistMangaCollection, mangaCollection)
 	}
 
 	c.App.WSEventManager.SendEvent(events.RefreshedAnilistAnimeCollection, animeCollection)
+
+	c.notifyAiredEpisodes(animeCollection)
+	return nil
 }
 
-func SyncLocalDataJob(c *JobCtx) {
-	defer func() {
-		if r := recover(); r != nil {
+// notifyAiredEpisodes compares the next airing episodes with the ones seen during the last refresh
+// and sends a notification for each anime whose next airing episode moved forward.
+func (c *JobCtx) notifyAiredEpisodes(animeCollection *anilist.AnimeCollection) {
+	if animeCollection == nil {
+		return
+	}
+
+	nextAiringEpisodes := make(map[int]int)
+	for _, media := range animeCollection.GetAllAnime() {
+		if media.GetNextAiringEpisode() == nil {
+			continue
 		}
-	}()
+		nextAiringEpisodes[media.GetID()] = media.GetNextAiringEpisode().GetEpisode()
+	}
+
+	// Skip the first refresh, there's nothing to compare against
+	if c.nextAiringEpisodes != nil {
+		for _, media := range animeCollection.GetAllAnime() {
+			prev, found := c.nextAiringEpisodes[media.GetID()]
+			if !found {
+				continue
+			}
+			// The next episode moved forward, or the anime finished airing with the last episode
+			next, ok := nextAiringEpisodes[media.GetID()]
+			if !ok && media.GetStatus() != nil && *media.GetStatus() == anilist.MediaStatusFinished {
+				next = prev + 1
+			}
+			if next <= prev {
+				continue
+			}
+			notifier.GlobalDispatcher.Dispatch(notifier.EventEpisodeAired, map[string]interface{}{
+				"media":   media.GetPreferredTitle(),
+				"mediaId": media.GetID(),
+				"episode": next - 1,
+			})
+		}
+	}
+
+	c.nextAiringEpisodes = nextAiringEpisodes
+}
+
+func SyncLocalDataJob(c *JobCtx) error {
+	if err := c.requireOnline(); err != nil {
+		return err
+	}
 
 	if c.App.Settings == nil || c.App.Settings.Library == nil || !c.App.Settings.Library.AutoSyncOfflineLocalData {
-		return
+		return fmt.Errorf("automatic synchronization is disabled: %w", scheduler.ErrSkipped)
+	}
+
+	return c.App.SyncManager.SynchronizeLocal()
+}
+
+func CheckForUpdatesJob(c *JobCtx) error {
+	if err := c.requireOnline(); err != nil {
+		return err
+	}
+
+	c.App.Updater.ShouldRefetchReleases()
+
+	update, err := c.App.Updater.GetLatestUpdate()
+	if err != nil {
+		return err
+	}
+
+	// Only notify once per version
+	if update != nil && update.Release != nil && update.Release.Version != c.notifiedVersion {
+		c.notifiedVersion = update.Release.Version
+		notifier.GlobalDispatcher.Dispatch(notifier.EventUpdateAvailable, map[string]interface{}{
+			"version":        update.Release.Version,
+			"currentVersion": update.CurrentVersion,
+		})
 	}
 
-	_ = c.App.SyncManager.SynchronizeLocal()
+	return nil
 }
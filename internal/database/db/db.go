@@ -83,12 +83,23 @@ func migrateTables(db *gorm.DB) error {
 		&models.ChapterDownloadQueueItem{},
 		&models.TorrentstreamSettings{},
 		&models.TorrentstreamHistory{},
+		&models.TorrentstreamImport{},
 		&models.MediastreamSettings{},
 		&models.MediaFiller{},
 		&models.MangaMapping{},
 		&models.OnlinestreamMapping{},
 		&models.DebridSettings{},
 		&models.DebridTorrentItem{},
+		&models.TrackPreferenceSettings{},
+		&models.MediaTrackPreference{},
+		&models.DownloadManagerSettings{},
+		&models.ScheduledJob{},
+		&models.ScheduledJobRun{},
+		&models.NotificationChannel{},
+		&models.EventWebhook{},
+		&models.MatchingRule{},
+		&models.FileHash{},
+		&models.FileMediaInfo{},
 		//&models.MangaChapterContainer{},
 	)
 	if err != nil {
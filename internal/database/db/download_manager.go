@@ -0,0 +1,42 @@
+package db
+
+import (
+	"seanime/internal/database/models"
+
+	"gorm.io/gorm/clause"
+)
+
+var CurrDownloadManagerSettings *models.DownloadManagerSettings
+
+func (db *Database) UpsertDownloadManagerSettings(settings *models.DownloadManagerSettings) (*models.DownloadManagerSettings, error) {
+
+	err := db.gormdb.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		UpdateAll: true,
+	}).Create(settings).Error
+
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to save download manager settings in the database")
+		return nil, err
+	}
+
+	CurrDownloadManagerSettings = settings
+
+	db.Logger.Debug().Msg("db: Download manager settings saved")
+	return settings, nil
+}
+
+func (db *Database) GetDownloadManagerSettings() (*models.DownloadManagerSettings, bool) {
+
+	if CurrDownloadManagerSettings != nil {
+		return CurrDownloadManagerSettings, true
+	}
+
+	var settings models.DownloadManagerSettings
+	err := db.gormdb.Where("id = ?", 1).First(&settings).Error
+
+	if err != nil {
+		return nil, false
+	}
+	return &settings, true
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"seanime/internal/database/models"
+	"time"
+)
+
+func (db *Database) GetEventWebhooks() ([]*models.EventWebhook, error) {
+	var res []*models.EventWebhook
+	err := db.gormdb.Order("id ASC").Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SaveEventWebhook creates the webhook if its ID is 0, otherwise it updates it.
+// When updating, the stored secret is kept if the secret is empty and the delivery status is never overwritten.
+// The webhook is reloaded with the stored values.
+func (db *Database) SaveEventWebhook(webhook *models.EventWebhook) error {
+	if webhook.ID == 0 {
+		return db.gormdb.Create(webhook).Error
+	}
+
+	columns := []string{"url", "enabled", "events"}
+	if webhook.Secret != "" {
+		columns = append(columns, "secret")
+	}
+	err := db.gormdb.Model(&models.EventWebhook{BaseModel: models.BaseModel{ID: webhook.ID}}).Select(columns).Updates(webhook).Error
+	if err != nil {
+		return err
+	}
+
+	return db.gormdb.First(webhook, webhook.ID).Error
+}
+
+func (db *Database) DeleteEventWebhook(id uint) error {
+	return db.gormdb.Delete(&models.EventWebhook{}, id).Error
+}
+
+// UpdateEventWebhookDelivery stores the result of the last delivery attempt.
+func (db *Database) UpdateEventWebhookDelivery(id uint, at time.Time, status int, deliveryErr string) error {
+	return db.gormdb.Model(&models.EventWebhook{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_delivery_at":     at,
+		"last_delivery_status": status,
+		"last_delivery_error":  deliveryErr,
+	}).Error
+}
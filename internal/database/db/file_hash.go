@@ -0,0 +1,48 @@
+package db
+
+import (
+	"seanime/internal/database/models"
+
+	"github.com/samber/lo"
+	"gorm.io/gorm/clause"
+)
+
+func (db *Database) GetFileHashes() ([]*models.FileHash, error) {
+	var res []*models.FileHash
+	err := db.gormdb.Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SaveFileHashes creates or replaces the hashes of the files.
+func (db *Database) SaveFileHashes(hashes []*models.FileHash) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	err := db.gormdb.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "path"}},
+		DoUpdates: clause.AssignmentColumns([]string{"size", "mod_time", "crc32", "ed2k", "updated_at"}),
+	}).CreateInBatches(hashes, 100).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to save file hashes")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteFileHashes removes the file hashes of the files.
+func (db *Database) DeleteFileHashes(paths []string) error {
+	for _, batch := range lo.Chunk(paths, 500) {
+		err := db.gormdb.Where("path IN ?", batch).Delete(&models.FileHash{}).Error
+		if err != nil {
+			db.Logger.Error().Err(err).Msg("db: Failed to delete file hashes")
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"seanime/internal/database/models"
+
+	"github.com/samber/lo"
+	"gorm.io/gorm/clause"
+)
+
+func (db *Database) GetFileMediaInfos() ([]*models.FileMediaInfo, error) {
+	var res []*models.FileMediaInfo
+	err := db.gormdb.Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SaveFileMediaInfos creates or replaces the media info of the files.
+func (db *Database) SaveFileMediaInfos(infos []*models.FileMediaInfo) error {
+	if len(infos) == 0 {
+		return nil
+	}
+	err := db.gormdb.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "path"}},
+		DoUpdates: clause.AssignmentColumns([]string{"size", "mod_time", "value", "updated_at"}),
+	}).CreateInBatches(infos, 100).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to save file media info")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteFileMediaInfos removes the file media info of the files.
+func (db *Database) DeleteFileMediaInfos(paths []string) error {
+	for _, batch := range lo.Chunk(paths, 500) {
+		err := db.gormdb.Where("path IN ?", batch).Delete(&models.FileMediaInfo{}).Error
+		if err != nil {
+			db.Logger.Error().Err(err).Msg("db: Failed to delete file media info")
+			return err
+		}
+	}
+
+	return nil
+}
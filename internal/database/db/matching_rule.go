@@ -0,0 +1,40 @@
+package db
+
+import (
+	"seanime/internal/database/models"
+)
+
+func (db *Database) GetMatchingRules() ([]*models.MatchingRule, error) {
+	var res []*models.MatchingRule
+	err := db.gormdb.Order("id ASC").Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SaveMatchingRule creates the rule if its ID is 0, otherwise it updates it.
+func (db *Database) SaveMatchingRule(rule *models.MatchingRule) error {
+	return db.gormdb.Save(rule).Error
+}
+
+// SaveLearnedMatchingRule updates the rule with the same type, pattern and release group, or creates it.
+func (db *Database) SaveLearnedMatchingRule(rule *models.MatchingRule) error {
+	var existing models.MatchingRule
+	err := db.gormdb.
+		Where("type = ? AND pattern = ? AND release_group = ?", rule.Type, rule.Pattern, rule.ReleaseGroup).
+		Limit(1).
+		Find(&existing).Error
+	if err != nil {
+		return err
+	}
+	if existing.ID != 0 {
+		rule.BaseModel = existing.BaseModel
+	}
+	return db.gormdb.Save(rule).Error
+}
+
+func (db *Database) DeleteMatchingRule(id uint) error {
+	return db.gormdb.Delete(&models.MatchingRule{}, id).Error
+}
@@ -0,0 +1,24 @@
+package db
+
+import (
+	"seanime/internal/database/models"
+)
+
+func (db *Database) GetNotificationChannels() ([]*models.NotificationChannel, error) {
+	var res []*models.NotificationChannel
+	err := db.gormdb.Order("id ASC").Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SaveNotificationChannel creates the channel if its ID is 0, otherwise it updates it.
+func (db *Database) SaveNotificationChannel(channel *models.NotificationChannel) error {
+	return db.gormdb.Save(channel).Error
+}
+
+func (db *Database) DeleteNotificationChannel(id uint) error {
+	return db.gormdb.Delete(&models.NotificationChannel{}, id).Error
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"seanime/internal/database/models"
+
+	"gorm.io/gorm/clause"
+)
+
+func (db *Database) GetScheduledJob(name string) (*models.ScheduledJob, error) {
+	var res models.ScheduledJob
+	err := db.gormdb.Where("name = ?", name).First(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (db *Database) UpsertScheduledJob(job *models.ScheduledJob) error {
+	err := db.gormdb.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"schedule", "enabled", "updated_at"}),
+	}).Create(job).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to save scheduled job")
+		return err
+	}
+
+	return nil
+}
+
+func (db *Database) InsertScheduledJobRun(run *models.ScheduledJobRun) error {
+	return db.gormdb.Create(run).Error
+}
+
+// GetScheduledJobRuns returns the most recent runs of a job, newest first.
+func (db *Database) GetScheduledJobRuns(jobName string, limit int) ([]*models.ScheduledJobRun, error) {
+	var res []*models.ScheduledJobRun
+	err := db.gormdb.Where("job_name = ?", jobName).Order("id DESC").Limit(limit).Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// TrimScheduledJobRuns keeps the most recent runs of a job.
+func (db *Database) TrimScheduledJobRuns(jobName string, keep int) {
+	err := db.gormdb.Where("job_name = ? AND id NOT IN (SELECT id FROM scheduled_job_runs WHERE job_name = ? ORDER BY id DESC LIMIT ?)", jobName, jobName, keep).
+		Delete(&models.ScheduledJobRun{}).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to delete old scheduled job runs")
+	}
+}
@@ -0,0 +1,26 @@
+package db
+
+import (
+	"seanime/internal/database/models"
+)
+
+// SaveTorrentstreamImport saves the pending import, replacing the previous one of the same torrent.
+func (db *Database) SaveTorrentstreamImport(item *models.TorrentstreamImport) error {
+	if err := db.DeleteTorrentstreamImport(item.InfoHash); err != nil {
+		return err
+	}
+	return db.gormdb.Create(item).Error
+}
+
+func (db *Database) GetTorrentstreamImports() ([]*models.TorrentstreamImport, error) {
+	var res []*models.TorrentstreamImport
+	err := db.gormdb.Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (db *Database) DeleteTorrentstreamImport(infoHash string) error {
+	return db.gormdb.Where("info_hash = ?", infoHash).Delete(&models.TorrentstreamImport{}).Error
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"seanime/internal/database/models"
+	"seanime/internal/util/result"
+
+	"gorm.io/gorm/clause"
+)
+
+var CurrTrackPreferenceSettings *models.TrackPreferenceSettings
+
+func (db *Database) UpsertTrackPreferenceSettings(settings *models.TrackPreferenceSettings) (*models.TrackPreferenceSettings, error) {
+
+	err := db.gormdb.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		UpdateAll: true,
+	}).Create(settings).Error
+
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to save track preference settings in the database")
+		return nil, err
+	}
+
+	CurrTrackPreferenceSettings = settings
+
+	db.Logger.Debug().Msg("db: Track preference settings saved")
+	return settings, nil
+}
+
+func (db *Database) GetTrackPreferenceSettings() (*models.TrackPreferenceSettings, bool) {
+
+	if CurrTrackPreferenceSettings != nil {
+		return CurrTrackPreferenceSettings, true
+	}
+
+	var settings models.TrackPreferenceSettings
+	err := db.gormdb.Where("id = ?", 1).First(&settings).Error
+
+	if err != nil {
+		return nil, false
+	}
+	return &settings, true
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+var mediaTrackPreferenceCache = result.NewResultMap[int, *models.MediaTrackPreference]()
+
+func (db *Database) GetMediaTrackPreference(mediaId int) (*models.MediaTrackPreference, bool) {
+
+	if res, ok := mediaTrackPreferenceCache.Get(mediaId); ok {
+		return res, res != nil
+	}
+
+	var res models.MediaTrackPreference
+	err := db.gormdb.Where("media_id = ?", mediaId).First(&res).Error
+	if err != nil {
+		// Cache the miss, most media won't have an override
+		mediaTrackPreferenceCache.Set(mediaId, nil)
+		return nil, false
+	}
+
+	mediaTrackPreferenceCache.Set(mediaId, &res)
+
+	return &res, true
+}
+
+func (db *Database) UpsertMediaTrackPreference(pref *models.MediaTrackPreference) (*models.MediaTrackPreference, error) {
+
+	err := db.gormdb.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "media_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "audio_languages", "subtitle_languages", "signs_and_songs"}),
+	}).Create(pref).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to save media track preference in the database")
+		return nil, err
+	}
+
+	mediaTrackPreferenceCache.Delete(pref.MediaId)
+
+	return pref, nil
+}
+
+func (db *Database) DeleteMediaTrackPreference(mediaId int) error {
+	err := db.gormdb.Where("media_id = ?", mediaId).Delete(&models.MediaTrackPreference{}).Error
+	if err != nil {
+		return err
+	}
+
+	mediaTrackPreferenceCache.Delete(mediaId)
+	return nil
+}
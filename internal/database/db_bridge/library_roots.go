@@ -0,0 +1,23 @@
+package db_bridge
+
+import (
+	"seanime/internal/database/db"
+	"seanime/internal/library/anime"
+	"seanime/internal/library/filesystem"
+)
+
+// ResolveDownloadDestination returns the directory files are downloaded to, using the library settings and local files.
+// If destination is empty, the default download destination is returned, see filesystem.ResolveDownloadDestination.
+func ResolveDownloadDestination(db *db.Database, destination string) (string, error) {
+	settings, err := db.GetSettings()
+	if err != nil {
+		return "", err
+	}
+
+	var localFilePaths []string
+	if lfs, _, err := GetLocalFiles(db); err == nil {
+		localFilePaths = anime.GetLocalFilePaths(lfs)
+	}
+
+	return filesystem.ResolveDownloadDestination(settings.Library, destination, localFilePaths)
+}
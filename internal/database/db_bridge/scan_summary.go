@@ -1,6 +1,7 @@
 package db_bridge
 
 import (
+	"errors"
 	"github.com/goccy/go-json"
 	"seanime/internal/database/db"
 	"seanime/internal/database/models"
@@ -47,3 +48,34 @@ func InsertScanSummary(db *db.Database, sm *summary.ScanSummary) error {
 		Value: bytes,
 	}).Error
 }
+
+// GetScanSummary returns the scan summary with the given ID along with the ID of its entry.
+func GetScanSummary(database *db.Database, id string) (uint, *summary.ScanSummary, error) {
+	var res []*models.ScanSummary
+	err := database.Gorm().Order("id DESC").Find(&res).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for _, r := range res {
+		var sm summary.ScanSummary
+		if err := json.Unmarshal(r.Value, &sm); err != nil {
+			return 0, nil, err
+		}
+		if sm.ID == id {
+			return r.ID, &sm, nil
+		}
+	}
+
+	return 0, nil, errors.New("scan summary not found")
+}
+
+func UpdateScanSummary(db *db.Database, entryId uint, sm *summary.ScanSummary) error {
+	// Marshal the data
+	bytes, err := json.Marshal(sm)
+	if err != nil {
+		return err
+	}
+
+	return db.Gorm().Model(&models.ScanSummary{}).Where("id = ?", entryId).Update("value", bytes).Error
+}
@@ -44,3 +44,21 @@ func InsertTorrentstreamHistory(db *db.Database, mId int, torrent *hibiketorrent
 		Torrent: bytes,
 	}).Error
 }
+
+// GetTorrentstreamHistories returns the last torrent streamed for each media.
+func GetTorrentstreamHistories(db *db.Database) (map[int]*hibiketorrent.AnimeTorrent, error) {
+	var histories []*models.TorrentstreamHistory
+	if err := db.Gorm().Find(&histories).Error; err != nil {
+		return nil, err
+	}
+
+	ret := make(map[int]*hibiketorrent.AnimeTorrent)
+	for _, history := range histories {
+		var torrent hibiketorrent.AnimeTorrent
+		if err := json.Unmarshal(history.Torrent, &torrent); err != nil {
+			continue
+		}
+		ret[history.MediaId] = &torrent
+	}
+	return ret, nil
+}
@@ -2,7 +2,10 @@ package models
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
@@ -81,6 +84,18 @@ type LibrarySettings struct {
 	// v2.6+
 	ScannerMatchingThreshold float64 `gorm:"column:scanner_matching_threshold" json:"scannerMatchingThreshold"`
 	ScannerMatchingAlgorithm string  `gorm:"column:scanner_matching_algorithm" json:"scannerMatchingAlgorithm"`
+	// Create a matching rule from each manual match
+	ScannerLearnMatchingRules bool `gorm:"column:scanner_learn_matching_rules" json:"scannerLearnMatchingRules"`
+	// Compute the CRC32 and ED2K hashes of scanned files
+	ScannerHashFiles bool `gorm:"column:scanner_hash_files" json:"scannerHashFiles"`
+	// JSON file mapping file hashes to media and episodes, files found in it are not fuzzy matched
+	ScannerHashLookupPath string `gorm:"column:scanner_hash_lookup_path" json:"scannerHashLookupPath"`
+	// Extract the technical metadata of scanned files using FFprobe
+	ScannerProbeMediaInfo bool `gorm:"column:scanner_probe_media_info" json:"scannerProbeMediaInfo"`
+	// Maximum number of scan logs kept, older ones are removed. 0 keeps all the logs
+	ScannerLogRetention int `gorm:"column:scanner_log_retention" json:"scannerLogRetention"`
+	// Settings of the library directories, directories without settings are enabled
+	LibraryRoots LibraryRoots `gorm:"column:library_roots;type:text" json:"libraryRoots"`
 }
 
 func (o *LibrarySettings) GetLibraryPaths() (ret []string) {
@@ -92,6 +107,91 @@ func (o *LibrarySettings) GetLibraryPaths() (ret []string) {
 	return
 }
 
+// GetLibraryRoots returns the settings of each library directory, sorted by descending priority.
+// Directories without settings get the default ones.
+func (o *LibrarySettings) GetLibraryRoots() []*LibraryRoot {
+	if o == nil {
+		return nil
+	}
+
+	settings := make(map[string]*LibraryRoot, len(o.LibraryRoots))
+	for _, root := range o.LibraryRoots {
+		if root != nil {
+			settings[normalizeLibraryRootPath(root.Path)] = root
+		}
+	}
+
+	ret := make([]*LibraryRoot, 0, len(o.LibraryPaths)+1)
+	for _, path := range o.GetLibraryPaths() {
+		if path == "" {
+			continue
+		}
+		root := &LibraryRoot{Path: path, Enabled: true}
+		if s, ok := settings[normalizeLibraryRootPath(path)]; ok {
+			root.Enabled = s.Enabled
+			root.ReadOnly = s.ReadOnly
+			root.Priority = s.Priority
+			root.DefaultMediaFormat = s.DefaultMediaFormat
+		}
+		ret = append(ret, root)
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Priority > ret[j].Priority
+	})
+
+	return ret
+}
+
+func normalizeLibraryRootPath(path string) string {
+	return strings.ToLower(filepath.ToSlash(filepath.Clean(path)))
+}
+
+// LibraryRoot holds the settings of a library directory.
+type LibraryRoot struct {
+	Path string `json:"path"`
+	// Disabled roots are not scanned or watched, their files are removed from the library
+	Enabled bool `json:"enabled"`
+	// Files under read-only roots are never deleted, and torrents are not downloaded to them
+	ReadOnly bool `json:"readOnly"`
+	// Roots with a higher priority are scanned first and are the default download destination
+	Priority int `json:"priority"`
+	// AniList format preferred when matching the files of the root, e.g. "MOVIE"
+	DefaultMediaFormat string `json:"defaultMediaFormat"`
+}
+
+type LibraryRoots []*LibraryRoot
+
+func (o *LibraryRoots) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	case nil:
+		*o = nil
+		return nil
+	default:
+		return errors.New("src value cannot cast to string")
+	}
+	if len(data) == 0 {
+		*o = nil
+		return nil
+	}
+	return json.Unmarshal(data, o)
+}
+func (o LibraryRoots) Value() (driver.Value, error) {
+	if len(o) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
 type LibraryPaths []string
 
 func (o *LibraryPaths) Scan(src interface{}) error {
@@ -316,6 +416,10 @@ type MediastreamSettings struct {
 	FfprobePath                   string `gorm:"column:ffprobe_path" json:"ffprobePath"`
 	// v2.2+
 	TranscodeHwAccelCustomSettings string `gorm:"column:transcode_hw_accel_custom_settings" json:"transcodeHwAccelCustomSettings"`
+	// v2.8+
+	TrickplayEnabled bool `gorm:"column:trickplay_enabled" json:"trickplayEnabled"` // Generate seek preview thumbnails when a file is played
+	TranscodeTonemap bool `gorm:"column:transcode_tonemap" json:"transcodeTonemap"` // Convert HDR videos to SDR when transcoding
+	TranscodeBurnIn  bool `gorm:"column:transcode_burn_in" json:"transcodeBurnIn"`  // Offer a rendition with the subtitles burned in when transcoding
 
 	//TranscodeTempDir              string `gorm:"column:transcode_temp_dir" json:"transcodeTempDir"` // DEPRECATED
 }
@@ -331,7 +435,7 @@ type TorrentstreamSettings struct {
 	PreferredResolution string `gorm:"column:preferred_resolution" json:"preferredResolution"`
 	DisableIPV6         bool   `gorm:"column:disable_ipv6" json:"disableIPV6"`
 	DownloadDir         string `gorm:"column:download_dir" json:"downloadDir"`
-	AddToLibrary        bool   `gorm:"column:add_to_library" json:"addToLibrary"`
+	AddToLibrary        bool   `gorm:"column:add_to_library" json:"addToLibrary"` // Keep and import every stream into the library when it stops
 	TorrentClientHost   string `gorm:"column:torrent_client_host" json:"torrentClientHost"`
 	TorrentClientPort   int    `gorm:"column:torrent_client_port" json:"torrentClientPort"`
 	StreamingServerHost string `gorm:"column:streaming_server_host" json:"streamingServerHost"`
@@ -342,6 +446,14 @@ type TorrentstreamSettings struct {
 	StreamUrlAddress string `gorm:"column:stream_url_address" json:"streamUrlAddress"`
 	// v2.7+
 	SlowSeeding bool `gorm:"column:slow_seeding" json:"slowSeeding"`
+	// v2.8+
+	SessionDownloadRateLimit int `gorm:"column:session_download_rate_limit" json:"sessionDownloadRateLimit"` // KiB/s, 0 = unlimited
+	GlobalDownloadRateLimit  int `gorm:"column:global_download_rate_limit" json:"globalDownloadRateLimit"`   // KiB/s, 0 = unlimited
+	// ImportDir is where finished torrents are imported, it should be inside a library directory.
+	// Defaults to the library directory with the highest priority that is enabled, writable and online.
+	ImportDir      string  `gorm:"column:import_dir" json:"importDir"`
+	SeedRatioLimit float64 `gorm:"column:seed_ratio_limit" json:"seedRatioLimit"` // 0 = no ratio limit
+	SeedTimeLimit  int     `gorm:"column:seed_time_limit" json:"seedTimeLimit"`   // Minutes, 0 = no time limit
 }
 
 type TorrentstreamHistory struct {
@@ -350,6 +462,15 @@ type TorrentstreamHistory struct {
 	Torrent []byte `gorm:"column:torrent" json:"torrent"`
 }
 
+// TorrentstreamImport is a torrent kept after its stream ended that has not been imported into the library yet.
+// It is restored when the torrent client is initialized.
+type TorrentstreamImport struct {
+	BaseModel
+	InfoHash string `gorm:"column:info_hash;uniqueIndex" json:"infoHash"`
+	MediaId  int    `gorm:"column:media_id" json:"mediaId"`
+	Torrent  []byte `gorm:"column:torrent" json:"torrent"` // Bencoded metainfo
+}
+
 // +---------------------+
 // |        Filler       |
 // +---------------------+
@@ -406,6 +527,8 @@ type DebridSettings struct {
 	IncludeDebridStreamInLibrary bool   `gorm:"column:include_debrid_stream_in_library" json:"includeDebridStreamInLibrary"`
 	StreamAutoSelect             bool   `gorm:"column:stream_auto_select" json:"streamAutoSelect"`
 	StreamPreferredResolution    string `gorm:"column:stream_preferred_resolution" json:"streamPreferredResolution"`
+	// Show completed torrents of the debrid service as library entries
+	IncludeCloudTorrentsInLibrary bool `gorm:"column:include_cloud_torrents_in_library" json:"includeCloudTorrentsInLibrary"`
 }
 
 type DebridTorrentItem struct {
@@ -415,3 +538,305 @@ type DebridTorrentItem struct {
 	Provider      string `gorm:"column:provider" json:"provider"`
 	MediaId       int    `gorm:"column:media_id" json:"mediaId"`
 }
+
+// +---------------------+
+// |  Track preferences  |
+// +---------------------+
+
+type TrackPreferenceSettings struct {
+	BaseModel
+	AudioLanguages    LanguageList `gorm:"column:audio_languages;type:text" json:"audioLanguages"`       // ISO 639 codes ordered by priority
+	SubtitleLanguages LanguageList `gorm:"column:subtitle_languages;type:text" json:"subtitleLanguages"` // ISO 639 codes ordered by priority
+	SignsAndSongs     string       `gorm:"column:signs_and_songs" json:"signsAndSongs"`                  // "auto", "prefer" or "never"
+}
+
+// MediaTrackPreference overrides the track preferences for a single media.
+// Empty fields fall back to the global TrackPreferenceSettings.
+type MediaTrackPreference struct {
+	BaseModel
+	MediaId           int          `gorm:"column:media_id;uniqueIndex" json:"mediaId"`
+	AudioLanguages    LanguageList `gorm:"column:audio_languages;type:text" json:"audioLanguages"`
+	SubtitleLanguages LanguageList `gorm:"column:subtitle_languages;type:text" json:"subtitleLanguages"`
+	SignsAndSongs     string       `gorm:"column:signs_and_songs" json:"signsAndSongs"`
+}
+
+type LanguageList []string
+
+func (o *LanguageList) Scan(src interface{}) error {
+	str, ok := src.(string)
+	if !ok {
+		return errors.New("src value cannot cast to string")
+	}
+	if str == "" {
+		*o = nil
+		return nil
+	}
+	*o = strings.Split(str, ",")
+	return nil
+}
+func (o LanguageList) Value() (driver.Value, error) {
+	if len(o) == 0 {
+		return nil, nil
+	}
+	return strings.Join(o, ","), nil
+}
+
+// +---------------------+
+// |  Download manager   |
+// +---------------------+
+
+type DownloadManagerSettings struct {
+	BaseModel
+	// Pauses all downloads
+	Paused bool `gorm:"column:paused" json:"paused"`
+	// If true, downloads only run inside the schedule windows, except high priority ones
+	ScheduleEnabled bool                    `gorm:"column:schedule_enabled" json:"scheduleEnabled"`
+	ScheduleWindows DownloadScheduleWindows `gorm:"column:schedule_windows;type:text" json:"scheduleWindows"`
+}
+
+// DownloadScheduleWindow is a time range during which downloads are allowed.
+// A window ending before it starts (e.g. 23:00 to 07:00) ends on the next day.
+type DownloadScheduleWindow struct {
+	Start string `json:"start"` // "HH:MM"
+	End   string `json:"end"`   // "HH:MM"
+	Days  []int  `json:"days"`  // Days on which the window starts, 0 is Sunday. Empty means every day.
+	// Download speed limit inside the window, in KiB/s. 0 means no limit.
+	RateLimit int `json:"rateLimit"`
+}
+
+type DownloadScheduleWindows []*DownloadScheduleWindow
+
+func (o *DownloadScheduleWindows) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	case nil:
+		*o = nil
+		return nil
+	default:
+		return errors.New("src value cannot cast to string")
+	}
+	if len(data) == 0 {
+		*o = nil
+		return nil
+	}
+	return json.Unmarshal(data, o)
+}
+func (o DownloadScheduleWindows) Value() (driver.Value, error) {
+	if len(o) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// +---------------------+
+// |   Scheduled jobs    |
+// +---------------------+
+
+// ScheduledJob stores the user-defined schedule of a scheduled job.
+type ScheduledJob struct {
+	BaseModel
+	Name string `gorm:"column:name;uniqueIndex" json:"name"`
+	// Cron expression (e.g. "0 4 * * *") or interval (e.g. "@every 30m")
+	Schedule string `gorm:"column:schedule" json:"schedule"`
+	Enabled  bool   `gorm:"column:enabled" json:"enabled"`
+}
+
+// ScheduledJobRun is an entry in the run history of a scheduled job.
+type ScheduledJobRun struct {
+	BaseModel
+	JobName   string    `gorm:"column:job_name;index" json:"jobName"`
+	Trigger   string    `gorm:"column:run_trigger" json:"trigger"` // "schedule" or "manual"
+	Status    string    `gorm:"column:status" json:"status"`       // "success", "failed" or "skipped"
+	StartedAt time.Time `gorm:"column:started_at" json:"startedAt"`
+	Duration  int64     `gorm:"column:duration" json:"duration"` // Milliseconds
+	Error     string    `gorm:"column:error" json:"error"`
+}
+
+// +---------------------+
+// |    Notifications    |
+// +---------------------+
+
+// NotificationChannel is an external service that receives notifications, e.g. a Discord webhook.
+type NotificationChannel struct {
+	BaseModel
+	Name    string `gorm:"column:name" json:"name"`
+	Type    string `gorm:"column:type" json:"type"` // "webhook", "ntfy", "gotify", "discord" or "smtp"
+	Enabled bool   `gorm:"column:enabled" json:"enabled"`
+	// Events sent to the channel, empty means all events
+	Events    NotificationEvents        `gorm:"column:events;type:text" json:"events"`
+	Config    NotificationChannelConfig `gorm:"column:config;type:text" json:"config"`
+	Templates NotificationTemplates     `gorm:"column:templates;type:text" json:"templates"`
+}
+
+// NotificationChannelConfig holds the options of all channel types, each type only uses some of them.
+type NotificationChannelConfig struct {
+	// Webhook URL (webhook, discord) or server URL (ntfy, gotify)
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"` // webhook
+	Token   string            `json:"token,omitempty"`   // ntfy access token, gotify application token
+	Topic   string            `json:"topic,omitempty"`   // ntfy
+	// ntfy (1-5) and gotify (0-10) priority, 0 uses the server default
+	Priority int      `json:"priority,omitempty"`
+	Host     string   `json:"host,omitempty"` // smtp
+	Port     int      `json:"port,omitempty"` // smtp, 465 uses implicit TLS
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+}
+
+// NotificationTemplate overrides the default title and message of an event.
+// Both are Go templates executed with the data of the event.
+type NotificationTemplate struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+type NotificationEvents []string
+
+// NotificationTemplates maps event names to templates.
+type NotificationTemplates map[string]*NotificationTemplate
+
+func scanJSONColumn(src interface{}, dest interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	case nil:
+		return nil
+	default:
+		return errors.New("src value cannot cast to string")
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func jsonColumnValue(v interface{}) (driver.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (o *NotificationEvents) Scan(src interface{}) error {
+	*o = nil
+	return scanJSONColumn(src, o)
+}
+func (o NotificationEvents) Value() (driver.Value, error) {
+	if len(o) == 0 {
+		return nil, nil
+	}
+	return jsonColumnValue(o)
+}
+
+func (o *NotificationChannelConfig) Scan(src interface{}) error {
+	*o = NotificationChannelConfig{}
+	return scanJSONColumn(src, o)
+}
+func (o NotificationChannelConfig) Value() (driver.Value, error) {
+	return jsonColumnValue(o)
+}
+
+func (o *NotificationTemplates) Scan(src interface{}) error {
+	*o = nil
+	return scanJSONColumn(src, o)
+}
+func (o NotificationTemplates) Value() (driver.Value, error) {
+	if len(o) == 0 {
+		return nil, nil
+	}
+	return jsonColumnValue(o)
+}
+
+// +---------------------+
+// |    Event webhooks   |
+// +---------------------+
+
+// EventWebhook is an HTTP endpoint that receives the events of the public event stream.
+type EventWebhook struct {
+	BaseModel
+	URL string `gorm:"column:url" json:"url"`
+	// Key used to sign the payloads with HMAC-SHA256, the signature is sent in the X-Seanime-Signature header
+	Secret  string `gorm:"column:secret" json:"secret"`
+	Enabled bool   `gorm:"column:enabled" json:"enabled"`
+	// Event types sent to the webhook, empty means all events
+	Events             EventWebhookEvents `gorm:"column:events;type:text" json:"events"`
+	LastDeliveryAt     *time.Time         `gorm:"column:last_delivery_at" json:"lastDeliveryAt,omitempty"`
+	LastDeliveryStatus int                `gorm:"column:last_delivery_status" json:"lastDeliveryStatus"` // HTTP status code, 0 if the request failed
+	LastDeliveryError  string             `gorm:"column:last_delivery_error" json:"lastDeliveryError"`
+}
+
+type EventWebhookEvents []string
+
+func (o *EventWebhookEvents) Scan(src interface{}) error {
+	*o = nil
+	return scanJSONColumn(src, o)
+}
+func (o EventWebhookEvents) Value() (driver.Value, error) {
+	if len(o) == 0 {
+		return nil, nil
+	}
+	return jsonColumnValue(o)
+}
+
+// +---------------------+
+// |   Matching rules    |
+// +---------------------+
+
+// MatchingRule is a user-defined rule evaluated by the scanner before fuzzy matching.
+type MatchingRule struct {
+	BaseModel
+	// "path", "regex", "title-alias" or "episode-offset"
+	Type string `gorm:"column:type" json:"type"`
+	// Directory, regular expression or title depending on the type, unused by episode offsets
+	Pattern string `gorm:"column:pattern" json:"pattern"`
+	// Restricts a title alias to the files of a release group
+	ReleaseGroup  string `gorm:"column:release_group" json:"releaseGroup"`
+	MediaId       int    `gorm:"column:media_id" json:"mediaId"`
+	EpisodeOffset int    `gorm:"column:episode_offset" json:"episodeOffset"`
+	Enabled       bool   `gorm:"column:enabled" json:"enabled"`
+	// Whether the rule was created from a manual correction
+	Learned bool `gorm:"column:learned" json:"learned"`
+}
+
+// +---------------------+
+// |     File hashes     |
+// +---------------------+
+
+// FileHash caches the hashes of a file computed by the scanner.
+// The hashes are reused as long as the size and modification time of the file do not change.
+type FileHash struct {
+	BaseModel
+	Path    string    `gorm:"column:path;uniqueIndex" json:"path"`
+	Size    int64     `gorm:"column:size" json:"size"`
+	ModTime time.Time `gorm:"column:mod_time" json:"modTime"`
+	CRC32   string    `gorm:"column:crc32" json:"crc32"`
+	ED2K    string    `gorm:"column:ed2k" json:"ed2k"`
+}
+
+// +---------------------+
+// |   File media info   |
+// +---------------------+
+
+// FileMediaInfo caches the technical metadata of a file extracted by the scanner.
+// The metadata is reused as long as the size and modification time of the file do not change.
+type FileMediaInfo struct {
+	BaseModel
+	Path    string    `gorm:"column:path;uniqueIndex" json:"path"`
+	Size    int64     `gorm:"column:size" json:"size"`
+	ModTime time.Time `gorm:"column:mod_time" json:"modTime"`
+	Value   []byte    `gorm:"column:value" json:"value"` // Marshaled anime.LocalFileMediaInfo
+}
@@ -0,0 +1,583 @@
+package alldebrid
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dustin/go-humanize"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"github.com/samber/mo"
+	"io"
+	"net/http"
+	"net/url"
+	"seanime/internal/debrid/debrid"
+	"seanime/internal/util"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	AllDebrid struct {
+		baseUrl string
+		apiKey  mo.Option[string]
+		client  *http.Client
+		logger  *zerolog.Logger
+	}
+
+	Response struct {
+		Status string          `json:"status"` // "success" or "error"
+		Data   json.RawMessage `json:"data"`
+		Error  *ErrorResponse  `json:"error"`
+	}
+
+	ErrorResponse struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+
+	Magnet struct {
+		ID             int     `json:"id"`
+		Filename       string  `json:"filename"`
+		Size           int64   `json:"size"`
+		Hash           string  `json:"hash"`
+		Status         string  `json:"status"`
+		StatusCode     int     `json:"statusCode"` // 0-3: processing, 4: ready, 5+: error
+		Downloaded     int64   `json:"downloaded"`
+		DownloadSpeed  int64   `json:"downloadSpeed"`
+		Seeders        int     `json:"seeders"`
+		UploadDate     int64   `json:"uploadDate"` // Unix timestamp
+		CompletionDate int64   `json:"completionDate"`
+		Links          []*Link `json:"links"`
+	}
+
+	// Link is a file of a magnet that is ready.
+	// It needs to be unlocked to get the download URL.
+	Link struct {
+		Link     string      `json:"link"`
+		Filename string      `json:"filename"`
+		Size     int64       `json:"size"`
+		Files    []*FileNode `json:"files"`
+	}
+
+	// FileNode is a node of the file tree of a link.
+	// Folders have entries, files have a size.
+	FileNode struct {
+		Name    string      `json:"n"`
+		Size    int64       `json:"s"`
+		Entries []*FileNode `json:"e"`
+	}
+
+	UploadedMagnet struct {
+		Magnet string         `json:"magnet"`
+		Hash   string         `json:"hash"`
+		Name   string         `json:"name"`
+		Size   int64          `json:"size"`
+		Ready  bool           `json:"ready"`
+		ID     int            `json:"id"`
+		Error  *ErrorResponse `json:"error"`
+	}
+
+	InstantAvailabilityItem struct {
+		Magnet  string      `json:"magnet"`
+		Hash    string      `json:"hash"`
+		Instant bool        `json:"instant"`
+		Files   []*FileNode `json:"files"`
+	}
+
+	UnlockedLink struct {
+		Link     string `json:"link"`
+		Filename string `json:"filename"`
+		Filesize int64  `json:"filesize"`
+	}
+)
+
+const (
+	statusCodeReady = 4
+	agent           = "seanime"
+)
+
+func NewAllDebrid(logger *zerolog.Logger) debrid.Provider {
+	return &AllDebrid{
+		baseUrl: "https://api.alldebrid.com/v4",
+		apiKey:  mo.None[string](),
+		client: &http.Client{
+			Timeout: time.Second * 30,
+		},
+		logger: logger,
+	}
+}
+
+func (t *AllDebrid) GetSettings() debrid.Settings {
+	return debrid.Settings{
+		ID:   "alldebrid",
+		Name: "AllDebrid",
+	}
+}
+
+// doQuery sends a request to the API and unmarshals the response data into ret.
+// Every request must include the agent parameter.
+func (t *AllDebrid) doQuery(method, endpoint string, params url.Values, ret interface{}) error {
+	apiKey, found := t.apiKey.Get()
+	if !found {
+		return debrid.ErrNotAuthenticated
+	}
+
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("agent", agent)
+
+	var body io.Reader
+	uri := t.baseUrl + endpoint
+	if method == http.MethodGet {
+		uri += "?" + params.Encode()
+	} else {
+		body = strings.NewReader(params.Encode())
+	}
+
+	req, err := http.NewRequest(method, uri, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.Header.Add("Authorization", "Bearer "+apiKey)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var r Response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		t.logger.Error().Err(err).Msg("alldebrid: Failed to decode response")
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if r.Status != "success" {
+		if r.Error != nil {
+			return fmt.Errorf("request failed: %s, %s", r.Error.Code, r.Error.Message)
+		}
+		return fmt.Errorf("request failed: %s", resp.Status)
+	}
+
+	if ret == nil {
+		return nil
+	}
+
+	return json.Unmarshal(r.Data, ret)
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (t *AllDebrid) Authenticate(apiKey string) error {
+	t.apiKey = mo.Some(apiKey)
+	return nil
+}
+
+func (t *AllDebrid) GetInstantAvailability(hashes []string) map[string]debrid.TorrentItemInstantAvailability {
+
+	t.logger.Trace().Strs("hashes", hashes).Msg("alldebrid: Checking instant availability")
+
+	availability := make(map[string]debrid.TorrentItemInstantAvailability)
+
+	if len(hashes) == 0 {
+		return availability
+	}
+
+	for _, batch := range lo.Chunk(hashes, 100) {
+		params := url.Values{}
+		for _, hash := range batch {
+			params.Add("magnets[]", hash)
+		}
+
+		var data struct {
+			Magnets []*InstantAvailabilityItem `json:"magnets"`
+		}
+		err := t.doQuery(http.MethodGet, "/magnet/instant", params, &data)
+		if err != nil {
+			t.logger.Error().Err(err).Msg("alldebrid: Failed to get instant availability")
+			return availability
+		}
+
+		for _, item := range data.Magnets {
+			if !item.Instant {
+				continue
+			}
+
+			// Use the hash as it was requested
+			hash, found := lo.Find(batch, func(h string) bool {
+				return strings.EqualFold(h, item.Hash) || strings.EqualFold(h, item.Magnet)
+			})
+			if !found {
+				continue
+			}
+
+			avail := debrid.TorrentItemInstantAvailability{
+				CachedFiles: make(map[string]*debrid.CachedFile),
+			}
+			for idx, f := range flattenFileNodes(item.Files, "") {
+				avail.CachedFiles[strconv.Itoa(idx)] = &debrid.CachedFile{
+					Name: f.Name,
+					Size: f.Size,
+				}
+			}
+			availability[hash] = avail
+		}
+	}
+
+	return availability
+}
+
+func (t *AllDebrid) AddTorrent(opts debrid.AddTorrentOptions) (string, error) {
+
+	// Check if the torrent is already added
+	if opts.InfoHash != "" {
+		magnets, err := t.getMagnets()
+		if err == nil {
+			for _, magnet := range magnets {
+				if strings.EqualFold(magnet.Hash, opts.InfoHash) {
+					t.logger.Debug().Int("torrentId", magnet.ID).Msg("alldebrid: Torrent already added")
+					return strconv.Itoa(magnet.ID), nil
+				}
+			}
+		}
+	}
+
+	t.logger.Trace().Str("magnetLink", opts.MagnetLink).Msg("alldebrid: Adding torrent")
+
+	// AllDebrid downloads all the files, opts.SelectFileId is not used
+	magnet, err := t.uploadMagnet(opts.MagnetLink)
+	if err != nil {
+		return "", fmt.Errorf("alldebrid: Failed to add torrent: %w", err)
+	}
+
+	t.logger.Debug().Int("torrentId", magnet.ID).Str("torrentName", magnet.Name).Str("torrentHash", magnet.Hash).Msg("alldebrid: Torrent added")
+
+	return strconv.Itoa(magnet.ID), nil
+}
+
+// GetTorrentStreamUrl blocks until the torrent is downloaded and returns the stream URL for the torrent file by calling GetTorrentDownloadUrl.
+func (t *AllDebrid) GetTorrentStreamUrl(ctx context.Context, opts debrid.StreamTorrentOptions, itemCh chan debrid.TorrentItem) (streamUrl string, err error) {
+
+	t.logger.Trace().Str("torrentId", opts.ID).Str("fileId", opts.FileId).Msg("alldebrid: Retrieving stream link")
+
+	doneCh := make(chan struct{})
+
+	go func(ctx context.Context) {
+		defer func() {
+			close(doneCh)
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				return
+			case <-time.After(4 * time.Second):
+				torrent, _err := t.GetTorrent(opts.ID)
+				if _err != nil {
+					t.logger.Error().Err(_err).Msg("alldebrid: Failed to get torrent")
+					err = fmt.Errorf("alldebrid: Failed to get torrent: %w", _err)
+					return
+				}
+
+				itemCh <- *torrent
+
+				if torrent.Status == debrid.TorrentItemStatusError {
+					err = fmt.Errorf("alldebrid: Torrent failed to download")
+					return
+				}
+
+				// Check if the torrent is ready
+				if torrent.IsReady {
+					streamUrl, err = t.GetTorrentDownloadUrl(debrid.DownloadTorrentOptions{
+						ID:     opts.ID,
+						FileId: opts.FileId,
+					})
+					if err != nil {
+						t.logger.Error().Err(err).Msg("alldebrid: Failed to get download URL")
+					}
+					return
+				}
+			}
+		}
+	}(ctx)
+
+	<-doneCh
+
+	return
+}
+
+// GetTorrentDownloadUrl returns the download URL for the torrent file.
+// If no opts.FileId is provided, it will return a comma-separated list of download URLs for all files in the torrent.
+func (t *AllDebrid) GetTorrentDownloadUrl(opts debrid.DownloadTorrentOptions) (downloadUrl string, err error) {
+
+	t.logger.Trace().Str("torrentId", opts.ID).Msg("alldebrid: Retrieving download link")
+
+	magnet, err := t.getMagnet(opts.ID)
+	if err != nil {
+		return "", fmt.Errorf("alldebrid: Failed to get download URL: %w", err)
+	}
+
+	if magnet.StatusCode != statusCodeReady {
+		return "", fmt.Errorf("alldebrid: Torrent is not ready")
+	}
+
+	links := magnet.Links
+	if opts.FileId != "" {
+		idx, err := strconv.Atoi(opts.FileId)
+		if err != nil || idx < 0 || idx >= len(magnet.Links) {
+			return "", fmt.Errorf("alldebrid: File not found")
+		}
+		links = []*Link{magnet.Links[idx]}
+	}
+
+	urls := make([]string, 0, len(links))
+	for _, link := range links {
+		unlocked, err := t.unlockLink(link.Link)
+		if err != nil {
+			return "", fmt.Errorf("alldebrid: Failed to get download URL: %w", err)
+		}
+		urls = append(urls, unlocked.Link)
+	}
+
+	downloadUrl = strings.Join(urls, ",")
+
+	t.logger.Debug().Str("downloadUrl", downloadUrl).Msg("alldebrid: Download link retrieved")
+
+	return downloadUrl, nil
+}
+
+func (t *AllDebrid) GetTorrent(id string) (ret *debrid.TorrentItem, err error) {
+	magnet, err := t.getMagnet(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDebridTorrent(magnet), nil
+}
+
+// GetTorrentInfo returns the torrent's data.
+// AllDebrid only lists the files of a torrent once it's ready, so this adds the torrent to the user's account.
+// The torrent is not removed since adding it again to stream it would return the same item.
+func (t *AllDebrid) GetTorrentInfo(opts debrid.GetTorrentInfoOptions) (ret *debrid.TorrentInfo, err error) {
+
+	if opts.ID != "" {
+		magnet, err := t.getMagnet(opts.ID)
+		if err != nil {
+			return nil, fmt.Errorf("alldebrid: Failed to get info: %w", err)
+		}
+		return toDebridTorrentInfo(magnet), nil
+	}
+
+	if opts.MagnetLink == "" {
+		return nil, fmt.Errorf("alldebrid: Magnet link is required")
+	}
+
+	uploaded, err := t.uploadMagnet(opts.MagnetLink)
+	if err != nil {
+		return nil, fmt.Errorf("alldebrid: Failed to get info: %w", err)
+	}
+
+	if !uploaded.Ready {
+		return nil, fmt.Errorf("alldebrid: Torrent is not cached, files are only available once it's downloaded")
+	}
+
+	magnet, err := t.getMagnet(strconv.Itoa(uploaded.ID))
+	if err != nil {
+		return nil, fmt.Errorf("alldebrid: Failed to get info: %w", err)
+	}
+
+	return toDebridTorrentInfo(magnet), nil
+}
+
+func (t *AllDebrid) GetTorrents() (ret []*debrid.TorrentItem, err error) {
+
+	magnets, err := t.getMagnets()
+	if err != nil {
+		return nil, fmt.Errorf("alldebrid: Failed to get torrents: %w", err)
+	}
+
+	// Limit the number of torrents to 500
+	if len(magnets) > 500 {
+		magnets = magnets[:500]
+	}
+
+	for _, m := range magnets {
+		ret = append(ret, toDebridTorrent(m))
+	}
+
+	slices.SortFunc(ret, func(i, j *debrid.TorrentItem) int {
+		return cmp.Compare(j.AddedAt, i.AddedAt)
+	})
+
+	return ret, nil
+}
+
+func (t *AllDebrid) DeleteTorrent(id string) error {
+
+	err := t.doQuery(http.MethodGet, "/magnet/delete", url.Values{"id": {id}}, nil)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("alldebrid: Failed to delete torrent")
+		return fmt.Errorf("alldebrid: Failed to delete torrent: %w", err)
+	}
+
+	return nil
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (t *AllDebrid) uploadMagnet(magnetLink string) (*UploadedMagnet, error) {
+	var data struct {
+		Magnets []*UploadedMagnet `json:"magnets"`
+	}
+	err := t.doQuery(http.MethodPost, "/magnet/upload", url.Values{"magnets[]": {magnetLink}}, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data.Magnets) == 0 {
+		return nil, fmt.Errorf("no magnet returned")
+	}
+	if data.Magnets[0].Error != nil {
+		return nil, fmt.Errorf("%s, %s", data.Magnets[0].Error.Code, data.Magnets[0].Error.Message)
+	}
+
+	return data.Magnets[0], nil
+}
+
+func (t *AllDebrid) getMagnet(id string) (*Magnet, error) {
+	var data struct {
+		Magnets *Magnet `json:"magnets"`
+	}
+	err := t.doQuery(http.MethodGet, "/magnet/status", url.Values{"id": {id}}, &data)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("alldebrid: Failed to get torrent")
+		return nil, fmt.Errorf("alldebrid: Failed to get torrent: %w", err)
+	}
+	if data.Magnets == nil {
+		return nil, fmt.Errorf("alldebrid: Torrent not found")
+	}
+
+	return data.Magnets, nil
+}
+
+func (t *AllDebrid) getMagnets() ([]*Magnet, error) {
+	var data struct {
+		Magnets []*Magnet `json:"magnets"`
+	}
+	err := t.doQuery(http.MethodGet, "/magnet/status", nil, &data)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("alldebrid: Failed to get torrents")
+		return nil, fmt.Errorf("alldebrid: Failed to get torrents: %w", err)
+	}
+
+	return data.Magnets, nil
+}
+
+func (t *AllDebrid) unlockLink(link string) (*UnlockedLink, error) {
+	var ret *UnlockedLink
+	err := t.doQuery(http.MethodGet, "/link/unlock", url.Values{"link": {link}}, &ret)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("alldebrid: Failed to unlock link")
+		return nil, fmt.Errorf("alldebrid: Failed to unlock link: %w", err)
+	}
+	if ret == nil || ret.Link == "" {
+		return nil, fmt.Errorf("alldebrid: Failed to unlock link")
+	}
+
+	return ret, nil
+}
+
+// flattenFileNodes returns the files of the tree, with their path relative to the root.
+func flattenFileNodes(nodes []*FileNode, parent string) (ret []*FileNode) {
+	for _, node := range nodes {
+		p := node.Name
+		if parent != "" {
+			p = parent + "/" + node.Name
+		}
+		if len(node.Entries) > 0 {
+			ret = append(ret, flattenFileNodes(node.Entries, p)...)
+			continue
+		}
+		ret = append(ret, &FileNode{Name: p, Size: node.Size})
+	}
+	return
+}
+
+func toDebridTorrent(m *Magnet) (ret *debrid.TorrentItem) {
+
+	completionPercentage := 0
+	if m.StatusCode == statusCodeReady {
+		completionPercentage = 100
+	} else if m.Size > 0 {
+		completionPercentage = int(m.Downloaded * 100 / m.Size)
+	}
+
+	ret = &debrid.TorrentItem{
+		ID:                   strconv.Itoa(m.ID),
+		Name:                 m.Filename,
+		Hash:                 m.Hash,
+		Size:                 m.Size,
+		FormattedSize:        humanize.Bytes(uint64(m.Size)),
+		CompletionPercentage: completionPercentage,
+		ETA:                  "",
+		Status:               toDebridTorrentStatus(m),
+		AddedAt:              time.Unix(m.UploadDate, 0).Format(time.RFC3339),
+		Speed:                util.ToHumanReadableSpeed(int(m.DownloadSpeed)),
+		Seeders:              m.Seeders,
+		IsReady:              m.StatusCode == statusCodeReady,
+	}
+
+	return
+}
+
+func toDebridTorrentInfo(m *Magnet) (ret *debrid.TorrentInfo) {
+
+	// Each link is a file of the torrent
+	var files []*debrid.TorrentItemFile
+	for idx, link := range m.Links {
+		p := link.Filename
+		if nodes := flattenFileNodes(link.Files, ""); len(nodes) == 1 {
+			p = nodes[0].Name
+		}
+
+		files = append(files, &debrid.TorrentItemFile{
+			ID:    strconv.Itoa(idx), // Index of the link
+			Index: idx,
+			Name:  link.Filename, // e.g. "Big Buck Bunny.mp4"
+			Path:  "/" + p,       // e.g. "/Big Buck Bunny/Big Buck Bunny.mp4"
+			Size:  link.Size,
+		})
+	}
+
+	id := strconv.Itoa(m.ID)
+	ret = &debrid.TorrentInfo{
+		ID:    &id,
+		Name:  m.Filename,
+		Hash:  m.Hash,
+		Size:  m.Size,
+		Files: files,
+	}
+
+	return
+}
+
+func toDebridTorrentStatus(m *Magnet) debrid.TorrentItemStatus {
+	switch m.StatusCode {
+	case 0:
+		return debrid.TorrentItemStatusStalled
+	case 1, 2, 3:
+		return debrid.TorrentItemStatusDownloading
+	case statusCodeReady:
+		return debrid.TorrentItemStatusCompleted
+	default:
+		return debrid.TorrentItemStatusError
+	}
+}
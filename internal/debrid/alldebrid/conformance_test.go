@@ -0,0 +1,163 @@
+package alldebrid
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"seanime/internal/debrid/debrid"
+	"seanime/internal/debrid/debridtest"
+	"seanime/internal/util"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAllDebrid_Conformance(t *testing.T) {
+	debridtest.Run(t, debridtest.Options{
+		NewProvider: func(baseUrl string) debrid.Provider {
+			ad := NewAllDebrid(util.NewLogger()).(*AllDebrid)
+			ad.baseUrl = baseUrl
+			return ad
+		},
+		NewHandler: newFakeApi,
+	})
+}
+
+// newFakeApi returns a fake of the AllDebrid v4 API.
+func newFakeApi(t *testing.T, torrents []*debridtest.Torrent) http.Handler {
+	var mu sync.Mutex
+	items := make(map[int]*Magnet)
+	links := make(map[string]string) // Hoster link -> download link
+	nextId := 1
+
+	writeData := func(w http.ResponseWriter, data interface{}) {
+		b, _ := json.Marshal(data)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Response{Status: "success", Data: b})
+	}
+	writeError := func(w http.ResponseWriter, code, message string) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Response{Status: "error", Error: &ErrorResponse{Code: code, Message: message}})
+	}
+	// toFileTree returns the file tree of a torrent file, e.g. "Show/Show - 01.mkv" -> [{Show [{Show - 01.mkv}]}]
+	toFileTree := func(f *debridtest.File) []*FileNode {
+		parts := strings.Split(f.Path, "/")
+		node := &FileNode{Name: parts[len(parts)-1], Size: f.Size}
+		for i := len(parts) - 2; i >= 0; i-- {
+			node = &FileNode{Name: parts[i], Entries: []*FileNode{node}}
+		}
+		return []*FileNode{node}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("agent") == "" && r.FormValue("agent") == "" {
+			writeError(w, "AUTH_MISSING_AGENT", "You must send a meaningful agent parameter")
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+debridtest.ApiKey {
+			writeError(w, "AUTH_BAD_APIKEY", "The auth apikey is invalid")
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/magnet/instant":
+			ret := make([]*InstantAvailabilityItem, 0)
+			for _, hash := range r.URL.Query()["magnets[]"] {
+				item := &InstantAvailabilityItem{Magnet: hash, Hash: strings.ToLower(hash)}
+				if torrent, found := debridtest.FindTorrent(torrents, hash); found && torrent.Cached {
+					item.Instant = true
+					for _, f := range torrent.Files {
+						item.Files = append(item.Files, toFileTree(f)...)
+					}
+				}
+				ret = append(ret, item)
+			}
+			writeData(w, map[string]interface{}{"magnets": ret})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/magnet/upload":
+			magnet := r.FormValue("magnets[]")
+			torrent, found := debridtest.FindTorrent(torrents, magnet)
+			if !found {
+				writeData(w, map[string]interface{}{"magnets": []*UploadedMagnet{{Magnet: magnet, Error: &ErrorResponse{Code: "MAGNET_INVALID_URI", Message: "This magnet is not valid"}}}})
+				return
+			}
+			// Uploading a magnet that was already added returns the existing item
+			var item *Magnet
+			for _, m := range items {
+				if m.Hash == torrent.Hash {
+					item = m
+				}
+			}
+			if item == nil {
+				item = &Magnet{ID: nextId, Filename: torrent.Name, Hash: torrent.Hash, Status: "Downloading", StatusCode: 1, UploadDate: time.Now().Unix()}
+				nextId++
+				for idx, f := range torrent.Files {
+					item.Size += f.Size
+					if torrent.Cached {
+						link := fmt.Sprintf("https://alldebrid.test/f/%d%d", item.ID, idx)
+						links[link] = f.DownloadUrl
+						item.Links = append(item.Links, &Link{Link: link, Filename: path.Base(f.Path), Size: f.Size, Files: toFileTree(f)})
+					}
+				}
+				if torrent.Cached {
+					item.Status, item.StatusCode = "Ready", 4
+					item.Downloaded = item.Size
+					item.CompletionDate = item.UploadDate
+				}
+				items[item.ID] = item
+			}
+			writeData(w, map[string]interface{}{"magnets": []*UploadedMagnet{{
+				Magnet: magnet,
+				Hash:   item.Hash,
+				Name:   item.Filename,
+				Size:   item.Size,
+				Ready:  item.StatusCode == 4,
+				ID:     item.ID,
+			}}})
+
+		case r.Method == http.MethodGet && r.URL.Path == "/magnet/status":
+			if id := r.URL.Query().Get("id"); id != "" {
+				item, found := items[util.StringToIntMust(id)]
+				if !found {
+					writeError(w, "MAGNET_INVALID_ID", "This magnet ID does not exists or is invalid")
+					return
+				}
+				writeData(w, map[string]interface{}{"magnets": item})
+				return
+			}
+			ret := make([]*Magnet, 0)
+			for _, item := range items {
+				ret = append(ret, item)
+			}
+			writeData(w, map[string]interface{}{"magnets": ret})
+
+		case r.Method == http.MethodGet && r.URL.Path == "/magnet/delete":
+			id, _ := strconv.Atoi(r.URL.Query().Get("id"))
+			if _, found := items[id]; !found {
+				writeError(w, "MAGNET_INVALID_ID", "This magnet ID does not exists or is invalid")
+				return
+			}
+			delete(items, id)
+			writeData(w, map[string]interface{}{"message": "Magnet was successfully deleted"})
+
+		case r.Method == http.MethodGet && r.URL.Path == "/link/unlock":
+			link := r.URL.Query().Get("link")
+			downloadUrl, found := links[link]
+			if !found {
+				writeError(w, "LINK_HOST_UNAVAILABLE", "Host is under maintenance or not available")
+				return
+			}
+			writeData(w, &UnlockedLink{Link: downloadUrl, Filename: path.Base(downloadUrl)})
+
+		default:
+			t.Errorf("alldebrid: unexpected request %s %s", r.Method, r.URL.String())
+			writeError(w, "NO_SERVER", "Not found")
+		}
+	})
+}
@@ -15,11 +15,13 @@ import (
 	"runtime"
 	"seanime/internal/debrid/debrid"
 	"seanime/internal/events"
+	"seanime/internal/eventstream"
 	"seanime/internal/notifier"
 	"seanime/internal/util"
 	"seanime/internal/util/result"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -48,6 +50,7 @@ func (r *Repository) launchDownloadLoop(ctx context.Context) {
 
 				readyItems := make([]*debrid.TorrentItem, 0)
 				for _, item := range items {
+					r.torrentNames.Set(item.ID, item.Name)
 					if item.IsReady {
 						readyItems = append(readyItems, item)
 					}
@@ -60,6 +63,10 @@ func (r *Repository) launchDownloadLoop(ctx context.Context) {
 				}
 
 				for _, dbItem := range dbItems {
+					// Paused items stay in the queue
+					if r.isDownloadPaused(dbItem.TorrentItemID) {
+						continue
+					}
 					// Check if the item is ready for download
 					for _, readyItem := range readyItems {
 						if dbItem.TorrentItemID == readyItem.ID {
@@ -72,9 +79,13 @@ func (r *Repository) launchDownloadLoop(ctx context.Context) {
 							}
 							time.Sleep(1 * time.Second)
 							// Download the torrent locally
-							err = r.downloadTorrentItem(readyItem.ID, readyItem.Name, dbItem.Destination)
+							err = r.downloadTorrentItem(readyItem.ID, readyItem.Name, dbItem.Destination, dbItem.MediaId)
 							if err != nil {
 								r.logger.Err(err).Msg("debrid: Failed to download torrent")
+								notifier.GlobalDispatcher.Dispatch(notifier.EventError, map[string]interface{}{
+									"source": "Debrid",
+									"error":  fmt.Sprintf("Failed to download %q: %v", readyItem.Name, err),
+								})
 								continue
 							}
 						}
@@ -89,7 +100,7 @@ func (r *Repository) launchDownloadLoop(ctx context.Context) {
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 func (r *Repository) DownloadTorrent(item debrid.TorrentItem, destination string) error {
-	return r.downloadTorrentItem(item.ID, item.Name, destination)
+	return r.downloadTorrentItem(item.ID, item.Name, destination, 0)
 }
 
 type downloadStatus struct {
@@ -97,7 +108,7 @@ type downloadStatus struct {
 	TotalSize  int64
 }
 
-func (r *Repository) downloadTorrentItem(tId string, torrentName string, destination string) (err error) {
+func (r *Repository) downloadTorrentItem(tId string, torrentName string, destination string, mediaId int) (err error) {
 	defer util.HandlePanicInModuleWithError("debrid/client/downloadTorrentItem", &err)
 
 	provider, err := r.GetProvider()
@@ -118,32 +129,66 @@ func (r *Repository) downloadTorrentItem(tId string, torrentName string, destina
 	ctx, cancel := context.WithCancel(context.Background())
 	r.ctxMap.Set(tId, cancel)
 
+	dl := &localDownload{
+		name:        torrentName,
+		mediaId:     mediaId,
+		destination: destination,
+		files:       result.NewResultMap[string, downloadStatus](),
+	}
+	r.downloads.Set(tId, dl)
+
 	go func(ctx context.Context) {
 		defer func() {
 			cancel()
 			r.ctxMap.Delete(tId)
+			r.downloads.Delete(tId)
+			r.pausedDownloads.Delete(tId)
 		}()
 
 		wg := sync.WaitGroup{}
 		downloadUrls := strings.Split(downloadUrl, ",")
 		downloadMap := result.NewResultMap[string, downloadStatus]()
 
+		var failedCount atomic.Int32
 		for _, url := range downloadUrls {
 			wg.Add(1)
 			go func(ctx context.Context, url string) {
 				defer wg.Done()
 
 				// Download the file
-				ok := r.downloadFile(ctx, tId, url, destination, downloadMap)
+				ok := r.downloadFile(ctx, tId, url, destination, downloadMap, dl.files)
 				if !ok {
-					return
+					failedCount.Add(1)
 				}
 			}(ctx, url)
 		}
 		wg.Wait()
 
-		r.sendDownloadCompletedEvent(tId)
-		notifier.GlobalNotifier.Notify(notifier.Debrid, fmt.Sprintf("Downloaded %q", torrentName))
+		switch {
+		case ctx.Err() != nil:
+			r.logger.Debug().Str("torrentName", torrentName).Msg("debrid: Download cancelled")
+			eventstream.GlobalBroker.Publish(eventstream.TypeDownloadCancelled, &eventstream.DownloadCompletedPayload{
+				Source:  "debrid",
+				Name:    torrentName,
+				MediaId: mediaId,
+			})
+		case failedCount.Load() > 0:
+			r.logger.Error().Str("torrentName", torrentName).Int32("failed", failedCount.Load()).Msg("debrid: Failed to download files")
+			eventstream.GlobalBroker.Publish(eventstream.TypeDownloadFailed, &eventstream.DownloadFailedPayload{
+				Source:  "debrid",
+				Name:    torrentName,
+				MediaId: mediaId,
+				Error:   fmt.Sprintf("%d of %d files failed to download", failedCount.Load(), len(downloadUrls)),
+			})
+		default:
+			r.sendDownloadCompletedEvent(tId)
+			notifier.GlobalNotifier.Notify(notifier.Debrid, fmt.Sprintf("Downloaded %q", torrentName))
+			eventstream.GlobalBroker.Publish(eventstream.TypeDownloadCompleted, &eventstream.DownloadCompletedPayload{
+				Source:  "debrid",
+				Name:    torrentName,
+				MediaId: mediaId,
+			})
+		}
 	}(ctx)
 
 	// Send a starting event
@@ -158,18 +203,11 @@ func (r *Repository) downloadTorrentItem(tId string, torrentName string, destina
 	return nil
 }
 
-func (r *Repository) downloadFile(ctx context.Context, tId string, downloadUrl string, destination string, downloadMap *result.Map[string, downloadStatus]) (ok bool) {
+func (r *Repository) downloadFile(ctx context.Context, tId string, downloadUrl string, destination string, downloadMap *result.Map[string, downloadStatus], progressMap *result.Map[string, downloadStatus]) (ok bool) {
 	defer util.HandlePanicInModuleThen("debrid/client/downloadFile", func() {
 		ok = false
 	})
 
-	// Create a cancellable HTTP request
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadUrl, nil)
-	if err != nil {
-		r.logger.Err(err).Str("downloadUrl", downloadUrl).Msg("debrid: Failed to create request")
-		return false
-	}
-
 	_ = os.MkdirAll(destination, os.ModePerm)
 
 	// Download the files to a temporary folder
@@ -188,14 +226,16 @@ func (r *Repository) downloadFile(ctx context.Context, tId string, downloadUrl s
 	}
 
 	// Execute the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := r.requestDownload(ctx, tId, downloadUrl, 0)
 	if err != nil {
 		r.logger.Err(err).Str("downloadUrl", downloadUrl).Msg("debrid: Failed to execute request")
 		r.wsEventManager.SendEvent(events.ErrorToast, fmt.Sprintf("debrid: Failed to execute download request: %v", err))
 		return false
 	}
-	defer resp.Body.Close()
+	// The response is replaced when the download is resumed
+	defer func() {
+		_ = resp.Body.Close()
+	}()
 
 	// e.g. "my-torrent.zip", "downloaded_torrent"
 	filename := "downloaded_torrent"
@@ -253,10 +293,36 @@ func (r *Repository) downloadFile(ctx context.Context, tId string, downloadUrl s
 	lastSent := time.Now()
 
 	// Copy response body to the temporary file
-	buffer := make([]byte, 32*1024)
+	buffer := make([]byte, downloadBufferSize)
 	var totalBytes int64
 	var lastBytes int64
 	for {
+		// Release the connection while the download is paused, it's resumed from the current offset
+		if r.isDownloadPaused(tId) {
+			_ = resp.Body.Close()
+			resp, err = r.resumeDownload(ctx, tId, downloadUrl, file, &totalBytes)
+			if err != nil {
+				_ = file.Close()
+				if ctx.Err() != nil {
+					r.logger.Debug().Err(err).Msg("debrid: Download cancelled")
+				} else {
+					r.logger.Err(err).Str("downloadUrl", downloadUrl).Msg("debrid: Failed to resume download")
+					r.wsEventManager.SendEvent(events.ErrorToast, fmt.Sprintf("debrid: Failed to resume download: %v", err))
+				}
+				r.sendDownloadCancelledEvent(tId, downloadUrl, downloadMap)
+				return false
+			}
+			lastBytes = totalBytes
+		}
+
+		// Wait while the download is throttled
+		if err := r.waitForDownloadSlot(ctx, len(buffer)); err != nil {
+			_ = file.Close()
+			r.logger.Debug().Err(err).Msg("debrid: Download cancelled")
+			r.sendDownloadCancelledEvent(tId, downloadUrl, downloadMap)
+			return false
+		}
+
 		n, err := resp.Body.Read(buffer)
 		if n > 0 {
 			_, writeErr := file.Write(buffer[:n])
@@ -277,6 +343,10 @@ func (r *Repository) downloadFile(ctx context.Context, tId string, downloadUrl s
 				TotalBytes: totalBytes,
 				TotalSize:  totalSize,
 			})
+			progressMap.Set(downloadUrl, downloadStatus{
+				TotalBytes: totalBytes,
+				TotalSize:  totalSize,
+			})
 
 			if time.Since(lastSent) > time.Second*2 {
 				_totalBytes := uint64(0)
@@ -301,6 +371,10 @@ func (r *Repository) downloadFile(ctx context.Context, tId string, downloadUrl s
 			if err == io.EOF {
 				break
 			}
+			// The request was interrupted because the download was paused
+			if ctx.Err() == nil && r.isDownloadPaused(tId) {
+				continue
+			}
 			if errors.Is(err, context.Canceled) {
 				_ = file.Close()
 				r.logger.Debug().Msg("debrid: Download cancelled")
@@ -355,7 +429,7 @@ func (r *Repository) downloadFile(ctx context.Context, tId string, downloadUrl s
 			r.sendDownloadCancelledEvent(tId, downloadUrl, downloadMap)
 			return false
 		}
-		return false
+		return true
 	}
 	if err != nil {
 		r.logger.Err(err).Str("tmpDownloadedFilePath", tmpDownloadedFilePath).Msg("debrid: Failed to extract downloaded file")
@@ -426,3 +500,83 @@ func getFilenameFromHeaders(url string) (string, error) {
 	}
 	return "", fmt.Errorf("filename not found in Content-Disposition header")
 }
+
+// requestDownload executes the download request, starting at offset if it's not 0.
+// The request is interrupted when the download is paused, so that the connection isn't kept open.
+func (r *Repository) requestDownload(ctx context.Context, tId string, downloadUrl string, offset int64) (*http.Response, error) {
+	reqCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		for {
+			select {
+			case <-reqCtx.Done():
+				return
+			case <-time.After(500 * time.Millisecond):
+				if r.isDownloadPaused(tId) {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, downloadUrl, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// cancelOnCloseBody releases the context of the request when the response body is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// resumeDownload waits until the download is resumed and requests the rest of the file.
+// If the server doesn't support range requests, the file is downloaded again from the start and totalBytes is reset.
+func (r *Repository) resumeDownload(ctx context.Context, tId string, downloadUrl string, file *os.File, totalBytes *int64) (*http.Response, error) {
+	if err := r.waitWhileDownloadPaused(ctx, tId); err != nil {
+		return nil, err
+	}
+
+	resp, err := r.requestDownload(ctx, tId, downloadUrl, *totalBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if *totalBytes > 0 && resp.StatusCode != http.StatusPartialContent {
+		r.logger.Debug().Str("downloadUrl", downloadUrl).Msg("debrid: Range requests are not supported, restarting download")
+		if err := file.Truncate(0); err != nil {
+			_ = resp.Body.Close()
+			return nil, err
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			_ = resp.Body.Close()
+			return nil, err
+		}
+		*totalBytes = 0
+	}
+
+	r.logger.Debug().Str("torrentItemId", tId).Int64("offset", *totalBytes).Msg("debrid: Download resumed")
+	return resp, nil
+}
@@ -0,0 +1,144 @@
+package debrid_client
+
+import (
+	"context"
+	"fmt"
+	"seanime/internal/util/result"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// downloadBufferSize is the size of the chunks read from the response body
+	downloadBufferSize = 32 * 1024
+)
+
+type (
+	// DownloadJob is a torrent being downloaded locally from the debrid service,
+	// or a torrent waiting for the debrid service before being downloaded.
+	DownloadJob struct {
+		TorrentItemID   string
+		Name            string
+		MediaId         int
+		Destination     string
+		TotalBytes      int64
+		DownloadedBytes int64
+		// Whether the torrent is still being cached by the debrid service
+		Queued bool
+		Paused bool
+	}
+
+	localDownload struct {
+		name        string
+		mediaId     int
+		destination string
+		files       *result.Map[string, downloadStatus]
+	}
+)
+
+// GetDownloadJobs returns the local downloads and the torrents queued for download.
+func (r *Repository) GetDownloadJobs() []*DownloadJob {
+	ret := make([]*DownloadJob, 0)
+
+	r.downloads.Range(func(tId string, dl *localDownload) bool {
+		job := &DownloadJob{
+			TorrentItemID: tId,
+			Name:          dl.name,
+			MediaId:       dl.mediaId,
+			Destination:   dl.destination,
+			Paused:        r.isDownloadPaused(tId),
+		}
+		dl.files.Range(func(_ string, status downloadStatus) bool {
+			job.DownloadedBytes += status.TotalBytes
+			if status.TotalSize > 0 {
+				job.TotalBytes += status.TotalSize
+			}
+			return true
+		})
+		ret = append(ret, job)
+		return true
+	})
+
+	if !r.HasProvider() {
+		return ret
+	}
+
+	dbItems, err := r.db.GetDebridTorrentItems()
+	if err != nil {
+		return ret
+	}
+	for _, dbItem := range dbItems {
+		if _, found := r.downloads.Get(dbItem.TorrentItemID); found {
+			continue
+		}
+		name, found := r.torrentNames.Get(dbItem.TorrentItemID)
+		if !found {
+			name = dbItem.TorrentItemID
+		}
+		ret = append(ret, &DownloadJob{
+			TorrentItemID: dbItem.TorrentItemID,
+			Name:          name,
+			MediaId:       dbItem.MediaId,
+			Destination:   dbItem.Destination,
+			Queued:        true,
+			Paused:        r.isDownloadPaused(dbItem.TorrentItemID),
+		})
+	}
+
+	return ret
+}
+
+// PauseDownload pauses a local download.
+// If the torrent is still queued, it won't be downloaded until it's resumed.
+func (r *Repository) PauseDownload(tId string) {
+	r.pausedDownloads.Set(tId, struct{}{})
+	r.logger.Debug().Str("torrentItemId", tId).Msg("debrid: Download paused")
+}
+
+// ResumeDownload resumes a paused download.
+func (r *Repository) ResumeDownload(tId string) {
+	r.pausedDownloads.Delete(tId)
+	r.logger.Debug().Str("torrentItemId", tId).Msg("debrid: Download resumed")
+}
+
+// SetDownloadRateLimit limits the combined speed of local downloads, in bytes per second.
+// A value of 0 removes the limit.
+func (r *Repository) SetDownloadRateLimit(bytesPerSecond int64) error {
+	if bytesPerSecond <= 0 {
+		r.downloadLimiter.SetLimit(rate.Inf)
+		return nil
+	}
+	// The burst must allow reading a full buffer at once
+	r.downloadLimiter.SetBurst(max(int(bytesPerSecond), downloadBufferSize))
+	r.downloadLimiter.SetLimit(rate.Limit(bytesPerSecond))
+	return nil
+}
+
+func (r *Repository) isDownloadPaused(tId string) bool {
+	_, found := r.pausedDownloads.Get(tId)
+	return found
+}
+
+// waitWhileDownloadPaused blocks until the download is resumed or cancelled.
+func (r *Repository) waitWhileDownloadPaused(ctx context.Context, tId string) error {
+	for r.isDownloadPaused(tId) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+	return nil
+}
+
+// waitForDownloadSlot blocks until n bytes can be read without exceeding the rate limit.
+func (r *Repository) waitForDownloadSlot(ctx context.Context, n int) error {
+	if err := r.downloadLimiter.WaitN(ctx, n); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("debrid: rate limiter: %w", err)
+	}
+	return nil
+}
@@ -1,15 +1,23 @@
 package debrid_client
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"seanime/internal/database/db"
 	"seanime/internal/database/models"
 	"seanime/internal/debrid/debrid"
+	"seanime/internal/events"
 	"seanime/internal/test_utils"
 	"seanime/internal/util"
+	"seanime/internal/util/result"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -75,7 +83,7 @@ func TestTorBoxDownload(t *testing.T) {
 	require.NoError(t, err)
 
 	// Download the torrent
-	err = repo.downloadTorrentItem(dbTorrentItem.TorrentItemID, torrentItem.Name, dbTorrentItem.Destination)
+	err = repo.downloadTorrentItem(dbTorrentItem.TorrentItemID, torrentItem.Name, dbTorrentItem.Destination, dbTorrentItem.MediaId)
 	require.NoError(t, err)
 
 	time.Sleep(time.Millisecond * 500)
@@ -108,3 +116,72 @@ loop:
 
 	require.NotEmpty(t, entries)
 }
+
+func TestDownloadFilePauseResume(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 20*downloadBufferSize/10)
+	half := len(content) / 2
+
+	var requests atomic.Int32
+	var rangeHeader atomic.Value
+	connectionClosed := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			return
+		}
+		if requests.Add(1) == 1 {
+			// Send the first half and hold the connection until the client closes it
+			w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+			_, _ = w.Write(content[:half])
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			close(connectionClosed)
+			return
+		}
+		rangeHeader.Store(r.Header.Get("Range"))
+		http.ServeContent(w, r, "video.mkv", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	logger := util.NewLogger()
+	repo := &Repository{
+		logger:          logger,
+		wsEventManager:  events.NewMockWSEventManager(logger),
+		pausedDownloads: result.NewResultMap[string, struct{}](),
+		downloadLimiter: rate.NewLimiter(rate.Inf, downloadBufferSize),
+	}
+
+	destination := t.TempDir()
+	progressMap := result.NewResultMap[string, downloadStatus]()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		repo.downloadFile(context.Background(), "1", server.URL+"/video.mkv", destination, result.NewResultMap[string, downloadStatus](), progressMap)
+	}()
+
+	require.Eventually(t, func() bool {
+		status, found := progressMap.Get(server.URL + "/video.mkv")
+		return found && status.TotalBytes == int64(half)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The connection is released while the download is paused
+	repo.PauseDownload("1")
+	select {
+	case <-connectionClosed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection was not closed after pausing the download")
+	}
+
+	// The download is resumed from the current offset
+	repo.ResumeDownload("1")
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("download did not complete after resuming")
+	}
+
+	require.Equal(t, fmt.Sprintf("bytes=%d-", half), rangeHeader.Load())
+	downloaded, err := os.ReadFile(filepath.Join(destination, "video.mkv"))
+	require.NoError(t, err)
+	require.Equal(t, content, downloaded)
+}
@@ -0,0 +1,434 @@
+package debrid_client
+
+import (
+	"fmt"
+	"github.com/5rahim/habari"
+	"github.com/samber/lo"
+	"seanime/internal/api/anilist"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/debrid/debrid"
+	"seanime/internal/events"
+	"seanime/internal/library/anime"
+	torrentanalyzer "seanime/internal/torrents/analyzer"
+	"seanime/internal/util"
+	"seanime/internal/util/comparison"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The cloud library shows the completed torrents of the debrid service as library entries, without downloading them.
+// Each torrent is analyzed once, the files are mapped to media and episodes with the torrent analyzer.
+// Stream URLs are resolved when the file is played since they expire.
+
+const (
+	// cloudLibraryRefreshInterval is the minimum time between two fetches of the torrent list
+	cloudLibraryRefreshInterval = 5 * time.Minute
+	// cloudLibraryMinTitleRating is the minimum similarity between a torrent name and a media title
+	cloudLibraryMinTitleRating = 0.7
+)
+
+type (
+	// CloudFile is a video file of a completed debrid torrent that is mapped to a media episode.
+	CloudFile struct {
+		TorrentItemId string                   `json:"torrentItemId"`
+		TorrentName   string                   `json:"torrentName"`
+		FileId        string                   `json:"fileId"`
+		Path          string                   `json:"path"`
+		Size          int64                    `json:"size"`
+		MediaId       int                      `json:"mediaId"`
+		Metadata      *anime.LocalFileMetadata `json:"metadata"`
+	}
+
+	cloudLibrary struct {
+		mu          sync.RWMutex
+		refreshMu   sync.Mutex              // Only one refresh at a time
+		files       map[string][]*CloudFile // Analyzed files, by torrent item ID
+		itemIds     []string                // IDs of the completed torrents, as of the last refresh
+		refreshedAt time.Time
+		refreshing  atomic.Bool
+	}
+
+	StartCloudStreamOptions struct {
+		AnimeCollection *anilist.AnimeCollection
+		MediaId         int
+		EpisodeNumber   int
+		AniDBEpisode    string
+		UserAgent       string
+		ClientId        string
+		PlaybackType    StreamPlaybackType
+	}
+
+	HydrateCloudCollectionOptions struct {
+		AnimeCollection   *anilist.AnimeCollection
+		LibraryCollection *anime.LibraryCollection
+	}
+)
+
+func newCloudLibrary() *cloudLibrary {
+	return &cloudLibrary{
+		files:   make(map[string][]*CloudFile),
+		itemIds: make([]string, 0),
+	}
+}
+
+// reset clears the cache, e.g. when the provider changes.
+func (c *cloudLibrary) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.files = make(map[string][]*CloudFile)
+	c.itemIds = make([]string, 0)
+	c.refreshedAt = time.Time{}
+}
+
+// GetCloudFiles returns the files of the completed torrents that are mapped to a media of the user's collection.
+// The torrent list is cached, refresh bypasses the cache.
+func (r *Repository) GetCloudFiles(animeCollection *anilist.AnimeCollection, refresh bool) (ret []*CloudFile, err error) {
+	defer util.HandlePanicInModuleWithError("debrid_client/GetCloudFiles", &err)
+
+	if refresh || r.cloudLibrary.isStale() {
+		if _, err = r.refreshCloudFiles(animeCollection); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.cloudLibrary.getFiles(animeCollection), nil
+}
+
+// refreshCloudFiles fetches the completed torrents and analyzes the new ones, along with the ones that were not matched to any media.
+// It returns true if the list of torrents changed or if torrents were matched.
+func (r *Repository) refreshCloudFiles(animeCollection *anilist.AnimeCollection) (changed bool, err error) {
+	provider, err := r.GetProvider()
+	if err != nil {
+		return false, err
+	}
+
+	c := r.cloudLibrary
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	torrents, err := provider.GetTorrents()
+	if err != nil {
+		return false, err
+	}
+
+	hints := r.getCloudMediaHints()
+
+	c.mu.RLock()
+	previous := c.files
+	previousIds := c.itemIds
+	c.mu.RUnlock()
+
+	files := make(map[string][]*CloudFile)
+	itemIds := make([]string, 0)
+	matched := false // Whether torrents were matched to media
+	for _, t := range torrents {
+		if !t.IsReady {
+			continue
+		}
+		itemIds = append(itemIds, t.ID)
+
+		if f, found := previous[t.ID]; found {
+			files[t.ID] = f
+			continue
+		}
+
+		f, err := r.analyzeCloudTorrent(provider, t, animeCollection, hints)
+		if err != nil {
+			// Don't cache the result so that the torrent is analyzed again on the next refresh
+			r.logger.Warn().Err(err).Str("torrent", t.Name).Msg("debrid: Failed to analyze cloud torrent")
+			continue
+		}
+		// Torrents without media are analyzed again on the next refresh,
+		// their media can be added to the collection in the meantime
+		if len(f) == 0 {
+			continue
+		}
+		files[t.ID] = f
+		matched = true
+	}
+
+	c.mu.Lock()
+	c.files = files
+	c.itemIds = itemIds
+	c.refreshedAt = time.Now()
+	c.mu.Unlock()
+
+	return matched || !slices.Equal(previousIds, itemIds), nil
+}
+
+func (c *cloudLibrary) isStale() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return time.Since(c.refreshedAt) > cloudLibraryRefreshInterval
+}
+
+// getFiles returns the cached files of media in the collection.
+func (c *cloudLibrary) getFiles(animeCollection *anilist.AnimeCollection) []*CloudFile {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ret := make([]*CloudFile, 0)
+	for _, id := range c.itemIds {
+		for _, f := range c.files[id] {
+			if _, found := animeCollection.GetListEntryFromAnimeId(f.MediaId); found {
+				ret = append(ret, f)
+			}
+		}
+	}
+	return ret
+}
+
+// GetMediaCloudFiles returns the cloud files of a media, sorted by episode.
+func (r *Repository) GetMediaCloudFiles(animeCollection *anilist.AnimeCollection, mediaId int) ([]*CloudFile, error) {
+	files, err := r.GetCloudFiles(animeCollection, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := lo.Filter(files, func(f *CloudFile, _ int) bool {
+		return f.MediaId == mediaId
+	})
+	slices.SortStableFunc(ret, func(a, b *CloudFile) int {
+		return a.Metadata.Episode - b.Metadata.Episode
+	})
+
+	return ret, nil
+}
+
+// HydrateCloudCollection adds the media available in the cloud to the library collection.
+// It doesn't wait for the torrents to be analyzed, the client is notified when the cloud library is updated.
+func (r *Repository) HydrateCloudCollection(opts *HydrateCloudCollectionOptions) {
+	if !r.HasProvider() {
+		return
+	}
+
+	c := r.cloudLibrary
+	if c.isStale() && c.refreshing.CompareAndSwap(false, true) {
+		go func() {
+			defer util.HandlePanicInModuleThen("debrid_client/HydrateCloudCollection", func() {})
+			defer c.refreshing.Store(false)
+
+			changed, err := r.refreshCloudFiles(opts.AnimeCollection)
+			if err != nil {
+				r.logger.Error().Err(err).Msg("debrid: Failed to refresh cloud library")
+				return
+			}
+			if changed {
+				r.wsEventManager.SendEvent(events.DebridCloudLibraryUpdated, nil)
+			}
+		}()
+	}
+
+	mediaIds := lo.Uniq(lo.Map(c.getFiles(opts.AnimeCollection), func(f *CloudFile, _ int) int {
+		return f.MediaId
+	}))
+
+	opts.LibraryCollection.AddCloudEntries(opts.AnimeCollection, mediaIds)
+}
+
+// StartCloudStream plays an episode available in the cloud.
+// A new stream URL is requested from the debrid service each time.
+func (r *Repository) StartCloudStream(opts *StartCloudStreamOptions) (err error) {
+	defer util.HandlePanicInModuleWithError("debrid_client/StartCloudStream", &err)
+
+	provider, err := r.GetProvider()
+	if err != nil {
+		return err
+	}
+
+	files, err := r.GetMediaCloudFiles(opts.AnimeCollection, opts.MediaId)
+	if err != nil {
+		return fmt.Errorf("debridstream: Failed to get cloud files: %w", err)
+	}
+
+	file, found := lo.Find(files, func(f *CloudFile) bool {
+		return f.Metadata.AniDBEpisode == opts.AniDBEpisode
+	})
+	if !found {
+		return fmt.Errorf("debridstream: Episode %s is not available in the cloud", opts.AniDBEpisode)
+	}
+
+	media, _, err := r.streamManager.getMediaInfo(opts.MediaId)
+	if err != nil {
+		return err
+	}
+
+	// Cancel the current stream if it's running
+	r.streamManager.cancelDownload()
+
+	r.wsEventManager.SendEvent(events.DebridStreamState, StreamState{
+		Status:      StreamStatusDownloading,
+		TorrentName: file.TorrentName,
+		Message:     "Retrieving stream link...",
+	})
+
+	streamUrl, err := provider.GetTorrentDownloadUrl(debrid.DownloadTorrentOptions{
+		ID:     file.TorrentItemId,
+		FileId: file.FileId,
+	})
+	if err != nil {
+		r.wsEventManager.SendEvent(events.DebridStreamState, StreamState{
+			Status:      StreamStatusFailed,
+			TorrentName: file.TorrentName,
+			Message:     fmt.Sprintf("Failed to get stream URL, %v", err),
+		})
+		return fmt.Errorf("debridstream: Failed to get stream URL: %w", err)
+	}
+
+	r.wsEventManager.SendEvent(events.DebridStreamState, StreamState{
+		Status:      StreamStatusReady,
+		TorrentName: file.TorrentName,
+		Message:     "Ready to stream the file",
+	})
+
+	r.streamManager.sendStreamToPlayer(streamUrl, file.TorrentName, media, opts.AniDBEpisode, &StartStreamOptions{
+		MediaId:       opts.MediaId,
+		EpisodeNumber: opts.EpisodeNumber,
+		AniDBEpisode:  opts.AniDBEpisode,
+		UserAgent:     opts.UserAgent,
+		ClientId:      opts.ClientId,
+		PlaybackType:  opts.PlaybackType,
+	})
+
+	return nil
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// analyzeCloudTorrent maps the video files of a completed torrent to media episodes.
+// It returns an empty slice if the torrent doesn't correspond to any media.
+func (r *Repository) analyzeCloudTorrent(provider debrid.Provider, t *debrid.TorrentItem, animeCollection *anilist.AnimeCollection, hints map[string]int) ([]*CloudFile, error) {
+
+	// Use the media the torrent was added for if known, otherwise guess it from the torrent name
+	mediaId, forceMatch := hints[strings.ToLower(t.Hash)]
+	if !forceMatch {
+		mediaId, forceMatch = hints[t.ID]
+	}
+	if !forceMatch {
+		media, found := findCloudTorrentMedia(t.Name, animeCollection)
+		if !found {
+			r.logger.Trace().Str("torrent", t.Name).Msg("debrid: No media found for cloud torrent")
+			return make([]*CloudFile, 0), nil
+		}
+		mediaId = media.GetID()
+	}
+
+	media, found := r.completeAnimeCache.Get(mediaId)
+	if !found {
+		var err error
+		media, err = r.platform.GetAnimeWithRelations(mediaId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch media: %w", err)
+		}
+		r.completeAnimeCache.Set(mediaId, media)
+	}
+
+	// Get the files from the torrent in the user's account, the magnet link is only used by providers that can't
+	info, err := provider.GetTorrentInfo(debrid.GetTorrentInfoOptions{
+		ID:         t.ID,
+		MagnetLink: "magnet:?xt=urn:btih:" + t.Hash,
+		InfoHash:   t.Hash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get torrent info: %w", err)
+	}
+
+	files := debrid.FilterVideoFiles(info.Files)
+	if len(files) == 0 {
+		return make([]*CloudFile, 0), nil
+	}
+
+	analyzer := torrentanalyzer.NewAnalyzer(&torrentanalyzer.NewAnalyzerOptions{
+		Logger: r.logger,
+		Filepaths: lo.Map(files, func(f *debrid.TorrentItemFile, _ int) string {
+			return f.Path
+		}),
+		Media:            media,
+		Platform:         r.platform,
+		MetadataProvider: r.metadataProvider,
+		ForceMatch:       forceMatch,
+	})
+
+	analysis, err := analyzer.AnalyzeTorrentFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze files: %w", err)
+	}
+
+	ret := make([]*CloudFile, 0)
+	for _, af := range analysis.GetFiles() {
+		lf := af.GetLocalFile()
+		if lf.MediaId == 0 || lf.Metadata == nil || lf.Metadata.Type == "" || lf.Metadata.Type == anime.LocalFileTypeNC {
+			continue
+		}
+
+		f := files[af.GetIndex()]
+		ret = append(ret, &CloudFile{
+			TorrentItemId: t.ID,
+			TorrentName:   t.Name,
+			FileId:        f.ID,
+			Path:          f.Path,
+			Size:          f.Size,
+			MediaId:       lf.MediaId,
+			Metadata:      lf.Metadata,
+		})
+	}
+
+	r.logger.Debug().Str("torrent", t.Name).Int("files", len(ret)).Msg("debrid: Analyzed cloud torrent")
+
+	return ret, nil
+}
+
+// getCloudMediaHints returns the media IDs of torrents added from Seanime, by torrent item ID and info hash.
+func (r *Repository) getCloudMediaHints() map[string]int {
+	ret := make(map[string]int)
+
+	items, err := r.db.GetDebridTorrentItems()
+	if err == nil {
+		for _, item := range items {
+			if item.MediaId != 0 {
+				ret[item.TorrentItemID] = item.MediaId
+			}
+		}
+	}
+
+	history, err := db_bridge.GetTorrentstreamHistories(r.db)
+	if err == nil {
+		for mId, t := range history {
+			if t.InfoHash != "" {
+				ret[strings.ToLower(t.InfoHash)] = mId
+			}
+		}
+	}
+
+	return ret
+}
+
+// findCloudTorrentMedia returns the media of the collection whose title is the most similar to the torrent name.
+func findCloudTorrentMedia(torrentName string, animeCollection *anilist.AnimeCollection) (*anilist.BaseAnime, bool) {
+	title := habari.Parse(torrentName).Title
+	if title == "" {
+		return nil, false
+	}
+
+	var ret *anilist.BaseAnime
+	bestRating := 0.0
+	for _, media := range animeCollection.GetAllAnime() {
+		res, found := comparison.FindBestMatchWithSorensenDice(&title, media.GetAllTitles())
+		if !found || res == nil {
+			continue
+		}
+		if res.Rating > bestRating {
+			bestRating = res.Rating
+			ret = media
+		}
+	}
+
+	if ret == nil || bestRating < cloudLibraryMinTitleRating {
+		return nil, false
+	}
+
+	return ret, true
+}
@@ -0,0 +1,72 @@
+package debrid_client
+
+import (
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"seanime/internal/api/anilist"
+	"testing"
+)
+
+func TestFindCloudTorrentMedia(t *testing.T) {
+
+	newMedia := func(id int, romaji string, english string) *anilist.AnimeCollection_MediaListCollection_Lists_Entries {
+		return &anilist.AnimeCollection_MediaListCollection_Lists_Entries{
+			Media: &anilist.BaseAnime{
+				ID: id,
+				Title: &anilist.BaseAnime_Title{
+					Romaji:  lo.ToPtr(romaji),
+					English: lo.ToPtr(english),
+				},
+			},
+		}
+	}
+
+	animeCollection := &anilist.AnimeCollection{
+		MediaListCollection: &anilist.AnimeCollection_MediaListCollection{
+			Lists: []*anilist.AnimeCollection_MediaListCollection_Lists{
+				{
+					Status: lo.ToPtr(anilist.MediaListStatusCurrent),
+					Entries: []*anilist.AnimeCollection_MediaListCollection_Lists_Entries{
+						newMedia(154587, "Sousou no Frieren", "Frieren: Beyond Journey's End"),
+						newMedia(21, "ONE PIECE", "ONE PIECE"),
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		torrentName     string
+		expectedMediaId int
+		expectedFound   bool
+	}{
+		{
+			torrentName:     "[SubsPlease] Sousou no Frieren (01-28) (1080p) [Batch]",
+			expectedMediaId: 154587,
+			expectedFound:   true,
+		},
+		{
+			torrentName:     "[Erai-raws] One Piece - 1100 [1080p][Multiple Subtitle].mkv",
+			expectedMediaId: 21,
+			expectedFound:   true,
+		},
+		{
+			torrentName:   "[SubsPlease] Kusuriya no Hitorigoto - 01 (1080p).mkv",
+			expectedFound: false,
+		},
+		{
+			torrentName:   "",
+			expectedFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.torrentName, func(t *testing.T) {
+			media, found := findCloudTorrentMedia(tt.torrentName, animeCollection)
+			assert.Equal(t, tt.expectedFound, found)
+			if tt.expectedFound {
+				assert.Equal(t, tt.expectedMediaId, media.ID)
+			}
+		})
+	}
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"github.com/rs/zerolog"
 	"github.com/samber/mo"
+	"golang.org/x/time/rate"
 	"path/filepath"
 	"seanime/internal/api/anilist"
 	"seanime/internal/api/metadata"
 	"seanime/internal/database/db"
 	"seanime/internal/database/models"
+	"seanime/internal/debrid/alldebrid"
 	"seanime/internal/debrid/debrid"
+	"seanime/internal/debrid/premiumize"
 	"seanime/internal/debrid/realdebrid"
 	"seanime/internal/debrid/torbox"
 	"seanime/internal/events"
@@ -32,11 +35,16 @@ type (
 		settings               *models.DebridSettings
 		wsEventManager         events.WSEventManagerInterface
 		ctxMap                 *result.Map[string, context.CancelFunc]
+		downloads              *result.Map[string, *localDownload]
+		pausedDownloads        *result.Map[string, struct{}]
+		torrentNames           *result.Map[string, string]
+		downloadLimiter        *rate.Limiter
 		downloadLoopCancelFunc context.CancelFunc
 		torrentRepository      *torrent.Repository
 
 		playbackManager    *playbackmanager.PlaybackManager
 		streamManager      *StreamManager
+		cloudLibrary       *cloudLibrary
 		completeAnimeCache *anilist.CompleteAnimeCache
 		metadataProvider   metadata.Provider
 		platform           platform.Platform
@@ -69,9 +77,14 @@ func NewRepository(opts *NewRepositoryOptions) (ret *Repository) {
 		metadataProvider:   opts.MetadataProvider,
 		completeAnimeCache: anilist.NewCompleteAnimeCache(),
 		ctxMap:             result.NewResultMap[string, context.CancelFunc](),
+		downloads:          result.NewResultMap[string, *localDownload](),
+		pausedDownloads:    result.NewResultMap[string, struct{}](),
+		torrentNames:       result.NewResultMap[string, string](),
+		downloadLimiter:    rate.NewLimiter(rate.Inf, downloadBufferSize),
 	}
 
 	ret.streamManager = NewStreamManager(ret)
+	ret.cloudLibrary = newCloudLibrary()
 
 	return
 }
@@ -96,6 +109,9 @@ func (r *Repository) startOrStopDownloadLoop() {
 func (r *Repository) InitializeProvider(settings *models.DebridSettings) error {
 	r.settings = settings
 
+	// The cached cloud files belong to the previous provider
+	r.cloudLibrary.reset()
+
 	if !settings.Enabled {
 		r.provider = mo.None[debrid.Provider]()
 		// Stop the download loop if it's running
@@ -108,6 +124,10 @@ func (r *Repository) InitializeProvider(settings *models.DebridSettings) error {
 		r.provider = mo.Some(torbox.NewTorBox(r.logger))
 	case "realdebrid":
 		r.provider = mo.Some(realdebrid.NewRealDebrid(r.logger))
+	case "alldebrid":
+		r.provider = mo.Some(alldebrid.NewAllDebrid(r.logger))
+	case "premiumize":
+		r.provider = mo.Some(premiumize.NewPremiumize(r.logger))
 	default:
 		r.provider = mo.None[debrid.Provider]()
 	}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"seanime/internal/api/anilist"
 	"seanime/internal/database/db_bridge"
 	"seanime/internal/debrid/debrid"
 	"seanime/internal/events"
 	"seanime/internal/library/playbackmanager"
 	"seanime/internal/util"
 	"strconv"
+	"sync"
 	"time"
 
 	hibiketorrent "github.com/5rahim/hibike/pkg/extension/torrent"
@@ -20,6 +22,7 @@ type (
 		repository            *Repository
 		currentTorrentItemId  string
 		downloadCtxCancelFunc context.CancelFunc
+		mu                    sync.Mutex // Guards downloadCtxCancelFunc
 	}
 
 	StreamPlaybackType string
@@ -82,10 +85,7 @@ func (s *StreamManager) startStream(opts *StartStreamOptions) (err error) {
 		Int("mediaId", opts.MediaId).Msgf("debridstream: Starting stream for episode %s", opts.AniDBEpisode)
 
 	// Cancel the download context if it's running
-	if s.downloadCtxCancelFunc != nil {
-		s.downloadCtxCancelFunc()
-		s.downloadCtxCancelFunc = nil
-	}
+	s.cancelDownload()
 
 	provider, err := s.repository.GetProvider()
 	if err != nil {
@@ -187,19 +187,16 @@ func (s *StreamManager) startStream(opts *StartStreamOptions) (err error) {
 	// Save the current torrent item id
 	s.currentTorrentItemId = torrentItemId
 	ctx, cancelCtx := context.WithCancel(context.Background())
+	s.mu.Lock()
 	s.downloadCtxCancelFunc = cancelCtx
+	s.mu.Unlock()
 
 	// Launch a goroutine that will listen to the added torrent's status
 	go func(ctx context.Context) {
 		defer util.HandlePanicInModuleThen("debrid/client/StartStream", func() {})
 
-		defer func() {
-			// Cancel the context
-			if s.downloadCtxCancelFunc != nil {
-				s.downloadCtxCancelFunc()
-				s.downloadCtxCancelFunc = nil
-			}
-		}()
+		// Cancel the context
+		defer cancelCtx()
 
 		s.repository.logger.Debug().Msg("debridstream: Listening to torrent status")
 
@@ -305,48 +302,7 @@ func (s *StreamManager) startStream(opts *StartStreamOptions) (err error) {
 			return
 		}
 
-		switch opts.PlaybackType {
-		case PlaybackTypeDefault:
-			//
-			// Start the stream
-			//
-			s.repository.logger.Debug().Msg("debridstream: Starting the media player")
-			// Sends the stream to the media player
-			// DEVNOTE: Events are handled by the torrentstream.Repository module
-			err = s.repository.playbackManager.StartStreamingUsingMediaPlayer(fmt.Sprintf("%s - Episode %s", selectedTorrent.Name, aniDbEpisode), &playbackmanager.StartPlayingOptions{
-				Payload:   streamUrl,
-				UserAgent: opts.UserAgent,
-				ClientId:  opts.ClientId,
-			}, media.ToBaseAnime(), aniDbEpisode)
-			if err != nil {
-				// Failed to start the stream, we'll drop the torrents and stop the server
-				s.repository.wsEventManager.SendEvent(events.DebridStreamState, StreamState{
-					Status:      StreamStatusFailed,
-					TorrentName: selectedTorrent.Name,
-					Message:     fmt.Sprintf("Failed to send the stream to the media player, %v", err),
-				})
-			}
-
-		case PlaybackTypeExternalPlayer:
-			// Send the external player link
-			s.repository.wsEventManager.SendEventTo(opts.ClientId, events.ExternalPlayerOpenURL, struct {
-				Url           string `json:"url"`
-				MediaId       int    `json:"mediaId"`
-				EpisodeNumber int    `json:"episodeNumber"`
-			}{
-				Url:           streamUrl,
-				MediaId:       opts.MediaId,
-				EpisodeNumber: opts.EpisodeNumber,
-			})
-
-			// Signal to the client that the torrent has started playing (remove loading status)
-			// We can't know for sure
-			s.repository.wsEventManager.SendEvent(events.DebridStreamState, StreamState{
-				Status:      StreamStatusReady,
-				TorrentName: selectedTorrent.Name,
-				Message:     "External player link sent",
-			})
-		}
+		s.sendStreamToPlayer(streamUrl, selectedTorrent.Name, media, aniDbEpisode, opts)
 
 		go func() {
 			defer util.HandlePanicInModuleThen("debridstream/AddBatchHistory", func() {})
@@ -365,11 +321,64 @@ func (s *StreamManager) startStream(opts *StartStreamOptions) (err error) {
 	return nil
 }
 
-func (s *StreamManager) cancelStream(opts *CancelStreamOptions) {
+// sendStreamToPlayer sends the stream URL to the media player or to the client's external player.
+func (s *StreamManager) sendStreamToPlayer(streamUrl string, torrentName string, media *anilist.CompleteAnime, aniDbEpisode string, opts *StartStreamOptions) {
+	switch opts.PlaybackType {
+	case PlaybackTypeDefault:
+		//
+		// Start the stream
+		//
+		s.repository.logger.Debug().Msg("debridstream: Starting the media player")
+		// Sends the stream to the media player
+		// DEVNOTE: Events are handled by the torrentstream.Repository module
+		err := s.repository.playbackManager.StartStreamingUsingMediaPlayer(fmt.Sprintf("%s - Episode %s", torrentName, aniDbEpisode), &playbackmanager.StartPlayingOptions{
+			Payload:   streamUrl,
+			UserAgent: opts.UserAgent,
+			ClientId:  opts.ClientId,
+		}, media.ToBaseAnime(), aniDbEpisode)
+		if err != nil {
+			// Failed to start the stream, we'll drop the torrents and stop the server
+			s.repository.wsEventManager.SendEvent(events.DebridStreamState, StreamState{
+				Status:      StreamStatusFailed,
+				TorrentName: torrentName,
+				Message:     fmt.Sprintf("Failed to send the stream to the media player, %v", err),
+			})
+		}
+
+	case PlaybackTypeExternalPlayer:
+		// Send the external player link
+		s.repository.wsEventManager.SendEventTo(opts.ClientId, events.ExternalPlayerOpenURL, struct {
+			Url           string `json:"url"`
+			MediaId       int    `json:"mediaId"`
+			EpisodeNumber int    `json:"episodeNumber"`
+		}{
+			Url:           streamUrl,
+			MediaId:       opts.MediaId,
+			EpisodeNumber: opts.EpisodeNumber,
+		})
+
+		// Signal to the client that the torrent has started playing (remove loading status)
+		// We can't know for sure
+		s.repository.wsEventManager.SendEvent(events.DebridStreamState, StreamState{
+			Status:      StreamStatusReady,
+			TorrentName: torrentName,
+			Message:     "External player link sent",
+		})
+	}
+}
+
+// cancelDownload cancels the context of the stream's download if it's running.
+func (s *StreamManager) cancelDownload() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.downloadCtxCancelFunc != nil {
 		s.downloadCtxCancelFunc()
 		s.downloadCtxCancelFunc = nil
 	}
+}
+
+func (s *StreamManager) cancelStream(opts *CancelStreamOptions) {
+	s.cancelDownload()
 
 	if opts.RemoveTorrent && s.currentTorrentItemId != "" {
 		// Remove the torrent from the debrid service
@@ -44,6 +44,9 @@ type (
 	GetTorrentInfoOptions struct {
 		MagnetLink string `json:"magnetLink"`
 		InfoHash   string `json:"infoHash"`
+		// ID of the torrent if it's already in the user's account.
+		// The info is then retrieved from the existing torrent instead of adding the magnet link.
+		ID string `json:"id,omitempty"`
 	}
 
 	DownloadTorrentOptions struct {
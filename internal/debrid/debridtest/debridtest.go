@@ -0,0 +1,260 @@
+// Package debridtest contains a conformance test suite for debrid.Provider implementations.
+//
+// Each provider runs the suite against a fake of its API served by httptest.
+// The fakes know the torrents returned by Torrents and must behave like the real service for a cached torrent:
+// adding it makes it ready immediately and its files can be unrestricted to File.DownloadUrl.
+package debridtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"seanime/internal/debrid/debrid"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const ApiKey = "debridtest-api-key"
+
+type (
+	// Torrent is a torrent known by the fake API.
+	Torrent struct {
+		Hash   string
+		Name   string
+		Magnet string
+		Cached bool // Whether the service has a cached copy of the torrent
+		Files  []*File
+	}
+
+	File struct {
+		Path        string // Path of the file in the torrent, e.g. "Show/Show - 01.mkv"
+		Size        int64
+		DownloadUrl string // Direct link returned by the service for this file
+	}
+
+	Options struct {
+		// NewProvider returns an unauthenticated provider that sends its requests to baseUrl.
+		NewProvider func(baseUrl string) debrid.Provider
+		// NewHandler returns the fake API. Requests that do not use ApiKey must be rejected.
+		NewHandler func(t *testing.T, torrents []*Torrent) http.Handler
+	}
+)
+
+// Torrents returns the torrents known by the fake APIs.
+// The first one is cached, the second one is not.
+func Torrents() []*Torrent {
+	return []*Torrent{
+		{
+			Hash:   "80431b4f9a12f4e06616062d3d3973b9ef99b5e6",
+			Name:   "[Group] Show (01-03)",
+			Magnet: "magnet:?xt=urn:btih:80431b4f9a12f4e06616062d3d3973b9ef99b5e6&dn=%5BGroup%5D%20Show%20%2801-03%29",
+			Cached: true,
+			Files: []*File{
+				{Path: "[Group] Show (01-03)/[Group] Show - 01.mkv", Size: 1_400_000_000, DownloadUrl: "https://cdn.debrid.test/dl/1/%5BGroup%5D%20Show%20-%2001.mkv"},
+				{Path: "[Group] Show (01-03)/[Group] Show - 02.mkv", Size: 1_300_000_000, DownloadUrl: "https://cdn.debrid.test/dl/2/%5BGroup%5D%20Show%20-%2002.mkv"},
+				{Path: "[Group] Show (01-03)/[Group] Show - 03.mkv", Size: 1_350_000_000, DownloadUrl: "https://cdn.debrid.test/dl/3/%5BGroup%5D%20Show%20-%2003.mkv"},
+			},
+		},
+		{
+			Hash:   "2b9f4e1a7c3d5e8f0a1b2c3d4e5f6a7b8c9d0e1f",
+			Name:   "[Group] Other Show - 01.mkv",
+			Magnet: "magnet:?xt=urn:btih:2b9f4e1a7c3d5e8f0a1b2c3d4e5f6a7b8c9d0e1f&dn=%5BGroup%5D%20Other%20Show%20-%2001.mkv",
+			Cached: false,
+			Files: []*File{
+				{Path: "[Group] Other Show - 01.mkv", Size: 700_000_000, DownloadUrl: "https://cdn.debrid.test/dl/4/%5BGroup%5D%20Other%20Show%20-%2001.mkv"},
+			},
+		},
+	}
+}
+
+// Run runs the conformance suite.
+// The subtests share the same provider and server and must run in order.
+func Run(t *testing.T, opts Options) {
+	torrents := Torrents()
+	cached, uncached := torrents[0], torrents[1]
+	// The file that is streamed and downloaded
+	target := cached.Files[1]
+
+	server := httptest.NewServer(opts.NewHandler(t, torrents))
+	t.Cleanup(server.Close)
+
+	provider := opts.NewProvider(server.URL)
+
+	var torrentId string
+	var fileId string
+
+	// Each step depends on the previous ones
+	step := func(name string, f func(t *testing.T)) {
+		if !t.Run(name, f) {
+			t.FailNow()
+		}
+	}
+
+	step("requires authentication", func(t *testing.T) {
+		_, err := provider.GetTorrents()
+		require.ErrorIs(t, err, debrid.ErrNotAuthenticated)
+
+		require.NoError(t, provider.Authenticate(ApiKey))
+	})
+
+	step("instant availability", func(t *testing.T) {
+		require.Empty(t, provider.GetInstantAvailability([]string{}))
+
+		avail := provider.GetInstantAvailability([]string{cached.Hash, uncached.Hash})
+		require.Contains(t, avail, cached.Hash, "cached torrents should be keyed by the requested hash")
+		require.NotContains(t, avail, uncached.Hash)
+		require.NotEmpty(t, avail[cached.Hash].CachedFiles)
+
+		// Hashes are matched regardless of case
+		avail = provider.GetInstantAvailability([]string{strings.ToUpper(cached.Hash)})
+		require.Contains(t, avail, strings.ToUpper(cached.Hash))
+	})
+
+	step("torrent info", func(t *testing.T) {
+		info, err := provider.GetTorrentInfo(debrid.GetTorrentInfoOptions{
+			MagnetLink: cached.Magnet,
+			InfoHash:   cached.Hash,
+		})
+		require.NoError(t, err)
+		require.True(t, strings.EqualFold(cached.Hash, info.Hash))
+		require.Len(t, info.Files, len(cached.Files))
+
+		// The files must be in the order of the torrent, the analyzer uses their index
+		for idx, f := range info.Files {
+			require.Equal(t, path.Base(cached.Files[idx].Path), f.Name)
+			require.Equal(t, "/"+cached.Files[idx].Path, f.Path)
+			require.Equal(t, cached.Files[idx].Size, f.Size)
+			require.NotEmpty(t, f.ID)
+		}
+		fileId = info.Files[1].ID
+
+		// Providers may clean up the torrent asynchronously after retrieving the info
+		time.Sleep(200 * time.Millisecond)
+	})
+
+	step("add torrent", func(t *testing.T) {
+		require.NotEmpty(t, fileId)
+
+		var err error
+		torrentId, err = provider.AddTorrent(debrid.AddTorrentOptions{
+			MagnetLink:   cached.Magnet,
+			InfoHash:     cached.Hash,
+			SelectFileId: fileId,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, torrentId)
+
+		// Adding the same torrent again should not create a new item
+		id, err := provider.AddTorrent(debrid.AddTorrentOptions{
+			MagnetLink:   cached.Magnet,
+			InfoHash:     cached.Hash,
+			SelectFileId: fileId,
+		})
+		require.NoError(t, err)
+		require.Equal(t, torrentId, id)
+	})
+
+	step("get torrent", func(t *testing.T) {
+		require.NotEmpty(t, torrentId)
+
+		item, err := provider.GetTorrent(torrentId)
+		require.NoError(t, err)
+		require.Equal(t, torrentId, item.ID)
+		require.True(t, strings.EqualFold(cached.Hash, item.Hash))
+		require.True(t, item.IsReady, "cached torrents should be ready")
+		require.Equal(t, debrid.TorrentItemStatusCompleted, item.Status)
+		require.Equal(t, 100, item.CompletionPercentage)
+	})
+
+	step("torrent info by id", func(t *testing.T) {
+		require.NotEmpty(t, torrentId)
+
+		// Info of a torrent in the user's account is retrieved without adding it again
+		info, err := provider.GetTorrentInfo(debrid.GetTorrentInfoOptions{
+			ID: torrentId,
+		})
+		require.NoError(t, err)
+		require.True(t, strings.EqualFold(cached.Hash, info.Hash))
+		require.True(t, slices.ContainsFunc(info.Files, func(f *debrid.TorrentItemFile) bool {
+			return f.ID == fileId && f.Path == "/"+cached.Files[1].Path
+		}))
+	})
+
+	step("list torrents", func(t *testing.T) {
+		items, err := provider.GetTorrents()
+		require.NoError(t, err)
+		require.True(t, slices.ContainsFunc(items, func(item *debrid.TorrentItem) bool {
+			return item.ID == torrentId
+		}))
+	})
+
+	step("download url", func(t *testing.T) {
+		downloadUrl, err := provider.GetTorrentDownloadUrl(debrid.DownloadTorrentOptions{
+			ID:     torrentId,
+			FileId: fileId,
+		})
+		require.NoError(t, err)
+		require.Equal(t, target.DownloadUrl, downloadUrl)
+
+		// Without a file, the link(s) of the whole torrent are returned
+		downloadUrl, err = provider.GetTorrentDownloadUrl(debrid.DownloadTorrentOptions{
+			ID: torrentId,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, downloadUrl)
+	})
+
+	step("stream url", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		itemCh := make(chan debrid.TorrentItem)
+		go func() {
+			for range itemCh {
+			}
+		}()
+		defer close(itemCh)
+
+		streamUrl, err := provider.GetTorrentStreamUrl(ctx, debrid.StreamTorrentOptions{
+			ID:     torrentId,
+			FileId: fileId,
+		}, itemCh)
+		require.NoError(t, err)
+		require.Equal(t, target.DownloadUrl, streamUrl)
+	})
+
+	step("delete torrent", func(t *testing.T) {
+		require.NoError(t, provider.DeleteTorrent(torrentId))
+
+		items, err := provider.GetTorrents()
+		require.NoError(t, err)
+		require.False(t, slices.ContainsFunc(items, func(item *debrid.TorrentItem) bool {
+			return item.ID == torrentId
+		}))
+	})
+
+	step("rejects invalid api key", func(t *testing.T) {
+		p := opts.NewProvider(server.URL)
+		require.NoError(t, p.Authenticate("invalid"))
+		_, err := p.GetTorrents()
+		require.Error(t, err)
+		require.False(t, errors.Is(err, debrid.ErrNotAuthenticated))
+	})
+}
+
+// FindTorrent returns the torrent whose hash or magnet link matches.
+// Fakes can use it to resolve the torrents added by the providers.
+func FindTorrent(torrents []*Torrent, hashOrMagnet string) (*Torrent, bool) {
+	for _, t := range torrents {
+		if strings.EqualFold(t.Hash, hashOrMagnet) || t.Magnet == hashOrMagnet || strings.Contains(strings.ToLower(hashOrMagnet), "btih:"+t.Hash) {
+			return t, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,122 @@
+package premiumize
+
+import (
+	"encoding/json"
+	"net/http"
+	"seanime/internal/debrid/debrid"
+	"seanime/internal/debrid/debridtest"
+	"seanime/internal/util"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestPremiumize_Conformance(t *testing.T) {
+	debridtest.Run(t, debridtest.Options{
+		NewProvider: func(baseUrl string) debrid.Provider {
+			pm := NewPremiumize(util.NewLogger()).(*Premiumize)
+			pm.baseUrl = baseUrl
+			return pm
+		},
+		NewHandler: newFakeApi,
+	})
+}
+
+// newFakeApi returns a fake of the Premiumize API.
+func newFakeApi(t *testing.T, torrents []*debridtest.Torrent) http.Handler {
+	var mu sync.Mutex
+	var transfers []*Transfer // Newest first
+	nextId := 1
+
+	writeJson := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	writeError := func(w http.ResponseWriter, message string) {
+		writeJson(w, Response{Status: "error", Message: message})
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("apikey") != debridtest.ApiKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeError(w, "Not logged in.")
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/cache/check":
+			ret := map[string]interface{}{"status": "success", "response": []bool{}, "filename": []string{}, "filesize": []string{}}
+			for _, hash := range r.URL.Query()["items[]"] {
+				torrent, found := debridtest.FindTorrent(torrents, hash)
+				cached := found && torrent.Cached
+				var name, size string
+				if cached {
+					var total int64
+					for _, f := range torrent.Files {
+						total += f.Size
+					}
+					name, size = torrent.Name, strconv.FormatInt(total, 10)
+				}
+				ret["response"] = append(ret["response"].([]bool), cached)
+				ret["filename"] = append(ret["filename"].([]string), name)
+				ret["filesize"] = append(ret["filesize"].([]string), size)
+			}
+			writeJson(w, ret)
+
+		case r.Method == http.MethodPost && r.URL.Path == "/transfer/create":
+			torrent, found := debridtest.FindTorrent(torrents, r.FormValue("src"))
+			if !found {
+				writeError(w, "Invalid magnet link.")
+				return
+			}
+			for _, tr := range transfers {
+				if tr.Src == torrent.Magnet {
+					writeError(w, "You already added this job.")
+					return
+				}
+			}
+			tr := &Transfer{ID: "PM" + strconv.Itoa(nextId), Name: torrent.Name, Status: "queued", Src: torrent.Magnet}
+			nextId++
+			if torrent.Cached {
+				tr.Status = "finished"
+			} else {
+				progress := 0.0
+				tr.Progress = &progress
+			}
+			transfers = append([]*Transfer{tr}, transfers...)
+			writeJson(w, map[string]interface{}{"status": "success", "id": tr.ID, "name": tr.Name, "type": "torrent"})
+
+		case r.Method == http.MethodGet && r.URL.Path == "/transfer/list":
+			writeJson(w, map[string]interface{}{"status": "success", "transfers": append([]*Transfer{}, transfers...)})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/transfer/delete":
+			for idx, tr := range transfers {
+				if tr.ID == r.FormValue("id") {
+					transfers = append(transfers[:idx], transfers[idx+1:]...)
+					writeJson(w, Response{Status: "success"})
+					return
+				}
+			}
+			writeError(w, "Transfer not found.")
+
+		case r.Method == http.MethodPost && r.URL.Path == "/transfer/directdl":
+			torrent, found := debridtest.FindTorrent(torrents, r.FormValue("src"))
+			if !found || !torrent.Cached {
+				writeError(w, "content not in cache")
+				return
+			}
+			content := make([]*DirectDlContent, 0)
+			for _, f := range torrent.Files {
+				content = append(content, &DirectDlContent{Path: f.Path, Size: f.Size, Link: f.DownloadUrl})
+			}
+			writeJson(w, map[string]interface{}{"status": "success", "content": content})
+
+		default:
+			t.Errorf("premiumize: unexpected request %s %s", r.Method, r.URL.String())
+			writeError(w, "Not found.")
+		}
+	})
+}
@@ -0,0 +1,521 @@
+package premiumize
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"github.com/samber/mo"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"seanime/internal/debrid/debrid"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	Premiumize struct {
+		baseUrl string
+		apiKey  mo.Option[string]
+		client  *http.Client
+		logger  *zerolog.Logger
+	}
+
+	// Response is the common part of every response of the API.
+	Response struct {
+		Status  string `json:"status"` // "success" or "error"
+		Message string `json:"message"`
+	}
+
+	CacheCheckResponse struct {
+		Response []bool   `json:"response"`
+		Filename []string `json:"filename"`
+		Filesize []any    `json:"filesize"` // Either a string or a number
+	}
+
+	Transfer struct {
+		ID       string   `json:"id"`
+		Name     string   `json:"name"`
+		Message  string   `json:"message"`
+		Status   string   `json:"status"`   // "waiting", "queued", "running", "finished", "seeding", "error", "timeout", "deleted", "banned"
+		Progress *float64 `json:"progress"` // 0-1, null when finished
+		Src      string   `json:"src"`      // Magnet link
+		FolderID string   `json:"folder_id"`
+		FileID   string   `json:"file_id"`
+	}
+
+	TransferListResponse struct {
+		Transfers []*Transfer `json:"transfers"`
+	}
+
+	TransferCreateResponse struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+
+	DirectDlContent struct {
+		Path       string `json:"path"`
+		Size       int64  `json:"size"`
+		Link       string `json:"link"`
+		StreamLink string `json:"stream_link"`
+	}
+
+	DirectDlResponse struct {
+		Content []*DirectDlContent `json:"content"`
+	}
+)
+
+func NewPremiumize(logger *zerolog.Logger) debrid.Provider {
+	return &Premiumize{
+		baseUrl: "https://www.premiumize.me/api",
+		apiKey:  mo.None[string](),
+		client: &http.Client{
+			Timeout: time.Second * 30,
+		},
+		logger: logger,
+	}
+}
+
+func (t *Premiumize) GetSettings() debrid.Settings {
+	return debrid.Settings{
+		ID:   "premiumize",
+		Name: "Premiumize",
+	}
+}
+
+// doQuery sends a request to the API and unmarshals the response into ret.
+func (t *Premiumize) doQuery(method, endpoint string, params url.Values, ret interface{}) error {
+	apiKey, found := t.apiKey.Get()
+	if !found {
+		return debrid.ErrNotAuthenticated
+	}
+
+	if params == nil {
+		params = url.Values{}
+	}
+
+	var body io.Reader
+	uri := t.baseUrl + endpoint + "?apikey=" + url.QueryEscape(apiKey)
+	if method == http.MethodGet {
+		if len(params) > 0 {
+			uri += "&" + params.Encode()
+		}
+	} else {
+		body = strings.NewReader(params.Encode())
+	}
+
+	req, err := http.NewRequest(method, uri, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var r Response
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.logger.Error().Err(err).Msg("premiumize: Failed to decode response")
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if r.Status != "success" {
+		if r.Message != "" {
+			return fmt.Errorf("request failed: %s", r.Message)
+		}
+		return fmt.Errorf("request failed: %s", resp.Status)
+	}
+
+	if ret == nil {
+		return nil
+	}
+
+	return json.Unmarshal(b, ret)
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (t *Premiumize) Authenticate(apiKey string) error {
+	t.apiKey = mo.Some(apiKey)
+	return nil
+}
+
+// GetInstantAvailability returns the cached torrents.
+// Premiumize does not list the files of cached torrents, so each torrent has a single entry describing the whole torrent.
+func (t *Premiumize) GetInstantAvailability(hashes []string) map[string]debrid.TorrentItemInstantAvailability {
+
+	t.logger.Trace().Strs("hashes", hashes).Msg("premiumize: Checking instant availability")
+
+	availability := make(map[string]debrid.TorrentItemInstantAvailability)
+
+	if len(hashes) == 0 {
+		return availability
+	}
+
+	for _, batch := range lo.Chunk(hashes, 100) {
+		params := url.Values{}
+		for _, hash := range batch {
+			params.Add("items[]", hash)
+		}
+
+		var resp CacheCheckResponse
+		err := t.doQuery(http.MethodGet, "/cache/check", params, &resp)
+		if err != nil {
+			t.logger.Error().Err(err).Msg("premiumize: Failed to get instant availability")
+			return availability
+		}
+
+		// The results are in the same order as the request
+		for idx, cached := range resp.Response {
+			if !cached || idx >= len(batch) {
+				continue
+			}
+
+			file := &debrid.CachedFile{}
+			if idx < len(resp.Filename) {
+				file.Name = resp.Filename[idx]
+			}
+			if idx < len(resp.Filesize) {
+				file.Size = parseSize(resp.Filesize[idx])
+			}
+
+			availability[batch[idx]] = debrid.TorrentItemInstantAvailability{
+				CachedFiles: map[string]*debrid.CachedFile{
+					"0": file,
+				},
+			}
+		}
+	}
+
+	return availability
+}
+
+func (t *Premiumize) AddTorrent(opts debrid.AddTorrentOptions) (string, error) {
+
+	// Check if the torrent is already added
+	// Premiumize rejects transfers that were already added
+	hash := opts.InfoHash
+	if hash == "" {
+		hash = getMagnetHash(opts.MagnetLink)
+	}
+	if hash != "" {
+		transfers, err := t.getTransfers()
+		if err == nil {
+			for _, transfer := range transfers {
+				if strings.EqualFold(getMagnetHash(transfer.Src), hash) {
+					t.logger.Debug().Str("torrentId", transfer.ID).Msg("premiumize: Torrent already added")
+					return transfer.ID, nil
+				}
+			}
+		}
+	}
+
+	t.logger.Trace().Str("magnetLink", opts.MagnetLink).Msg("premiumize: Adding torrent")
+
+	// Premiumize downloads all the files, opts.SelectFileId is not used
+	var resp TransferCreateResponse
+	err := t.doQuery(http.MethodPost, "/transfer/create", url.Values{"src": {opts.MagnetLink}}, &resp)
+	if err != nil {
+		return "", fmt.Errorf("premiumize: Failed to add torrent: %w", err)
+	}
+
+	t.logger.Debug().Str("torrentId", resp.ID).Str("torrentName", resp.Name).Msg("premiumize: Torrent added")
+
+	return resp.ID, nil
+}
+
+// GetTorrentStreamUrl blocks until the torrent is downloaded and returns the stream URL for the torrent file by calling GetTorrentDownloadUrl.
+func (t *Premiumize) GetTorrentStreamUrl(ctx context.Context, opts debrid.StreamTorrentOptions, itemCh chan debrid.TorrentItem) (streamUrl string, err error) {
+
+	t.logger.Trace().Str("torrentId", opts.ID).Str("fileId", opts.FileId).Msg("premiumize: Retrieving stream link")
+
+	doneCh := make(chan struct{})
+
+	go func(ctx context.Context) {
+		defer func() {
+			close(doneCh)
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				return
+			case <-time.After(4 * time.Second):
+				torrent, _err := t.GetTorrent(opts.ID)
+				if _err != nil {
+					t.logger.Error().Err(_err).Msg("premiumize: Failed to get torrent")
+					err = fmt.Errorf("premiumize: Failed to get torrent: %w", _err)
+					return
+				}
+
+				itemCh <- *torrent
+
+				if torrent.Status == debrid.TorrentItemStatusError {
+					err = fmt.Errorf("premiumize: Torrent failed to download")
+					return
+				}
+
+				// Check if the torrent is ready
+				if torrent.IsReady {
+					streamUrl, err = t.GetTorrentDownloadUrl(debrid.DownloadTorrentOptions{
+						ID:     opts.ID,
+						FileId: opts.FileId,
+					})
+					if err != nil {
+						t.logger.Error().Err(err).Msg("premiumize: Failed to get download URL")
+					}
+					return
+				}
+			}
+		}
+	}(ctx)
+
+	<-doneCh
+
+	return
+}
+
+// GetTorrentDownloadUrl returns the download URL for the torrent file.
+// If no opts.FileId is provided, it will return a comma-separated list of download URLs for all files in the torrent.
+func (t *Premiumize) GetTorrentDownloadUrl(opts debrid.DownloadTorrentOptions) (downloadUrl string, err error) {
+
+	t.logger.Trace().Str("torrentId", opts.ID).Msg("premiumize: Retrieving download link")
+
+	transfer, err := t.getTransfer(opts.ID)
+	if err != nil {
+		return "", fmt.Errorf("premiumize: Failed to get download URL: %w", err)
+	}
+
+	if !isTransferReady(transfer) {
+		return "", fmt.Errorf("premiumize: Torrent is not ready")
+	}
+
+	content, err := t.getDirectDl(transfer.Src)
+	if err != nil {
+		return "", fmt.Errorf("premiumize: Failed to get download URL: %w", err)
+	}
+
+	if opts.FileId != "" {
+		idx, err := strconv.Atoi(opts.FileId)
+		if err != nil || idx < 0 || idx >= len(content) {
+			return "", fmt.Errorf("premiumize: File not found")
+		}
+		downloadUrl = content[idx].Link
+	} else {
+		downloadUrl = strings.Join(lo.Map(content, func(c *DirectDlContent, _ int) string {
+			return c.Link
+		}), ",")
+	}
+
+	t.logger.Debug().Str("downloadUrl", downloadUrl).Msg("premiumize: Download link retrieved")
+
+	return downloadUrl, nil
+}
+
+func (t *Premiumize) GetTorrent(id string) (ret *debrid.TorrentItem, err error) {
+	transfer, err := t.getTransfer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDebridTorrent(transfer), nil
+}
+
+// GetTorrentInfo returns the torrent's data.
+// Premiumize only lists the files of cached torrents.
+// The direct download lookup doesn't add the torrent to the user's account, the ID of an existing transfer is only used to get its magnet link.
+func (t *Premiumize) GetTorrentInfo(opts debrid.GetTorrentInfoOptions) (ret *debrid.TorrentInfo, err error) {
+
+	if opts.MagnetLink == "" && opts.ID != "" {
+		transfer, err := t.getTransfer(opts.ID)
+		if err != nil {
+			return nil, err
+		}
+		opts.MagnetLink = transfer.Src
+	}
+
+	if opts.MagnetLink == "" {
+		return nil, fmt.Errorf("premiumize: Magnet link is required")
+	}
+
+	content, err := t.getDirectDl(opts.MagnetLink)
+	if err != nil {
+		return nil, fmt.Errorf("premiumize: Failed to get info: %w", err)
+	}
+
+	hash := opts.InfoHash
+	if hash == "" {
+		hash = getMagnetHash(opts.MagnetLink)
+	}
+
+	ret = &debrid.TorrentInfo{
+		Hash: hash,
+	}
+
+	for idx, c := range content {
+		ret.Size += c.Size
+		ret.Files = append(ret.Files, &debrid.TorrentItemFile{
+			ID:    strconv.Itoa(idx), // Index of the file in the content
+			Index: idx,
+			Name:  path.Base(c.Path), // e.g. "Big Buck Bunny.mp4"
+			Path:  "/" + c.Path,      // e.g. "/Big Buck Bunny/Big Buck Bunny.mp4"
+			Size:  c.Size,
+		})
+	}
+
+	// The name of the torrent is the root folder, or the file name for single-file torrents
+	if len(content) > 0 {
+		ret.Name = strings.Split(content[0].Path, "/")[0]
+	}
+
+	return ret, nil
+}
+
+func (t *Premiumize) GetTorrents() (ret []*debrid.TorrentItem, err error) {
+
+	transfers, err := t.getTransfers()
+	if err != nil {
+		return nil, fmt.Errorf("premiumize: Failed to get torrents: %w", err)
+	}
+
+	// Transfers are already sorted by date, newest first
+	for _, transfer := range transfers {
+		ret = append(ret, toDebridTorrent(transfer))
+	}
+
+	return ret, nil
+}
+
+func (t *Premiumize) DeleteTorrent(id string) error {
+
+	err := t.doQuery(http.MethodPost, "/transfer/delete", url.Values{"id": {id}}, nil)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("premiumize: Failed to delete torrent")
+		return fmt.Errorf("premiumize: Failed to delete torrent: %w", err)
+	}
+
+	return nil
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (t *Premiumize) getTransfers() ([]*Transfer, error) {
+	var resp TransferListResponse
+	err := t.doQuery(http.MethodGet, "/transfer/list", nil, &resp)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("premiumize: Failed to get transfers")
+		return nil, err
+	}
+
+	return resp.Transfers, nil
+}
+
+// getTransfer returns the transfer with the given ID.
+// The API does not have an endpoint for a single transfer.
+func (t *Premiumize) getTransfer(id string) (*Transfer, error) {
+	transfers, err := t.getTransfers()
+	if err != nil {
+		return nil, fmt.Errorf("premiumize: Failed to get torrent: %w", err)
+	}
+
+	transfer, found := lo.Find(transfers, func(tr *Transfer) bool {
+		return tr.ID == id
+	})
+	if !found {
+		return nil, fmt.Errorf("premiumize: Torrent not found")
+	}
+
+	return transfer, nil
+}
+
+// getDirectDl returns the files of a cached torrent with their download links.
+func (t *Premiumize) getDirectDl(src string) ([]*DirectDlContent, error) {
+	var resp DirectDlResponse
+	err := t.doQuery(http.MethodPost, "/transfer/directdl", url.Values{"src": {src}}, &resp)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("premiumize: Failed to get direct download links")
+		return nil, err
+	}
+
+	return resp.Content, nil
+}
+
+func getMagnetHash(magnetLink string) string {
+	u, err := url.Parse(magnetLink)
+	if err != nil {
+		return ""
+	}
+	for _, xt := range u.Query()["xt"] {
+		if hash, found := strings.CutPrefix(xt, "urn:btih:"); found {
+			return strings.ToLower(hash)
+		}
+	}
+	return ""
+}
+
+func parseSize(v any) int64 {
+	switch s := v.(type) {
+	case float64:
+		return int64(s)
+	case string:
+		size, _ := strconv.ParseInt(s, 10, 64)
+		return size
+	}
+	return 0
+}
+
+func isTransferReady(tr *Transfer) bool {
+	return tr.Status == "finished" || tr.Status == "seeding"
+}
+
+func toDebridTorrent(tr *Transfer) (ret *debrid.TorrentItem) {
+
+	completionPercentage := 0
+	if isTransferReady(tr) {
+		completionPercentage = 100
+	} else if tr.Progress != nil {
+		completionPercentage = int(*tr.Progress * 100)
+	}
+
+	ret = &debrid.TorrentItem{
+		ID:                   tr.ID,
+		Name:                 tr.Name,
+		Hash:                 getMagnetHash(tr.Src),
+		CompletionPercentage: completionPercentage,
+		ETA:                  tr.Message,
+		Status:               toDebridTorrentStatus(tr),
+		IsReady:              isTransferReady(tr),
+	}
+
+	return
+}
+
+func toDebridTorrentStatus(tr *Transfer) debrid.TorrentItemStatus {
+	switch tr.Status {
+	case "waiting", "queued":
+		return debrid.TorrentItemStatusStalled
+	case "running":
+		return debrid.TorrentItemStatusDownloading
+	case "finished":
+		return debrid.TorrentItemStatusCompleted
+	case "seeding":
+		return debrid.TorrentItemStatusSeeding
+	default:
+		return debrid.TorrentItemStatusError
+	}
+}
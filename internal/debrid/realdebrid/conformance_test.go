@@ -0,0 +1,179 @@
+package realdebrid
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"seanime/internal/debrid/debrid"
+	"seanime/internal/debrid/debridtest"
+	"seanime/internal/util"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRealDebrid_Conformance(t *testing.T) {
+	debridtest.Run(t, debridtest.Options{
+		NewProvider: func(baseUrl string) debrid.Provider {
+			rd := NewRealDebridT(util.NewLogger())
+			rd.baseUrl = baseUrl
+			return rd
+		},
+		NewHandler: newFakeApi,
+	})
+}
+
+type fakeTorrent struct {
+	id       string
+	torrent  *debridtest.Torrent
+	selected bool
+	added    time.Time
+}
+
+// newFakeApi returns a fake of the Real-Debrid REST API.
+func newFakeApi(t *testing.T, torrents []*debridtest.Torrent) http.Handler {
+	var mu sync.Mutex
+	items := make(map[string]*fakeTorrent)
+	links := make(map[string]string) // Hoster link -> download link
+	nextId := 1
+
+	writeJson := func(w http.ResponseWriter, status int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	writeError := func(w http.ResponseWriter, status int, code int, details string) {
+		writeJson(w, status, ErrorResponse{Error: details, ErrorDetails: details, ErrorCode: code})
+	}
+	getInfo := func(item *fakeTorrent) *TorrentInfo {
+		ret := &TorrentInfo{
+			ID:               item.id,
+			Filename:         item.torrent.Name,
+			OriginalFilename: item.torrent.Name,
+			Hash:             item.torrent.Hash,
+			Added:            item.added.Format(time.RFC3339),
+			Status:           "waiting_files_selection",
+			Files:            make([]*TorrentInfoFile, 0),
+			Links:            make([]string, 0),
+		}
+		for idx, f := range item.torrent.Files {
+			selected := 0
+			if item.selected {
+				selected = 1
+				ret.Bytes += f.Size
+				link := fmt.Sprintf("https://real-debrid.test/d/%s%d", item.id, idx)
+				links[link] = f.DownloadUrl
+				ret.Links = append(ret.Links, link)
+			}
+			ret.OriginalBytes += f.Size
+			// File IDs start at 1
+			ret.Files = append(ret.Files, &TorrentInfoFile{ID: idx + 1, Path: "/" + f.Path, Bytes: f.Size, Selected: selected})
+		}
+		if item.selected {
+			if item.torrent.Cached {
+				ret.Status = "downloaded"
+				ret.Progress = 100
+			} else {
+				ret.Status = "downloading"
+			}
+		}
+		return ret
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+debridtest.ApiKey {
+			writeError(w, http.StatusUnauthorized, 8, "bad_token")
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/torrents/instantAvailability/"):
+			ret := make(instantAvailabilityResponse)
+			for _, hash := range strings.Split(strings.TrimPrefix(r.URL.Path, "/torrents/instantAvailability/"), "/") {
+				torrent, found := debridtest.FindTorrent(torrents, hash)
+				if !found || !torrent.Cached {
+					ret[strings.ToLower(hash)] = map[string][]map[int]instantAvailabilityFile{}
+					continue
+				}
+				variant := make(map[int]instantAvailabilityFile)
+				for idx, f := range torrent.Files {
+					variant[idx+1] = instantAvailabilityFile{Filename: path.Base(f.Path), Filesize: f.Size}
+				}
+				ret[strings.ToLower(hash)] = map[string][]map[int]instantAvailabilityFile{"rd": {variant}}
+			}
+			writeJson(w, http.StatusOK, ret)
+
+		case r.Method == http.MethodPost && r.URL.Path == "/torrents/addMagnet":
+			torrent, found := debridtest.FindTorrent(torrents, r.FormValue("magnet"))
+			if !found {
+				writeError(w, http.StatusBadRequest, 30, "invalid_magnet")
+				return
+			}
+			item := &fakeTorrent{id: "RD" + strconv.Itoa(nextId), torrent: torrent, added: time.Now()}
+			nextId++
+			items[item.id] = item
+			writeJson(w, http.StatusCreated, addMagnetResponse{ID: item.id, URI: "/torrents/info/" + item.id})
+
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/torrents/selectFiles/"):
+			item, found := items[strings.TrimPrefix(r.URL.Path, "/torrents/selectFiles/")]
+			if !found {
+				writeError(w, http.StatusNotFound, 7, "unknown_ressource")
+				return
+			}
+			item.selected = true
+			w.WriteHeader(http.StatusNoContent)
+
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/torrents/info/"):
+			item, found := items[strings.TrimPrefix(r.URL.Path, "/torrents/info/")]
+			if !found {
+				writeError(w, http.StatusNotFound, 7, "unknown_ressource")
+				return
+			}
+			writeJson(w, http.StatusOK, getInfo(item))
+
+		case r.Method == http.MethodGet && r.URL.Path == "/torrents":
+			ret := make([]*Torrent, 0)
+			for _, item := range items {
+				info := getInfo(item)
+				ret = append(ret, &Torrent{
+					ID:       info.ID,
+					Filename: info.Filename,
+					Hash:     info.Hash,
+					Bytes:    info.Bytes,
+					Progress: info.Progress,
+					Status:   info.Status,
+					Added:    info.Added,
+					Links:    info.Links,
+				})
+			}
+			writeJson(w, http.StatusOK, ret)
+
+		case r.Method == http.MethodPost && r.URL.Path == "/unrestrict/link":
+			downloadUrl, found := links[r.FormValue("link")]
+			if !found {
+				writeError(w, http.StatusServiceUnavailable, 19, "hoster_unavailable")
+				return
+			}
+			writeJson(w, http.StatusOK, unrestrictLinkResponse{Link: r.FormValue("link"), Download: downloadUrl, Streamable: 1})
+
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/torrents/delete/"):
+			id := strings.TrimPrefix(r.URL.Path, "/torrents/delete/")
+			if _, found := items[id]; !found {
+				writeError(w, http.StatusNotFound, 7, "unknown_ressource")
+				return
+			}
+			delete(items, id)
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			t.Errorf("realdebrid: unexpected request %s %s", r.Method, r.URL.String())
+			writeError(w, http.StatusNotFound, 7, "unknown_ressource")
+		}
+	})
+}
@@ -485,6 +485,14 @@ func (t *RealDebrid) GetTorrent(id string) (ret *debrid.TorrentItem, err error)
 // This adds the torrent to the user's account without downloading it and removes it after getting the info.
 func (t *RealDebrid) GetTorrentInfo(opts debrid.GetTorrentInfoOptions) (ret *debrid.TorrentInfo, err error) {
 
+	if opts.ID != "" {
+		torrent, err := t.getTorrentInfo(opts.ID)
+		if err != nil {
+			return nil, err
+		}
+		return toDebridTorrentInfo(torrent), nil
+	}
+
 	if opts.MagnetLink == "" {
 		return nil, fmt.Errorf("realdebrid: Magnet link is required")
 	}
@@ -590,6 +598,12 @@ func (t *RealDebrid) _selectFiles(id string, idStr string) (err error) {
 		return fmt.Errorf("realdebrid: Failed to select files: %w", err)
 	}
 
+	// Write the closing boundary
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("realdebrid: Failed to select files: %w", err)
+	}
+
 	_, err = t.doQuery("POST", t.baseUrl+fmt.Sprintf("/torrents/selectFiles/%s", id), &body, writer.FormDataContentType())
 	if err != nil {
 		t.logger.Error().Err(err).Msg("realdebrid: Failed to select files")
@@ -617,6 +631,12 @@ func (t *RealDebrid) addMagnet(magnet string) (ret *addMagnetResponse, err error
 		return nil, fmt.Errorf("torbox: Failed to add torrent: %w", err)
 	}
 
+	// Write the closing boundary
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("realdebrid: Failed to add torrent: %w", err)
+	}
+
 	resp, err := t.doQuery("POST", t.baseUrl+"/torrents/addMagnet", &body, writer.FormDataContentType())
 	if err != nil {
 		t.logger.Error().Err(err).Msg("realdebrid: Failed to add torrent")
@@ -643,6 +663,12 @@ func (t *RealDebrid) unrestrictLink(link string) (ret *unrestrictLinkResponse, e
 		return nil, fmt.Errorf("realdebrid: Failed to unrestrict link: %w", err)
 	}
 
+	// Write the closing boundary
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("realdebrid: Failed to unrestrict link: %w", err)
+	}
+
 	resp, err := t.doQuery("POST", t.baseUrl+"/unrestrict/link", &body, writer.FormDataContentType())
 	if err != nil {
 		t.logger.Error().Err(err).Msg("realdebrid: Failed to unrestrict link")
@@ -0,0 +1,178 @@
+package torbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"seanime/internal/debrid/debrid"
+	"seanime/internal/debrid/debridtest"
+	"seanime/internal/util"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTorBox_Conformance(t *testing.T) {
+	debridtest.Run(t, debridtest.Options{
+		NewProvider: func(baseUrl string) debrid.Provider {
+			tb := NewTorBox(util.NewLogger()).(*TorBox)
+			tb.baseUrl = baseUrl
+			return tb
+		},
+		NewHandler: newFakeApi,
+	})
+}
+
+// newFakeApi returns a fake of the TorBox API.
+func newFakeApi(t *testing.T, torrents []*debridtest.Torrent) http.Handler {
+	var mu sync.Mutex
+	items := make(map[int]*Torrent)
+	sources := make(map[int]*debridtest.Torrent)
+	nextId := 1
+
+	writeData := func(w http.ResponseWriter, data interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Response{Success: true, Data: data})
+	}
+	writeError := func(w http.ResponseWriter, status int, detail string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(Response{Success: false, Detail: detail})
+	}
+	toTorrentInfo := func(torrent *debridtest.Torrent) *TorrentInfo {
+		ret := &TorrentInfo{Name: torrent.Name, Hash: torrent.Hash}
+		for _, f := range torrent.Files {
+			ret.Size += f.Size
+			ret.Files = append(ret.Files, &TorrentInfoFile{Name: f.Path, Size: f.Size})
+		}
+		return ret
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+debridtest.ApiKey {
+			writeError(w, http.StatusForbidden, "Invalid API key.")
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/torrents/checkcached":
+			ret := make([]*InstantAvailabilityItem, 0)
+			for _, hash := range strings.Split(r.URL.Query().Get("hash"), ",") {
+				torrent, found := debridtest.FindTorrent(torrents, hash)
+				if !found || !torrent.Cached {
+					continue
+				}
+				item := &InstantAvailabilityItem{Name: torrent.Name, Hash: torrent.Hash}
+				for _, f := range torrent.Files {
+					item.Size += f.Size
+					item.Files = append(item.Files, struct {
+						Name string `json:"name"`
+						Size int64  `json:"size"`
+					}{Name: f.Path, Size: f.Size})
+				}
+				ret = append(ret, item)
+			}
+			writeData(w, ret)
+
+		case r.Method == http.MethodGet && r.URL.Path == "/torrents/torrentinfo":
+			torrent, found := debridtest.FindTorrent(torrents, r.URL.Query().Get("hash"))
+			if !found {
+				writeError(w, http.StatusNotFound, "Could not get torrent info.")
+				return
+			}
+			writeData(w, toTorrentInfo(torrent))
+
+		case r.Method == http.MethodPost && r.URL.Path == "/torrents/createtorrent":
+			torrent, found := debridtest.FindTorrent(torrents, r.FormValue("magnet"))
+			if !found {
+				writeError(w, http.StatusBadRequest, "Invalid magnet link.")
+				return
+			}
+			item := &Torrent{
+				ID:               nextId,
+				Hash:             torrent.Hash,
+				Name:             torrent.Name,
+				CreatedAt:        time.Now().Format(time.RFC3339Nano),
+				Magnet:           torrent.Magnet,
+				DownloadState:    "downloading",
+				DownloadPresent:  torrent.Cached,
+				DownloadFinished: torrent.Cached,
+			}
+			if torrent.Cached {
+				item.DownloadState = "cached"
+				item.Progress = 1
+			}
+			for idx, f := range torrent.Files {
+				item.Size += f.Size
+				item.Files = append(item.Files, &File{ID: idx, Name: f.Path, ShortName: path.Base(f.Path), Size: int(f.Size)})
+			}
+			items[item.ID] = item
+			sources[item.ID] = torrent
+			nextId++
+			writeData(w, map[string]interface{}{"torrent_id": item.ID, "name": item.Name, "hash": item.Hash})
+
+		case r.Method == http.MethodGet && r.URL.Path == "/torrents/mylist":
+			if id := r.URL.Query().Get("id"); id != "" {
+				item, found := items[util.StringToIntMust(id)]
+				if !found {
+					writeError(w, http.StatusNotFound, "Torrent not found.")
+					return
+				}
+				writeData(w, item)
+				return
+			}
+			ret := make([]*Torrent, 0)
+			for _, item := range items {
+				ret = append(ret, item)
+			}
+			writeData(w, ret)
+
+		case r.Method == http.MethodGet && r.URL.Path == "/torrents/requestdl":
+			q := r.URL.Query()
+			if q.Get("token") != debridtest.ApiKey {
+				writeError(w, http.StatusForbidden, "Invalid API key.")
+				return
+			}
+			id := util.StringToIntMust(q.Get("torrent_id"))
+			item, found := items[id]
+			if !found || !item.DownloadPresent {
+				writeError(w, http.StatusNotFound, "Torrent not ready.")
+				return
+			}
+			if q.Get("file_id") == "" {
+				writeData(w, fmt.Sprintf("https://cdn.debrid.test/zip/%d.zip", id))
+				return
+			}
+			fileId, _ := strconv.Atoi(q.Get("file_id"))
+			if fileId < 0 || fileId >= len(sources[id].Files) {
+				writeError(w, http.StatusNotFound, "File not found.")
+				return
+			}
+			writeData(w, sources[id].Files[fileId].DownloadUrl)
+
+		case r.Method == http.MethodPost && r.URL.Path == "/torrents/controltorrent":
+			var body struct {
+				ID        int    `json:"torrent_id"`
+				Operation string `json:"operation"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if _, found := items[body.ID]; !found || body.Operation != "delete" {
+				writeError(w, http.StatusBadRequest, "Invalid operation.")
+				return
+			}
+			delete(items, body.ID)
+			delete(sources, body.ID)
+			writeData(w, nil)
+
+		default:
+			t.Errorf("torbox: unexpected request %s %s", r.Method, r.URL.String())
+			writeError(w, http.StatusNotFound, "Not found.")
+		}
+	})
+}
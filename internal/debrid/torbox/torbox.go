@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"github.com/dustin/go-humanize"
 	"github.com/rs/zerolog"
+	"github.com/samber/lo"
 	"github.com/samber/mo"
 	"io"
 	"mime/multipart"
@@ -186,12 +187,20 @@ func (t *TorBox) GetInstantAvailability(hashes []string) map[string]debrid.Torre
 		}
 
 		for _, item := range items {
-			availability[item.Hash] = debrid.TorrentItemInstantAvailability{
+			// Use the hash as it was requested, the API returns lowercase hashes
+			hash, found := lo.Find(batch, func(h string) bool {
+				return strings.EqualFold(h, item.Hash)
+			})
+			if !found {
+				continue
+			}
+
+			availability[hash] = debrid.TorrentItemInstantAvailability{
 				CachedFiles: make(map[string]*debrid.CachedFile),
 			}
 
 			for idx, file := range item.Files {
-				availability[item.Hash].CachedFiles[strconv.Itoa(idx)] = &debrid.CachedFile{
+				availability[hash].CachedFiles[strconv.Itoa(idx)] = &debrid.CachedFile{
 					Name: file.Name,
 					Size: file.Size,
 				}
@@ -390,6 +399,22 @@ func (t *TorBox) getTorrent(id string) (ret *Torrent, err error) {
 // GetTorrentInfo uses the info hash to return the torrent's data, retrieved from the Bittorrent network without adding it to the user's account.
 func (t *TorBox) GetTorrentInfo(opts debrid.GetTorrentInfoOptions) (ret *debrid.TorrentInfo, err error) {
 
+	if opts.ID != "" {
+		torrent, err := t.getTorrent(opts.ID)
+		if err != nil {
+			return nil, err
+		}
+		info := &TorrentInfo{
+			Name: torrent.Name,
+			Hash: torrent.Hash,
+			Size: torrent.Size,
+		}
+		for _, f := range torrent.Files {
+			info.Files = append(info.Files, &TorrentInfoFile{Name: f.Name, Size: int64(f.Size)})
+		}
+		return toDebridTorrentInfo(info), nil
+	}
+
 	if opts.InfoHash == "" {
 		return nil, fmt.Errorf("torbox: Info hash is required to retrieve torrent info")
 	}
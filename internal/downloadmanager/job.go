@@ -0,0 +1,97 @@
+package downloadmanager
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// Job is a download from any of the sources handled by the manager.
+	Job struct {
+		// Unique ID of the job, "{source}:{sourceId}"
+		ID string `json:"id"`
+		// ID of the download in its source, e.g. the info hash of a torrent
+		SourceId string    `json:"sourceId"`
+		Source   JobSource `json:"source"`
+		Name     string    `json:"name"`
+		// 0 if the download isn't linked to a media
+		MediaId int `json:"mediaId"`
+		// Episode or chapter number, empty if the download isn't a single episode or chapter
+		Episode         string   `json:"episode"`
+		TotalBytes      int64    `json:"totalBytes"`
+		DownloadedBytes int64    `json:"downloadedBytes"`
+		Progress        float64  `json:"progress"` // 0 to 1
+		Speed           int64    `json:"speed"`    // Bytes per second
+		Eta             int64    `json:"eta"`      // Seconds, -1 if unknown
+		State           JobState `json:"state"`
+		Priority        Priority `json:"priority"`
+		// Reason the job was paused, empty if it wasn't paused by the user or the manager
+		PausedBy PauseReason `json:"pausedBy"`
+		Error    string      `json:"error,omitempty"`
+	}
+
+	JobSource   string
+	JobState    string
+	Priority    int
+	PauseReason string
+)
+
+const (
+	JobSourceTorrentClient JobSource = "torrent-client"
+	JobSourceDebrid        JobSource = "debrid"
+	JobSourceTorrentstream JobSource = "torrentstream"
+	JobSourceManga         JobSource = "manga"
+	JobSourceOnlinestream  JobSource = "onlinestream"
+)
+
+const (
+	JobStateQueued      JobState = "queued"
+	JobStateDownloading JobState = "downloading"
+	JobStatePaused      JobState = "paused"
+	JobStateCompleted   JobState = "completed"
+	JobStateFailed      JobState = "failed"
+)
+
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+)
+
+const (
+	// PauseReasonUser means the job was paused from the download manager
+	PauseReasonUser PauseReason = "user"
+	// PauseReasonGlobal means all downloads are paused
+	PauseReasonGlobal PauseReason = "global"
+	// PauseReasonSchedule means the job is paused outside the schedule windows
+	PauseReasonSchedule PauseReason = "schedule"
+	// PauseReasonPriority means the job yields to higher priority jobs of the same source
+	PauseReasonPriority PauseReason = "priority"
+)
+
+func jobID(source JobSource, sourceId string) string {
+	return fmt.Sprintf("%s:%s", source, sourceId)
+}
+
+// parseJobID returns the source and the source ID of a job ID.
+func parseJobID(id string) (JobSource, string, bool) {
+	source, sourceId, found := strings.Cut(id, ":")
+	if !found || sourceId == "" {
+		return "", "", false
+	}
+	return JobSource(source), sourceId, true
+}
+
+// isActive returns true if the job hasn't finished.
+func (j *Job) isActive() bool {
+	return j.State == JobStateQueued || j.State == JobStateDownloading || j.State == JobStatePaused
+}
+
+// isRunning returns true if the job is downloading or waiting to download.
+func (j *Job) isRunning() bool {
+	return j.State == JobStateQueued || j.State == JobStateDownloading
+}
+
+func (p Priority) isValid() bool {
+	return p == PriorityLow || p == PriorityNormal || p == PriorityHigh
+}
@@ -0,0 +1,584 @@
+package downloadmanager
+
+import (
+	"context"
+	"errors"
+	"seanime/internal/database/db"
+	"seanime/internal/database/models"
+	"seanime/internal/events"
+	"seanime/internal/eventstream"
+	"seanime/internal/util"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+)
+
+const (
+	refreshInterval = 5 * time.Second
+)
+
+var (
+	ErrJobNotFound = errors.New("download manager: Job not found")
+	// ErrUnsupported is returned by sources that can't pause or resume the jobs separately
+	ErrUnsupported = errors.New("download manager: The source can't pause or resume these jobs separately")
+)
+
+type (
+	// Manager aggregates the downloads of the torrent client, the debrid service, torrent streaming imports,
+	// the manga chapter queue and the online stream queue.
+	// It pauses and resumes them according to the global pause, the schedule windows and the job priorities.
+	Manager struct {
+		logger         *zerolog.Logger
+		wsEventManager events.WSEventManagerInterface
+		db             *db.Database
+		sources        []Source
+		now            func() time.Time
+
+		refreshMu sync.Mutex // Only one refresh at a time, the sources are contacted without holding mu
+		mu        sync.Mutex
+		settings  *models.DownloadManagerSettings
+		jobs      []*Job
+		// Priorities set by the user, normal priority is not stored
+		priorities map[string]Priority
+		// Jobs paused by the user, they are never resumed by the manager
+		pausedByUser map[string]struct{}
+		// Jobs paused by the manager, they are resumed once the reason no longer applies
+		pausedByManager map[string]PauseReason
+		samples         map[string]byteSample
+		// Rate limit applied to the sources, in bytes per second
+		rateLimit        int64
+		inScheduleWindow bool
+		cancel           context.CancelFunc
+	}
+
+	// sourceResult is the result of Source.GetJobs
+	sourceResult struct {
+		jobs []*Job
+		err  error
+	}
+
+	byteSample struct {
+		bytes int64
+		at    time.Time
+		speed int64
+	}
+
+	// Snapshot is the state of the download manager.
+	Snapshot struct {
+		Jobs            []*Job `json:"jobs"`
+		Paused          bool   `json:"paused"`
+		ScheduleEnabled bool   `json:"scheduleEnabled"`
+		// Whether the current time is inside a schedule window
+		InScheduleWindow bool `json:"inScheduleWindow"`
+		// Current download limit in bytes per second, 0 if there is no limit
+		RateLimit int64 `json:"rateLimit"`
+		// Combined download speed of all jobs in bytes per second
+		TotalSpeed int64 `json:"totalSpeed"`
+	}
+
+	NewManagerOptions struct {
+		Logger         *zerolog.Logger
+		WSEventManager events.WSEventManagerInterface
+		Database       *db.Database // Optional, settings are not persisted if nil
+		Sources        []Source
+	}
+)
+
+func NewManager(opts *NewManagerOptions) *Manager {
+	ret := &Manager{
+		logger:          opts.Logger,
+		wsEventManager:  opts.WSEventManager,
+		db:              opts.Database,
+		sources:         opts.Sources,
+		now:             time.Now,
+		settings:        &models.DownloadManagerSettings{BaseModel: models.BaseModel{ID: 1}},
+		jobs:            make([]*Job, 0),
+		priorities:      make(map[string]Priority),
+		pausedByUser:    make(map[string]struct{}),
+		pausedByManager: make(map[string]PauseReason),
+		samples:         make(map[string]byteSample),
+	}
+
+	if opts.Database != nil {
+		if settings, found := opts.Database.GetDownloadManagerSettings(); found {
+			ret.settings = settings
+		}
+	}
+
+	return ret
+}
+
+// Start refreshes the jobs periodically.
+func (m *Manager) Start() {
+	m.mu.Lock()
+	if m.cancel != nil {
+		m.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.mu.Unlock()
+
+	go func() {
+		defer util.HandlePanicInModuleThen("downloadmanager/Start", func() {})
+
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.Refresh()
+			}
+		}
+	}()
+}
+
+// Shutdown stops refreshing the jobs and removes the rate limits set by the manager.
+func (m *Manager) Shutdown() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.applyRateLimit(0)
+}
+
+// Refresh fetches the jobs from the sources, applies the rules and sends the snapshot to the client.
+func (m *Manager) Refresh() {
+	m.refreshMu.Lock()
+	if m.refresh() {
+		// Get the new state of the jobs that were paused or resumed
+		m.refresh()
+	}
+	m.refreshMu.Unlock()
+
+	m.mu.Lock()
+	snapshot := m.snapshot()
+	m.mu.Unlock()
+
+	m.wsEventManager.SendEvent(events.DownloadManagerUpdated, snapshot)
+}
+
+// GetSnapshot returns the jobs from the last refresh.
+func (m *Manager) GetSnapshot() *Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snapshot()
+}
+
+func (m *Manager) GetSettings() *models.DownloadManagerSettings {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	settings := *m.settings
+	return &settings
+}
+
+// SaveSettings saves the settings and applies them immediately.
+func (m *Manager) SaveSettings(settings *models.DownloadManagerSettings) (*models.DownloadManagerSettings, error) {
+	if err := ValidateScheduleWindows(settings.ScheduleWindows); err != nil {
+		return nil, err
+	}
+
+	settings.BaseModel = models.BaseModel{ID: 1}
+	if settings.ScheduleWindows == nil {
+		settings.ScheduleWindows = make(models.DownloadScheduleWindows, 0)
+	}
+
+	m.mu.Lock()
+	if m.db != nil {
+		saved, err := m.db.UpsertDownloadManagerSettings(settings)
+		if err != nil {
+			m.mu.Unlock()
+			return nil, err
+		}
+		settings = saved
+	}
+	m.settings = settings
+	m.mu.Unlock()
+
+	m.Refresh()
+
+	return m.GetSettings(), nil
+}
+
+// SetPaused pauses or resumes all downloads.
+func (m *Manager) SetPaused(paused bool) error {
+	settings := m.GetSettings()
+	settings.Paused = paused
+	_, err := m.SaveSettings(settings)
+	return err
+}
+
+// PauseJob pauses a job until the user resumes it.
+func (m *Manager) PauseJob(id string) error {
+	m.mu.Lock()
+	job, source, err := m.findJob(id)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err = source.PauseJobs([]string{job.SourceId}); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.pausedByUser[id] = struct{}{}
+	delete(m.pausedByManager, id)
+	m.mu.Unlock()
+
+	m.Refresh()
+	return nil
+}
+
+// ResumeJob resumes a job paused by the user.
+// The job is paused again if the downloads are paused or if it's outside the schedule windows.
+func (m *Manager) ResumeJob(id string) error {
+	m.mu.Lock()
+	job, source, err := m.findJob(id)
+	if err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	delete(m.pausedByUser, id)
+	delete(m.pausedByManager, id)
+	m.mu.Unlock()
+
+	if err = source.ResumeJobs([]string{job.SourceId}); err != nil {
+		return err
+	}
+
+	m.Refresh()
+	return nil
+}
+
+// SetJobPriority sets the priority of a job.
+// High priority jobs are not paused outside the schedule windows.
+// Low priority jobs are paused while a job with a higher priority from the same source is downloading.
+func (m *Manager) SetJobPriority(id string, priority Priority) error {
+	if !priority.isValid() {
+		return errors.New("download manager: Invalid priority")
+	}
+
+	m.mu.Lock()
+	if _, _, err := m.findJob(id); err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	if priority == PriorityNormal {
+		delete(m.priorities, id)
+	} else {
+		m.priorities[id] = priority
+	}
+	m.mu.Unlock()
+
+	m.Refresh()
+	return nil
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (m *Manager) findJob(id string) (*Job, Source, error) {
+	sourceName, _, ok := parseJobID(id)
+	if !ok {
+		return nil, nil, ErrJobNotFound
+	}
+	source, found := lo.Find(m.sources, func(s Source) bool {
+		return s.Name() == sourceName
+	})
+	if !found {
+		return nil, nil, ErrJobNotFound
+	}
+	job, found := lo.Find(m.jobs, func(j *Job) bool {
+		return j.ID == id
+	})
+	if !found {
+		return nil, nil, ErrJobNotFound
+	}
+	return job, source, nil
+}
+
+// refresh fetches the jobs from the sources and pauses or resumes them according to the rules.
+// The sources are contacted without holding the lock, it's only held while merging the results.
+// It returns true if jobs were paused or resumed.
+func (m *Manager) refresh() (changed bool) {
+	now := m.now()
+
+	results := make([]sourceResult, len(m.sources))
+	for i, source := range m.sources {
+		results[i].jobs, results[i].err = source.GetJobs()
+	}
+
+	m.mu.Lock()
+	toPause, toResume := m.merge(results, now)
+	m.mu.Unlock()
+
+	for _, source := range m.sources {
+		if pause := toPause[source.Name()]; len(pause) > 0 {
+			err := source.PauseJobs(lo.Map(pause, func(j *Job, _ int) string { return j.SourceId }))
+			if err != nil {
+				logPauseError(m.logger, err).Str("source", string(source.Name())).Msg("download manager: Failed to pause jobs")
+				// Try again on the next refresh
+				m.mu.Lock()
+				for _, job := range pause {
+					delete(m.pausedByManager, job.ID)
+				}
+				m.mu.Unlock()
+			} else {
+				changed = true
+				m.logger.Debug().Str("source", string(source.Name())).Int("count", len(pause)).Msg("download manager: Paused jobs")
+			}
+		}
+		if resume := toResume[source.Name()]; len(resume) > 0 {
+			err := source.ResumeJobs(lo.Map(resume, func(j *Job, _ int) string { return j.SourceId }))
+			if err != nil {
+				logPauseError(m.logger, err).Str("source", string(source.Name())).Msg("download manager: Failed to resume jobs")
+			} else {
+				changed = true
+				m.logger.Debug().Str("source", string(source.Name())).Int("count", len(resume)).Msg("download manager: Resumed jobs")
+			}
+		}
+	}
+
+	return changed
+}
+
+// merge replaces the jobs with the ones fetched from the sources and applies the rules.
+// It returns the jobs to pause and resume by source.
+func (m *Manager) merge(results []sourceResult, now time.Time) (toPause map[JobSource][]*Job, toResume map[JobSource][]*Job) {
+	jobs := make([]*Job, 0)
+	refreshedSources := make(map[JobSource]struct{})
+	for i, source := range m.sources {
+		sourceJobs, err := results[i].jobs, results[i].err
+		if err != nil {
+			m.logger.Trace().Err(err).Str("source", string(source.Name())).Msg("download manager: Failed to get jobs")
+			// Keep the previous jobs of the source
+			jobs = append(jobs, lo.Filter(m.jobs, func(j *Job, _ int) bool {
+				return j.Source == source.Name()
+			})...)
+			continue
+		}
+		refreshedSources[source.Name()] = struct{}{}
+		for _, job := range sourceJobs {
+			job.Source = source.Name()
+			job.ID = jobID(job.Source, job.SourceId)
+			job.Priority = m.priorities[job.ID]
+			jobs = append(jobs, job)
+		}
+	}
+
+	m.computeStats(jobs, now)
+
+	// Forget the state of the jobs that no longer exist
+	exists := lo.SliceToMap(jobs, func(j *Job) (string, struct{}) {
+		return j.ID, struct{}{}
+	})
+	forget := func(id string) bool {
+		source, _, _ := parseJobID(id)
+		_, refreshed := refreshedSources[source]
+		_, found := exists[id]
+		return refreshed && !found
+	}
+	for id := range m.priorities {
+		if forget(id) {
+			delete(m.priorities, id)
+		}
+	}
+	for id := range m.pausedByUser {
+		if forget(id) {
+			delete(m.pausedByUser, id)
+		}
+	}
+	for id := range m.pausedByManager {
+		if forget(id) {
+			delete(m.pausedByManager, id)
+		}
+	}
+	for id := range m.samples {
+		if _, found := exists[id]; !found {
+			delete(m.samples, id)
+		}
+	}
+
+	// Schedule
+	window, inWindow := activeScheduleWindow(m.settings.ScheduleWindows, now)
+	m.inScheduleWindow = inWindow
+
+	var rateLimit int64
+	if m.settings.ScheduleEnabled && inWindow && window.RateLimit > 0 {
+		rateLimit = int64(window.RateLimit) * 1024
+	}
+	m.applyRateLimit(rateLimit)
+
+	// Rules
+	toPause = make(map[JobSource][]*Job)
+	toResume = make(map[JobSource][]*Job)
+	for _, job := range jobs {
+		if !job.isActive() {
+			delete(m.pausedByManager, job.ID)
+			continue
+		}
+
+		if _, paused := m.pausedByUser[job.ID]; paused {
+			if job.State == JobStatePaused {
+				job.PausedBy = PauseReasonUser
+			}
+			continue
+		}
+
+		reason := m.getPauseReason(job, jobs, inWindow)
+		_, pausedByManager := m.pausedByManager[job.ID]
+
+		switch {
+		case reason != "" && job.isRunning():
+			toPause[job.Source] = append(toPause[job.Source], job)
+			m.pausedByManager[job.ID] = reason
+		case reason != "" && pausedByManager:
+			m.pausedByManager[job.ID] = reason
+		case reason == "" && pausedByManager:
+			delete(m.pausedByManager, job.ID)
+			if job.State == JobStatePaused {
+				toResume[job.Source] = append(toResume[job.Source], job)
+			}
+		}
+	}
+
+	for _, job := range jobs {
+		if reason, found := m.pausedByManager[job.ID]; found && job.State == JobStatePaused {
+			job.PausedBy = reason
+		}
+	}
+
+	m.publishCompletedJobs(jobs)
+	m.jobs = jobs
+
+	return toPause, toResume
+}
+
+// logPauseError returns the log event of an error returned when pausing or resuming jobs.
+// Jobs that the source can't pause separately are retried on every refresh, so they are only traced.
+func logPauseError(logger *zerolog.Logger, err error) *zerolog.Event {
+	if errors.Is(err, ErrUnsupported) {
+		return logger.Trace().Err(err)
+	}
+	return logger.Warn().Err(err)
+}
+
+// publishCompletedJobs publishes the jobs that completed since the last refresh to the event stream.
+// Debrid downloads and manga chapters are removed from their source once they're done, they are published by their source.
+func (m *Manager) publishCompletedJobs(jobs []*Job) {
+	previous := lo.SliceToMap(m.jobs, func(j *Job) (string, JobState) {
+		return j.ID, j.State
+	})
+	for _, job := range jobs {
+		if state, found := previous[job.ID]; !found || state == JobStateCompleted || job.State != JobStateCompleted {
+			continue
+		}
+		eventstream.GlobalBroker.Publish(eventstream.TypeDownloadCompleted, &eventstream.DownloadCompletedPayload{
+			Source:  string(job.Source),
+			Name:    job.Name,
+			MediaId: job.MediaId,
+			Episode: job.Episode,
+		})
+	}
+}
+
+// getPauseReason returns the reason the job should be paused, or an empty string if it can run.
+func (m *Manager) getPauseReason(job *Job, jobs []*Job, inWindow bool) PauseReason {
+	if m.settings.Paused {
+		return PauseReasonGlobal
+	}
+	if m.settings.ScheduleEnabled && !inWindow && job.Priority < PriorityHigh {
+		return PauseReasonSchedule
+	}
+	if job.Priority == PriorityLow {
+		_, yields := lo.Find(jobs, func(other *Job) bool {
+			return other.Source == job.Source && other.Priority > PriorityLow && other.isRunning()
+		})
+		if yields {
+			return PauseReasonPriority
+		}
+	}
+	return ""
+}
+
+// computeStats sets the progress, speed and ETA of the jobs that the sources didn't provide.
+func (m *Manager) computeStats(jobs []*Job, now time.Time) {
+	for _, job := range jobs {
+		if job.Progress == 0 && job.TotalBytes > 0 {
+			job.Progress = float64(job.DownloadedBytes) / float64(job.TotalBytes)
+		}
+
+		if job.State != JobStateDownloading {
+			delete(m.samples, job.ID)
+			job.Speed = 0
+			job.Eta = -1
+			if job.State == JobStateCompleted {
+				job.Eta = 0
+			}
+			continue
+		}
+
+		if job.Speed == 0 {
+			prev, hasPrev := m.samples[job.ID]
+			switch {
+			case !hasPrev:
+				m.samples[job.ID] = byteSample{bytes: job.DownloadedBytes, at: now}
+			case now.Sub(prev.at) < time.Second:
+				// Refreshed twice in a row, keep the previous measure
+				job.Speed = prev.speed
+			default:
+				if delta := job.DownloadedBytes - prev.bytes; delta > 0 {
+					job.Speed = int64(float64(delta) / now.Sub(prev.at).Seconds())
+				}
+				m.samples[job.ID] = byteSample{bytes: job.DownloadedBytes, at: now, speed: job.Speed}
+			}
+		}
+
+		if job.Eta <= 0 {
+			job.Eta = -1
+			if job.Speed > 0 && job.TotalBytes > job.DownloadedBytes {
+				job.Eta = (job.TotalBytes - job.DownloadedBytes) / job.Speed
+			}
+		}
+	}
+}
+
+// applyRateLimit sets the download limit of the sources that support it if it changed.
+func (m *Manager) applyRateLimit(bytesPerSecond int64) {
+	if bytesPerSecond == m.rateLimit {
+		return
+	}
+	for _, source := range m.sources {
+		limiter, ok := source.(RateLimiter)
+		if !ok {
+			continue
+		}
+		if err := limiter.SetDownloadRateLimit(bytesPerSecond); err != nil {
+			m.logger.Warn().Err(err).Str("source", string(source.Name())).Msg("download manager: Failed to set download limit")
+		}
+	}
+	m.rateLimit = bytesPerSecond
+	m.logger.Debug().Int64("bytesPerSecond", bytesPerSecond).Msg("download manager: Download limit changed")
+}
+
+func (m *Manager) snapshot() *Snapshot {
+	ret := &Snapshot{
+		Jobs:             make([]*Job, 0, len(m.jobs)),
+		Paused:           m.settings.Paused,
+		ScheduleEnabled:  m.settings.ScheduleEnabled,
+		InScheduleWindow: m.inScheduleWindow,
+		RateLimit:        m.rateLimit,
+	}
+	for _, job := range m.jobs {
+		cp := *job
+		ret.Jobs = append(ret.Jobs, &cp)
+		ret.TotalSpeed += job.Speed
+	}
+	return ret
+}
@@ -0,0 +1,294 @@
+package downloadmanager
+
+import (
+	"seanime/internal/database/models"
+	"seanime/internal/events"
+	"seanime/internal/eventstream"
+	"seanime/internal/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	name      JobSource
+	jobs      map[string]*Job
+	order     []string
+	rateLimit int64
+}
+
+func newFakeSource(name JobSource, jobs ...*Job) *fakeSource {
+	ret := &fakeSource{name: name, jobs: make(map[string]*Job)}
+	for _, job := range jobs {
+		ret.jobs[job.SourceId] = job
+		ret.order = append(ret.order, job.SourceId)
+	}
+	return ret
+}
+
+func (s *fakeSource) Name() JobSource {
+	return s.name
+}
+
+func (s *fakeSource) GetJobs() ([]*Job, error) {
+	ret := make([]*Job, 0, len(s.order))
+	for _, id := range s.order {
+		cp := *s.jobs[id]
+		ret = append(ret, &cp)
+	}
+	return ret, nil
+}
+
+func (s *fakeSource) PauseJobs(sourceIds []string) error {
+	for _, id := range sourceIds {
+		s.jobs[id].State = JobStatePaused
+	}
+	return nil
+}
+
+func (s *fakeSource) ResumeJobs(sourceIds []string) error {
+	for _, id := range sourceIds {
+		s.jobs[id].State = JobStateDownloading
+	}
+	return nil
+}
+
+func (s *fakeSource) SetDownloadRateLimit(bytesPerSecond int64) error {
+	s.rateLimit = bytesPerSecond
+	return nil
+}
+
+func newTestManager(now *time.Time, sources ...Source) *Manager {
+	logger := util.NewLogger()
+	m := NewManager(&NewManagerOptions{
+		Logger:         logger,
+		WSEventManager: events.NewMockWSEventManager(logger),
+		Sources:        sources,
+	})
+	m.now = func() time.Time {
+		return *now
+	}
+	return m
+}
+
+func getJob(t *testing.T, m *Manager, id string) *Job {
+	for _, job := range m.GetSnapshot().Jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	t.Fatalf("job %s not found", id)
+	return nil
+}
+
+func TestManager_GlobalPause(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	torrents := newFakeSource(JobSourceTorrentClient,
+		&Job{SourceId: "a", State: JobStateDownloading},
+		&Job{SourceId: "b", State: JobStatePaused},
+	)
+	m := newTestManager(&now, torrents)
+	m.Refresh()
+
+	require.NoError(t, m.SetPaused(true))
+	assert.Equal(t, JobStatePaused, torrents.jobs["a"].State)
+	assert.Equal(t, PauseReasonGlobal, getJob(t, m, "torrent-client:a").PausedBy)
+	// Jobs paused outside the manager are not tracked
+	assert.Equal(t, PauseReason(""), getJob(t, m, "torrent-client:b").PausedBy)
+
+	require.NoError(t, m.SetPaused(false))
+	assert.Equal(t, JobStateDownloading, torrents.jobs["a"].State)
+	// Jobs paused outside the manager are not resumed
+	assert.Equal(t, JobStatePaused, torrents.jobs["b"].State)
+}
+
+func TestManager_UserPause(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	torrents := newFakeSource(JobSourceTorrentClient,
+		&Job{SourceId: "a", State: JobStateDownloading},
+	)
+	m := newTestManager(&now, torrents)
+	m.Refresh()
+
+	require.NoError(t, m.PauseJob("torrent-client:a"))
+	assert.Equal(t, PauseReasonUser, getJob(t, m, "torrent-client:a").PausedBy)
+
+	// Jobs paused by the user stay paused after a global pause
+	require.NoError(t, m.SetPaused(true))
+	require.NoError(t, m.SetPaused(false))
+	assert.Equal(t, JobStatePaused, torrents.jobs["a"].State)
+	assert.Equal(t, PauseReasonUser, getJob(t, m, "torrent-client:a").PausedBy)
+
+	require.NoError(t, m.ResumeJob("torrent-client:a"))
+	assert.Equal(t, JobStateDownloading, torrents.jobs["a"].State)
+
+	assert.ErrorIs(t, m.PauseJob("torrent-client:unknown"), ErrJobNotFound)
+}
+
+func TestManager_Schedule(t *testing.T) {
+	// Monday
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	torrents := newFakeSource(JobSourceTorrentClient,
+		&Job{SourceId: "normal", State: JobStateDownloading},
+		&Job{SourceId: "high", State: JobStateDownloading},
+	)
+	debrid := newFakeSource(JobSourceDebrid,
+		&Job{SourceId: "x", State: JobStateQueued},
+	)
+	m := newTestManager(&now, torrents, debrid)
+	m.Refresh()
+	require.NoError(t, m.SetJobPriority("torrent-client:high", PriorityHigh))
+
+	_, err := m.SaveSettings(&models.DownloadManagerSettings{
+		ScheduleEnabled: true,
+		ScheduleWindows: models.DownloadScheduleWindows{
+			{Start: "01:00", End: "07:00", RateLimit: 500},
+		},
+	})
+	require.NoError(t, err)
+
+	// Outside the window, only high priority jobs run
+	assert.Equal(t, JobStatePaused, torrents.jobs["normal"].State)
+	assert.Equal(t, JobStateDownloading, torrents.jobs["high"].State)
+	assert.Equal(t, JobStatePaused, debrid.jobs["x"].State)
+	assert.Equal(t, PauseReasonSchedule, getJob(t, m, "torrent-client:normal").PausedBy)
+	assert.False(t, m.GetSnapshot().InScheduleWindow)
+	assert.Equal(t, int64(0), torrents.rateLimit)
+
+	// Inside the window, everything runs with the window's rate limit
+	now = time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	m.Refresh()
+	assert.Equal(t, JobStateDownloading, torrents.jobs["normal"].State)
+	assert.Equal(t, JobStateDownloading, debrid.jobs["x"].State)
+	assert.True(t, m.GetSnapshot().InScheduleWindow)
+	assert.Equal(t, int64(500*1024), torrents.rateLimit)
+	assert.Equal(t, int64(500*1024), debrid.rateLimit)
+
+	// The rate limit is removed when the window ends
+	now = time.Date(2024, 1, 2, 8, 0, 0, 0, time.UTC)
+	m.Refresh()
+	assert.Equal(t, JobStatePaused, torrents.jobs["normal"].State)
+	assert.Equal(t, int64(0), torrents.rateLimit)
+}
+
+func TestManager_PriorityYield(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	torrents := newFakeSource(JobSourceTorrentClient,
+		&Job{SourceId: "low", State: JobStateDownloading},
+		&Job{SourceId: "normal", State: JobStateDownloading},
+	)
+	manga := newFakeSource(JobSourceManga,
+		&Job{SourceId: "chapter", State: JobStateDownloading},
+	)
+	m := newTestManager(&now, torrents, manga)
+	m.Refresh()
+
+	require.NoError(t, m.SetJobPriority("torrent-client:low", PriorityLow))
+	assert.Equal(t, JobStatePaused, torrents.jobs["low"].State)
+	assert.Equal(t, PauseReasonPriority, getJob(t, m, "torrent-client:low").PausedBy)
+	// Jobs only yield to jobs of the same source
+	require.NoError(t, m.SetJobPriority("manga:chapter", PriorityLow))
+	assert.Equal(t, JobStateDownloading, manga.jobs["chapter"].State)
+
+	// The low priority job resumes once the other job is done
+	torrents.jobs["normal"].State = JobStateCompleted
+	m.Refresh()
+	assert.Equal(t, JobStateDownloading, torrents.jobs["low"].State)
+	assert.Equal(t, PauseReason(""), getJob(t, m, "torrent-client:low").PausedBy)
+}
+
+func TestManager_ComputeStats(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	debrid := newFakeSource(JobSourceDebrid,
+		&Job{SourceId: "x", State: JobStateDownloading, TotalBytes: 10_000, DownloadedBytes: 1_000},
+	)
+	m := newTestManager(&now, debrid)
+	m.Refresh()
+
+	job := getJob(t, m, "debrid:x")
+	assert.Equal(t, 0.1, job.Progress)
+	assert.Equal(t, int64(-1), job.Eta)
+
+	now = now.Add(5 * time.Second)
+	debrid.jobs["x"].DownloadedBytes = 6_000
+	m.Refresh()
+
+	job = getJob(t, m, "debrid:x")
+	assert.Equal(t, int64(1_000), job.Speed)
+	assert.Equal(t, int64(4), job.Eta)
+	assert.Equal(t, int64(1_000), m.GetSnapshot().TotalSpeed)
+}
+
+func TestManager_PublishCompletedJobs(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	torrents := newFakeSource(JobSourceTorrentClient,
+		&Job{SourceId: "done", Name: "Already done", State: JobStateCompleted},
+		&Job{SourceId: "a", Name: "Frieren - 01", MediaId: 1, State: JobStateDownloading},
+	)
+	m := newTestManager(&now, torrents)
+
+	sub, _ := eventstream.GlobalBroker.Subscribe([]eventstream.Type{eventstream.TypeDownloadCompleted}, "")
+	defer eventstream.GlobalBroker.Unsubscribe(sub)
+
+	// Jobs that were already completed are not published
+	m.Refresh()
+	assert.Len(t, sub.C, 0)
+
+	torrents.jobs["a"].State = JobStateCompleted
+	m.Refresh()
+	m.Refresh()
+	require.Len(t, sub.C, 1)
+	payload := (<-sub.C).Data.(*eventstream.DownloadCompletedPayload)
+	assert.Equal(t, "torrent-client", payload.Source)
+	assert.Equal(t, "Frieren - 01", payload.Name)
+	assert.Equal(t, 1, payload.MediaId)
+}
+
+// blockingSource blocks GetJobs until it's released.
+type blockingSource struct {
+	*fakeSource
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSource) GetJobs() ([]*Job, error) {
+	close(s.started)
+	<-s.release
+	return s.fakeSource.GetJobs()
+}
+
+func TestManager_RefreshDoesNotBlockWhileFetching(t *testing.T) {
+	now := time.Now()
+	source := &blockingSource{
+		fakeSource: newFakeSource(JobSourceTorrentClient, &Job{SourceId: "a", State: JobStateDownloading}),
+		started:    make(chan struct{}),
+		release:    make(chan struct{}),
+	}
+	m := newTestManager(&now, source)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Refresh()
+	}()
+	<-source.started
+
+	// The snapshot and the settings are available while the source is being contacted
+	snapshotCh := make(chan *Snapshot)
+	go func() {
+		_ = m.GetSettings()
+		snapshotCh <- m.GetSnapshot()
+	}()
+	select {
+	case snapshot := <-snapshotCh:
+		assert.Empty(t, snapshot.Jobs)
+	case <-time.After(time.Second):
+		t.Fatal("the manager is locked while fetching the jobs")
+	}
+
+	close(source.release)
+	<-done
+	require.NotNil(t, getJob(t, m, jobID(JobSourceTorrentClient, "a")))
+}
@@ -0,0 +1,96 @@
+package downloadmanager
+
+import (
+	"errors"
+	"fmt"
+	"seanime/internal/database/models"
+	"time"
+)
+
+// activeScheduleWindow returns the schedule window containing the given time.
+func activeScheduleWindow(windows models.DownloadScheduleWindows, now time.Time) (*models.DownloadScheduleWindow, bool) {
+	for _, w := range windows {
+		if w != nil && isInScheduleWindow(w, now) {
+			return w, true
+		}
+	}
+	return nil, false
+}
+
+// isInScheduleWindow returns true if the time is inside the window.
+// Days refer to the day the window starts, so a window from 23:00 to 07:00 on Friday includes Saturday 06:00.
+func isInScheduleWindow(w *models.DownloadScheduleWindow, now time.Time) bool {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return false
+	}
+
+	minutes := now.Hour()*60 + now.Minute()
+	weekday := int(now.Weekday())
+
+	switch {
+	case start == end:
+		// The window lasts the whole day
+		return isScheduledOn(w, weekday)
+	case start < end:
+		return minutes >= start && minutes < end && isScheduledOn(w, weekday)
+	default:
+		// The window wraps past midnight
+		if minutes >= start {
+			return isScheduledOn(w, weekday)
+		}
+		if minutes < end {
+			return isScheduledOn(w, (weekday+6)%7)
+		}
+		return false
+	}
+}
+
+func isScheduledOn(w *models.DownloadScheduleWindow, weekday int) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock returns the number of minutes since midnight of a "HH:MM" time.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ValidateScheduleWindows returns an error if a window is invalid.
+func ValidateScheduleWindows(windows models.DownloadScheduleWindows) error {
+	for _, w := range windows {
+		if w == nil {
+			return errors.New("empty schedule window")
+		}
+		if _, err := parseClock(w.Start); err != nil {
+			return err
+		}
+		if _, err := parseClock(w.End); err != nil {
+			return err
+		}
+		for _, d := range w.Days {
+			if d < 0 || d > 6 {
+				return fmt.Errorf("invalid day %d, expected 0 (Sunday) to 6 (Saturday)", d)
+			}
+		}
+		if w.RateLimit < 0 {
+			return errors.New("rate limit cannot be negative")
+		}
+	}
+	return nil
+}
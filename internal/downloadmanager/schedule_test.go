@@ -0,0 +1,91 @@
+package downloadmanager
+
+import (
+	"seanime/internal/database/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsInScheduleWindow(t *testing.T) {
+	// 2024-01-05 is a Friday
+	at := func(day int, hour int, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		window   *models.DownloadScheduleWindow
+		time     time.Time
+		expected bool
+	}{
+		{
+			name:     "inside",
+			window:   &models.DownloadScheduleWindow{Start: "01:00", End: "07:00"},
+			time:     at(5, 3, 0),
+			expected: true,
+		},
+		{
+			name:     "end is exclusive",
+			window:   &models.DownloadScheduleWindow{Start: "01:00", End: "07:00"},
+			time:     at(5, 7, 0),
+			expected: false,
+		},
+		{
+			name:     "wraps past midnight, before midnight",
+			window:   &models.DownloadScheduleWindow{Start: "23:00", End: "07:00"},
+			time:     at(5, 23, 30),
+			expected: true,
+		},
+		{
+			name:     "wraps past midnight, after midnight",
+			window:   &models.DownloadScheduleWindow{Start: "23:00", End: "07:00"},
+			time:     at(6, 6, 59),
+			expected: true,
+		},
+		{
+			name:     "wraps past midnight, outside",
+			window:   &models.DownloadScheduleWindow{Start: "23:00", End: "07:00"},
+			time:     at(5, 12, 0),
+			expected: false,
+		},
+		{
+			name:     "day of the start of the window",
+			window:   &models.DownloadScheduleWindow{Start: "23:00", End: "07:00", Days: []int{5}},
+			time:     at(6, 2, 0), // Saturday
+			expected: true,
+		},
+		{
+			name:     "other day",
+			window:   &models.DownloadScheduleWindow{Start: "01:00", End: "07:00", Days: []int{0, 6}},
+			time:     at(5, 3, 0),
+			expected: false,
+		},
+		{
+			name:     "whole day",
+			window:   &models.DownloadScheduleWindow{Start: "00:00", End: "00:00", Days: []int{5}},
+			time:     at(5, 18, 0),
+			expected: true,
+		},
+		{
+			name:     "invalid time",
+			window:   &models.DownloadScheduleWindow{Start: "1am", End: "07:00"},
+			time:     at(5, 3, 0),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isInScheduleWindow(tt.window, tt.time))
+		})
+	}
+}
+
+func TestValidateScheduleWindows(t *testing.T) {
+	assert.NoError(t, ValidateScheduleWindows(models.DownloadScheduleWindows{{Start: "01:00", End: "07:00", Days: []int{0, 6}}}))
+	assert.Error(t, ValidateScheduleWindows(models.DownloadScheduleWindows{{Start: "25:00", End: "07:00"}}))
+	assert.Error(t, ValidateScheduleWindows(models.DownloadScheduleWindows{{Start: "01:00", End: "07:00", Days: []int{7}}}))
+	assert.Error(t, ValidateScheduleWindows(models.DownloadScheduleWindows{{Start: "01:00", End: "07:00", RateLimit: -1}}))
+}
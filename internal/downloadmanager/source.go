@@ -0,0 +1,21 @@
+package downloadmanager
+
+type (
+	// Source is a module that downloads files, e.g. the torrent client or the manga chapter queue.
+	Source interface {
+		Name() JobSource
+		// GetJobs returns the downloads of the source.
+		// The ID and the priority of the jobs are set by the manager.
+		GetJobs() ([]*Job, error)
+		// PauseJobs pauses the downloads with the given source IDs.
+		PauseJobs(sourceIds []string) error
+		// ResumeJobs resumes the downloads with the given source IDs.
+		ResumeJobs(sourceIds []string) error
+	}
+
+	// RateLimiter is implemented by sources that can limit their download speed.
+	RateLimiter interface {
+		// SetDownloadRateLimit sets the download limit in bytes per second, 0 removes the limit.
+		SetDownloadRateLimit(bytesPerSecond int64) error
+	}
+)
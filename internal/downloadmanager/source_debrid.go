@@ -0,0 +1,65 @@
+package downloadmanager
+
+import (
+	debrid_client "seanime/internal/debrid/client"
+)
+
+// DebridSource handles the torrents downloaded from the debrid service.
+// Torrents that are still being cached by the service are reported as queued.
+type DebridSource struct {
+	repository *debrid_client.Repository
+}
+
+func NewDebridSource(repository *debrid_client.Repository) *DebridSource {
+	return &DebridSource{
+		repository: repository,
+	}
+}
+
+func (s *DebridSource) Name() JobSource {
+	return JobSourceDebrid
+}
+
+func (s *DebridSource) GetJobs() ([]*Job, error) {
+	downloads := s.repository.GetDownloadJobs()
+
+	ret := make([]*Job, 0, len(downloads))
+	for _, dl := range downloads {
+		job := &Job{
+			SourceId:        dl.TorrentItemID,
+			Name:            dl.Name,
+			MediaId:         dl.MediaId,
+			TotalBytes:      dl.TotalBytes,
+			DownloadedBytes: dl.DownloadedBytes,
+		}
+		switch {
+		case dl.Paused:
+			job.State = JobStatePaused
+		case dl.Queued:
+			job.State = JobStateQueued
+		default:
+			job.State = JobStateDownloading
+		}
+		ret = append(ret, job)
+	}
+
+	return ret, nil
+}
+
+func (s *DebridSource) PauseJobs(sourceIds []string) error {
+	for _, id := range sourceIds {
+		s.repository.PauseDownload(id)
+	}
+	return nil
+}
+
+func (s *DebridSource) ResumeJobs(sourceIds []string) error {
+	for _, id := range sourceIds {
+		s.repository.ResumeDownload(id)
+	}
+	return nil
+}
+
+func (s *DebridSource) SetDownloadRateLimit(bytesPerSecond int64) error {
+	return s.repository.SetDownloadRateLimit(bytesPerSecond)
+}
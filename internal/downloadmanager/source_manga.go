@@ -0,0 +1,105 @@
+package downloadmanager
+
+import (
+	"fmt"
+	"seanime/internal/database/db"
+	"seanime/internal/manga"
+	chapter_downloader "seanime/internal/manga/downloader"
+	"slices"
+)
+
+// MangaSource handles the chapter download queue.
+// Chapters are downloaded one at a time, the queue can only be paused or resumed as a whole.
+type MangaSource struct {
+	downloader *manga.Downloader
+	database   *db.Database
+}
+
+func NewMangaSource(downloader *manga.Downloader, database *db.Database) *MangaSource {
+	return &MangaSource{
+		downloader: downloader,
+		database:   database,
+	}
+}
+
+func (s *MangaSource) Name() JobSource {
+	return JobSourceManga
+}
+
+func (s *MangaSource) GetJobs() ([]*Job, error) {
+	items, err := s.database.GetChapterDownloadQueue()
+	if err != nil {
+		return nil, err
+	}
+
+	running := s.downloader.IsChapterDownloadQueueRunning()
+	currentId, downloadedPages, totalPages, hasCurrent := s.downloader.GetCurrentChapterDownloadProgress()
+
+	ret := make([]*Job, 0, len(items))
+	for _, item := range items {
+		job := &Job{
+			SourceId: mangaJobSourceId(item.Provider, item.MediaID, item.ChapterID),
+			Name:     fmt.Sprintf("Chapter %s", item.ChapterNumber),
+			MediaId:  item.MediaID,
+			Episode:  item.ChapterNumber,
+		}
+		switch chapter_downloader.QueueStatus(item.Status) {
+		case chapter_downloader.QueueStatusDownloading:
+			job.State = JobStateDownloading
+			if hasCurrent && currentId.Provider == item.Provider && currentId.MediaId == item.MediaID && currentId.ChapterId == item.ChapterID && totalPages > 0 {
+				job.Progress = float64(downloadedPages) / float64(totalPages)
+			}
+		case chapter_downloader.QueueStatusErrored:
+			job.State = JobStateFailed
+		default:
+			job.State = JobStateQueued
+			if !running {
+				job.State = JobStatePaused
+			}
+		}
+		ret = append(ret, job)
+	}
+
+	return ret, nil
+}
+
+// PauseJobs stops the queue.
+// It returns ErrUnsupported if other chapters of the queue would keep downloading.
+func (s *MangaSource) PauseJobs(sourceIds []string) error {
+	if err := s.checkWholeQueue(sourceIds, (*Job).isRunning); err != nil {
+		return err
+	}
+
+	s.downloader.StopChapterDownloadQueue()
+	return nil
+}
+
+// ResumeJobs runs the queue.
+// It returns ErrUnsupported if other paused chapters of the queue would be resumed.
+func (s *MangaSource) ResumeJobs(sourceIds []string) error {
+	if err := s.checkWholeQueue(sourceIds, func(j *Job) bool { return j.State == JobStatePaused }); err != nil {
+		return err
+	}
+
+	s.downloader.RunChapterDownloadQueue()
+	return nil
+}
+
+// checkWholeQueue returns ErrUnsupported if a job matching the filter is not part of the source IDs.
+func (s *MangaSource) checkWholeQueue(sourceIds []string, filter func(*Job) bool) error {
+	jobs, err := s.GetJobs()
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if filter(job) && !slices.Contains(sourceIds, job.SourceId) {
+			return ErrUnsupported
+		}
+	}
+	return nil
+}
+
+func mangaJobSourceId(provider string, mediaId int, chapterId string) string {
+	return fmt.Sprintf("%s/%d/%s", provider, mediaId, chapterId)
+}
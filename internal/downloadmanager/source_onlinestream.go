@@ -0,0 +1,73 @@
+package downloadmanager
+
+import (
+	"seanime/internal/onlinestream"
+	"strconv"
+)
+
+// OnlinestreamSource handles the online stream episode download queue.
+// A paused episode starts over when it's resumed.
+type OnlinestreamSource struct {
+	downloader *onlinestream.Downloader
+}
+
+func NewOnlinestreamSource(downloader *onlinestream.Downloader) *OnlinestreamSource {
+	return &OnlinestreamSource{
+		downloader: downloader,
+	}
+}
+
+func (s *OnlinestreamSource) Name() JobSource {
+	return JobSourceOnlinestream
+}
+
+func (s *OnlinestreamSource) GetJobs() ([]*Job, error) {
+	items := s.downloader.GetQueue()
+
+	ret := make([]*Job, 0, len(items))
+	for _, item := range items {
+		job := &Job{
+			SourceId:        item.ID,
+			Name:            "Episode " + strconv.Itoa(item.EpisodeNumber),
+			MediaId:         item.MediaId,
+			Episode:         strconv.Itoa(item.EpisodeNumber),
+			DownloadedBytes: item.DownloadedBytes,
+			Progress:        item.Percentage / 100,
+			Error:           item.Error,
+		}
+		switch item.Status {
+		case onlinestream.DownloadStatusQueued:
+			job.State = JobStateQueued
+		case onlinestream.DownloadStatusPaused:
+			job.State = JobStatePaused
+		case onlinestream.DownloadStatusDownloading, onlinestream.DownloadStatusRemuxing:
+			job.State = JobStateDownloading
+		case onlinestream.DownloadStatusCompleted:
+			job.State = JobStateCompleted
+		default:
+			// Errored and cancelled downloads
+			job.State = JobStateFailed
+		}
+		ret = append(ret, job)
+	}
+
+	return ret, nil
+}
+
+func (s *OnlinestreamSource) PauseJobs(sourceIds []string) error {
+	for _, id := range sourceIds {
+		if err := s.downloader.PauseDownload(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *OnlinestreamSource) ResumeJobs(sourceIds []string) error {
+	for _, id := range sourceIds {
+		if err := s.downloader.ResumeDownload(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package downloadmanager
+
+import (
+	"errors"
+	"seanime/internal/torrent_clients/torrent_client"
+	"sync"
+	"time"
+)
+
+const (
+	// torrentClientRetryDelay is the time to wait before contacting the torrent client again after an error
+	torrentClientRetryDelay = time.Minute
+)
+
+// TorrentClientSource handles the torrents of qBittorrent or Transmission.
+// Seeding and stopped torrents are reported as completed.
+type TorrentClientSource struct {
+	// The repository is recreated when the settings change
+	getRepository func() *torrent_client.Repository
+
+	mu          sync.Mutex
+	repository  *torrent_client.Repository
+	rateLimit   int64
+	lastErrorAt time.Time
+	lastError   error
+}
+
+func NewTorrentClientSource(getRepository func() *torrent_client.Repository) *TorrentClientSource {
+	return &TorrentClientSource{
+		getRepository: getRepository,
+	}
+}
+
+func (s *TorrentClientSource) Name() JobSource {
+	return JobSourceTorrentClient
+}
+
+func (s *TorrentClientSource) GetJobs() ([]*Job, error) {
+	repo, err := s.getActiveRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Don't spam the torrent client if it's not running
+	if !s.lastErrorAt.IsZero() && time.Since(s.lastErrorAt) < torrentClientRetryDelay {
+		return nil, s.lastError
+	}
+
+	torrents, err := repo.GetActiveTorrents()
+	if err != nil {
+		s.lastErrorAt = time.Now()
+		s.lastError = err
+		return nil, err
+	}
+	s.lastErrorAt = time.Time{}
+	s.lastError = nil
+
+	ret := make([]*Job, 0, len(torrents))
+	for _, t := range torrents {
+		job := &Job{
+			SourceId:        t.Hash,
+			Name:            t.Name,
+			TotalBytes:      t.SizeBytes,
+			DownloadedBytes: int64(float64(t.SizeBytes) * t.Progress),
+			Progress:        t.Progress,
+			Speed:           t.DownSpeedBytes,
+			Eta:             t.EtaSeconds,
+		}
+		switch t.Status {
+		case torrent_client.TorrentStatusDownloading:
+			job.State = JobStateDownloading
+		case torrent_client.TorrentStatusPaused:
+			job.State = JobStatePaused
+		default:
+			job.State = JobStateCompleted
+		}
+		ret = append(ret, job)
+	}
+
+	return ret, nil
+}
+
+func (s *TorrentClientSource) PauseJobs(sourceIds []string) error {
+	repo, err := s.getActiveRepository()
+	if err != nil {
+		return err
+	}
+	return repo.PauseTorrents(sourceIds)
+}
+
+func (s *TorrentClientSource) ResumeJobs(sourceIds []string) error {
+	repo, err := s.getActiveRepository()
+	if err != nil {
+		return err
+	}
+	return repo.ResumeTorrents(sourceIds)
+}
+
+func (s *TorrentClientSource) SetDownloadRateLimit(bytesPerSecond int64) error {
+	s.mu.Lock()
+	s.rateLimit = bytesPerSecond
+	s.mu.Unlock()
+
+	repo, err := s.getActiveRepository()
+	if err != nil {
+		return nil
+	}
+	return repo.SetDownloadRateLimit(bytesPerSecond)
+}
+
+// getActiveRepository returns the current repository.
+// If the repository was recreated, the rate limit is applied to the new one.
+func (s *TorrentClientSource) getActiveRepository() (*torrent_client.Repository, error) {
+	repo := s.getRepository()
+	if repo == nil || repo.GetProvider() == torrent_client.NoneClient {
+		return nil, errors.New("no torrent client")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if repo != s.repository {
+		s.repository = repo
+		s.lastErrorAt = time.Time{}
+		if s.rateLimit > 0 {
+			_ = repo.SetDownloadRateLimit(s.rateLimit)
+		}
+	}
+
+	return repo, nil
+}
@@ -0,0 +1,73 @@
+package downloadmanager
+
+import (
+	"seanime/internal/torrentstream"
+)
+
+// TorrentstreamSource handles the torrents kept after a stream to be imported into the library.
+// The download speed isn't limited since it would also slow down the streams.
+type TorrentstreamSource struct {
+	repository *torrentstream.Repository
+}
+
+func NewTorrentstreamSource(repository *torrentstream.Repository) *TorrentstreamSource {
+	return &TorrentstreamSource{
+		repository: repository,
+	}
+}
+
+func (s *TorrentstreamSource) Name() JobSource {
+	return JobSourceTorrentstream
+}
+
+func (s *TorrentstreamSource) GetJobs() ([]*Job, error) {
+	importJobs := s.repository.GetImportJobs()
+
+	ret := make([]*Job, 0, len(importJobs))
+	for _, ij := range importJobs {
+		job := &Job{
+			SourceId:        ij.InfoHash,
+			Name:            ij.TorrentName,
+			MediaId:         ij.MediaId,
+			TotalBytes:      ij.TotalBytes,
+			DownloadedBytes: ij.DownloadedBytes,
+			Error:           ij.Error,
+		}
+		if job.Name == "" {
+			job.Name = ij.InfoHash
+		}
+		switch ij.Status {
+		case torrentstream.ImportStatusDownloading:
+			job.State = JobStateDownloading
+			if ij.Paused {
+				job.State = JobStatePaused
+			}
+		case torrentstream.ImportStatusFailed:
+			job.State = JobStateFailed
+		default:
+			// Importing and seeding torrents are already downloaded
+			job.State = JobStateCompleted
+		}
+		ret = append(ret, job)
+	}
+
+	return ret, nil
+}
+
+func (s *TorrentstreamSource) PauseJobs(sourceIds []string) error {
+	for _, id := range sourceIds {
+		if err := s.repository.PauseImportJob(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *TorrentstreamSource) ResumeJobs(sourceIds []string) error {
+	for _, id := range sourceIds {
+		if err := s.repository.ResumeImportJob(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
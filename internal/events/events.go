@@ -3,6 +3,7 @@ package events
 const (
 	EventScanProgress               = "scan-progress"                      // Progress of the scan
 	EventScanStatus                 = "scan-status"                        // Status text of the scan
+	EventScanProgressDetails        = "scan-progress-details"              // Structured progress of the scan (stage, counters, ETA)
 	RefreshedAnilistAnimeCollection = "refreshed-anilist-anime-collection" // The anilist collection has been refreshed
 	RefreshedAnilistMangaCollection = "refreshed-anilist-manga-collection" // The manga collection has been refreshed
 	LibraryWatcherFileAdded         = "library-watcher-file-added"         // A new file has been added to the library
@@ -46,5 +47,16 @@ const (
 
 	DebridDownloadProgress = "debrid-download-progress"
 
-	DebridStreamState = "debrid-stream-state"
+	DebridStreamState         = "debrid-stream-state"
+	DebridCloudLibraryUpdated = "debrid-cloud-library-updated" // Completed debrid torrents were analyzed
+
+	OnlinestreamDownloadQueueUpdated = "onlinestream-download-queue-updated"
+
+	SkipSegmentsUpdated = "skip-segments-updated"
+
+	MediastreamTrickplayUpdated = "mediastream-trickplay-updated"
+
+	DownloadManagerUpdated = "download-manager-updated"
+
+	ScheduledJobsUpdated = "scheduled-jobs-updated" // A scheduled job started, finished or was updated
 )
@@ -0,0 +1,188 @@
+package eventstream
+
+import (
+	"seanime/internal/database/models"
+	"seanime/internal/util"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/samber/mo"
+)
+
+const (
+	// Number of events kept to replay them to subscribers that reconnect
+	historySize = 100
+	// Number of events a subscriber can fall behind before events are dropped
+	subscriptionBufferSize = 64
+)
+
+type (
+	// Broker publishes the public events to the event stream subscribers and the webhooks.
+	Broker struct {
+		database    mo.Option[WebhookStore]
+		logger      mo.Option[*zerolog.Logger]
+		webhooks    []*models.EventWebhook
+		sender      *webhookSender
+		history     []*Event
+		subscribers map[*Subscription]struct{}
+		mu          sync.RWMutex
+	}
+
+	// WebhookStore loads the webhooks and saves their deliveries, it is implemented by db.Database.
+	// The database package isn't imported directly since it would create an import cycle with the platforms, which publish events.
+	WebhookStore interface {
+		GetEventWebhooks() ([]*models.EventWebhook, error)
+		UpdateEventWebhookDelivery(id uint, at time.Time, status int, deliveryErr string) error
+	}
+
+	// Subscription receives the published events on C.
+	Subscription struct {
+		C     chan *Event
+		types []Type
+	}
+)
+
+var GlobalBroker = NewBroker()
+
+func NewBroker() *Broker {
+	return &Broker{
+		database:    mo.None[WebhookStore](),
+		logger:      mo.None[*zerolog.Logger](),
+		webhooks:    make([]*models.EventWebhook, 0),
+		sender:      newWebhookSender(),
+		history:     make([]*Event, 0, historySize),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// SetDatabase loads the webhooks from the database.
+func (b *Broker) SetDatabase(database WebhookStore, logger *zerolog.Logger) {
+	b.mu.Lock()
+	b.database = mo.Some(database)
+	b.logger = mo.Some(logger)
+	b.mu.Unlock()
+
+	if err := b.Reload(); err != nil {
+		logger.Error().Err(err).Msg("eventstream: Failed to load webhooks")
+	}
+}
+
+// Reload reloads the webhooks from the database.
+// It should be called after the webhooks are modified.
+func (b *Broker) Reload() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	database, ok := b.database.Get()
+	if !ok {
+		return nil
+	}
+
+	webhooks, err := database.GetEventWebhooks()
+	if err != nil {
+		return err
+	}
+	b.webhooks = webhooks
+	return nil
+}
+
+// Publish sends an event to the subscribers and the enabled webhooks subscribed to its type.
+// Webhooks are called in the background.
+func (b *Broker) Publish(t Type, data interface{}) {
+	if b == nil {
+		return
+	}
+
+	event := &Event{
+		ID:        uuid.NewString(),
+		Version:   Version,
+		Type:      t,
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+
+	b.mu.Lock()
+	if len(b.history) == historySize {
+		b.history = append(b.history[:0], b.history[1:]...)
+	}
+	b.history = append(b.history, event)
+
+	for sub := range b.subscribers {
+		if !sub.wants(t) {
+			continue
+		}
+		select {
+		case sub.C <- event:
+		default:
+			b.log(func(l *zerolog.Logger) {
+				l.Warn().Str("type", string(t)).Msg("eventstream: Subscriber is too slow, event dropped")
+			})
+		}
+	}
+
+	webhooks := make([]*models.EventWebhook, 0, len(b.webhooks))
+	for _, webhook := range b.webhooks {
+		if webhook.Enabled && isSubscribed(webhook.Events, t) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, webhook := range webhooks {
+		go func(webhook *models.EventWebhook) {
+			defer util.HandlePanicInModuleThen("eventstream/Publish", func() {})
+			b.deliver(webhook, event)
+		}(webhook)
+	}
+}
+
+// Subscribe returns a subscription to the events of the given types, all events if types is empty.
+// If lastEventId is the ID of an event still in the history, the events published after it are returned so they can be replayed.
+func (b *Broker) Subscribe(types []Type, lastEventId string) (*Subscription, []*Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &Subscription{
+		C:     make(chan *Event, subscriptionBufferSize),
+		types: types,
+	}
+	b.subscribers[sub] = struct{}{}
+
+	missed := make([]*Event, 0)
+	if lastEventId != "" {
+		idx := slices.IndexFunc(b.history, func(e *Event) bool { return e.ID == lastEventId })
+		if idx != -1 {
+			for _, event := range b.history[idx+1:] {
+				if sub.wants(event.Type) {
+					missed = append(missed, event)
+				}
+			}
+		}
+	}
+
+	return sub, missed
+}
+
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscribers, sub)
+}
+
+func (b *Broker) log(f func(l *zerolog.Logger)) {
+	if logger, ok := b.logger.Get(); ok {
+		f(logger)
+	}
+}
+
+func (s *Subscription) wants(t Type) bool {
+	return isSubscribed(s.types, t)
+}
+
+// isSubscribed returns true if types is empty or contains t.
+func isSubscribed[T ~string](types []T, t Type) bool {
+	return len(types) == 0 || slices.Contains(types, T(t))
+}
@@ -0,0 +1,162 @@
+package eventstream
+
+import (
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seanime/internal/database/models"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receiveEvent(t *testing.T, sub *Subscription) *Event {
+	select {
+	case event := <-sub.C:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return nil
+	}
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	b := NewBroker()
+
+	all, _ := b.Subscribe(nil, "")
+	playback, _ := b.Subscribe([]Type{TypePlaybackStarted}, "")
+
+	b.Publish(TypeLibraryScanCompleted, &LibraryScanCompletedPayload{Files: 10})
+	b.Publish(TypePlaybackStarted, &PlaybackPayload{MediaId: 1})
+
+	first := receiveEvent(t, all)
+	assert.Equal(t, TypeLibraryScanCompleted, first.Type)
+	assert.Equal(t, Version, first.Version)
+	assert.NotEmpty(t, first.ID)
+	assert.Equal(t, TypePlaybackStarted, receiveEvent(t, all).Type)
+
+	// Filtered by type
+	assert.Equal(t, TypePlaybackStarted, receiveEvent(t, playback).Type)
+	assert.Len(t, playback.C, 0)
+
+	b.Unsubscribe(all)
+	b.Publish(TypePlaybackStopped, &PlaybackStoppedPayload{})
+	assert.Len(t, all.C, 0)
+
+	// Replay the events published after the last received event
+	_, missed := b.Subscribe(nil, first.ID)
+	require.Len(t, missed, 2)
+	assert.Equal(t, TypePlaybackStarted, missed[0].Type)
+	assert.Equal(t, TypePlaybackStopped, missed[1].Type)
+
+	// Unknown IDs don't replay anything
+	_, missed = b.Subscribe(nil, "unknown")
+	assert.Len(t, missed, 0)
+}
+
+func TestBroker_HistorySize(t *testing.T) {
+	b := NewBroker()
+	for i := 0; i < historySize+10; i++ {
+		b.Publish(TypeProgressUpdated, &PlaybackPayload{EpisodeNumber: i})
+	}
+	assert.Len(t, b.history, historySize)
+	assert.Equal(t, 10, b.history[0].Data.(*PlaybackPayload).EpisodeNumber)
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"type":"ping"}`)
+	sig := Sign("secret", 1700000000, body)
+	assert.Regexp(t, "^sha256=[0-9a-f]{64}$", sig)
+	assert.Equal(t, sig, Sign("secret", 1700000000, body))
+	assert.NotEqual(t, sig, Sign("other", 1700000000, body))
+	assert.NotEqual(t, sig, Sign("secret", 1700000001, body))
+}
+
+func TestValidateWebhook(t *testing.T) {
+	assert.NoError(t, ValidateWebhook(&models.EventWebhook{URL: "https://example.com/hook"}))
+	assert.NoError(t, ValidateWebhook(&models.EventWebhook{URL: "http://localhost:8123/api/webhook/x", Events: models.EventWebhookEvents{"playback.started"}}))
+	assert.Error(t, ValidateWebhook(&models.EventWebhook{URL: "ftp://example.com"}))
+	assert.Error(t, ValidateWebhook(&models.EventWebhook{URL: "example.com"}))
+	assert.Error(t, ValidateWebhook(&models.EventWebhook{URL: "https://example.com", Events: models.EventWebhookEvents{"unknown"}}))
+	assert.Error(t, ValidateWebhook(nil))
+}
+
+func TestBroker_Webhooks(t *testing.T) {
+	type received struct {
+		headers http.Header
+		body    []byte
+	}
+
+	var attempts atomic.Int32
+	ch := make(chan *received, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		ch <- &received{headers: r.Header, body: body}
+	}))
+	defer server.Close()
+
+	b := NewBroker()
+	b.sender.retryDelays = []time.Duration{10 * time.Millisecond}
+	b.webhooks = []*models.EventWebhook{
+		{URL: server.URL, Secret: "secret", Enabled: true, Events: models.EventWebhookEvents{string(TypePlaybackStarted)}},
+		{URL: server.URL, Enabled: false},
+	}
+
+	b.Publish(TypeLibraryScanCompleted, &LibraryScanCompletedPayload{})
+	b.Publish(TypePlaybackStarted, &PlaybackPayload{MediaId: 1, MediaTitle: "Frieren", EpisodeNumber: 3})
+
+	var req *received
+	select {
+	case req = <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for webhook")
+	}
+
+	assert.EqualValues(t, 2, attempts.Load())
+	assert.Equal(t, string(TypePlaybackStarted), req.headers.Get(EventHeader))
+
+	timestamp, err := strconv.ParseInt(req.headers.Get(TimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.True(t, hmac.Equal([]byte(Sign("secret", timestamp, req.body)), []byte(req.headers.Get(SignatureHeader))))
+
+	var event Event
+	require.NoError(t, json.Unmarshal(req.body, &event))
+	assert.Equal(t, req.headers.Get(DeliveryHeader), event.ID)
+	assert.Equal(t, "Frieren", event.Data.(map[string]interface{})["mediaTitle"])
+
+	// The scan event and the disabled webhook are not delivered
+	select {
+	case <-ch:
+		t.Fatal("unexpected delivery")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBroker_SendTest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(EventHeader) != string(TypePing) {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	b := NewBroker()
+	assert.NoError(t, b.SendTest(&models.EventWebhook{URL: server.URL}))
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer failing.Close()
+	assert.ErrorContains(t, b.SendTest(&models.EventWebhook{URL: failing.URL}), "401")
+}
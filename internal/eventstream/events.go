@@ -0,0 +1,111 @@
+package eventstream
+
+import (
+	"time"
+)
+
+// Version is the version of the event payloads.
+// It is increased when a payload changes in a way that isn't backwards compatible.
+const Version = 1
+
+type Type string
+
+const (
+	TypePlaybackStarted      Type = "playback.started"
+	TypePlaybackStopped      Type = "playback.stopped"
+	TypePlaybackCompleted    Type = "playback.completed"
+	TypeProgressUpdated      Type = "progress.updated"
+	TypeLibraryScanCompleted Type = "library.scan-completed"
+	TypeDownloadCompleted    Type = "download.completed"
+	TypeDownloadFailed       Type = "download.failed"
+	TypeDownloadCancelled    Type = "download.cancelled"
+	TypeListEntryChanged     Type = "list.entry-changed"
+)
+
+type (
+	// Event is the envelope sent to the event stream subscribers and the webhooks.
+	Event struct {
+		ID        string      `json:"id"`
+		Version   int         `json:"version"`
+		Type      Type        `json:"type"`
+		Timestamp time.Time   `json:"timestamp"`
+		Data      interface{} `json:"data"`
+	}
+
+	// TypeInfo documents an event type.
+	TypeInfo struct {
+		Type        Type   `json:"type"`
+		Description string `json:"description"`
+		// Name of the payload struct, e.g. "PlaybackPayload"
+		Payload string `json:"payload"`
+	}
+
+	// PlaybackPayload is the payload of the playback events and "progress.updated".
+	PlaybackPayload struct {
+		MediaId       int    `json:"mediaId"`
+		MediaTitle    string `json:"mediaTitle"`
+		EpisodeNumber int    `json:"episodeNumber"`
+		// Empty when streaming
+		Filename             string  `json:"filename"`
+		CompletionPercentage float64 `json:"completionPercentage"` // 0 to 1
+		Streaming            bool    `json:"streaming"`
+	}
+
+	PlaybackStoppedPayload struct {
+		Reason string `json:"reason"`
+	}
+
+	LibraryScanCompletedPayload struct {
+		Files     int `json:"files"`
+		Unmatched int `json:"unmatched"`
+	}
+
+	DownloadCompletedPayload struct {
+		// "torrent-client", "debrid", "torrentstream", "manga" or "onlinestream"
+		Source string `json:"source"`
+		Name   string `json:"name"`
+		// 0 if the download isn't linked to a media
+		MediaId int `json:"mediaId"`
+		// Episode or chapter number, empty if the download isn't a single episode or chapter
+		Episode string `json:"episode"`
+	}
+
+	DownloadFailedPayload struct {
+		// "debrid"
+		Source string `json:"source"`
+		Name   string `json:"name"`
+		// 0 if the download isn't linked to a media
+		MediaId int    `json:"mediaId"`
+		Error   string `json:"error"`
+	}
+
+	ListEntryChangedPayload struct {
+		MediaId int `json:"mediaId"`
+		// Fields that weren't changed are omitted
+		Status   string `json:"status,omitempty"`
+		Progress *int   `json:"progress,omitempty"`
+		Score    *int   `json:"score,omitempty"` // 0 to 100
+		Deleted  bool   `json:"deleted"`
+	}
+)
+
+var Types = []*TypeInfo{
+	{Type: TypePlaybackStarted, Description: "An episode started playing", Payload: "PlaybackPayload"},
+	{Type: TypePlaybackStopped, Description: "The media player was closed", Payload: "PlaybackStoppedPayload"},
+	{Type: TypePlaybackCompleted, Description: "An episode was watched until the end", Payload: "PlaybackPayload"},
+	{Type: TypeProgressUpdated, Description: "The progress of the playing episode was saved to AniList", Payload: "PlaybackPayload"},
+	{Type: TypeLibraryScanCompleted, Description: "A library scan finished", Payload: "LibraryScanCompletedPayload"},
+	{Type: TypeDownloadCompleted, Description: "A torrent, debrid, manga chapter or online stream download finished", Payload: "DownloadCompletedPayload"},
+	{Type: TypeDownloadFailed, Description: "A debrid download failed", Payload: "DownloadFailedPayload"},
+	{Type: TypeDownloadCancelled, Description: "A debrid download was cancelled", Payload: "DownloadCompletedPayload"},
+	{Type: TypeListEntryChanged, Description: "An anime or manga list entry was updated or deleted", Payload: "ListEntryChangedPayload"},
+}
+
+func isKnownType(t Type) bool {
+	for _, info := range Types {
+		if info.Type == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package eventstream
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"seanime/internal/database/models"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	SignatureHeader = "X-Seanime-Signature"
+	TimestampHeader = "X-Seanime-Timestamp"
+	EventHeader     = "X-Seanime-Event"
+	DeliveryHeader  = "X-Seanime-Delivery"
+
+	// TypePing is only sent to webhooks when they are tested
+	TypePing Type = "ping"
+
+	deliveryTimeout = 15 * time.Second
+)
+
+type (
+	webhookSender struct {
+		client *http.Client
+		// Delays between the delivery attempts, the number of retries is the length of the slice
+		retryDelays []time.Duration
+	}
+)
+
+func newWebhookSender() *webhookSender {
+	return &webhookSender{
+		client:      &http.Client{Timeout: deliveryTimeout},
+		retryDelays: []time.Duration{10 * time.Second, time.Minute, 5 * time.Minute},
+	}
+}
+
+// Sign returns the signature of a payload sent at the given Unix timestamp.
+// The signature is the hex-encoded HMAC-SHA256 of "{timestamp}.{body}", prefixed with "sha256=".
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateWebhook checks the URL and the event types of a webhook.
+func ValidateWebhook(webhook *models.EventWebhook) error {
+	if webhook == nil {
+		return errors.New("eventstream: webhook is required")
+	}
+
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("eventstream: invalid url %q", webhook.URL)
+	}
+
+	for _, t := range webhook.Events {
+		if !isKnownType(Type(t)) {
+			return fmt.Errorf("eventstream: unknown event type %q", t)
+		}
+	}
+
+	return nil
+}
+
+// SendTest sends a "ping" event to a webhook once, the webhook doesn't need to be saved.
+func (b *Broker) SendTest(webhook *models.EventWebhook) error {
+	if err := ValidateWebhook(webhook); err != nil {
+		return err
+	}
+
+	event := &Event{
+		ID:        uuid.NewString(),
+		Version:   Version,
+		Type:      TypePing,
+		Timestamp: time.Now(),
+		Data:      map[string]interface{}{},
+	}
+
+	_, err := b.sender.send(webhook, event)
+	return err
+}
+
+// deliver sends an event to a webhook, retrying on network errors, 5xx, 408 and 429 responses.
+// The result of the last attempt is saved.
+func (b *Broker) deliver(webhook *models.EventWebhook, event *Event) {
+	var status int
+	var err error
+	for attempt := 0; ; attempt++ {
+		status, err = b.sender.send(webhook, event)
+		if err == nil || !isRetryable(status) || attempt >= len(b.sender.retryDelays) {
+			break
+		}
+		b.log(func(l *zerolog.Logger) {
+			l.Debug().Err(err).Str("url", webhook.URL).Int("attempt", attempt+1).Msg("eventstream: Webhook delivery failed, retrying")
+		})
+		time.Sleep(b.sender.retryDelays[attempt])
+	}
+
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+		b.log(func(l *zerolog.Logger) {
+			l.Warn().Err(err).Str("url", webhook.URL).Str("type", string(event.Type)).Msg("eventstream: Failed to deliver event to webhook")
+		})
+	}
+
+	b.mu.RLock()
+	database, ok := b.database.Get()
+	b.mu.RUnlock()
+	if ok && webhook.ID != 0 {
+		if err := database.UpdateEventWebhookDelivery(webhook.ID, time.Now(), status, errMsg); err != nil {
+			b.log(func(l *zerolog.Logger) {
+				l.Error().Err(err).Msg("eventstream: Failed to save webhook delivery")
+			})
+		}
+	}
+}
+
+// send posts the signed event to the webhook and returns the response status, 0 if the request failed.
+func (s *webhookSender) send(webhook *models.EventWebhook, event *Event) (int, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Seanime")
+	req.Header.Set(EventHeader, string(event.Type))
+	req.Header.Set(DeliveryHeader, event.ID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	if webhook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode, fmt.Errorf("eventstream: unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return resp.StatusCode, nil
+}
+
+func isRetryable(status int) bool {
+	return status == 0 || status >= 500 || status == http.StatusRequestTimeout || status == http.StatusTooManyRequests
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"seanime/internal/api/anilist"
 	"seanime/internal/events"
+	"seanime/internal/eventstream"
 	"seanime/internal/util/result"
 	"strconv"
 	"time"
@@ -239,6 +240,11 @@ func (h *Handler) HandleDeleteAnilistListEntry(c echo.Context) error {
 		return h.RespondWithError(c, err)
 	}
 
+	eventstream.GlobalBroker.Publish(eventstream.TypeListEntryChanged, &eventstream.ListEntryChangedPayload{
+		MediaId: *p.MediaId,
+		Deleted: true,
+	})
+
 	switch *p.Type {
 	case "anime":
 		_, _ = h.App.RefreshAnimeCollection()
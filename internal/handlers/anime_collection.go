@@ -3,6 +3,7 @@ package handlers
 import (
 	"errors"
 	"seanime/internal/database/db_bridge"
+	debrid_client "seanime/internal/debrid/client"
 	"seanime/internal/library/anime"
 	"seanime/internal/torrentstream"
 
@@ -55,6 +56,13 @@ func (h *Handler) HandleGetLibraryCollection(c echo.Context) error {
 		})
 	}
 
+	if h.App.SecondarySettings.Debrid != nil && h.App.SecondarySettings.Debrid.Enabled && h.App.SecondarySettings.Debrid.IncludeCloudTorrentsInLibrary {
+		h.App.DebridClientRepository.HydrateCloudCollection(&debrid_client.HydrateCloudCollectionOptions{
+			AnimeCollection:   animeCollection,
+			LibraryCollection: libraryCollection,
+		})
+	}
+
 	// Hydrate total library size
 	libraryCollection.Stats.TotalSize = humanize.Bytes(h.App.TotalLibrarySize)
 
@@ -330,6 +330,7 @@ func (h *Handler) HandleAnimeEntryManualMatch(c echo.Context) error {
 		item.MediaId = b.MediaId
 		item.Locked = true
 		item.Ignored = false
+		item.IgnoredBySeaignore = false
 		return item
 	})
 
@@ -393,6 +394,8 @@ func (h *Handler) HandleAnimeEntryManualMatch(c echo.Context) error {
 		return h.RespondWithError(c, err)
 	}
 
+	h.learnMatchingRule(selectedLfs, b.MediaId, lfs)
+
 	return h.RespondWithData(c, retLfs)
 
 }
@@ -4,10 +4,13 @@ import (
 	"errors"
 	"path/filepath"
 	"seanime/internal/database/db_bridge"
+	"seanime/internal/database/models"
 	"seanime/internal/library/anime"
+	"seanime/internal/util"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
 )
 
 // HandleRunAutoDownloader
@@ -97,6 +100,7 @@ func (h *Handler) HandleCreateAutoDownloaderRule(c echo.Context) error {
 		EpisodeType         anime.AutoDownloaderRuleEpisodeType         `json:"episodeType"`
 		EpisodeNumbers      []int                                       `json:"episodeNumbers,omitempty"`
 		Destination         string                                      `json:"destination"`
+		DestinationRoot     string                                      `json:"destinationRoot"`
 	}
 
 	var b body
@@ -105,12 +109,8 @@ func (h *Handler) HandleCreateAutoDownloaderRule(c echo.Context) error {
 		return h.RespondWithError(c, err)
 	}
 
-	if b.Destination == "" {
-		return h.RespondWithError(c, errors.New("destination is required"))
-	}
-
-	if !filepath.IsAbs(b.Destination) {
-		return h.RespondWithError(c, errors.New("destination must be an absolute path"))
+	if err := h.validateAutoDownloaderRuleDestination(b.Destination, b.DestinationRoot); err != nil {
+		return h.RespondWithError(c, err)
 	}
 
 	rule := &anime.AutoDownloaderRule{
@@ -123,6 +123,7 @@ func (h *Handler) HandleCreateAutoDownloaderRule(c echo.Context) error {
 		EpisodeType:         b.EpisodeType,
 		EpisodeNumbers:      b.EpisodeNumbers,
 		Destination:         b.Destination,
+		DestinationRoot:     b.DestinationRoot,
 		AdditionalTerms:     b.AdditionalTerms,
 	}
 
@@ -160,6 +161,10 @@ func (h *Handler) HandleUpdateAutoDownloaderRule(c echo.Context) error {
 		return h.RespondWithError(c, errors.New("invalid id"))
 	}
 
+	if err := h.validateAutoDownloaderRuleDestination(b.Rule.Destination, b.Rule.DestinationRoot); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
 	// Update the rule based on its DbID (primary key)
 	if err := db_bridge.UpdateAutoDownloaderRule(h.App.Database, b.Rule.DbID, b.Rule); err != nil {
 		return h.RespondWithError(c, err)
@@ -168,6 +173,27 @@ func (h *Handler) HandleUpdateAutoDownloaderRule(c echo.Context) error {
 	return h.RespondWithData(c, b.Rule)
 }
 
+// validateAutoDownloaderRuleDestination checks that the destination is absolute, or relative to a library directory.
+func (h *Handler) validateAutoDownloaderRuleDestination(destination string, destinationRoot string) error {
+	if destinationRoot == "" {
+		if destination == "" {
+			return errors.New("destination is required")
+		}
+		if !filepath.IsAbs(destination) {
+			return errors.New("destination must be an absolute path")
+		}
+		return nil
+	}
+
+	_, found := lo.Find(h.App.Settings.Library.GetLibraryRoots(), func(root *models.LibraryRoot) bool {
+		return util.IsSameDir(root.Path, destinationRoot)
+	})
+	if !found {
+		return errors.New("destination library directory not found")
+	}
+	return nil
+}
+
 // HandleDeleteAutoDownloaderRule
 //
 //	@summary deletes a rule.
@@ -5,10 +5,12 @@ import (
 	"path/filepath"
 	"seanime/internal/api/anilist"
 	"seanime/internal/api/metadata"
+	"seanime/internal/database/db_bridge"
 	"seanime/internal/database/models"
 	debrid_client "seanime/internal/debrid/client"
 	"seanime/internal/debrid/debrid"
 	"seanime/internal/events"
+	"strconv"
 
 	hibiketorrent "github.com/5rahim/hibike/pkg/extension/torrent"
 
@@ -81,6 +83,13 @@ func (h *Handler) HandleDebridAddTorrents(c echo.Context) error {
 		return h.RespondWithError(c, errors.New("debrid provider not set"))
 	}
 
+	// Torrents are not downloaded to library directories that are disabled, read-only or offline
+	if b.Destination != "" {
+		if _, err := db_bridge.ResolveDownloadDestination(h.App.Database, b.Destination); err != nil {
+			return h.RespondWithError(c, err)
+		}
+	}
+
 	for _, torrent := range b.Torrents {
 		// Get the torrent's provider extension
 		animeTorrentProviderExtension, ok := h.App.TorrentRepository.GetAnimeProviderExtension(torrent.Provider)
@@ -144,6 +153,11 @@ func (h *Handler) HandleDebridDownloadTorrent(c echo.Context) error {
 		return h.RespondWithError(c, errors.New("destination must be an absolute path"))
 	}
 
+	// Torrents are not downloaded to library directories that are disabled, read-only or offline
+	if _, err := db_bridge.ResolveDownloadDestination(h.App.Database, b.Destination); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
 	// Remove the torrent from the database
 	// This is done so that the torrent is not downloaded automatically
 	// We ignore the error here because the torrent might not be in the database
@@ -403,3 +417,92 @@ func (h *Handler) HandleDebridCancelStream(c echo.Context) error {
 
 	return h.RespondWithData(c, true)
 }
+
+// HandleDebridGetCloudFiles
+//
+//	@summary get the files of an anime available in the cloud.
+//	@desc This returns the files of completed debrid torrents that are mapped to the anime's episodes.
+//	@desc Torrents are analyzed the first time they are seen, this can take a while.
+//	@route /api/v1/debrid/cloud/{id} [GET]
+//	@param id - int - true - "AniList anime media ID"
+//	@returns []debrid_client.CloudFile
+func (h *Handler) HandleDebridGetCloudFiles(c echo.Context) error {
+	mId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	animeCollection, err := h.App.GetAnimeCollection(false)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	files, err := h.App.DebridClientRepository.GetMediaCloudFiles(animeCollection, mId)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, files)
+}
+
+// HandleDebridRefreshCloudLibrary
+//
+//	@summary refresh the list of torrents available in the cloud.
+//	@desc This fetches the completed debrid torrents and analyzes the new ones.
+//	@desc The client should refetch the library collection.
+//	@route /api/v1/debrid/cloud/refresh [POST]
+//	@returns bool
+func (h *Handler) HandleDebridRefreshCloudLibrary(c echo.Context) error {
+	animeCollection, err := h.App.GetAnimeCollection(false)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	_, err = h.App.DebridClientRepository.GetCloudFiles(animeCollection, true)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleDebridStartCloudStream
+//
+//	@summary stream an episode available in the cloud.
+//	@desc This requests a new stream URL from the debrid service and sends it to the player.
+//	@returns bool
+//	@route /api/v1/debrid/cloud/stream [POST]
+func (h *Handler) HandleDebridStartCloudStream(c echo.Context) error {
+	type body struct {
+		MediaId       int                              `json:"mediaId"`
+		EpisodeNumber int                              `json:"episodeNumber"`
+		AniDBEpisode  string                           `json:"aniDBEpisode"`
+		PlaybackType  debrid_client.StreamPlaybackType `json:"playbackType"` // "default" or "externalPlayerLink"
+		ClientId      string                           `json:"clientId"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	animeCollection, err := h.App.GetAnimeCollection(false)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	err = h.App.DebridClientRepository.StartCloudStream(&debrid_client.StartCloudStreamOptions{
+		AnimeCollection: animeCollection,
+		MediaId:         b.MediaId,
+		EpisodeNumber:   b.EpisodeNumber,
+		AniDBEpisode:    b.AniDBEpisode,
+		UserAgent:       c.Request().Header.Get("User-Agent"),
+		ClientId:        b.ClientId,
+		PlaybackType:    b.PlaybackType,
+	})
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"seanime/internal/database/models"
+	"seanime/internal/downloadmanager"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetDownloadManagerSnapshot
+//
+//	@summary returns the downloads of all sources.
+//	@desc This includes the torrent client, debrid downloads, torrent streaming imports, the manga chapter queue and the online stream queue.
+//	@desc The client is notified of changes through the 'download-manager-updated' websocket event.
+//	@returns downloadmanager.Snapshot
+//	@route /api/v1/download-manager [GET]
+func (h *Handler) HandleGetDownloadManagerSnapshot(c echo.Context) error {
+	h.App.DownloadManager.Refresh()
+	return h.RespondWithData(c, h.App.DownloadManager.GetSnapshot())
+}
+
+// HandleDownloadManagerSetPaused
+//
+//	@summary pauses or resumes all downloads.
+//	@desc Downloads paused by the user stay paused when all downloads are resumed.
+//	@returns downloadmanager.Snapshot
+//	@route /api/v1/download-manager/pause [POST]
+func (h *Handler) HandleDownloadManagerSetPaused(c echo.Context) error {
+
+	type body struct {
+		Paused bool `json:"paused"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := h.App.DownloadManager.SetPaused(b.Paused); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, h.App.DownloadManager.GetSnapshot())
+}
+
+// HandleDownloadManagerJobAction
+//
+//	@summary pauses or resumes a download.
+//	@desc 'action' is either "pause" or "resume".
+//	@desc A resumed download is paused again if all downloads are paused or if it's outside the schedule windows and its priority isn't high.
+//	@returns downloadmanager.Snapshot
+//	@route /api/v1/download-manager/job/action [POST]
+func (h *Handler) HandleDownloadManagerJobAction(c echo.Context) error {
+
+	type body struct {
+		ID     string `json:"id"`
+		Action string `json:"action"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	var err error
+	switch b.Action {
+	case "pause":
+		err = h.App.DownloadManager.PauseJob(b.ID)
+	case "resume":
+		err = h.App.DownloadManager.ResumeJob(b.ID)
+	default:
+		err = errors.New("invalid action")
+	}
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, h.App.DownloadManager.GetSnapshot())
+}
+
+// HandleDownloadManagerSetJobPriority
+//
+//	@summary sets the priority of a download.
+//	@desc 'priority' is -1 (low), 0 (normal) or 1 (high).
+//	@desc High priority downloads ignore the schedule windows.
+//	@desc Low priority downloads are paused while a download with a higher priority from the same source is running.
+//	@returns downloadmanager.Snapshot
+//	@route /api/v1/download-manager/job/priority [POST]
+func (h *Handler) HandleDownloadManagerSetJobPriority(c echo.Context) error {
+
+	type body struct {
+		ID       string                   `json:"id"`
+		Priority downloadmanager.Priority `json:"priority"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := h.App.DownloadManager.SetJobPriority(b.ID, b.Priority); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, h.App.DownloadManager.GetSnapshot())
+}
+
+// HandleGetDownloadManagerSettings
+//
+//	@summary returns the download manager settings.
+//	@returns models.DownloadManagerSettings
+//	@route /api/v1/download-manager/settings [GET]
+func (h *Handler) HandleGetDownloadManagerSettings(c echo.Context) error {
+	return h.RespondWithData(c, h.App.DownloadManager.GetSettings())
+}
+
+// HandleSaveDownloadManagerSettings
+//
+//	@summary saves the download manager settings.
+//	@desc Schedule windows use "HH:MM" times, a window ending before it starts ends on the next day.
+//	@desc The rate limit of a window is in KiB/s and applies to the torrent client and debrid downloads.
+//	@returns models.DownloadManagerSettings
+//	@route /api/v1/download-manager/settings [PATCH]
+func (h *Handler) HandleSaveDownloadManagerSettings(c echo.Context) error {
+
+	type body struct {
+		Settings models.DownloadManagerSettings `json:"settings"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	settings, err := h.App.DownloadManager.SaveSettings(&b.Settings)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, settings)
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"seanime/internal/database/models"
+	"seanime/internal/eventstream"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/labstack/echo/v4"
+)
+
+const eventStreamHeartbeatInterval = 30 * time.Second
+
+// HandleEventStream
+//
+//	@summary streams the public events as Server-Sent Events.
+//	@desc The 'types' query parameter is an optional comma-separated list of event types, all events are sent if it's empty.
+//	@desc Each message has the event ID, the event type and the JSON-encoded event as data.
+//	@desc Clients that reconnect with the 'Last-Event-ID' header receive the events they missed, as long as they are still in the history.
+//	@desc A comment is sent every 30 seconds to keep the connection alive.
+//	@returns eventstream.Event
+//	@route /api/v1/event-stream [GET]
+func (h *Handler) HandleEventStream(c echo.Context) error {
+
+	types := make([]eventstream.Type, 0)
+	for _, t := range strings.Split(c.QueryParam("types"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, eventstream.Type(t))
+		}
+	}
+
+	sub, missed := eventstream.GlobalBroker.Subscribe(types, c.Request().Header.Get("Last-Event-ID"))
+	defer eventstream.GlobalBroker.Unsubscribe(sub)
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Ask the client to wait 5 seconds before reconnecting
+	if _, err := fmt.Fprint(w, "retry: 5000\n\n"); err != nil {
+		return nil
+	}
+	for _, event := range missed {
+		if err := writeServerSentEvent(w, event); err != nil {
+			return nil
+		}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return nil
+			}
+			w.Flush()
+		case event := <-sub.C:
+			if err := writeServerSentEvent(w, event); err != nil {
+				return nil
+			}
+			w.Flush()
+		}
+	}
+}
+
+func writeServerSentEvent(w *echo.Response, event *eventstream.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// HandleGetEventStreamTypes
+//
+//	@summary returns the event types published to the event stream and the webhooks.
+//	@desc The payload of each type is documented in the eventstream package.
+//	@returns []eventstream.TypeInfo
+//	@route /api/v1/event-stream/types [GET]
+func (h *Handler) HandleGetEventStreamTypes(c echo.Context) error {
+	return h.RespondWithData(c, eventstream.Types)
+}
+
+// HandleGetEventWebhooks
+//
+//	@summary returns the webhooks that receive the events.
+//	@returns []models.EventWebhook
+//	@route /api/v1/event-stream/webhooks [GET]
+func (h *Handler) HandleGetEventWebhooks(c echo.Context) error {
+	webhooks, err := h.App.Database.GetEventWebhooks()
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, webhooks)
+}
+
+// HandleSaveEventWebhook
+//
+//	@summary creates or updates a webhook.
+//	@desc A webhook with an ID of 0 is created, a secret is generated if it's empty.
+//	@desc When updating a webhook, an empty secret keeps the stored one.
+//	@desc Payloads are signed with HMAC-SHA256, the signature of "{timestamp}.{body}" is sent in the 'X-Seanime-Signature' header
+//	@desc and the Unix timestamp in the 'X-Seanime-Timestamp' header.
+//	@returns models.EventWebhook
+//	@route /api/v1/event-stream/webhook [POST]
+func (h *Handler) HandleSaveEventWebhook(c echo.Context) error {
+
+	type body struct {
+		Webhook *models.EventWebhook `json:"webhook"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := eventstream.ValidateWebhook(b.Webhook); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if b.Webhook.ID == 0 && b.Webhook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return h.RespondWithError(c, err)
+		}
+		b.Webhook.Secret = hex.EncodeToString(secret)
+	}
+
+	if err := h.App.Database.SaveEventWebhook(b.Webhook); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := eventstream.GlobalBroker.Reload(); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, b.Webhook)
+}
+
+// HandleDeleteEventWebhook
+//
+//	@summary deletes a webhook.
+//	@returns bool
+//	@route /api/v1/event-stream/webhook [DELETE]
+func (h *Handler) HandleDeleteEventWebhook(c echo.Context) error {
+
+	type body struct {
+		ID uint `json:"id"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := h.App.Database.DeleteEventWebhook(b.ID); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := eventstream.GlobalBroker.Reload(); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleTestEventWebhook
+//
+//	@summary sends a 'ping' event to a webhook.
+//	@desc The webhook doesn't need to be saved. The event is sent once, without retries.
+//	@returns bool
+//	@route /api/v1/event-stream/webhook/test [POST]
+func (h *Handler) HandleTestEventWebhook(c echo.Context) error {
+
+	type body struct {
+		Webhook *models.EventWebhook `json:"webhook"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := eventstream.GlobalBroker.SendTest(b.Webhook); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/library/anime"
+	"seanime/internal/library/filesystem"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetLibraryRoots
+//
+//	@summary returns the library directories with their settings and availability.
+//	@desc The directories are sorted by descending priority.
+//	@desc A directory is offline when it cannot be found, e.g. when its drive is unplugged. The local files of offline directories are kept.
+//	@desc An empty directory that had local files is offline too, it is likely the mount point of an unmounted drive.
+//	@route /api/v1/library/roots [GET]
+//	@returns []filesystem.LibraryRootStatus
+func (h *Handler) HandleGetLibraryRoots(c echo.Context) error {
+
+	settings, err := h.App.Database.GetSettings()
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	lfs, _, err := db_bridge.GetLocalFiles(h.App.Database)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, filesystem.GetLibraryRootStatuses(settings.Library, anime.GetLocalFilePaths(lfs)))
+}
@@ -31,6 +31,36 @@ func (h *Handler) HandleGetLocalFiles(c echo.Context) error {
 	return h.RespondWithData(c, lfs)
 }
 
+// HandleFilterLocalFiles
+//
+//	@summary returns the local files matching the filter.
+//	@desc Technical metadata (codecs, tracks, duration) is only available when media info probing is enabled in the library settings.
+//	@desc Files that were not probed are excluded as soon as the filter uses technical metadata.
+//	@route /api/v1/library/local-files/filter [POST]
+//	@returns []anime.LocalFile
+func (h *Handler) HandleFilterLocalFiles(c echo.Context) error {
+
+	type body struct {
+		Filter *anime.LocalFileFilter `json:"filter"`
+	}
+
+	b := new(body)
+	if err := c.Bind(b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	lfs, _, err := db_bridge.GetLocalFiles(h.App.Database)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if b.Filter == nil {
+		return h.RespondWithData(c, lfs)
+	}
+
+	return h.RespondWithData(c, b.Filter.Filter(lfs))
+}
+
 func (h *Handler) HandleDumpLocalFilesToFile(c echo.Context) error {
 
 	lfs, _, err := db_bridge.GetLocalFiles(h.App.Database)
@@ -173,6 +203,7 @@ func (h *Handler) HandleUpdateLocalFileData(c echo.Context) error {
 	if !found {
 		return h.RespondWithError(c, errors.New("local file not found"))
 	}
+	corrected := b.MediaId != 0 && b.MediaId != lf.MediaId
 	lf.Metadata = b.Metadata
 	lf.Locked = b.Locked
 	lf.Ignored = b.Ignored
@@ -184,6 +215,10 @@ func (h *Handler) HandleUpdateLocalFileData(c echo.Context) error {
 		return h.RespondWithError(c, err)
 	}
 
+	if corrected {
+		h.learnMatchingRule([]*anime.LocalFile{lf}, b.MediaId, lfs)
+	}
+
 	return h.RespondWithData(c, retLfs)
 }
 
@@ -212,6 +247,9 @@ func (h *Handler) HandleUpdateLocalFiles(c echo.Context) error {
 		return h.RespondWithError(c, err)
 	}
 
+	// Files manually matched to a different media
+	corrected := make([]*anime.LocalFile, 0)
+
 	// Update the files
 	for _, path := range b.Paths {
 		lf, found := lo.Find(lfs, func(i *anime.LocalFile) bool {
@@ -231,15 +269,20 @@ func (h *Handler) HandleUpdateLocalFiles(c echo.Context) error {
 			lf.Locked = false
 		case "unignore":
 			lf.Ignored = false
+			lf.IgnoredBySeaignore = false
 			lf.Locked = false
 		case "unmatch":
 			lf.MediaId = 0
 			lf.Locked = false
 			lf.Ignored = false
 		case "match":
+			if b.MediaId != 0 && lf.MediaId != b.MediaId {
+				corrected = append(corrected, lf)
+			}
 			lf.MediaId = b.MediaId
 			lf.Locked = true
 			lf.Ignored = false
+			lf.IgnoredBySeaignore = false
 		}
 	}
 
@@ -249,6 +292,8 @@ func (h *Handler) HandleUpdateLocalFiles(c echo.Context) error {
 		return h.RespondWithError(c, err)
 	}
 
+	h.learnMatchingRule(corrected, b.MediaId, lfs)
+
 	return h.RespondWithData(c, true)
 }
 
@@ -256,6 +301,7 @@ func (h *Handler) HandleUpdateLocalFiles(c echo.Context) error {
 //
 //	@summary deletes local files with the given paths.
 //	@desc This will delete the local files with the given paths.
+//	@desc Files of read-only library directories cannot be deleted.
 //	@desc The client should refetch the entire library collection and media entry.
 //	@route /api/v1/library/local-files [DELETE]
 //	@returns bool
@@ -276,6 +322,13 @@ func (h *Handler) HandleDeleteLocalFiles(c echo.Context) error {
 		return h.RespondWithError(c, err)
 	}
 
+	roots := h.App.Settings.Library.GetLibraryRoots()
+	for _, path := range b.Paths {
+		if filesystem.IsInReadOnlyRoot(roots, path) {
+			return h.RespondWithError(c, fmt.Errorf("cannot delete %s, its library directory is read-only", path))
+		}
+	}
+
 	// Delete the files
 	p := pool.New().WithErrors()
 	for _, path := range b.Paths {
@@ -310,17 +363,26 @@ func (h *Handler) HandleDeleteLocalFiles(c echo.Context) error {
 //
 //	@summary removes empty directories.
 //	@desc This will remove empty directories in the library path.
+//	@desc Disabled, read-only and offline library directories are left untouched.
 //	@route /api/v1/library/empty-directories [DELETE]
 //	@returns bool
 func (h *Handler) HandleRemoveEmptyDirectories(c echo.Context) error {
 
-	libraryPaths, err := h.App.Database.GetAllLibraryPathsFromSettings()
+	settings, err := h.App.Database.GetSettings()
 	if err != nil {
 		return h.RespondWithError(c, err)
 	}
 
-	for _, path := range libraryPaths {
-		filesystem.RemoveEmptyDirectories(path, h.App.Logger)
+	lfs, _, err := db_bridge.GetLocalFiles(h.App.Database)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	for _, status := range filesystem.GetLibraryRootStatuses(settings.Library, anime.GetLocalFilePaths(lfs)) {
+		if !status.Root.Enabled || status.Root.ReadOnly || status.Offline {
+			continue
+		}
+		filesystem.RemoveEmptyDirectories(status.Root.Path, h.App.Logger)
 	}
 
 	return h.RespondWithData(c, true)
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"seanime/internal/database/models"
+	"seanime/internal/library/anime"
+	"seanime/internal/library/scanner"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetMatchingRules
+//
+//	@summary returns the scanner's matching rules.
+//	@returns []models.MatchingRule
+//	@route /api/v1/library/matching-rules [GET]
+func (h *Handler) HandleGetMatchingRules(c echo.Context) error {
+	rules, err := h.App.Database.GetMatchingRules()
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, rules)
+}
+
+// HandleSaveMatchingRule
+//
+//	@summary creates or updates a matching rule.
+//	@desc A rule with an ID of 0 is created.
+//	@desc Rules are evaluated by the scanner before fuzzy matching, they apply to the next scan.
+//	@returns models.MatchingRule
+//	@route /api/v1/library/matching-rule [POST]
+func (h *Handler) HandleSaveMatchingRule(c echo.Context) error {
+
+	type body struct {
+		Rule *models.MatchingRule `json:"rule"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := scanner.ValidateMatchingRule(b.Rule); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := h.App.Database.SaveMatchingRule(b.Rule); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, b.Rule)
+}
+
+// HandleDeleteMatchingRule
+//
+//	@summary deletes a matching rule.
+//	@returns bool
+//	@route /api/v1/library/matching-rule [DELETE]
+func (h *Handler) HandleDeleteMatchingRule(c echo.Context) error {
+
+	type body struct {
+		ID uint `json:"id"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := h.App.Database.DeleteMatchingRule(b.ID); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// learnMatchingRule creates a matching rule from a manual match if the option is enabled.
+// localFiles are all the local files, after the match.
+func (h *Handler) learnMatchingRule(lfs []*anime.LocalFile, mediaId int, localFiles []*anime.LocalFile) {
+	if h.App.Settings == nil || h.App.Settings.Library == nil || !h.App.Settings.Library.ScannerLearnMatchingRules {
+		return
+	}
+
+	rule, ok := scanner.LearnMatchingRule(lfs, mediaId, h.App.Settings.Library.GetLibraryPaths(), localFiles)
+	if !ok {
+		return
+	}
+
+	if err := h.App.Database.SaveLearnedMatchingRule(rule); err != nil {
+		h.App.Logger.Error().Err(err).Msg("scanner: Failed to save learned matching rule")
+		return
+	}
+
+	h.App.Logger.Debug().Str("type", rule.Type).Str("pattern", rule.Pattern).Int("mediaId", mediaId).Msg("scanner: Learned matching rule")
+}
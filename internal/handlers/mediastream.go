@@ -135,6 +135,41 @@ func (h *Handler) HandleMediastreamGetAttachments(c echo.Context) error {
 	return h.App.MediastreamRepository.ServeEchoExtractedAttachments(c)
 }
 
+//
+// Trickplay
+//
+
+// HandleRequestMediastreamTrickplay
+//
+//	@summary requests the seek preview thumbnails of a file.
+//	@desc This returns the state of the thumbnails and starts generating them if they do not exist.
+//	@desc Once ready, the WebVTT thumbnail track is served at /api/v1/mediastream/trickplay/{hash}/thumbnails.vtt.
+//	@desc It returns null if seek previews are disabled.
+//	@returns trickplay.Info
+//	@route /api/v1/mediastream/trickplay [POST]
+func (h *Handler) HandleRequestMediastreamTrickplay(c echo.Context) error {
+
+	type body struct {
+		Path string `json:"path"` // The path of the file.
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	info, err := h.App.MediastreamRepository.RequestTrickplay(b.Path)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, info)
+}
+
+func (h *Handler) HandleMediastreamGetTrickplay(c echo.Context) error {
+	return h.App.MediastreamRepository.ServeEchoTrickplay(c)
+}
+
 //
 // Direct
 //
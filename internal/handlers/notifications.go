@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"seanime/internal/database/models"
+	"seanime/internal/notifier"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetNotificationEvents
+//
+//	@summary returns the events that can be routed to notification channels.
+//	@desc Each event lists the variables its title and message templates can use.
+//	@returns []notifier.EventInfo
+//	@route /api/v1/notifications/events [GET]
+func (h *Handler) HandleGetNotificationEvents(c echo.Context) error {
+	return h.RespondWithData(c, notifier.Events)
+}
+
+// HandleGetNotificationChannels
+//
+//	@summary returns the notification channels.
+//	@returns []models.NotificationChannel
+//	@route /api/v1/notifications/channels [GET]
+func (h *Handler) HandleGetNotificationChannels(c echo.Context) error {
+	channels, err := h.App.Database.GetNotificationChannels()
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, channels)
+}
+
+// HandleSaveNotificationChannel
+//
+//	@summary creates or updates a notification channel.
+//	@desc A channel with an ID of 0 is created.
+//	@desc The channel's configuration and templates are validated before being saved.
+//	@returns models.NotificationChannel
+//	@route /api/v1/notifications/channel [POST]
+func (h *Handler) HandleSaveNotificationChannel(c echo.Context) error {
+
+	type body struct {
+		Channel *models.NotificationChannel `json:"channel"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := notifier.ValidateChannel(b.Channel); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := h.App.Database.SaveNotificationChannel(b.Channel); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := notifier.GlobalDispatcher.Reload(); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, b.Channel)
+}
+
+// HandleDeleteNotificationChannel
+//
+//	@summary deletes a notification channel.
+//	@returns bool
+//	@route /api/v1/notifications/channel [DELETE]
+func (h *Handler) HandleDeleteNotificationChannel(c echo.Context) error {
+
+	type body struct {
+		ID uint `json:"id"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := h.App.Database.DeleteNotificationChannel(b.ID); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := notifier.GlobalDispatcher.Reload(); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleTestNotificationChannel
+//
+//	@summary sends a test notification through a channel.
+//	@desc The channel doesn't need to be saved, this can be used to check the configuration before saving it.
+//	@returns bool
+//	@route /api/v1/notifications/channel/test [POST]
+func (h *Handler) HandleTestNotificationChannel(c echo.Context) error {
+
+	type body struct {
+		Channel *models.NotificationChannel `json:"channel"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := notifier.GlobalDispatcher.SendTest(b.Channel); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
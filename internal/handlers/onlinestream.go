@@ -64,6 +64,9 @@ func (h *Handler) HandleGetOnlineStreamEpisodeList(c echo.Context) error {
 // HandleGetOnlineStreamEpisodeSource
 //
 //	@summary returns the video sources for the given media, episode number and provider.
+//	@desc The video sources are checked in parallel and ordered by availability and latency.
+//	@desc The best available source is flagged as recommended.
+//	@desc If no source is available, the episode servers are extracted again.
 //	@route /api/v1/onlinestream/episode-source [POST]
 //	@returns onlinestream.EpisodeSource
 func (h *Handler) HandleGetOnlineStreamEpisodeSource(c echo.Context) error {
@@ -225,3 +228,87 @@ func (h *Handler) HandleRemoveOnlinestreamMapping(c echo.Context) error {
 
 	return h.RespondWithData(c, true)
 }
+
+// HandleOnlinestreamDownloadEpisodes
+//
+//	@summary adds online stream episodes to the download queue.
+//	@desc The episodes are downloaded in the background, remuxed into MKV files with the subtitles embedded and saved in the library.
+//	@desc FFmpeg is required, the path set in the media streaming settings is used.
+//	@route /api/v1/onlinestream/download [POST]
+//	@returns bool
+func (h *Handler) HandleOnlinestreamDownloadEpisodes(c echo.Context) error {
+
+	type body struct {
+		MediaId        int    `json:"mediaId"`
+		Provider       string `json:"provider"`
+		Dubbed         bool   `json:"dubbed"`
+		EpisodeNumbers []int  `json:"episodeNumbers"`
+		Quality        string `json:"quality,omitempty"`
+		Server         string `json:"server,omitempty"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if h.App.Settings == nil || !h.App.Settings.Library.EnableOnlinestream {
+		return h.RespondWithError(c, errors.New("enable online streaming in the settings"))
+	}
+
+	err := h.App.OnlinestreamDownloader.DownloadEpisodes(&onlinestream.DownloadEpisodesOptions{
+		MediaId:        b.MediaId,
+		Provider:       b.Provider,
+		Dubbed:         b.Dubbed,
+		EpisodeNumbers: b.EpisodeNumbers,
+		Quality:        b.Quality,
+		Server:         b.Server,
+	})
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleGetOnlinestreamDownloadQueue
+//
+//	@summary returns the online stream download queue.
+//	@route /api/v1/onlinestream/download-queue [GET]
+//	@returns []onlinestream.DownloadQueueItem
+func (h *Handler) HandleGetOnlinestreamDownloadQueue(c echo.Context) error {
+	return h.RespondWithData(c, h.App.OnlinestreamDownloader.GetQueue())
+}
+
+// HandleCancelOnlinestreamDownload
+//
+//	@summary cancels a queued or running online stream download.
+//	@route /api/v1/onlinestream/download-queue/cancel [POST]
+//	@returns bool
+func (h *Handler) HandleCancelOnlinestreamDownload(c echo.Context) error {
+
+	type body struct {
+		ID string `json:"id"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := h.App.OnlinestreamDownloader.CancelDownload(b.ID); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleClearOnlinestreamDownloadQueue
+//
+//	@summary removes the finished downloads from the online stream download queue.
+//	@route /api/v1/onlinestream/download-queue [DELETE]
+//	@returns bool
+func (h *Handler) HandleClearOnlinestreamDownloadQueue(c echo.Context) error {
+	h.App.OnlinestreamDownloader.ClearFinished()
+	return h.RespondWithData(c, true)
+}
@@ -151,6 +151,7 @@ func (h *Handler) HandleDeletePlaylist(c echo.Context) error {
 // HandleGetPlaylistEpisodes
 //
 //	@summary returns all the local files of a playlist media entry that have not been watched.
+//	@desc Specials and NC files are listed after the main episodes, ordered by AniDB episode.
 //	@route /api/v1/playlist/episodes/{id}/{progress} [GET]
 //	@param id - int - true - "The ID of the media entry."
 //	@param progress - int - true - "The progress of the media entry."
@@ -180,6 +181,7 @@ func (h *Handler) HandleGetPlaylistEpisodes(c echo.Context) error {
 	}
 
 	toWatch := group.GetUnwatchedLocalFiles(progress)
+	toWatch = append(toWatch, group.GetExtraLocalFiles()...)
 
 	return h.RespondWithData(c, toWatch)
 }
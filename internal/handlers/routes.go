@@ -37,6 +37,7 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 		"/events",
 		"/api/v1/image-proxy",
 		"/api/v1/mediastream/transcode/",
+		"/api/v1/mediastream/trickplay/",
 		"/api/v1/torrent-client/list",
 		"/api/v1/proxy",
 	}
@@ -195,6 +196,9 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1Library := v1.Group("/library")
 
 	v1Library.POST("/scan", h.HandleScanLocalFiles)
+	v1Library.GET("/roots", h.HandleGetLibraryRoots)
+	v1Library.POST("/scan/dry-run", h.HandleDryRunScanLocalFiles)
+	v1Library.POST("/scan/cancel", h.HandleCancelScan)
 
 	v1Library.DELETE("/empty-directories", h.HandleRemoveEmptyDirectories)
 
@@ -203,12 +207,21 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1Library.PATCH("/local-files", h.HandleUpdateLocalFiles)
 	v1Library.DELETE("/local-files", h.HandleDeleteLocalFiles)
 	v1Library.GET("/local-files/dump", h.HandleDumpLocalFilesToFile)
+	v1Library.POST("/local-files/filter", h.HandleFilterLocalFiles)
 	v1Library.POST("/local-files/import", h.HandleImportLocalFiles)
 	v1Library.PATCH("/local-file", h.HandleUpdateLocalFileData)
 
 	v1Library.GET("/collection", h.HandleGetLibraryCollection)
 
 	v1Library.GET("/scan-summaries", h.HandleGetScanSummaries)
+	v1Library.GET("/scan-logs", h.HandleGetScanLogs)
+	v1Library.POST("/scan-logs/entries", h.HandleGetScanLogEntries)
+	v1Library.POST("/scan-diff/apply", h.HandleApplyScanDiff)
+	v1Library.POST("/scan-diff/discard", h.HandleDiscardScanDiff)
+
+	v1Library.GET("/matching-rules", h.HandleGetMatchingRules)
+	v1Library.POST("/matching-rule", h.HandleSaveMatchingRule)
+	v1Library.DELETE("/matching-rule", h.HandleDeleteMatchingRule)
 
 	v1Library.GET("/missing-episodes", h.HandleGetMissingEpisodes)
 
@@ -234,6 +247,54 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1.POST("/torrent-client/action", h.HandleTorrentClientAction)
 	v1.POST("/torrent-client/rule-magnet", h.HandleTorrentClientAddMagnetFromRule)
 
+	//
+	// SeaDex
+	//
+
+	v1.POST("/seadex/library-analysis", h.HandleGetSeaDexLibraryAnalysis)
+	v1.POST("/seadex/library-analysis/enqueue", h.HandleSeaDexEnqueueReleases)
+
+	//
+	// Download Manager
+	//
+
+	v1.GET("/download-manager", h.HandleGetDownloadManagerSnapshot)
+	v1.POST("/download-manager/pause", h.HandleDownloadManagerSetPaused)
+	v1.POST("/download-manager/job/action", h.HandleDownloadManagerJobAction)
+	v1.POST("/download-manager/job/priority", h.HandleDownloadManagerSetJobPriority)
+	v1.GET("/download-manager/settings", h.HandleGetDownloadManagerSettings)
+	v1.PATCH("/download-manager/settings", h.HandleSaveDownloadManagerSettings)
+
+	//
+	// Scheduler
+	//
+
+	v1.GET("/scheduler/jobs", h.HandleGetScheduledJobs)
+	v1.PATCH("/scheduler/job", h.HandleUpdateScheduledJob)
+	v1.POST("/scheduler/job/run", h.HandleRunScheduledJob)
+	v1.GET("/scheduler/job/:name/history", h.HandleGetScheduledJobHistory)
+
+	//
+	// Notifications
+	//
+
+	v1.GET("/notifications/events", h.HandleGetNotificationEvents)
+	v1.GET("/notifications/channels", h.HandleGetNotificationChannels)
+	v1.POST("/notifications/channel", h.HandleSaveNotificationChannel)
+	v1.DELETE("/notifications/channel", h.HandleDeleteNotificationChannel)
+	v1.POST("/notifications/channel/test", h.HandleTestNotificationChannel)
+
+	//
+	// Event stream
+	//
+
+	v1.GET("/event-stream", h.HandleEventStream)
+	v1.GET("/event-stream/types", h.HandleGetEventStreamTypes)
+	v1.GET("/event-stream/webhooks", h.HandleGetEventWebhooks)
+	v1.POST("/event-stream/webhook", h.HandleSaveEventWebhook)
+	v1.DELETE("/event-stream/webhook", h.HandleDeleteEventWebhook)
+	v1.POST("/event-stream/webhook/test", h.HandleTestEventWebhook)
+
 	//
 	// Download
 	//
@@ -294,6 +355,10 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1.POST("/onlinestream/manual-mapping", h.HandleOnlinestreamManualMapping)
 	v1.POST("/onlinestream/get-mapping", h.HandleGetOnlinestreamMapping)
 	v1.POST("/onlinestream/remove-mapping", h.HandleRemoveOnlinestreamMapping)
+	v1.POST("/onlinestream/download", h.HandleOnlinestreamDownloadEpisodes)
+	v1.GET("/onlinestream/download-queue", h.HandleGetOnlinestreamDownloadQueue)
+	v1.POST("/onlinestream/download-queue/cancel", h.HandleCancelOnlinestreamDownload)
+	v1.DELETE("/onlinestream/download-queue", h.HandleClearOnlinestreamDownloadQueue)
 
 	//
 	// Metadata Provider
@@ -358,6 +423,15 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1Discord.POST("/presence/anime", h.HandleSetDiscordAnimeActivity)
 	v1Discord.POST("/presence/cancel", h.HandleCancelDiscordActivity)
 
+	//
+	// Track Preferences
+	//
+	v1.GET("/track-preferences", h.HandleGetTrackPreferenceSettings)
+	v1.PATCH("/track-preferences", h.HandleSaveTrackPreferenceSettings)
+	v1.GET("/track-preferences/media/:id", h.HandleGetMediaTrackPreference)
+	v1.POST("/track-preferences/media", h.HandleSaveMediaTrackPreference)
+	v1.DELETE("/track-preferences/media/:id", h.HandleDeleteMediaTrackPreference)
+
 	//
 	// Media Stream
 	//
@@ -373,6 +447,15 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1.GET("/mediastream/direct", h.HandleMediastreamDirectPlay)
 	v1.HEAD("/mediastream/direct", h.HandleMediastreamDirectPlay)
 	v1.GET("/mediastream/file/*", h.HandleMediastreamFile)
+	// Trickplay
+	v1.POST("/mediastream/trickplay", h.HandleRequestMediastreamTrickplay)
+	v1.GET("/mediastream/trickplay/:hash/*", h.HandleMediastreamGetTrickplay)
+
+	//
+	// Skip Segments
+	//
+	v1.POST("/skip-segments", h.HandleGetSkipSegments)
+	v1.DELETE("/skip-segments", h.HandleDeleteSkipSegments)
 
 	//
 	// Torrent stream
@@ -385,6 +468,9 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1.POST("/torrentstream/drop", h.HandleTorrentstreamDropTorrent)
 	v1.POST("/torrentstream/torrent-file-previews", h.HandleGetTorrentstreamTorrentFilePreviews)
 	v1.POST("/torrentstream/batch-history", h.HandleGetTorrentstreamBatchHistory)
+	v1.GET("/torrentstream/sessions", h.HandleGetTorrentstreamSessions)
+	v1.POST("/torrentstream/import", h.HandleTorrentstreamKeepAndImport)
+	v1.GET("/torrentstream/imports", h.HandleGetTorrentstreamImportJobs)
 	v1.GET("/torrentstream/stream/*", echo.WrapHandler(h.HandleTorrentstreamServeStream()))
 
 	//
@@ -444,6 +530,9 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1.POST("/debrid/torrents/file-previews", h.HandleDebridGetTorrentFilePreviews)
 	v1.POST("/debrid/stream/start", h.HandleDebridStartStream)
 	v1.POST("/debrid/stream/cancel", h.HandleDebridCancelStream)
+	v1.POST("/debrid/cloud/refresh", h.HandleDebridRefreshCloudLibrary)
+	v1.POST("/debrid/cloud/stream", h.HandleDebridStartCloudStream)
+	v1.GET("/debrid/cloud/:id", h.HandleDebridGetCloudFiles)
 
 	//
 	// Report
@@ -465,6 +554,13 @@ func (h *Handler) RespondWithError(c echo.Context, err error) error {
 	return c.JSON(500, NewErrorResponse(err))
 }
 
+// getClientId returns the ID of the client making the request.
+// The ID is set by the client ID middleware.
+func (h *Handler) getClientId(c echo.Context) string {
+	id, _ := c.Get("Seanime-Client-Id").(string)
+	return id
+}
+
 func headMethodMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		if c.Request().Method == http.MethodHead {
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"github.com/labstack/echo/v4"
 	"seanime/internal/database/db_bridge"
+	"seanime/internal/library/anime"
 	"seanime/internal/library/scanner"
 	"seanime/internal/library/summary"
 )
@@ -13,6 +15,8 @@ import (
 //	@summary scans the user's library.
 //	@desc This will scan the user's library.
 //	@desc The response is ignored, the client should re-fetch the library after this.
+//	@desc If the scan is cancelled, an error is returned and the local files are left untouched.
+//	@desc If another scan is running, e.g. from the auto scanner, the scan starts once it has finished.
 //	@route /api/v1/library/scan [POST]
 //	@returns []anime.LocalFile
 func (h *Handler) HandleScanLocalFiles(c echo.Context) error {
@@ -24,25 +28,136 @@ func (h *Handler) HandleScanLocalFiles(c echo.Context) error {
 	}
 
 	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
 
-	// Retrieve the user's library path
-	libraryPath, err := h.App.Database.GetLibraryPathFromSettings()
+	// Wait for the other scans to save their local files, they would be overwritten otherwise
+	unlock := h.App.ScanCanceller.Lock()
+	defer unlock()
+
+	existingLfs, allLfs, scanSummaryLogger, err := h.scanLocalFiles(b.Enhanced, b.SkipLockedFiles, b.SkipIgnoredFiles)
+	if err != nil {
+		if errors.Is(err, scanner.ErrNoLocalFiles) {
+			return h.RespondWithData(c, []interface{}{})
+		} else {
+			return h.RespondWithError(c, err)
+		}
+	}
+
+	// Insert the local files
+	lfs, err := db_bridge.InsertLocalFiles(h.App.Database, allLfs)
 	if err != nil {
 		return h.RespondWithError(c, err)
 	}
-	additionalLibraryPaths, err := h.App.Database.GetAdditionalLibraryPathsFromSettings()
+
+	// Save the scan summary, all the changes are accepted
+	sm := scanSummaryLogger.GenerateSummary()
+	if sm != nil {
+		sm.Diff = summary.NewScanDiff(existingLfs, allLfs)
+		sm.Diff.Status = summary.ScanDiffStatusApplied
+		sm.Diff.AcceptAll()
+	}
+	err = db_bridge.InsertScanSummary(h.App.Database, sm)
+
+	go h.App.AutoDownloader.CleanUpDownloadedItems()
+
+	go h.App.OnLibraryScanned(existingLfs, lfs)
+
+	return h.RespondWithData(c, lfs)
+
+}
+
+// HandleDryRunScanLocalFiles
+//
+//	@summary scans the user's library without saving the local files.
+//	@desc The changes the scan would make are returned in the diff of the scan summary.
+//	@desc They are saved once approved with HandleApplyScanDiff.
+//	@desc If no local files are found, a summary without changes is returned and not saved.
+//	@route /api/v1/library/scan/dry-run [POST]
+//	@returns summary.ScanSummary
+func (h *Handler) HandleDryRunScanLocalFiles(c echo.Context) error {
+
+	type body struct {
+		Enhanced         bool `json:"enhanced"`
+		SkipLockedFiles  bool `json:"skipLockedFiles"`
+		SkipIgnoredFiles bool `json:"skipIgnoredFiles"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	existingLfs, allLfs, scanSummaryLogger, err := h.scanLocalFiles(b.Enhanced, b.SkipLockedFiles, b.SkipIgnoredFiles)
 	if err != nil {
+		if errors.Is(err, scanner.ErrNoLocalFiles) {
+			// Like HandleScanLocalFiles, nothing would be saved
+			diff := &summary.ScanDiff{
+				Status:  summary.ScanDiffStatusDiscarded,
+				DryRun:  true,
+				Changes: make([]*summary.ScanDiffChange, 0),
+			}
+			return h.RespondWithData(c, scanSummaryLogger.GenerateSummaryWithDiff(diff))
+		}
 		return h.RespondWithError(c, err)
 	}
 
-	if err = c.Bind(&b); err != nil {
+	diff := summary.NewScanDiff(existingLfs, allLfs)
+	diff.DryRun = true
+	sm := scanSummaryLogger.GenerateSummaryWithDiff(diff)
+
+	if err := db_bridge.InsertScanSummary(h.App.Database, sm); err != nil {
 		return h.RespondWithError(c, err)
 	}
 
+	return h.RespondWithData(c, sm)
+}
+
+// HandleCancelScan
+//
+//	@summary cancels the running scans.
+//	@desc This cancels the scans started manually and by the auto scanner.
+//	@desc The results of cancelled scans are discarded, the local files are left untouched.
+//	@desc Returns false if no scan was running.
+//	@route /api/v1/library/scan/cancel [POST]
+//	@returns bool
+func (h *Handler) HandleCancelScan(c echo.Context) error {
+	return h.RespondWithData(c, h.App.ScanCanceller.Cancel())
+}
+
+// scanLocalFiles scans the library and returns the existing local files along with the scanned ones.
+func (h *Handler) scanLocalFiles(enhanced bool, skipLockedFiles bool, skipIgnoredFiles bool) ([]*anime.LocalFile, []*anime.LocalFile, *summary.ScanSummaryLogger, error) {
+
+	// Retrieve the user's library path
+	libraryPath, err := h.App.Database.GetLibraryPathFromSettings()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	additionalLibraryPaths, err := h.App.Database.GetAdditionalLibraryPathsFromSettings()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	// Get the latest local files
 	existingLfs, _, err := db_bridge.GetLocalFiles(h.App.Database)
 	if err != nil {
-		return h.RespondWithError(c, err)
+		return nil, nil, nil, err
+	}
+
+	matchingRules, err := h.App.Database.GetMatchingRules()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	hashLookup, err := scanner.NewHashLookupBackend(h.App.Settings.Library)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var ffprobePath string
+	if mediastreamSettings, found := h.App.Database.GetMediastreamSettings(); found {
+		ffprobePath = mediastreamSettings.FfprobePath
 	}
 
 	// +---------------------+
@@ -55,49 +170,50 @@ func (h *Handler) HandleScanLocalFiles(c echo.Context) error {
 	// Create a new scan logger
 	scanLogger, err := scanner.NewScanLogger(h.App.Config.Logs.Dir)
 	if err != nil {
-		return h.RespondWithError(c, err)
+		return nil, nil, nil, err
 	}
 	defer scanLogger.Done()
 
+	// Remove the oldest scan logs
+	if _, err := scanner.CleanUpScanLogs(h.App.Config.Logs.Dir, h.App.Settings.Library.ScannerLogRetention); err != nil {
+		h.App.Logger.Warn().Err(err).Msg("handlers: Failed to clean up scan logs")
+	}
+
 	// Create a new scanner
 	sc := scanner.Scanner{
 		DirPath:            libraryPath,
 		OtherDirPaths:      additionalLibraryPaths,
-		Enhanced:           b.Enhanced,
+		Roots:              h.App.Settings.Library.GetLibraryRoots(),
+		Enhanced:           enhanced,
 		Platform:           h.App.AnilistPlatform,
 		Logger:             h.App.Logger,
 		WSEventManager:     h.App.WSEventManager,
 		ExistingLocalFiles: existingLfs,
-		SkipLockedFiles:    b.SkipLockedFiles,
-		SkipIgnoredFiles:   b.SkipIgnoredFiles,
+		SkipLockedFiles:    skipLockedFiles,
+		SkipIgnoredFiles:   skipIgnoredFiles,
 		ScanSummaryLogger:  scanSummaryLogger,
 		ScanLogger:         scanLogger,
 		MetadataProvider:   h.App.MetadataProvider,
 		MatchingAlgorithm:  h.App.Settings.Library.ScannerMatchingAlgorithm,
 		MatchingThreshold:  h.App.Settings.Library.ScannerMatchingThreshold,
+		MatchingRules:      matchingRules,
+		HashFiles:          h.App.Settings.Library.ScannerHashFiles,
+		FileHashCache:      h.App.Database,
+		HashLookup:         hashLookup,
+		ProbeMediaInfo:     h.App.Settings.Library.ScannerProbeMediaInfo,
+		FfprobePath:        ffprobePath,
+		FileMediaInfoCache: h.App.Database,
 	}
 
-	// Scan the library
-	allLfs, err := sc.Scan()
-	if err != nil {
-		if errors.Is(err, scanner.ErrNoLocalFiles) {
-			return h.RespondWithData(c, []interface{}{})
-		} else {
-			return h.RespondWithError(c, err)
-		}
-	}
+	// The scan can be cancelled with HandleCancelScan
+	ctx, done := h.App.ScanCanceller.Start(context.Background())
+	defer done()
 
-	// Insert the local files
-	lfs, err := db_bridge.InsertLocalFiles(h.App.Database, allLfs)
+	// Scan the library
+	allLfs, err := sc.Scan(ctx)
 	if err != nil {
-		return h.RespondWithError(c, err)
+		return existingLfs, nil, scanSummaryLogger, err
 	}
 
-	// Save the scan summary
-	err = db_bridge.InsertScanSummary(h.App.Database, scanSummaryLogger.GenerateSummary())
-
-	go h.App.AutoDownloader.CleanUpDownloadedItems()
-
-	return h.RespondWithData(c, lfs)
-
+	return existingLfs, allLfs, scanSummaryLogger, nil
 }
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"seanime/internal/library/scanner"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetScanLogs
+//
+//	@summary returns the scan logs.
+//	@desc The logs are sorted from newest to oldest.
+//	@route /api/v1/library/scan-logs [GET]
+//	@returns []scanner.ScanLogInfo
+func (h *Handler) HandleGetScanLogs(c echo.Context) error {
+	if h.App.Config == nil || h.App.Config.Logs.Dir == "" {
+		return h.RespondWithData(c, []*scanner.ScanLogInfo{})
+	}
+
+	logs, err := scanner.ListScanLogs(h.App.Config.Logs.Dir)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, logs)
+}
+
+// HandleGetScanLogEntries
+//
+//	@summary returns the entries of a scan log.
+//	@desc The entries can be filtered by file path, media ID, stage and decision.
+//	@desc e.g. filtering by the path of a file returns the entries explaining why it was matched to its media.
+//	@route /api/v1/library/scan-logs/entries [POST]
+//	@returns []scanner.ScanLogEntry
+func (h *Handler) HandleGetScanLogEntries(c echo.Context) error {
+
+	type body struct {
+		Name   string                 `json:"name"`
+		Filter *scanner.ScanLogFilter `json:"filter"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if h.App.Config == nil || h.App.Config.Logs.Dir == "" {
+		return h.RespondWithData(c, []*scanner.ScanLogEntry{})
+	}
+
+	entries, err := scanner.ReadScanLog(h.App.Config.Logs.Dir, b.Name, b.Filter)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, entries)
+}
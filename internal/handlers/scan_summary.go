@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"seanime/internal/database/db_bridge"
+	"seanime/internal/library/summary"
 
 	"github.com/labstack/echo/v4"
 )
@@ -20,3 +22,96 @@ func (h *Handler) HandleGetScanSummaries(c echo.Context) error {
 
 	return h.RespondWithData(c, sm)
 }
+
+// HandleApplyScanDiff
+//
+//	@summary saves the approved changes of a dry run scan.
+//	@desc If 'acceptAll' is true, all the changes are saved, otherwise only the ones listed in 'changeIds'.
+//	@desc The changes are applied to the current local files, edits made since the dry run are kept.
+//	@route /api/v1/library/scan-diff/apply [POST]
+//	@returns []anime.LocalFile
+func (h *Handler) HandleApplyScanDiff(c echo.Context) error {
+
+	type body struct {
+		SummaryId string   `json:"summaryId"`
+		ChangeIds []string `json:"changeIds"`
+		AcceptAll bool     `json:"acceptAll"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	entryId, sm, err := db_bridge.GetScanSummary(h.App.Database, b.SummaryId)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+	if sm.Diff == nil || sm.Diff.Status != summary.ScanDiffStatusPending {
+		return h.RespondWithError(c, errors.New("scan has no pending changes"))
+	}
+
+	// A scan saving its local files meanwhile would overwrite the applied changes
+	unlock := h.App.ScanCanceller.Lock()
+	defer unlock()
+
+	existingLfs, _, err := db_bridge.GetLocalFiles(h.App.Database)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if b.AcceptAll {
+		sm.Diff.AcceptAll()
+	} else {
+		sm.Diff.Accept(b.ChangeIds)
+	}
+
+	// Insert the local files
+	lfs, err := db_bridge.InsertLocalFiles(h.App.Database, sm.Diff.Apply(existingLfs))
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	sm.Diff.Status = summary.ScanDiffStatusApplied
+	if err := db_bridge.UpdateScanSummary(h.App.Database, entryId, sm); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	go h.App.AutoDownloader.CleanUpDownloadedItems()
+
+	go h.App.OnLibraryScanned(existingLfs, lfs)
+
+	return h.RespondWithData(c, lfs)
+}
+
+// HandleDiscardScanDiff
+//
+//	@summary discards the changes of a dry run scan.
+//	@route /api/v1/library/scan-diff/discard [POST]
+//	@returns bool
+func (h *Handler) HandleDiscardScanDiff(c echo.Context) error {
+
+	type body struct {
+		SummaryId string `json:"summaryId"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	entryId, sm, err := db_bridge.GetScanSummary(h.App.Database, b.SummaryId)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+	if sm.Diff == nil || sm.Diff.Status != summary.ScanDiffStatusPending {
+		return h.RespondWithError(c, errors.New("scan has no pending changes"))
+	}
+
+	sm.Diff.Status = summary.ScanDiffStatusDiscarded
+	if err := db_bridge.UpdateScanSummary(h.App.Database, entryId, sm); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
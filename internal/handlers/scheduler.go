@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetScheduledJobs
+//
+//	@summary returns the scheduled jobs.
+//	@desc The client is notified when a job starts, finishes or is updated through the 'scheduled-jobs-updated' websocket event.
+//	@returns []scheduler.JobInfo
+//	@route /api/v1/scheduler/jobs [GET]
+func (h *Handler) HandleGetScheduledJobs(c echo.Context) error {
+	return h.RespondWithData(c, h.App.Scheduler.GetJobs())
+}
+
+// HandleUpdateScheduledJob
+//
+//	@summary updates the schedule of a job and enables or disables it.
+//	@desc 'schedule' is a 5-field cron expression (e.g. "0 4 * * *"), a descriptor (e.g. "@daily") or an interval (e.g. "@every 30m").
+//	@desc An empty schedule resets the job to its default schedule.
+//	@returns scheduler.JobInfo
+//	@route /api/v1/scheduler/job [PATCH]
+func (h *Handler) HandleUpdateScheduledJob(c echo.Context) error {
+
+	type body struct {
+		Name     string `json:"name"`
+		Schedule string `json:"schedule"`
+		Enabled  bool   `json:"enabled"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	info, err := h.App.Scheduler.UpdateJob(b.Name, b.Schedule, b.Enabled)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, info)
+}
+
+// HandleRunScheduledJob
+//
+//	@summary runs a job immediately.
+//	@desc The job runs in the background, even if it's disabled.
+//	@desc Returns an error if the job is already running.
+//	@returns bool
+//	@route /api/v1/scheduler/job/run [POST]
+func (h *Handler) HandleRunScheduledJob(c echo.Context) error {
+
+	type body struct {
+		Name string `json:"name"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := h.App.Scheduler.RunNow(b.Name); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleGetScheduledJobHistory
+//
+//	@summary returns the most recent runs of a job.
+//	@desc The runs are sorted from newest to oldest.
+//	@returns []models.ScheduledJobRun
+//	@param name - string - true - "The name of the job"
+//	@route /api/v1/scheduler/job/{name}/history [GET]
+func (h *Handler) HandleGetScheduledJobHistory(c echo.Context) error {
+	runs, err := h.App.Scheduler.GetRunHistory(c.Param("name"))
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, runs)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"path/filepath"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/debrid/debrid"
+	"seanime/internal/events"
+	"seanime/internal/torrents/seadex"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetSeaDexLibraryAnalysis
+//
+//	@summary returns the SeaDex release analysis of the library.
+//	@desc This compares the local files of each media with SeaDex's best and alternative releases.
+//	@desc Media that have no SeaDex entry are listed separately.
+//	@route /api/v1/seadex/library-analysis [POST]
+//	@returns seadex.LibraryAnalysis
+func (h *Handler) HandleGetSeaDexLibraryAnalysis(c echo.Context) error {
+
+	type body struct {
+		PreferDualAudio bool `json:"preferDualAudio"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	lfs, _, err := db_bridge.GetLocalFiles(h.App.Database)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	animeCollection, err := h.App.GetAnimeCollection(false)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	analysis, err := seadex.New(h.App.Logger).AnalyzeLibrary(&seadex.AnalyzeLibraryOptions{
+		LocalFiles:      lfs,
+		AnimeCollection: animeCollection,
+		PreferDualAudio: b.PreferDualAudio,
+	})
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, analysis)
+}
+
+// HandleSeaDexEnqueueReleases
+//
+//	@summary adds recommended SeaDex releases to the torrent client or the debrid service.
+//	@desc 'target' is either "torrent-client" or "debrid".
+//	@desc If no destination is provided, the releases are downloaded to the library directory with the highest priority that is enabled, writable and online.
+//	@desc An error is returned if the destination is in a library directory that is disabled, read-only or offline.
+//	@route /api/v1/seadex/library-analysis/enqueue [POST]
+//	@returns bool
+func (h *Handler) HandleSeaDexEnqueueReleases(c echo.Context) error {
+
+	type body struct {
+		Items       []*seadex.LibraryUpgrade `json:"items"`
+		Target      string                   `json:"target"`
+		Destination string                   `json:"destination"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if len(b.Items) == 0 {
+		return h.RespondWithError(c, errors.New("no releases to enqueue"))
+	}
+
+	if b.Destination != "" && !filepath.IsAbs(b.Destination) {
+		return h.RespondWithError(c, errors.New("destination path must be absolute"))
+	}
+
+	destination, err := db_bridge.ResolveDownloadDestination(h.App.Database, b.Destination)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+	b.Destination = destination
+
+	switch b.Target {
+	case "torrent-client":
+		// try to start torrent client if it's not running
+		ok := h.App.TorrentClientRepository.Start()
+		if !ok {
+			return h.RespondWithError(c, errors.New("could not contact torrent client, verify your settings or make sure it's running"))
+		}
+
+		magnets := make([]string, 0, len(b.Items))
+		for _, item := range b.Items {
+			if item == nil || item.Torrent == nil || item.Torrent.InfoHash == "" {
+				continue
+			}
+			magnets = append(magnets, item.Torrent.GetMagnetLink())
+		}
+
+		err := h.App.TorrentClientRepository.AddMagnets(magnets, b.Destination)
+		if err != nil {
+			return h.RespondWithError(c, err)
+		}
+
+	case "debrid":
+		if !h.App.DebridClientRepository.HasProvider() {
+			return h.RespondWithError(c, errors.New("debrid provider not set"))
+		}
+
+		for _, item := range b.Items {
+			if item == nil || item.Torrent == nil || item.Torrent.InfoHash == "" {
+				continue
+			}
+
+			_, err := h.App.DebridClientRepository.AddAndQueueTorrent(debrid.AddTorrentOptions{
+				MagnetLink:   item.Torrent.GetMagnetLink(),
+				InfoHash:     item.Torrent.InfoHash,
+				SelectFileId: "all",
+			}, b.Destination, item.MediaId)
+			if err != nil {
+				// If there is only one release, return the error
+				if len(b.Items) == 1 {
+					return h.RespondWithError(c, err)
+				}
+				// Otherwise, send an error toast and continue to the next release
+				h.App.Logger.Err(err).Msg("seadex: Failed to add release to debrid")
+				h.App.WSEventManager.SendEvent(events.ErrorToast, err.Error())
+				continue
+			}
+		}
+
+	default:
+		return h.RespondWithError(c, errors.New("invalid target"))
+	}
+
+	return h.RespondWithData(c, true)
+}
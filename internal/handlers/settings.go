@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"seanime/internal/cron"
 	"seanime/internal/database/models"
 	"seanime/internal/torrents/torrent"
 	"seanime/internal/util"
@@ -173,17 +174,37 @@ func (h *Handler) HandleSaveSettings(c echo.Context) error {
 		b.Library.LibraryPaths[i] = filepath.ToSlash(filepath.Clean(path))
 	}
 
+	prevSettings, err := h.App.Database.GetSettings()
+	if err != nil {
+		prevSettings = nil
+	}
+
 	b.Library.LibraryPaths = lo.Filter(b.Library.LibraryPaths, func(s string, _ int) bool {
 		if s == "" || util.IsSameDir(s, b.Library.LibraryPath) {
 			return false
 		}
 		info, err := os.Stat(s)
 		if err != nil {
-			return false
+			// Keep the directories that were already added, their drive might be offline
+			return prevSettings != nil && prevSettings.Library != nil && lo.ContainsBy(prevSettings.Library.LibraryPaths, func(p string) bool {
+				return util.IsSameDir(p, s)
+			})
 		}
 		return info.IsDir()
 	})
 
+	// Only keep the settings of the library directories
+	allLibraryPaths := b.Library.GetLibraryPaths()
+	b.Library.LibraryRoots = lo.Filter(b.Library.LibraryRoots, func(root *models.LibraryRoot, _ int) bool {
+		if root == nil || root.Path == "" {
+			return false
+		}
+		root.Path = filepath.ToSlash(filepath.Clean(root.Path))
+		return lo.ContainsBy(allLibraryPaths, func(p string) bool {
+			return p != "" && util.IsSameDir(p, root.Path)
+		})
+	})
+
 	// Check that any library paths are not subdirectories of each other
 	for i, path1 := range b.Library.LibraryPaths {
 		if util.IsSubdirectory(b.Library.LibraryPath, path1) || util.IsSubdirectory(path1, b.Library.LibraryPath) {
@@ -197,8 +218,7 @@ func (h *Handler) HandleSaveSettings(c echo.Context) error {
 	}
 
 	autoDownloaderSettings := models.AutoDownloaderSettings{}
-	prevSettings, err := h.App.Database.GetSettings()
-	if err == nil && prevSettings.AutoDownloader != nil {
+	if prevSettings != nil && prevSettings.AutoDownloader != nil {
 		autoDownloaderSettings = *prevSettings.AutoDownloader
 	}
 	// Disable auto-downloader if the torrent provider is set to none
@@ -278,6 +298,13 @@ func (h *Handler) HandleSaveAutoDownloaderSettings(c echo.Context) error {
 		UseDebrid:             b.UseDebrid,
 	}
 
+	// The Auto Downloader interval is the default schedule of its scheduled job
+	if prevInterval := cron.AutoDownloaderInterval(currSettings.AutoDownloader); prevInterval != b.Interval {
+		if err := cron.UpdateAutoDownloaderSchedule(h.App.Scheduler, prevInterval, b.Interval); err != nil {
+			h.App.Logger.Warn().Err(err).Msg("settings: Failed to update the auto downloader schedule")
+		}
+	}
+
 	currSettings.AutoDownloader = autoDownloaderSettings
 	currSettings.BaseModel = models.BaseModel{
 		ID:        1,
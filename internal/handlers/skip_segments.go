@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"seanime/internal/mediastream/skipsegments"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetSkipSegments
+//
+//	@summary returns the skip segments of a local file.
+//	@desc The intro and outro are read from the chapters of the file when possible.
+//	@desc Otherwise, they are detected in the background by comparing the audio with another episode of the series.
+//	@desc It returns null while detection is in progress, the markers are then sent through the "skip-segments-updated" websocket event.
+//	@returns skipsegments.Markers
+//	@route /api/v1/skip-segments [POST]
+func (h *Handler) HandleGetSkipSegments(c echo.Context) error {
+
+	type body struct {
+		Path string `json:"path"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if b.Path == "" {
+		return h.RespondWithError(c, errors.New("path is required"))
+	}
+
+	markers, err := h.App.SkipSegmentsManager.RequestMarkers(b.Path)
+	if err != nil {
+		if errors.Is(err, skipsegments.ErrDetectionInProgress) {
+			return h.RespondWithData(c, nil)
+		}
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, markers)
+}
+
+// HandleDeleteSkipSegments
+//
+//	@summary deletes the skip segments of a local file.
+//	@desc The skip segments will be derived again the next time they are requested.
+//	@returns bool
+//	@route /api/v1/skip-segments [DELETE]
+func (h *Handler) HandleDeleteSkipSegments(c echo.Context) error {
+
+	type body struct {
+		Path string `json:"path"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := h.App.SkipSegmentsManager.DeleteMarkers(b.Path); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
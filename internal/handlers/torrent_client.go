@@ -120,15 +120,10 @@ func (h *Handler) HandleTorrentClientDownload(c echo.Context) error {
 		return h.RespondWithError(c, errors.New("destination path must be absolute"))
 	}
 
-	// Check that the destination path is a library path
-	//libraryPaths, err := h.App.Database.GetAllLibraryPathsFromSettings()
-	//if err != nil {
-	//	return h.RespondWithError(c, err)
-	//}
-	//isInLibrary := util.IsSubdirectoryOfAny(libraryPaths, b.Destination)
-	//if !isInLibrary {
-	//	return h.RespondWithError(c, errors.New("destination path is not a library path"))
-	//}
+	// Torrents are not downloaded to library directories that are disabled, read-only or offline
+	if _, err := db_bridge.ResolveDownloadDestination(h.App.Database, b.Destination); err != nil {
+		return h.RespondWithError(c, err)
+	}
 
 	// try to start torrent client if it's not running
 	ok := h.App.TorrentClientRepository.Start()
@@ -134,6 +134,7 @@ func (h *Handler) HandleGetTorrentstreamTorrentFilePreviews(c echo.Context) erro
 //
 //	@summary starts a torrent stream.
 //	@desc This starts the entire streaming process.
+//	@desc Each client has its own stream, starting a stream only replaces the stream of the same client.
 //	@returns bool
 //	@route /api/v1/torrentstream/start [POST]
 func (h *Handler) HandleTorrentstreamStartStream(c echo.Context) error {
@@ -156,6 +157,10 @@ func (h *Handler) HandleTorrentstreamStartStream(c echo.Context) error {
 
 	userAgent := c.Request().Header.Get("User-Agent")
 
+	if b.ClientId == "" {
+		b.ClientId = h.getClientId(c)
+	}
+
 	err := h.App.TorrentstreamRepository.StartStream(&torrentstream.StartStreamOptions{
 		MediaId:       b.MediaId,
 		EpisodeNumber: b.EpisodeNumber,
@@ -177,13 +182,13 @@ func (h *Handler) HandleTorrentstreamStartStream(c echo.Context) error {
 // HandleTorrentstreamStopStream
 //
 //	@summary stop a torrent stream.
-//	@desc This stops the entire streaming process and drops the torrent if it's below a threshold.
+//	@desc This stops the streaming process of the client and drops the torrent if it's below a threshold.
 //	@desc This is made to be used while the stream is running.
 //	@returns bool
 //	@route /api/v1/torrentstream/stop [POST]
 func (h *Handler) HandleTorrentstreamStopStream(c echo.Context) error {
 
-	err := h.App.TorrentstreamRepository.StopStream()
+	err := h.App.TorrentstreamRepository.StopStream(h.getClientId(c))
 	if err != nil {
 		return h.RespondWithError(c, err)
 	}
@@ -194,13 +199,14 @@ func (h *Handler) HandleTorrentstreamStopStream(c echo.Context) error {
 // HandleTorrentstreamDropTorrent
 //
 //	@summary drops a torrent stream.
-//	@desc This stops the entire streaming process and drops the torrent completely.
+//	@desc This stops the streaming process of the client and drops the torrent completely.
+//	@desc The torrent is kept if another client is streaming from it.
 //	@desc This is made to be used to force drop a torrent.
 //	@returns bool
 //	@route /api/v1/torrentstream/drop [POST]
 func (h *Handler) HandleTorrentstreamDropTorrent(c echo.Context) error {
 
-	err := h.App.TorrentstreamRepository.DropTorrent()
+	err := h.App.TorrentstreamRepository.DropTorrent(h.getClientId(c))
 	if err != nil {
 		return h.RespondWithError(c, err)
 	}
@@ -227,6 +233,42 @@ func (h *Handler) HandleGetTorrentstreamBatchHistory(c echo.Context) error {
 	return h.RespondWithData(c, ret)
 }
 
+// HandleGetTorrentstreamSessions
+//
+//	@summary returns the active torrent streams.
+//	@desc This returns a snapshot of the streams of all clients.
+//	@returns []torrentstream.SessionInfo
+//	@route /api/v1/torrentstream/sessions [GET]
+func (h *Handler) HandleGetTorrentstreamSessions(c echo.Context) error {
+	return h.RespondWithData(c, h.App.TorrentstreamRepository.GetSessions())
+}
+
+// HandleTorrentstreamKeepAndImport
+//
+//	@summary keeps the torrent of the client's stream and imports it into the library.
+//	@desc The torrent is downloaded in the background after the stream ends, its files are added to the library and scanned.
+//	@desc The torrent is seeded until the seed ratio or time limit is reached.
+//	@returns bool
+//	@route /api/v1/torrentstream/import [POST]
+func (h *Handler) HandleTorrentstreamKeepAndImport(c echo.Context) error {
+
+	err := h.App.TorrentstreamRepository.KeepAndImport(h.getClientId(c))
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleGetTorrentstreamImportJobs
+//
+//	@summary returns the torrents being imported into the library.
+//	@returns []torrentstream.ImportJobInfo
+//	@route /api/v1/torrentstream/imports [GET]
+func (h *Handler) HandleGetTorrentstreamImportJobs(c echo.Context) error {
+	return h.RespondWithData(c, h.App.TorrentstreamRepository.GetImportJobs())
+}
+
 // route /api/v1/torrentstream/stream/*
 func (h *Handler) HandleTorrentstreamServeStream() http.Handler {
 	return h.App.TorrentstreamRepository.HTTPStreamHandler()
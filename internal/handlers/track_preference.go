@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"seanime/internal/database/models"
+	"seanime/internal/trackpreference"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetTrackPreferenceSettings
+//
+//	@summary get the track preferences.
+//	@desc This returns the preferred audio and subtitle languages used by the media players, media streaming and online streaming.
+//	@returns models.TrackPreferenceSettings
+//	@route /api/v1/track-preferences [GET]
+func (h *Handler) HandleGetTrackPreferenceSettings(c echo.Context) error {
+	return h.RespondWithData(c, h.App.TrackPreferenceRepository.GetSettings())
+}
+
+// HandleSaveTrackPreferenceSettings
+//
+//	@summary save the track preferences.
+//	@desc This saves the preferred audio and subtitle languages.
+//	@desc Languages can be ISO 639-1 or ISO 639-2 codes and are ordered by priority.
+//	@returns models.TrackPreferenceSettings
+//	@route /api/v1/track-preferences [PATCH]
+func (h *Handler) HandleSaveTrackPreferenceSettings(c echo.Context) error {
+	type body struct {
+		Settings models.TrackPreferenceSettings `json:"settings"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if b.Settings.SignsAndSongs == "" {
+		b.Settings.SignsAndSongs = string(trackpreference.SignsAndSongsAuto)
+	}
+	if err := validateSignsAndSongsMode(b.Settings.SignsAndSongs); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	b.Settings.BaseModel = models.BaseModel{
+		ID: 1,
+	}
+	b.Settings.AudioLanguages = trackpreference.NormalizeLanguages(b.Settings.AudioLanguages)
+	b.Settings.SubtitleLanguages = trackpreference.NormalizeLanguages(b.Settings.SubtitleLanguages)
+
+	settings, err := h.App.Database.UpsertTrackPreferenceSettings(&b.Settings)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, settings)
+}
+
+// HandleGetMediaTrackPreference
+//
+//	@summary get the track preferences of a media.
+//	@desc This returns the overrides of the track preferences for the media.
+//	@desc It returns null if the media has no overrides.
+//	@returns models.MediaTrackPreference
+//	@param id - int - true - "AniList anime media ID"
+//	@route /api/v1/track-preferences/media/{id} [GET]
+func (h *Handler) HandleGetMediaTrackPreference(c echo.Context) error {
+	mId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	pref, found := h.App.Database.GetMediaTrackPreference(mId)
+	if !found {
+		return h.RespondWithData(c, nil)
+	}
+
+	return h.RespondWithData(c, pref)
+}
+
+// HandleSaveMediaTrackPreference
+//
+//	@summary save the track preferences of a media.
+//	@desc This overrides the track preferences for the media.
+//	@desc Empty fields fall back to the global track preferences.
+//	@returns models.MediaTrackPreference
+//	@route /api/v1/track-preferences/media [POST]
+func (h *Handler) HandleSaveMediaTrackPreference(c echo.Context) error {
+	type body struct {
+		MediaId           int      `json:"mediaId"`
+		AudioLanguages    []string `json:"audioLanguages"`
+		SubtitleLanguages []string `json:"subtitleLanguages"`
+		SignsAndSongs     string   `json:"signsAndSongs"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if b.MediaId == 0 {
+		return h.RespondWithError(c, errors.New("media id is required"))
+	}
+
+	if b.SignsAndSongs != "" {
+		if err := validateSignsAndSongsMode(b.SignsAndSongs); err != nil {
+			return h.RespondWithError(c, err)
+		}
+	}
+
+	pref, err := h.App.Database.UpsertMediaTrackPreference(&models.MediaTrackPreference{
+		MediaId:           b.MediaId,
+		AudioLanguages:    trackpreference.NormalizeLanguages(b.AudioLanguages),
+		SubtitleLanguages: trackpreference.NormalizeLanguages(b.SubtitleLanguages),
+		SignsAndSongs:     b.SignsAndSongs,
+	})
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, pref)
+}
+
+// HandleDeleteMediaTrackPreference
+//
+//	@summary delete the track preferences of a media.
+//	@desc This removes the overrides, the global track preferences will be used for the media.
+//	@returns bool
+//	@param id - int - true - "AniList anime media ID"
+//	@route /api/v1/track-preferences/media/{id} [DELETE]
+func (h *Handler) HandleDeleteMediaTrackPreference(c echo.Context) error {
+	mId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	err = h.App.Database.DeleteMediaTrackPreference(mId)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+func validateSignsAndSongsMode(mode string) error {
+	switch trackpreference.SignsAndSongsMode(mode) {
+	case trackpreference.SignsAndSongsAuto, trackpreference.SignsAndSongsPrefer, trackpreference.SignsAndSongsNever:
+		return nil
+	}
+	return errors.New("invalid signs and songs mode")
+}
@@ -31,5 +31,6 @@ type (
 		EpisodeNumbers      []int                                 `json:"episodeNumbers,omitempty"`
 		Destination         string                                `json:"destination"`
 		AdditionalTerms     []string                              `json:"additionalTerms"`
+		DestinationRoot     string                                `json:"destinationRoot,omitempty"` // Library directory the destination is resolved against, optional
 	}
 )
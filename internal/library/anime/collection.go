@@ -64,6 +64,7 @@ type (
 		MediaId          int                `json:"mediaId"`
 		EntryLibraryData *EntryLibraryData  `json:"libraryData"` // Library data
 		EntryListData    *EntryListData     `json:"listData"`    // AniList list data
+		InCloud          bool               `json:"inCloud"`     // Whether episodes are available from the debrid service
 	}
 
 	// UnmatchedGroup holds the data for a group of unmatched local files.
@@ -117,11 +118,11 @@ func NewLibraryCollection(opts *NewLibraryCollectionOptions) (lc *LibraryCollect
 	)
 
 	lc.UnmatchedLocalFiles = lo.Filter(opts.LocalFiles, func(lf *LocalFile, index int) bool {
-		return lf.MediaId == 0 && !lf.Ignored
+		return lf.MediaId == 0 && !lf.IsIgnored()
 	})
 
 	lc.IgnoredLocalFiles = lo.Filter(opts.LocalFiles, func(lf *LocalFile, index int) bool {
-		return lf.Ignored == true
+		return lf.IsIgnored()
 	})
 
 	slices.SortStableFunc(lc.IgnoredLocalFiles, func(i, j *LocalFile) int {
@@ -425,6 +426,71 @@ func (lc *LibraryCollection) hydrateUnmatchedGroups() {
 
 //----------------------------------------------------------------------------------------------------------------------
 
+// AddCloudEntries flags the entries of the given media as available in the cloud (debrid service).
+// Media that have no local files are added to the list matching their AniList status.
+// Media that are not in the user's AniList collection are ignored.
+func (lc *LibraryCollection) AddCloudEntries(animeCollection *anilist.AnimeCollection, mediaIds []int) {
+	if lc == nil || animeCollection == nil {
+		return
+	}
+
+	added := make(map[int]struct{})
+	for _, list := range lc.Lists {
+		for _, entry := range list.Entries {
+			if slices.Contains(mediaIds, entry.MediaId) {
+				entry.InCloud = true
+				added[entry.MediaId] = struct{}{}
+			}
+		}
+	}
+
+	for _, mId := range mediaIds {
+		if _, found := added[mId]; found {
+			continue
+		}
+		added[mId] = struct{}{}
+
+		entry, found := animeCollection.GetListEntryFromAnimeId(mId)
+		if !found || entry.Status == nil {
+			continue
+		}
+
+		status := getLibraryCollectionEntryFromListStatus(*entry.Status)
+		list, found := lo.Find(lc.Lists, func(item *LibraryCollectionList) bool {
+			return item.Status == status
+		})
+		if !found {
+			list = &LibraryCollectionList{
+				Type:    status,
+				Status:  status,
+				Entries: make([]*LibraryCollectionEntry, 0),
+			}
+			lc.Lists = append(lc.Lists, list)
+		}
+
+		list.Entries = append(list.Entries, &LibraryCollectionEntry{
+			MediaId: mId,
+			Media:   entry.Media,
+			EntryListData: &EntryListData{
+				Progress:    entry.GetProgressSafe(),
+				Score:       entry.GetScoreSafe(),
+				Status:      entry.Status,
+				Repeat:      entry.GetRepeatSafe(),
+				StartedAt:   anilist.ToEntryStartDate(entry.StartedAt),
+				CompletedAt: anilist.ToEntryCompletionDate(entry.CompletedAt),
+			},
+			InCloud: true,
+		})
+
+		// Sort by title
+		sort.Slice(list.Entries, func(i, j int) bool {
+			return list.Entries[i].Media.GetTitleSafe() < list.Entries[j].Media.GetTitleSafe()
+		})
+	}
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
 // getLibraryCollectionEntryFromListStatus maps anilist.MediaListStatus to LibraryCollectionListType.
 func getLibraryCollectionEntryFromListStatus(st anilist.MediaListStatus) anilist.MediaListStatus {
 	if st == anilist.MediaListStatusRepeating {
@@ -20,6 +20,7 @@ type (
 		EntryLibraryData    *EntryLibraryData  `json:"libraryData"`
 		EntryDownloadInfo   *EntryDownloadInfo `json:"downloadInfo,omitempty"`
 		Episodes            []*Episode         `json:"episodes"`
+		EpisodeSections     []*EpisodeSection  `json:"episodeSections"` // Specials and NC episodes, grouped by AniDB episode type
 		NextEpisode         *Episode           `json:"nextEpisode"`
 		LocalFiles          []*LocalFile       `json:"localFiles"`
 		AnidbId             int                `json:"anidbId"`
@@ -57,6 +58,7 @@ type (
 //   - EntryLibraryData: Details of the local files (if any)
 //   - EntryDownloadInfo: Details of the download status
 //   - Episodes: List of episodes (if any)
+//   - EpisodeSections: Specials and NC episodes, in ordered sections (if any)
 //   - NextEpisode: Next episode to watch (if any)
 //   - LocalFiles: List of local files (if any)
 //   - AnidbId: AniDB id
@@ -143,6 +145,7 @@ func NewEntry(opts *NewEntryOptions) (*Entry, error) {
 			EntryLibraryData:    simpleAnimeEntry.EntryLibraryData,
 			EntryDownloadInfo:   nil,
 			Episodes:            simpleAnimeEntry.Episodes,
+			EpisodeSections:     simpleAnimeEntry.EpisodeSections,
 			NextEpisode:         simpleAnimeEntry.NextEpisode,
 			LocalFiles:          simpleAnimeEntry.LocalFiles,
 			AnidbId:             0,
@@ -239,6 +242,7 @@ func (e *Entry) hydrateEntryEpisodeData(
 		return episodes[i].EpisodeNumber < episodes[j].EpisodeNumber
 	})
 	e.Episodes = episodes
+	e.EpisodeSections = NewEpisodeSections(episodes)
 
 	// +---------------------+
 	// |    Download Info    |
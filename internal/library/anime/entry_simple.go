@@ -15,6 +15,7 @@ type (
 		EntryListData       *EntryListData     `json:"listData"`
 		EntryLibraryData    *EntryLibraryData  `json:"libraryData"`
 		Episodes            []*Episode         `json:"episodes"`
+		EpisodeSections     []*EpisodeSection  `json:"episodeSections"`
 		NextEpisode         *Episode           `json:"nextEpisode"`
 		LocalFiles          []*LocalFile       `json:"localFiles"`
 		CurrentEpisodeCount int                `json:"currentEpisodeCount"`
@@ -137,6 +138,7 @@ func (e *SimpleEntry) hydrateEntryEpisodeData() {
 		return episodes[i].EpisodeNumber < episodes[j].EpisodeNumber
 	})
 	e.Episodes = episodes
+	e.EpisodeSections = NewEpisodeSections(episodes)
 
 	nextEp, found := e.FindNextEpisode()
 	if found {
@@ -1,10 +1,10 @@
 package anime
 
 import (
+	"cmp"
 	"seanime/internal/api/anilist"
 	"seanime/internal/api/metadata"
 	"strconv"
-	"strings"
 )
 
 type (
@@ -189,12 +189,7 @@ func NewEpisode(opts *NewEpisodeOptions) *Episode {
 		if episodeMetadata, foundAnizipEpisode := opts.AnimeMetadata.FindEpisode(opts.OptionalAniDBEpisode); foundAnizipEpisode {
 
 			entryEp.IsDownloaded = false
-			entryEp.Type = LocalFileTypeMain
-			if strings.HasPrefix(opts.OptionalAniDBEpisode, "S") {
-				entryEp.Type = LocalFileTypeSpecial
-			} else if strings.HasPrefix(opts.OptionalAniDBEpisode, "OP") || strings.HasPrefix(opts.OptionalAniDBEpisode, "ED") {
-				entryEp.Type = LocalFileTypeNC
-			}
+			entryEp.Type = GetAniDBEpisodeType(opts.OptionalAniDBEpisode)
 			entryEp.EpisodeNumber = 0
 			entryEp.ProgressNumber = 0
 
@@ -216,7 +211,7 @@ func NewEpisode(opts *NewEpisodeOptions) *Episode {
 					entryEp.DisplayTitle = "Special " + strconv.Itoa(episodeInt)
 					entryEp.EpisodeTitle = episodeMetadata.GetTitle()
 				case LocalFileTypeNC:
-					entryEp.DisplayTitle = opts.OptionalAniDBEpisode
+					entryEp.DisplayTitle = cmp.Or(episodeMetadata.GetTitle(), opts.OptionalAniDBEpisode)
 					entryEp.EpisodeTitle = ""
 				}
 				hydrated = true
@@ -273,7 +268,13 @@ func NewEpisodeMetadata(
 		md.Overview = epMetadata.Overview
 		md.IsFiller = false
 	} else {
-		md.Image = media.GetBannerImageSafe()
+		// Specials and NC episodes, e.g. "S1", "C1"
+		md.AnidbId = episode.AnidbId
+		md.Image = cmp.Or(episode.Image, media.GetBannerImageSafe())
+		md.AirDate = episode.AirDate
+		md.Length = episode.Length
+		md.Summary = episode.Summary
+		md.Overview = episode.Overview
 	}
 
 	return md
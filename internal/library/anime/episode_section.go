@@ -0,0 +1,125 @@
+package anime
+
+import (
+	"cmp"
+	"seanime/internal/api/metadata"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type (
+	// EpisodeSectionType is the kind of episodes listed in an EpisodeSection.
+	// It is the prefix of their AniDB episode, e.g. "S" for "S1".
+	EpisodeSectionType string
+
+	// EpisodeSection lists the special or NC episodes of an entry that share the same AniDB episode type.
+	EpisodeSection struct {
+		Type     EpisodeSectionType `json:"type"`
+		Title    string             `json:"title"`
+		Episodes []*Episode         `json:"episodes"`
+	}
+)
+
+const (
+	EpisodeSectionTypeSpecial EpisodeSectionType = "S" // OVA, ONA, etc.
+	EpisodeSectionTypeCredit  EpisodeSectionType = "C" // Creditless openings and endings
+	EpisodeSectionTypeTrailer EpisodeSectionType = "T" // Trailers, promos, commercials
+	EpisodeSectionTypeOther   EpisodeSectionType = "O"
+)
+
+// episodeSectionTypes is the order in which the sections are listed
+var episodeSectionTypes = []EpisodeSectionType{
+	EpisodeSectionTypeSpecial,
+	EpisodeSectionTypeCredit,
+	EpisodeSectionTypeTrailer,
+	EpisodeSectionTypeOther,
+}
+
+var episodeSectionTitles = map[EpisodeSectionType]string{
+	EpisodeSectionTypeSpecial: "Specials",
+	EpisodeSectionTypeCredit:  "Openings & Endings",
+	EpisodeSectionTypeTrailer: "Trailers",
+	EpisodeSectionTypeOther:   "Others",
+}
+
+// GetAniDBEpisodeType returns the type of the local file matching the AniDB episode.
+// e.g. "1" -> main, "S1" -> special, "C1", "T1", "O1" -> nc
+func GetAniDBEpisodeType(aniDBEpisode string) LocalFileType {
+	switch {
+	case strings.HasPrefix(aniDBEpisode, string(EpisodeSectionTypeSpecial)):
+		return LocalFileTypeSpecial
+	case strings.HasPrefix(aniDBEpisode, string(EpisodeSectionTypeCredit)),
+		strings.HasPrefix(aniDBEpisode, string(EpisodeSectionTypeTrailer)),
+		strings.HasPrefix(aniDBEpisode, string(EpisodeSectionTypeOther)),
+		// Episodes mapped before NC files had AniDB episodes
+		strings.HasPrefix(aniDBEpisode, "OP"),
+		strings.HasPrefix(aniDBEpisode, "ED"):
+		return LocalFileTypeNC
+	}
+	return LocalFileTypeMain
+}
+
+// NewEpisodeSections groups the special and NC episodes by the type of their AniDB episode.
+// Sections are ordered as specials, openings & endings, trailers and others, and their episodes by AniDB episode number.
+// Empty sections are omitted.
+func NewEpisodeSections(episodes []*Episode) []*EpisodeSection {
+	ret := make([]*EpisodeSection, 0)
+
+	grouped := make(map[EpisodeSectionType][]*Episode)
+	for _, ep := range episodes {
+		if ep == nil || ep.Type == LocalFileTypeMain {
+			continue
+		}
+		sectionType := getEpisodeSectionType(ep)
+		grouped[sectionType] = append(grouped[sectionType], ep)
+	}
+
+	for _, sectionType := range episodeSectionTypes {
+		sectionEpisodes, ok := grouped[sectionType]
+		if !ok {
+			continue
+		}
+		slices.SortStableFunc(sectionEpisodes, func(a, b *Episode) int {
+			na, _ := metadata.ExtractEpisodeInteger(getEpisodeAniDBEpisode(a))
+			nb, _ := metadata.ExtractEpisodeInteger(getEpisodeAniDBEpisode(b))
+			return cmp.Or(cmp.Compare(na, nb), strings.Compare(a.DisplayTitle, b.DisplayTitle))
+		})
+		ret = append(ret, &EpisodeSection{
+			Type:     sectionType,
+			Title:    episodeSectionTitles[sectionType],
+			Episodes: sectionEpisodes,
+		})
+	}
+
+	return ret
+}
+
+func getEpisodeSectionType(ep *Episode) EpisodeSectionType {
+	return getAniDBEpisodeSectionType(getEpisodeAniDBEpisode(ep), ep.Type)
+}
+
+// getAniDBEpisodeSectionType returns the section of a special or NC episode.
+// Episodes without a valid AniDB episode are listed in the specials or others.
+func getAniDBEpisodeSectionType(aniDBEp string, fileType LocalFileType) EpisodeSectionType {
+	for _, sectionType := range episodeSectionTypes {
+		if _, err := strconv.Atoi(strings.TrimPrefix(aniDBEp, string(sectionType))); err == nil && strings.HasPrefix(aniDBEp, string(sectionType)) {
+			return sectionType
+		}
+	}
+	if fileType == LocalFileTypeSpecial {
+		return EpisodeSectionTypeSpecial
+	}
+	return EpisodeSectionTypeOther
+}
+
+// getEpisodeAniDBEpisode returns the AniDB episode of the episode, or of its local file if it was not found in the AniDB metadata.
+func getEpisodeAniDBEpisode(ep *Episode) string {
+	if ep.AniDBEpisode != "" {
+		return ep.AniDBEpisode
+	}
+	if ep.FileMetadata != nil {
+		return ep.FileMetadata.AniDBEpisode
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package anime
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEpisodeSections(t *testing.T) {
+
+	lfs := MockHydratedLocalFiles([]MockHydratedLocalFileOptions{
+		{FilePath: "/mnt/anime/Show/Show - 01.mkv", LibraryPath: "/mnt/anime/", MediaId: 1, MetadataEpisode: 1, MetadataAniDbEpisode: "1", MetadataType: LocalFileTypeMain},
+		{FilePath: "/mnt/anime/Show/Show - PV 1.mkv", LibraryPath: "/mnt/anime/", MediaId: 1, MetadataEpisode: 1, MetadataAniDbEpisode: "T1", MetadataType: LocalFileTypeNC},
+		{FilePath: "/mnt/anime/Show/Show - NCED.mkv", LibraryPath: "/mnt/anime/", MediaId: 1, MetadataEpisode: 2, MetadataAniDbEpisode: "C2", MetadataType: LocalFileTypeNC},
+		{FilePath: "/mnt/anime/Show/Show - NCOP.mkv", LibraryPath: "/mnt/anime/", MediaId: 1, MetadataEpisode: 1, MetadataAniDbEpisode: "C1", MetadataType: LocalFileTypeNC},
+		{FilePath: "/mnt/anime/Show/Show - OVA 2.mkv", LibraryPath: "/mnt/anime/", MediaId: 1, MetadataEpisode: 2, MetadataAniDbEpisode: "S2", MetadataType: LocalFileTypeSpecial},
+		{FilePath: "/mnt/anime/Show/Show - OVA 1.mkv", LibraryPath: "/mnt/anime/", MediaId: 1, MetadataEpisode: 1, MetadataAniDbEpisode: "S1", MetadataType: LocalFileTypeSpecial},
+		// Hydrated before NC files were mapped to AniDB episodes
+		{FilePath: "/mnt/anime/Show/Show - Menu.mkv", LibraryPath: "/mnt/anime/", MediaId: 1, MetadataEpisode: 0, MetadataAniDbEpisode: "", MetadataType: LocalFileTypeNC},
+	})
+
+	episodes := lo.Map(lfs, func(lf *LocalFile, _ int) *Episode {
+		return &Episode{
+			Type:         lf.GetType(),
+			DisplayTitle: lf.Name,
+			LocalFile:    lf,
+			FileMetadata: lf.GetMetadata(),
+		}
+	})
+
+	sections := NewEpisodeSections(episodes)
+
+	require.Len(t, sections, 4)
+	getAniDBEpisodes := func(section *EpisodeSection) []string {
+		return lo.Map(section.Episodes, func(ep *Episode, _ int) string {
+			return ep.FileMetadata.AniDBEpisode
+		})
+	}
+	assert.Equal(t, EpisodeSectionTypeSpecial, sections[0].Type)
+	assert.Equal(t, "Specials", sections[0].Title)
+	assert.Equal(t, []string{"S1", "S2"}, getAniDBEpisodes(sections[0]))
+	assert.Equal(t, EpisodeSectionTypeCredit, sections[1].Type)
+	assert.Equal(t, []string{"C1", "C2"}, getAniDBEpisodes(sections[1]))
+	assert.Equal(t, EpisodeSectionTypeTrailer, sections[2].Type)
+	assert.Equal(t, []string{"T1"}, getAniDBEpisodes(sections[2]))
+	assert.Equal(t, EpisodeSectionTypeOther, sections[3].Type)
+	assert.Equal(t, []string{""}, getAniDBEpisodes(sections[3]))
+
+	// Playlists list the same files in the same order
+	entry, ok := NewLocalFileWrapper(lfs).GetLocalEntryById(1)
+	require.True(t, ok)
+	extras := lo.Map(entry.GetExtraLocalFiles(), func(lf *LocalFile, _ int) string {
+		return lf.GetAniDBEpisode()
+	})
+	assert.Equal(t, []string{"S1", "S2", "C1", "C2", "T1", ""}, extras)
+
+	assert.Equal(t, LocalFileTypeMain, GetAniDBEpisodeType("12"))
+	assert.Equal(t, LocalFileTypeSpecial, GetAniDBEpisodeType("S1"))
+	assert.Equal(t, LocalFileTypeNC, GetAniDBEpisodeType("C1"))
+	assert.Equal(t, LocalFileTypeNC, GetAniDBEpisodeType("T2"))
+}
@@ -8,7 +8,7 @@ import (
 const (
 	LocalFileTypeMain    LocalFileType = "main"    // Main episodes that are trackable
 	LocalFileTypeSpecial LocalFileType = "special" // OVA, ONA, etc.
-	LocalFileTypeNC      LocalFileType = "nc"      // Opening, ending, etc.
+	LocalFileTypeNC      LocalFileType = "nc"      // Opening, ending, trailer, etc.
 )
 
 type (
@@ -16,14 +16,18 @@ type (
 	// LocalFile represents a media file on the local filesystem.
 	// It is used to store information about and state of the file, such as its path, name, and parsed data.
 	LocalFile struct {
-		Path             string                 `json:"path"`
-		Name             string                 `json:"name"`
-		ParsedData       *LocalFileParsedData   `json:"parsedInfo"`
-		ParsedFolderData []*LocalFileParsedData `json:"parsedFolderInfo"`
-		Metadata         *LocalFileMetadata     `json:"metadata"`
-		Locked           bool                   `json:"locked"`
-		Ignored          bool                   `json:"ignored"` // Unused for now
-		MediaId          int                    `json:"mediaId"`
+		Path               string                 `json:"path"`
+		Name               string                 `json:"name"`
+		ParsedData         *LocalFileParsedData   `json:"parsedInfo"`
+		ParsedFolderData   []*LocalFileParsedData `json:"parsedFolderInfo"`
+		Metadata           *LocalFileMetadata     `json:"metadata"`
+		Locked             bool                   `json:"locked"`
+		Ignored            bool                   `json:"ignored"` // Set by the user, ignored files are not matched
+		MediaId            int                    `json:"mediaId"`
+		Hashes             *LocalFileHashes       `json:"hashes,omitempty"`             // Set when file hashing is enabled
+		MediaInfo          *LocalFileMediaInfo    `json:"mediaInfo,omitempty"`          // Set when media info probing is enabled
+		Offline            bool                   `json:"offline,omitempty"`            // Set when the library root of the file is offline, the file is kept until it is back
+		IgnoredBySeaignore bool                   `json:"ignoredBySeaignore,omitempty"` // Set when the file is excluded by a .seaignore file, cleared once no rule matches it
 	}
 
 	// LocalFileMetadata holds metadata related to a media episode.
@@ -33,6 +37,34 @@ type (
 		Type         LocalFileType `json:"type"`
 	}
 
+	// LocalFileHashes holds the hashes of a media file's content.
+	LocalFileHashes struct {
+		CRC32 string `json:"crc32"`
+		ED2K  string `json:"ed2k"`
+		Size  int64  `json:"size"`
+		// Whether the CRC32 written in the file name differs from the computed one
+		ChecksumMismatch bool `json:"checksumMismatch"`
+	}
+
+	// LocalFileMediaInfo holds the technical metadata of a media file, as reported by FFprobe.
+	LocalFileMediaInfo struct {
+		Duration   float64 `json:"duration"` // In seconds
+		Container  string  `json:"container"`
+		VideoCodec string  `json:"videoCodec"`
+		Width      int     `json:"width"`
+		Height     int     `json:"height"`
+		// Embedded tracks, external subtitle files are not included
+		AudioTracks    []*LocalFileMediaTrack `json:"audioTracks"`
+		SubtitleTracks []*LocalFileMediaTrack `json:"subtitleTracks"`
+	}
+
+	LocalFileMediaTrack struct {
+		Codec    string `json:"codec"`
+		Language string `json:"language,omitempty"` // BCP 47 tag, e.g. "en", empty if undefined
+		Title    string `json:"title,omitempty"`
+		Forced   bool   `json:"forced,omitempty"`
+	}
+
 	// LocalFileParsedData holds parsed data from a media file's name.
 	// This data is used to identify the media file during the scanning process.
 	LocalFileParsedData struct {
@@ -47,6 +79,7 @@ type (
 		EpisodeRange []string `json:"episodeRange,omitempty"`
 		EpisodeTitle string   `json:"episodeTitle,omitempty"`
 		Year         string   `json:"year,omitempty"`
+		Checksum     string   `json:"checksum,omitempty"` // CRC32 written in the file name
 	}
 )
 
@@ -99,6 +132,7 @@ func NewLocalFileParsedData(original string, elements *habari.Metadata) *LocalFi
 	i.ReleaseGroup = elements.ReleaseGroup
 	i.EpisodeTitle = elements.EpisodeTitle
 	i.Year = elements.Year
+	i.Checksum = elements.FileChecksum
 
 	if len(elements.SeasonNumber) > 0 {
 		if len(elements.SeasonNumber) == 1 {
@@ -0,0 +1,140 @@
+package anime
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// LocalFileFilter selects local files by their technical metadata.
+// Zero values are ignored, files that were not probed only match an empty filter.
+type LocalFileFilter struct {
+	MediaId    int    `json:"mediaId,omitempty"`
+	VideoCodec string `json:"videoCodec,omitempty"` // e.g. "hevc", "h264", "av1"
+	MinHeight  int    `json:"minHeight,omitempty"`
+	MaxHeight  int    `json:"maxHeight,omitempty"`
+	// Duration bounds in seconds
+	MinDuration float64 `json:"minDuration,omitempty"`
+	MaxDuration float64 `json:"maxDuration,omitempty"`
+	// Language of a track the file must have, e.g. "en" or "eng"
+	AudioLanguage    string `json:"audioLanguage,omitempty"`
+	SubtitleLanguage string `json:"subtitleLanguage,omitempty"`
+	// Language of a track the file must not have
+	MissingAudioLanguage    string `json:"missingAudioLanguage,omitempty"`
+	MissingSubtitleLanguage string `json:"missingSubtitleLanguage,omitempty"`
+}
+
+// Common names of video codecs, mapped to the codec names reported by FFprobe
+var videoCodecAliases = map[string]string{
+	"h265":  "hevc",
+	"x265":  "hevc",
+	"h.265": "hevc",
+	"h264":  "h264",
+	"x264":  "h264",
+	"h.264": "h264",
+	"avc":   "h264",
+}
+
+// NormalizeMediaLanguage returns the BCP 47 base of a language tag, e.g. "eng" -> "en".
+// It returns an empty string for undefined or invalid tags.
+func NormalizeMediaLanguage(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return ""
+	}
+	parsed, err := language.Parse(tag)
+	if err != nil || parsed == language.Und {
+		return ""
+	}
+	base, _ := parsed.Base()
+	return base.String()
+}
+
+func normalizeVideoCodec(codec string) string {
+	codec = strings.ToLower(strings.TrimSpace(codec))
+	if alias, ok := videoCodecAliases[codec]; ok {
+		return alias
+	}
+	return codec
+}
+
+// HasMediaInfoCriteria returns true if the filter uses the technical metadata of the files.
+func (f *LocalFileFilter) HasMediaInfoCriteria() bool {
+	return f.VideoCodec != "" ||
+		f.MinHeight > 0 || f.MaxHeight > 0 ||
+		f.MinDuration > 0 || f.MaxDuration > 0 ||
+		f.AudioLanguage != "" || f.SubtitleLanguage != "" ||
+		f.MissingAudioLanguage != "" || f.MissingSubtitleLanguage != ""
+}
+
+// Match returns true if the local file satisfies all the criteria of the filter.
+func (f *LocalFileFilter) Match(lf *LocalFile) bool {
+	if lf == nil {
+		return false
+	}
+	if f.MediaId > 0 && lf.MediaId != f.MediaId {
+		return false
+	}
+	if !f.HasMediaInfoCriteria() {
+		return true
+	}
+
+	info := lf.MediaInfo
+	if info == nil {
+		return false
+	}
+
+	if f.VideoCodec != "" && normalizeVideoCodec(info.VideoCodec) != normalizeVideoCodec(f.VideoCodec) {
+		return false
+	}
+	if f.MinHeight > 0 && info.Height < f.MinHeight {
+		return false
+	}
+	if f.MaxHeight > 0 && info.Height > f.MaxHeight {
+		return false
+	}
+	if f.MinDuration > 0 && info.Duration < f.MinDuration {
+		return false
+	}
+	if f.MaxDuration > 0 && info.Duration > f.MaxDuration {
+		return false
+	}
+	if f.AudioLanguage != "" && !hasTrackLanguage(info.AudioTracks, f.AudioLanguage) {
+		return false
+	}
+	if f.SubtitleLanguage != "" && !hasTrackLanguage(info.SubtitleTracks, f.SubtitleLanguage) {
+		return false
+	}
+	if f.MissingAudioLanguage != "" && hasTrackLanguage(info.AudioTracks, f.MissingAudioLanguage) {
+		return false
+	}
+	if f.MissingSubtitleLanguage != "" && hasTrackLanguage(info.SubtitleTracks, f.MissingSubtitleLanguage) {
+		return false
+	}
+
+	return true
+}
+
+// Filter returns the local files matching the filter.
+func (f *LocalFileFilter) Filter(lfs []*LocalFile) []*LocalFile {
+	ret := make([]*LocalFile, 0)
+	for _, lf := range lfs {
+		if f.Match(lf) {
+			ret = append(ret, lf)
+		}
+	}
+	return ret
+}
+
+func hasTrackLanguage(tracks []*LocalFileMediaTrack, lang string) bool {
+	lang = NormalizeMediaLanguage(lang)
+	if lang == "" {
+		return false
+	}
+	for _, track := range tracks {
+		if track != nil && track.Language == lang {
+			return true
+		}
+	}
+	return false
+}
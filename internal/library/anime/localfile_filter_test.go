@@ -0,0 +1,117 @@
+package anime_test
+
+import (
+	"seanime/internal/library/anime"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeMediaLanguage(t *testing.T) {
+	tests := map[string]string{
+		"eng":   "en",
+		"en":    "en",
+		"jpn":   "ja",
+		"en-US": "en",
+		"und":   "",
+		"":      "",
+		"???":   "",
+	}
+
+	for tag, expected := range tests {
+		assert.Equal(t, expected, anime.NormalizeMediaLanguage(tag), tag)
+	}
+}
+
+func TestLocalFileFilter_Filter(t *testing.T) {
+
+	lfs := []*anime.LocalFile{
+		{
+			Path:    "/Anime/Show/Show - 01.mkv",
+			MediaId: 1,
+			MediaInfo: &anime.LocalFileMediaInfo{
+				Duration:       1420,
+				VideoCodec:     "hevc",
+				Height:         1080,
+				AudioTracks:    []*anime.LocalFileMediaTrack{{Codec: "aac", Language: "ja"}},
+				SubtitleTracks: []*anime.LocalFileMediaTrack{{Codec: "ass", Language: "en"}},
+			},
+		},
+		{
+			Path:    "/Anime/Show/Show - NCOP.mkv",
+			MediaId: 1,
+			MediaInfo: &anime.LocalFileMediaInfo{
+				Duration:    90,
+				VideoCodec:  "h264",
+				Height:      720,
+				AudioTracks: []*anime.LocalFileMediaTrack{{Codec: "flac", Language: "ja"}},
+			},
+		},
+		{
+			Path:    "/Anime/Other/Other - 01.mkv",
+			MediaId: 2,
+			MediaInfo: &anime.LocalFileMediaInfo{
+				Duration:       1440,
+				VideoCodec:     "h264",
+				Height:         1080,
+				AudioTracks:    []*anime.LocalFileMediaTrack{{Codec: "aac", Language: "ja"}, {Codec: "aac", Language: "en"}},
+				SubtitleTracks: []*anime.LocalFileMediaTrack{{Codec: "hdmv_pgs_subtitle", Language: "en"}},
+			},
+		},
+		{
+			// Not probed
+			Path:    "/Anime/Other/Other - 02.mkv",
+			MediaId: 2,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		filter   anime.LocalFileFilter
+		expected []string
+	}{
+		{
+			name:     "empty filter",
+			filter:   anime.LocalFileFilter{},
+			expected: []string{"/Anime/Show/Show - 01.mkv", "/Anime/Show/Show - NCOP.mkv", "/Anime/Other/Other - 01.mkv", "/Anime/Other/Other - 02.mkv"},
+		},
+		{
+			name:     "media",
+			filter:   anime.LocalFileFilter{MediaId: 2},
+			expected: []string{"/Anime/Other/Other - 01.mkv", "/Anime/Other/Other - 02.mkv"},
+		},
+		{
+			name:     "HEVC files",
+			filter:   anime.LocalFileFilter{VideoCodec: "x265"},
+			expected: []string{"/Anime/Show/Show - 01.mkv"},
+		},
+		{
+			name:     "without English subtitles",
+			filter:   anime.LocalFileFilter{MissingSubtitleLanguage: "eng"},
+			expected: []string{"/Anime/Show/Show - NCOP.mkv"},
+		},
+		{
+			name:     "English audio",
+			filter:   anime.LocalFileFilter{AudioLanguage: "en"},
+			expected: []string{"/Anime/Other/Other - 01.mkv"},
+		},
+		{
+			name:     "shorter than 10 minutes",
+			filter:   anime.LocalFileFilter{MaxDuration: 600},
+			expected: []string{"/Anime/Show/Show - NCOP.mkv"},
+		},
+		{
+			name:     "1080p episodes of a media",
+			filter:   anime.LocalFileFilter{MediaId: 1, MinHeight: 1080},
+			expected: []string{"/Anime/Show/Show - 01.mkv"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ret := tt.filter.Filter(lfs)
+			assert.Equal(t, tt.expected, lo.Map(ret, func(lf *anime.LocalFile, _ int) string { return lf.Path }))
+		})
+	}
+}
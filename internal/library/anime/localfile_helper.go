@@ -70,8 +70,9 @@ func (f *LocalFile) IsLocked() bool {
 	return f.Locked
 }
 
+// IsIgnored returns true if the file was ignored by the user or is excluded by a .seaignore file.
 func (f *LocalFile) IsIgnored() bool {
-	return f.Ignored
+	return f.Ignored || f.IgnoredBySeaignore
 }
 
 // GetNormalizedPath returns the lowercase path of the LocalFile.
@@ -437,3 +438,12 @@ func (f *LocalFile) GetTitleVariations() []*string {
 	return lo.ToSlicePtr(titleVariations)
 
 }
+
+// GetLocalFilePaths returns the paths of the local files.
+func GetLocalFilePaths(lfs []*LocalFile) []string {
+	ret := make([]string, 0, len(lfs))
+	for _, lf := range lfs {
+		ret = append(ret, lf.Path)
+	}
+	return ret
+}
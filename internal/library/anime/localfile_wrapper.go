@@ -66,6 +66,23 @@ func (e *LocalFileWrapperEntry) GetMainLocalFiles() ([]*LocalFile, bool) {
 	return lfs, true
 }
 
+// GetExtraLocalFiles returns the *special* and *NC* local files.
+// They are ordered like the entry's episode sections, then by AniDB episode number.
+func (e *LocalFileWrapperEntry) GetExtraLocalFiles() []*LocalFile {
+	lfs := make([]*LocalFile, 0)
+	for _, lf := range e.localFiles {
+		if lf.Metadata != nil && lf.Metadata.Type != "" && !lf.IsMain() {
+			lfs = append(lfs, lf)
+		}
+	}
+	slices.SortStableFunc(lfs, func(a, b *LocalFile) int {
+		sa := slices.Index(episodeSectionTypes, getAniDBEpisodeSectionType(a.GetAniDBEpisode(), a.GetType()))
+		sb := slices.Index(episodeSectionTypes, getAniDBEpisodeSectionType(b.GetAniDBEpisode(), b.GetType()))
+		return cmp.Or(cmp.Compare(sa, sb), cmp.Compare(a.GetEpisodeNumber(), b.GetEpisodeNumber()))
+	})
+	return lfs
+}
+
 // GetUnwatchedLocalFiles returns the *main* local files that have not been watched.
 // It returns an empty slice if all local files have been watched.
 //
@@ -148,7 +165,7 @@ func (e *LocalFileWrapperEntry) FindLatestLocalFile() (*LocalFile, bool) {
 // FindNextEpisode returns the *main* local file whose episode number is after the given local file.
 func (e *LocalFileWrapperEntry) FindNextEpisode(lf *LocalFile) (*LocalFile, bool) {
 	lfs, ok := e.GetMainLocalFiles()
-	if !ok {
+	if !ok || !lf.IsMain() {
 		return nil, false
 	}
 	// Get the local file whose episode number is after the given local file
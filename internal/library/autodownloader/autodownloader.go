@@ -19,7 +19,6 @@ import (
 	"sort"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/5rahim/habari"
 	hibiketorrent "github.com/5rahim/hibike/pkg/extension/torrent"
@@ -50,6 +49,7 @@ type (
 		startCh                 chan struct{}
 		debugTrace              bool
 		mu                      sync.Mutex
+		checkMu                 sync.Mutex
 	}
 
 	NewAutoDownloaderOptions struct {
@@ -193,13 +193,8 @@ func (ad *AutoDownloader) start() {
 		ad.logger.Info().Msg("autodownloader: Module started")
 	}
 
+	// Periodic checks are run by the scheduler, see CheckForNewEpisodes
 	for {
-		interval := 20
-		// Use the user-defined interval if it's greater or equal to 15
-		if ad.settings != nil && ad.settings.Interval > 0 && ad.settings.Interval >= 15 {
-			interval = ad.settings.Interval
-		}
-		ticker := time.NewTicker(time.Duration(interval) * time.Minute)
 		select {
 		case <-ad.settingsUpdatedCh:
 			break // Restart the loop
@@ -210,19 +205,27 @@ func (ad *AutoDownloader) start() {
 				ad.logger.Debug().Msg("autodownloader: Auto Downloader started")
 				ad.checkForNewEpisodes()
 			}
-		case <-ticker.C:
-			if ad.settings.Enabled {
-				ad.checkForNewEpisodes()
-			}
 		}
-		ticker.Stop()
 	}
 
 }
 
+// CheckForNewEpisodes checks for new episodes and waits for the check to finish.
+// It is run periodically by the scheduler.
+func (ad *AutoDownloader) CheckForNewEpisodes() {
+	if ad == nil {
+		return
+	}
+	ad.checkForNewEpisodes()
+}
+
 func (ad *AutoDownloader) checkForNewEpisodes() {
 	defer util.HandlePanicInModuleThen("autodownloader/checkForNewEpisodes", func() {})
 
+	// Prevent the scheduler and the start signal from checking at the same time
+	ad.checkMu.Lock()
+	defer ad.checkMu.Unlock()
+
 	ad.mu.Lock()
 	if ad == nil || ad.torrentRepository == nil || !ad.settings.Enabled || ad.settings.Provider == "" || ad.settings.Provider == torrent.ProviderNone {
 		ad.logger.Warn().Msg("autodownloader: Could not check for new episodes. AutoDownloader is not enabled or provider is not set.")
@@ -406,6 +409,11 @@ func (ad *AutoDownloader) checkForNewEpisodes() {
 	p.Wait()
 
 	if downloaded > 0 {
+		notifier.GlobalDispatcher.Dispatch(notifier.EventAutoDownloadFinished, map[string]interface{}{
+			"count":  downloaded,
+			"queued": !ad.settings.DownloadAutomatically,
+		})
+
 		if ad.settings.DownloadAutomatically {
 			notifier.GlobalNotifier.Notify(
 				notifier.AutoDownloader,
@@ -476,6 +484,20 @@ func (ad *AutoDownloader) downloadTorrent(t *NormalizedTorrent, rule *anime.Auto
 		return false
 	}
 
+	var roots []*models.LibraryRoot
+	if settings, err := ad.database.GetSettings(); err == nil {
+		roots = settings.Library.GetLibraryRoots()
+	}
+	var localFilePaths []string
+	if lfs, _, err := db_bridge.GetLocalFiles(ad.database); err == nil {
+		localFilePaths = anime.GetLocalFilePaths(lfs)
+	}
+	destination, err := ResolveRuleDestination(rule, roots, localFilePaths)
+	if err != nil {
+		ad.logger.Warn().Err(err).Str("name", t.Name).Msg("autodownloader: Could not download torrent. Invalid destination")
+		return false
+	}
+
 	if ad.torrentClientRepository == nil {
 		ad.logger.Error().Msg("autodownloader: torrent client not found")
 		return false
@@ -512,7 +534,7 @@ func (ad *AutoDownloader) downloadTorrent(t *NormalizedTorrent, rule *anime.Auto
 			_, err := ad.debridClientRepository.AddAndQueueTorrent(debrid.AddTorrentOptions{
 				MagnetLink:   magnet,
 				SelectFileId: "all", // RD-only, select all files
-			}, rule.Destination, rule.MediaId)
+			}, destination, rule.MediaId)
 			if err != nil {
 				ad.logger.Error().Err(err).Str("link", t.Link).Str("name", t.Name).Msg("autodownloader: Failed to add torrent to debrid")
 				return false
@@ -558,7 +580,7 @@ func (ad *AutoDownloader) downloadTorrent(t *NormalizedTorrent, rule *anime.Auto
 			ad.logger.Debug().Msgf("autodownloader: Downloading torrent: %s", t.Name)
 
 			// Add the torrent to torrent client
-			err := ad.torrentClientRepository.AddMagnets([]string{magnet}, rule.Destination)
+			err := ad.torrentClientRepository.AddMagnets([]string{magnet}, destination)
 			if err != nil {
 				ad.logger.Error().Err(err).Str("link", t.Link).Str("name", t.Name).Msg("autodownloader: Failed to add torrent to torrent client")
 				return false
@@ -571,6 +593,21 @@ func (ad *AutoDownloader) downloadTorrent(t *NormalizedTorrent, rule *anime.Auto
 	ad.logger.Info().Str("name", t.Name).Msg("autodownloader: Added torrent")
 	ad.wsEventManager.SendEvent(events.AutoDownloaderItemAdded, t.Name)
 
+	if downloaded {
+		mediaTitle := rule.ComparisonTitle
+		if ac, ok := ad.animeCollection.Get(); ok {
+			if media, found := ac.FindAnime(rule.MediaId); found {
+				mediaTitle = media.GetPreferredTitle()
+			}
+		}
+		notifier.GlobalDispatcher.Dispatch(notifier.EventAutoDownloadStarted, map[string]interface{}{
+			"media":   mediaTitle,
+			"mediaId": rule.MediaId,
+			"episode": episode,
+			"torrent": t.Name,
+		})
+	}
+
 	// Add the torrent to the database
 	item := &models.AutoDownloaderItem{
 		RuleID:      rule.DbID,
@@ -1,8 +1,15 @@
 package autodownloader
 
 import (
+	"fmt"
+	"path/filepath"
+	"seanime/internal/database/models"
 	"seanime/internal/library/anime"
+	"seanime/internal/library/filesystem"
+	"seanime/internal/util"
 	"strings"
+
+	"github.com/samber/lo"
 )
 
 func GetUniqueReleaseGroups(rules []*anime.AutoDownloaderRule) []string {
@@ -19,3 +26,38 @@ func GetUniqueReleaseGroups(rules []*anime.AutoDownloaderRule) []string {
 	}
 	return result
 }
+
+// ResolveRuleDestination returns the directory the torrents of the rule are downloaded to.
+// A relative destination is resolved against the library directory targeted by the rule.
+// It returns an error if the library directory of the destination is disabled, read-only or offline.
+// localFilePaths are the paths of the known local files, see filesystem.IsRootOffline.
+func ResolveRuleDestination(rule *anime.AutoDownloaderRule, roots []*models.LibraryRoot, localFilePaths []string) (string, error) {
+	destination := rule.Destination
+
+	var root *models.LibraryRoot
+	if rule.DestinationRoot != "" {
+		r, ok := lo.Find(roots, func(r *models.LibraryRoot) bool {
+			return util.IsSameDir(r.Path, rule.DestinationRoot)
+		})
+		if !ok {
+			return "", fmt.Errorf("library directory %s not found", rule.DestinationRoot)
+		}
+		root = r
+		if !filepath.IsAbs(destination) {
+			destination = filepath.Join(root.Path, destination)
+		} else if !util.IsSameDir(root.Path, destination) && !util.IsSubdirectory(root.Path, destination) {
+			return "", fmt.Errorf("destination %s is not in library directory %s", destination, root.Path)
+		}
+	} else if r, ok := filesystem.FindLibraryRoot(roots, destination); ok {
+		root = r
+	}
+
+	if root == nil {
+		return destination, nil
+	}
+	if err := filesystem.CheckDownloadRoot(root, localFilePaths); err != nil {
+		return "", err
+	}
+
+	return destination, nil
+}
@@ -0,0 +1,82 @@
+package autodownloader
+
+import (
+	"os"
+	"path/filepath"
+	"seanime/internal/database/models"
+	"seanime/internal/library/anime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRuleDestination(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "Anime")
+	archivePath := filepath.Join(dir, "Archive")
+	externalPath := filepath.Join(dir, "External") // Unplugged
+	require.NoError(t, os.MkdirAll(mainPath, 0755))
+	require.NoError(t, os.MkdirAll(archivePath, 0755))
+
+	roots := []*models.LibraryRoot{
+		{Path: mainPath, Enabled: true},
+		{Path: archivePath, Enabled: true, ReadOnly: true},
+		{Path: externalPath, Enabled: true},
+	}
+
+	tests := []struct {
+		name     string
+		rule     *anime.AutoDownloaderRule
+		expected string
+		err      bool
+	}{
+		{
+			name:     "absolute destination",
+			rule:     &anime.AutoDownloaderRule{Destination: filepath.Join(mainPath, "Show")},
+			expected: filepath.Join(mainPath, "Show"),
+		},
+		{
+			name:     "destination outside of the library",
+			rule:     &anime.AutoDownloaderRule{Destination: filepath.Join(dir, "Downloads")},
+			expected: filepath.Join(dir, "Downloads"),
+		},
+		{
+			name:     "relative to the targeted root",
+			rule:     &anime.AutoDownloaderRule{Destination: "Show", DestinationRoot: mainPath},
+			expected: filepath.Join(mainPath, "Show"),
+		},
+		{
+			name: "absolute destination outside of the targeted root",
+			rule: &anime.AutoDownloaderRule{Destination: filepath.Join(dir, "Downloads"), DestinationRoot: mainPath},
+			err:  true,
+		},
+		{
+			name: "unknown root",
+			rule: &anime.AutoDownloaderRule{Destination: "Show", DestinationRoot: filepath.Join(dir, "Unknown")},
+			err:  true,
+		},
+		{
+			name: "read-only root",
+			rule: &anime.AutoDownloaderRule{Destination: filepath.Join(archivePath, "Show")},
+			err:  true,
+		},
+		{
+			name: "offline root",
+			rule: &anime.AutoDownloaderRule{Destination: "Show", DestinationRoot: externalPath},
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destination, err := ResolveRuleDestination(tt.rule, roots, nil)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, destination)
+		})
+	}
+}
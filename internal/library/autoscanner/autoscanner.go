@@ -1,19 +1,23 @@
 package autoscanner
 
 import (
+	"context"
 	"errors"
 	"github.com/rs/zerolog"
+	"reflect"
 	"seanime/internal/api/metadata"
 	"seanime/internal/database/db"
 	"seanime/internal/database/db_bridge"
 	"seanime/internal/database/models"
 	"seanime/internal/events"
+	"seanime/internal/library/anime"
 	"seanime/internal/library/autodownloader"
 	"seanime/internal/library/scanner"
 	"seanime/internal/library/summary"
 	"seanime/internal/notifier"
 	"seanime/internal/platforms/platform"
 	"seanime/internal/util"
+	"slices"
 	"sync"
 	"time"
 )
@@ -35,6 +39,13 @@ type (
 		autoDownloader   *autodownloader.AutoDownloader // AutoDownloader instance is required to refresh queue.
 		metadataProvider metadata.Provider
 		logsDir          string
+		onScanned        func(previous []*anime.LocalFile, current []*anime.LocalFile)
+		pendingPaths     map[string]struct{} // Paths changed since the last scan, only these are scanned (delta scan).
+		fullScanPending  bool                // Whether a change requires scanning the whole library.
+		fullScanInterval time.Duration       // Maximum time between two full scans, delta scans are used in between.
+		lastFullScan     time.Time
+		mediaCache       *scanner.MediaCache // Media of the last scan, used to match the files of delta scans.
+		scanCanceller    *scanner.ScanCanceller
 	}
 	NewAutoScannerOptions struct {
 		Database         *db.Database
@@ -46,6 +57,9 @@ type (
 		WaitTime         time.Duration
 		MetadataProvider metadata.Provider
 		LogsDir          string
+		OnScanned        func(previous []*anime.LocalFile, current []*anime.LocalFile) // Called after the local files are saved
+		FullScanInterval time.Duration                                                 // Defaults to 12 hours
+		ScanCanceller    *scanner.ScanCanceller                                        // optional, used to cancel the scans from the API and to run them one at a time
 	}
 )
 
@@ -55,6 +69,17 @@ func New(opts *NewAutoScannerOptions) *AutoScanner {
 		wt = opts.WaitTime
 	}
 
+	fsi := time.Hour * 12
+	if opts.FullScanInterval > 0 {
+		fsi = opts.FullScanInterval
+	}
+
+	// The scans of the auto scanner are run one at a time even without a shared canceller
+	scanCanceller := opts.ScanCanceller
+	if scanCanceller == nil {
+		scanCanceller = scanner.NewScanCanceller()
+	}
+
 	return &AutoScanner{
 		fileActionCh:     make(chan struct{}, 1),
 		waiting:          false,
@@ -70,11 +95,30 @@ func New(opts *NewAutoScannerOptions) *AutoScanner {
 		autoDownloader:   opts.AutoDownloader,
 		metadataProvider: opts.MetadataProvider,
 		logsDir:          opts.LogsDir,
+		onScanned:        opts.OnScanned,
+		pendingPaths:     make(map[string]struct{}),
+		fullScanInterval: fsi,
+		lastFullScan:     time.Now(),
+		scanCanceller:    scanCanceller,
 	}
 }
 
 // Notify is used to notify the AutoScanner that a file action has occurred.
+// The whole library will be scanned.
 func (as *AutoScanner) Notify() {
+	as.notify(nil)
+}
+
+// NotifyFileAction is used to notify the AutoScanner that a file or directory changed.
+// Only the changed paths will be scanned, unless a full scan is due.
+func (as *AutoScanner) NotifyFileAction(action *scanner.FileAction) {
+	if action == nil || action.Path == "" {
+		return
+	}
+	as.notify(action)
+}
+
+func (as *AutoScanner) notify(action *scanner.FileAction) {
 	if as == nil {
 		return
 	}
@@ -86,18 +130,75 @@ func (as *AutoScanner) Notify() {
 	as.mu.Lock()
 	defer as.mu.Unlock()
 
+	if !as.enabled {
+		return
+	}
+
+	if action == nil {
+		as.fullScanPending = true
+	} else {
+		as.pendingPaths[action.Path] = struct{}{}
+	}
+
+	as.signalFileAction()
+}
+
+// signalFileAction wakes up the watcher so that the pending changes are scanned.
+// The caller must hold the lock.
+func (as *AutoScanner) signalFileAction() {
 	// If we are currently scanning, we will set the missedAction flag to true.
 	if as.waiting {
 		as.missedAction = true
 		return
 	}
 
-	if as.enabled {
-		go func() {
-			// Otherwise, we will send a signal to the fileActionCh.
-			as.fileActionCh <- struct{}{}
-		}()
+	// Otherwise, we will send a signal to the fileActionCh.
+	// If a signal is already pending, the changes are scanned with it.
+	select {
+	case as.fileActionCh <- struct{}{}:
+	default:
+	}
+}
+
+// takePendingScan returns the paths to scan and resets them.
+// It returns no paths if the whole library should be scanned, and false if there is nothing to scan.
+// The caller must hold the lock.
+func (as *AutoScanner) takePendingScan() ([]string, bool) {
+	paths := make([]string, 0, len(as.pendingPaths))
+	for path := range as.pendingPaths {
+		paths = append(paths, path)
+	}
+	// Due full scans are only run by the enabled autoscanner, requeued paths are scanned alone otherwise
+	full := as.fullScanPending || (as.enabled && time.Since(as.lastFullScan) > as.fullScanInterval)
+
+	as.clearPendingScan()
+
+	if full {
+		return nil, true
+	}
+	return paths, len(paths) > 0
+}
+
+// requeuePendingPaths adds paths that could not be scanned back to the pending paths and triggers a scan of them.
+// The paths are scanned even if the autoscanner is disabled, e.g. the paths of a cancelled targeted scan.
+func (as *AutoScanner) requeuePendingPaths(paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	for _, path := range paths {
+		as.pendingPaths[path] = struct{}{}
 	}
+	as.signalFileAction()
+}
+
+// clearPendingScan resets the paths and full scan waiting to be scanned.
+// The caller must hold the lock.
+func (as *AutoScanner) clearPendingScan() {
+	as.pendingPaths = make(map[string]struct{})
+	as.fullScanPending = false
 }
 
 // Start starts the AutoScanner in a goroutine.
@@ -109,6 +210,7 @@ func (as *AutoScanner) Start() {
 
 		as.watch()
 	}()
+	go as.watchFullScanInterval()
 }
 
 // SetSettings should be called after the settings are fetched and updated from the database.
@@ -116,10 +218,28 @@ func (as *AutoScanner) SetSettings(settings models.LibrarySettings) {
 	as.mu.Lock()
 	defer as.mu.Unlock()
 
+	// The cached media were matched using the previous settings
+	if scanSettingsChanged(&as.settings, &settings) {
+		as.mediaCache = nil
+	}
+
 	as.enabled = settings.AutoScan
 	as.settings = settings
 }
 
+// scanSettingsChanged returns true if the settings used to fetch and match the media of delta scans changed.
+func scanSettingsChanged(prev *models.LibrarySettings, curr *models.LibrarySettings) bool {
+	if prev.LibraryPath != curr.LibraryPath ||
+		!slices.Equal(prev.LibraryPaths, curr.LibraryPaths) ||
+		prev.ScannerMatchingThreshold != curr.ScannerMatchingThreshold ||
+		prev.ScannerMatchingAlgorithm != curr.ScannerMatchingAlgorithm {
+		return true
+	}
+	return !slices.EqualFunc(prev.LibraryRoots, curr.LibraryRoots, func(a, b *models.LibraryRoot) bool {
+		return reflect.DeepEqual(a, b)
+	})
+}
+
 // watch is used to watch for file actions and trigger a scan.
 // When a file action occurs, it will wait 30 seconds before triggering a scan.
 // If another file action occurs within that 30 seconds, it will reset the timer.
@@ -138,6 +258,31 @@ func (as *AutoScanner) watch() {
 
 }
 
+// watchFullScanInterval triggers a full scan when none happened during the full scan interval,
+// even if no file action occurred.
+func (as *AutoScanner) watchFullScanInterval() {
+	defer util.HandlePanicInModuleThen("scanner/autoscanner/watchFullScanInterval", func() {
+		as.logger.Error().Msg("autoscanner: recovered from panic")
+	})
+
+	ticker := time.NewTicker(min(as.fullScanInterval, time.Hour))
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if as.isFullScanDue() {
+			as.logger.Debug().Msg("autoscanner: Full scan is due")
+			as.Notify()
+		}
+	}
+}
+
+// isFullScanDue returns true if a full scan should be triggered because none happened during the full scan interval.
+func (as *AutoScanner) isFullScanDue() bool {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	return as.enabled && !as.fullScanPending && time.Since(as.lastFullScan) > as.fullScanInterval
+}
+
 // waitAndScan is used to wait for additional file actions before triggering a scan.
 func (as *AutoScanner) waitAndScan() {
 	as.logger.Trace().Msgf("autoscanner: File action occurred, waiting %v seconds before triggering a scan.", as.waitTime.Seconds())
@@ -160,23 +305,54 @@ func (as *AutoScanner) waitAndScan() {
 	}
 
 	as.waiting = false
+	paths, ok := as.takePendingScan()
 	as.mu.Unlock()
 
+	// The changes were already scanned, e.g. by RunNow
+	if !ok {
+		return
+	}
+
 	// Trigger a scan.
-	as.scan()
+	// If only some paths changed, only the files under them are scanned.
+	if len(paths) > 0 {
+		as.logger.Debug().Int("count", len(paths)).Msg("autoscanner: Scanning changed paths")
+	}
+	as.scan(paths)
 }
 
 // RunNow bypasses checks and triggers a scan immediately, even if the autoscanner is disabled.
+// The pending changes are scanned with the rest of the library.
 func (as *AutoScanner) RunNow() {
-	as.scan()
+	as.mu.Lock()
+	as.clearPendingScan()
+	as.mu.Unlock()
+
+	as.scan(nil)
+}
+
+// RunTargeted triggers a scan of the given paths immediately, even if the autoscanner is disabled.
+// The other local files are kept as-is.
+// The paths should be inside the library directories.
+func (as *AutoScanner) RunTargeted(paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	as.scan(paths)
 }
 
 // scan is used to trigger a scan.
-func (as *AutoScanner) scan() {
+// If targetPaths is not empty, only the files under those paths are scanned.
+func (as *AutoScanner) scan(targetPaths []string) {
 	defer util.HandlePanicInModuleThen("scanner/autoscanner/scan", func() {
 		as.logger.Error().Msg("autoscanner: Recovered from panic")
 	})
 
+	// Wait for the other scans, e.g. a targeted scan started while a delta scan is running,
+	// so that the local files saved by one scan are not overwritten by another
+	unlock := as.scanCanceller.Lock()
+	defer unlock()
+
 	// Create scan summary logger
 	scanSummaryLogger := summary.NewScanSummaryLogger()
 
@@ -211,12 +387,43 @@ func (as *AutoScanner) scan() {
 			return
 		}
 		defer scanLogger.Done()
+
+		// Remove the oldest scan logs
+		if _, err := scanner.CleanUpScanLogs(as.logsDir, settings.Library.ScannerLogRetention); err != nil {
+			as.logger.Warn().Err(err).Msg("autoscanner: Failed to clean up scan logs")
+		}
+	}
+
+	matchingRules, err := as.db.GetMatchingRules()
+	if err != nil {
+		as.logger.Error().Err(err).Msg("autoscanner: Failed to get matching rules")
+		return
+	}
+
+	hashLookup, err := scanner.NewHashLookupBackend(settings.Library)
+	if err != nil {
+		// Files are still hashed, they are matched by title
+		as.logger.Warn().Err(err).Msg("autoscanner: Failed to load hash lookup file")
+	}
+
+	var ffprobePath string
+	if mediastreamSettings, found := as.db.GetMediastreamSettings(); found {
+		ffprobePath = mediastreamSettings.FfprobePath
+	}
+
+	// Delta scans reuse the media of the previous scan
+	var mediaCache *scanner.MediaCache
+	if len(targetPaths) > 0 {
+		as.mu.Lock()
+		mediaCache = as.mediaCache
+		as.mu.Unlock()
 	}
 
 	// Create a new scanner
 	sc := scanner.Scanner{
 		DirPath:            settings.Library.LibraryPath,
 		OtherDirPaths:      settings.Library.LibraryPaths,
+		Roots:              settings.Library.GetLibraryRoots(),
 		Enhanced:           false, // Do not use enhanced mode for auto scanner.
 		Platform:           as.platform,
 		Logger:             as.logger,
@@ -229,18 +436,44 @@ func (as *AutoScanner) scan() {
 		MetadataProvider:   as.metadataProvider,
 		MatchingThreshold:  as.settings.ScannerMatchingThreshold,
 		MatchingAlgorithm:  as.settings.ScannerMatchingAlgorithm,
+		TargetPaths:        targetPaths,
+		MediaCache:         mediaCache,
+		MatchingRules:      matchingRules,
+		HashFiles:          settings.Library.ScannerHashFiles,
+		FileHashCache:      as.db,
+		HashLookup:         hashLookup,
+		ProbeMediaInfo:     settings.Library.ScannerProbeMediaInfo,
+		FfprobePath:        ffprobePath,
+		FileMediaInfoCache: as.db,
 	}
 
-	allLfs, err := sc.Scan()
+	ctx, done := as.scanCanceller.Start(context.Background())
+	defer done()
+
+	allLfs, err := sc.Scan(ctx)
 	if err != nil {
 		if errors.Is(err, scanner.ErrNoLocalFiles) {
 			return
+		} else if errors.Is(err, scanner.ErrScanCancelled) {
+			as.logger.Info().Msg("autoscanner: Scan cancelled")
+			// The changed paths were not scanned, they are scanned with the next changes
+			as.requeuePendingPaths(targetPaths)
+			return
 		} else {
 			as.logger.Error().Err(err).Msg("autoscanner: Failed to scan library")
 			return
 		}
 	}
 
+	as.mu.Lock()
+	if sc.MediaCache != nil {
+		as.mediaCache = sc.MediaCache
+	}
+	if len(targetPaths) == 0 {
+		as.lastFullScan = time.Now()
+	}
+	as.mu.Unlock()
+
 	if as.db != nil && len(allLfs) > 0 {
 		as.logger.Trace().Msg("autoscanner: Updating local files")
 
@@ -251,10 +484,20 @@ func (as *AutoScanner) scan() {
 			return
 		}
 
+		if as.onScanned != nil {
+			go as.onScanned(existingLfs, allLfs)
+		}
+
 	}
 
-	// Save the scan summary
-	err = db_bridge.InsertScanSummary(as.db, scanSummaryLogger.GenerateSummary())
+	// Save the scan summary, all the changes are accepted
+	sm := scanSummaryLogger.GenerateSummary()
+	if sm != nil {
+		sm.Diff = summary.NewScanDiff(existingLfs, allLfs)
+		sm.Diff.Status = summary.ScanDiffStatusApplied
+		sm.Diff.AcceptAll()
+	}
+	err = db_bridge.InsertScanSummary(as.db, sm)
 	if err != nil {
 		as.logger.Error().Err(err).Msg("failed to insert scan summary")
 	}
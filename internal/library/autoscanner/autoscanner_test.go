@@ -1,9 +1,154 @@
 package autoscanner
 
 import (
+	"seanime/internal/database/models"
+	"seanime/internal/library/scanner"
+	"seanime/internal/util"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAutoScanner(t *testing.T) {
 
 }
+
+func TestAutoScanner_TakePendingScan(t *testing.T) {
+	logger := util.NewLogger()
+	as := New(&NewAutoScannerOptions{
+		Logger:           logger,
+		Enabled:          true,
+		FullScanInterval: time.Hour,
+	})
+
+	as.NotifyFileAction(&scanner.FileAction{Path: "/library/Show/Show - 01.mkv", Type: scanner.FileActionCreated})
+	as.NotifyFileAction(&scanner.FileAction{Path: "/library/Show/Show - 01.mkv", Type: scanner.FileActionCreated})
+	as.NotifyFileAction(&scanner.FileAction{Path: "/library/Other", Type: scanner.FileActionRemoved})
+
+	as.mu.Lock()
+	paths, ok := as.takePendingScan()
+	as.mu.Unlock()
+	assert.True(t, ok)
+	assert.ElementsMatch(t, []string{"/library/Show/Show - 01.mkv", "/library/Other"}, paths)
+
+	// Pending paths are reset
+	as.mu.Lock()
+	assert.Empty(t, as.pendingPaths)
+	_, ok = as.takePendingScan()
+	assert.False(t, ok, "there should be nothing to scan")
+	as.mu.Unlock()
+
+	// Paths of a cancelled scan are scanned again
+	drainFileActions(as)
+	as.requeuePendingPaths(paths)
+	assert.Len(t, as.fileActionCh, 1, "the watcher should be signalled")
+	as.mu.Lock()
+	requeued, ok := as.takePendingScan()
+	as.mu.Unlock()
+	assert.True(t, ok)
+	assert.ElementsMatch(t, paths, requeued)
+
+	// A full scan is requested
+	as.NotifyFileAction(&scanner.FileAction{Path: "/library/Show/Show - 02.mkv", Type: scanner.FileActionCreated})
+	as.Notify()
+	as.mu.Lock()
+	paths, ok = as.takePendingScan()
+	assert.True(t, ok)
+	assert.Nil(t, paths)
+	as.mu.Unlock()
+
+	// A full scan is due
+	as.NotifyFileAction(&scanner.FileAction{Path: "/library/Show/Show - 03.mkv", Type: scanner.FileActionCreated})
+	as.mu.Lock()
+	as.lastFullScan = time.Now().Add(-2 * time.Hour)
+	paths, ok = as.takePendingScan()
+	assert.True(t, ok)
+	assert.Nil(t, paths)
+	as.mu.Unlock()
+
+	// A full scan is due even without file actions
+	as.mu.Lock()
+	as.lastFullScan = time.Now().Add(-2 * time.Hour)
+	as.mu.Unlock()
+	assert.True(t, as.isFullScanDue())
+	as.Notify()
+	assert.False(t, as.isFullScanDue(), "a full scan is already pending")
+	as.mu.Lock()
+	as.clearPendingScan()
+	as.lastFullScan = time.Now()
+	as.mu.Unlock()
+	assert.False(t, as.isFullScanDue())
+
+	// Nothing is recorded while the autoscanner is disabled
+	as.SetSettings(models.LibrarySettings{AutoScan: false})
+	as.NotifyFileAction(&scanner.FileAction{Path: "/library/Show/Show - 04.mkv", Type: scanner.FileActionCreated})
+	as.mu.Lock()
+	assert.Empty(t, as.pendingPaths)
+	as.mu.Unlock()
+
+	// The paths of a cancelled targeted scan are scanned again while the autoscanner is disabled, without a due full scan
+	drainFileActions(as)
+	as.mu.Lock()
+	as.lastFullScan = time.Now().Add(-2 * time.Hour)
+	as.mu.Unlock()
+	as.requeuePendingPaths([]string{"/library/Show/Show - 05.mkv"})
+	assert.Len(t, as.fileActionCh, 1, "the watcher should be signalled")
+	as.mu.Lock()
+	paths, ok = as.takePendingScan()
+	as.mu.Unlock()
+	assert.True(t, ok)
+	assert.Equal(t, []string{"/library/Show/Show - 05.mkv"}, paths)
+}
+
+func drainFileActions(as *AutoScanner) {
+	for len(as.fileActionCh) > 0 {
+		<-as.fileActionCh
+	}
+}
+
+func TestAutoScanner_SetSettingsResetsMediaCache(t *testing.T) {
+	as := New(&NewAutoScannerOptions{
+		Logger:  util.NewLogger(),
+		Enabled: true,
+	})
+
+	settings := models.LibrarySettings{
+		AutoScan:                 true,
+		LibraryPath:              "/library",
+		ScannerMatchingThreshold: 0.5,
+		LibraryRoots:             models.LibraryRoots{{Path: "/library", Enabled: true}},
+	}
+	as.SetSettings(settings)
+
+	cacheMedia := func() {
+		as.mu.Lock()
+		as.mediaCache = &scanner.MediaCache{CreatedAt: time.Now()}
+		as.mu.Unlock()
+	}
+	hasMediaCache := func() bool {
+		as.mu.Lock()
+		defer as.mu.Unlock()
+		return as.mediaCache != nil
+	}
+
+	// Unrelated settings keep the cache
+	cacheMedia()
+	settings.AutoUpdateProgress = true
+	as.SetSettings(settings)
+	assert.True(t, hasMediaCache())
+
+	settings.ScannerMatchingThreshold = 0.7
+	as.SetSettings(settings)
+	assert.False(t, hasMediaCache())
+
+	cacheMedia()
+	settings.LibraryPaths = models.LibraryPaths{"/other"}
+	as.SetSettings(settings)
+	assert.False(t, hasMediaCache())
+
+	cacheMedia()
+	settings.LibraryRoots = models.LibraryRoots{{Path: "/library", Enabled: false}}
+	as.SetSettings(settings)
+	assert.False(t, hasMediaCache())
+}
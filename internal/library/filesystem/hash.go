@@ -0,0 +1,90 @@
+package filesystem
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/md4"
+)
+
+// ED2KChunkSize is the size of the chunks hashed separately by the ED2K algorithm.
+const ED2KChunkSize = 9728000
+
+// FileHashes holds the hashes of a file's content.
+type FileHashes struct {
+	CRC32 string // Uppercase, as written in release file names
+	ED2K  string // Lowercase, as used by AniDB
+	Size  int64
+}
+
+// HashFile computes the CRC32 and ED2K hashes of a file in a single read.
+func HashFile(path string) (*FileHashes, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return HashReader(f)
+}
+
+// HashReader computes the CRC32 and ED2K hashes of the content of a reader.
+// The ED2K hash of content larger than a chunk is the MD4 hash of the chunks' MD4 hashes.
+// No empty chunk is appended to content whose size is a multiple of the chunk size.
+func HashReader(r io.Reader) (*FileHashes, error) {
+	crc := crc32.NewIEEE()
+	chunkHash := md4.New()
+	chunkHashes := make([]byte, 0)
+
+	buf := make([]byte, 1<<20)
+	var size int64
+	var chunkSize int64
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			data := buf[:n]
+			crc.Write(data)
+			for len(data) > 0 {
+				toWrite := min(int64(len(data)), ED2KChunkSize-chunkSize)
+				chunkHash.Write(data[:toWrite])
+				chunkSize += toWrite
+				data = data[toWrite:]
+				if chunkSize == ED2KChunkSize {
+					chunkHashes = chunkHash.Sum(chunkHashes)
+					chunkHash.Reset()
+					chunkSize = 0
+				}
+			}
+			size += int64(n)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &FileHashes{
+		CRC32: fmt.Sprintf("%08X", crc.Sum32()),
+		ED2K:  ed2kSum(chunkHash, chunkSize, chunkHashes),
+		Size:  size,
+	}, nil
+}
+
+func ed2kSum(chunkHash hash.Hash, chunkSize int64, chunkHashes []byte) string {
+	if chunkSize > 0 || len(chunkHashes) == 0 {
+		chunkHashes = chunkHash.Sum(chunkHashes)
+	}
+	// Content that fits in a single chunk is identified by the chunk's hash
+	if len(chunkHashes) == md4.Size {
+		return hex.EncodeToString(chunkHashes)
+	}
+	root := md4.New()
+	root.Write(chunkHashes)
+	return hex.EncodeToString(root.Sum(nil))
+}
@@ -0,0 +1,71 @@
+package filesystem
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/md4"
+)
+
+func TestHashReader(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		content       []byte
+		expectedCRC32 string
+		expectedED2K  string
+	}{
+		{name: "empty", content: []byte{}, expectedCRC32: "00000000", expectedED2K: "31d6cfe0d16ae931b73c59d7e0c089c0"},
+		{name: "single chunk", content: []byte("abc"), expectedCRC32: "352441C2", expectedED2K: "a448017aaf21d8525fc10ae87aa6729d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hashes, err := HashReader(bytes.NewReader(tt.content))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCRC32, hashes.CRC32)
+			assert.Equal(t, tt.expectedED2K, hashes.ED2K)
+			assert.Equal(t, int64(len(tt.content)), hashes.Size)
+		})
+	}
+}
+
+func TestHashReader_MultipleChunks(t *testing.T) {
+	content := bytes.Repeat([]byte{1}, ED2KChunkSize+10)
+
+	first := md4Sum(content[:ED2KChunkSize])
+	second := md4Sum(content[ED2KChunkSize:])
+	expected := md4.New()
+	expected.Write(first)
+	expected.Write(second)
+
+	hashes, err := HashReader(bytes.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(expected.Sum(nil)), hashes.ED2K)
+
+	// Content of exactly one chunk is identified by the chunk's hash
+	hashes, err = HashReader(bytes.NewReader(content[:ED2KChunkSize]))
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(first), hashes.ED2K)
+}
+
+func md4Sum(data []byte) []byte {
+	h := md4.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.mkv")
+	require.NoError(t, os.WriteFile(path, []byte("abc"), 0644))
+
+	hashes, err := HashFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "352441C2", hashes.CRC32)
+	assert.Equal(t, int64(3), hashes.Size)
+}
@@ -0,0 +1,210 @@
+package filesystem
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"seanime/internal/util"
+	"strings"
+)
+
+// IgnoreFileName is the name of the files used to exclude paths from library scans.
+// They follow the .gitignore syntax and apply to the directory they are in and all of its subdirectories.
+const IgnoreFileName = ".seaignore"
+
+// Paths are matched case-insensitively on file systems that usually are.
+var ignoreCaseInsensitive = runtime.GOOS == "windows" || runtime.GOOS == "darwin"
+
+type (
+	// IgnoreMatcher matches paths against the rules of one or more .seaignore files.
+	// Rules are applied in the order they were added, the last matching rule wins.
+	IgnoreMatcher struct {
+		rules []*ignoreRule
+	}
+
+	ignoreRule struct {
+		base     string   // Directory containing the .seaignore file, slash-separated
+		segments []string // Pattern split on "/"
+		negate   bool     // "!pattern" re-includes paths excluded by a previous rule
+		dirOnly  bool     // "pattern/" only matches directories
+		anchored bool     // Patterns containing a "/" are matched against the path relative to the base, others against the name
+	}
+)
+
+func NewIgnoreMatcher() *IgnoreMatcher {
+	return &IgnoreMatcher{
+		rules: make([]*ignoreRule, 0),
+	}
+}
+
+// LoadDir adds the rules of the .seaignore file in a directory, if there is one.
+func (m *IgnoreMatcher) LoadDir(dir string) error {
+	data, err := os.ReadFile(filepath.Join(dir, IgnoreFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	m.AddPatterns(dir, strings.Split(string(data), "\n"))
+	return nil
+}
+
+// AddPatterns adds gitignore-style patterns relative to a directory.
+// Blank lines and comments are skipped.
+func (m *IgnoreMatcher) AddPatterns(dir string, lines []string) {
+	base := normalizeIgnorePath(dir)
+	for _, line := range lines {
+		if rule, ok := parseIgnoreRule(base, line); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+}
+
+// Match returns true if the path is excluded by the rules.
+// Parent directories are not checked, callers walking a tree should not descend into ignored directories.
+func (m *IgnoreMatcher) Match(p string, isDir bool) bool {
+	if m == nil || len(m.rules) == 0 {
+		return false
+	}
+	p = normalizeIgnorePath(p)
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.match(p, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// IsIgnoredPath returns true if a path under one of the root directories is excluded by a .seaignore file,
+// either directly or because one of its parent directories is.
+func IsIgnoredPath(roots []string, p string, isDir bool) bool {
+	for _, root := range roots {
+		if !util.IsSubdirectory(root, p) {
+			continue
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			continue
+		}
+
+		m := NewIgnoreMatcher()
+		_ = m.LoadDir(root)
+
+		dir := root
+		parts := strings.Split(rel, string(filepath.Separator))
+		for _, part := range parts[:len(parts)-1] {
+			dir = filepath.Join(dir, part)
+			if m.Match(dir, true) {
+				return true
+			}
+			_ = m.LoadDir(dir)
+		}
+
+		return m.Match(p, isDir)
+	}
+	return false
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+func parseIgnoreRule(base string, line string) (*ignoreRule, bool) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+		return nil, false
+	}
+	line = strings.TrimRight(line, " \t")
+
+	rule := &ignoreRule{base: base}
+
+	switch {
+	case strings.HasPrefix(line, "!"):
+		rule.negate = true
+		line = line[1:]
+	case strings.HasPrefix(line, `\!`), strings.HasPrefix(line, `\#`):
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	// A separator at the beginning or in the middle anchors the pattern to the base directory
+	rule.anchored = strings.Contains(line, "/")
+	line = strings.TrimLeft(line, "/")
+	if line == "" {
+		return nil, false
+	}
+
+	if ignoreCaseInsensitive {
+		line = strings.ToLower(line)
+	}
+	rule.segments = strings.Split(line, "/")
+
+	return rule, true
+}
+
+func (r *ignoreRule) match(p string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+
+	prefix := r.base
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if !strings.HasPrefix(p, prefix) {
+		return false
+	}
+	rel := p[len(prefix):]
+	if rel == "" {
+		return false
+	}
+
+	if !r.anchored {
+		return matchIgnoreSegment(r.segments[0], path.Base(rel))
+	}
+	return matchIgnoreSegments(r.segments, strings.Split(rel, "/"))
+}
+
+// matchIgnoreSegments matches path segments against pattern segments.
+// "**" matches any number of segments, a trailing "**" only matches what is inside the directory.
+func matchIgnoreSegments(pattern []string, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+
+	if pattern[0] == "**" {
+		if len(pattern) == 1 {
+			return len(parts) > 0
+		}
+		for i := 0; i <= len(parts); i++ {
+			if matchIgnoreSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(parts) == 0 {
+		return false
+	}
+	return matchIgnoreSegment(pattern[0], parts[0]) && matchIgnoreSegments(pattern[1:], parts[1:])
+}
+
+func matchIgnoreSegment(pattern string, name string) bool {
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
+
+func normalizeIgnorePath(p string) string {
+	p = filepath.ToSlash(filepath.Clean(p))
+	if ignoreCaseInsensitive {
+		p = strings.ToLower(p)
+	}
+	return p
+}
@@ -0,0 +1,85 @@
+package filesystem
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{name: "name pattern matches at any depth", patterns: []string{"*.sample.mkv"}, path: "/lib/Show/S01/ep.sample.mkv", expected: true},
+		{name: "name pattern does not match other files", patterns: []string{"*.sample.mkv"}, path: "/lib/Show/S01/ep.mkv", expected: false},
+		{name: "comments and blank lines are skipped", patterns: []string{"# *.mkv", "", "   "}, path: "/lib/ep.mkv", expected: false},
+		{name: "escaped hash", patterns: []string{`\#ep.mkv`}, path: "/lib/#ep.mkv", expected: true},
+		{name: "directory-only rule matches directories", patterns: []string{"Extras/"}, path: "/lib/Show/Extras", isDir: true, expected: true},
+		{name: "directory-only rule does not match files", patterns: []string{"Extras/"}, path: "/lib/Show/Extras", isDir: false, expected: false},
+		{name: "anchored pattern matches from the base", patterns: []string{"/Show"}, path: "/lib/Show", isDir: true, expected: true},
+		{name: "anchored pattern does not match deeper", patterns: []string{"/Show"}, path: "/lib/Other/Show", isDir: true, expected: false},
+		{name: "middle separator anchors the pattern", patterns: []string{"Show/Extras"}, path: "/lib/Other/Show/Extras", isDir: true, expected: false},
+		{name: "leading double star", patterns: []string{"**/Trailers"}, path: "/lib/Show/S01/Trailers", isDir: true, expected: true},
+		{name: "middle double star matches zero directories", patterns: []string{"Show/**/ep.mkv"}, path: "/lib/Show/ep.mkv", expected: true},
+		{name: "middle double star matches several directories", patterns: []string{"Show/**/ep.mkv"}, path: "/lib/Show/a/b/ep.mkv", expected: true},
+		{name: "trailing double star matches the content", patterns: []string{"Show/**"}, path: "/lib/Show/ep.mkv", expected: true},
+		{name: "trailing double star does not match the directory", patterns: []string{"Show/**"}, path: "/lib/Show", isDir: true, expected: false},
+		{name: "negation re-includes a file", patterns: []string{"*.mkv", "!ep1.mkv"}, path: "/lib/ep1.mkv", expected: false},
+		{name: "last matching rule wins", patterns: []string{"!ep1.mkv", "*.mkv"}, path: "/lib/ep1.mkv", expected: true},
+		{name: "rules do not apply outside their directory", patterns: []string{"*.mkv"}, path: "/other/ep.mkv", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewIgnoreMatcher()
+			m.AddPatterns("/lib", tt.patterns)
+			assert.Equal(t, tt.expected, m.Match(tt.path, tt.isDir))
+		})
+	}
+}
+
+func TestWalkMediaFilePaths_SeaIgnore(t *testing.T) {
+	libDir := t.TempDir()
+
+	showDir := filepath.Join(libDir, "Show")
+	extrasDir := filepath.Join(showDir, "Extras")
+	require.NoError(t, os.MkdirAll(extrasDir, 0755))
+
+	createFile(t, filepath.Join(showDir, "Show - 01.mkv"))
+	createFile(t, filepath.Join(showDir, "Show - 02.mkv"))
+	createFile(t, filepath.Join(showDir, "Show - 02.sample.mkv"))
+	createFile(t, filepath.Join(showDir, "Show - 03.sample.mkv"))
+	createFile(t, filepath.Join(extrasDir, "Interview.mkv"))
+	createFile(t, filepath.Join(extrasDir, "Keep.mkv"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, IgnoreFileName), []byte("# Samples\n*.sample.mkv\nExtras/\n"), 0644))
+	// A nested file can re-include files, but not the content of an ignored directory
+	require.NoError(t, os.WriteFile(filepath.Join(showDir, IgnoreFileName), []byte("!Show - 03.sample.mkv\n!Extras/Keep.mkv\n"), 0644))
+
+	ret, err := WalkMediaFilePaths(context.Background(), libDir)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		filepath.Join(showDir, "Show - 01.mkv"),
+		filepath.Join(showDir, "Show - 02.mkv"),
+		filepath.Join(showDir, "Show - 03.sample.mkv"),
+	}, ret.Paths)
+	assert.ElementsMatch(t, []string{
+		filepath.Join(showDir, "Show - 02.sample.mkv"),
+		filepath.Join(extrasDir, "Interview.mkv"),
+		filepath.Join(extrasDir, "Keep.mkv"),
+	}, ret.IgnoredPaths)
+
+	assert.True(t, IsIgnoredPath([]string{libDir}, filepath.Join(extrasDir, "New.mkv"), false))
+	assert.True(t, IsIgnoredPath([]string{libDir}, filepath.Join(showDir, "Show - 04.sample.mkv"), false))
+	assert.False(t, IsIgnoredPath([]string{libDir}, filepath.Join(showDir, "Show - 04.mkv"), false))
+}
@@ -1,6 +1,7 @@
 package filesystem
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -57,11 +58,35 @@ func GetMediaFilePathsFromDir(dirPath string) ([]string, error) {
 	return filePaths, nil
 }
 
+// MediaFilePaths holds the video files found in a directory.
+type MediaFilePaths struct {
+	Paths []string
+	// IgnoredPaths are the video files excluded by a .seaignore file.
+	IgnoredPaths []string
+}
+
 // GetMediaFilePathsFromDirS returns a slice of strings containing the paths of all the video files in a directory.
-// Unlike GetMediaFilePathsFromDir, it follows symlinks.
+// Unlike GetMediaFilePathsFromDir, it follows symlinks and skips the files excluded by .seaignore files.
 func GetMediaFilePathsFromDirS(oDirPath string) ([]string, error) {
-	filePaths := make([]string, 0)
+	ret, err := WalkMediaFilePaths(context.Background(), oDirPath)
+	if err != nil {
+		return nil, err
+	}
+	return ret.Paths, nil
+}
+
+// WalkMediaFilePaths returns the paths of all the video files in a directory, following symlinks.
+// The .seaignore files found along the way are honored, the files they exclude are returned separately.
+// The context is checked before each directory is read, the walk stops with its error once it is done.
+func WalkMediaFilePaths(ctx context.Context, oDirPath string) (*MediaFilePaths, error) {
+	ret := &MediaFilePaths{
+		Paths:        make([]string, 0),
+		IgnoredPaths: make([]string, 0),
+	}
 	visited := make(map[string]bool)
+	matcher := NewIgnoreMatcher()
+	// Directories excluded by a .seaignore file, everything inside them is ignored
+	ignoredDirs := make(map[string]bool)
 
 	// Normalize the initial directory path
 	dirPath, err := filepath.Abs(oDirPath)
@@ -69,8 +94,8 @@ func GetMediaFilePathsFromDirS(oDirPath string) ([]string, error) {
 		return nil, fmt.Errorf("could not resolve path: %w", err)
 	}
 
-	var walkDir func(string) error
-	walkDir = func(oCurrentPath string) error {
+	var walkDir func(string, bool) error
+	walkDir = func(oCurrentPath string, rootIgnored bool) error {
 		// Normalize current path
 		currentPath, err := filepath.EvalSymlinks(oCurrentPath)
 		if err != nil {
@@ -88,6 +113,14 @@ func GetMediaFilePathsFromDirS(oDirPath string) ([]string, error) {
 				return err
 			}
 
+			if d.IsDir() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+
+			ignored := ignoredDirs[filepath.Dir(path)] || (path == currentPath && rootIgnored)
+
 			// If it's a symlink directory, resolve and walk the symlink
 			info, err := os.Lstat(path)
 			if err != nil {
@@ -105,26 +138,42 @@ func GetMediaFilePathsFromDirS(oDirPath string) ([]string, error) {
 					linkPath = filepath.Join(filepath.Dir(path), linkPath)
 				}
 
-				return walkDir(linkPath)
+				isDir := false
+				if stat, err := os.Stat(path); err == nil {
+					isDir = stat.IsDir()
+				}
+
+				return walkDir(linkPath, ignored || matcher.Match(path, isDir))
 			}
 
 			if d.IsDir() {
+				if ignored || matcher.Match(path, true) {
+					ignoredDirs[path] = true
+					return nil
+				}
+				if err := matcher.LoadDir(path); err != nil {
+					return fmt.Errorf("could not read %s: %w", IgnoreFileName, err)
+				}
 				return nil
 			}
 
 			ext := strings.ToLower(filepath.Ext(path))
 			if util.IsValidMediaFile(path) && util.IsValidVideoExtension(ext) {
-				filePaths = append(filePaths, path)
+				if ignored || matcher.Match(path, false) {
+					ret.IgnoredPaths = append(ret.IgnoredPaths, path)
+				} else {
+					ret.Paths = append(ret.Paths, path)
+				}
 			}
 			return nil
 		})
 	}
 
-	if err = walkDir(dirPath); err != nil {
+	if err = walkDir(dirPath, false); err != nil {
 		return nil, fmt.Errorf("could not traverse directory %s: %w", dirPath, err)
 	}
 
-	return filePaths, nil
+	return ret, nil
 }
 
 //----------------------------------------------------------------------------------------------------------------------
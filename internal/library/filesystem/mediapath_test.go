@@ -1,6 +1,8 @@
 package filesystem
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -65,6 +67,13 @@ func TestGetVideoFilePathsFromDir_WithSymlinks(t *testing.T) {
 			t.Errorf("Expected file path %s not found in result", expected)
 		}
 	}
+
+	// The walk stops once the context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := WalkMediaFilePaths(ctx, tmpDir); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the walk to be cancelled, got %v", err)
+	}
 }
 
 func createFile(t *testing.T, path string) {
@@ -0,0 +1,134 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"seanime/internal/database/models"
+	"seanime/internal/util"
+)
+
+// LibraryRootStatus is a library root with its availability.
+type LibraryRootStatus struct {
+	Root *models.LibraryRoot `json:"root"`
+	// The directory cannot be found or is empty while it had local files, e.g. because its drive is unplugged.
+	// The local files of offline roots are kept until the root is back.
+	Offline bool `json:"offline"`
+}
+
+// IsRootOffline returns true if the root directory cannot be found.
+// A root that is empty while local files are known under it is offline too, it is likely the mount point of an unmounted drive.
+// localFilePaths are the paths of the known local files, they can be nil.
+func IsRootOffline(path string, localFilePaths []string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return true
+	}
+	if !info.IsDir() {
+		return true
+	}
+	hadFiles := false
+	for _, lfPath := range localFilePaths {
+		if util.IsSubdirectory(path, lfPath) {
+			hadFiles = true
+			break
+		}
+	}
+	return hadFiles && isDirEmpty(path)
+}
+
+func isDirEmpty(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	_, err = f.Readdirnames(1)
+	return errors.Is(err, io.EOF)
+}
+
+// GetLibraryRootStatuses returns the library roots of the settings, sorted by descending priority, with their availability.
+// localFilePaths are the paths of the known local files, see IsRootOffline.
+func GetLibraryRootStatuses(settings *models.LibrarySettings, localFilePaths []string) []*LibraryRootStatus {
+	ret := make([]*LibraryRootStatus, 0)
+	if settings == nil {
+		return ret
+	}
+	for _, root := range settings.GetLibraryRoots() {
+		ret = append(ret, &LibraryRootStatus{
+			Root:    root,
+			Offline: IsRootOffline(root.Path, localFilePaths),
+		})
+	}
+	return ret
+}
+
+// GetOnlineLibraryPaths returns the paths of the enabled library roots that are online, sorted by descending priority.
+func GetOnlineLibraryPaths(settings *models.LibrarySettings, localFilePaths []string) []string {
+	ret := make([]string, 0)
+	for _, status := range GetLibraryRootStatuses(settings, localFilePaths) {
+		if status.Root.Enabled && !status.Offline {
+			ret = append(ret, status.Root.Path)
+		}
+	}
+	return ret
+}
+
+// FindLibraryRoot returns the root containing the path.
+func FindLibraryRoot(roots []*models.LibraryRoot, path string) (*models.LibraryRoot, bool) {
+	for _, root := range roots {
+		if util.IsSameDir(root.Path, path) || util.IsSubdirectory(root.Path, path) {
+			return root, true
+		}
+	}
+	return nil, false
+}
+
+// IsInReadOnlyRoot returns true if the path is under a read-only library root.
+func IsInReadOnlyRoot(roots []*models.LibraryRoot, path string) bool {
+	root, ok := FindLibraryRoot(roots, path)
+	return ok && root.ReadOnly
+}
+
+// CheckDownloadRoot returns an error if files cannot be downloaded to the library root,
+// i.e. if it is disabled, read-only or offline.
+// localFilePaths are the paths of the known local files, see IsRootOffline.
+func CheckDownloadRoot(root *models.LibraryRoot, localFilePaths []string) error {
+	if !root.Enabled {
+		return fmt.Errorf("library directory %s is disabled", root.Path)
+	}
+	if root.ReadOnly {
+		return fmt.Errorf("library directory %s is read-only", root.Path)
+	}
+	if IsRootOffline(root.Path, localFilePaths) {
+		return fmt.Errorf("library directory %s is offline", root.Path)
+	}
+	return nil
+}
+
+// ResolveDownloadDestination returns the directory files are downloaded to.
+// If destination is empty, the library root with the highest priority that files can be downloaded to is returned.
+// Otherwise, it returns an error if the destination is under a library root that files cannot be downloaded to, see CheckDownloadRoot.
+// Destinations outside the library are returned as-is.
+func ResolveDownloadDestination(settings *models.LibrarySettings, destination string, localFilePaths []string) (string, error) {
+	roots := settings.GetLibraryRoots()
+
+	if destination == "" {
+		// The roots are sorted by descending priority
+		for _, root := range roots {
+			if CheckDownloadRoot(root, localFilePaths) == nil {
+				return root.Path, nil
+			}
+		}
+		return "", errors.New("no library directory available for downloads")
+	}
+
+	if root, ok := FindLibraryRoot(roots, destination); ok {
+		if err := CheckDownloadRoot(root, localFilePaths); err != nil {
+			return "", err
+		}
+	}
+	return destination, nil
+}
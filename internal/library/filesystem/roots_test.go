@@ -0,0 +1,107 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"seanime/internal/database/models"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLibraryRootStatuses(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.ToSlash(filepath.Join(dir, "Anime"))
+	externalPath := filepath.ToSlash(filepath.Join(dir, "External"))
+	moviesPath := filepath.ToSlash(filepath.Join(dir, "Movies"))
+	require.NoError(t, mkdirs(mainPath, moviesPath))
+
+	settings := &models.LibrarySettings{
+		LibraryPath:  mainPath,
+		LibraryPaths: []string{externalPath, moviesPath},
+		LibraryRoots: models.LibraryRoots{
+			{Path: externalPath, Enabled: true, Priority: 1},
+			{Path: moviesPath + "/", Enabled: false, ReadOnly: true, DefaultMediaFormat: "MOVIE"},
+		},
+	}
+
+	statuses := GetLibraryRootStatuses(settings, nil)
+	require.Len(t, statuses, 3)
+
+	// Sorted by priority, directories without settings are enabled
+	assert.Equal(t, []string{externalPath, mainPath, moviesPath}, lo.Map(statuses, func(s *LibraryRootStatus, _ int) string { return s.Root.Path }))
+	assert.True(t, statuses[0].Offline)
+	assert.True(t, statuses[1].Root.Enabled)
+	assert.False(t, statuses[1].Offline)
+	assert.False(t, statuses[2].Root.Enabled)
+	assert.Equal(t, "MOVIE", statuses[2].Root.DefaultMediaFormat)
+
+	// Only enabled directories that are online
+	assert.Equal(t, []string{mainPath}, GetOnlineLibraryPaths(settings, nil))
+
+	roots := settings.GetLibraryRoots()
+	assert.True(t, IsInReadOnlyRoot(roots, moviesPath+"/Movie/Movie.mkv"))
+	assert.False(t, IsInReadOnlyRoot(roots, mainPath+"/Show/Show - 01.mkv"))
+	assert.False(t, IsInReadOnlyRoot(roots, filepath.Join(dir, "Other", "Show - 01.mkv")))
+
+	// An empty root is offline if local files were found under it, e.g. an unmounted drive
+	knownPaths := []string{mainPath + "/Show/Show - 01.mkv"}
+	assert.True(t, IsRootOffline(mainPath, knownPaths))
+	assert.False(t, IsRootOffline(moviesPath, knownPaths), "new empty roots are online")
+	require.NoError(t, mkdirs(mainPath+"/Show"))
+	assert.False(t, IsRootOffline(mainPath, knownPaths))
+	assert.Equal(t, []string{mainPath}, GetOnlineLibraryPaths(settings, knownPaths))
+}
+
+func TestResolveDownloadDestination(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.ToSlash(filepath.Join(dir, "Anime"))
+	archivePath := filepath.ToSlash(filepath.Join(dir, "Archive"))
+	externalPath := filepath.ToSlash(filepath.Join(dir, "External")) // Unplugged
+	disabledPath := filepath.ToSlash(filepath.Join(dir, "Disabled"))
+	require.NoError(t, mkdirs(mainPath, archivePath, disabledPath))
+
+	settings := &models.LibrarySettings{
+		LibraryPath:  mainPath,
+		LibraryPaths: []string{archivePath, externalPath, disabledPath},
+		LibraryRoots: models.LibraryRoots{
+			{Path: archivePath, Enabled: true, ReadOnly: true, Priority: 3},
+			{Path: externalPath, Enabled: true, Priority: 2},
+			{Path: disabledPath, Enabled: false, Priority: 1},
+		},
+	}
+
+	// The writable and online root with the highest priority
+	destination, err := ResolveDownloadDestination(settings, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, mainPath, destination)
+
+	destination, err = ResolveDownloadDestination(settings, mainPath+"/Show", nil)
+	require.NoError(t, err)
+	assert.Equal(t, mainPath+"/Show", destination)
+
+	destination, err = ResolveDownloadDestination(settings, filepath.Join(dir, "Downloads"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Downloads"), destination, "destinations outside the library are kept")
+
+	for _, path := range []string{archivePath, externalPath, disabledPath} {
+		_, err = ResolveDownloadDestination(settings, path+"/Show", nil)
+		assert.Error(t, err, path)
+	}
+
+	// No root files can be downloaded to
+	settings.LibraryRoots = append(settings.LibraryRoots, &models.LibraryRoot{Path: mainPath, Enabled: true, ReadOnly: true})
+	_, err = ResolveDownloadDestination(settings, "", nil)
+	assert.Error(t, err)
+}
+
+func mkdirs(paths ...string) error {
+	for _, path := range paths {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
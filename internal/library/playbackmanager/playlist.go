@@ -1,6 +1,7 @@
 package playbackmanager
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"github.com/rs/zerolog"
@@ -133,14 +134,14 @@ func (h *playlistHub) onVideoStart(currListEntry *anilist.AnimeListEntry, currLf
 	// Refresh current playlist state
 	playlistState := &PlaylistState{}
 	playlistState.Current = &PlaylistStateItem{
-		Name:       fmt.Sprintf("%s - Episode %d", currListEntry.GetMedia().GetPreferredTitle(), currLf.GetEpisodeNumber()),
+		Name:       getPlaylistItemName(currListEntry.GetMedia(), currLf),
 		MediaImage: currListEntry.GetMedia().GetCoverImageSafe(),
 	}
 	if h.nextLocalFile != nil {
 		lfe, found := h.playbackManager.animeCollection.MustGet().GetListEntryFromAnimeId(h.nextLocalFile.MediaId)
 		if found {
 			playlistState.Next = &PlaylistStateItem{
-				Name:       fmt.Sprintf("%s - Episode %d", lfe.GetMedia().GetPreferredTitle(), h.nextLocalFile.GetEpisodeNumber()),
+				Name:       getPlaylistItemName(lfe.GetMedia(), h.nextLocalFile),
 				MediaImage: lfe.GetMedia().GetCoverImageSafe(),
 			}
 		}
@@ -221,3 +222,14 @@ func (h *playlistHub) onTrackingError() {
 
 	return
 }
+
+// getPlaylistItemName returns the name of a playlist item, e.g. "Title - Episode 1", "Title - Special 2", "Title - C1"
+func getPlaylistItemName(media *anilist.BaseAnime, lf *anime.LocalFile) string {
+	switch lf.GetType() {
+	case anime.LocalFileTypeSpecial:
+		return fmt.Sprintf("%s - Special %d", media.GetPreferredTitle(), lf.GetEpisodeNumber())
+	case anime.LocalFileTypeNC:
+		return fmt.Sprintf("%s - %s", media.GetPreferredTitle(), cmp.Or(lf.GetAniDBEpisode(), lf.GetParsedTitle()))
+	}
+	return fmt.Sprintf("%s - Episode %d", media.GetPreferredTitle(), lf.GetEpisodeNumber())
+}
@@ -7,6 +7,7 @@ import (
 	"seanime/internal/continuity"
 	discordrpc_presence "seanime/internal/discordrpc/presence"
 	"seanime/internal/events"
+	"seanime/internal/eventstream"
 	"seanime/internal/library/anime"
 	"seanime/internal/mediaplayers/mediaplayer"
 	"seanime/internal/util"
@@ -67,6 +68,8 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 					Int("episode", pm.currentLocalFile.MustGet().GetEpisodeNumber()).
 					Msg("playback manager: Playback started")
 
+				pm.publishPlaybackEvent(eventstream.TypePlaybackStarted, pm.getLocalFilePlaybackState(status))
+
 				pm.continuityManager.SetExternalPlayerEpisodeDetails(&continuity.ExternalPlayerEpisodeDetails{
 					EpisodeNumber: pm.currentLocalFile.MustGet().GetEpisodeNumber(),
 					MediaId:       pm.currentMediaListEntry.MustGet().GetMedia().GetID(),
@@ -105,6 +108,7 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 				// Send the playback state with the `ProgressUpdated` flag
 				// The client will use this to notify the user if the progress has been updated
 				pm.wsEventManager.SendEvent(events.PlaybackManagerProgressVideoCompleted, _ps)
+				pm.publishPlaybackEvent(eventstream.TypePlaybackCompleted, _ps)
 				// Push the video playback state to the history
 				pm.historyMap[status.Filename] = _ps
 
@@ -119,6 +123,7 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 
 				pm.Logger.Debug().Msg("playback manager: Received tracking stopped event")
 				pm.wsEventManager.SendEvent(events.PlaybackManagerProgressTrackingStopped, reason)
+				eventstream.GlobalBroker.Publish(eventstream.TypePlaybackStopped, &eventstream.PlaybackStoppedPayload{Reason: reason})
 
 				// Find the next episode and set it to [PlaybackManager.nextEpisodeLocalFile]
 				if pm.currentMediaListEntry.IsPresent() && pm.currentLocalFile.IsPresent() && pm.currentLocalFileWrapperEntry.IsPresent() {
@@ -201,6 +206,7 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 				pm.Logger.Debug().Msg("playback manager: Tracking started for stream")
 				// Send event to the client
 				pm.wsEventManager.SendEvent(events.PlaybackManagerProgressTrackingStarted, _ps)
+				pm.publishPlaybackEvent(eventstream.TypePlaybackStarted, _ps)
 
 				pm.continuityManager.SetExternalPlayerEpisodeDetails(&continuity.ExternalPlayerEpisodeDetails{
 					EpisodeNumber: pm.currentStreamEpisode.MustGet().GetProgressNumber(),
@@ -262,6 +268,7 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 				// Send the playback state with the `ProgressUpdated` flag
 				// The client will use this to notify the user if the progress has been updated
 				pm.wsEventManager.SendEvent(events.PlaybackManagerProgressVideoCompleted, _ps)
+				pm.publishPlaybackEvent(eventstream.TypePlaybackCompleted, _ps)
 				// Push the video playback state to the history
 				pm.historyMap[status.Filename] = _ps
 
@@ -279,6 +286,7 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 
 				pm.Logger.Debug().Msg("playback manager: Received tracking stopped event")
 				pm.wsEventManager.SendEvent(events.PlaybackManagerProgressTrackingStopped, reason)
+				eventstream.GlobalBroker.Publish(eventstream.TypePlaybackStopped, &eventstream.PlaybackStoppedPayload{Reason: reason})
 
 				// ------- Discord ------- //
 				if pm.discordPresence != nil && !pm.isOffline {
@@ -368,6 +376,10 @@ func (pm *PlaybackManager) autoSyncCurrentProgress(_ps *PlaybackState) {
 		if pm.currentMediaListEntry.IsAbsent() || pm.currentLocalFileWrapperEntry.IsAbsent() || pm.currentLocalFile.IsAbsent() {
 			return
 		}
+		// Specials and NC files are not part of the AniList progress
+		if !pm.currentLocalFile.MustGet().IsMain() {
+			return
+		}
 		// Check if we should update the progress
 		// If the current progress is lower than the episode progress number
 		epProgressNum := pm.currentLocalFileWrapperEntry.MustGet().GetProgressNumber(pm.currentLocalFile.MustGet())
@@ -396,6 +408,7 @@ func (pm *PlaybackManager) autoSyncCurrentProgress(_ps *PlaybackState) {
 	} else {
 		_ps.ProgressUpdated = true
 		pm.wsEventManager.SendEvent(events.PlaybackManagerProgressUpdated, _ps)
+		pm.publishPlaybackEvent(eventstream.TypeProgressUpdated, *_ps)
 	}
 
 }
@@ -418,13 +431,14 @@ func (pm *PlaybackManager) SyncCurrentProgress() error {
 		var _ps PlaybackState
 		switch pm.currentPlaybackType {
 		case LocalFilePlayback:
-			pm.getLocalFilePlaybackState(pm.currentMediaPlaybackStatus)
+			_ps = pm.getLocalFilePlaybackState(pm.currentMediaPlaybackStatus)
 		case StreamPlayback:
-			pm.getStreamPlaybackState(pm.currentMediaPlaybackStatus)
+			_ps = pm.getStreamPlaybackState(pm.currentMediaPlaybackStatus)
 		}
 		_ps.ProgressUpdated = true
 		pm.historyMap[pm.currentMediaPlaybackStatus.Filename] = _ps
 		pm.wsEventManager.SendEvent(events.PlaybackManagerProgressUpdated, _ps)
+		pm.publishPlaybackEvent(eventstream.TypeProgressUpdated, _ps)
 	}
 
 	pm.refreshAnimeCollectionFunc()
@@ -453,6 +467,10 @@ func (pm *PlaybackManager) updateProgress() (err error) {
 
 		defer util.HandlePanicInModuleWithError("playbackmanager/updateProgress", &err)
 
+		if !pm.currentLocalFile.MustGet().IsMain() {
+			return errors.New("specials and NC files are not tracked on AniList")
+		}
+
 		/// Online
 		mediaId = pm.currentMediaListEntry.MustGet().GetMedia().GetID()
 		epNum = pm.currentLocalFileWrapperEntry.MustGet().GetProgressNumber(pm.currentLocalFile.MustGet())
@@ -515,3 +533,20 @@ func (pm *PlaybackManager) updateProgress() (err error) {
 
 	return nil
 }
+
+// publishPlaybackEvent sends a playback state to the public event stream.
+func (pm *PlaybackManager) publishPlaybackEvent(t eventstream.Type, ps PlaybackState) {
+	streaming := pm.currentPlaybackType == StreamPlayback
+	filename := ps.Filename
+	if streaming {
+		filename = ""
+	}
+	eventstream.GlobalBroker.Publish(t, &eventstream.PlaybackPayload{
+		MediaId:              ps.MediaId,
+		MediaTitle:           ps.MediaTitle,
+		EpisodeNumber:        ps.EpisodeNumber,
+		Filename:             filename,
+		CompletionPercentage: ps.CompletionPercentage,
+		Streaming:            streaming,
+	})
+}
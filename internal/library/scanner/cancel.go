@@ -0,0 +1,86 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+)
+
+// ScanCanceller keeps track of the running scans so that they can be cancelled, e.g. from the API.
+// Scans started by the handlers and the auto scanner share the same canceller.
+type ScanCanceller struct {
+	mu      sync.Mutex
+	nextId  int
+	cancels map[int]context.CancelFunc
+	saveMu  sync.Mutex // Held by the scans that save the local files, see Lock
+}
+
+func NewScanCanceller() *ScanCanceller {
+	return &ScanCanceller{
+		cancels: make(map[int]context.CancelFunc),
+	}
+}
+
+// Start returns the context of a new scan, it is cancelled when Cancel is called.
+// done must be called once the scan has returned.
+func (sc *ScanCanceller) Start(parent context.Context) (ctx context.Context, done func()) {
+	ctx, cancel := context.WithCancel(parent)
+	if sc == nil {
+		return ctx, cancel
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	id := sc.nextId
+	sc.nextId++
+	sc.cancels[id] = cancel
+
+	return ctx, func() {
+		sc.mu.Lock()
+		defer sc.mu.Unlock()
+		delete(sc.cancels, id)
+		cancel()
+	}
+}
+
+// Cancel cancels all the running scans.
+// It returns false if no scan was running.
+func (sc *ScanCanceller) Cancel() bool {
+	if sc == nil {
+		return false
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	// The scans are removed by their done function once they have returned
+	for _, cancel := range sc.cancels {
+		cancel()
+	}
+	return len(sc.cancels) > 0
+}
+
+// IsScanning returns true if a scan is running.
+func (sc *ScanCanceller) IsScanning() bool {
+	if sc == nil {
+		return false
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	return len(sc.cancels) > 0
+}
+
+// Lock waits until no other scan that saves the local files is running.
+// Scans read the existing local files and save the whole set, so they are run one at a time,
+// otherwise the last scan to finish would overwrite the files saved by the others.
+// The lock should be held from reading the existing local files until saving them, unlock must then be called.
+func (sc *ScanCanceller) Lock() (unlock func()) {
+	if sc == nil {
+		return func() {}
+	}
+
+	sc.saveMu.Lock()
+	return sc.saveMu.Unlock
+}
@@ -0,0 +1,139 @@
+package scanner
+
+import (
+	"context"
+	"seanime/internal/api/anilist"
+	"seanime/internal/library/anime"
+	"seanime/internal/util/limiter"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// MediaCache holds the media fetched by a previous scan.
+// Delta scans match the files against it instead of fetching the user's collection again.
+type MediaCache struct {
+	MediaContainer     *MediaContainer
+	AnimeCollection    *anilist.AnimeCollectionWithRelations
+	CompleteAnimeCache *anilist.CompleteAnimeCache
+	CreatedAt          time.Time
+}
+
+// scanMedia holds the media used to match the local files during a scan.
+type scanMedia struct {
+	container       *MediaContainer
+	animeCollection *anilist.AnimeCollectionWithRelations
+	unknownMediaIds []int
+	cached          bool // Whether the media come from Scanner.MediaCache
+}
+
+// getScanMedia returns the media used to match the local files.
+// If Scanner.MediaCache is set, its media are reused. Otherwise, the media are fetched and cached when not in enhanced mode,
+// since they then only depend on the user's collection.
+func (scn *Scanner) getScanMedia(ctx context.Context, localFiles []*anime.LocalFile, completeAnimeCache *anilist.CompleteAnimeCache, anilistRateLimiter *limiter.Limiter) (*scanMedia, error) {
+
+	if scn.MediaCache != nil && scn.MediaCache.MediaContainer != nil {
+		// Copy the container so that the scan logger of the previous scan is not used
+		mc := *scn.MediaCache.MediaContainer
+		mc.ScanLogger = scn.ScanLogger
+
+		scn.Logger.Debug().
+			Any("count", len(mc.NormalizedMedia)).
+			Time("cachedAt", scn.MediaCache.CreatedAt).
+			Msg("media container: Using cached media")
+
+		return &scanMedia{
+			container:       &mc,
+			animeCollection: scn.MediaCache.AnimeCollection,
+			unknownMediaIds: make([]int, 0),
+			cached:          true,
+		}, nil
+	}
+
+	mf, err := NewMediaFetcher(&MediaFetcherOptions{
+		Enhanced:               scn.Enhanced,
+		Platform:               scn.Platform,
+		MetadataProvider:       scn.MetadataProvider,
+		LocalFiles:             localFiles,
+		CompleteAnimeCache:     completeAnimeCache,
+		Logger:                 scn.Logger,
+		AnilistRateLimiter:     anilistRateLimiter,
+		DisableAnimeCollection: false,
+		ScanLogger:             scn.ScanLogger,
+		ctx:                    ctx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new container for media
+	mc := NewMediaContainer(&MediaContainerOptions{
+		AllMedia:   mf.AllMedia,
+		ScanLogger: scn.ScanLogger,
+	})
+
+	scn.Logger.Debug().
+		Any("count", len(mc.NormalizedMedia)).
+		Msg("media container: Media container created")
+
+	if !scn.Enhanced {
+		scn.MediaCache = &MediaCache{
+			MediaContainer:     mc,
+			AnimeCollection:    mf.AnimeCollectionWithRelations,
+			CompleteAnimeCache: completeAnimeCache,
+			CreatedAt:          time.Now(),
+		}
+	}
+
+	return &scanMedia{
+		container:       mc,
+		animeCollection: mf.AnimeCollectionWithRelations,
+		unknownMediaIds: mf.UnknownMediaIds,
+	}, nil
+}
+
+// matchLocalFiles matches the local files against the media of the container.
+func (scn *Scanner) matchLocalFiles(ctx context.Context, localFiles []*anime.LocalFile, mc *MediaContainer, completeAnimeCache *anilist.CompleteAnimeCache, rules *MatchingRules, hashMatches *HashMatches) error {
+	matcher := &Matcher{
+		LocalFiles:         localFiles,
+		MediaContainer:     mc,
+		CompleteAnimeCache: completeAnimeCache,
+		Logger:             scn.Logger,
+		ScanLogger:         scn.ScanLogger,
+		ScanSummaryLogger:  scn.ScanSummaryLogger,
+		Algorithm:          scn.MatchingAlgorithm,
+		Threshold:          scn.MatchingThreshold,
+		Rules:              rules,
+		HashMatches:        hashMatches,
+		Roots:              scn.Roots,
+		ctx:                ctx,
+	}
+	return matcher.MatchLocalFilesWithMedia()
+}
+
+// rematchWithFreshMedia matches the files that could not be matched against cached media again, using freshly fetched media.
+// They may belong to media added to the user's collection after the media were cached.
+func (scn *Scanner) rematchWithFreshMedia(ctx context.Context, sm *scanMedia, localFiles []*anime.LocalFile, completeAnimeCache *anilist.CompleteAnimeCache, anilistRateLimiter *limiter.Limiter, rules *MatchingRules, hashMatches *HashMatches) (*scanMedia, error) {
+	unmatched := lo.Filter(localFiles, func(lf *anime.LocalFile, _ int) bool {
+		return lf.MediaId == 0
+	})
+	if len(unmatched) == 0 {
+		return sm, nil
+	}
+
+	scn.Logger.Debug().
+		Int("count", len(unmatched)).
+		Msg("scanner: Files could not be matched against cached media, fetching media")
+
+	scn.MediaCache = nil
+	fresh, err := scn.getScanMedia(ctx, unmatched, completeAnimeCache, anilistRateLimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := scn.matchLocalFiles(ctx, unmatched, fresh.container, completeAnimeCache, rules, hashMatches); err != nil {
+		return nil, err
+	}
+
+	return fresh, nil
+}
@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"seanime/internal/api/anilist"
+	"seanime/internal/library/anime"
+	"seanime/internal/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_DeltaScanMedia(t *testing.T) {
+	cachedContainer := NewMediaContainer(&MediaContainerOptions{
+		AllMedia: []*anilist.CompleteAnime{},
+	})
+	cache := &MediaCache{
+		MediaContainer:     cachedContainer,
+		AnimeCollection:    &anilist.AnimeCollectionWithRelations{},
+		CompleteAnimeCache: anilist.NewCompleteAnimeCache(),
+		CreatedAt:          time.Now(),
+	}
+
+	scanner := &Scanner{
+		Logger:     util.NewLogger(),
+		MediaCache: cache,
+	}
+
+	// No platform is set, the media must come from the cache
+	sm, err := scanner.getScanMedia(context.Background(), []*anime.LocalFile{}, cache.CompleteAnimeCache, nil)
+	require.NoError(t, err)
+	assert.True(t, sm.cached)
+	assert.Same(t, cache.AnimeCollection, sm.animeCollection)
+	assert.NotSame(t, cachedContainer, sm.container, "the cached container should not be modified")
+	assert.Same(t, cache, scanner.MediaCache)
+}
+
+func TestScanner_DeltaScanFilePaths(t *testing.T) {
+	dir := t.TempDir()
+	showDir := filepath.Join(dir, "Show")
+	require.NoError(t, os.MkdirAll(showDir, 0755))
+
+	createdPath := filepath.Join(showDir, "Show - 02.mkv")
+	require.NoError(t, os.WriteFile(createdPath, []byte{}, 0644))
+
+	scanner := &Scanner{
+		DirPath: dir,
+		Logger:  util.NewLogger(),
+		TargetPaths: []string{
+			createdPath,
+			filepath.Join(showDir, "Show - 01.mkv"), // Removed
+			filepath.Join(dir, "Removed directory"),
+		},
+	}
+
+	paths, ignoredPaths, err := scanner.getFilePaths(context.Background(), scanner.getLibraryDirs().scanned)
+	require.NoError(t, err)
+	assert.Equal(t, []string{createdPath}, paths)
+	assert.Empty(t, ignoredPaths)
+}
@@ -0,0 +1,139 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"seanime/internal/library/anime"
+	"seanime/internal/util"
+	"sync"
+	"time"
+)
+
+type (
+	// cachedFileWorker processes local files concurrently, reusing the cached result of the files
+	// whose size and modification time did not change.
+	// It is used by the FileHasher and the FileProber.
+	cachedFileWorker[E any] struct {
+		Workers int
+		Cached  []E
+		// Stat returns the path, size and modification time of the file a cached entry was computed from
+		Stat func(entry E) (string, int64, time.Time)
+		// Reuse sets the cached result on the local file, it returns false if the entry cannot be used
+		Reuse func(lf *anime.LocalFile, entry E) bool
+		// Compute sets the result on the local file and returns the entry to cache
+		Compute func(lf *anime.LocalFile, info os.FileInfo) (E, error)
+		// ctx stops the processing when the scan is cancelled, optional
+		ctx context.Context
+	}
+
+	cachedFileWorkerResult[E any] struct {
+		Computed []E // Entries of the files that were processed, to be cached
+		Reused   int
+		Failed   int
+	}
+)
+
+func (w *cachedFileWorker[E]) run(lfs []*anime.LocalFile) *cachedFileWorkerResult[E] {
+	cached := make(map[string]E, len(w.Cached))
+	for _, entry := range w.Cached {
+		path, _, _ := w.Stat(entry)
+		cached[util.NormalizePath(path)] = entry
+	}
+
+	mu := sync.Mutex{}
+	ret := &cachedFileWorkerResult[E]{
+		Computed: make([]E, 0),
+	}
+
+	jobs := make(chan *anime.LocalFile)
+	wg := sync.WaitGroup{}
+	for i := 0; i < w.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for lf := range jobs {
+				info, err := os.Stat(lf.Path)
+				if err != nil {
+					continue
+				}
+
+				if entry, ok := cached[lf.GetNormalizedPath()]; ok {
+					_, size, modTime := w.Stat(entry)
+					if size == info.Size() && modTime.Equal(info.ModTime()) && w.Reuse(lf, entry) {
+						mu.Lock()
+						ret.Reused++
+						mu.Unlock()
+						continue
+					}
+				}
+
+				entry, err := w.Compute(lf, info)
+				mu.Lock()
+				if err != nil {
+					ret.Failed++
+				} else {
+					ret.Computed = append(ret.Computed, entry)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, lf := range lfs {
+		if isCancelled(w.ctx) {
+			break
+		}
+		jobs <- lf
+	}
+	close(jobs)
+	wg.Wait()
+
+	return ret
+}
+
+// getStaleFileCachePaths returns the paths of the cached entries that are not local files anymore.
+func getStaleFileCachePaths(cachedPaths []string, lfs []*anime.LocalFile) []string {
+	current := make(map[string]struct{}, len(lfs))
+	for _, lf := range lfs {
+		current[lf.GetNormalizedPath()] = struct{}{}
+	}
+
+	ret := make([]string, 0)
+	for _, path := range cachedPaths {
+		if _, ok := current[util.NormalizePath(path)]; !ok {
+			ret = append(ret, path)
+		}
+	}
+	return ret
+}
+
+// pruneFileCaches removes the cached hashes and media info of the files that are not local files anymore.
+// lfs must be all the local files of the library.
+func (scn *Scanner) pruneFileCaches(lfs []*anime.LocalFile) {
+	if scn.FileHashCache != nil {
+		if hashes, err := scn.FileHashCache.GetFileHashes(); err == nil {
+			paths := make([]string, 0, len(hashes))
+			for _, h := range hashes {
+				paths = append(paths, h.Path)
+			}
+			if stale := getStaleFileCachePaths(paths, lfs); len(stale) > 0 {
+				if err := scn.FileHashCache.DeleteFileHashes(stale); err != nil {
+					scn.Logger.Warn().Err(err).Msg("scanner: Failed to remove stale file hashes")
+				}
+			}
+		}
+	}
+
+	if scn.FileMediaInfoCache != nil {
+		if infos, err := scn.FileMediaInfoCache.GetFileMediaInfos(); err == nil {
+			paths := make([]string, 0, len(infos))
+			for _, info := range infos {
+				paths = append(paths, info.Path)
+			}
+			if stale := getStaleFileCachePaths(paths, lfs); len(stale) > 0 {
+				if err := scn.FileMediaInfoCache.DeleteFileMediaInfos(stale); err != nil {
+					scn.Logger.Warn().Err(err).Msg("scanner: Failed to remove stale file media info")
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"seanime/internal/database/models"
+	"seanime/internal/library/anime"
+	"seanime/internal/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryFileCache implements FileHashCache and FileMediaInfoCache.
+type memoryFileCache struct {
+	hashes map[string]*models.FileHash
+	infos  map[string]*models.FileMediaInfo
+}
+
+func newMemoryFileCache() *memoryFileCache {
+	return &memoryFileCache{
+		hashes: make(map[string]*models.FileHash),
+		infos:  make(map[string]*models.FileMediaInfo),
+	}
+}
+
+func (c *memoryFileCache) GetFileHashes() ([]*models.FileHash, error) {
+	ret := make([]*models.FileHash, 0, len(c.hashes))
+	for _, h := range c.hashes {
+		ret = append(ret, h)
+	}
+	return ret, nil
+}
+
+func (c *memoryFileCache) SaveFileHashes(hashes []*models.FileHash) error {
+	for _, h := range hashes {
+		c.hashes[h.Path] = h
+	}
+	return nil
+}
+
+func (c *memoryFileCache) DeleteFileHashes(paths []string) error {
+	for _, path := range paths {
+		delete(c.hashes, path)
+	}
+	return nil
+}
+
+func (c *memoryFileCache) GetFileMediaInfos() ([]*models.FileMediaInfo, error) {
+	ret := make([]*models.FileMediaInfo, 0, len(c.infos))
+	for _, info := range c.infos {
+		ret = append(ret, info)
+	}
+	return ret, nil
+}
+
+func (c *memoryFileCache) SaveFileMediaInfos(infos []*models.FileMediaInfo) error {
+	for _, info := range infos {
+		c.infos[info.Path] = info
+	}
+	return nil
+}
+
+func (c *memoryFileCache) DeleteFileMediaInfos(paths []string) error {
+	for _, path := range paths {
+		delete(c.infos, path)
+	}
+	return nil
+}
+
+func TestScanner_PruneFileCaches(t *testing.T) {
+	dir := t.TempDir()
+	keptPath := filepath.Join(dir, "Show - 01.mkv")
+	removedPath := filepath.Join(dir, "Show - 02.mkv")
+	for _, path := range []string{keptPath, removedPath} {
+		require.NoError(t, os.WriteFile(path, []byte("abc"), 0644))
+	}
+
+	cache := newMemoryFileCache()
+	lfs := []*anime.LocalFile{
+		anime.NewLocalFile(keptPath, dir),
+		anime.NewLocalFile(removedPath, dir),
+	}
+	hasher := &FileHasher{
+		LocalFiles: lfs,
+		Cache:      cache,
+		Logger:     util.NewLogger(),
+	}
+	hasher.HashLocalFiles()
+	require.Len(t, cache.hashes, 2)
+	cache.infos[removedPath] = &models.FileMediaInfo{Path: removedPath}
+
+	scanner := &Scanner{
+		Logger:             util.NewLogger(),
+		FileHashCache:      cache,
+		FileMediaInfoCache: cache,
+	}
+	scanner.pruneFileCaches(lfs[:1])
+
+	assert.Contains(t, cache.hashes, keptPath)
+	assert.NotContains(t, cache.hashes, removedPath)
+	assert.Empty(t, cache.infos)
+}
@@ -0,0 +1,191 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"seanime/internal/database/models"
+	"seanime/internal/library/anime"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+type (
+	// HashLookupBackend identifies files by their content, like AniDB's FILE command.
+	HashLookupBackend interface {
+		// LookupFile returns the media and episode of the file with the given size and ED2K hash.
+		// It returns false if the file is unknown.
+		LookupFile(size int64, ed2k string) (*HashLookupResult, bool, error)
+	}
+
+	HashLookupResult struct {
+		MediaId int `json:"mediaId"`
+		// AniDB episode number, "S1", "C1", etc. are specials and extras
+		AniDBEpisode string `json:"aniDBEpisode"`
+	}
+
+	// JSONHashLookup is a HashLookupBackend reading the files from a JSON file.
+	//
+	//	[{ "size": 123, "ed2k": "...", "mediaId": 1, "aniDBEpisode": "1" }]
+	JSONHashLookup struct {
+		entries map[string]*HashLookupResult // By size and hash
+	}
+
+	jsonHashLookupEntry struct {
+		Size int64  `json:"size"`
+		ED2K string `json:"ed2k"`
+		HashLookupResult
+	}
+)
+
+// NewHashLookupBackend returns the lookup backend configured in the library settings, or nil if there is none.
+func NewHashLookupBackend(settings *models.LibrarySettings) (HashLookupBackend, error) {
+	if settings == nil || !settings.ScannerHashFiles || settings.ScannerHashLookupPath == "" {
+		return nil, nil
+	}
+	return NewJSONHashLookup(settings.ScannerHashLookupPath)
+}
+
+func NewJSONHashLookup(path string) (*JSONHashLookup, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*jsonHashLookupEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid hash lookup file: %w", err)
+	}
+
+	ret := &JSONHashLookup{
+		entries: make(map[string]*HashLookupResult, len(entries)),
+	}
+	for _, entry := range entries {
+		if entry == nil || entry.ED2K == "" || entry.MediaId <= 0 {
+			continue
+		}
+		result := entry.HashLookupResult
+		ret.entries[hashLookupKey(entry.Size, entry.ED2K)] = &result
+	}
+
+	return ret, nil
+}
+
+func (l *JSONHashLookup) LookupFile(size int64, ed2k string) (*HashLookupResult, bool, error) {
+	result, ok := l.entries[hashLookupKey(size, ed2k)]
+	return result, ok, nil
+}
+
+func hashLookupKey(size int64, ed2k string) string {
+	return fmt.Sprintf("%d|%s", size, strings.ToLower(ed2k))
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// HashMatches holds the local files identified by a HashLookupBackend.
+// A nil *HashMatches matches nothing.
+type HashMatches struct {
+	results map[*anime.LocalFile]*HashLookupResult // Read-only once looked up
+}
+
+// LookupHashes identifies the hashed local files using the backend.
+// Files that cannot be looked up are left to the matcher.
+func LookupHashes(backend HashLookupBackend, lfs []*anime.LocalFile, logger *zerolog.Logger) *HashMatches {
+	ret := &HashMatches{
+		results: make(map[*anime.LocalFile]*HashLookupResult),
+	}
+	if backend == nil {
+		return ret
+	}
+
+	for _, lf := range lfs {
+		if lf.Hashes == nil {
+			continue
+		}
+		result, found, err := backend.LookupFile(lf.Hashes.Size, lf.Hashes.ED2K)
+		if err != nil {
+			logger.Warn().Err(err).Str("path", lf.Path).Msg("scanner: Failed to look up file hash")
+			continue
+		}
+		if !found || result == nil || result.MediaId <= 0 {
+			continue
+		}
+		ret.results[lf] = result
+	}
+
+	return ret
+}
+
+// Get returns the lookup result of the local file.
+func (h *HashMatches) Get(lf *anime.LocalFile) (*HashLookupResult, bool) {
+	if h == nil {
+		return nil, false
+	}
+	result, ok := h.results[lf]
+	return result, ok
+}
+
+// MediaIds returns the IDs of the media the files were identified as.
+func (h *HashMatches) MediaIds() []int {
+	if h == nil {
+		return nil
+	}
+	ret := make([]int, 0, len(h.results))
+	for _, result := range h.results {
+		ret = append(ret, result.MediaId)
+	}
+	return ret
+}
+
+// ApplyEpisodes replaces the hydrated episode of the identified files with the looked up one.
+// Files that were not kept matched to the looked up media are left as-is.
+func (h *HashMatches) ApplyEpisodes(lfs []*anime.LocalFile) {
+	if h == nil {
+		return
+	}
+	for _, lf := range lfs {
+		result, ok := h.Get(lf)
+		if !ok || result.AniDBEpisode == "" || lf.MediaId != result.MediaId {
+			continue
+		}
+		metadata, err := hashLookupMetadata(result.AniDBEpisode)
+		if err != nil {
+			continue
+		}
+		lf.Metadata = metadata
+	}
+}
+
+// hashLookupMetadata converts an AniDB episode number to local file metadata.
+// "S" episodes are specials, other prefixed episodes (credits, trailers, etc.) are NC.
+func hashLookupMetadata(aniDBEpisode string) (*anime.LocalFileMetadata, error) {
+	aniDBEpisode = strings.ToUpper(strings.TrimSpace(aniDBEpisode))
+	if aniDBEpisode == "" {
+		return nil, errors.New("empty episode")
+	}
+
+	if episode, err := strconv.Atoi(aniDBEpisode); err == nil {
+		return &anime.LocalFileMetadata{
+			Episode:      episode,
+			AniDBEpisode: aniDBEpisode,
+			Type:         anime.LocalFileTypeMain,
+		}, nil
+	}
+
+	episode, err := strconv.Atoi(aniDBEpisode[1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid episode: %s", aniDBEpisode)
+	}
+	ret := &anime.LocalFileMetadata{
+		Episode:      episode,
+		AniDBEpisode: aniDBEpisode,
+		Type:         anime.LocalFileTypeNC,
+	}
+	if aniDBEpisode[0] == 'S' {
+		ret.Type = anime.LocalFileTypeSpecial
+	}
+	return ret, nil
+}
@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"seanime/internal/database/models"
+	"seanime/internal/library/anime"
+	"seanime/internal/library/filesystem"
+	"seanime/internal/library/summary"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// FileHashCache stores the hashes computed by the scanner so that unchanged files are not read again.
+type FileHashCache interface {
+	GetFileHashes() ([]*models.FileHash, error)
+	SaveFileHashes(hashes []*models.FileHash) error
+	DeleteFileHashes(paths []string) error
+}
+
+// FileHasher computes the CRC32 and ED2K hashes of local files
+// and flags the files whose content does not match the CRC32 written in their name.
+type FileHasher struct {
+	LocalFiles        []*anime.LocalFile
+	Cache             FileHashCache // optional
+	Workers           int           // Number of files hashed concurrently, defaults to 2
+	Logger            *zerolog.Logger
+	ScanLogger        *ScanLogger                // optional
+	ScanSummaryLogger *summary.ScanSummaryLogger // optional
+	// ctx stops the hashing when the scan is cancelled, optional
+	ctx context.Context
+}
+
+var checksumRegex = regexp.MustCompile(`^[0-9A-Fa-f]{8}$`)
+
+// HashLocalFiles sets the hashes of the local files.
+// Cached hashes are reused if the size and modification time of the file did not change.
+func (fh *FileHasher) HashLocalFiles() {
+	start := time.Now()
+
+	workers := fh.Workers
+	if workers <= 0 {
+		workers = 2
+	}
+
+	var cached []*models.FileHash
+	if fh.Cache != nil {
+		hashes, err := fh.Cache.GetFileHashes()
+		if err != nil {
+			fh.Logger.Warn().Err(err).Msg("scanner: Failed to get cached file hashes")
+		}
+		cached = hashes
+	}
+
+	worker := &cachedFileWorker[*models.FileHash]{
+		Workers: workers,
+		Cached:  cached,
+		Stat: func(h *models.FileHash) (string, int64, time.Time) {
+			return h.Path, h.Size, h.ModTime
+		},
+		Reuse: func(lf *anime.LocalFile, h *models.FileHash) bool {
+			lf.Hashes = &anime.LocalFileHashes{CRC32: h.CRC32, ED2K: h.ED2K, Size: h.Size}
+			return true
+		},
+		Compute: func(lf *anime.LocalFile, info os.FileInfo) (*models.FileHash, error) {
+			hashes, err := filesystem.HashFile(lf.Path)
+			if err != nil {
+				fh.Logger.Warn().Err(err).Str("path", lf.Path).Msg("scanner: Failed to hash file")
+				return nil, err
+			}
+			lf.Hashes = &anime.LocalFileHashes{CRC32: hashes.CRC32, ED2K: hashes.ED2K, Size: hashes.Size}
+			return &models.FileHash{
+				Path:    lf.Path,
+				Size:    hashes.Size,
+				ModTime: info.ModTime(),
+				CRC32:   hashes.CRC32,
+				ED2K:    hashes.ED2K,
+			}, nil
+		},
+		ctx: fh.ctx,
+	}
+	res := worker.run(fh.LocalFiles)
+
+	for _, lf := range fh.LocalFiles {
+		fh.verifyChecksum(lf)
+	}
+
+	if fh.Cache != nil {
+		if err := fh.Cache.SaveFileHashes(res.Computed); err != nil {
+			fh.Logger.Warn().Err(err).Msg("scanner: Failed to cache file hashes")
+		}
+	}
+
+	if fh.ScanLogger != nil {
+		fh.ScanLogger.LogFileHasher(zerolog.InfoLevel).
+			Int64("ms", time.Since(start).Milliseconds()).
+			Int("computed", len(res.Computed)).
+			Int("cached", res.Reused).
+			Int("failed", res.Failed).
+			Msg("Finished hashing files")
+	}
+}
+
+// verifyChecksum flags the local file if the CRC32 written in its name differs from the computed one.
+func (fh *FileHasher) verifyChecksum(lf *anime.LocalFile) {
+	// Only verify checksums that look like a CRC32
+	if lf.Hashes == nil || lf.ParsedData == nil || !checksumRegex.MatchString(lf.ParsedData.Checksum) {
+		return
+	}
+	if strings.EqualFold(lf.ParsedData.Checksum, lf.Hashes.CRC32) {
+		return
+	}
+
+	lf.Hashes.ChecksumMismatch = true
+	fh.ScanSummaryLogger.LogChecksumMismatch(lf, strings.ToUpper(lf.ParsedData.Checksum), lf.Hashes.CRC32)
+	if fh.ScanLogger != nil {
+		fh.ScanLogger.LogFileHasher(zerolog.WarnLevel).
+			EmbedObject(scanLogFile{lf}).
+			Str("expected", lf.ParsedData.Checksum).
+			Str("actual", lf.Hashes.CRC32).
+			Msg("Checksum mismatch")
+	}
+}
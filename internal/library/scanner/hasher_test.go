@@ -0,0 +1,93 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"seanime/internal/database/models"
+	"seanime/internal/library/anime"
+	"seanime/internal/library/summary"
+	"seanime/internal/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileHasher(t *testing.T) {
+	dir := t.TempDir()
+
+	// CRC32 of "abc" is 352441C2
+	validPath := filepath.Join(dir, "[Group] Show - 01 [352441C2].mkv")
+	corruptedPath := filepath.Join(dir, "[Group] Show - 02 [00000000].mkv")
+	require.NoError(t, os.WriteFile(validPath, []byte("abc"), 0644))
+	require.NoError(t, os.WriteFile(corruptedPath, []byte("abc"), 0644))
+
+	cache := newMemoryFileCache()
+	summaryLogger := summary.NewScanSummaryLogger()
+
+	lfs := []*anime.LocalFile{
+		anime.NewLocalFile(validPath, dir),
+		anime.NewLocalFile(corruptedPath, dir),
+	}
+	hasher := &FileHasher{
+		LocalFiles:        lfs,
+		Cache:             cache,
+		Logger:            util.NewLogger(),
+		ScanSummaryLogger: summaryLogger,
+	}
+	hasher.HashLocalFiles()
+
+	require.NotNil(t, lfs[0].Hashes)
+	assert.Equal(t, "352441C2", lfs[0].Hashes.CRC32)
+	assert.Equal(t, "a448017aaf21d8525fc10ae87aa6729d", lfs[0].Hashes.ED2K)
+	assert.False(t, lfs[0].Hashes.ChecksumMismatch)
+	require.NotNil(t, lfs[1].Hashes)
+	assert.True(t, lfs[1].Hashes.ChecksumMismatch)
+	assert.Len(t, summaryLogger.Logs, 1)
+	assert.Len(t, cache.hashes, 2)
+
+	// Unchanged files are not read again
+	cache.hashes[validPath].ED2K = "cached"
+	lf := anime.NewLocalFile(validPath, dir)
+	hasher.LocalFiles = []*anime.LocalFile{lf}
+	hasher.HashLocalFiles()
+	assert.Equal(t, "cached", lf.Hashes.ED2K)
+}
+
+func TestJSONHashLookup(t *testing.T) {
+	dir := t.TempDir()
+	lookupPath := filepath.Join(dir, "hashes.json")
+	require.NoError(t, os.WriteFile(lookupPath, []byte(`[
+		{"size": 3, "ed2k": "A448017AAF21D8525FC10AE87AA6729D", "mediaId": 1, "aniDBEpisode": "2"},
+		{"size": 4, "ed2k": "31d6cfe0d16ae931b73c59d7e0c089c0", "mediaId": 1, "aniDBEpisode": "S1"}
+	]`), 0644))
+
+	backend, err := NewHashLookupBackend(&models.LibrarySettings{ScannerHashFiles: true, ScannerHashLookupPath: lookupPath})
+	require.NoError(t, err)
+
+	main := &anime.LocalFile{Path: "/lib/a.mkv", Hashes: &anime.LocalFileHashes{Size: 3, ED2K: "a448017aaf21d8525fc10ae87aa6729d"}}
+	special := &anime.LocalFile{Path: "/lib/b.mkv", Hashes: &anime.LocalFileHashes{Size: 4, ED2K: "31d6cfe0d16ae931b73c59d7e0c089c0"}}
+	unknown := &anime.LocalFile{Path: "/lib/c.mkv", Hashes: &anime.LocalFileHashes{Size: 5, ED2K: "31d6cfe0d16ae931b73c59d7e0c089c0"}}
+	notHashed := &anime.LocalFile{Path: "/lib/d.mkv"}
+	lfs := []*anime.LocalFile{main, special, unknown, notHashed}
+
+	matches := LookupHashes(backend, lfs, util.NewLogger())
+	_, ok := matches.Get(unknown)
+	assert.False(t, ok)
+	_, ok = matches.Get(notHashed)
+	assert.False(t, ok)
+	assert.Equal(t, []int{1, 1}, matches.MediaIds())
+
+	// The matcher assigns the media, the looked up episodes replace the hydrated ones
+	main.MediaId = 1
+	special.MediaId = 1
+	main.Metadata = &anime.LocalFileMetadata{Episode: 5, AniDBEpisode: "5", Type: anime.LocalFileTypeMain}
+	matches.ApplyEpisodes(lfs)
+	assert.Equal(t, &anime.LocalFileMetadata{Episode: 2, AniDBEpisode: "2", Type: anime.LocalFileTypeMain}, main.Metadata)
+	assert.Equal(t, &anime.LocalFileMetadata{Episode: 1, AniDBEpisode: "S1", Type: anime.LocalFileTypeSpecial}, special.Metadata)
+
+	// No backend is configured when hashing is disabled
+	backend, err = NewHashLookupBackend(&models.LibrarySettings{ScannerHashLookupPath: lookupPath})
+	require.NoError(t, err)
+	assert.Nil(t, backend)
+}
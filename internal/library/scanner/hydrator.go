@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"context"
 	"errors"
 	"github.com/rs/zerolog"
 	"github.com/samber/lo"
@@ -15,6 +16,7 @@ import (
 	"seanime/internal/util/comparison"
 	"seanime/internal/util/limiter"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -31,6 +33,10 @@ type FileHydrator struct {
 	ScanLogger         *ScanLogger                // optional
 	ScanSummaryLogger  *summary.ScanSummaryLogger // optional
 	ForceMediaId       int                        // optional - force all local files to have this media ID
+	// ctx stops the hydration when the scan is cancelled, optional
+	ctx context.Context
+	// onHydrated is called with the number of files of each hydrated media group, optional
+	onHydrated func(count int)
 }
 
 // HydrateMetadata will hydrate the metadata of each LocalFile with the metadata of the matched anilist.BaseAnime.
@@ -59,9 +65,15 @@ func (fh *FileHydrator) HydrateMetadata() {
 	p := pool.New()
 	for mId, files := range groups {
 		p.Go(func() {
+			if isCancelled(fh.ctx) {
+				return
+			}
 			if len(files) > 0 {
 				fh.hydrateGroupMetadata(mId, files, rateLimiter)
 			}
+			if fh.onHydrated != nil {
+				fh.onHydrated(len(files))
+			}
 		})
 	}
 	p.Wait()
@@ -97,6 +109,28 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 	// Tree analysis used for episode normalization
 	var mediaTreeAnalysis *MediaTreeAnalysis
 	treeFetched := false
+	// AniDB metadata used to map specials and NC files to their AniDB episode, fetched when needed
+	var animeMetadata *metadata.AnimeMetadata
+	animeMetadataFetched := false
+	getAnimeMetadata := func() *metadata.AnimeMetadata {
+		if animeMetadataFetched || fh.MetadataProvider == nil {
+			return animeMetadata
+		}
+		animeMetadataFetched = true
+		rateLimiter.Wait()
+		md, err := fh.MetadataProvider.GetAnimeMetadata(metadata.AnilistPlatform, mId)
+		if err != nil {
+			if fh.ScanLogger != nil {
+				fh.ScanLogger.LogFileHydrator(zerolog.WarnLevel).
+					Int("mediaId", mId).
+					Str("error", err.Error()).
+					Msg("Could not fetch AniDB metadata, specials and NC files will not be mapped to AniDB episodes")
+			}
+			return nil
+		}
+		animeMetadata = md
+		return animeMetadata
+	}
 
 	// Process each local file in the group sequentially
 	lo.ForEach(lfs, func(lf *anime.LocalFile, index int) {
@@ -106,7 +140,8 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 			/*Log*/
 			if fh.ScanLogger != nil {
 				fh.ScanLogger.LogFileHydrator(zerolog.ErrorLevel).
-					Str("filename", lf.Name).
+					EmbedObject(scanLogFile{lf}).
+					Str("decision", ScanLogDecisionUnmatched).
 					Msg("Panic occurred, file un-matched")
 			}
 			fh.ScanSummaryLogger.LogPanic(lf, stackTrace)
@@ -123,17 +158,17 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 		}
 
 		// NC metadata
-		if comparison.ValueContainsNC(lf.Name) {
-			lf.Metadata.Episode = 0
-			lf.Metadata.AniDBEpisode = ""
+		if ncType, ncNumber, ok := comparison.ExtractNC(lf.Name); ok {
 			lf.Metadata.Type = anime.LocalFileTypeNC
+			lf.Metadata.Episode, lf.Metadata.AniDBEpisode = findNCEpisode(getAnimeMetadata(), ncType, ncNumber)
 
 			/*Log */
 			if fh.ScanLogger != nil {
-				fh.logFileHydration(zerolog.DebugLevel, lf, mId, episode).
+				fh.logFileClassification(zerolog.DebugLevel, lf, mId, episode).
+					Str("ncType", string(ncType)).
 					Msg("File has been marked as NC")
 			}
-			fh.ScanSummaryLogger.LogMetadataNC(lf)
+			fh.ScanSummaryLogger.LogMetadataNC(lf, lf.Metadata.AniDBEpisode)
 			return
 		}
 
@@ -149,6 +184,9 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 					lf.Metadata.Episode = episode
 					lf.Metadata.AniDBEpisode = "S" + strconv.Itoa(episode)
 				}
+			} else if ep, aniDBEp, found := findSpecialEpisodeByTitle(getAnimeMetadata(), lf.ParsedData.EpisodeTitle); found {
+				lf.Metadata.Episode = ep
+				lf.Metadata.AniDBEpisode = aniDBEp
 			} else {
 				lf.Metadata.Episode = 1
 				lf.Metadata.AniDBEpisode = "S1"
@@ -156,7 +194,7 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 
 			/*Log */
 			if fh.ScanLogger != nil {
-				fh.logFileHydration(zerolog.DebugLevel, lf, mId, episode).
+				fh.logFileClassification(zerolog.DebugLevel, lf, mId, episode).
 					Msg("File has been marked as special")
 			}
 			fh.ScanSummaryLogger.LogMetadataSpecial(lf, lf.Metadata.Episode, lf.Metadata.AniDBEpisode)
@@ -169,7 +207,7 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 
 			/*Log */
 			if fh.ScanLogger != nil {
-				fh.logFileHydration(zerolog.DebugLevel, lf, mId, episode).
+				fh.logFileClassification(zerolog.DebugLevel, lf, mId, episode).
 					Msg("File has been marked as main")
 			}
 			fh.ScanSummaryLogger.LogMetadataMain(lf, lf.Metadata.Episode, lf.Metadata.AniDBEpisode)
@@ -190,7 +228,7 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 
 				/*Log */
 				if fh.ScanLogger != nil {
-					fh.logFileHydration(zerolog.DebugLevel, lf, mId, episode).
+					fh.logFileClassification(zerolog.DebugLevel, lf, mId, episode).
 						Msg("File has been marked as main")
 				}
 				fh.ScanSummaryLogger.LogMetadataEpisodeZero(lf, lf.Metadata.Episode, lf.Metadata.AniDBEpisode)
@@ -202,7 +240,7 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 
 			/*Log */
 			if fh.ScanLogger != nil {
-				fh.logFileHydration(zerolog.DebugLevel, lf, mId, episode).
+				fh.logFileClassification(zerolog.DebugLevel, lf, mId, episode).
 					Msg("File has been marked as main")
 			}
 			fh.ScanSummaryLogger.LogMetadataMain(lf, lf.Metadata.Episode, lf.Metadata.AniDBEpisode)
@@ -218,7 +256,7 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 
 			/*Log */
 			if fh.ScanLogger != nil {
-				fh.logFileHydration(zerolog.WarnLevel, lf, mId, episode).
+				fh.logFileClassification(zerolog.WarnLevel, lf, mId, episode).
 					Str("warning", "File's episode number is higher than the media's episode count, but the media only has 1 episode").
 					Msg("File has been marked as main")
 			}
@@ -233,7 +271,7 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 
 			/*Log */
 			if fh.ScanLogger != nil {
-				fh.logFileHydration(zerolog.WarnLevel, lf, mId, episode).
+				fh.logFileClassification(zerolog.WarnLevel, lf, mId, episode).
 					Str("warning", "No episode number found, but the media only has 1 episode").
 					Msg("File has been marked as main")
 			}
@@ -247,10 +285,14 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 			lf.Metadata.Type = anime.LocalFileTypeSpecial
 			lf.Metadata.Episode = 1
 			lf.Metadata.AniDBEpisode = "S1"
+			if ep, aniDBEp, found := findSpecialEpisodeByTitle(getAnimeMetadata(), lf.ParsedData.EpisodeTitle); found {
+				lf.Metadata.Episode = ep
+				lf.Metadata.AniDBEpisode = aniDBEp
+			}
 
 			/*Log */
 			if fh.ScanLogger != nil {
-				fh.logFileHydration(zerolog.ErrorLevel, lf, mId, episode).
+				fh.logFileClassification(zerolog.ErrorLevel, lf, mId, episode).
 					Msg("No episode number found, file has been marked as special")
 			}
 			fh.ScanSummaryLogger.LogMetadataEpisodeNormalizationFailed(lf, errors.New("no episode number found"), lf.Metadata.Episode, lf.Metadata.AniDBEpisode)
@@ -305,7 +347,7 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 
 				/*Log */
 				if fh.ScanLogger != nil {
-					fh.logFileHydration(zerolog.WarnLevel, lf, mId, episode).
+					fh.logFileClassification(zerolog.WarnLevel, lf, mId, episode).
 						Dict("mediaTreeAnalysis", zerolog.Dict().
 							Bool("normalized", false).
 							Str("error", err.Error()).
@@ -317,7 +359,7 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 			} else {
 				/*Log */
 				if fh.ScanLogger != nil {
-					fh.logFileHydration(zerolog.DebugLevel, lf, mId, episode).
+					fh.logFileClassification(zerolog.DebugLevel, lf, mId, episode).
 						Dict("mediaTreeAnalysis", zerolog.Dict().
 							Bool("normalized", true).
 							Bool("hasNewMediaId", lf.MediaId != mId).
@@ -393,7 +435,7 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 
 			if relativeEp < 1 {
 				if fh.ScanLogger != nil {
-					fh.logFileHydration(zerolog.WarnLevel, lf, mId, episode).
+					fh.logFileClassification(zerolog.WarnLevel, lf, mId, episode).
 						Dict("normalization", zerolog.Dict().
 							Bool("normalized", false).
 							Str("reason", "Episode normalization failed, could not find relative episode number"),
@@ -408,7 +450,7 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 			}
 
 			if fh.ScanLogger != nil {
-				fh.logFileHydration(zerolog.DebugLevel, lf, mId, relativeEp).
+				fh.logFileClassification(zerolog.DebugLevel, lf, mId, relativeEp).
 					Dict("mediaTreeAnalysis", zerolog.Dict().
 						Bool("normalized", true).
 						Int("forcedMediaId", fh.ForceMediaId),
@@ -429,7 +471,7 @@ func (fh *FileHydrator) hydrateGroupMetadata(
 
 func (fh *FileHydrator) logFileHydration(level zerolog.Level, lf *anime.LocalFile, mId int, episode int) *zerolog.Event {
 	return fh.ScanLogger.LogFileHydrator(level).
-		Str("filename", lf.Name).
+		EmbedObject(scanLogFile{lf}).
 		Int("mediaId", mId).
 		Dict("vars", zerolog.Dict().
 			Str("parsedEpisode", lf.ParsedData.Episode).
@@ -440,6 +482,12 @@ func (fh *FileHydrator) logFileHydration(level zerolog.Level, lf *anime.LocalFil
 			Str("aniDBEpisode", lf.Metadata.AniDBEpisode))
 }
 
+// logFileClassification logs the final type of the local file, tagged as the decision of the entry.
+func (fh *FileHydrator) logFileClassification(level zerolog.Level, lf *anime.LocalFile, mId int, episode int) *zerolog.Event {
+	return fh.logFileHydration(level, lf, mId, episode).
+		Str("decision", getScanLogTypeDecision(lf.Metadata.Type))
+}
+
 // normalizeEpisodeNumberAndHydrate will normalize the episode number and hydrate the metadata of the LocalFile.
 // If the MediaTreeAnalysis is nil, the episode number will not be normalized.
 func (fh *FileHydrator) normalizeEpisodeNumberAndHydrate(
@@ -473,3 +521,71 @@ func (fh *FileHydrator) normalizeEpisodeNumberAndHydrate(
 	lf.MediaId = mediaId
 	return nil
 }
+
+// findNCEpisode returns the AniDB episode of a NC file.
+//   - Creditless openings and endings are mapped to "C" episodes, e.g. the 2nd opening is the 2nd "C" episode titled "Opening"
+//   - Trailers are mapped to "T" episodes
+//   - Other files are mapped to "O" episodes
+//
+// If the episode cannot be found in the AniDB metadata, the number of the file is used, e.g. "PV 2" -> "T2".
+func findNCEpisode(animeMetadata *metadata.AnimeMetadata, ncType comparison.NCType, number int) (int, string) {
+	number = max(number, 1)
+
+	prefix := "O"
+	keyword := ""
+	switch ncType {
+	case comparison.NCTypeOpening:
+		prefix, keyword = "C", "opening"
+	case comparison.NCTypeEnding:
+		prefix, keyword = "C", "ending"
+	case comparison.NCTypeTrailer:
+		prefix = "T"
+	}
+
+	candidates := lo.Filter(animeMetadata.GetEpisodesWithPrefix(prefix), func(aniDBEp string, _ int) bool {
+		if keyword == "" {
+			return true
+		}
+		episodeMetadata, _ := animeMetadata.FindEpisode(aniDBEp)
+		return strings.Contains(strings.ToLower(episodeMetadata.GetTitle()), keyword)
+	})
+	if number <= len(candidates) {
+		ep, _ := metadata.ExtractEpisodeInteger(candidates[number-1])
+		return ep, candidates[number-1]
+	}
+
+	return number, prefix + strconv.Itoa(number)
+}
+
+// findSpecialEpisodeByTitle returns the AniDB special episode whose title is the closest to the parsed episode title.
+// e.g. "[Group] Show - OVA - The Beach Episode.mkv" -> "S2"
+func findSpecialEpisodeByTitle(animeMetadata *metadata.AnimeMetadata, episodeTitle string) (int, string, bool) {
+	if animeMetadata == nil || len(episodeTitle) == 0 {
+		return 0, "", false
+	}
+
+	specials := animeMetadata.GetEpisodesWithPrefix("S")
+	titles := make([]*string, 0, len(specials))
+	titleToEp := make(map[string]string, len(specials))
+	for _, aniDBEp := range specials {
+		episodeMetadata, _ := animeMetadata.FindEpisode(aniDBEp)
+		title := episodeMetadata.GetTitle()
+		if len(title) == 0 {
+			continue
+		}
+		if _, ok := titleToEp[title]; ok {
+			continue
+		}
+		titleToEp[title] = aniDBEp
+		titles = append(titles, &title)
+	}
+
+	res, found := comparison.FindBestMatchWithSorensenDice(&episodeTitle, titles)
+	if !found || res == nil || res.Rating < 0.7 {
+		return 0, "", false
+	}
+
+	aniDBEp := titleToEp[*res.Value]
+	ep, _ := metadata.ExtractEpisodeInteger(aniDBEp)
+	return ep, aniDBEp, true
+}
@@ -6,6 +6,7 @@ import (
 	"seanime/internal/library/anime"
 	"seanime/internal/platforms/anilist_platform"
 	"seanime/internal/util"
+	"seanime/internal/util/comparison"
 	"seanime/internal/util/limiter"
 	"testing"
 )
@@ -120,3 +121,51 @@ func TestFileHydrator_HydrateMetadata(t *testing.T) {
 	}
 
 }
+
+func TestFindNCEpisode(t *testing.T) {
+	animeMetadata := &metadata.AnimeMetadata{
+		Episodes: map[string]*metadata.EpisodeMetadata{
+			"1":  {Episode: "1", Title: "Episode 1"},
+			"S1": {Episode: "S1", Title: "Beach Episode"},
+			"S2": {Episode: "S2", Title: "Hot Spring Episode"},
+			"C1": {Episode: "C1", Title: "Opening"},
+			"C2": {Episode: "C2", Title: "Ending"},
+			"C3": {Episode: "C3", Title: "Opening 2"},
+			"T1": {Episode: "T1", Title: "PV 1"},
+		},
+	}
+
+	tests := []struct {
+		name                 string
+		ncType               comparison.NCType
+		number               int
+		animeMetadata        *metadata.AnimeMetadata
+		expectedEpisode      int
+		expectedAniDBEpisode string
+	}{
+		{"first opening", comparison.NCTypeOpening, 0, animeMetadata, 1, "C1"},
+		{"second opening", comparison.NCTypeOpening, 2, animeMetadata, 3, "C3"},
+		{"first ending", comparison.NCTypeEnding, 1, animeMetadata, 2, "C2"},
+		{"trailer", comparison.NCTypeTrailer, 1, animeMetadata, 1, "T1"},
+		{"trailer not in metadata", comparison.NCTypeTrailer, 2, animeMetadata, 2, "T2"},
+		{"other", comparison.NCTypeOther, 0, animeMetadata, 1, "O1"},
+		{"no metadata", comparison.NCTypeEnding, 2, nil, 2, "C2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep, aniDBEp := findNCEpisode(tt.animeMetadata, tt.ncType, tt.number)
+			if ep != tt.expectedEpisode || aniDBEp != tt.expectedAniDBEpisode {
+				t.Fatalf("expected (%d, %s), got (%d, %s)", tt.expectedEpisode, tt.expectedAniDBEpisode, ep, aniDBEp)
+			}
+		})
+	}
+
+	ep, aniDBEp, found := findSpecialEpisodeByTitle(animeMetadata, "Hot Spring Episode")
+	if !found || ep != 2 || aniDBEp != "S2" {
+		t.Fatalf("expected (2, S2), got (%d, %s)", ep, aniDBEp)
+	}
+	if _, _, found = findSpecialEpisodeByTitle(animeMetadata, "Recap"); found {
+		t.Fatal("expected no special episode to be found")
+	}
+}
@@ -1,3 +1,45 @@
 package scanner
 
-// .seaignore
+import (
+	"seanime/internal/library/anime"
+	"seanime/internal/util"
+)
+
+// getIgnoredLocalFiles returns the local files excluded by .seaignore files, marked as ignored by a .seaignore file.
+// Existing local files are reused to keep their hashes and media info, but they are unmatched.
+func (scn *Scanner) getIgnoredLocalFiles(paths []string) []*anime.LocalFile {
+	ret := make([]*anime.LocalFile, 0, len(paths))
+	if len(paths) == 0 {
+		return ret
+	}
+
+	existing := make(map[string]*anime.LocalFile, len(scn.ExistingLocalFiles))
+	for _, lf := range scn.ExistingLocalFiles {
+		existing[lf.GetNormalizedPath()] = lf
+	}
+
+	for _, path := range paths {
+		var lf *anime.LocalFile
+		if existingLf, ok := existing[util.NormalizePath(path)]; ok {
+			// Copy the existing file, it must not be modified until the scan result is saved
+			copied := *existingLf
+			lf = &copied
+		} else {
+			lf = anime.NewLocalFile(path, scn.DirPath)
+		}
+		lf.IgnoredBySeaignore = true
+		lf.MediaId = 0
+		lf.Locked = false
+		lf.Metadata = &anime.LocalFileMetadata{}
+		scn.ScanSummaryLogger.LogIgnored(lf, "Excluded by a .seaignore file")
+		ret = append(ret, lf)
+	}
+
+	if scn.ScanLogger != nil {
+		scn.ScanLogger.logger.Debug().
+			Any("count", len(ret)).
+			Msg("Files excluded by .seaignore files")
+	}
+
+	return ret
+}
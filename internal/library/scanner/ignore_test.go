@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"seanime/internal/api/anilist"
+	"seanime/internal/library/anime"
+	"seanime/internal/library/filesystem"
+	"seanime/internal/library/summary"
+	"seanime/internal/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_SeaIgnore(t *testing.T) {
+	dir := t.TempDir()
+	showDir := filepath.Join(dir, "[SubsPlease] 86 - Eighty Six")
+	require.NoError(t, os.MkdirAll(showDir, 0755))
+
+	episodePath := filepath.Join(showDir, "[SubsPlease] 86 - Eighty Six - 01 (1080p).mkv")
+	samplePath := filepath.Join(showDir, "[SubsPlease] 86 - Eighty Six - 02 (1080p) sample.mkv")
+	for _, path := range []string{episodePath, samplePath} {
+		require.NoError(t, os.WriteFile(path, []byte{}, 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, filesystem.IgnoreFileName), []byte("*sample.mkv\n"), 0644))
+
+	// The sample was previously matched
+	existingLf := anime.NewLocalFile(samplePath, dir)
+	existingLf.MediaId = 116589
+	existingLf.Metadata = &anime.LocalFileMetadata{Episode: 2, AniDBEpisode: "2", Type: anime.LocalFileTypeMain}
+
+	scanner := &Scanner{
+		DirPath:            dir,
+		Logger:             util.NewLogger(),
+		ExistingLocalFiles: []*anime.LocalFile{existingLf},
+		ScanSummaryLogger:  summary.NewScanSummaryLogger(),
+	}
+
+	paths, ignoredPaths, err := scanner.getFilePaths(context.Background(), scanner.getLibraryDirs().scanned)
+	require.NoError(t, err)
+	assert.Equal(t, []string{episodePath}, paths)
+	assert.Equal(t, []string{samplePath}, ignoredPaths)
+
+	ignoredLfs := scanner.getIgnoredLocalFiles(ignoredPaths)
+	require.Len(t, ignoredLfs, 1)
+	assert.True(t, ignoredLfs[0].IgnoredBySeaignore)
+	assert.False(t, ignoredLfs[0].Ignored, "the user's ignored flag should not be set")
+	assert.True(t, ignoredLfs[0].IsIgnored())
+	assert.Equal(t, 0, ignoredLfs[0].MediaId, "ignored files should be unmatched")
+	assert.Equal(t, 0, ignoredLfs[0].Metadata.Episode)
+	assert.False(t, existingLf.IgnoredBySeaignore, "existing local files should not be modified")
+	assert.Equal(t, 116589, existingLf.MediaId, "existing local files should not be modified")
+
+	// Ignored files are listed separately in the summary
+	scanner.ScanSummaryLogger.HydrateData(ignoredLfs, []*anime.NormalizedMedia{}, &anilist.AnimeCollectionWithRelations{})
+	scanSummary := scanner.ScanSummaryLogger.GenerateSummary()
+	require.NotNil(t, scanSummary)
+	assert.Empty(t, scanSummary.Groups)
+	require.Len(t, scanSummary.IgnoredFiles, 1)
+	assert.Equal(t, samplePath, scanSummary.IgnoredFiles[0].LocalFile.Path)
+	assert.Len(t, scanSummary.IgnoredFiles[0].Logs, 1)
+
+	// Targeted scans honor the .seaignore files of the parent directories
+	scanner.TargetPaths = []string{showDir}
+	paths, ignoredPaths, err = scanner.getFilePaths(context.Background(), scanner.getLibraryDirs().scanned)
+	require.NoError(t, err)
+	assert.Equal(t, []string{episodePath}, paths)
+	assert.Equal(t, []string{samplePath}, ignoredPaths)
+}
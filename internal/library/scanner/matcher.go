@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/adrg/strutil/metrics"
@@ -10,10 +11,13 @@ import (
 	"github.com/sourcegraph/conc/pool"
 	"math"
 	"seanime/internal/api/anilist"
+	"seanime/internal/database/models"
 	"seanime/internal/library/anime"
+	"seanime/internal/library/filesystem"
 	"seanime/internal/library/summary"
 	"seanime/internal/util"
 	"seanime/internal/util/comparison"
+	"sync"
 	"time"
 )
 
@@ -26,6 +30,14 @@ type Matcher struct {
 	ScanSummaryLogger  *summary.ScanSummaryLogger // optional
 	Algorithm          string
 	Threshold          float64
+	Rules              *MatchingRules // optional, evaluated before fuzzy matching
+	HashMatches        *HashMatches   // optional, files identified by their hashes
+	// Roots is used to prefer the default media format of the library root of each file, optional
+	Roots            []*models.LibraryRoot
+	identified       sync.Map                   // Local files matched by a rule or by hash, they are not validated
+	formatContainers map[string]*MediaContainer // Media of each default media format of the roots
+	// ctx stops the matching when the scan is cancelled, optional
+	ctx context.Context
 }
 
 var (
@@ -56,10 +68,29 @@ func (m *Matcher) MatchLocalFilesWithMedia() error {
 
 	m.Logger.Debug().Msg("Starting matching process")
 
+	m.formatContainers = make(map[string]*MediaContainer)
+	for _, root := range m.Roots {
+		if root.DefaultMediaFormat == "" {
+			continue
+		}
+		if _, ok := m.formatContainers[root.DefaultMediaFormat]; ok {
+			continue
+		}
+		if container, ok := m.MediaContainer.FilterByFormat(root.DefaultMediaFormat); ok {
+			m.formatContainers[root.DefaultMediaFormat] = container
+		}
+	}
+
 	// Parallelize the matching process
 	lop.ForEach(m.LocalFiles, func(localFile *anime.LocalFile, _ int) {
+		if isCancelled(m.ctx) {
+			return
+		}
 		m.matchLocalFileWithMedia(localFile)
 	})
+	if isCancelled(m.ctx) {
+		return ErrScanCancelled
+	}
 
 	m.validateMatches()
 
@@ -85,7 +116,8 @@ func (m *Matcher) matchLocalFileWithMedia(lf *anime.LocalFile) {
 		/*Log*/
 		if m.ScanLogger != nil {
 			m.ScanLogger.LogMatcher(zerolog.ErrorLevel).
-				Str("filename", lf.Name).
+				EmbedObject(scanLogFile{lf}).
+				Str("decision", ScanLogDecisionUnmatched).
 				Msg("Panic occurred, file un-matched")
 		}
 		m.ScanSummaryLogger.LogPanic(lf, stackTrace)
@@ -95,17 +127,51 @@ func (m *Matcher) matchLocalFileWithMedia(lf *anime.LocalFile) {
 	if lf.MediaId != 0 {
 		if m.ScanLogger != nil {
 			m.ScanLogger.LogMatcher(zerolog.DebugLevel).
-				Str("filename", lf.Name).
+				EmbedObject(scanLogFile{lf}).
+				Int("mediaId", lf.MediaId).
+				Str("decision", ScanLogDecisionSkipped).
 				Msg("File already matched")
 		}
 		m.ScanSummaryLogger.LogFileNotMatched(lf, "Already matched")
 		return
 	}
+	// User-defined rules take precedence over fuzzy matching
+	if rule, ok := m.Rules.Match(lf); ok {
+		if m.ScanLogger != nil {
+			m.ScanLogger.LogMatcher(zerolog.DebugLevel).
+				EmbedObject(scanLogFile{lf}).
+				Uint("ruleId", rule.ID).
+				Str("ruleType", rule.Type).
+				Int("mediaId", rule.MediaId).
+				Str("decision", ScanLogDecisionMatched).
+				Msg("Matched by rule")
+		}
+		m.ScanSummaryLogger.LogMatchingRule(lf, rule.ID, fmt.Sprintf("Matched to media %d (%s \"%s\")", rule.MediaId, rule.Type, rule.Pattern))
+		m.identified.Store(lf, struct{}{})
+		lf.MediaId = rule.MediaId
+		return
+	}
+	if result, ok := m.HashMatches.Get(lf); ok {
+		if m.ScanLogger != nil {
+			m.ScanLogger.LogMatcher(zerolog.DebugLevel).
+				EmbedObject(scanLogFile{lf}).
+				Str("ed2k", lf.Hashes.ED2K).
+				Int("mediaId", result.MediaId).
+				Str("decision", ScanLogDecisionMatched).
+				Msg("Matched by hash")
+		}
+		m.ScanSummaryLogger.LogHashMatch(lf, result.MediaId, result.AniDBEpisode)
+		m.identified.Store(lf, struct{}{})
+		lf.MediaId = result.MediaId
+		return
+	}
+
 	// Check if the local file has a title
 	if lf.GetParsedTitle() == "" {
 		if m.ScanLogger != nil {
 			m.ScanLogger.LogMatcher(zerolog.WarnLevel).
-				Str("filename", lf.Name).
+				EmbedObject(scanLogFile{lf}).
+				Str("decision", ScanLogDecisionUnmatched).
 				Msg("File has no parsed title")
 		}
 		m.ScanSummaryLogger.LogFileNotMatched(lf, "No parsed title found")
@@ -120,7 +186,8 @@ func (m *Matcher) matchLocalFileWithMedia(lf *anime.LocalFile) {
 	if len(titleVariations) == 0 {
 		if m.ScanLogger != nil {
 			m.ScanLogger.LogMatcher(zerolog.WarnLevel).
-				Str("filename", lf.Name).
+				EmbedObject(scanLogFile{lf}).
+				Str("decision", ScanLogDecisionUnmatched).
 				Msg("No titles found")
 		}
 		m.ScanSummaryLogger.LogFileNotMatched(lf, "No title variations found")
@@ -129,7 +196,7 @@ func (m *Matcher) matchLocalFileWithMedia(lf *anime.LocalFile) {
 
 	if m.ScanLogger != nil {
 		m.ScanLogger.LogMatcher(zerolog.DebugLevel).
-			Str("filename", lf.Name).
+			EmbedObject(scanLogFile{lf}).
 			Interface("titleVariations", titleVariations).
 			Msg("Matching local file")
 	}
@@ -137,6 +204,83 @@ func (m *Matcher) matchLocalFileWithMedia(lf *anime.LocalFile) {
 
 	//------------------
 
+	var mediaMatch *anime.NormalizedMedia
+	var found bool
+	finalRating := 0.0
+
+	// Media of the format preferred by the library root are matched first,
+	// the other media are only considered if none of them is a good enough match
+	if format, ok := m.getDefaultMediaFormat(lf); ok {
+		if container, ok := m.formatContainers[format]; ok {
+			mediaMatch, finalRating, found = m.findBestMatch(lf, titleVariations, container)
+			if found && finalRating < m.Threshold {
+				found = false
+			}
+		}
+	}
+	if !found {
+		mediaMatch, finalRating, found = m.findBestMatch(lf, titleVariations, m.MediaContainer)
+	}
+
+	if !found {
+		if m.ScanLogger != nil {
+			m.ScanLogger.LogMatcher(zerolog.ErrorLevel).
+				EmbedObject(scanLogFile{lf}).
+				Str("decision", ScanLogDecisionUnmatched).
+				Msg("No media found from comparison result")
+		}
+		m.ScanSummaryLogger.LogFileNotMatched(lf, "No media found from comparison result")
+		return
+	}
+
+	if m.ScanLogger != nil {
+		m.ScanLogger.LogMatcher(zerolog.DebugLevel).
+			EmbedObject(scanLogFile{lf}).
+			Str("title", mediaMatch.GetTitleSafe()).
+			Int("mediaId", mediaMatch.ID).
+			Msg("Best match found")
+	}
+
+	if finalRating < m.Threshold {
+		if m.ScanLogger != nil {
+			m.ScanLogger.LogMatcher(zerolog.DebugLevel).
+				EmbedObject(scanLogFile{lf}).
+				Int("mediaId", mediaMatch.ID).
+				Float64("rating", finalRating).
+				Float64("threshold", m.Threshold).
+				Str("decision", ScanLogDecisionUnmatched).
+				Msg("Best match Sorensen-Dice rating too low, un-matching file")
+		}
+		m.ScanSummaryLogger.LogFailedMatch(lf, "Rating too low, threshold is "+fmt.Sprintf("%f", m.Threshold))
+		return
+	}
+
+	if m.ScanLogger != nil {
+		m.ScanLogger.LogMatcher(zerolog.DebugLevel).
+			EmbedObject(scanLogFile{lf}).
+			Int("mediaId", mediaMatch.ID).
+			Float64("rating", finalRating).
+			Float64("threshold", m.Threshold).
+			Str("decision", ScanLogDecisionMatched).
+			Msg("Best match rating high enough, matching file")
+	}
+	m.ScanSummaryLogger.LogSuccessfullyMatched(lf, mediaMatch.ID)
+
+	lf.MediaId = mediaMatch.ID
+}
+
+// getDefaultMediaFormat returns the AniList format preferred by the library root of the local file.
+func (m *Matcher) getDefaultMediaFormat(lf *anime.LocalFile) (string, bool) {
+	root, ok := filesystem.FindLibraryRoot(m.Roots, lf.Path)
+	if !ok || root.DefaultMediaFormat == "" {
+		return "", false
+	}
+	return root.DefaultMediaFormat, true
+}
+
+// findBestMatch compares the title variations of the local file with the titles of the media in the container.
+// It returns the media with the best rating, the rating, and false if no media was found.
+func (m *Matcher) findBestMatch(lf *anime.LocalFile, titleVariations []*string, container *MediaContainer) (mediaMatch *anime.NormalizedMedia, finalRating float64, found bool) {
 	var levMatch *comparison.LevenshteinResult
 	var sdMatch *comparison.SorensenDiceResult
 	var jaccardMatch *comparison.JaccardResult
@@ -146,18 +290,18 @@ func (m *Matcher) matchLocalFileWithMedia(lf *anime.LocalFile) {
 		// Get the matchs for each title variation
 		compResults := lop.Map(titleVariations, func(title *string, _ int) *comparison.JaccardResult {
 			comps := make([]*comparison.JaccardResult, 0)
-			if len(m.MediaContainer.engTitles) > 0 {
-				if eng, found := comparison.FindBestMatchWithJaccard(title, m.MediaContainer.engTitles); found {
+			if len(container.engTitles) > 0 {
+				if eng, found := comparison.FindBestMatchWithJaccard(title, container.engTitles); found {
 					comps = append(comps, eng)
 				}
 			}
-			if len(m.MediaContainer.romTitles) > 0 {
-				if rom, found := comparison.FindBestMatchWithJaccard(title, m.MediaContainer.romTitles); found {
+			if len(container.romTitles) > 0 {
+				if rom, found := comparison.FindBestMatchWithJaccard(title, container.romTitles); found {
 					comps = append(comps, rom)
 				}
 			}
-			if len(m.MediaContainer.synonyms) > 0 {
-				if syn, found := comparison.FindBestMatchWithJaccard(title, m.MediaContainer.synonyms); found {
+			if len(container.synonyms) > 0 {
+				if syn, found := comparison.FindBestMatchWithJaccard(title, container.synonyms); found {
 					comps = append(comps, syn)
 				}
 			}
@@ -187,7 +331,7 @@ func (m *Matcher) matchLocalFileWithMedia(lf *anime.LocalFile) {
 
 		if m.ScanLogger != nil {
 			m.ScanLogger.LogMatcher(zerolog.DebugLevel).
-				Str("filename", lf.Name).
+				EmbedObject(scanLogFile{lf}).
 				Interface("match", jaccardMatch).
 				Interface("results", compResults).
 				Msg("Jaccard match")
@@ -199,18 +343,18 @@ func (m *Matcher) matchLocalFileWithMedia(lf *anime.LocalFile) {
 		// Get the matchs for each title variation
 		compResults := lop.Map(titleVariations, func(title *string, _ int) *comparison.SorensenDiceResult {
 			comps := make([]*comparison.SorensenDiceResult, 0)
-			if len(m.MediaContainer.engTitles) > 0 {
-				if eng, found := comparison.FindBestMatchWithSorensenDice(title, m.MediaContainer.engTitles); found {
+			if len(container.engTitles) > 0 {
+				if eng, found := comparison.FindBestMatchWithSorensenDice(title, container.engTitles); found {
 					comps = append(comps, eng)
 				}
 			}
-			if len(m.MediaContainer.romTitles) > 0 {
-				if rom, found := comparison.FindBestMatchWithSorensenDice(title, m.MediaContainer.romTitles); found {
+			if len(container.romTitles) > 0 {
+				if rom, found := comparison.FindBestMatchWithSorensenDice(title, container.romTitles); found {
 					comps = append(comps, rom)
 				}
 			}
-			if len(m.MediaContainer.synonyms) > 0 {
-				if syn, found := comparison.FindBestMatchWithSorensenDice(title, m.MediaContainer.synonyms); found {
+			if len(container.synonyms) > 0 {
+				if syn, found := comparison.FindBestMatchWithSorensenDice(title, container.synonyms); found {
 					comps = append(comps, syn)
 				}
 			}
@@ -242,7 +386,7 @@ func (m *Matcher) matchLocalFileWithMedia(lf *anime.LocalFile) {
 
 		if m.ScanLogger != nil {
 			m.ScanLogger.LogMatcher(zerolog.DebugLevel).
-				Str("filename", lf.Name).
+				EmbedObject(scanLogFile{lf}).
 				Interface("match", sdMatch).
 				Interface("results", compResults).
 				Msg("Sorensen-Dice match")
@@ -254,18 +398,18 @@ func (m *Matcher) matchLocalFileWithMedia(lf *anime.LocalFile) {
 		// Get the matches for each title variation
 		levCompResults := lop.Map(titleVariations, func(title *string, _ int) *comparison.LevenshteinResult {
 			comps := make([]*comparison.LevenshteinResult, 0)
-			if len(m.MediaContainer.engTitles) > 0 {
-				if eng, found := comparison.FindBestMatchWithLevenshtein(title, m.MediaContainer.engTitles); found {
+			if len(container.engTitles) > 0 {
+				if eng, found := comparison.FindBestMatchWithLevenshtein(title, container.engTitles); found {
 					comps = append(comps, eng)
 				}
 			}
-			if len(m.MediaContainer.romTitles) > 0 {
-				if rom, found := comparison.FindBestMatchWithLevenshtein(title, m.MediaContainer.romTitles); found {
+			if len(container.romTitles) > 0 {
+				if rom, found := comparison.FindBestMatchWithLevenshtein(title, container.romTitles); found {
 					comps = append(comps, rom)
 				}
 			}
-			if len(m.MediaContainer.synonyms) > 0 {
-				if syn, found := comparison.FindBestMatchWithLevenshtein(title, m.MediaContainer.synonyms); found {
+			if len(container.synonyms) > 0 {
+				if syn, found := comparison.FindBestMatchWithLevenshtein(title, container.synonyms); found {
 					comps = append(comps, syn)
 				}
 			}
@@ -294,7 +438,7 @@ func (m *Matcher) matchLocalFileWithMedia(lf *anime.LocalFile) {
 
 		if m.ScanLogger != nil {
 			m.ScanLogger.LogMatcher(zerolog.DebugLevel).
-				Str("filename", lf.Name).
+				EmbedObject(scanLogFile{lf}).
 				Interface("match", levMatch).
 				Interface("results", levCompResults).
 				Int("distance", levMatch.Distance).
@@ -305,66 +449,23 @@ func (m *Matcher) matchLocalFileWithMedia(lf *anime.LocalFile) {
 
 	//------------------
 
-	var mediaMatch *anime.NormalizedMedia
-	var found bool
-	finalRating := 0.0
-
 	if sdMatch != nil {
 		finalRating = sdMatch.Rating
-		mediaMatch, found = m.MediaContainer.GetMediaFromTitleOrSynonym(sdMatch.Value)
+		mediaMatch, found = container.GetMediaFromTitleOrSynonym(sdMatch.Value)
 
 	} else if jaccardMatch != nil {
 		finalRating = jaccardMatch.Rating
-		mediaMatch, found = m.MediaContainer.GetMediaFromTitleOrSynonym(jaccardMatch.Value)
+		mediaMatch, found = container.GetMediaFromTitleOrSynonym(jaccardMatch.Value)
 
 	} else {
 		dice := metrics.NewSorensenDice()
 		dice.CaseSensitive = false
 		finalRating = dice.Compare(*levMatch.OriginalValue, *levMatch.Value)
 		m.ScanSummaryLogger.LogComparison(lf, "Sorensen-Dice", *levMatch.Value, "Final rating", util.InlineSpewT(finalRating))
-		mediaMatch, found = m.MediaContainer.GetMediaFromTitleOrSynonym(levMatch.Value)
-	}
-
-	if !found {
-		if m.ScanLogger != nil {
-			m.ScanLogger.LogMatcher(zerolog.ErrorLevel).
-				Str("filename", lf.Name).
-				Msg("No media found from comparison result")
-		}
-		m.ScanSummaryLogger.LogFileNotMatched(lf, "No media found from comparison result")
-		return
+		mediaMatch, found = container.GetMediaFromTitleOrSynonym(levMatch.Value)
 	}
 
-	if m.ScanLogger != nil {
-		m.ScanLogger.LogMatcher(zerolog.DebugLevel).
-			Str("filename", lf.Name).
-			Str("title", mediaMatch.GetTitleSafe()).
-			Int("id", mediaMatch.ID).
-			Msg("Best match found")
-	}
-
-	if finalRating < m.Threshold {
-		if m.ScanLogger != nil {
-			m.ScanLogger.LogMatcher(zerolog.DebugLevel).
-				Str("filename", lf.Name).
-				Float64("rating", finalRating).
-				Float64("threshold", m.Threshold).
-				Msg("Best match Sorensen-Dice rating too low, un-matching file")
-		}
-		m.ScanSummaryLogger.LogFailedMatch(lf, "Rating too low, threshold is "+fmt.Sprintf("%f", m.Threshold))
-		return
-	}
-
-	if m.ScanLogger != nil {
-		m.ScanLogger.LogMatcher(zerolog.DebugLevel).
-			Str("filename", lf.Name).
-			Float64("rating", finalRating).
-			Float64("threshold", m.Threshold).
-			Msg("Best match rating high enough, matching file")
-	}
-	m.ScanSummaryLogger.LogSuccessfullyMatched(lf, mediaMatch.ID)
-
-	lf.MediaId = mediaMatch.ID
+	return mediaMatch, finalRating, found
 }
 
 //----------------------------------------------------------------------------------------------------------------------
@@ -402,6 +503,15 @@ func (m *Matcher) validateMatches() {
 // This is done to try and filter out wrong matches.
 func (m *Matcher) validateMatchGroup(mediaId int, lfs []*anime.LocalFile) {
 
+	// Files matched by a rule or by hash are kept as-is
+	lfs = lo.Filter(lfs, func(lf *anime.LocalFile, _ int) bool {
+		_, ok := m.identified.Load(lf)
+		return !ok
+	})
+	if len(lfs) == 0 {
+		return
+	}
+
 	media, found := m.MediaContainer.GetMediaFromId(mediaId)
 	if !found {
 		if m.ScanLogger != nil {
@@ -461,9 +571,10 @@ func (m *Matcher) validateMatchGroup(mediaId int, lfs []*anime.LocalFile) {
 					if m.ScanLogger != nil {
 						m.ScanLogger.LogMatcher(zerolog.WarnLevel).
 							Int("mediaId", mediaId).
-							Str("filename", lf.Name).
+							EmbedObject(scanLogFile{lf}).
 							Float64("rating", compRes.Rating).
 							Float64("highestRating", highestRating).
+							Str("decision", ScanLogDecisionUnmatched).
 							Msg("Rating does not match parameters, un-matching file")
 					}
 					m.ScanSummaryLogger.LogUnmatched(lf, fmt.Sprintf("Rating does not match parameters. File rating: %f, highest rating: %f", compRes.Rating, highestRating))
@@ -473,7 +584,7 @@ func (m *Matcher) validateMatchGroup(mediaId int, lfs []*anime.LocalFile) {
 					if m.ScanLogger != nil {
 						m.ScanLogger.LogMatcher(zerolog.DebugLevel).
 							Int("mediaId", mediaId).
-							Str("filename", lf.Name).
+							EmbedObject(scanLogFile{lf}).
 							Float64("rating", compRes.Rating).
 							Float64("highestRating", highestRating).
 							Msg("Rating matches parameters, keeping file matched")
@@ -71,6 +71,22 @@ func NewMediaContainer(opts *MediaContainerOptions) *MediaContainer {
 		mc.NormalizedMedia = append(mc.NormalizedMedia, m)
 	}
 
+	mc.indexTitles()
+	mc.allMedia = opts.AllMedia
+
+	if mc.ScanLogger != nil {
+		mc.ScanLogger.LogMediaContainer(zerolog.InfoLevel).
+			Any("inputCount", len(opts.AllMedia)).
+			Any("mediaCount", len(mc.NormalizedMedia)).
+			Any("titles", len(mc.engTitles)+len(mc.romTitles)+len(mc.synonyms)).
+			Msg("Created media container")
+	}
+
+	return mc
+}
+
+// indexTitles creates the lists of titles compared by the Matcher.
+func (mc *MediaContainer) indexTitles() {
 	engTitles := lop.Map(mc.NormalizedMedia, func(m *anime.NormalizedMedia, index int) *string {
 		if m.Title.English != nil {
 			return m.Title.English
@@ -97,17 +113,25 @@ func NewMediaContainer(opts *MediaContainerOptions) *MediaContainer {
 	mc.engTitles = engTitles
 	mc.romTitles = romTitles
 	mc.synonyms = synonyms
-	mc.allMedia = opts.AllMedia
+}
 
-	if mc.ScanLogger != nil {
-		mc.ScanLogger.LogMediaContainer(zerolog.InfoLevel).
-			Any("inputCount", len(opts.AllMedia)).
-			Any("mediaCount", len(mc.NormalizedMedia)).
-			Any("titles", len(mc.engTitles)+len(mc.romTitles)+len(mc.synonyms)).
-			Msg("Created media container")
+// FilterByFormat returns a container with the media of the given AniList format, or false if there are none.
+func (mc *MediaContainer) FilterByFormat(format string) (*MediaContainer, bool) {
+	ret := &MediaContainer{
+		ScanLogger: mc.ScanLogger,
+		NormalizedMedia: lo.Filter(mc.NormalizedMedia, func(m *anime.NormalizedMedia, _ int) bool {
+			return m.Format != nil && strings.EqualFold(string(*m.Format), format)
+		}),
+		allMedia: mc.allMedia,
 	}
-
-	return mc
+	if len(ret.NormalizedMedia) == 0 {
+		return nil, false
+	}
+	ret.indexTitles()
+	if len(ret.engTitles)+len(ret.romTitles)+len(ret.synonyms) == 0 {
+		return nil, false
+	}
+	return ret, true
 }
 
 func (mc *MediaContainer) GetMediaFromTitleOrSynonym(title *string) (*anime.NormalizedMedia, bool) {
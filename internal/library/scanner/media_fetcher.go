@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"context"
 	"errors"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/rs/zerolog"
@@ -36,6 +37,8 @@ type MediaFetcherOptions struct {
 	AnilistRateLimiter     *limiter.Limiter
 	DisableAnimeCollection bool
 	ScanLogger             *ScanLogger
+	// ctx stops the fetching when the scan is cancelled, optional
+	ctx context.Context
 }
 
 // NewMediaFetcher
@@ -118,6 +121,7 @@ func NewMediaFetcher(opts *MediaFetcherOptions) (ret *MediaFetcher, retErr error
 	if opts.Enhanced {
 
 		_, ok := FetchMediaFromLocalFiles(
+			opts.ctx,
 			opts.Platform,
 			opts.LocalFiles,
 			opts.CompleteAnimeCache, // CompleteAnimeCache will be populated on success
@@ -134,6 +138,9 @@ func NewMediaFetcher(opts *MediaFetcherOptions) (ret *MediaFetcher, retErr error
 				return true
 			})
 		}
+		if isCancelled(opts.ctx) {
+			return nil, ErrScanCancelled
+		}
 	}
 
 	// +---------------------+
@@ -169,7 +176,9 @@ func NewMediaFetcher(opts *MediaFetcherOptions) (ret *MediaFetcher, retErr error
 // queries AniList to retrieve all anilist.BaseAnime using anilist.GetBaseAnimeById and their relations using anilist.FetchMediaTree.
 // It does not return an error if one of the steps fails.
 // It returns the scanned media and a boolean indicating whether the process was successful.
+// The remaining requests are skipped once the context is done, the context can be nil.
 func FetchMediaFromLocalFiles(
+	ctx context.Context,
 	platform platform.Platform,
 	localFiles []*anime.LocalFile,
 	completeAnime *anilist.CompleteAnimeCache,
@@ -207,11 +216,14 @@ func FetchMediaFromLocalFiles(
 	// Get MAL media from titles
 	malSR := parallel.NewSettledResults[string, *mal.SearchResultAnime](titles)
 	malSR.AllSettled(func(title string, index int) (*mal.SearchResultAnime, error) {
+		if isCancelled(ctx) {
+			return nil, ErrScanCancelled
+		}
 		rateLimiter.Wait()
 		return mal.AdvancedSearchWithMAL(title)
 	})
 	malRes, ok := malSR.GetFulfilledResults()
-	if !ok {
+	if !ok || isCancelled(ctx) {
 		return nil, false
 	}
 
@@ -236,6 +248,9 @@ func FetchMediaFromLocalFiles(
 	// Get AniZip mappings for each MAL ID and store them in `metadataProvider`
 	// This step is necessary because MAL doesn't provide AniList IDs and some MAL media don't exist on AniList
 	lop.ForEach(malIds, func(id int, index int) {
+		if isCancelled(ctx) {
+			return
+		}
 		rateLimiter2.Wait()
 		//_, _ = metadataProvider.GetAnimeMetadata(metadata.MalPlatform, id)
 		_, _ = metadataProvider.GetCache().GetOrSet(metadata.GetAnimeMetadataCacheKey(metadata.MalPlatform, id), func() (*metadata.AnimeMetadata, error) {
@@ -257,9 +272,16 @@ func FetchMediaFromLocalFiles(
 		return true
 	})
 
+	if isCancelled(ctx) {
+		return nil, false
+	}
+
 	// Fetch all media from the AniList IDs
 	anilistMedia := make([]*anilist.CompleteAnime, 0)
 	lop.ForEach(anilistIds, func(id int, index int) {
+		if isCancelled(ctx) {
+			return
+		}
 		anilistRateLimiter.Wait()
 		media, err := platform.GetAnimeWithRelations(id)
 		if err == nil {
@@ -301,10 +323,17 @@ func FetchMediaFromLocalFiles(
 	// For each media, fetch its relations
 	// The relations are fetched in parallel and added to `completeAnime`
 	lop.ForEach(anilistMedia, func(m *anilist.CompleteAnime, index int) {
+		if isCancelled(ctx) {
+			return
+		}
 		// We ignore errors because we want to continue even if one of the media fails
 		_ = m.FetchMediaTree(anilist.FetchMediaTreeAll, platform.GetAnilistClient(), anilistRateLimiter, tree, completeAnime)
 	})
 
+	if isCancelled(ctx) {
+		return nil, false
+	}
+
 	// +---------------------+
 	// |        Cache        |
 	// +---------------------+
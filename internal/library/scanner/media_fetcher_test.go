@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"context"
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"seanime/internal/api/anilist"
@@ -237,6 +238,7 @@ func TestFetchMediaFromLocalFiles(t *testing.T) {
 			// +--------------------------+
 
 			media, ok := FetchMediaFromLocalFiles(
+				context.Background(),
 				anilistPlatform,
 				lfs,
 				completeAnimeCache,
@@ -0,0 +1,170 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"seanime/internal/database/models"
+	"seanime/internal/library/anime"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"gopkg.in/vansante/go-ffprobe.v2"
+)
+
+// FileMediaInfoCache stores the media info extracted by the scanner so that unchanged files are not probed again.
+type FileMediaInfoCache interface {
+	GetFileMediaInfos() ([]*models.FileMediaInfo, error)
+	SaveFileMediaInfos(infos []*models.FileMediaInfo) error
+	DeleteFileMediaInfos(paths []string) error
+}
+
+// FileProber extracts the technical metadata (duration, codecs, tracks, etc.) of local files using FFprobe.
+type FileProber struct {
+	LocalFiles  []*anime.LocalFile
+	FfprobePath string             // optional, defaults to "ffprobe" in the PATH
+	Cache       FileMediaInfoCache // optional
+	Workers     int                // Number of files probed concurrently, defaults to 4
+	Logger      *zerolog.Logger
+	ScanLogger  *ScanLogger // optional
+	// probe replaces FFprobe in tests
+	probe func(path string) (*anime.LocalFileMediaInfo, error)
+	// ctx stops the probing when the scan is cancelled, optional
+	ctx context.Context
+}
+
+const probeTimeout = 30 * time.Second
+
+// ProbeLocalFiles sets the media info of the local files.
+// Cached media info is reused if the size and modification time of the file did not change.
+func (fp *FileProber) ProbeLocalFiles() {
+	start := time.Now()
+
+	workers := fp.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+
+	probe := fp.probe
+	if probe == nil {
+		if fp.FfprobePath != "" {
+			ffprobe.SetFFProbeBinPath(fp.FfprobePath)
+		}
+		probe = fp.ffprobe
+	}
+
+	var cached []*models.FileMediaInfo
+	if fp.Cache != nil {
+		infos, err := fp.Cache.GetFileMediaInfos()
+		if err != nil {
+			fp.Logger.Warn().Err(err).Msg("scanner: Failed to get cached file media info")
+		}
+		cached = infos
+	}
+
+	worker := &cachedFileWorker[*models.FileMediaInfo]{
+		Workers: workers,
+		Cached:  cached,
+		Stat: func(c *models.FileMediaInfo) (string, int64, time.Time) {
+			return c.Path, c.Size, c.ModTime
+		},
+		Reuse: func(lf *anime.LocalFile, c *models.FileMediaInfo) bool {
+			var info anime.LocalFileMediaInfo
+			if err := json.Unmarshal(c.Value, &info); err != nil {
+				return false
+			}
+			lf.MediaInfo = &info
+			return true
+		},
+		Compute: func(lf *anime.LocalFile, stat os.FileInfo) (*models.FileMediaInfo, error) {
+			info, err := probe(lf.Path)
+			if err != nil {
+				fp.Logger.Warn().Err(err).Str("path", lf.Path).Msg("scanner: Failed to probe file")
+				return nil, err
+			}
+			lf.MediaInfo = info
+
+			value, err := json.Marshal(info)
+			if err != nil {
+				return nil, err
+			}
+			return &models.FileMediaInfo{
+				Path:    lf.Path,
+				Size:    stat.Size(),
+				ModTime: stat.ModTime(),
+				Value:   value,
+			}, nil
+		},
+		ctx: fp.ctx,
+	}
+	res := worker.run(fp.LocalFiles)
+
+	if fp.Cache != nil {
+		if err := fp.Cache.SaveFileMediaInfos(res.Computed); err != nil {
+			fp.Logger.Warn().Err(err).Msg("scanner: Failed to cache file media info")
+		}
+	}
+
+	if fp.ScanLogger != nil {
+		fp.ScanLogger.LogFileProber(zerolog.InfoLevel).
+			Int64("ms", time.Since(start).Milliseconds()).
+			Int("probed", len(res.Computed)).
+			Int("cached", res.Reused).
+			Int("failed", res.Failed).
+			Msg("Finished probing files")
+	}
+}
+
+func (fp *FileProber) ffprobe(path string) (*anime.LocalFileMediaInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	data, err := ffprobe.ProbeURL(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return newLocalFileMediaInfo(data), nil
+}
+
+// newLocalFileMediaInfo keeps the metadata of the FFprobe output that can be filtered on.
+func newLocalFileMediaInfo(data *ffprobe.ProbeData) *anime.LocalFileMediaInfo {
+	ret := &anime.LocalFileMediaInfo{
+		AudioTracks:    make([]*anime.LocalFileMediaTrack, 0),
+		SubtitleTracks: make([]*anime.LocalFileMediaTrack, 0),
+	}
+	if data == nil {
+		return ret
+	}
+
+	if data.Format != nil {
+		ret.Duration = data.Format.DurationSeconds
+		ret.Container = data.Format.FormatName
+	}
+
+	if video := data.FirstVideoStream(); video != nil {
+		ret.VideoCodec = video.CodecName
+		ret.Width = video.Width
+		ret.Height = video.Height
+	}
+
+	for _, stream := range data.Streams {
+		if stream == nil {
+			continue
+		}
+		track := &anime.LocalFileMediaTrack{
+			Codec:    stream.CodecName,
+			Language: anime.NormalizeMediaLanguage(stream.Tags.Language),
+			Title:    stream.Tags.Title,
+			Forced:   stream.Disposition.Forced != 0,
+		}
+		switch stream.CodecType {
+		case string(ffprobe.StreamAudio):
+			ret.AudioTracks = append(ret.AudioTracks, track)
+		case string(ffprobe.StreamSubtitle):
+			ret.SubtitleTracks = append(ret.SubtitleTracks, track)
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"seanime/internal/library/anime"
+	"seanime/internal/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/vansante/go-ffprobe.v2"
+)
+
+func TestFileProber(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "[Group] Show - 01.mkv")
+	require.NoError(t, os.WriteFile(path, []byte("abc"), 0644))
+
+	probed := 0
+	cache := newMemoryFileCache()
+
+	lf := anime.NewLocalFile(path, dir)
+	prober := &FileProber{
+		LocalFiles: []*anime.LocalFile{lf},
+		Cache:      cache,
+		Logger:     util.NewLogger(),
+		probe: func(path string) (*anime.LocalFileMediaInfo, error) {
+			probed++
+			return &anime.LocalFileMediaInfo{Duration: 1420, VideoCodec: "hevc", Height: 1080}, nil
+		},
+	}
+	prober.ProbeLocalFiles()
+
+	require.NotNil(t, lf.MediaInfo)
+	assert.Equal(t, "hevc", lf.MediaInfo.VideoCodec)
+	assert.Equal(t, 1, probed)
+	assert.Len(t, cache.infos, 1)
+
+	// Unchanged files are not probed again
+	lf = anime.NewLocalFile(path, dir)
+	prober.LocalFiles = []*anime.LocalFile{lf}
+	prober.ProbeLocalFiles()
+
+	require.NotNil(t, lf.MediaInfo)
+	assert.Equal(t, 1080, lf.MediaInfo.Height)
+	assert.Equal(t, 1, probed)
+}
+
+func TestNewLocalFileMediaInfo(t *testing.T) {
+	data := &ffprobe.ProbeData{
+		Format: &ffprobe.Format{
+			FormatName:      "matroska,webm",
+			DurationSeconds: 1420.5,
+		},
+		Streams: []*ffprobe.Stream{
+			{CodecType: "video", CodecName: "hevc", Width: 1920, Height: 1080},
+			{CodecType: "audio", CodecName: "aac", Tags: ffprobe.StreamTags{Language: "jpn"}},
+			{CodecType: "subtitle", CodecName: "ass", Tags: ffprobe.StreamTags{Language: "eng", Title: "Signs"}, Disposition: ffprobe.StreamDisposition{Forced: 1}},
+			{CodecType: "subtitle", CodecName: "hdmv_pgs_subtitle", Tags: ffprobe.StreamTags{Language: "und"}},
+			{CodecType: "attachment", CodecName: "ttf"},
+		},
+	}
+
+	info := newLocalFileMediaInfo(data)
+
+	assert.Equal(t, 1420.5, info.Duration)
+	assert.Equal(t, "matroska,webm", info.Container)
+	assert.Equal(t, "hevc", info.VideoCodec)
+	assert.Equal(t, 1920, info.Width)
+	assert.Equal(t, 1080, info.Height)
+	if assert.Len(t, info.AudioTracks, 1) {
+		assert.Equal(t, "ja", info.AudioTracks[0].Language)
+	}
+	// Subtitles that cannot be streamed are kept
+	if assert.Len(t, info.SubtitleTracks, 2) {
+		assert.Equal(t, &anime.LocalFileMediaTrack{Codec: "ass", Language: "en", Title: "Signs", Forced: true}, info.SubtitleTracks[0])
+		assert.Equal(t, "", info.SubtitleTracks[1].Language)
+	}
+}
@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"seanime/internal/events"
+	"sync"
+	"time"
+)
+
+var ErrScanCancelled = errors.New("scan cancelled")
+
+type ScanStage string
+
+const (
+	ScanStageStarting   ScanStage = "starting"
+	ScanStageFiles      ScanStage = "files"
+	ScanStageHashing    ScanStage = "hashing"
+	ScanStageProbing    ScanStage = "probing"
+	ScanStageFetching   ScanStage = "fetching"
+	ScanStageMatching   ScanStage = "matching"
+	ScanStageHydrating  ScanStage = "hydrating"
+	ScanStageFinalizing ScanStage = "finalizing"
+	ScanStageCompleted  ScanStage = "completed"
+	ScanStageCancelled  ScanStage = "cancelled"
+)
+
+// ScanProgress is the progress of a scan, sent with events.EventScanProgressDetails.
+type ScanProgress struct {
+	Stage   ScanStage `json:"stage"`
+	Percent int       `json:"percent"`
+	// Media files found in the library directories
+	FilesFound int `json:"filesFound"`
+	// Files that are matched and hydrated, i.e. files found minus skipped files
+	FilesToScan  int   `json:"filesToScan"`
+	MediaFetched int   `json:"mediaFetched"`
+	Matched      int   `json:"matched"`
+	Hydrated     int   `json:"hydrated"`
+	ElapsedMs    int64 `json:"elapsedMs"`
+	// Estimated remaining time, 0 when unknown
+	EtaMs int64 `json:"etaMs"`
+}
+
+// Minimum time between two progress events sent for the same stage
+const scanProgressThrottle = 250 * time.Millisecond
+
+// scanProgressTracker sends the progress of a scan to the client.
+// The legacy events.EventScanProgress and events.EventScanStatus events are sent along with the structured progress.
+type scanProgressTracker struct {
+	mu             sync.Mutex
+	wsEventManager events.WSEventManagerInterface
+	start          time.Time
+	lastSent       time.Time
+	progress       ScanProgress
+}
+
+func newScanProgressTracker(wsEventManager events.WSEventManagerInterface) *scanProgressTracker {
+	return &scanProgressTracker{
+		wsEventManager: wsEventManager,
+		start:          time.Now(),
+		progress:       ScanProgress{Stage: ScanStageStarting},
+	}
+}
+
+// setStage moves the scan to a new stage.
+// An empty status does not change the status text.
+func (t *scanProgressTracker) setStage(stage ScanStage, percent int, status string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.progress.Stage = stage
+	t.progress.Percent = percent
+	t.wsEventManager.SendEvent(events.EventScanProgress, percent)
+	if status != "" {
+		t.wsEventManager.SendEvent(events.EventScanStatus, status)
+	}
+	t.send()
+}
+
+// update modifies the counters of the progress.
+// Updates are throttled, the latest counters are sent with the next update or stage.
+func (t *scanProgressTracker) update(fn func(p *ScanProgress)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fn(&t.progress)
+	if time.Since(t.lastSent) < scanProgressThrottle {
+		return
+	}
+	t.send()
+}
+
+// get returns a copy of the progress.
+func (t *scanProgressTracker) get() ScanProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.progress
+}
+
+func (t *scanProgressTracker) send() {
+	elapsed := time.Since(t.start)
+	t.progress.ElapsedMs = elapsed.Milliseconds()
+	t.progress.EtaMs = 0
+	// The remaining time is extrapolated from the elapsed time, stages that do not report any progress make it coarse
+	if t.progress.Percent > 0 && t.progress.Percent < 100 {
+		t.progress.EtaMs = elapsed.Milliseconds() * int64(100-t.progress.Percent) / int64(t.progress.Percent)
+	}
+	t.lastSent = time.Now()
+	t.wsEventManager.SendEvent(events.EventScanProgressDetails, t.progress)
+}
+
+// isCancelled returns true if the context is set and done.
+func isCancelled(ctx context.Context) bool {
+	return ctx != nil && ctx.Err() != nil
+}
@@ -0,0 +1,121 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"seanime/internal/events"
+	"seanime/internal/library/anime"
+	"seanime/internal/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanCanceller(t *testing.T) {
+	sc := NewScanCanceller()
+
+	assert.False(t, sc.IsScanning())
+	assert.False(t, sc.Cancel())
+
+	ctx1, done1 := sc.Start(context.Background())
+	ctx2, done2 := sc.Start(context.Background())
+	assert.True(t, sc.IsScanning())
+
+	assert.True(t, sc.Cancel())
+	assert.ErrorIs(t, ctx1.Err(), context.Canceled)
+	assert.ErrorIs(t, ctx2.Err(), context.Canceled)
+
+	// The scans are running until they return
+	assert.True(t, sc.IsScanning())
+	done1()
+	done2()
+	assert.False(t, sc.IsScanning())
+
+	// A nil canceller still returns a usable context
+	var nilSc *ScanCanceller
+	ctx, done := nilSc.Start(context.Background())
+	assert.NoError(t, ctx.Err())
+	done()
+	assert.Error(t, ctx.Err())
+}
+
+func TestScanner_Cancelled(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "[Group] Show - 01.mkv"), []byte("abc"), 0644))
+
+	logger := util.NewLogger()
+	existingLf := &anime.LocalFile{Path: filepath.Join(dir, "[Group] Show - 01.mkv"), MediaId: 1}
+
+	scn := &Scanner{
+		DirPath:            dir,
+		Logger:             logger,
+		WSEventManager:     events.NewMockWSEventManager(logger),
+		ExistingLocalFiles: []*anime.LocalFile{existingLf},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	lfs, err := scn.Scan(ctx)
+	assert.ErrorIs(t, err, ErrScanCancelled)
+	assert.Nil(t, lfs)
+	assert.Equal(t, ScanStageCancelled, scn.progress.get().Stage)
+	// Existing local files are not modified
+	assert.Equal(t, 1, existingLf.MediaId)
+}
+
+func TestScanProgressTracker(t *testing.T) {
+	logger := util.NewLogger()
+	tracker := newScanProgressTracker(events.NewMockWSEventManager(logger))
+
+	tracker.setStage(ScanStageFiles, 10, "Retrieving local files...")
+	tracker.update(func(p *ScanProgress) {
+		p.FilesFound = 12
+		p.FilesToScan = 10
+	})
+	tracker.setStage(ScanStageHydrating, 70, "Hydrating metadata...")
+
+	progress := tracker.get()
+	assert.Equal(t, ScanStageHydrating, progress.Stage)
+	assert.Equal(t, 70, progress.Percent)
+	// Throttled updates are kept and sent with the next stage
+	assert.Equal(t, 12, progress.FilesFound)
+	assert.Equal(t, 10, progress.FilesToScan)
+	assert.GreaterOrEqual(t, progress.EtaMs, int64(0))
+
+	tracker.setStage(ScanStageCompleted, 100, "Scan completed")
+	assert.Equal(t, int64(0), tracker.get().EtaMs)
+}
+
+func TestScanCanceller_Lock(t *testing.T) {
+	sc := NewScanCanceller()
+
+	unlock := sc.Lock()
+
+	locked := make(chan struct{})
+	go func() {
+		unlock2 := sc.Lock()
+		close(locked)
+		unlock2()
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("a second scan should wait for the first one to finish")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("the second scan should run once the first one has finished")
+	}
+
+	// A nil canceller does not lock
+	var nilSc *ScanCanceller
+	nilSc.Lock()()
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"seanime/internal/library/anime"
+	"seanime/internal/library/filesystem"
+	"seanime/internal/util"
+)
+
+// libraryDirs holds the library directories of a scan.
+type libraryDirs struct {
+	scanned []string // Enabled and online, sorted by descending priority
+	offline []string // Enabled but missing, their files are kept as-is
+}
+
+// getLibraryDirs returns the library directories to scan.
+// Without Roots, DirPath and OtherDirPaths are scanned.
+func (scn *Scanner) getLibraryDirs() *libraryDirs {
+	ret := &libraryDirs{
+		scanned: make([]string, 0),
+		offline: make([]string, 0),
+	}
+
+	if len(scn.Roots) == 0 {
+		ret.scanned = append(ret.scanned, scn.DirPath)
+		ret.scanned = append(ret.scanned, scn.OtherDirPaths...)
+		return ret
+	}
+
+	// Roots that had files and are now empty are likely unmounted drives
+	localFilePaths := anime.GetLocalFilePaths(scn.ExistingLocalFiles)
+	for _, root := range scn.Roots {
+		if !root.Enabled {
+			scn.Logger.Debug().Str("path", root.Path).Msg("scanner: Skipping disabled library directory")
+			continue
+		}
+		if filesystem.IsRootOffline(root.Path, localFilePaths) {
+			scn.Logger.Warn().Str("path", root.Path).Msg("scanner: Library directory is offline, keeping its files")
+			ret.offline = append(ret.offline, root.Path)
+			continue
+		}
+		ret.scanned = append(ret.scanned, root.Path)
+	}
+
+	if scn.ScanLogger != nil {
+		scn.ScanLogger.logger.Info().
+			Strs("scanned", ret.scanned).
+			Strs("offline", ret.offline).
+			Msg("Resolved library directories")
+	}
+
+	return ret
+}
+
+// separateOfflineLocalFiles removes the existing local files under offline directories from the existing local files.
+// They are returned flagged as offline so that they are kept in the library until their directory is back.
+// Existing local files are copied before being flagged.
+func (scn *Scanner) separateOfflineLocalFiles(offlineDirs []string) []*anime.LocalFile {
+	ret := make([]*anime.LocalFile, 0)
+	if scn.ExistingLocalFiles == nil {
+		return ret
+	}
+
+	others := make([]*anime.LocalFile, 0, len(scn.ExistingLocalFiles))
+	for _, lf := range scn.ExistingLocalFiles {
+		if util.IsSubdirectoryOfAny(offlineDirs, lf.Path) {
+			offlineLf := *lf
+			offlineLf.Offline = true
+			ret = append(ret, &offlineLf)
+			continue
+		}
+		// The directory of the file is back
+		if lf.Offline {
+			onlineLf := *lf
+			onlineLf.Offline = false
+			lf = &onlineLf
+		}
+		others = append(others, lf)
+	}
+	scn.ExistingLocalFiles = others
+
+	return ret
+}
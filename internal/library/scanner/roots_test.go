@@ -0,0 +1,91 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"seanime/internal/api/anilist"
+	"seanime/internal/database/models"
+	"seanime/internal/library/anime"
+	"seanime/internal/util"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_LibraryRoots(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "Anime")
+	externalPath := filepath.Join(dir, "External") // Unplugged
+	disabledPath := filepath.Join(dir, "Disabled")
+	require.NoError(t, os.MkdirAll(mainPath, 0755))
+	require.NoError(t, os.MkdirAll(disabledPath, 0755))
+
+	onlineLf := &anime.LocalFile{Path: filepath.Join(mainPath, "Show - 01.mkv"), MediaId: 1, Offline: true}
+	offlineLf := &anime.LocalFile{Path: filepath.Join(externalPath, "Other - 01.mkv"), MediaId: 2}
+	require.NoError(t, os.WriteFile(onlineLf.Path, []byte{}, 0644))
+
+	scn := &Scanner{
+		DirPath:       mainPath,
+		OtherDirPaths: []string{externalPath, disabledPath},
+		Roots: []*models.LibraryRoot{
+			{Path: externalPath, Enabled: true, Priority: 1},
+			{Path: mainPath, Enabled: true},
+			{Path: disabledPath, Enabled: false},
+		},
+		ExistingLocalFiles: []*anime.LocalFile{onlineLf, offlineLf},
+		Logger:             util.NewLogger(),
+	}
+
+	dirs := scn.getLibraryDirs()
+	assert.Equal(t, []string{mainPath}, dirs.scanned)
+	assert.Equal(t, []string{externalPath}, dirs.offline)
+
+	offlineLfs := scn.separateOfflineLocalFiles(dirs.offline)
+	if assert.Len(t, offlineLfs, 1) {
+		assert.Equal(t, offlineLf.Path, offlineLfs[0].Path)
+		assert.True(t, offlineLfs[0].Offline)
+	}
+	if assert.Len(t, scn.ExistingLocalFiles, 1) {
+		// The file of a directory that is back is no longer offline
+		assert.False(t, scn.ExistingLocalFiles[0].Offline)
+	}
+	// Existing local files are not modified
+	assert.False(t, offlineLf.Offline)
+	assert.True(t, onlineLf.Offline)
+
+	// The empty mount point of an unplugged drive is offline since files were found under it
+	require.NoError(t, os.MkdirAll(externalPath, 0755))
+	scn.ExistingLocalFiles = []*anime.LocalFile{onlineLf, offlineLf}
+	dirs = scn.getLibraryDirs()
+	assert.Equal(t, []string{mainPath}, dirs.scanned)
+	assert.Equal(t, []string{externalPath}, dirs.offline)
+}
+
+func TestMediaContainer_FilterByFormat(t *testing.T) {
+	newMedia := func(id int, title string, format anilist.MediaFormat) *anime.NormalizedMedia {
+		return anime.NewNormalizedMedia(&anilist.BaseAnime{
+			ID:     id,
+			Format: &format,
+			Title:  &anilist.BaseAnime_Title{Romaji: lo.ToPtr(title)},
+		})
+	}
+
+	mc := &MediaContainer{
+		NormalizedMedia: []*anime.NormalizedMedia{
+			newMedia(1, "Show", anilist.MediaFormatTv),
+			newMedia(2, "Show", anilist.MediaFormatMovie),
+		},
+	}
+	mc.indexTitles()
+
+	movies, ok := mc.FilterByFormat("MOVIE")
+	require.True(t, ok)
+	media, found := movies.GetMediaFromTitleOrSynonym(lo.ToPtr("Show"))
+	require.True(t, found)
+	assert.Equal(t, 2, media.ID)
+
+	_, ok = mc.FilterByFormat("OVA")
+	assert.False(t, ok)
+}
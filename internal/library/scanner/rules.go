@@ -0,0 +1,287 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"seanime/internal/database/models"
+	"seanime/internal/library/anime"
+	"seanime/internal/library/summary"
+	"seanime/internal/util"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	MatchingRuleTypePath          = "path"           // Files under a directory
+	MatchingRuleTypeRegex         = "regex"          // Files whose path matches a regular expression
+	MatchingRuleTypeTitleAlias    = "title-alias"    // Files whose parsed title is the pattern, optionally from a specific release group
+	MatchingRuleTypeEpisodeOffset = "episode-offset" // Offset added to the episode numbers of the files matched to a media
+)
+
+// MatchingRules evaluates the user-defined matching rules.
+// A nil *MatchingRules matches nothing.
+type MatchingRules struct {
+	paths   []*models.MatchingRule // Sorted from the deepest directory
+	regexes []*compiledMatchingRule
+	aliases []*models.MatchingRule
+	offsets map[int]*models.MatchingRule // Episode offsets by media ID
+}
+
+type compiledMatchingRule struct {
+	rule *models.MatchingRule
+	re   *regexp.Regexp
+}
+
+// ValidateMatchingRule returns an error if the rule cannot be evaluated.
+func ValidateMatchingRule(rule *models.MatchingRule) error {
+	if rule == nil {
+		return errors.New("rule is required")
+	}
+	if rule.MediaId <= 0 {
+		return errors.New("media ID is required")
+	}
+	switch rule.Type {
+	case MatchingRuleTypePath, MatchingRuleTypeTitleAlias:
+		if strings.TrimSpace(rule.Pattern) == "" {
+			return errors.New("pattern is required")
+		}
+	case MatchingRuleTypeRegex:
+		if rule.Pattern == "" {
+			return errors.New("pattern is required")
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid regular expression: %w", err)
+		}
+	case MatchingRuleTypeEpisodeOffset:
+		if rule.EpisodeOffset == 0 {
+			return errors.New("episode offset is required")
+		}
+	default:
+		return fmt.Errorf("unknown rule type: %s", rule.Type)
+	}
+	return nil
+}
+
+// NewMatchingRules prepares the enabled rules for evaluation.
+// Invalid rules are skipped.
+func NewMatchingRules(rules []*models.MatchingRule, logger *zerolog.Logger) *MatchingRules {
+	ret := &MatchingRules{
+		paths:   make([]*models.MatchingRule, 0),
+		regexes: make([]*compiledMatchingRule, 0),
+		aliases: make([]*models.MatchingRule, 0),
+		offsets: make(map[int]*models.MatchingRule),
+	}
+
+	for _, rule := range rules {
+		if rule == nil || !rule.Enabled {
+			continue
+		}
+		if err := ValidateMatchingRule(rule); err != nil {
+			if logger != nil {
+				logger.Warn().Err(err).Uint("id", rule.ID).Msg("scanner: Skipping invalid matching rule")
+			}
+			continue
+		}
+		switch rule.Type {
+		case MatchingRuleTypePath:
+			ret.paths = append(ret.paths, rule)
+		case MatchingRuleTypeRegex:
+			ret.regexes = append(ret.regexes, &compiledMatchingRule{rule: rule, re: regexp.MustCompile(rule.Pattern)})
+		case MatchingRuleTypeTitleAlias:
+			ret.aliases = append(ret.aliases, rule)
+		case MatchingRuleTypeEpisodeOffset:
+			ret.offsets[rule.MediaId] = rule
+		}
+	}
+
+	// The most specific directory wins
+	sort.SliceStable(ret.paths, func(i, j int) bool {
+		return len(normalizeRuleDir(ret.paths[i].Pattern)) > len(normalizeRuleDir(ret.paths[j].Pattern))
+	})
+
+	return ret
+}
+
+// Match returns the rule that assigns a media to the local file.
+// Directory rules are evaluated first, then regular expressions and title aliases.
+func (r *MatchingRules) Match(lf *anime.LocalFile) (*models.MatchingRule, bool) {
+	if r == nil || lf == nil {
+		return nil, false
+	}
+
+	path := util.NormalizePath(lf.Path)
+	for _, rule := range r.paths {
+		if strings.HasPrefix(path, normalizeRuleDir(rule.Pattern)+"/") {
+			return rule, true
+		}
+	}
+
+	slashPath := filepath.ToSlash(lf.Path)
+	for _, c := range r.regexes {
+		if c.re.MatchString(slashPath) {
+			return c.rule, true
+		}
+	}
+
+	title := normalizeRuleTitle(lf.GetParsedTitle())
+	if title != "" {
+		for _, rule := range r.aliases {
+			if normalizeRuleTitle(rule.Pattern) != title {
+				continue
+			}
+			if rule.ReleaseGroup != "" && (lf.ParsedData == nil || !strings.EqualFold(strings.TrimSpace(lf.ParsedData.ReleaseGroup), strings.TrimSpace(rule.ReleaseGroup))) {
+				continue
+			}
+			return rule, true
+		}
+	}
+
+	return nil, false
+}
+
+// MediaIds returns the IDs of the media files can be assigned to by the rules.
+func (r *MatchingRules) MediaIds() []int {
+	if r == nil {
+		return nil
+	}
+	ret := make([]int, 0)
+	for _, rule := range r.paths {
+		ret = append(ret, rule.MediaId)
+	}
+	for _, c := range r.regexes {
+		ret = append(ret, c.rule.MediaId)
+	}
+	for _, rule := range r.aliases {
+		ret = append(ret, rule.MediaId)
+	}
+	return ret
+}
+
+// ApplyEpisodeOffsets shifts the episode numbers of the hydrated main episodes matched to media with an episode offset rule.
+func (r *MatchingRules) ApplyEpisodeOffsets(lfs []*anime.LocalFile, summaryLogger *summary.ScanSummaryLogger) {
+	if r == nil || len(r.offsets) == 0 {
+		return
+	}
+	for _, lf := range lfs {
+		rule, ok := r.offsets[lf.MediaId]
+		if !ok || lf.Metadata == nil || lf.Metadata.Type != anime.LocalFileTypeMain {
+			continue
+		}
+		episode := lf.Metadata.Episode + rule.EpisodeOffset
+		if episode < 0 {
+			continue
+		}
+		summaryLogger.LogMatchingRule(lf, rule.ID, fmt.Sprintf("Episode %d shifted to %d", lf.Metadata.Episode, episode))
+		lf.Metadata.Episode = episode
+		lf.Metadata.AniDBEpisode = strconv.Itoa(episode)
+	}
+}
+
+func normalizeRuleDir(dir string) string {
+	return strings.TrimRight(util.NormalizePath(filepath.Clean(dir)), "/")
+}
+
+func normalizeRuleTitle(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// LearnMatchingRule returns a rule that reproduces a manual match of local files to a media.
+// Files sharing a directory that is not a library root are matched by directory, if all the local files under it belong to the media.
+// Otherwise, a single file is matched by file name and several files by title alias.
+// localFiles are all the local files of the library, after the match.
+// It returns false if no rule can describe the files.
+func LearnMatchingRule(lfs []*anime.LocalFile, mediaId int, libraryPaths []string, localFiles []*anime.LocalFile) (*models.MatchingRule, bool) {
+	if len(lfs) == 0 || mediaId <= 0 {
+		return nil, false
+	}
+
+	dir := filepath.Dir(lfs[0].Path)
+	sameDir := true
+	for _, lf := range lfs[1:] {
+		if !util.IsSameDir(filepath.Dir(lf.Path), dir) {
+			sameDir = false
+			break
+		}
+	}
+	isLibraryRoot := false
+	for _, libraryPath := range libraryPaths {
+		if libraryPath != "" && util.IsSameDir(libraryPath, dir) {
+			isLibraryRoot = true
+			break
+		}
+	}
+
+	if sameDir && !isLibraryRoot && isDirOfMedia(dir, lfs, mediaId, localFiles) {
+		return &models.MatchingRule{
+			Type:    MatchingRuleTypePath,
+			Pattern: dir,
+			MediaId: mediaId,
+			Enabled: true,
+			Learned: true,
+		}, true
+	}
+
+	// Other files of the directory belong to other media, only match the file itself
+	if len(lfs) == 1 {
+		return &models.MatchingRule{
+			Type:    MatchingRuleTypeRegex,
+			Pattern: "(?i)(^|/)" + regexp.QuoteMeta(lfs[0].Name) + "$",
+			MediaId: mediaId,
+			Enabled: true,
+			Learned: true,
+		}, true
+	}
+
+	// All the files must share the same title
+	title := lfs[0].GetParsedTitle()
+	releaseGroup := ""
+	if lfs[0].ParsedData != nil {
+		releaseGroup = lfs[0].ParsedData.ReleaseGroup
+	}
+	if normalizeRuleTitle(title) == "" {
+		return nil, false
+	}
+	for _, lf := range lfs[1:] {
+		if normalizeRuleTitle(lf.GetParsedTitle()) != normalizeRuleTitle(title) {
+			return nil, false
+		}
+		if lf.ParsedData == nil || !strings.EqualFold(lf.ParsedData.ReleaseGroup, releaseGroup) {
+			releaseGroup = ""
+		}
+	}
+
+	return &models.MatchingRule{
+		Type:         MatchingRuleTypeTitleAlias,
+		Pattern:      title,
+		ReleaseGroup: releaseGroup,
+		MediaId:      mediaId,
+		Enabled:      true,
+		Learned:      true,
+	}, true
+}
+
+// isDirOfMedia returns true if all the local files under the directory are matched to the media or are part of the manual match.
+// A directory rule would match all of them.
+func isDirOfMedia(dir string, lfs []*anime.LocalFile, mediaId int, localFiles []*anime.LocalFile) bool {
+	matched := make(map[string]struct{}, len(lfs))
+	for _, lf := range lfs {
+		matched[lf.GetNormalizedPath()] = struct{}{}
+	}
+	prefix := normalizeRuleDir(dir) + "/"
+	for _, lf := range localFiles {
+		if !strings.HasPrefix(lf.GetNormalizedPath(), prefix) {
+			continue
+		}
+		if _, ok := matched[lf.GetNormalizedPath()]; !ok && lf.MediaId != mediaId {
+			return false
+		}
+	}
+	return true
+}
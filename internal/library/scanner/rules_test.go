@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"path/filepath"
+	"seanime/internal/database/models"
+	"seanime/internal/library/anime"
+	"seanime/internal/library/summary"
+	"seanime/internal/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchingRules_Match(t *testing.T) {
+	libDir := filepath.FromSlash("/lib")
+
+	rules := NewMatchingRules([]*models.MatchingRule{
+		{BaseModel: models.BaseModel{ID: 1}, Type: MatchingRuleTypePath, Pattern: filepath.Join(libDir, "Show"), MediaId: 1, Enabled: true},
+		{BaseModel: models.BaseModel{ID: 2}, Type: MatchingRuleTypePath, Pattern: filepath.Join(libDir, "Show", "Season 2"), MediaId: 2, Enabled: true},
+		{BaseModel: models.BaseModel{ID: 3}, Type: MatchingRuleTypeRegex, Pattern: `(?i)/Movies/.*Movie`, MediaId: 3, Enabled: true},
+		{BaseModel: models.BaseModel{ID: 4}, Type: MatchingRuleTypeTitleAlias, Pattern: "Alias Title", ReleaseGroup: "SubsPlease", MediaId: 4, Enabled: true},
+		{BaseModel: models.BaseModel{ID: 5}, Type: MatchingRuleTypeTitleAlias, Pattern: "Other Title", MediaId: 5, Enabled: false},
+		{BaseModel: models.BaseModel{ID: 6}, Type: MatchingRuleTypeRegex, Pattern: `(`, MediaId: 6, Enabled: true}, // Invalid
+	}, util.NewLogger())
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedRuleId uint
+		expectedMatch  bool
+	}{
+		{name: "directory rule", path: filepath.Join(libDir, "Show", "Show - 01.mkv"), expectedRuleId: 1, expectedMatch: true},
+		{name: "deepest directory rule wins", path: filepath.Join(libDir, "Show", "Season 2", "Show S2 - 01.mkv"), expectedRuleId: 2, expectedMatch: true},
+		{name: "directory rule does not match sibling prefix", path: filepath.Join(libDir, "Show 2", "Show 2 - 01.mkv"), expectedMatch: false},
+		{name: "regex rule", path: filepath.Join(libDir, "Movies", "The Movie (2020).mkv"), expectedRuleId: 3, expectedMatch: true},
+		{name: "title alias with release group", path: filepath.Join(libDir, "[SubsPlease] Alias Title - 01.mkv"), expectedRuleId: 4, expectedMatch: true},
+		{name: "title alias from another release group", path: filepath.Join(libDir, "[Erai-raws] Alias Title - 01.mkv"), expectedMatch: false},
+		{name: "disabled rule", path: filepath.Join(libDir, "Other Title - 01.mkv"), expectedMatch: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lf := anime.NewLocalFile(tt.path, libDir)
+			rule, ok := rules.Match(lf)
+			require.Equal(t, tt.expectedMatch, ok)
+			if tt.expectedMatch {
+				assert.Equal(t, tt.expectedRuleId, rule.ID)
+			}
+		})
+	}
+
+	assert.ElementsMatch(t, []int{1, 2, 3, 4}, rules.MediaIds())
+}
+
+func TestMatchingRules_ApplyEpisodeOffsets(t *testing.T) {
+	rules := NewMatchingRules([]*models.MatchingRule{
+		{BaseModel: models.BaseModel{ID: 1}, Type: MatchingRuleTypeEpisodeOffset, MediaId: 1, EpisodeOffset: -12, Enabled: true},
+	}, nil)
+
+	main := &anime.LocalFile{MediaId: 1, Metadata: &anime.LocalFileMetadata{Episode: 13, AniDBEpisode: "13", Type: anime.LocalFileTypeMain}}
+	special := &anime.LocalFile{MediaId: 1, Metadata: &anime.LocalFileMetadata{Episode: 1, AniDBEpisode: "S1", Type: anime.LocalFileTypeSpecial}}
+	other := &anime.LocalFile{MediaId: 2, Metadata: &anime.LocalFileMetadata{Episode: 13, AniDBEpisode: "13", Type: anime.LocalFileTypeMain}}
+
+	summaryLogger := summary.NewScanSummaryLogger()
+	rules.ApplyEpisodeOffsets([]*anime.LocalFile{main, special, other}, summaryLogger)
+
+	assert.Equal(t, 1, main.Metadata.Episode)
+	assert.Equal(t, "1", main.Metadata.AniDBEpisode)
+	assert.Equal(t, "S1", special.Metadata.AniDBEpisode)
+	assert.Equal(t, 13, other.Metadata.Episode)
+	assert.Len(t, summaryLogger.Logs, 1)
+}
+
+func TestLearnMatchingRule(t *testing.T) {
+	libDir := filepath.FromSlash("/lib")
+
+	// Files in their own directory are matched by directory
+	ep1 := anime.NewLocalFile(filepath.Join(libDir, "Show", "Show - 01.mkv"), libDir)
+	ep2 := anime.NewLocalFile(filepath.Join(libDir, "Show", "Show - 02.mkv"), libDir)
+	rule, ok := LearnMatchingRule([]*anime.LocalFile{ep1, ep2}, 1, []string{libDir}, []*anime.LocalFile{ep1, ep2})
+	require.True(t, ok)
+	assert.Equal(t, MatchingRuleTypePath, rule.Type)
+	assert.Equal(t, filepath.Join(libDir, "Show"), rule.Pattern)
+	assert.True(t, rule.Learned)
+
+	// Files already matched to the media do not prevent directory rules
+	ep3 := anime.NewLocalFile(filepath.Join(libDir, "Show", "Extras", "Show - NCOP.mkv"), libDir)
+	ep3.MediaId = 1
+	rule, ok = LearnMatchingRule([]*anime.LocalFile{ep1}, 1, []string{libDir}, []*anime.LocalFile{ep1, ep2, ep3})
+	require.True(t, ok)
+	assert.Equal(t, MatchingRuleTypeRegex, rule.Type, "the other episode belongs to another media")
+
+	ep2.MediaId = 1
+	rule, ok = LearnMatchingRule([]*anime.LocalFile{ep1}, 1, []string{libDir}, []*anime.LocalFile{ep1, ep2, ep3})
+	require.True(t, ok)
+	assert.Equal(t, MatchingRuleTypePath, rule.Type)
+
+	// A single file of a directory shared with other media is matched by file name
+	batchDir := filepath.Join(libDir, "Batch")
+	mismatched := anime.NewLocalFile(filepath.Join(batchDir, "Show S2 - 01.mkv"), libDir)
+	other := anime.NewLocalFile(filepath.Join(batchDir, "Show - 01.mkv"), libDir)
+	other.MediaId = 2
+	rule, ok = LearnMatchingRule([]*anime.LocalFile{mismatched}, 1, []string{libDir}, []*anime.LocalFile{mismatched, other})
+	require.True(t, ok)
+	assert.Equal(t, MatchingRuleTypeRegex, rule.Type)
+	require.NoError(t, ValidateMatchingRule(rule))
+	rules := NewMatchingRules([]*models.MatchingRule{rule}, nil)
+	_, ok = rules.Match(mismatched)
+	assert.True(t, ok)
+	_, ok = rules.Match(other)
+	assert.False(t, ok)
+
+	// Files at the root of the library are matched by title
+	rootEp1 := anime.NewLocalFile(filepath.Join(libDir, "[SubsPlease] Show - 01.mkv"), libDir)
+	rootEp2 := anime.NewLocalFile(filepath.Join(libDir, "[SubsPlease] Show - 02.mkv"), libDir)
+	rule, ok = LearnMatchingRule([]*anime.LocalFile{rootEp1, rootEp2}, 1, []string{libDir}, []*anime.LocalFile{rootEp1, rootEp2})
+	require.True(t, ok)
+	assert.Equal(t, MatchingRuleTypeTitleAlias, rule.Type)
+	assert.Equal(t, "Show", rule.Pattern)
+	assert.Equal(t, "SubsPlease", rule.ReleaseGroup)
+
+	// Files with different titles cannot be described by a single rule
+	showEp := anime.NewLocalFile(filepath.Join(libDir, "Show - 01.mkv"), libDir)
+	otherEp := anime.NewLocalFile(filepath.Join(libDir, "Other - 01.mkv"), libDir)
+	_, ok = LearnMatchingRule([]*anime.LocalFile{showEp, otherEp}, 1, []string{libDir}, []*anime.LocalFile{showEp, otherEp})
+	assert.False(t, ok)
+}
@@ -1,12 +1,16 @@
 package scanner
 
 import (
+	"context"
 	"errors"
 	"github.com/rs/zerolog"
 	"github.com/samber/lo"
 	lop "github.com/samber/lo/parallel"
+	"os"
+	"path/filepath"
 	"seanime/internal/api/anilist"
 	"seanime/internal/api/metadata"
+	"seanime/internal/database/models"
 	"seanime/internal/events"
 	"seanime/internal/library/anime"
 	"seanime/internal/library/filesystem"
@@ -14,12 +18,16 @@ import (
 	"seanime/internal/platforms/platform"
 	"seanime/internal/util"
 	"seanime/internal/util/limiter"
+	"slices"
 	"sync"
 )
 
 type Scanner struct {
-	DirPath            string
-	OtherDirPaths      []string
+	DirPath       string
+	OtherDirPaths []string
+	// Roots, if set, replaces DirPath and OtherDirPaths as the directories to scan.
+	// Disabled roots are not scanned, the files of offline roots are kept.
+	Roots              []*models.LibraryRoot
 	Enhanced           bool
 	Platform           platform.Platform
 	Logger             *zerolog.Logger
@@ -32,16 +40,53 @@ type Scanner struct {
 	MetadataProvider   metadata.Provider
 	MatchingThreshold  float64
 	MatchingAlgorithm  string
+	// TargetPaths restricts the scan to the files under these paths.
+	// Other existing local files are kept as-is.
+	TargetPaths []string
+	// MatchingRules are evaluated before fuzzy matching.
+	MatchingRules []*models.MatchingRule
+	// MediaCache, if set, is used to match the files instead of fetching the media.
+	// It is replaced when the media are fetched during the scan (non-enhanced mode only).
+	MediaCache *MediaCache
+	// HashFiles enables the computation of the CRC32 and ED2K hashes of the scanned files.
+	HashFiles     bool
+	FileHashCache FileHashCache     // optional
+	HashLookup    HashLookupBackend // optional, used to identify hashed files
+	// ProbeMediaInfo enables the extraction of the technical metadata of the scanned files using FFprobe.
+	ProbeMediaInfo     bool
+	FfprobePath        string             // optional
+	FileMediaInfoCache FileMediaInfoCache // optional
+
+	progress *scanProgressTracker
 }
 
 // Scan will scan the directory and return a list of anime.LocalFile.
-func (scn *Scanner) Scan() (lfs []*anime.LocalFile, err error) {
+// If the context is cancelled, the scan stops and returns ErrScanCancelled without any local file.
+func (scn *Scanner) Scan(ctx context.Context) (lfs []*anime.LocalFile, err error) {
 	defer util.HandlePanicWithError(&err)
 
-	scn.WSEventManager.SendEvent(events.EventScanProgress, 0)
-	scn.WSEventManager.SendEvent(events.EventScanStatus, "Retrieving local files...")
+	scn.progress = newScanProgressTracker(scn.WSEventManager)
+	scn.progress.setStage(ScanStageStarting, 0, "Retrieving local files...")
+
+	defer func() {
+		if !errors.Is(err, ErrScanCancelled) {
+			return
+		}
+		// Partial results are discarded
+		lfs = nil
+		scn.Logger.Info().Msg("scanner: Scan cancelled")
+		scn.progress.setStage(ScanStageCancelled, scn.progress.get().Percent, "Scan cancelled")
+		if scn.ScanLogger != nil {
+			scn.ScanLogger.logger.Info().
+				Str("stage", string(scn.progress.get().Stage)).
+				Msg("Scan cancelled")
+		}
+	}()
 
 	completeAnimeCache := anilist.NewCompleteAnimeCache()
+	if scn.MediaCache != nil && scn.MediaCache.CompleteAnimeCache != nil {
+		completeAnimeCache = scn.MediaCache.CompleteAnimeCache
+	}
 
 	// Create a new Anilist rate limiter
 	anilistRateLimiter := limiter.NewAnilistLimiter()
@@ -51,60 +96,22 @@ func (scn *Scanner) Scan() (lfs []*anime.LocalFile, err error) {
 	}
 
 	scn.Logger.Debug().Msg("scanner: Starting scan")
-	scn.WSEventManager.SendEvent(events.EventScanProgress, 10)
-	scn.WSEventManager.SendEvent(events.EventScanStatus, "Retrieving local files...")
+	scn.progress.setStage(ScanStageFiles, 10, "Retrieving local files...")
 
 	// +---------------------+
 	// |     Local Files     |
 	// +---------------------+
 
-	paths, err := filesystem.GetMediaFilePathsFromDirS(scn.DirPath)
+	dirs := scn.getLibraryDirs()
+	offlineLfs := scn.separateOfflineLocalFiles(dirs.offline)
+
+	paths, ignoredPaths, err := scn.getFilePaths(ctx, dirs.scanned)
 	if err != nil {
 		return nil, err
 	}
-
-	if scn.ScanLogger != nil {
-		scn.ScanLogger.logger.Info().
-			Any("count", len(paths)).
-			Msg("Retrieved file paths from main directory")
-	}
-
-	// Get local files
-	//localFiles, err := GetLocalFilesFromDir(scn.DirPath, scn.Logger)
-	//if err != nil {
-	//	return nil, err
-	//}
-
-	localFilePathsMap := make(map[string]struct{})
-	for _, path := range paths {
-		localFilePathsMap[util.NormalizePath(path)] = struct{}{}
-	}
-
-	// Get local files from other directories
-	for _, dirPath := range scn.OtherDirPaths {
-		//otherLocalFiles, err := GetLocalFilesFromDir(dirPath, scn.Logger)
-		otherPaths, err := filesystem.GetMediaFilePathsFromDirS(dirPath)
-		if scn.ScanLogger != nil {
-			scn.ScanLogger.logger.Info().
-				Any("count", len(otherPaths)).
-				Msgf("Retrieved file paths from other directory: %s", dirPath)
-		}
-		if err != nil {
-			return nil, err
-		}
-		for _, path := range otherPaths {
-			//if _, ok := localFilePathsMap[strings.ToLower(path)]; !ok {
-			if _, ok := localFilePathsMap[util.NormalizePath(path)]; !ok {
-				paths = append(paths, path)
-			}
-		}
-	}
-
-	if scn.ScanLogger != nil {
-		scn.ScanLogger.logger.Info().
-			Any("count", len(paths)).
-			Msg("Retrieved file paths from all directories")
-	}
+	scn.progress.update(func(p *ScanProgress) {
+		p.FilesFound = len(paths)
+	})
 
 	// +---------------------+
 	// | Filter local files  |
@@ -114,12 +121,16 @@ func (scn *Scanner) Scan() (lfs []*anime.LocalFile, err error) {
 
 	// Get skipped files depending on options
 	skippedLfs := make(map[string]*anime.LocalFile)
-	if (scn.SkipLockedFiles || scn.SkipIgnoredFiles) && scn.ExistingLocalFiles != nil {
+	if (scn.SkipLockedFiles || scn.SkipIgnoredFiles || len(scn.TargetPaths) > 0) && scn.ExistingLocalFiles != nil {
 		// Retrieve skipped files from existing local files
 		for _, lf := range scn.ExistingLocalFiles {
 			if scn.SkipLockedFiles && lf.IsLocked() {
 				skippedLfs[lf.GetNormalizedPath()] = lf
-			} else if scn.SkipIgnoredFiles && lf.IsIgnored() {
+			} else if scn.SkipIgnoredFiles && lf.Ignored {
+				// Files excluded by a .seaignore file are scanned again in case the rule was removed
+				skippedLfs[lf.GetNormalizedPath()] = lf
+			} else if len(scn.TargetPaths) > 0 && !scn.isTargeted(lf.Path) {
+				// Files outside the targeted paths are not scanned
 				skippedLfs[lf.GetNormalizedPath()] = lf
 			}
 		}
@@ -155,8 +166,7 @@ func (scn *Scanner) Scan() (lfs []*anime.LocalFile, err error) {
 	for _, lf := range localFiles {
 		if scn.ScanLogger != nil {
 			scn.ScanLogger.logger.Trace().
-				Str("path", lf.Path).
-				Str("filename", lf.Name).
+				EmbedObject(scanLogFile{lf}).
 				Interface("parsedData", lf.ParsedData).
 				Interface("parsedFolderData", lf.ParsedFolderData).
 				Msg("Parsed local file")
@@ -169,8 +179,7 @@ func (scn *Scanner) Scan() (lfs []*anime.LocalFile, err error) {
 	}
 
 	// Remove local files from both skipped and un-skipped files if they are not under any of the directories
-	allLibraries := []string{scn.DirPath}
-	allLibraries = append(allLibraries, scn.OtherDirPaths...)
+	allLibraries := dirs.scanned
 	localFiles = lo.Filter(localFiles, func(lf *anime.LocalFile, _ int) bool {
 		if !util.IsSubdirectoryOfAny(allLibraries, lf.Path) {
 			return false
@@ -189,14 +198,35 @@ func (scn *Scanner) Scan() (lfs []*anime.LocalFile, err error) {
 		}
 	}
 
+	// +---------------------+
+	// |    Ignored files    |
+	// +---------------------+
+
+	// Files excluded by .seaignore files are not matched, they are kept as ignored local files
+	ignoredLfs := scn.getIgnoredLocalFiles(ignoredPaths)
+	for _, lf := range ignoredLfs {
+		delete(skippedLfs, lf.GetNormalizedPath())
+	}
+	// The remaining skipped files are no longer excluded by a .seaignore file
+	for path, lf := range skippedLfs {
+		if lf.IgnoredBySeaignore {
+			copied := *lf
+			copied.IgnoredBySeaignore = false
+			skippedLfs[path] = &copied
+		}
+	}
+
+	scn.progress.update(func(p *ScanProgress) {
+		p.FilesToScan = len(localFiles)
+	})
+
 	// +---------------------+
 	// |  No files to scan   |
 	// +---------------------+
 
 	// If there are no local files to scan (all files are skipped, or a file was deleted)
 	if len(localFiles) == 0 {
-		scn.WSEventManager.SendEvent(events.EventScanProgress, 90)
-		scn.WSEventManager.SendEvent(events.EventScanStatus, "Verifying file integrity...")
+		scn.progress.setStage(ScanStageFinalizing, 90, "Verifying file integrity...")
 		// Add skipped files
 		if len(skippedLfs) > 0 {
 			for _, sf := range skippedLfs {
@@ -205,88 +235,123 @@ func (scn *Scanner) Scan() (lfs []*anime.LocalFile, err error) {
 				}
 			}
 		}
+		localFiles = append(localFiles, ignoredLfs...)
+		localFiles = append(localFiles, offlineLfs...)
+		scn.pruneFileCaches(localFiles)
 		scn.Logger.Debug().Msg("scanner: Scan completed")
-		scn.WSEventManager.SendEvent(events.EventScanProgress, 100)
-		scn.WSEventManager.SendEvent(events.EventScanStatus, "Scan completed")
+		scn.progress.setStage(ScanStageCompleted, 100, "Scan completed")
 
 		return localFiles, nil
 	}
 
-	scn.WSEventManager.SendEvent(events.EventScanProgress, 20)
-	if scn.Enhanced {
-		scn.WSEventManager.SendEvent(events.EventScanStatus, "Fetching media detected from file titles...")
-	} else {
-		scn.WSEventManager.SendEvent(events.EventScanStatus, "Fetching media...")
+	// +---------------------+
+	// |     FileHasher      |
+	// +---------------------+
+
+	var hashMatches *HashMatches
+	if scn.HashFiles {
+		scn.progress.setStage(ScanStageHashing, 12, "Hashing files...")
+
+		hasher := &FileHasher{
+			LocalFiles:        localFiles,
+			Cache:             scn.FileHashCache,
+			Logger:            scn.Logger,
+			ScanLogger:        scn.ScanLogger,
+			ScanSummaryLogger: scn.ScanSummaryLogger,
+			ctx:               ctx,
+		}
+		hasher.HashLocalFiles()
+		if isCancelled(ctx) {
+			return nil, ErrScanCancelled
+		}
+
+		hashMatches = LookupHashes(scn.HashLookup, localFiles, scn.Logger)
 	}
 
 	// +---------------------+
-	// |    MediaFetcher     |
+	// |     FileProber      |
 	// +---------------------+
 
-	// Fetch media needed for matching
-	mf, err := NewMediaFetcher(&MediaFetcherOptions{
-		Enhanced:               scn.Enhanced,
-		Platform:               scn.Platform,
-		MetadataProvider:       scn.MetadataProvider,
-		LocalFiles:             localFiles,
-		CompleteAnimeCache:     completeAnimeCache,
-		Logger:                 scn.Logger,
-		AnilistRateLimiter:     anilistRateLimiter,
-		DisableAnimeCollection: false,
-		ScanLogger:             scn.ScanLogger,
-	})
-	if err != nil {
-		return nil, err
+	if scn.ProbeMediaInfo {
+		scn.progress.setStage(ScanStageProbing, 16, "Reading media info...")
+
+		prober := &FileProber{
+			LocalFiles:  localFiles,
+			FfprobePath: scn.FfprobePath,
+			Cache:       scn.FileMediaInfoCache,
+			Logger:      scn.Logger,
+			ScanLogger:  scn.ScanLogger,
+			ctx:         ctx,
+		}
+		prober.ProbeLocalFiles()
+		if isCancelled(ctx) {
+			return nil, ErrScanCancelled
+		}
 	}
 
-	scn.WSEventManager.SendEvent(events.EventScanProgress, 40)
-	scn.WSEventManager.SendEvent(events.EventScanStatus, "Matching local files...")
+	if scn.Enhanced {
+		scn.progress.setStage(ScanStageFetching, 20, "Fetching media detected from file titles...")
+	} else {
+		scn.progress.setStage(ScanStageFetching, 20, "Fetching media...")
+	}
 
 	// +---------------------+
-	// |   MediaContainer    |
+	// |    MediaFetcher     |
 	// +---------------------+
 
-	// Create a new container for media
-	mc := NewMediaContainer(&MediaContainerOptions{
-		AllMedia:   mf.AllMedia,
-		ScanLogger: scn.ScanLogger,
+	// Fetch media needed for matching, or reuse the media of a previous scan
+	sm, err := scn.getScanMedia(ctx, localFiles, completeAnimeCache, anilistRateLimiter)
+	if err != nil {
+		return nil, err
+	}
+	if isCancelled(ctx) {
+		return nil, ErrScanCancelled
+	}
+	scn.progress.update(func(p *ScanProgress) {
+		p.MediaFetched = len(sm.container.NormalizedMedia)
 	})
 
-	scn.Logger.Debug().
-		Any("count", len(mc.NormalizedMedia)).
-		Msg("media container: Media container created")
+	scn.progress.setStage(ScanStageMatching, 40, "Matching local files...")
 
 	// +---------------------+
 	// |      Matcher        |
 	// +---------------------+
 
-	// Create a new matcher
-	matcher := &Matcher{
-		LocalFiles:         localFiles,
-		MediaContainer:     mc,
-		CompleteAnimeCache: completeAnimeCache,
-		Logger:             scn.Logger,
-		ScanLogger:         scn.ScanLogger,
-		ScanSummaryLogger:  scn.ScanSummaryLogger,
-		Algorithm:          scn.MatchingAlgorithm,
-		Threshold:          scn.MatchingThreshold,
-	}
+	rules := NewMatchingRules(scn.MatchingRules, scn.Logger)
 
-	scn.WSEventManager.SendEvent(events.EventScanProgress, 60)
-
-	err = matcher.MatchLocalFilesWithMedia()
+	err = scn.matchLocalFiles(ctx, localFiles, sm.container, completeAnimeCache, rules, hashMatches)
 	if err != nil {
 		// If the matcher received no local files, return an error
 		if errors.Is(err, ErrNoLocalFiles) {
 			scn.Logger.Debug().Msg("scanner: Scan completed")
-			scn.WSEventManager.SendEvent(events.EventScanProgress, 100)
-			scn.WSEventManager.SendEvent(events.EventScanStatus, "Scan completed")
+			scn.progress.setStage(ScanStageCompleted, 100, "Scan completed")
 		}
 		return nil, err
 	}
 
-	scn.WSEventManager.SendEvent(events.EventScanProgress, 70)
-	scn.WSEventManager.SendEvent(events.EventScanStatus, "Hydrating metadata...")
+	if sm.cached {
+		sm, err = scn.rematchWithFreshMedia(ctx, sm, localFiles, completeAnimeCache, anilistRateLimiter, rules, hashMatches)
+		if err != nil {
+			return nil, err
+		}
+	}
+	mc := sm.container
+	if isCancelled(ctx) {
+		return nil, ErrScanCancelled
+	}
+
+	matchedCount := lo.CountBy(localFiles, func(lf *anime.LocalFile) bool {
+		return lf.MediaId != 0
+	})
+	scn.progress.update(func(p *ScanProgress) {
+		p.MediaFetched = len(mc.NormalizedMedia)
+		p.Matched = matchedCount
+	})
+
+	// Media assigned by matching rules or hash lookups might not be in the container
+	allMedia := scn.addAssignedMedia(mc.NormalizedMedia, append(rules.MediaIds(), hashMatches.MediaIds()...), localFiles, anilistRateLimiter)
+
+	scn.progress.setStage(ScanStageHydrating, 70, "Hydrating metadata...")
 
 	// +---------------------+
 	// |    FileHydrator     |
@@ -294,7 +359,7 @@ func (scn *Scanner) Scan() (lfs []*anime.LocalFile, err error) {
 
 	// Create a new hydrator
 	hydrator := &FileHydrator{
-		AllMedia:           mc.NormalizedMedia,
+		AllMedia:           allMedia,
 		LocalFiles:         localFiles,
 		MetadataProvider:   scn.MetadataProvider,
 		Platform:           scn.Platform,
@@ -303,10 +368,26 @@ func (scn *Scanner) Scan() (lfs []*anime.LocalFile, err error) {
 		Logger:             scn.Logger,
 		ScanLogger:         scn.ScanLogger,
 		ScanSummaryLogger:  scn.ScanSummaryLogger,
+		ctx:                ctx,
+		onHydrated: func(count int) {
+			scn.progress.update(func(p *ScanProgress) {
+				p.Hydrated += count
+				if matchedCount > 0 {
+					p.Percent = 70 + 10*min(p.Hydrated, matchedCount)/matchedCount
+				}
+			})
+		},
 	}
 	hydrator.HydrateMetadata()
+	// The AniList collection is modified below, the scan cannot be cancelled past this point
+	if isCancelled(ctx) {
+		return nil, ErrScanCancelled
+	}
+
+	hashMatches.ApplyEpisodes(localFiles)
+	rules.ApplyEpisodeOffsets(localFiles, scn.ScanSummaryLogger)
 
-	scn.WSEventManager.SendEvent(events.EventScanProgress, 80)
+	scn.progress.setStage(ScanStageFinalizing, 80, "")
 
 	// +---------------------+
 	// |  Add missing media  |
@@ -314,19 +395,21 @@ func (scn *Scanner) Scan() (lfs []*anime.LocalFile, err error) {
 
 	// Add non-added media entries to AniList collection
 	// Max of 4 to avoid rate limit issues
-	if len(mf.UnknownMediaIds) < 5 {
-		scn.WSEventManager.SendEvent(events.EventScanStatus, "Adding missing media to AniList...")
+	if len(sm.unknownMediaIds) < 5 {
+		scn.progress.setStage(ScanStageFinalizing, 80, "Adding missing media to AniList...")
 
-		if err = scn.Platform.AddMediaToCollection(mf.UnknownMediaIds); err != nil {
+		if err = scn.Platform.AddMediaToCollection(sm.unknownMediaIds); err != nil {
 			scn.Logger.Warn().Msg("scanner: An error occurred while adding media to planning list: " + err.Error())
 		}
 	}
 
-	scn.WSEventManager.SendEvent(events.EventScanProgress, 90)
-	scn.WSEventManager.SendEvent(events.EventScanStatus, "Verifying file integrity...")
+	scn.progress.setStage(ScanStageFinalizing, 90, "Verifying file integrity...")
+
+	// Add the ignored files so that they are shown in the summary
+	localFiles = append(localFiles, ignoredLfs...)
 
 	// Hydrate the summary logger before merging files
-	scn.ScanSummaryLogger.HydrateData(localFiles, mc.NormalizedMedia, mf.AnimeCollectionWithRelations)
+	scn.ScanSummaryLogger.HydrateData(localFiles, allMedia, sm.animeCollection)
 
 	// +---------------------+
 	// |    Merge files      |
@@ -351,16 +434,174 @@ func (scn *Scanner) Scan() (lfs []*anime.LocalFile, err error) {
 		wg.Wait()
 	}
 
+	// Keep the files of offline directories
+	localFiles = append(localFiles, offlineLfs...)
+
+	// Cached hashes and media info of removed files are not needed anymore
+	scn.pruneFileCaches(localFiles)
+
 	scn.Logger.Info().Msg("scanner: Scan completed")
-	scn.WSEventManager.SendEvent(events.EventScanProgress, 100)
-	scn.WSEventManager.SendEvent(events.EventScanStatus, "Scan completed")
+	scn.progress.setStage(ScanStageCompleted, 100, "Scan completed")
 
 	if scn.ScanLogger != nil {
 		scn.ScanLogger.logger.Info().
 			Int("count", len(localFiles)).
-			Int("unknownMediaCount", len(mf.UnknownMediaIds)).
+			Int("unknownMediaCount", len(sm.unknownMediaIds)).
 			Msg("Scan completed")
 	}
 
 	return localFiles, nil
 }
+
+// getFilePaths returns the paths of the media files to scan and the paths of the files excluded by .seaignore files.
+// If TargetPaths is set, only the files under those paths are returned.
+// The context is checked before each directory is read.
+func (scn *Scanner) getFilePaths(ctx context.Context, allLibraries []string) ([]string, []string, error) {
+
+	if len(scn.TargetPaths) > 0 {
+		paths := make([]string, 0)
+		ignoredPaths := make([]string, 0)
+		for _, targetPath := range scn.TargetPaths {
+			if isCancelled(ctx) {
+				return nil, nil, ErrScanCancelled
+			}
+			if !util.IsSubdirectoryOfAny(allLibraries, targetPath) {
+				scn.Logger.Warn().Str("path", targetPath).Msg("scanner: Targeted path is not in a library directory, skipping")
+				continue
+			}
+			info, err := os.Stat(targetPath)
+			if err != nil {
+				// The targeted path was removed, its local files will be dropped
+				continue
+			}
+			// The targeted path can be a single file
+			if !info.IsDir() {
+				if util.IsValidMediaFile(filepath.Base(targetPath)) && util.IsValidVideoExtension(filepath.Ext(targetPath)) {
+					if filesystem.IsIgnoredPath(allLibraries, targetPath, false) {
+						ignoredPaths = append(ignoredPaths, targetPath)
+					} else {
+						paths = append(paths, targetPath)
+					}
+				}
+				continue
+			}
+			targetFilePaths, err := filesystem.WalkMediaFilePaths(ctx, targetPath)
+			if err != nil {
+				if isCancelled(ctx) {
+					return nil, nil, ErrScanCancelled
+				}
+				return nil, nil, err
+			}
+			ignoredPaths = append(ignoredPaths, targetFilePaths.IgnoredPaths...)
+			// The walk only knows about the .seaignore files under the targeted directory, check the parent ones
+			for _, path := range targetFilePaths.Paths {
+				if filesystem.IsIgnoredPath(allLibraries, path, false) {
+					ignoredPaths = append(ignoredPaths, path)
+				} else {
+					paths = append(paths, path)
+				}
+			}
+		}
+
+		if scn.ScanLogger != nil {
+			scn.ScanLogger.logger.Info().
+				Any("count", len(paths)).
+				Any("ignoredCount", len(ignoredPaths)).
+				Any("targetPaths", scn.TargetPaths).
+				Msg("Retrieved file paths from targeted directories")
+		}
+
+		return paths, ignoredPaths, nil
+	}
+
+	paths := make([]string, 0)
+	ignoredPaths := make([]string, 0)
+	localFilePathsMap := make(map[string]struct{})
+
+	// Directories are walked by descending priority
+	for _, dirPath := range allLibraries {
+		if isCancelled(ctx) {
+			return nil, nil, ErrScanCancelled
+		}
+		dirPaths, err := filesystem.WalkMediaFilePaths(ctx, dirPath)
+		if err != nil {
+			if isCancelled(ctx) {
+				return nil, nil, ErrScanCancelled
+			}
+			return nil, nil, err
+		}
+		if scn.ScanLogger != nil {
+			scn.ScanLogger.logger.Info().
+				Any("count", len(dirPaths.Paths)).
+				Any("ignoredCount", len(dirPaths.IgnoredPaths)).
+				Msgf("Retrieved file paths from directory: %s", dirPath)
+		}
+		for _, path := range dirPaths.Paths {
+			if _, ok := localFilePathsMap[util.NormalizePath(path)]; !ok {
+				localFilePathsMap[util.NormalizePath(path)] = struct{}{}
+				paths = append(paths, path)
+			}
+		}
+		for _, path := range dirPaths.IgnoredPaths {
+			if _, ok := localFilePathsMap[util.NormalizePath(path)]; !ok {
+				localFilePathsMap[util.NormalizePath(path)] = struct{}{}
+				ignoredPaths = append(ignoredPaths, path)
+			}
+		}
+	}
+
+	if scn.ScanLogger != nil {
+		scn.ScanLogger.logger.Info().
+			Any("count", len(paths)).
+			Any("ignoredCount", len(ignoredPaths)).
+			Msg("Retrieved file paths from all directories")
+	}
+
+	return paths, ignoredPaths, nil
+}
+
+// isTargeted returns true if the file is under one of the targeted paths.
+func (scn *Scanner) isTargeted(path string) bool {
+	for _, targetPath := range scn.TargetPaths {
+		if util.IsSameDir(targetPath, path) || util.IsSubdirectory(targetPath, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// addAssignedMedia returns the media with the media assigned by matching rules or hash lookups that are missing from it.
+// The missing media are fetched.
+func (scn *Scanner) addAssignedMedia(allMedia []*anime.NormalizedMedia, assignedMediaIds []int, lfs []*anime.LocalFile, rateLimiter *limiter.Limiter) []*anime.NormalizedMedia {
+	if len(assignedMediaIds) == 0 {
+		return allMedia
+	}
+
+	known := make(map[int]struct{}, len(allMedia))
+	for _, m := range allMedia {
+		known[m.ID] = struct{}{}
+	}
+	used := make(map[int]struct{})
+	for _, lf := range lfs {
+		used[lf.MediaId] = struct{}{}
+	}
+
+	ret := slices.Clone(allMedia)
+	for _, id := range lo.Uniq(assignedMediaIds) {
+		if _, ok := used[id]; !ok {
+			continue
+		}
+		if _, ok := known[id]; ok {
+			continue
+		}
+		rateLimiter.Wait()
+		media, err := scn.Platform.GetAnime(id)
+		if err != nil {
+			scn.Logger.Warn().Err(err).Int("mediaId", id).Msg("scanner: Could not fetch assigned media")
+			continue
+		}
+		ret = append(ret, anime.NewNormalizedMedia(media))
+	}
+
+	return ret
+}
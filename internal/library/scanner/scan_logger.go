@@ -5,23 +5,68 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"seanime/internal/library/anime"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
 )
 
 // ScanLogger is a custom logger struct for scanning operations.
+//
+// Scan logs are written as JSON lines with the following keys:
+//   - time, level, message
+//   - scanId: the name of the log file without its extension
+//   - stage: the part of the scanner that wrote the entry, e.g. "Matcher"
+//   - path, filename: the local file the entry is about, if any
+//   - mediaId: the media the entry is about, if any
+//   - decision: what was decided for the local file, if any (ScanLogDecisionMatched, etc.)
+//
+// Other keys depend on the entry.
 type ScanLogger struct {
 	logger  *zerolog.Logger
 	logFile *os.File
 	buffer  *bytes.Buffer
 }
 
+const scanLogSuffix = "-scan.log"
+
+// Decisions tagged on the scan log entries of local files
+const (
+	ScanLogDecisionMatched   = "matched"
+	ScanLogDecisionUnmatched = "unmatched"
+	ScanLogDecisionSkipped   = "skipped"
+	// Types given to the matched local files by the hydrator
+	ScanLogDecisionMain    = "main"
+	ScanLogDecisionSpecial = "special"
+	ScanLogDecisionNC      = "nc"
+)
+
+// getScanLogTypeDecision returns the decision tagged on the entry that classifies a local file.
+func getScanLogTypeDecision(fileType anime.LocalFileType) string {
+	switch fileType {
+	case anime.LocalFileTypeSpecial:
+		return ScanLogDecisionSpecial
+	case anime.LocalFileTypeNC:
+		return ScanLogDecisionNC
+	}
+	return ScanLogDecisionMain
+}
+
+// scanLogFile tags a scan log entry with a local file.
+type scanLogFile struct {
+	lf *anime.LocalFile
+}
+
+func (f scanLogFile) MarshalZerologObject(e *zerolog.Event) {
+	e.Str("path", f.lf.Path).Str("filename", f.lf.Name)
+}
+
 // NewScanLogger creates a new ScanLogger with a log file named based on the current datetime.
 // - dir: The directory to save the log file in. This should come from the config.
 func NewScanLogger(outputDir string) (*ScanLogger, error) {
 	// Generate a log file name with the current datetime
-	logFileName := fmt.Sprintf("%s-scan.log", time.Now().Format("2006-01-02_15-04-05"))
+	logFileName := fmt.Sprintf("%s%s", time.Now().Format("2006-01-02_15-04-05"), scanLogSuffix)
 
 	// Create the logs directory if it doesn't exist
 	if _, err := os.Stat(outputDir); os.IsNotExist(err) {
@@ -41,7 +86,10 @@ func NewScanLogger(outputDir string) (*ScanLogger, error) {
 	buffer := new(bytes.Buffer)
 
 	// Create an array writer to wrap the JSON encoder
-	logger := zerolog.New(buffer).With().Logger()
+	logger := zerolog.New(buffer).With().
+		Timestamp().
+		Str("scanId", strings.TrimSuffix(logFileName, ".log")).
+		Logger()
 
 	return &ScanLogger{&logger, logFile, buffer}, nil
 }
@@ -60,19 +108,27 @@ func NewConsoleScanLogger() (*ScanLogger, error) {
 }
 
 func (sl *ScanLogger) LogMediaContainer(level zerolog.Level) *zerolog.Event {
-	return sl.logger.WithLevel(level).Str("context", "MediaContainer")
+	return sl.logger.WithLevel(level).Str("stage", "MediaContainer")
 }
 
 func (sl *ScanLogger) LogMatcher(level zerolog.Level) *zerolog.Event {
-	return sl.logger.WithLevel(level).Str("context", "Matcher")
+	return sl.logger.WithLevel(level).Str("stage", "Matcher")
 }
 
 func (sl *ScanLogger) LogFileHydrator(level zerolog.Level) *zerolog.Event {
-	return sl.logger.WithLevel(level).Str("context", "FileHydrator")
+	return sl.logger.WithLevel(level).Str("stage", "FileHydrator")
 }
 
 func (sl *ScanLogger) LogMediaFetcher(level zerolog.Level) *zerolog.Event {
-	return sl.logger.WithLevel(level).Str("context", "MediaFetcher")
+	return sl.logger.WithLevel(level).Str("stage", "MediaFetcher")
+}
+
+func (sl *ScanLogger) LogFileHasher(level zerolog.Level) *zerolog.Event {
+	return sl.logger.WithLevel(level).Str("stage", "FileHasher")
+}
+
+func (sl *ScanLogger) LogFileProber(level zerolog.Level) *zerolog.Event {
+	return sl.logger.WithLevel(level).Str("stage", "FileProber")
 }
 
 // Done flushes the buffer to the log file and closes the file.
@@ -0,0 +1,209 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"seanime/internal/util"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalidScanLog = errors.New("invalid scan log name")
+
+// ScanLogInfo describes a scan log file.
+type ScanLogInfo struct {
+	// Name of the log file, used to read the log
+	Name string    `json:"name"`
+	Date time.Time `json:"date"`
+	Size int64     `json:"size"`
+}
+
+// ScanLogEntry is an entry of a scan log.
+type ScanLogEntry struct {
+	Time     string `json:"time,omitempty"`
+	Level    string `json:"level"`
+	Stage    string `json:"stage,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	MediaId  int    `json:"mediaId,omitempty"`
+	Decision string `json:"decision,omitempty"`
+	Message  string `json:"message"`
+	// Other keys of the entry
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+// ScanLogFilter selects the entries of a scan log.
+// Zero values are ignored.
+type ScanLogFilter struct {
+	Path     string `json:"path,omitempty"`
+	MediaId  int    `json:"mediaId,omitempty"`
+	Stage    string `json:"stage,omitempty"`
+	Decision string `json:"decision,omitempty"`
+}
+
+// ListScanLogs returns the scan logs of the directory, from newest to oldest.
+func ListScanLogs(dir string) ([]*ScanLogInfo, error) {
+	ret := make([]*ScanLogInfo, 0)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ret, nil
+		}
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), scanLogSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		date, err := time.ParseInLocation("2006-01-02_15-04-05", strings.TrimSuffix(entry.Name(), scanLogSuffix), time.Local)
+		if err != nil {
+			date = info.ModTime()
+		}
+		ret = append(ret, &ScanLogInfo{
+			Name: entry.Name(),
+			Date: date,
+			Size: info.Size(),
+		})
+	}
+
+	slices.SortStableFunc(ret, func(a, b *ScanLogInfo) int {
+		return b.Date.Compare(a.Date)
+	})
+
+	return ret, nil
+}
+
+// ReadScanLog returns the entries of a scan log matching the filter.
+// Logs written before entries were tagged are supported, their entries are matched by file name.
+func ReadScanLog(dir string, name string, filter *ScanLogFilter) ([]*ScanLogEntry, error) {
+	if filepath.Base(name) != name || !strings.HasSuffix(name, scanLogSuffix) {
+		return nil, ErrInvalidScanLog
+	}
+
+	file, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if filter == nil {
+		filter = &ScanLogFilter{}
+	}
+
+	ret := make([]*ScanLogEntry, 0)
+
+	lines := bufio.NewScanner(file)
+	// Entries can contain large comparison results
+	lines.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for lines.Scan() {
+		line := lines.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		entry, ok := parseScanLogEntry(line)
+		if !ok {
+			continue
+		}
+		if filter.match(entry) {
+			ret = append(ret, entry)
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// CleanUpScanLogs removes the oldest scan logs of the directory so that at most maxLogs are kept.
+// Nothing is removed if maxLogs is 0 or less.
+func CleanUpScanLogs(dir string, maxLogs int) (removed int, err error) {
+	if maxLogs <= 0 {
+		return 0, nil
+	}
+
+	logs, err := ListScanLogs(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(logs) <= maxLogs {
+		return 0, nil
+	}
+	for _, log := range logs[maxLogs:] {
+		if err := os.Remove(filepath.Join(dir, log.Name)); err != nil {
+			continue
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+func parseScanLogEntry(line []byte) (*ScanLogEntry, bool) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return nil, false
+	}
+
+	ret := &ScanLogEntry{}
+	popString := func(key string) string {
+		v, _ := fields[key].(string)
+		delete(fields, key)
+		return v
+	}
+	ret.Time = popString("time")
+	ret.Level = popString("level")
+	ret.Message = popString("message")
+	ret.Path = popString("path")
+	ret.Filename = popString("filename")
+	ret.Decision = popString("decision")
+	ret.Stage = popString("stage")
+	// Logs written before entries were tagged with a stage
+	if ret.Stage == "" {
+		ret.Stage = popString("context")
+	}
+	if v, ok := fields["mediaId"].(float64); ok {
+		ret.MediaId = int(v)
+		delete(fields, "mediaId")
+	}
+	delete(fields, "scanId")
+
+	if len(fields) > 0 {
+		ret.Fields = fields
+	}
+
+	return ret, true
+}
+
+func (f *ScanLogFilter) match(entry *ScanLogEntry) bool {
+	if f.Path != "" {
+		if entry.Path != "" {
+			if util.NormalizePath(entry.Path) != util.NormalizePath(f.Path) {
+				return false
+			}
+		} else if entry.Filename == "" || entry.Filename != filepath.Base(f.Path) {
+			return false
+		}
+	}
+	if f.MediaId > 0 && entry.MediaId != f.MediaId {
+		return false
+	}
+	if f.Stage != "" && !strings.EqualFold(entry.Stage, f.Stage) {
+		return false
+	}
+	if f.Decision != "" && entry.Decision != f.Decision {
+		return false
+	}
+	return true
+}
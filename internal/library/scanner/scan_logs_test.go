@@ -0,0 +1,92 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"seanime/internal/library/anime"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanLogs(t *testing.T) {
+	dir := t.TempDir()
+
+	scanLogger, err := NewScanLogger(dir)
+	require.NoError(t, err)
+
+	lf := anime.NewLocalFile("/Anime/[Group] Show - 01.mkv", "/Anime")
+	other := anime.NewLocalFile("/Anime/[Group] Other - 01.mkv", "/Anime")
+
+	scanLogger.LogMatcher(zerolog.DebugLevel).
+		EmbedObject(scanLogFile{lf}).
+		Int("mediaId", 1).
+		Float64("rating", 0.9).
+		Str("decision", ScanLogDecisionMatched).
+		Msg("Best match rating high enough, matching file")
+	scanLogger.LogMatcher(zerolog.DebugLevel).
+		EmbedObject(scanLogFile{other}).
+		Int("mediaId", 2).
+		Str("decision", ScanLogDecisionUnmatched).
+		Msg("Best match Sorensen-Dice rating too low, un-matching file")
+	scanLogger.LogFileHydrator(zerolog.DebugLevel).
+		EmbedObject(scanLogFile{lf}).
+		Int("mediaId", 1).
+		Msg("File has been marked as main")
+	require.NoError(t, scanLogger.Done())
+
+	// Logs written before entries were tagged
+	legacy := `{"level":"debug","context":"Matcher","filename":"[Group] Show - 01.mkv","id":1,"message":"Best match found"}` + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2020-01-01_00-00-00-scan.log"), []byte(legacy), 0644))
+	// Not a scan log
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "seanime-2020-01-01_00-00-00.log"), []byte("{}"), 0644))
+
+	logs, err := ListScanLogs(dir)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "2020-01-01_00-00-00-scan.log", logs[1].Name)
+
+	entries, err := ReadScanLog(dir, logs[0].Name, &ScanLogFilter{Path: lf.Path})
+	require.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "Matcher", entries[0].Stage)
+		assert.Equal(t, ScanLogDecisionMatched, entries[0].Decision)
+		assert.Equal(t, 1, entries[0].MediaId)
+		assert.Equal(t, 0.9, entries[0].Fields["rating"])
+		assert.NotEmpty(t, entries[0].Time)
+		assert.Equal(t, "FileHydrator", entries[1].Stage)
+	}
+
+	entries, err = ReadScanLog(dir, logs[0].Name, &ScanLogFilter{MediaId: 2, Decision: ScanLogDecisionUnmatched})
+	require.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, other.Path, entries[0].Path)
+	}
+
+	entries, err = ReadScanLog(dir, logs[1].Name, &ScanLogFilter{Path: lf.Path, Stage: "matcher"})
+	require.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "Matcher", entries[0].Stage)
+	}
+
+	_, err = ReadScanLog(dir, "../"+logs[0].Name, nil)
+	assert.ErrorIs(t, err, ErrInvalidScanLog)
+
+	// Retention
+	removed, err := CleanUpScanLogs(dir, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed)
+
+	removed, err = CleanUpScanLogs(dir, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	logs, err = ListScanLogs(dir)
+	require.NoError(t, err)
+	if assert.Len(t, logs, 1) {
+		assert.NotEqual(t, "2020-01-01_00-00-00-scan.log", logs[0].Name)
+	}
+	assert.FileExists(t, filepath.Join(dir, "seanime-2020-01-01_00-00-00.log"))
+}
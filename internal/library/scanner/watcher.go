@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"seanime/internal/events"
+	"seanime/internal/library/filesystem"
 	"strings"
 )
 
@@ -15,8 +16,28 @@ type Watcher struct {
 	Logger         *zerolog.Logger
 	WSEventManager events.WSEventManagerInterface
 	TotalSize      string
+	LibraryPaths   []string
 }
 
+type (
+	FileActionType string
+
+	// FileAction is a change detected by the Watcher.
+	FileAction struct {
+		Path string
+		Type FileActionType
+	}
+)
+
+const (
+	FileActionCreated FileActionType = "created"
+	FileActionRemoved FileActionType = "removed"
+	// FileActionRenamed is sent for the old path of a renamed file
+	FileActionRenamed FileActionType = "renamed"
+	// FileActionIgnoreFileChanged is sent for the directory of a .seaignore file that changed
+	FileActionIgnoreFileChanged FileActionType = "ignore-file-changed"
+)
+
 type NewWatcherOptions struct {
 	Logger         *zerolog.Logger
 	WSEventManager events.WSEventManagerInterface
@@ -58,6 +79,8 @@ func (w *Watcher) InitLibraryFileWatcher(opts *WatchLibraryFilesOptions) error {
 		return err
 	}
 
+	w.LibraryPaths = opts.LibraryPaths
+
 	// Add the initial directory and its subdirectories to the watcher
 	for _, path := range opts.LibraryPaths {
 		if err := watchDir(path); err != nil {
@@ -70,8 +93,10 @@ func (w *Watcher) InitLibraryFileWatcher(opts *WatchLibraryFilesOptions) error {
 	return nil
 }
 
+// StartWatching handles the file system events in a goroutine.
+// onFileAction is called with the path affected by each relevant event.
 func (w *Watcher) StartWatching(
-	onFileAction func(),
+	onFileAction func(action *FileAction),
 ) {
 	// Start a goroutine to handle file system events
 	go func() {
@@ -86,15 +111,32 @@ func (w *Watcher) StartWatching(
 				if strings.Contains(event.Name, ".part") || strings.Contains(event.Name, ".tmp") {
 					continue
 				}
+				// Editing a .seaignore file changes which files are scanned in its directory
+				if filepath.Base(event.Name) == filesystem.IgnoreFileName {
+					if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Remove|fsnotify.Rename) != 0 {
+						w.Logger.Debug().Msgf("watcher: Ignore file changed: %s", event.Name)
+						onFileAction(&FileAction{Path: filepath.Dir(event.Name), Type: FileActionIgnoreFileChanged})
+					}
+					continue
+				}
+				if w.isIgnored(event.Name) {
+					continue
+				}
 				if event.Op&fsnotify.Create == fsnotify.Create {
 					w.Logger.Debug().Msgf("watcher: File created: %s", event.Name)
 					w.WSEventManager.SendEvent(events.LibraryWatcherFileAdded, event.Name)
-					onFileAction()
+					onFileAction(&FileAction{Path: event.Name, Type: FileActionCreated})
 				}
 				if event.Op&fsnotify.Remove == fsnotify.Remove {
 					w.Logger.Debug().Msgf("watcher: File removed: %s", event.Name)
 					w.WSEventManager.SendEvent(events.LibraryWatcherFileRemoved, event.Name)
-					onFileAction()
+					onFileAction(&FileAction{Path: event.Name, Type: FileActionRemoved})
+				}
+				// The new name of a renamed file is reported as a separate Create event
+				if event.Op&fsnotify.Rename == fsnotify.Rename {
+					w.Logger.Debug().Msgf("watcher: File renamed: %s", event.Name)
+					w.WSEventManager.SendEvent(events.LibraryWatcherFileRemoved, event.Name)
+					onFileAction(&FileAction{Path: event.Name, Type: FileActionRenamed})
 				}
 
 			case err, ok := <-w.Watcher.Errors:
@@ -107,6 +149,15 @@ func (w *Watcher) StartWatching(
 	}()
 }
 
+// isIgnored returns true if the path is excluded by a .seaignore file.
+func (w *Watcher) isIgnored(path string) bool {
+	isDir := false
+	if info, err := os.Stat(path); err == nil {
+		isDir = info.IsDir()
+	}
+	return filesystem.IsIgnoredPath(w.LibraryPaths, path, isDir)
+}
+
 func (w *Watcher) StopWatching() {
 	err := w.Watcher.Close()
 	if err == nil {
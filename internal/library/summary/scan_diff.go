@@ -0,0 +1,233 @@
+package summary
+
+import (
+	"reflect"
+	"seanime/internal/library/anime"
+	"seanime/internal/util"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ScanDiffChangeMatched        ScanDiffChangeType = "matched"         // New or previously unmatched file, now matched
+	ScanDiffChangeRematched      ScanDiffChangeType = "rematched"       // Matched to a different media
+	ScanDiffChangeEpisodeChanged ScanDiffChangeType = "episode-changed" // Same media, different episode or type
+	ScanDiffChangeUnmatched      ScanDiffChangeType = "unmatched"       // New or previously matched file, now unmatched
+	ScanDiffChangeRemoved        ScanDiffChangeType = "removed"         // File no longer found
+	ScanDiffChangeUpdated        ScanDiffChangeType = "updated"         // Same media and episode, different ignored or offline state, hashes or media info
+
+	ScanDiffStatusPending   ScanDiffStatus = "pending"   // Dry run waiting for approval
+	ScanDiffStatusApplied   ScanDiffStatus = "applied"   // Accepted changes were saved
+	ScanDiffStatusDiscarded ScanDiffStatus = "discarded" // No change was saved
+)
+
+type (
+	ScanDiffChangeType string
+	ScanDiffStatus     string
+
+	// ScanDiff holds the changes a scan makes to the local files.
+	ScanDiff struct {
+		Status  ScanDiffStatus    `json:"status"`
+		DryRun  bool              `json:"dryRun"`
+		Changes []*ScanDiffChange `json:"changes"`
+	}
+
+	ScanDiffChange struct {
+		ID       string             `json:"id"`
+		Type     ScanDiffChangeType `json:"type"`
+		Path     string             `json:"path"`
+		Previous *anime.LocalFile   `json:"previous,omitempty"` // Not set for new files
+		Current  *anime.LocalFile   `json:"current,omitempty"`  // Not set for removed files
+		Accepted bool               `json:"accepted"`
+	}
+)
+
+// NewScanDiff compares the local files returned by a scan with the existing ones.
+// Files that did not change are not part of the diff.
+func NewScanDiff(existing []*anime.LocalFile, scanned []*anime.LocalFile) *ScanDiff {
+	ret := &ScanDiff{
+		Status:  ScanDiffStatusPending,
+		Changes: make([]*ScanDiffChange, 0),
+	}
+
+	previous := make(map[string]*anime.LocalFile, len(existing))
+	for _, lf := range existing {
+		previous[lf.GetNormalizedPath()] = lf
+	}
+
+	seen := make(map[string]struct{}, len(scanned))
+	for _, lf := range scanned {
+		path := lf.GetNormalizedPath()
+		seen[path] = struct{}{}
+
+		prev, ok := previous[path]
+		changeType, changed := getScanDiffChangeType(prev, ok, lf)
+		if !changed {
+			continue
+		}
+		ret.Changes = append(ret.Changes, &ScanDiffChange{
+			ID:       uuid.NewString(),
+			Type:     changeType,
+			Path:     lf.Path,
+			Previous: prev,
+			Current:  lf,
+		})
+	}
+
+	for path, lf := range previous {
+		if _, ok := seen[path]; ok {
+			continue
+		}
+		ret.Changes = append(ret.Changes, &ScanDiffChange{
+			ID:       uuid.NewString(),
+			Type:     ScanDiffChangeRemoved,
+			Path:     lf.Path,
+			Previous: lf,
+		})
+	}
+
+	sort.SliceStable(ret.Changes, func(i, j int) bool {
+		return ret.Changes[i].Path < ret.Changes[j].Path
+	})
+
+	return ret
+}
+
+func getScanDiffChangeType(prev *anime.LocalFile, found bool, current *anime.LocalFile) (ScanDiffChangeType, bool) {
+	if !found || prev.MediaId == 0 {
+		if current.MediaId == 0 {
+			if found && !isSameFileData(prev, current) {
+				return ScanDiffChangeUpdated, true
+			}
+			// Files that stay unmatched are not a change
+			return ScanDiffChangeUnmatched, !found
+		}
+		return ScanDiffChangeMatched, true
+	}
+	if current.MediaId == 0 {
+		return ScanDiffChangeUnmatched, true
+	}
+	if current.MediaId != prev.MediaId {
+		return ScanDiffChangeRematched, true
+	}
+	if !isSameEpisode(prev.Metadata, current.Metadata) {
+		return ScanDiffChangeEpisodeChanged, true
+	}
+	if !isSameFileData(prev, current) {
+		return ScanDiffChangeUpdated, true
+	}
+	return "", false
+}
+
+// isSameFileData returns true if the data of the files that does not concern their match is the same.
+func isSameFileData(a *anime.LocalFile, b *anime.LocalFile) bool {
+	return a.Ignored == b.Ignored &&
+		a.IgnoredBySeaignore == b.IgnoredBySeaignore &&
+		a.Offline == b.Offline &&
+		reflect.DeepEqual(a.Hashes, b.Hashes) &&
+		reflect.DeepEqual(a.MediaInfo, b.MediaInfo)
+}
+
+func isSameEpisode(a *anime.LocalFileMetadata, b *anime.LocalFileMetadata) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Episode == b.Episode && a.AniDBEpisode == b.AniDBEpisode && a.Type == b.Type
+}
+
+// GenerateSummaryWithDiff generates the summary of the scan and attaches the diff to it.
+// Unlike GenerateSummary, it returns a summary even if the logger was not hydrated, e.g. when all the files were skipped.
+func (l *ScanSummaryLogger) GenerateSummaryWithDiff(diff *ScanDiff) *ScanSummary {
+	ret := l.GenerateSummary()
+	if ret == nil {
+		ret = &ScanSummary{
+			ID:             uuid.NewString(),
+			Groups:         make([]*ScanSummaryGroup, 0),
+			UnmatchedFiles: make([]*ScanSummaryFile, 0),
+			IgnoredFiles:   make([]*ScanSummaryFile, 0),
+		}
+	}
+	ret.Diff = diff
+	return ret
+}
+
+// AcceptAll marks all the changes as accepted.
+func (d *ScanDiff) AcceptAll() {
+	for _, change := range d.Changes {
+		change.Accepted = true
+	}
+}
+
+// Accept marks the changes with the given IDs as accepted.
+func (d *ScanDiff) Accept(changeIds []string) {
+	ids := make(map[string]struct{}, len(changeIds))
+	for _, id := range changeIds {
+		ids[id] = struct{}{}
+	}
+	for _, change := range d.Changes {
+		if _, ok := ids[change.ID]; ok {
+			change.Accepted = true
+		}
+	}
+}
+
+// Apply returns the local files with the accepted changes applied.
+// Changes are applied to the given local files rather than the ones the diff was computed from,
+// so that edits made after a dry run are kept, see mergeScanDiffChange.
+func (d *ScanDiff) Apply(lfs []*anime.LocalFile) []*anime.LocalFile {
+	accepted := make(map[string]*ScanDiffChange)
+	for _, change := range d.Changes {
+		if change.Accepted {
+			accepted[util.NormalizePath(change.Path)] = change
+		}
+	}
+
+	ret := make([]*anime.LocalFile, 0, len(lfs))
+	for _, lf := range lfs {
+		path := lf.GetNormalizedPath()
+		change, ok := accepted[path]
+		if !ok {
+			ret = append(ret, lf)
+			continue
+		}
+		delete(accepted, path)
+		if change.Current != nil {
+			ret = append(ret, mergeScanDiffChange(lf, change))
+		}
+	}
+
+	// New files
+	for _, change := range d.Changes {
+		if _, ok := accepted[util.NormalizePath(change.Path)]; ok && change.Current != nil {
+			ret = append(ret, change.Current)
+		}
+	}
+
+	return ret
+}
+
+// mergeScanDiffChange returns the scanned file of the change, with the edits made to the local file since the scan.
+// The lock, ignored state and match edited by the user are kept.
+func mergeScanDiffChange(lf *anime.LocalFile, change *ScanDiffChange) *anime.LocalFile {
+	ret := *change.Current
+
+	prev := change.Previous
+	if prev == nil {
+		// The file was added after the scan
+		prev = &anime.LocalFile{}
+	}
+
+	if lf.Locked != prev.Locked {
+		ret.Locked = lf.Locked
+	}
+	if lf.Ignored != prev.Ignored {
+		ret.Ignored = lf.Ignored
+	}
+	if lf.MediaId != prev.MediaId || !isSameEpisode(lf.Metadata, prev.Metadata) {
+		ret.MediaId = lf.MediaId
+		ret.Metadata = lf.Metadata
+	}
+
+	return &ret
+}
@@ -0,0 +1,135 @@
+package summary
+
+import (
+	"seanime/internal/library/anime"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDiffLocalFile(path string, mediaId int, episode int) *anime.LocalFile {
+	return &anime.LocalFile{
+		Path:    path,
+		MediaId: mediaId,
+		Metadata: &anime.LocalFileMetadata{
+			Episode:      episode,
+			AniDBEpisode: "",
+			Type:         anime.LocalFileTypeMain,
+		},
+	}
+}
+
+func TestScanDiff(t *testing.T) {
+	existing := []*anime.LocalFile{
+		newDiffLocalFile("/lib/unchanged.mkv", 1, 1),
+		newDiffLocalFile("/lib/rematched.mkv", 1, 2),
+		newDiffLocalFile("/lib/episode.mkv", 1, 3),
+		newDiffLocalFile("/lib/unmatched.mkv", 1, 4),
+		newDiffLocalFile("/lib/previously-unmatched.mkv", 0, 0),
+		newDiffLocalFile("/lib/still-unmatched.mkv", 0, 0),
+		newDiffLocalFile("/lib/removed.mkv", 1, 5),
+	}
+	scanned := []*anime.LocalFile{
+		newDiffLocalFile("/lib/unchanged.mkv", 1, 1),
+		newDiffLocalFile("/lib/rematched.mkv", 2, 2),
+		newDiffLocalFile("/lib/episode.mkv", 1, 6),
+		newDiffLocalFile("/lib/unmatched.mkv", 0, 0),
+		newDiffLocalFile("/lib/previously-unmatched.mkv", 1, 7),
+		newDiffLocalFile("/lib/still-unmatched.mkv", 0, 0),
+		newDiffLocalFile("/lib/new.mkv", 3, 1),
+	}
+
+	diff := NewScanDiff(existing, scanned)
+	assert.Equal(t, ScanDiffStatusPending, diff.Status)
+
+	types := lo.SliceToMap(diff.Changes, func(change *ScanDiffChange) (string, ScanDiffChangeType) {
+		return change.Path, change.Type
+	})
+	assert.Equal(t, map[string]ScanDiffChangeType{
+		"/lib/rematched.mkv":            ScanDiffChangeRematched,
+		"/lib/episode.mkv":              ScanDiffChangeEpisodeChanged,
+		"/lib/unmatched.mkv":            ScanDiffChangeUnmatched,
+		"/lib/previously-unmatched.mkv": ScanDiffChangeMatched,
+		"/lib/new.mkv":                  ScanDiffChangeMatched,
+		"/lib/removed.mkv":              ScanDiffChangeRemoved,
+	}, types)
+
+	changeIds := lo.SliceToMap(diff.Changes, func(change *ScanDiffChange) (string, string) {
+		return change.Path, change.ID
+	})
+
+	// Only the accepted changes are applied
+	diff.Accept([]string{changeIds["/lib/rematched.mkv"], changeIds["/lib/new.mkv"], changeIds["/lib/removed.mkv"]})
+	lfs := diff.Apply(existing)
+
+	byPath := lo.SliceToMap(lfs, func(lf *anime.LocalFile) (string, *anime.LocalFile) {
+		return lf.Path, lf
+	})
+	require.Len(t, lfs, 7)
+	assert.Equal(t, 2, byPath["/lib/rematched.mkv"].MediaId)
+	assert.Equal(t, 3, byPath["/lib/new.mkv"].MediaId)
+	assert.NotContains(t, byPath, "/lib/removed.mkv")
+	assert.Equal(t, 3, byPath["/lib/episode.mkv"].Metadata.Episode, "rejected changes should not be applied")
+	assert.Equal(t, 1, byPath["/lib/unmatched.mkv"].MediaId, "rejected changes should not be applied")
+}
+
+func TestScanDiff_ApplyKeepsFileDataAndEdits(t *testing.T) {
+	existing := []*anime.LocalFile{
+		newDiffLocalFile("/lib/hashed.mkv", 1, 1),
+		newDiffLocalFile("/lib/rematched.mkv", 1, 2),
+		newDiffLocalFile("/lib/edited.mkv", 1, 3),
+		newDiffLocalFile("/lib/ignored.mkv", 0, 0),
+	}
+
+	hashed := newDiffLocalFile("/lib/hashed.mkv", 1, 1)
+	hashed.Hashes = &anime.LocalFileHashes{CRC32: "ABCD1234"}
+	hashed.MediaInfo = &anime.LocalFileMediaInfo{Duration: 1420}
+	rematched := newDiffLocalFile("/lib/rematched.mkv", 2, 2)
+	rematched.Hashes = &anime.LocalFileHashes{CRC32: "1234ABCD"}
+	edited := newDiffLocalFile("/lib/edited.mkv", 2, 3)
+	ignored := newDiffLocalFile("/lib/ignored.mkv", 0, 0)
+	ignored.IgnoredBySeaignore = true
+	scanned := []*anime.LocalFile{hashed, rematched, edited, ignored}
+
+	diff := NewScanDiff(existing, scanned)
+	types := lo.SliceToMap(diff.Changes, func(change *ScanDiffChange) (string, ScanDiffChangeType) {
+		return change.Path, change.Type
+	})
+	assert.Equal(t, map[string]ScanDiffChangeType{
+		"/lib/hashed.mkv":    ScanDiffChangeUpdated,
+		"/lib/rematched.mkv": ScanDiffChangeRematched,
+		"/lib/edited.mkv":    ScanDiffChangeRematched,
+		"/lib/ignored.mkv":   ScanDiffChangeUpdated,
+	}, types)
+
+	// The user edits the files after the dry run
+	current := []*anime.LocalFile{
+		newDiffLocalFile("/lib/hashed.mkv", 1, 1),
+		newDiffLocalFile("/lib/rematched.mkv", 1, 2),
+		newDiffLocalFile("/lib/edited.mkv", 3, 3),
+		newDiffLocalFile("/lib/ignored.mkv", 0, 0),
+	}
+	current[1].Locked = true
+	current[2].Locked = true
+
+	diff.AcceptAll()
+	lfs := diff.Apply(current)
+	byPath := lo.SliceToMap(lfs, func(lf *anime.LocalFile) (string, *anime.LocalFile) {
+		return lf.Path, lf
+	})
+	require.Len(t, lfs, 4)
+
+	require.NotNil(t, byPath["/lib/hashed.mkv"].Hashes)
+	assert.Equal(t, "ABCD1234", byPath["/lib/hashed.mkv"].Hashes.CRC32)
+	require.NotNil(t, byPath["/lib/hashed.mkv"].MediaInfo)
+	assert.True(t, byPath["/lib/ignored.mkv"].IgnoredBySeaignore)
+
+	assert.Equal(t, 2, byPath["/lib/rematched.mkv"].MediaId)
+	assert.Equal(t, "1234ABCD", byPath["/lib/rematched.mkv"].Hashes.CRC32)
+	assert.True(t, byPath["/lib/rematched.mkv"].Locked, "the lock set after the dry run should be kept")
+
+	assert.Equal(t, 3, byPath["/lib/edited.mkv"].MediaId, "the match edited after the dry run should be kept")
+	assert.True(t, byPath["/lib/edited.mkv"].Locked)
+}
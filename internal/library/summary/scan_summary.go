@@ -25,6 +25,10 @@ const (
 	LogMetadataHydrated
 	LogPanic
 	LogDebug
+	LogIgnored
+	LogMatchingRule
+	LogChecksumMismatch
+	LogHashMatch
 )
 
 type (
@@ -48,6 +52,8 @@ type (
 		ID             string              `json:"id"`
 		Groups         []*ScanSummaryGroup `json:"groups"`
 		UnmatchedFiles []*ScanSummaryFile  `json:"unmatchedFiles"`
+		IgnoredFiles   []*ScanSummaryFile  `json:"ignoredFiles"` // Files excluded by .seaignore files
+		Diff           *ScanDiff           `json:"diff,omitempty"`
 	}
 
 	ScanSummaryFile struct {
@@ -87,6 +93,7 @@ func (l *ScanSummaryLogger) GenerateSummary() *ScanSummary {
 		ID:             uuid.NewString(),
 		Groups:         make([]*ScanSummaryGroup, 0),
 		UnmatchedFiles: make([]*ScanSummaryFile, 0),
+		IgnoredFiles:   make([]*ScanSummaryFile, 0),
 	}
 
 	groupsMap := make(map[int][]*ScanSummaryFile)
@@ -94,6 +101,15 @@ func (l *ScanSummaryLogger) GenerateSummary() *ScanSummary {
 	// Generate summary files
 	for _, lf := range l.LocalFiles {
 
+		if lf.IsIgnored() {
+			summary.IgnoredFiles = append(summary.IgnoredFiles, &ScanSummaryFile{
+				ID:        uuid.NewString(),
+				LocalFile: lf,
+				Logs:      l.getFileLogs(lf),
+			})
+			continue
+		}
+
 		if lf.MediaId == 0 {
 			summary.UnmatchedFiles = append(summary.UnmatchedFiles, &ScanSummaryFile{
 				ID:        uuid.NewString(),
@@ -201,6 +217,41 @@ func (l *ScanSummaryLogger) LogUnmatched(lf *anime.LocalFile, reason string) {
 	l.logType(LogUnmatched, lf, msg)
 }
 
+func (l *ScanSummaryLogger) LogIgnored(lf *anime.LocalFile, reason string) {
+	if l == nil {
+		return
+	}
+	msg := fmt.Sprintf("Ignored: %s", reason)
+	l.logType(LogIgnored, lf, msg)
+}
+
+func (l *ScanSummaryLogger) LogMatchingRule(lf *anime.LocalFile, ruleId uint, message string) {
+	if l == nil {
+		return
+	}
+	msg := fmt.Sprintf("Matching rule #%d applied: %s", ruleId, message)
+	l.logType(LogMatchingRule, lf, msg)
+}
+
+func (l *ScanSummaryLogger) LogChecksumMismatch(lf *anime.LocalFile, expected string, actual string) {
+	if l == nil {
+		return
+	}
+	msg := fmt.Sprintf("Checksum mismatch: file name has %s, content has %s", expected, actual)
+	l.logType(LogChecksumMismatch, lf, msg)
+}
+
+func (l *ScanSummaryLogger) LogHashMatch(lf *anime.LocalFile, mediaId int, episode string) {
+	if l == nil {
+		return
+	}
+	msg := fmt.Sprintf("Identified by hash as media %d", mediaId)
+	if episode != "" {
+		msg += fmt.Sprintf(", episode %s", episode)
+	}
+	l.logType(LogHashMatch, lf, msg)
+}
+
 func (l *ScanSummaryLogger) LogFileNotMatched(lf *anime.LocalFile, reason string) {
 	if l == nil {
 		return
@@ -241,11 +292,11 @@ func (l *ScanSummaryLogger) LogMetadataEpisodeNormalizationFailed(lf *anime.Loca
 	l.logType(LogMetadataEpisodeNormalizationFailed, lf, msg)
 }
 
-func (l *ScanSummaryLogger) LogMetadataNC(lf *anime.LocalFile) {
+func (l *ScanSummaryLogger) LogMetadataNC(lf *anime.LocalFile, aniDBEpisode string) {
 	if l == nil {
 		return
 	}
-	msg := fmt.Sprintf("Marked as NC file")
+	msg := fmt.Sprintf("Marked as NC file. AniDB episode: %s", aniDBEpisode)
 	l.logType(LogMetadataNC, lf, msg)
 }
 
@@ -327,6 +378,14 @@ func (l *ScanSummaryLogger) logType(logType LogType, lf *anime.LocalFile, messag
 		l.log(lf, "error", message)
 	case LogDebug:
 		l.log(lf, "info", message)
+	case LogIgnored:
+		l.log(lf, "info", message)
+	case LogMatchingRule:
+		l.log(lf, "info", message)
+	case LogChecksumMismatch:
+		l.log(lf, "warning", message)
+	case LogHashMatch:
+		l.log(lf, "info", message)
 	}
 }
 
@@ -9,8 +9,10 @@ import (
 	"seanime/internal/database/db"
 	"seanime/internal/database/models"
 	"seanime/internal/events"
+	"seanime/internal/eventstream"
 	"seanime/internal/manga/downloader"
 	"seanime/internal/manga/providers"
+	"seanime/internal/notifier"
 	"seanime/internal/util"
 	"seanime/internal/util/filecache"
 	"sync"
@@ -122,6 +124,18 @@ func (d *Downloader) Start() {
 					}
 				}()
 
+				notifier.GlobalDispatcher.Dispatch(notifier.EventMangaChapterAvailable, map[string]interface{}{
+					"mediaId":  downloadId.MediaId,
+					"chapter":  downloadId.ChapterNumber,
+					"provider": downloadId.Provider,
+				})
+				eventstream.GlobalBroker.Publish(eventstream.TypeDownloadCompleted, &eventstream.DownloadCompletedPayload{
+					Source:  "manga",
+					Name:    fmt.Sprintf("Chapter %s", downloadId.ChapterNumber),
+					MediaId: downloadId.MediaId,
+					Episode: downloadId.ChapterNumber,
+				})
+
 				// Refresh the media map when a chapter is downloaded
 				d.hydrateMediaMap()
 			}
@@ -267,6 +281,16 @@ func (d *Downloader) StopChapterDownloadQueue() {
 	d.chapterDownloader.Stop()
 }
 
+// IsChapterDownloadQueueRunning returns true if the chapter download queue is running.
+func (d *Downloader) IsChapterDownloadQueueRunning() bool {
+	return d.chapterDownloader.IsRunning()
+}
+
+// GetCurrentChapterDownloadProgress returns the chapter being downloaded and the number of its pages that have been downloaded.
+func (d *Downloader) GetCurrentChapterDownloadProgress() (id chapter_downloader.DownloadID, downloadedPages int, totalPages int, ok bool) {
+	return d.chapterDownloader.GetCurrentProgress()
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type (
@@ -187,6 +187,24 @@ func (cd *Downloader) Stop() {
 	cd.queue.Stop()
 }
 
+// IsRunning returns true if the queue is active.
+func (cd *Downloader) IsRunning() bool {
+	return cd.queue.IsActive()
+}
+
+// GetCurrentProgress returns the chapter being downloaded and the number of its pages that have been downloaded.
+func (cd *Downloader) GetCurrentProgress() (id DownloadID, downloadedPages int, totalPages int, ok bool) {
+	current, ok := cd.queue.GetCurrent()
+	if !ok {
+		return DownloadID{}, 0, 0, false
+	}
+
+	cd.downloadMu.Lock()
+	defer cd.downloadMu.Unlock()
+
+	return current.DownloadID, len(current.DownloadedUrls), len(current.Pages), true
+}
+
 // run downloads the chapter based on the QueueInfo provided.
 // This is called successively for each current item being processed.
 // It invokes downloadChapterImages to download the chapter pages.
@@ -257,7 +275,7 @@ func (cd *Downloader) downloadChapterImages(queueInfo *QueueInfo) (err error) {
 				//cd.logger.Warn().Msg("chapter downloader: Download goroutine canceled")
 				return
 			default:
-				cd.downloadPage(page, destination, registry)
+				cd.downloadPage(page, destination, registry, queueInfo)
 			}
 		}(page, &registry)
 	}
@@ -281,7 +299,7 @@ func (cd *Downloader) downloadChapterImages(queueInfo *QueueInfo) (err error) {
 
 // downloadPage downloads a single page from the URL and saves it to the destination directory.
 // It also updates the Registry with the page information.
-func (cd *Downloader) downloadPage(page *hibikemanga.ChapterPage, destination string, registry *Registry) {
+func (cd *Downloader) downloadPage(page *hibikemanga.ChapterPage, destination string, registry *Registry, queueInfo *QueueInfo) {
 
 	defer util.HandlePanicInModuleThen("manga/downloader/downloadImage", func() {
 	})
@@ -331,6 +349,7 @@ func (cd *Downloader) downloadPage(page *hibikemanga.ChapterPage, destination st
 		OriginalURL: page.URL,
 		Size:        int64(len(buf)),
 	}
+	queueInfo.DownloadedUrls = append(queueInfo.DownloadedUrls, page.URL)
 	cd.downloadMu.Unlock()
 
 	return
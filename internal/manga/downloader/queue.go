@@ -146,6 +146,14 @@ func (q *Queue) Stop() {
 	q.active = false
 }
 
+// IsActive returns true if the queue is running.
+func (q *Queue) IsActive() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.active
+}
+
 // runNext runs the next item in the queue.
 //   - Checks if there is a current item, if so, it returns.
 //   - If nothing is running, it gets the next item (QueueInfo) from the database, sets it as current and sends it to the downloader.
@@ -9,6 +9,8 @@ import (
 	mpchc2 "seanime/internal/mediaplayers/mpchc"
 	"seanime/internal/mediaplayers/mpv"
 	vlc2 "seanime/internal/mediaplayers/vlc"
+	"seanime/internal/mediastream/skipsegments"
+	"seanime/internal/trackpreference"
 	"seanime/internal/util/result"
 	"sync"
 	"time"
@@ -30,6 +32,8 @@ type (
 		Mpv                   *mpv.Mpv
 		wsEventManager        events.WSEventManagerInterface
 		continuityManager     *continuity.Manager
+		trackPreferences      *trackpreference.Repository
+		skipSegments          *skipsegments.Manager
 		playerInUse           string
 		completionThreshold   float64
 		mu                    sync.Mutex
@@ -48,6 +52,8 @@ type (
 		Mpv               *mpv.Mpv
 		WSEventManager    events.WSEventManagerInterface
 		ContinuityManager *continuity.Manager
+		TrackPreferences  *trackpreference.Repository
+		SkipSegments      *skipsegments.Manager
 	}
 
 	RepositorySubscriber struct {
@@ -87,6 +93,8 @@ func NewRepository(opts *NewRepositoryOptions) *Repository {
 		Mpv:                   opts.Mpv,
 		wsEventManager:        opts.WSEventManager,
 		continuityManager:     opts.ContinuityManager,
+		trackPreferences:      opts.TrackPreferences,
+		skipSegments:          opts.SkipSegments,
 		completionThreshold:   0.8,
 		subscribers:           result.NewResultMap[string, *RepositorySubscriber](),
 		currentPlaybackStatus: &PlaybackStatus{},
@@ -146,6 +154,8 @@ func (m *Repository) Play(path string) error {
 
 	lastWatched := m.continuityManager.GetExternalPlayerEpisodeWatchHistoryItem(path, false, 0, 0)
 
+	selection, mediaInfo := m.getTrackSelection(path)
+
 	switch m.Default {
 	case "vlc":
 		err := m.VLC.Start()
@@ -175,6 +185,8 @@ func (m *Repository) Play(path string) error {
 			}
 		}
 
+		m.applyVlcTracks(selection)
+
 		return nil
 	case "mpc-hc":
 		err := m.MpcHc.Start()
@@ -199,10 +211,13 @@ func (m *Repository) Play(path string) error {
 			}
 		}
 
+		m.applyMpcHcTracks(selection, mediaInfo)
+
 		return nil
 	case "mpv":
+		trackArgs := m.applyMpvOptions(mpvTrackOptions(selection))
 		if m.continuityManager.GetSettings().WatchContinuityEnabled {
-			var args []string
+			args := trackArgs
 			if lastWatched.Found {
 				//args = append(args, "--no-resume-playback", fmt.Sprintf("--start=+%d", int(lastWatched.Item.CurrentTime)))
 				args = append(args, "--no-resume-playback")
@@ -216,7 +231,7 @@ func (m *Repository) Play(path string) error {
 				_ = m.Mpv.SeekTo(lastWatched.Item.CurrentTime)
 			}
 		} else {
-			err := m.Mpv.OpenAndPlay(path)
+			err := m.Mpv.OpenAndPlay(path, trackArgs...)
 			if err != nil {
 				m.Logger.Error().Err(err).Msg("media player: Could not open and play video using MPV")
 				return fmt.Errorf("could not open and play video, %w", err)
@@ -226,6 +241,8 @@ func (m *Repository) Play(path string) error {
 			}
 		}
 
+		m.pushMpvSkipSegments(path)
+
 		return nil
 	default:
 		return errors.New("no default media player set")
@@ -287,7 +304,8 @@ func (m *Repository) Stream(streamUrl string, episode int, mediaId int, windowTi
 		}
 
 	case "mpv":
-		args := []string{"--force-window"}
+		// The tracks of the stream are not known in advance, so they are selected by language
+		args := append([]string{"--force-window"}, m.applyMpvOptions(m.mpvLanguageOptions(mediaId))...)
 		if windowTitle != "" {
 			args = append(args, fmt.Sprintf("--title=%q", windowTitle))
 		}
@@ -0,0 +1,101 @@
+package mediaplayer
+
+import (
+	"errors"
+	"seanime/internal/mediastream/skipsegments"
+	"seanime/internal/util"
+	"time"
+)
+
+const (
+	// skipSegmentsDetectionWait is how long to wait for the skip segments of a file being detected, detection times out after 5 minutes
+	skipSegmentsDetectionWait = 6 * time.Minute
+	// skipSegmentsPollInterval is how often the skip segments are requested while they are being detected
+	skipSegmentsPollInterval = 5 * time.Second
+)
+
+// pushMpvSkipSegments adds the skip segments of the file to mpv as chapters once the file is loaded.
+// On the first playback of the file, the segments are added once their detection is done.
+// Files whose markers come from their own chapters are left untouched.
+// The chapters can then be skipped with mpv's chapter commands (PGUP/PGDWN by default).
+func (m *Repository) pushMpvSkipSegments(path string) {
+	if m.skipSegments == nil {
+		return
+	}
+
+	go func() {
+		defer util.HandlePanicInModuleThen("mediaplayer/pushMpvSkipSegments", func() {})
+
+		markers, err := m.skipSegments.RequestMarkers(path)
+		for deadline := time.Now().Add(skipSegmentsDetectionWait); errors.Is(err, skipsegments.ErrDetectionInProgress) && time.Now().Before(deadline); {
+			time.Sleep(skipSegmentsPollInterval)
+			markers, err = m.skipSegments.RequestMarkers(path)
+		}
+		if err != nil || markers == nil || markers.Source != skipsegments.SourceFingerprint || len(markers.Segments) == 0 {
+			return
+		}
+
+		// Wait for the file to be loaded, it might have been closed while the segments were being detected
+		duration := 0.0
+		for i := 0; i < 20; i++ {
+			time.Sleep(500 * time.Millisecond)
+			playback, err := m.Mpv.GetPlaybackStatus()
+			if err != nil {
+				continue
+			}
+			if util.NormalizePath(playback.Filepath) == util.NormalizePath(path) && playback.Duration > 0 {
+				duration = playback.Duration
+				break
+			}
+		}
+		if duration == 0 {
+			return
+		}
+
+		err = m.Mpv.SetProperty("chapter-list", mpvChaptersFromSegments(markers.Segments, duration))
+		if err != nil {
+			m.Logger.Warn().Err(err).Msg("media player: Could not set skip segments as mpv chapters")
+			return
+		}
+
+		m.Logger.Debug().Int("segments", len(markers.Segments)).Msg("media player: Skip segments added as mpv chapters")
+	}()
+}
+
+// mpvChaptersFromSegments returns the mpv chapter list delimiting the skip segments.
+func mpvChaptersFromSegments(segments []*skipsegments.Segment, duration float64) []map[string]interface{} {
+	ret := make([]map[string]interface{}, 0, len(segments)*2+1)
+	addChapter := func(title string, t float64) {
+		// Replace chapters starting at the same time
+		if len(ret) > 0 && ret[len(ret)-1]["time"].(float64) >= t-1 {
+			ret = ret[:len(ret)-1]
+		}
+		ret = append(ret, map[string]interface{}{"title": title, "time": t})
+	}
+
+	var intro, outro *skipsegments.Segment
+	for _, s := range segments {
+		switch s.Type {
+		case skipsegments.SegmentTypeIntro:
+			intro = s
+		case skipsegments.SegmentTypeOutro:
+			outro = s
+		}
+	}
+
+	if intro != nil {
+		addChapter("Prologue", 0)
+		addChapter("Opening", intro.StartTime)
+		addChapter("Episode", intro.EndTime)
+	} else {
+		addChapter("Episode", 0)
+	}
+	if outro != nil {
+		addChapter("Ending", outro.StartTime)
+		if outro.EndTime < duration-1 {
+			addChapter("Preview", outro.EndTime)
+		}
+	}
+
+	return ret
+}
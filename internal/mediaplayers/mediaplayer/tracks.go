@@ -0,0 +1,187 @@
+package mediaplayer
+
+import (
+	"fmt"
+	"seanime/internal/mediastream/videofile"
+	"seanime/internal/trackpreference"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// getTrackSelection applies the track preferences to the local file.
+// It returns nil if no preference applies or if the tracks of the file could not be read.
+func (m *Repository) getTrackSelection(path string) (*trackpreference.Selection, *videofile.MediaInfo) {
+	if m.trackPreferences == nil {
+		return nil, nil
+	}
+
+	selection, info, err := m.trackPreferences.SelectTracksForFile(path)
+	if err != nil {
+		m.Logger.Warn().Err(err).Msg("media player: Could not read the tracks of the file, track preferences will not be applied")
+		return nil, nil
+	}
+	if info == nil {
+		return nil, nil
+	}
+
+	m.Logger.Debug().Int("audio", selection.AudioIndex).Int("subtitle", selection.SubtitleIndex).Bool("noSubtitles", selection.NoSubtitles).Msg("media player: Track preferences applied")
+
+	return selection, info
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// MPV
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// mpvTrackOptions returns the mpv options selecting the tracks.
+// mpv track IDs start at 1 for each track type.
+func mpvTrackOptions(selection *trackpreference.Selection) map[string]string {
+	ret := map[string]string{
+		"aid": "auto",
+		"sid": "auto",
+	}
+	if selection.HasAudio() {
+		ret["aid"] = strconv.Itoa(selection.AudioIndex + 1)
+	}
+	if selection.HasSubtitle() {
+		ret["sid"] = strconv.Itoa(selection.SubtitleIndex + 1)
+	} else if selection != nil && selection.NoSubtitles {
+		ret["sid"] = "no"
+	}
+	return ret
+}
+
+// mpvLanguageOptions returns the mpv options selecting the tracks by language.
+// This is used for streams since their tracks are not known in advance.
+func (m *Repository) mpvLanguageOptions(mediaId int) map[string]string {
+	ret := map[string]string{
+		"aid": "auto",
+		"sid": "auto",
+	}
+	if m.trackPreferences == nil {
+		return ret
+	}
+	prefs := m.trackPreferences.GetPreferences(mediaId)
+	ret["alang"] = strings.Join(prefs.AudioLanguages, ",")
+	ret["slang"] = strings.Join(prefs.SubtitleLanguages, ",")
+	return ret
+}
+
+// applyMpvOptions sets the options on the running player and returns them as command-line arguments.
+// The arguments are used when mpv is launched, otherwise the file is loaded in the running player and the properties apply.
+func (m *Repository) applyMpvOptions(options map[string]string) []string {
+	args := make([]string, 0, len(options))
+	for name, value := range options {
+		args = append(args, fmt.Sprintf("--%s=%s", name, value))
+		_ = m.Mpv.SetProperty(name, value)
+	}
+	return args
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// VLC
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// applyVlcTracks selects the tracks once VLC has opened the file.
+func (m *Repository) applyVlcTracks(selection *trackpreference.Selection) {
+	if selection == nil || (!selection.HasAudio() && !selection.HasSubtitle() && !selection.NoSubtitles) {
+		return
+	}
+
+	go func() {
+		// Wait for VLC to list the streams of the file
+		var audioIds, subtitleIds []int
+		for i := 0; i < 10; i++ {
+			time.Sleep(500 * time.Millisecond)
+			audioIds, _ = m.VLC.GetStreamIds("Audio")
+			subtitleIds, _ = m.VLC.GetStreamIds("Subtitle")
+			if len(audioIds) > 0 {
+				break
+			}
+		}
+
+		if selection.HasAudio() && selection.AudioIndex < len(audioIds) {
+			_ = m.VLC.SelectAudioTrack(audioIds[selection.AudioIndex])
+		}
+		if selection.HasSubtitle() && selection.SubtitleIndex < len(subtitleIds) {
+			_ = m.VLC.SelectSubtitleTrack(subtitleIds[selection.SubtitleIndex])
+		} else if selection.NoSubtitles {
+			_ = m.VLC.SelectSubtitleTrack(-1)
+		}
+	}()
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// MPC-HC
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// applyMpcHcTracks selects the tracks once MPC-HC has opened the file.
+// MPC-HC can only cycle through the tracks, starting from the default track of the file.
+func (m *Repository) applyMpcHcTracks(selection *trackpreference.Selection, info *videofile.MediaInfo) {
+	if selection == nil || info == nil {
+		return
+	}
+
+	audios := trackpreference.AudioTracksFromMediaInfo(info)
+	subtitles := mpcHcSubtitleTracks(info)
+
+	audioSteps := 0
+	if selection.HasAudio() {
+		audioSteps = cycleSteps(audios, selection.AudioIndex)
+	}
+	subtitleSteps := 0
+	if selection.HasSubtitle() {
+		subtitleSteps = cycleSteps(subtitles, selection.SubtitleIndex)
+	}
+	toggleSubtitles := selection.NoSubtitles && len(subtitles) > 0
+
+	if audioSteps == 0 && subtitleSteps == 0 && !toggleSubtitles {
+		return
+	}
+
+	go func() {
+		time.Sleep(1 * time.Second)
+		for i := 0; i < audioSteps; i++ {
+			_ = m.MpcHc.NextAudioTrack()
+		}
+		for i := 0; i < subtitleSteps; i++ {
+			_ = m.MpcHc.NextSubtitleTrack()
+		}
+		if toggleSubtitles {
+			_ = m.MpcHc.ToggleSubtitles()
+		}
+	}()
+}
+
+// mpcHcSubtitleTracks returns the subtitle tracks in the order MPC-HC cycles through them.
+// The player also lists the subtitle tracks that are not supported by the media info, e.g. image-based subtitles.
+func mpcHcSubtitleTracks(info *videofile.MediaInfo) []*trackpreference.Track {
+	ret := trackpreference.SubtitleTracksFromMediaInfo(info)
+	for _, s := range info.UnsupportedSubtitles {
+		ret = append(ret, &trackpreference.Track{
+			Index:     int(s.Index),
+			IsDefault: s.IsDefault,
+		})
+	}
+	slices.SortFunc(ret, func(a, b *trackpreference.Track) int {
+		return a.Index - b.Index
+	})
+	return ret
+}
+
+// cycleSteps returns the number of times the player should switch to the next track to reach the target track.
+// tracks are all the tracks of the type, in the order of the player, target is the Index of the track to select.
+func cycleSteps(tracks []*trackpreference.Track, target int) int {
+	targetPos := slices.IndexFunc(tracks, func(t *trackpreference.Track) bool {
+		return t.Index == target
+	})
+	if targetPos == -1 {
+		return 0
+	}
+	current := max(slices.IndexFunc(tracks, func(t *trackpreference.Track) bool {
+		return t.IsDefault
+	}), 0)
+	return (targetPos - current + len(tracks)) % len(tracks)
+}
@@ -0,0 +1,49 @@
+package mediaplayer
+
+import (
+	"seanime/internal/mediastream/videofile"
+	"seanime/internal/trackpreference"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCycleSteps(t *testing.T) {
+	// The second subtitle stream is image-based, it is not in the subtitles of the media info
+	info := &videofile.MediaInfo{
+		Subtitles: []videofile.Subtitle{
+			{Index: 0, Language: lo.ToPtr("eng"), IsDefault: true},
+			{Index: 2, Language: lo.ToPtr("fre")},
+			{Index: 3, Language: lo.ToPtr("ger")},
+		},
+		UnsupportedSubtitles: []videofile.Subtitle{
+			{Index: 1, Language: lo.ToPtr("eng")},
+		},
+	}
+	subtitles := mpcHcSubtitleTracks(info)
+	assert.Equal(t, []int{0, 1, 2, 3}, lo.Map(subtitles, func(track *trackpreference.Track, _ int) int { return track.Index }))
+
+	tests := []struct {
+		name     string
+		target   int
+		expected int
+	}{
+		{name: "default track", target: 0, expected: 0},
+		{name: "after the filtered-out track", target: 2, expected: 2},
+		{name: "last track", target: 3, expected: 3},
+		{name: "unknown track", target: 5, expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, cycleSteps(subtitles, tt.target))
+		})
+	}
+
+	// The player starts from the default track and wraps around
+	info.Subtitles[0].IsDefault = false
+	info.UnsupportedSubtitles[0].IsDefault = true
+	subtitles = mpcHcSubtitleTracks(info)
+	assert.Equal(t, 3, cycleSteps(subtitles, 0))
+	assert.Equal(t, 1, cycleSteps(subtitles, 2))
+}
@@ -27,6 +27,24 @@ func (api *MpcHc) ToggleFullScreen() (err error) {
 	return
 }
 
+// NextAudioTrack switches to the next audio track
+func (api *MpcHc) NextAudioTrack() (err error) {
+	_, err = api.Execute(nextAudioCmd, nil)
+	return
+}
+
+// NextSubtitleTrack switches to the next subtitle track
+func (api *MpcHc) NextSubtitleTrack() (err error) {
+	_, err = api.Execute(nextSubtitleCmd, nil)
+	return
+}
+
+// ToggleSubtitles turns the subtitles on or off
+func (api *MpcHc) ToggleSubtitles() (err error) {
+	_, err = api.Execute(onOffSubtitleCmd, nil)
+	return
+}
+
 // Seek position in ms
 func (api *MpcHc) Seek(pos int) (err error) {
 	_, err = api.Execute(seekCmd, map[string]interface{}{"position": millisecondsToDuration(pos)})
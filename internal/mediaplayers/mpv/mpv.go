@@ -258,6 +258,20 @@ func (m *Mpv) SeekTo(position float64) error {
 	return nil
 }
 
+// SetProperty sets a property of the running player.
+// Track options (e.g. "aid", "sid") also apply to the next files that are loaded.
+func (m *Mpv) SetProperty(name string, value interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.conn == nil || m.conn.IsClosed() {
+		return errors.New("mpv is not running")
+	}
+
+	_, err := m.conn.Call("set_property", name, value)
+	return err
+}
+
 func (m *Mpv) establishConnection() error {
 	tries := 1
 	for {
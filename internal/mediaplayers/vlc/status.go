@@ -5,6 +5,7 @@ import (
 	"github.com/goccy/go-json"
 	"net/url"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -116,6 +117,28 @@ func (vlc *VLC) GetStatus() (status *Status, err error) {
 	return
 }
 
+// GetStreamIds returns the ids of the elementary streams of the given type (e.g. "Audio", "Subtitle") of the current item, in order.
+// The ids can be used with SelectAudioTrack and SelectSubtitleTrack.
+func (vlc *VLC) GetStreamIds(streamType string) (ids []int, err error) {
+	status, err := vlc.GetStatus()
+	if err != nil {
+		return
+	}
+	for name, category := range status.Information.Category {
+		id, found := strings.CutPrefix(name, "Stream ")
+		if !found || !strings.EqualFold(category.Type, streamType) {
+			continue
+		}
+		i, err := strconv.Atoi(id)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, i)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
 // Play playlist item with given id. If id is omitted, play last active item
 func (vlc *VLC) Play(itemID ...int) (err error) {
 	// Check variadic arguments and form urlSegment
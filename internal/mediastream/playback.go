@@ -6,6 +6,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/samber/mo"
 	"seanime/internal/mediastream/videofile"
+	"seanime/internal/trackpreference"
 	"seanime/internal/util/result"
 )
 
@@ -35,6 +36,8 @@ type (
 		StreamType StreamType           `json:"streamType"` // Tells the frontend how to play the media.
 		StreamUrl  string               `json:"streamUrl"`  // The relative endpoint to stream the media.
 		MediaInfo  *videofile.MediaInfo `json:"mediaInfo"`
+		// The tracks to select according to the track preferences.
+		TrackSelection *trackpreference.Selection `json:"trackSelection"`
 		//Metadata  *Metadata       `json:"metadata"`
 		// todo: add more fields (e.g. metadata)
 	}
@@ -69,6 +72,9 @@ func (p *PlaybackManager) RequestPlayback(filepath string, streamType StreamType
 		return nil, fmt.Errorf("failed to create media container: %v", err)
 	}
 
+	// Apply the track preferences, they might have changed since the container was created
+	p.applyTrackPreferences(ret)
+
 	// Set the current media container.
 	p.currentMediaContainer = mo.Some(ret)
 
@@ -95,6 +101,14 @@ func (p *PlaybackManager) PreloadPlayback(filepath string, streamType StreamType
 	return
 }
 
+func (p *PlaybackManager) applyTrackPreferences(mc *MediaContainer) {
+	if p.repository.trackPreferences == nil {
+		return
+	}
+	mediaId := p.repository.trackPreferences.GetMediaIdFromPath(mc.Filepath)
+	mc.TrackSelection = p.repository.trackPreferences.SelectTracks(mediaId, mc.MediaInfo)
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Optimize
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
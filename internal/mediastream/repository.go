@@ -10,7 +10,9 @@ import (
 	"seanime/internal/events"
 	"seanime/internal/mediastream/optimizer"
 	"seanime/internal/mediastream/transcoder"
+	"seanime/internal/mediastream/trickplay"
 	"seanime/internal/mediastream/videofile"
+	"seanime/internal/trackpreference"
 	"seanime/internal/util/filecache"
 	"sync"
 )
@@ -25,15 +27,18 @@ type (
 		logger             *zerolog.Logger
 		wsEventManager     events.WSEventManagerInterface
 		fileCacher         *filecache.Cacher
+		trackPreferences   *trackpreference.Repository
+		trickplay          *trickplay.Generator
 		reqMu              sync.Mutex
 		cacheDir           string // where attachments are stored
 		transcodeDir       string // where stream segments are stored
 	}
 
 	NewRepositoryOptions struct {
-		Logger         *zerolog.Logger
-		WSEventManager events.WSEventManagerInterface
-		FileCacher     *filecache.Cacher
+		Logger           *zerolog.Logger
+		WSEventManager   events.WSEventManagerInterface
+		FileCacher       *filecache.Cacher
+		TrackPreferences *trackpreference.Repository
 	}
 )
 
@@ -48,7 +53,13 @@ func NewRepository(opts *NewRepositoryOptions) *Repository {
 		transcoder:         mo.None[*transcoder.Transcoder](),
 		wsEventManager:     opts.WSEventManager,
 		fileCacher:         opts.FileCacher,
+		trackPreferences:   opts.TrackPreferences,
 		mediaInfoExtractor: videofile.NewMediaInfoExtractor(opts.FileCacher, opts.Logger),
+		trickplay: trickplay.NewGenerator(&trickplay.NewGeneratorOptions{
+			Logger:         opts.Logger,
+			WSEventManager: opts.WSEventManager,
+			FileCacher:     opts.FileCacher,
+		}),
 	}
 	ret.playbackManager = NewPlaybackManager(ret)
 
@@ -88,6 +99,12 @@ func (r *Repository) InitializeModules(settings *models.MediastreamSettings, cac
 	r.cacheDir = cacheDir
 	r.transcodeDir = transcodeDir
 
+	r.trickplay.SetSettings(&trickplay.Settings{
+		FfmpegPath:  settings.FfmpegPath,
+		FfprobePath: settings.FfprobePath,
+		CacheDir:    cacheDir,
+	})
+
 	// Set the optimizer settings
 	r.optimizer.SetLibraryDir(settings.PreTranscodeLibraryDir)
 
@@ -269,6 +286,7 @@ func (r *Repository) initializeTranscoder(settings mo.Option[*models.Mediastream
 		FfprobePath:           settings.MustGet().FfprobePath,
 		HwAccelCustomSettings: settings.MustGet().TranscodeHwAccelCustomSettings,
 		TempOutDir:            r.transcodeDir,
+		Tonemap:               settings.MustGet().TranscodeTonemap,
 	}
 
 	tc, err := transcoder.NewTranscoder(opts)
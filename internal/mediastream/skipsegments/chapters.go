@@ -0,0 +1,65 @@
+package skipsegments
+
+import (
+	"regexp"
+	"seanime/internal/mediastream/videofile"
+	"strings"
+)
+
+const (
+	minChapterSegmentDuration = 10  // seconds
+	maxChapterSegmentDuration = 240 // seconds
+)
+
+var (
+	introChapterRegex = regexp.MustCompile(`^(op|nc ?op|opening|intro|introduction|opening (song|theme|credits))\s*\d*$`)
+	outroChapterRegex = regexp.MustCompile(`^(ed|nc ?ed|ending|outro|credits|end credits|ending (song|theme|credits))\s*\d*$`)
+)
+
+// segmentsFromChapters returns the intro and outro ranges named in the chapters of the file.
+func segmentsFromChapters(chapters []videofile.Chapter) []*Segment {
+	ret := make([]*Segment, 0)
+
+	for _, chapter := range chapters {
+		t, ok := chapterSegmentType(chapter.Name)
+		if !ok {
+			continue
+		}
+		duration := chapter.EndTime - chapter.StartTime
+		if duration < minChapterSegmentDuration || duration > maxChapterSegmentDuration {
+			continue
+		}
+		// Only keep the first range of each type
+		if hasSegmentType(ret, t) {
+			continue
+		}
+		ret = append(ret, &Segment{
+			Type:      t,
+			StartTime: float64(chapter.StartTime),
+			EndTime:   float64(chapter.EndTime),
+		})
+	}
+
+	return ret
+}
+
+func chapterSegmentType(name string) (SegmentType, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.NewReplacer("_", " ", "-", " ", ".", "").Replace(name)
+	switch {
+	case introChapterRegex.MatchString(name):
+		return SegmentTypeIntro, true
+	case outroChapterRegex.MatchString(name):
+		return SegmentTypeOutro, true
+	}
+	return "", false
+}
+
+func hasSegmentType(segments []*Segment, t SegmentType) bool {
+	for _, s := range segments {
+		if s.Type == t {
+			return true
+		}
+	}
+	return false
+}
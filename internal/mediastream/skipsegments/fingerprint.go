@@ -0,0 +1,136 @@
+package skipsegments
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"seanime/internal/util"
+	"strconv"
+)
+
+const (
+	// fingerprintItemDuration is the duration covered by each item of a chromaprint fingerprint (seconds).
+	// Chromaprint resamples the audio to 11025Hz and emits an item every 4096/3 samples.
+	fingerprintItemDuration = 4096.0 / 3.0 / 11025.0
+
+	introWindow = 300 // Seconds analyzed at the start of the episode
+	outroWindow = 240 // Seconds analyzed at the end of the episode
+
+	minFingerprintSegmentDuration = 15  // seconds
+	maxFingerprintSegmentDuration = 150 // seconds
+
+	minFingerprintItems = minFingerprintSegmentDuration * 11025 * 3 / 4096
+
+	// maxItemBitErrors is the number of bits that can differ for two items to be considered the same audio.
+	maxItemBitErrors = 6
+	// maxItemGap is the number of consecutive mismatching items tolerated inside a common range.
+	maxItemGap = 28
+)
+
+// fingerprint is the raw chromaprint fingerprint of a part of the audio of a file.
+type fingerprint struct {
+	Offset float64  `json:"offset"` // Start of the analyzed part (seconds)
+	Items  []uint32 `json:"items"`
+}
+
+// getFingerprint computes the chromaprint fingerprint of the audio between start and start+duration using FFmpeg.
+func getFingerprint(ctx context.Context, ffmpegPath string, path string, start float64, duration float64) (*fingerprint, error) {
+	if start < 0 {
+		start = 0
+	}
+
+	cmd := util.NewCmdCtx(ctx, ffmpegPath,
+		"-hide_banner",
+		"-loglevel", "error",
+		"-ss", strconv.FormatFloat(start, 'f', 3, 64),
+		"-t", strconv.FormatFloat(duration, 'f', 3, 64),
+		"-i", path,
+		"-map", "0:a:0",
+		"-ac", "1",
+		"-f", "chromaprint",
+		"-fp_format", "raw",
+		"-",
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	raw := stdout.Bytes()
+	if len(raw) < 4 {
+		return nil, errors.New("empty fingerprint")
+	}
+
+	items := make([]uint32, len(raw)/4)
+	for i := range items {
+		items[i] = binary.LittleEndian.Uint32(raw[i*4:])
+	}
+
+	return &fingerprint{
+		Offset: start,
+		Items:  items,
+	}, nil
+}
+
+// commonRange is a range of audio shared by two fingerprints, in items.
+type commonRange struct {
+	AStart int
+	BStart int
+	Length int
+}
+
+// findCommonRange returns the longest range of audio shared by both fingerprints.
+// Every alignment of the fingerprints is tried, and short mismatches inside a range are tolerated.
+func findCommonRange(a []uint32, b []uint32) (ret commonRange, found bool) {
+	// a[i] is aligned with b[i-shift]
+	for shift := -(len(b) - 1); shift < len(a); shift++ {
+		iStart := max(0, shift)
+		iEnd := min(len(a), len(b)+shift)
+
+		runStart, lastMatch := -1, -1
+		for i := iStart; i < iEnd; i++ {
+			if bits.OnesCount32(a[i]^b[i-shift]) > maxItemBitErrors {
+				if runStart != -1 && i-lastMatch > maxItemGap {
+					runStart = -1
+				}
+				continue
+			}
+			if runStart == -1 {
+				runStart = i
+			}
+			lastMatch = i
+
+			if length := lastMatch - runStart + 1; length > ret.Length {
+				ret = commonRange{
+					AStart: runStart,
+					BStart: runStart - shift,
+					Length: length,
+				}
+			}
+		}
+	}
+
+	return ret, ret.Length >= minFingerprintItems
+}
+
+// segmentsFromCommonRange converts the common range to a segment for each file.
+func segmentsFromCommonRange(t SegmentType, a *fingerprint, b *fingerprint, r commonRange) (*Segment, *Segment, bool) {
+	duration := float64(r.Length) * fingerprintItemDuration
+	if duration < minFingerprintSegmentDuration || duration > maxFingerprintSegmentDuration {
+		return nil, nil, false
+	}
+
+	aStart := a.Offset + float64(r.AStart)*fingerprintItemDuration
+	bStart := b.Offset + float64(r.BStart)*fingerprintItemDuration
+
+	return &Segment{Type: t, StartTime: aStart, EndTime: aStart + duration},
+		&Segment{Type: t, StartTime: bStart, EndTime: bStart + duration},
+		true
+}
@@ -0,0 +1,323 @@
+package skipsegments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"seanime/internal/database/db"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/events"
+	"seanime/internal/library/anime"
+	"seanime/internal/mediastream/videofile"
+	"seanime/internal/util"
+	"seanime/internal/util/filecache"
+	"seanime/internal/util/result"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+)
+
+type (
+	SegmentType string
+	Source      string
+
+	// Segment is a range of the episode that can be skipped.
+	Segment struct {
+		Type      SegmentType `json:"type"`
+		StartTime float64     `json:"startTime"` // seconds
+		EndTime   float64     `json:"endTime"`   // seconds
+	}
+
+	// Markers are the skip segments of a file.
+	Markers struct {
+		Hash      string     `json:"hash"`
+		Path      string     `json:"path"`
+		Source    Source     `json:"source"`
+		Segments  []*Segment `json:"segments"`
+		CreatedAt time.Time  `json:"createdAt"`
+	}
+
+	// Manager derives the skip segments of local files and stores them per file hash.
+	// Segments come from the chapters of the file, or are detected by comparing the audio of consecutive episodes.
+	Manager struct {
+		logger             *zerolog.Logger
+		db                 *db.Database
+		fileCacher         *filecache.Cacher
+		wsEventManager     events.WSEventManagerInterface
+		mediaInfoExtractor *videofile.MediaInfoExtractor
+		detecting          *result.Map[string, struct{}] // Hashes of the files being analyzed
+		failed             *result.Map[string, struct{}] // Hashes of the files that could not be analyzed
+	}
+
+	NewManagerOptions struct {
+		Logger         *zerolog.Logger
+		Database       *db.Database
+		FileCacher     *filecache.Cacher
+		WSEventManager events.WSEventManagerInterface
+	}
+)
+
+const (
+	SegmentTypeIntro SegmentType = "intro"
+	SegmentTypeOutro SegmentType = "outro"
+
+	SourceChapters    Source = "chapters"
+	SourceFingerprint Source = "fingerprint"
+	SourceNone        Source = "none" // Nothing was found
+)
+
+const detectionTimeout = 5 * time.Minute
+
+var ErrDetectionInProgress = errors.New("skip segments are being detected")
+
+func NewManager(opts *NewManagerOptions) *Manager {
+	return &Manager{
+		logger:             opts.Logger,
+		db:                 opts.Database,
+		fileCacher:         opts.FileCacher,
+		wsEventManager:     opts.WSEventManager,
+		mediaInfoExtractor: videofile.NewMediaInfoExtractor(opts.FileCacher, opts.Logger),
+		detecting:          result.NewResultMap[string, struct{}](),
+		failed:             result.NewResultMap[string, struct{}](),
+	}
+}
+
+func (m *Manager) getMarkersBucket() filecache.PermanentBucket {
+	return filecache.NewPermanentBucket("skipsegments_markers")
+}
+
+func (m *Manager) getFingerprintBucket(hash string) filecache.Bucket {
+	return filecache.NewBucket("skipsegments_fingerprint_"+hash, 30*24*time.Hour)
+}
+
+// GetMarkers returns the stored skip segments of the file.
+func (m *Manager) GetMarkers(path string) (*Markers, bool) {
+	hash, err := videofile.GetHashFromPath(path)
+	if err != nil {
+		return nil, false
+	}
+	var ret *Markers
+	if found, _ := m.fileCacher.GetPerm(m.getMarkersBucket(), hash, &ret); found && ret != nil {
+		return ret, true
+	}
+	return nil, false
+}
+
+// RequestMarkers returns the skip segments of the file.
+// If they are not stored, they are derived from the chapters of the file.
+// When the file has no intro/outro chapters, detection is started in the background and ErrDetectionInProgress is returned,
+// the markers are sent to the client once they are available.
+func (m *Manager) RequestMarkers(path string) (*Markers, error) {
+	if markers, found := m.GetMarkers(path); found {
+		return markers, nil
+	}
+
+	hash, err := videofile.GetHashFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := m.mediaInfoExtractor.GetInfo(m.getFfprobePath(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	if segments := segmentsFromChapters(info.Chapters); len(segments) > 0 {
+		return m.saveMarkers(path, hash, SourceChapters, segments), nil
+	}
+
+	if _, failed := m.failed.Get(hash); failed {
+		return m.newMarkers(path, hash, SourceNone, nil), nil
+	}
+
+	if _, detecting := m.detecting.Get(hash); !detecting {
+		m.detecting.Set(hash, struct{}{})
+		go func() {
+			defer util.HandlePanicInModuleThen("mediastream/skipsegments/RequestMarkers", func() {})
+			defer m.detecting.Delete(hash)
+
+			ctx, cancel := context.WithTimeout(context.Background(), detectionTimeout)
+			defer cancel()
+
+			if err := m.detect(ctx, path, hash, info); err != nil {
+				m.logger.Warn().Err(err).Str("path", path).Msg("skipsegments: Could not detect skip segments")
+				m.failed.Set(hash, struct{}{})
+				m.wsEventManager.SendEvent(events.SkipSegmentsUpdated, m.newMarkers(path, hash, SourceNone, nil))
+			}
+		}()
+	}
+
+	return nil, ErrDetectionInProgress
+}
+
+// DeleteMarkers removes the stored skip segments of the file so that they are derived again.
+func (m *Manager) DeleteMarkers(path string) error {
+	hash, err := videofile.GetHashFromPath(path)
+	if err != nil {
+		return err
+	}
+	m.failed.Delete(hash)
+	return m.fileCacher.DeletePerm(m.getMarkersBucket(), hash)
+}
+
+func (m *Manager) newMarkers(path string, hash string, source Source, segments []*Segment) *Markers {
+	if segments == nil {
+		segments = make([]*Segment, 0)
+	}
+	return &Markers{
+		Hash:      hash,
+		Path:      path,
+		Source:    source,
+		Segments:  segments,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (m *Manager) saveMarkers(path string, hash string, source Source, segments []*Segment) *Markers {
+	markers := m.newMarkers(path, hash, source, segments)
+	if err := m.fileCacher.SetPerm(m.getMarkersBucket(), hash, markers); err != nil {
+		m.logger.Error().Err(err).Msg("skipsegments: Failed to save markers")
+	}
+	m.wsEventManager.SendEvent(events.SkipSegmentsUpdated, markers)
+	return markers
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// detect finds the intro and outro of the file by comparing its audio with another episode of the same series.
+// The markers of both files are saved.
+func (m *Manager) detect(ctx context.Context, path string, hash string, info *videofile.MediaInfo) error {
+	otherPath, found := m.findOtherEpisode(path)
+	if !found {
+		return errors.New("no other episode of the series found")
+	}
+
+	otherHash, err := videofile.GetHashFromPath(otherPath)
+	if err != nil {
+		return err
+	}
+	otherInfo, err := m.mediaInfoExtractor.GetInfo(m.getFfprobePath(), otherPath)
+	if err != nil {
+		return err
+	}
+
+	m.logger.Debug().Str("path", path).Str("other", otherPath).Msg("skipsegments: Detecting skip segments using audio fingerprints")
+
+	segments := make([]*Segment, 0, 2)
+	otherSegments := make([]*Segment, 0, 2)
+
+	windows := []struct {
+		t      SegmentType
+		start  func(duration float64) float64
+		length float64
+	}{
+		{SegmentTypeIntro, func(float64) float64 { return 0 }, introWindow},
+		{SegmentTypeOutro, func(d float64) float64 { return d - outroWindow }, outroWindow},
+	}
+
+	for _, w := range windows {
+		a, err := m.getFingerprint(ctx, path, hash, w.t, w.start(float64(info.Duration)), w.length)
+		if err != nil {
+			return err
+		}
+		b, err := m.getFingerprint(ctx, otherPath, otherHash, w.t, w.start(float64(otherInfo.Duration)), w.length)
+		if err != nil {
+			return err
+		}
+
+		r, found := findCommonRange(a.Items, b.Items)
+		if !found {
+			continue
+		}
+		if s, o, ok := segmentsFromCommonRange(w.t, a, b, r); ok {
+			segments = append(segments, s)
+			otherSegments = append(otherSegments, o)
+		}
+	}
+
+	if len(segments) == 0 {
+		return errors.New("no common audio found")
+	}
+
+	m.saveMarkers(path, hash, SourceFingerprint, segments)
+	// Don't overwrite the markers of the other episode if it already has some
+	if _, found := m.GetMarkers(otherPath); !found && len(otherSegments) > 0 {
+		m.saveMarkers(otherPath, otherHash, SourceFingerprint, otherSegments)
+	}
+
+	m.logger.Debug().Str("path", path).Int("segments", len(segments)).Msg("skipsegments: Skip segments detected")
+
+	return nil
+}
+
+// getFingerprint returns the fingerprint of a part of the file, computed fingerprints are cached.
+func (m *Manager) getFingerprint(ctx context.Context, path string, hash string, t SegmentType, start float64, duration float64) (*fingerprint, error) {
+	bucket := m.getFingerprintBucket(hash)
+	key := fmt.Sprintf("%s_%.0f_%.0f", t, start, duration)
+
+	var ret *fingerprint
+	if found, _ := m.fileCacher.Get(bucket, key, &ret); found && ret != nil {
+		return ret, nil
+	}
+
+	ret, err := getFingerprint(ctx, m.getFfmpegPath(), path, start, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = m.fileCacher.Set(bucket, key, ret)
+	return ret, nil
+}
+
+// findOtherEpisode returns the path of the next episode of the series, or the previous one if it is the last episode.
+func (m *Manager) findOtherEpisode(path string) (string, bool) {
+	lfs, _, err := db_bridge.GetLocalFiles(m.db)
+	if err != nil {
+		return "", false
+	}
+
+	normalized := util.NormalizePath(path)
+	current, found := lo.Find(lfs, func(lf *anime.LocalFile) bool {
+		return lf.GetNormalizedPath() == normalized
+	})
+	if !found || current.MediaId == 0 {
+		return "", false
+	}
+
+	episodes := make([]*anime.LocalFile, 0)
+	for _, lf := range lfs {
+		if lf.MediaId == current.MediaId && lf.IsMain() && lf.GetNormalizedPath() != normalized {
+			episodes = append(episodes, lf)
+		}
+	}
+	if len(episodes) == 0 {
+		return "", false
+	}
+
+	slices.SortFunc(episodes, func(a, b *anime.LocalFile) int {
+		return a.GetEpisodeNumber() - b.GetEpisodeNumber()
+	})
+
+	for _, lf := range episodes {
+		if lf.GetEpisodeNumber() > current.GetEpisodeNumber() {
+			return lf.Path, true
+		}
+	}
+	return episodes[len(episodes)-1].Path, true
+}
+
+func (m *Manager) getFfmpegPath() string {
+	if settings, found := m.db.GetMediastreamSettings(); found && settings.FfmpegPath != "" {
+		return settings.FfmpegPath
+	}
+	return "ffmpeg"
+}
+
+func (m *Manager) getFfprobePath() string {
+	if settings, found := m.db.GetMediastreamSettings(); found && settings.FfprobePath != "" {
+		return settings.FfprobePath
+	}
+	return "ffprobe"
+}
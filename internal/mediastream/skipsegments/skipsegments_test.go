@@ -0,0 +1,91 @@
+package skipsegments
+
+import (
+	"math/rand"
+	"seanime/internal/mediastream/videofile"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentsFromChapters(t *testing.T) {
+	chapters := []videofile.Chapter{
+		{StartTime: 0, EndTime: 95, Name: "Prologue"},
+		{StartTime: 95, EndTime: 185, Name: "Opening"},
+		{StartTime: 185, EndTime: 1300, Name: "Part A"},
+		{StartTime: 1300, EndTime: 1390, Name: "ED"},
+		{StartTime: 1390, EndTime: 1420, Name: "Preview"},
+	}
+
+	segments := segmentsFromChapters(chapters)
+	require.Equal(t, []*Segment{
+		{Type: SegmentTypeIntro, StartTime: 95, EndTime: 185},
+		{Type: SegmentTypeOutro, StartTime: 1300, EndTime: 1390},
+	}, segments)
+
+	// Ranges that are too short or too long are ignored
+	require.Empty(t, segmentsFromChapters([]videofile.Chapter{
+		{StartTime: 0, EndTime: 5, Name: "OP"},
+		{StartTime: 5, EndTime: 1400, Name: "Ending"},
+	}))
+
+	for _, name := range []string{"OP", "op1", "Opening Song", "NCOP", "Intro"} {
+		typ, ok := chapterSegmentType(name)
+		require.True(t, ok, name)
+		require.Equal(t, SegmentTypeIntro, typ, name)
+	}
+	for _, name := range []string{"ED", "ED 2", "Ending_Credits", "Outro", "Credits"} {
+		typ, ok := chapterSegmentType(name)
+		require.True(t, ok, name)
+		require.Equal(t, SegmentTypeOutro, typ, name)
+	}
+	for _, name := range []string{"Part A", "Opening Act", "Edit", "Chapter 01"} {
+		_, ok := chapterSegmentType(name)
+		require.False(t, ok, name)
+	}
+}
+
+func TestFindCommonRange(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randomItems := func(n int) []uint32 {
+		ret := make([]uint32, n)
+		for i := range ret {
+			ret[i] = rnd.Uint32()
+		}
+		return ret
+	}
+
+	// 90 seconds of shared audio
+	shared := randomItems(727)
+
+	// The intro starts after a cold open of different lengths
+	a := append(randomItems(400), shared...)
+	a = append(a, randomItems(300)...)
+	b := append(randomItems(150), shared...)
+	b = append(b, randomItems(500)...)
+
+	// Add noise to the second fingerprint
+	for i := 150; i < 150+len(shared); i += 10 {
+		b[i] ^= 0b101
+	}
+	// And a short glitch
+	for i := 500; i < 510; i++ {
+		b[i] = rnd.Uint32()
+	}
+
+	r, found := findCommonRange(a, b)
+	require.True(t, found)
+	require.Equal(t, 400, r.AStart)
+	require.Equal(t, 150, r.BStart)
+	require.Equal(t, len(shared), r.Length)
+
+	sa, sb, ok := segmentsFromCommonRange(SegmentTypeIntro, &fingerprint{Offset: 0, Items: a}, &fingerprint{Offset: 0, Items: b}, r)
+	require.True(t, ok)
+	require.InDelta(t, 49.5, sa.StartTime, 0.1)
+	require.InDelta(t, 139.5, sa.EndTime, 0.1)
+	require.InDelta(t, 18.6, sb.StartTime, 0.1)
+
+	// Unrelated audio
+	_, found = findCommonRange(randomItems(1000), randomItems(1000))
+	require.False(t, found)
+}
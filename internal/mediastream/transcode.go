@@ -2,11 +2,16 @@ package mediastream
 
 import (
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"seanime/internal/events"
 	"seanime/internal/mediastream/transcoder"
+	"seanime/internal/mediastream/videofile"
 	"strconv"
 	"strings"
 
+	"github.com/samber/lo"
 	"github.com/samber/mo"
 
 	"github.com/labstack/echo/v4"
@@ -36,7 +41,11 @@ func (r *Repository) ServeEchoTranscodeStream(c echo.Context, clientId string) e
 	}
 
 	if path == "master.m3u8" {
-		ret, err := r.transcoder.MustGet().GetMaster(mediaContainer.Filepath, mediaContainer.Hash, mediaContainer.MediaInfo, clientId)
+		defaultAudio := -1
+		if mediaContainer.TrackSelection.HasAudio() {
+			defaultAudio = mediaContainer.TrackSelection.AudioIndex
+		}
+		ret, err := r.transcoder.MustGet().GetMaster(mediaContainer.Filepath, mediaContainer.Hash, mediaContainer.MediaInfo, defaultAudio, r.getBurnInSubtitle(mediaContainer), clientId)
 		if err != nil {
 			return err
 		}
@@ -141,6 +150,49 @@ func (r *Repository) ServeEchoTranscodeStream(c echo.Context, clientId string) e
 	return errors.New("invalid path")
 }
 
+// getBurnInSubtitle returns the subtitle track to render into the burn-in rendition.
+// It is the track selected by the track preferences, or the default track of the file.
+// It returns nil if burn-in is disabled or if there is no subtitle track to burn in.
+func (r *Repository) getBurnInSubtitle(mc *MediaContainer) *transcoder.BurnInSubtitle {
+	if !r.settings.MustGet().TranscodeBurnIn || mc.MediaInfo == nil || len(mc.MediaInfo.Subtitles) == 0 {
+		return nil
+	}
+
+	if mc.TrackSelection != nil && mc.TrackSelection.NoSubtitles {
+		return nil
+	}
+
+	var sub videofile.Subtitle
+	found := false
+	if mc.TrackSelection.HasSubtitle() {
+		sub, found = lo.Find(mc.MediaInfo.Subtitles, func(s videofile.Subtitle) bool {
+			return int(s.Index) == mc.TrackSelection.SubtitleIndex
+		})
+	}
+	if !found {
+		sub = lo.FindOrElse(mc.MediaInfo.Subtitles, mc.MediaInfo.Subtitles[0], func(s videofile.Subtitle) bool {
+			return s.IsDefault
+		})
+	}
+
+	ret := &transcoder.BurnInSubtitle{
+		Index: sub.Index,
+	}
+
+	// Use the subtitle file extracted by videofile.ExtractAttachment, FFmpeg would have to read the whole video file otherwise
+	if sub.Extension != nil {
+		subPath := filepath.Join(videofile.GetFileSubsCacheDir(r.cacheDir, mc.Hash), fmt.Sprintf("%d.%s", sub.Index, *sub.Extension))
+		if _, err := os.Stat(subPath); err == nil {
+			ret.Path = subPath
+		}
+	}
+	if len(mc.MediaInfo.Fonts) > 0 {
+		ret.FontsDir = videofile.GetFileAttCacheDir(r.cacheDir, mc.Hash)
+	}
+
+	return ret
+}
+
 // ShutdownTranscodeStream It should be called when unmounting the player (playback is no longer needed).
 // This will also send an events.MediastreamShutdownStream event.
 func (r *Repository) ShutdownTranscodeStream(clientId string) {
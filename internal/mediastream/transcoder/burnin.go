@@ -0,0 +1,48 @@
+package transcoder
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// BurnInSubtitle is the subtitle track rendered into the video of the BurnIn rendition.
+// This is useful for clients that cannot render ASS subtitles.
+type BurnInSubtitle struct {
+	// Index of the subtitle stream in the file (0:s:Index)
+	Index uint32
+	// Path of the extracted subtitle file. If empty, the track is read from the video file.
+	Path string
+	// Directory containing the fonts attached to the video file
+	FontsDir string
+}
+
+// burnInHeight is the maximum height of the BurnIn rendition.
+// The subtitles are rendered on the CPU so higher resolutions would be too slow to transcode in real-time.
+const burnInHeight = 1080
+
+// filter returns the FFmpeg video filter rendering the subtitles.
+// videoPath is used when the subtitle file has not been extracted.
+func (b *BurnInSubtitle) filter(videoPath string) string {
+	var ret string
+	if b.Path != "" {
+		ret = fmt.Sprintf("subtitles=f='%s'", escapeFilterValue(b.Path))
+	} else {
+		ret = fmt.Sprintf("subtitles=f='%s':si=%d", escapeFilterValue(videoPath), b.Index)
+	}
+	if b.FontsDir != "" {
+		ret += fmt.Sprintf(":fontsdir='%s'", escapeFilterValue(b.FontsDir))
+	}
+	return ret
+}
+
+// escapeFilterValue escapes a path so that it can be used as a quoted option value in a filtergraph.
+// The value is unescaped twice by FFmpeg, once when parsing the graph and once when parsing the filter options.
+// See https://ffmpeg.org/ffmpeg-filters.html#Notes-on-filtergraph-escaping
+func escapeFilterValue(value string) string {
+	value = filepath.ToSlash(value)
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, ":", `\:`)
+	value = strings.ReplaceAll(value, "'", `'\\\''`)
+	return value
+}
@@ -0,0 +1,80 @@
+package transcoder
+
+import (
+	"seanime/internal/mediastream/videofile"
+	"seanime/internal/util"
+	"seanime/internal/util/result"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBurnInSubtitleFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		burnIn   *BurnInSubtitle
+		expected string
+	}{
+		{
+			name:     "extracted file with fonts",
+			burnIn:   &BurnInSubtitle{Index: 1, Path: "/cache/videofiles/abc/subs/1.ass", FontsDir: "/cache/videofiles/abc/att"},
+			expected: "subtitles=f='/cache/videofiles/abc/subs/1.ass':fontsdir='/cache/videofiles/abc/att'",
+		},
+		{
+			name:     "read from the video file",
+			burnIn:   &BurnInSubtitle{Index: 2},
+			expected: "subtitles=f='/anime/Show - 01.mkv':si=2",
+		},
+		{
+			name:     "special characters",
+			burnIn:   &BurnInSubtitle{Index: 0, Path: "/anime/It's: a show/0.ass"},
+			expected: `subtitles=f='/anime/It'\\\''s\: a show/0.ass'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.burnIn.filter("/anime/Show - 01.mkv"))
+		})
+	}
+}
+
+func TestFileStreamBurnInRendition(t *testing.T) {
+	logger := util.NewLogger()
+	fs := &FileStream{
+		Path:   "/anime/Show - 01.mkv",
+		videos: result.NewResultMap[Quality, *VideoStream](),
+		audios: result.NewResultMap[int32, *AudioStream](),
+		logger: logger,
+		Info: &videofile.MediaInfo{
+			Video: &videofile.Video{
+				Quality: videofile.P1440,
+				Width:   2560,
+				Height:  1440,
+				Bitrate: 10_000_000,
+			},
+		},
+	}
+
+	master := fs.GetMaster(-1, nil)
+	require.NotContains(t, master, string(BurnIn))
+
+	// The burn-in rendition is capped at 1080p
+	master = fs.GetMaster(-1, &BurnInSubtitle{Index: 0})
+	require.Contains(t, master, "RESOLUTION=1920x1080,CODECS=\"avc1.640028\",AUDIO=\"audio\",CLOSED-CAPTIONS=NONE,NAME=\"Burned-in subtitles\"\n./burnin/index.m3u8\n")
+	require.Equal(t, 1, strings.Count(master, "burnin"))
+
+	quality, height := fs.getBurnInQuality()
+	require.Equal(t, P1080, quality)
+	require.Equal(t, int32(1080), height)
+
+	// Changing the track replaces the subtitle used by new streams
+	fs.GetMaster(-1, &BurnInSubtitle{Index: 1})
+	require.Equal(t, uint32(1), fs.getBurnIn().Index)
+
+	// The rendition cannot be requested once burn-in is turned off
+	fs.GetMaster(-1, nil)
+	_, err := fs.GetVideoIndex(BurnIn)
+	require.Error(t, err)
+}
@@ -2,6 +2,7 @@ package transcoder
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/rs/zerolog"
 	"math"
@@ -27,6 +28,8 @@ type FileStream struct {
 	audios    *result.Map[int32, *AudioStream]   // A map of audio streams.
 	logger    *zerolog.Logger
 	settings  *Settings
+	burnInMu  sync.RWMutex
+	burnIn    *BurnInSubtitle // The subtitle track rendered into the BurnIn rendition
 }
 
 // NewFileStream creates a new FileStream.
@@ -76,7 +79,11 @@ func (fs *FileStream) Destroy() {
 }
 
 // GetMaster generates the master playlist.
-func (fs *FileStream) GetMaster() string {
+// The audio track at defaultAudio is flagged as the default one, -1 keeps the default track of the file.
+// The BurnIn rendition is only included if burnIn is not nil.
+func (fs *FileStream) GetMaster(defaultAudio int, burnIn *BurnInSubtitle) string {
+	fs.setBurnIn(burnIn)
+
 	master := "#EXTM3U\n"
 	if fs.Info.Video != nil {
 		var transmuxQuality Quality
@@ -120,6 +127,20 @@ func (fs *FileStream) GetMaster() string {
 			}
 		}
 
+		if burnIn != nil {
+			quality, height := fs.getBurnInQuality()
+			master += "#EXT-X-STREAM-INF:"
+			master += fmt.Sprintf("AVERAGE-BANDWIDTH=%d,", quality.AverageBitrate())
+			master += fmt.Sprintf("BANDWIDTH=%d,", quality.MaxBitrate())
+			master += fmt.Sprintf("RESOLUTION=%dx%d,", int(aspectRatio*float32(height)+0.5), height)
+			master += fmt.Sprintf("CODECS=\"%s\",", transmuxCodec)
+			master += "AUDIO=\"audio\","
+			master += "CLOSED-CAPTIONS=NONE,"
+			// Not part of the spec, but used by hls.js as the name of the level
+			master += "NAME=\"Burned-in subtitles\"\n"
+			master += fmt.Sprintf("./%s/index.m3u8\n", BurnIn)
+		}
+
 		//for _, quality := range Qualities {
 		//	if quality.Height() < fs.Info.Video.Quality.Height() && quality.AverageBitrate() < fs.Info.Video.Bitrate {
 		//		master += "#EXT-X-STREAM-INF:"
@@ -146,7 +167,7 @@ func (fs *FileStream) GetMaster() string {
 		} else {
 			master += fmt.Sprintf("NAME=\"Audio %d\",", audio.Index)
 		}
-		if audio.IsDefault {
+		if (defaultAudio < 0 && audio.IsDefault) || int(audio.Index) == defaultAudio {
 			master += "DEFAULT=YES,"
 		}
 		master += "CHANNELS=\"2\","
@@ -155,8 +176,42 @@ func (fs *FileStream) GetMaster() string {
 	return master
 }
 
+// setBurnIn sets the subtitle track of the BurnIn rendition.
+// The current BurnIn stream is destroyed if the track changed.
+func (fs *FileStream) setBurnIn(burnIn *BurnInSubtitle) {
+	fs.burnInMu.Lock()
+	defer fs.burnInMu.Unlock()
+
+	if fs.burnIn == burnIn || (fs.burnIn != nil && burnIn != nil && *fs.burnIn == *burnIn) {
+		return
+	}
+	fs.burnIn = burnIn
+
+	if stream, found := fs.videos.Get(BurnIn); found {
+		fs.logger.Debug().Msg("filestream: Burned-in subtitle track changed, killing stream")
+		stream.Kill()
+		fs.videos.Delete(BurnIn)
+	}
+}
+
+// getBurnInQuality returns the quality used for the bitrates and the height of the BurnIn rendition.
+// It keeps the height of the source, up to burnInHeight.
+func (fs *FileStream) getBurnInQuality() (Quality, int32) {
+	height := closestMultiple(int32(min(fs.Info.Video.Height, burnInHeight)), 2)
+	return QualityFromHeight(uint32(height)), height
+}
+
+func (fs *FileStream) getBurnIn() *BurnInSubtitle {
+	fs.burnInMu.RLock()
+	defer fs.burnInMu.RUnlock()
+	return fs.burnIn
+}
+
 // GetVideoIndex gets the index of a video stream of a specific quality.
 func (fs *FileStream) GetVideoIndex(quality Quality) (string, error) {
+	if quality == BurnIn && fs.getBurnIn() == nil {
+		return "", errors.New("no subtitle track to burn in")
+	}
 	stream := fs.getVideoStream(quality)
 	return stream.GetIndex()
 }
@@ -179,6 +234,9 @@ func (fs *FileStream) getVideoStream(quality Quality) *VideoStream {
 // GetVideoSegment gets a segment of a video stream of a specific quality.
 func (fs *FileStream) GetVideoSegment(quality Quality, segment int32) (string, error) {
 	streamLogger.Debug().Msgf("filestream: Retrieving video segment %d (%s)", segment, quality)
+	if quality == BurnIn && fs.getBurnIn() == nil {
+		return "", errors.New("no subtitle track to burn in")
+	}
 	// Debug
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
@@ -6,6 +6,11 @@ import (
 	"github.com/goccy/go-json"
 )
 
+// cpuTonemapFilter scales the video then converts it from HDR (PQ/HLG, bt2020) to SDR (bt709) on the CPU.
+// The frames are converted to linear light before applying the hable curve, as recommended by the zscale/tonemap documentation.
+// This requires FFmpeg to be built with libzimg.
+const cpuTonemapFilter = "scale=%d:%d,zscale=t=linear:npl=100,format=gbrpf32le,zscale=p=bt709,tonemap=tonemap=hable:desat=0,zscale=t=bt709:m=bt709:r=tv,format=yuv420p"
+
 type (
 	HwAccelOptions struct {
 		Kind           string
@@ -61,6 +66,7 @@ func GetHardwareAccelSettings(opts HwAccelOptions) HwAccelSettings {
 			// we could put :force_original_aspect_ratio=decrease:force_divisible_by=2 here but we already calculate a correct width and
 			// aspect ratio in our code so there is no need.
 			ScaleFilter:   "scale=%d:%d",
+			TonemapFilter: cpuTonemapFilter,
 			WithForcedIdr: true,
 		}
 	case "vaapi":
@@ -89,7 +95,10 @@ func GetHardwareAccelSettings(opts HwAccelOptions) HwAccelSettings {
 			//   convert whatever to nv12 on GPU // scale_vaapi doesn't support passthrough option, so it has to make a copy
 			// }
 			// See https://www.reddit.com/r/ffmpeg/comments/1bqn60w/hardware_accelerated_decoding_without_hwdownload/ for more info
-			ScaleFilter:   "format=nv12|vaapi,hwupload,scale_vaapi=%d:%d:format=nv12",
+			ScaleFilter: "format=nv12|vaapi,hwupload,scale_vaapi=%d:%d:format=nv12",
+			// tonemap_vaapi needs 10bits frames, so software decoded frames are uploaded as p010 instead of nv12.
+			// it outputs nv12 (8bits) frames with bt709 colors which are then scaled like above.
+			TonemapFilter: "format=p010|vaapi,hwupload,tonemap_vaapi=format=nv12:p=bt709:t=bt709:m=bt709,scale_vaapi=%d:%d:format=nv12",
 			WithForcedIdr: true,
 		}
 	case "qsv", "intel":
@@ -137,7 +146,9 @@ func GetHardwareAccelSettings(opts HwAccelOptions) HwAccelSettings {
 				"-c:v", "h264_videotoolbox",
 				"-profile:v", "main",
 			},
-			ScaleFilter:   "scale=%d:%d",
+			ScaleFilter: "scale=%d:%d",
+			// frames are decoded to cpu memory (no hwaccel_output_format) so the cpu filter can be used
+			TonemapFilter: cpuTonemapFilter,
 			WithForcedIdr: true,
 		}
 	case "custom":
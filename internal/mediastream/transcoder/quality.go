@@ -16,6 +16,9 @@ const (
 	P4k      Quality = "4k"
 	P8k      Quality = "8k"
 	Original Quality = "original"
+	// BurnIn is the rendition with the selected subtitle track rendered into the video.
+	// Like Original, it is not part of Qualities and must be handled specially.
+	BurnIn Quality = "burnin"
 )
 
 // Qualities
@@ -26,6 +29,9 @@ func QualityFromString(str string) (Quality, error) {
 	if str == string(Original) {
 		return Original, nil
 	}
+	if str == string(BurnIn) {
+		return BurnIn, nil
+	}
 
 	qualities := Qualities
 	for _, quality := range qualities {
@@ -56,8 +62,8 @@ func (q Quality) AverageBitrate() uint32 {
 		return 16_000_000
 	case P8k:
 		return 28_000_000
-	case Original:
-		panic("Original quality must be handled specially")
+	case Original, BurnIn:
+		panic("Original and BurnIn qualities must be handled specially")
 	}
 	panic("Invalid quality value")
 }
@@ -80,8 +86,8 @@ func (q Quality) MaxBitrate() uint32 {
 		return 28_000_000
 	case P8k:
 		return 40_000_000
-	case Original:
-		panic("Original quality must be handled specially")
+	case Original, BurnIn:
+		panic("Original and BurnIn qualities must be handled specially")
 	}
 	panic("Invalid quality value")
 }
@@ -104,8 +110,8 @@ func (q Quality) Height() uint32 {
 		return 2160
 	case P8k:
 		return 4320
-	case Original:
-		panic("Original quality must be handled specially")
+	case Original, BurnIn:
+		panic("Original and BurnIn qualities must be handled specially")
 	}
 	panic("Invalid quality value")
 }
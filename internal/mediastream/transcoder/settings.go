@@ -11,9 +11,12 @@ func GetEnvOr(env string, def string) string {
 }
 
 type HwAccelSettings struct {
-	Name          string   `json:"name"`
-	DecodeFlags   []string `json:"decodeFlags"`
-	EncodeFlags   []string `json:"encodeFlags"`
-	ScaleFilter   string   `json:"scaleFilter"`
-	WithForcedIdr bool     `json:"removeForcedIdr"`
+	Name        string   `json:"name"`
+	DecodeFlags []string `json:"decodeFlags"`
+	EncodeFlags []string `json:"encodeFlags"`
+	ScaleFilter string   `json:"scaleFilter"`
+	// TonemapFilter replaces ScaleFilter for HDR sources when tone mapping is enabled.
+	// It converts the video to SDR before/while scaling it. Empty if the accelerator does not support tone mapping.
+	TonemapFilter string `json:"tonemapFilter"`
+	WithForcedIdr bool   `json:"removeForcedIdr"`
 }
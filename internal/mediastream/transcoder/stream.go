@@ -27,6 +27,7 @@ const (
 	AudioF   Flags = 1 << 0
 	VideoF   Flags = 1 << 1
 	Transmux Flags = 1 << 3
+	Software Flags = 1 << 4 // Decoded and encoded on the CPU regardless of the hardware acceleration settings
 )
 
 type StreamHandle interface {
@@ -421,7 +422,11 @@ func (ts *Stream) run(start int32) error {
 		"-nostats", "-hide_banner", "-loglevel", "warning",
 	}
 
-	args = append(args, ts.settings.HwAccel.DecodeFlags...)
+	hwAccel := ts.settings.HwAccel
+	if ts.handle.getFlags()&Software != 0 {
+		hwAccel = ts.settings.SoftwareAccel
+	}
+	args = append(args, hwAccel.DecodeFlags...)
 
 	if startRef != 0 {
 		if ts.handle.getFlags()&VideoF != 0 {
@@ -483,8 +488,8 @@ func (ts *Stream) run(start int32) error {
 
 	// Added logging for ffmpeg command and hardware transcoding state
 	streamLogger.Trace().Msgf("transcoder: ffmpeg command: %s %s", ts.settings.FfmpegPath, strings.Join(args, " "))
-	if len(ts.settings.HwAccel.DecodeFlags) > 0 {
-		streamLogger.Trace().Msgf("transcoder: Hardware transcoding enabled with flags: %v", ts.settings.HwAccel.DecodeFlags)
+	if len(hwAccel.DecodeFlags) > 0 {
+		streamLogger.Trace().Msgf("transcoder: Hardware transcoding enabled with flags: %v", hwAccel.DecodeFlags)
 	} else {
 		streamLogger.Trace().Msg("transcoder: Hardware transcoding not enabled")
 	}
@@ -596,7 +601,7 @@ func (ts *Stream) run(start int32) error {
 		err := cmd.Wait()
 		var exitErr *exec.ExitError
 		// Check if hardware acceleration was attempted and if stderr indicates a failure to use it
-		if len(ts.settings.HwAccel.DecodeFlags) > 0 {
+		if len(hwAccel.DecodeFlags) > 0 {
 			lowerOutput := strings.ToLower(stderr.String())
 			if strings.Contains(lowerOutput, "failed") &&
 				(strings.Contains(lowerOutput, "hwaccel") || strings.Contains(lowerOutput, "vaapi") || strings.Contains(lowerOutput, "cuvid") || strings.Contains(lowerOutput, "vdpau")) {
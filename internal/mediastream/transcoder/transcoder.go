@@ -1,6 +1,7 @@
 package transcoder
 
 import (
+	"cmp"
 	"fmt"
 	"os"
 	"path"
@@ -23,10 +24,14 @@ type (
 	}
 
 	Settings struct {
-		StreamDir   string
-		HwAccel     HwAccelSettings
-		FfmpegPath  string
-		FfprobePath string
+		StreamDir string
+		HwAccel   HwAccelSettings
+		// Used by the renditions that must be processed on the CPU (e.g. BurnIn)
+		SoftwareAccel HwAccelSettings
+		FfmpegPath    string
+		FfprobePath   string
+		// Convert HDR sources to SDR when transcoding
+		Tonemap bool
 	}
 
 	NewTranscoderOptions struct {
@@ -37,6 +42,7 @@ type (
 		FfmpegPath            string
 		FfprobePath           string
 		HwAccelCustomSettings string
+		Tonemap               bool
 	}
 )
 
@@ -66,8 +72,13 @@ func NewTranscoder(opts *NewTranscoderOptions) (*Transcoder, error) {
 				Preset:         opts.Preset,
 				CustomSettings: opts.HwAccelCustomSettings,
 			}),
+			SoftwareAccel: GetHardwareAccelSettings(HwAccelOptions{
+				Kind:   "cpu",
+				Preset: cmp.Or(opts.Preset, "fast"),
+			}),
 			FfmpegPath:  opts.FfmpegPath,
 			FfprobePath: opts.FfprobePath,
+			Tonemap:     opts.Tonemap,
 		},
 	}
 	ret.tracker = NewTracker(ret)
@@ -120,7 +131,9 @@ func (t *Transcoder) getFileStream(path string, hash string, mediaInfo *videofil
 	return ret, nil
 }
 
-func (t *Transcoder) GetMaster(path string, hash string, mediaInfo *videofile.MediaInfo, client string) (string, error) {
+// GetMaster returns the master playlist of the file.
+// If burnIn is not nil, the playlist includes a BurnIn rendition with that subtitle track rendered into the video.
+func (t *Transcoder) GetMaster(path string, hash string, mediaInfo *videofile.MediaInfo, defaultAudio int, burnIn *BurnInSubtitle, client string) (string, error) {
 	if debugStream {
 		start := time.Now()
 		t.logger.Trace().Msgf("transcoder: Retrieving master file")
@@ -137,7 +150,7 @@ func (t *Transcoder) GetMaster(path string, hash string, mediaInfo *videofile.Me
 		audio:   -1,
 		head:    -1,
 	}
-	return stream.GetMaster(), nil
+	return stream.GetMaster(defaultAudio, burnIn), nil
 }
 
 func (t *Transcoder) GetVideoIndex(
@@ -10,6 +10,7 @@ import (
 type VideoStream struct {
 	Stream
 	quality  Quality
+	burnIn   *BurnInSubtitle // Subtitle rendered into the video, only set for the BurnIn quality
 	logger   *zerolog.Logger
 	settings *Settings
 }
@@ -20,6 +21,9 @@ func NewVideoStream(file *FileStream, quality Quality, logger *zerolog.Logger, s
 	ret.quality = quality
 	ret.logger = logger
 	ret.settings = settings
+	if quality == BurnIn {
+		ret.burnIn = file.getBurnIn()
+	}
 	NewStream(fmt.Sprintf("video (%s)", quality), file, ret, &ret.Stream, settings, logger)
 	return ret
 }
@@ -28,6 +32,9 @@ func (vs *VideoStream) getFlags() Flags {
 	if vs.quality == Original {
 		return VideoF | Transmux
 	}
+	if vs.quality == BurnIn {
+		return VideoF | Software
+	}
 	return VideoF
 }
 
@@ -58,21 +65,27 @@ func (vs *VideoStream) getTranscodeArgs(segments string) []string {
 		return args
 	}
 
-	vs.logger.Debug().Interface("hwaccelArgs", vs.settings.HwAccel).Msg("videostream: Hardware Acceleration")
+	hwAccel := vs.settings.HwAccel
+	if vs.getFlags()&Software != 0 {
+		hwAccel = vs.settings.SoftwareAccel
+	}
+
+	vs.logger.Debug().Interface("hwaccelArgs", hwAccel).Msg("videostream: Hardware Acceleration")
 
-	args = append(args, vs.settings.HwAccel.EncodeFlags...)
-	width := int32(float64(vs.quality.Height()) / float64(vs.file.Info.Video.Height) * float64(vs.file.Info.Video.Width))
+	quality, height := vs.getOutputQuality()
+	args = append(args, hwAccel.EncodeFlags...)
+	width := int32(float64(height) / float64(vs.file.Info.Video.Height) * float64(vs.file.Info.Video.Width))
 	// force a width that is a multiple of two else some apps behave badly.
 	width = closestMultiple(width, 2)
 	args = append(args,
-		"-vf", fmt.Sprintf(vs.settings.HwAccel.ScaleFilter, width, vs.quality.Height()),
+		"-vf", vs.getVideoFilter(hwAccel, width, height),
 		// Even less sure but buf size are 5x the average bitrate since the average bitrate is only
 		// useful for hls segments.
-		"-bufsize", fmt.Sprint(vs.quality.MaxBitrate()*5),
-		"-b:v", fmt.Sprint(vs.quality.AverageBitrate()),
-		"-maxrate", fmt.Sprint(vs.quality.MaxBitrate()),
+		"-bufsize", fmt.Sprint(quality.MaxBitrate()*5),
+		"-b:v", fmt.Sprint(quality.AverageBitrate()),
+		"-maxrate", fmt.Sprint(quality.MaxBitrate()),
 	)
-	if vs.settings.HwAccel.WithForcedIdr {
+	if hwAccel.WithForcedIdr {
 		// Force segments to be split exactly on keyframes (only works when transcoding)
 		// forced-idr is needed to force keyframes to be an idr-frame (by default it can be any i frames)
 		// without this option, some hardware encoders uses others i-frames and the -f segment can't cut at them.
@@ -89,3 +102,31 @@ func (vs *VideoStream) getTranscodeArgs(segments string) []string {
 
 	return args
 }
+
+// getOutputQuality returns the quality used for the bitrates and the height of the output.
+func (vs *VideoStream) getOutputQuality() (Quality, int32) {
+	if vs.quality == BurnIn {
+		return vs.file.getBurnInQuality()
+	}
+	return vs.quality, int32(vs.quality.Height())
+}
+
+// getVideoFilter returns the filter scaling the video.
+// HDR sources are tone mapped if enabled and the subtitles are rendered for the BurnIn rendition.
+func (vs *VideoStream) getVideoFilter(hwAccel HwAccelSettings, width int32, height int32) string {
+	scaleFilter := hwAccel.ScaleFilter
+	if vs.settings.Tonemap && vs.file.Info.Video.IsHDR() {
+		if hwAccel.TonemapFilter != "" {
+			scaleFilter = hwAccel.TonemapFilter
+		} else {
+			vs.logger.Warn().Str("hwaccel", hwAccel.Name).Msg("videostream: Tone mapping is not supported by this hardware accelerator")
+		}
+	}
+
+	ret := fmt.Sprintf(scaleFilter, width, height)
+	if vs.burnIn != nil {
+		// Subtitles are rendered after scaling so that they are not blurred, the software filters keep the frames in memory
+		ret += "," + vs.burnIn.filter(vs.file.Path)
+	}
+	return ret
+}
@@ -0,0 +1,49 @@
+package mediastream
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"seanime/internal/mediastream/trickplay"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestTrickplay returns the state of the seek preview thumbnails of the file.
+// Generation is started if the thumbnails do not exist yet.
+// It returns nil if seek previews are disabled.
+func (r *Repository) RequestTrickplay(path string) (*trickplay.Info, error) {
+	if !r.IsInitialized() {
+		return nil, errors.New("module not initialized")
+	}
+
+	if !r.settings.MustGet().TrickplayEnabled {
+		return nil, nil
+	}
+
+	return r.trickplay.Request(path)
+}
+
+// ServeEchoTrickplay serves the WebVTT track and sprite sheets of a file.
+func (r *Repository) ServeEchoTrickplay(c echo.Context) error {
+	hash := c.Param("hash")
+	if hash == "" || strings.ContainsAny(hash, `/\.`) {
+		return errors.New("invalid hash")
+	}
+
+	dir, ok := r.trickplay.GetDir(hash)
+	if !ok {
+		return errors.New("module not initialized")
+	}
+
+	// Only files directly in the directory are served
+	filename := filepath.Base(c.Param("*"))
+
+	// Mark the video file cache as recently used so that it is not trimmed
+	now := time.Now()
+	_ = os.Chtimes(dir, now, now)
+
+	return c.File(filepath.Join(dir, filename))
+}
@@ -0,0 +1,86 @@
+package trickplay
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	thumbnailWidth = 320
+	sheetColumns   = 10
+	sheetRows      = 10
+	minInterval    = 10  // seconds
+	maxThumbnails  = 500 // Above this, the interval between thumbnails is increased
+	sheetFormat    = "sheet-%03d.jpg"
+	vttFilename    = "thumbnails.vtt"
+)
+
+// layout describes how the thumbnails of a file are laid out in the sprite sheets.
+type layout struct {
+	Interval float64 // Seconds between two thumbnails
+	Width    int
+	Height   int
+	Columns  int
+	Rows     int
+	Count    int // Number of thumbnails
+}
+
+func newLayout(duration float64, videoWidth uint32, videoHeight uint32) *layout {
+	interval := math.Max(minInterval, math.Ceil(duration/maxThumbnails))
+
+	height := 180
+	if videoWidth > 0 && videoHeight > 0 {
+		// Keep the aspect ratio, FFmpeg requires even dimensions
+		height = int(math.Round(float64(thumbnailWidth)*float64(videoHeight)/float64(videoWidth)/2)) * 2
+	}
+
+	return &layout{
+		Interval: interval,
+		Width:    thumbnailWidth,
+		Height:   max(height, 2),
+		Columns:  sheetColumns,
+		Rows:     sheetRows,
+		Count:    max(int(math.Ceil(duration/interval)), 1),
+	}
+}
+
+// filter returns the FFmpeg video filter generating the sprite sheets.
+func (l *layout) filter() string {
+	return fmt.Sprintf("fps=1/%g,scale=%d:%d,tile=%dx%d", l.Interval, l.Width, l.Height, l.Columns, l.Rows)
+}
+
+func (l *layout) thumbnailsPerSheet() int {
+	return l.Columns * l.Rows
+}
+
+// vtt returns the WebVTT thumbnail track referencing the sprite sheets.
+// Each cue points to a region of a sheet using a media fragment (#xywh=x,y,w,h).
+func (l *layout) vtt(duration float64) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+
+	for i := 0; i < l.Count; i++ {
+		start := float64(i) * l.Interval
+		end := math.Min(float64(i+1)*l.Interval, duration)
+		if end <= start {
+			break
+		}
+
+		sheet := i/l.thumbnailsPerSheet() + 1 // FFmpeg numbers the images from 1
+		pos := i % l.thumbnailsPerSheet()
+		x := (pos % l.Columns) * l.Width
+		y := (pos / l.Columns) * l.Height
+
+		b.WriteString("\n")
+		b.WriteString(formatVttTime(start) + " --> " + formatVttTime(end) + "\n")
+		b.WriteString(fmt.Sprintf(sheetFormat+"#xywh=%d,%d,%d,%d\n", sheet, x, y, l.Width, l.Height))
+	}
+
+	return b.String()
+}
+
+func formatVttTime(seconds float64) string {
+	ms := int64(math.Round(seconds * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3_600_000, ms/60_000%60, ms/1000%60, ms%1000)
+}
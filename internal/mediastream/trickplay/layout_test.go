@@ -0,0 +1,54 @@
+package trickplay
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLayout(t *testing.T) {
+	tests := []struct {
+		name             string
+		duration         float64
+		width, height    uint32
+		expectedInterval float64
+		expectedHeight   int
+		expectedCount    int
+	}{
+		{"episode 16:9", 1420, 1920, 1080, 10, 180, 142},
+		{"episode 4:3", 1440.5, 640, 480, 10, 240, 145},
+		{"movie", 7200, 1920, 800, 15, 134, 480},
+		{"unknown dimensions", 95, 0, 0, 10, 180, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLayout(tt.duration, tt.width, tt.height)
+			require.Equal(t, tt.expectedInterval, l.Interval)
+			require.Equal(t, thumbnailWidth, l.Width)
+			require.Equal(t, tt.expectedHeight, l.Height)
+			require.Equal(t, tt.expectedCount, l.Count)
+		})
+	}
+}
+
+func TestLayoutVtt(t *testing.T) {
+	l := newLayout(1005, 1920, 1080)
+	require.Equal(t, 101, l.Count)
+	require.Equal(t, "fps=1/10,scale=320:180,tile=10x10", l.filter())
+
+	vtt := l.vtt(1005)
+	require.True(t, strings.HasPrefix(vtt, "WEBVTT\n\n00:00:00.000 --> 00:00:10.000\nsheet-001.jpg#xywh=0,0,320,180\n"))
+	// 12th thumbnail, second row of the first sheet
+	require.Contains(t, vtt, "00:01:50.000 --> 00:02:00.000\nsheet-001.jpg#xywh=320,180,320,180\n")
+	// Last thumbnail, first one of the second sheet, ends with the file
+	require.True(t, strings.HasSuffix(vtt, "00:16:40.000 --> 00:16:45.000\nsheet-002.jpg#xywh=0,0,320,180\n"))
+	require.Equal(t, 101, strings.Count(vtt, "-->"))
+}
+
+func TestFormatVttTime(t *testing.T) {
+	require.Equal(t, "00:00:00.000", formatVttTime(0))
+	require.Equal(t, "00:01:05.250", formatVttTime(65.25))
+	require.Equal(t, "02:00:00.000", formatVttTime(7200))
+}
@@ -0,0 +1,279 @@
+package trickplay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"seanime/internal/events"
+	"seanime/internal/mediastream/videofile"
+	"seanime/internal/util"
+	"seanime/internal/util/filecache"
+	"seanime/internal/util/result"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type (
+	Status string
+
+	// Info is the state of the seek preview thumbnails of a file.
+	Info struct {
+		Hash   string `json:"hash"`
+		Path   string `json:"path"`
+		Status Status `json:"status"`
+	}
+
+	// Generator generates seek preview thumbnails (WebVTT thumbnail track + JPEG sprite sheets) for local files.
+	// Files are processed one at a time in the background.
+	// The thumbnails are stored in the mediastream video file cache and are subject to filecache.Cacher.TrimMediastreamVideoFiles.
+	Generator struct {
+		logger             *zerolog.Logger
+		wsEventManager     events.WSEventManagerInterface
+		mediaInfoExtractor *videofile.MediaInfoExtractor
+		settings           *Settings
+		settingsMu         sync.RWMutex
+		queueMu            sync.Mutex
+		queue              []*Info
+		infos              *result.Map[string, *Info] // Hash -> Info of the files that are queued, being processed or that failed
+		wakeCh             chan struct{}
+		startOnce          sync.Once
+	}
+
+	Settings struct {
+		FfmpegPath  string
+		FfprobePath string
+		CacheDir    string
+	}
+
+	NewGeneratorOptions struct {
+		Logger         *zerolog.Logger
+		WSEventManager events.WSEventManagerInterface
+		FileCacher     *filecache.Cacher
+	}
+)
+
+const (
+	StatusQueued     Status = "queued"
+	StatusGenerating Status = "generating"
+	StatusReady      Status = "ready"
+	StatusFailed     Status = "failed"
+)
+
+const generationTimeout = 30 * time.Minute
+
+func NewGenerator(opts *NewGeneratorOptions) *Generator {
+	return &Generator{
+		logger:             opts.Logger,
+		wsEventManager:     opts.WSEventManager,
+		mediaInfoExtractor: videofile.NewMediaInfoExtractor(opts.FileCacher, opts.Logger),
+		queue:              make([]*Info, 0),
+		infos:              result.NewResultMap[string, *Info](),
+		wakeCh:             make(chan struct{}, 1),
+	}
+}
+
+func (g *Generator) SetSettings(settings *Settings) {
+	g.settingsMu.Lock()
+	defer g.settingsMu.Unlock()
+	g.settings = settings
+}
+
+func (g *Generator) getSettings() (*Settings, bool) {
+	g.settingsMu.RLock()
+	defer g.settingsMu.RUnlock()
+	return g.settings, g.settings != nil && g.settings.CacheDir != ""
+}
+
+// GetDir returns the directory containing the thumbnails of the file.
+func (g *Generator) GetDir(hash string) (string, bool) {
+	settings, ok := g.getSettings()
+	if !ok {
+		return "", false
+	}
+	return videofile.GetFileTrickplayCacheDir(settings.CacheDir, hash), true
+}
+
+// Request returns the state of the thumbnails of the file.
+// If they have not been generated, the file is put at the front of the queue.
+func (g *Generator) Request(path string) (*Info, error) {
+	hash, err := videofile.GetHashFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.isReady(hash) {
+		return &Info{Hash: hash, Path: path, Status: StatusReady}, nil
+	}
+
+	if info, found := g.infos.Get(hash); found {
+		if info.Status == StatusQueued {
+			g.moveToFront(hash)
+		}
+		return info, nil
+	}
+
+	return g.enqueue(path, hash), nil
+}
+
+// enqueue puts the file at the front of the queue, the file played last is generated first.
+func (g *Generator) enqueue(path string, hash string) *Info {
+	info := &Info{Hash: hash, Path: path, Status: StatusQueued}
+	g.infos.Set(hash, info)
+
+	g.queueMu.Lock()
+	g.queue = append([]*Info{info}, g.queue...)
+	g.queueMu.Unlock()
+
+	g.startOnce.Do(func() {
+		go g.work()
+	})
+
+	select {
+	case g.wakeCh <- struct{}{}:
+	default:
+	}
+
+	return info
+}
+
+func (g *Generator) moveToFront(hash string) {
+	g.queueMu.Lock()
+	defer g.queueMu.Unlock()
+	for i, info := range g.queue {
+		if info.Hash == hash {
+			g.queue = append(append([]*Info{info}, g.queue[:i]...), g.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+func (g *Generator) isReady(hash string) bool {
+	dir, ok := g.GetDir(hash)
+	if !ok {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(dir, vttFilename))
+	return err == nil
+}
+
+func (g *Generator) work() {
+	defer util.HandlePanicInModuleThen("mediastream/trickplay/work", func() {})
+
+	for {
+		g.queueMu.Lock()
+		if len(g.queue) == 0 {
+			g.queueMu.Unlock()
+			<-g.wakeCh
+			continue
+		}
+		info := g.queue[0]
+		g.queue = g.queue[1:]
+		g.queueMu.Unlock()
+
+		g.setStatus(info, StatusGenerating)
+
+		if err := g.generate(info); err != nil {
+			g.logger.Error().Err(err).Str("path", info.Path).Msg("trickplay: Failed to generate thumbnails")
+			g.setStatus(info, StatusFailed)
+			continue
+		}
+
+		g.setStatus(info, StatusReady)
+	}
+}
+
+func (g *Generator) setStatus(info *Info, status Status) {
+	updated := &Info{
+		Hash:   info.Hash,
+		Path:   info.Path,
+		Status: status,
+	}
+	if status == StatusReady {
+		g.infos.Delete(info.Hash)
+	} else {
+		g.infos.Set(info.Hash, updated)
+	}
+	g.wsEventManager.SendEvent(events.MediastreamTrickplayUpdated, updated)
+}
+
+// generate extracts the thumbnails of the file into sprite sheets and writes the WebVTT track.
+// The files are written to a temporary directory that is renamed once everything is done.
+func (g *Generator) generate(info *Info) error {
+	settings, ok := g.getSettings()
+	if !ok {
+		return errors.New("module not initialized")
+	}
+
+	mediaInfo, err := g.mediaInfoExtractor.GetInfo(settings.FfprobePath, info.Path)
+	if err != nil {
+		return err
+	}
+	if mediaInfo.Video == nil || mediaInfo.Duration <= 0 {
+		return errors.New("file has no video stream")
+	}
+
+	g.logger.Debug().Str("path", info.Path).Msg("trickplay: Generating thumbnails")
+	start := time.Now()
+
+	duration := float64(mediaInfo.Duration)
+	l := newLayout(duration, mediaInfo.Video.Width, mediaInfo.Video.Height)
+
+	dir := videofile.GetFileTrickplayCacheDir(settings.CacheDir, info.Hash)
+	tmpDir := dir + ".tmp"
+	_ = os.RemoveAll(tmpDir)
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), generationTimeout)
+	defer cancel()
+
+	cmd := util.NewCmdCtx(ctx, settings.FfmpegPath,
+		"-hide_banner",
+		"-loglevel", "error",
+		// Only decode keyframes, this is much faster and precise enough for previews
+		"-skip_frame", "nokey",
+		"-i", info.Path,
+		"-map", "0:V:0",
+		"-an", "-sn", "-dn",
+		"-vf", l.filter(),
+		"-q:v", "5",
+		"-f", "image2",
+		filepath.Join(tmpDir, sheetFormat),
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	// Only reference the thumbnails that were extracted
+	sheets, _ := filepath.Glob(filepath.Join(tmpDir, "sheet-*.jpg"))
+	if len(sheets) == 0 {
+		return errors.New("no thumbnails were extracted")
+	}
+	l.Count = min(l.Count, len(sheets)*l.thumbnailsPerSheet())
+
+	if err := os.WriteFile(filepath.Join(tmpDir, vttFilename), []byte(l.vtt(duration)), 0644); err != nil {
+		return err
+	}
+
+	_ = os.RemoveAll(dir)
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return err
+	}
+
+	g.logger.Debug().
+		Str("path", info.Path).
+		Int("count", l.Count).
+		Dur("took", time.Since(start)).
+		Msg("trickplay: Thumbnails generated")
+
+	return nil
+}
@@ -18,6 +18,10 @@ func GetFileAttCacheDir(outDir string, hash string) string {
 	return filepath.Join(outDir, "videofiles", hash, "/att")
 }
 
+func GetFileTrickplayCacheDir(outDir string, hash string) string {
+	return filepath.Join(outDir, "videofiles", hash, "/trickplay")
+}
+
 func ExtractAttachment(ffmpegPath string, path string, hash string, mediaInfo *MediaInfo, cacheDir string, logger *zerolog.Logger) (err error) {
 	logger.Debug().Str("hash", hash).Msgf("videofile: Starting media attachment extraction")
 
@@ -41,6 +41,9 @@ type MediaInfo struct {
 	Audios []Audio `json:"audios"`
 	// The list of subtitles tracks
 	Subtitles []Subtitle `json:"subtitles"`
+	// The subtitle tracks that cannot be extracted, e.g. image-based subtitles.
+	// They are not in Subtitles but media players still list them.
+	UnsupportedSubtitles []Subtitle `json:"unsupportedSubtitles"`
 	// The list of fonts that can be used to display subtitles
 	Fonts []string `json:"fonts"`
 	// The list of chapters. See Chapter for more information
@@ -62,6 +65,21 @@ type Video struct {
 	Height uint32 `json:"height"`
 	// The average bitrate of the video in bytes/s
 	Bitrate uint32 `json:"bitrate"`
+	// The pixel format of the video stream (e.g. yuv420p10le)
+	PixFmt string `json:"pixFmt"`
+	// The transfer characteristics of the video stream (e.g. smpte2084 for HDR10)
+	ColorTransfer string `json:"colorTransfer"`
+	// The color primaries of the video stream (e.g. bt2020)
+	ColorPrimaries string `json:"colorPrimaries"`
+}
+
+// IsHDR returns true if the video uses a HDR transfer function (PQ or HLG).
+func (v *Video) IsHDR() bool {
+	switch v.ColorTransfer {
+	case "smpte2084", "arib-std-b67":
+		return true
+	}
+	return false
 }
 
 type Audio struct {
@@ -134,7 +152,9 @@ func (e *MediaInfoExtractor) GetInfo(ffprobePath, path string) (mi *MediaInfo, e
 
 	e.logger.Debug().Str("path", path).Str("hash", hash).Msg("mediastream: Getting media information [MediaInfoExtractor]")
 
-	bucketName := fmt.Sprintf("mediastream_mediainfo_%s", hash)
+	// The version is bumped when fields are added, e.g. the color information used for tone mapping,
+	// so that the files probed before are probed again
+	bucketName := fmt.Sprintf("mediastream_mediainfo_v2_%s", hash)
 	bucket := filecache.NewBucket(bucketName, 24*7*52*time.Hour)
 	e.logger.Trace().Str("bucketName", bucketName).Msg("mediastream: Using cache bucket [MediaInfoExtractor]")
 
@@ -205,7 +225,10 @@ func FfprobeGetInfo(ffprobePath, path, hash string) (*MediaInfo, error) {
 			Height:    uint32(stream.Height),
 			// ffmpeg does not report bitrate in mkv files, fallback to bitrate of the whole container
 			// (bigger than the result since it contains audio and other videos but better than nothing).
-			Bitrate: uint32(bitrate),
+			Bitrate:        uint32(bitrate),
+			PixFmt:         stream.PixFmt,
+			ColorTransfer:  stream.ColorTransfer,
+			ColorPrimaries: stream.ColorPrimaries,
 		}
 	})
 
@@ -251,7 +274,7 @@ func FfprobeGetInfo(ffprobePath, path, hash string) (*MediaInfo, error) {
 	})
 
 	// Remove subtitles without extensions (not supported)
-	mi.Subtitles = lo.Filter(mi.Subtitles, func(item Subtitle, _ int) bool {
+	mi.Subtitles, mi.UnsupportedSubtitles = lo.FilterReject(mi.Subtitles, func(item Subtitle, _ int) bool {
 		if item.Extension == nil || *item.Extension == "" || item.Link == nil {
 			return false
 		}
@@ -0,0 +1,138 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"seanime/internal/database/models"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	ChannelTypeWebhook = "webhook"
+	ChannelTypeNtfy    = "ntfy"
+	ChannelTypeGotify  = "gotify"
+	ChannelTypeDiscord = "discord"
+	ChannelTypeSMTP    = "smtp"
+)
+
+type (
+	// Message is a rendered notification.
+	Message struct {
+		Event     Event                  `json:"event"`
+		Title     string                 `json:"title"`
+		Message   string                 `json:"message"`
+		Data      map[string]interface{} `json:"data"`
+		Timestamp time.Time              `json:"timestamp"`
+	}
+
+	// Channel sends messages to an external service.
+	Channel interface {
+		Send(ctx context.Context, msg *Message) error
+	}
+)
+
+// NewChannel returns the channel implementation matching the type of the channel.
+func NewChannel(channel *models.NotificationChannel, client *http.Client) (Channel, error) {
+	config := channel.Config
+	switch channel.Type {
+	case ChannelTypeWebhook:
+		return &webhookChannel{config: config, client: client}, nil
+	case ChannelTypeNtfy:
+		return &ntfyChannel{config: config, client: client}, nil
+	case ChannelTypeGotify:
+		return &gotifyChannel{config: config, client: client}, nil
+	case ChannelTypeDiscord:
+		return &discordChannel{config: config, client: client}, nil
+	case ChannelTypeSMTP:
+		return &smtpChannel{config: config}, nil
+	}
+	return nil, fmt.Errorf("notifier: unknown channel type %q", channel.Type)
+}
+
+// ValidateChannel checks that the options required by the channel type are set.
+func ValidateChannel(channel *models.NotificationChannel) error {
+	if channel == nil {
+		return fmt.Errorf("notifier: channel is required")
+	}
+
+	config := channel.Config
+	switch channel.Type {
+	case ChannelTypeWebhook, ChannelTypeDiscord, ChannelTypeGotify:
+		if config.URL == "" {
+			return fmt.Errorf("notifier: url is required")
+		}
+		if channel.Type == ChannelTypeGotify && config.Token == "" {
+			return fmt.Errorf("notifier: token is required")
+		}
+	case ChannelTypeNtfy:
+		if config.Topic == "" {
+			return fmt.Errorf("notifier: topic is required")
+		}
+	case ChannelTypeSMTP:
+		if config.Host == "" || config.From == "" || len(config.To) == 0 {
+			return fmt.Errorf("notifier: host, sender and recipients are required")
+		}
+	default:
+		return fmt.Errorf("notifier: unknown channel type %q", channel.Type)
+	}
+
+	for _, event := range channel.Events {
+		if _, found := getEventInfo(Event(event)); !found {
+			return fmt.Errorf("notifier: unknown event %q", event)
+		}
+	}
+	for event, tmpl := range channel.Templates {
+		if _, found := getEventInfo(Event(event)); !found {
+			return fmt.Errorf("notifier: unknown event %q", event)
+		}
+		if tmpl == nil {
+			continue
+		}
+		if _, err := parseTemplate(tmpl.Title); err != nil {
+			return fmt.Errorf("notifier: invalid title template for %s: %w", event, err)
+		}
+		if _, err := parseTemplate(tmpl.Message); err != nil {
+			return fmt.Errorf("notifier: invalid message template for %s: %w", event, err)
+		}
+	}
+
+	return nil
+}
+
+// postJSON sends a JSON request and returns an error if the response status isn't 2xx.
+func postJSON(ctx context.Context, client *http.Client, url string, body interface{}, headers map[string]string) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	return doRequest(client, req)
+}
+
+func doRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notifier: unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return nil
+}
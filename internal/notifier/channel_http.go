@@ -0,0 +1,102 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"seanime/internal/database/models"
+	"strconv"
+	"strings"
+)
+
+const defaultNtfyServer = "https://ntfy.sh"
+
+// webhookChannel posts the message as JSON to a URL.
+type webhookChannel struct {
+	config models.NotificationChannelConfig
+	client *http.Client
+}
+
+func (c *webhookChannel) Send(ctx context.Context, msg *Message) error {
+	return postJSON(ctx, c.client, c.config.URL, msg, c.config.Headers)
+}
+
+// ntfyChannel publishes the message to an ntfy topic.
+// https://docs.ntfy.sh/publish/
+type ntfyChannel struct {
+	config models.NotificationChannelConfig
+	client *http.Client
+}
+
+func (c *ntfyChannel) Send(ctx context.Context, msg *Message) error {
+	server := strings.TrimSuffix(c.config.URL, "/")
+	if server == "" {
+		server = defaultNtfyServer
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server+"/"+url.PathEscape(c.config.Topic), strings.NewReader(msg.Message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", msg.Title)
+	req.Header.Set("Tags", string(msg.Event))
+	if c.config.Priority > 0 {
+		req.Header.Set("Priority", strconv.Itoa(c.config.Priority))
+	}
+	if c.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+
+	return doRequest(c.client, req)
+}
+
+// gotifyChannel sends the message to a Gotify server.
+// https://gotify.net/docs/pushmsg
+type gotifyChannel struct {
+	config models.NotificationChannelConfig
+	client *http.Client
+}
+
+func (c *gotifyChannel) Send(ctx context.Context, msg *Message) error {
+	body := map[string]interface{}{
+		"title":   msg.Title,
+		"message": msg.Message,
+	}
+	if c.config.Priority > 0 {
+		body["priority"] = c.config.Priority
+	}
+
+	return postJSON(ctx, c.client, strings.TrimSuffix(c.config.URL, "/")+"/message", body, map[string]string{
+		"X-Gotify-Key": c.config.Token,
+	})
+}
+
+// discordChannel sends the message as an embed to a Discord webhook.
+// https://discord.com/developers/docs/resources/webhook#execute-webhook
+type discordChannel struct {
+	config models.NotificationChannelConfig
+	client *http.Client
+}
+
+const discordEmbedColor = 0x6152D8
+
+func (c *discordChannel) Send(ctx context.Context, msg *Message) error {
+	color := discordEmbedColor
+	if msg.Event == EventError {
+		color = 0xE5484D
+	}
+
+	body := map[string]interface{}{
+		"username": "Seanime",
+		"embeds": []map[string]interface{}{
+			{
+				"title":       msg.Title,
+				"description": msg.Message,
+				"color":       color,
+				"timestamp":   msg.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+			},
+		},
+	}
+
+	return postJSON(ctx, c.client, c.config.URL, body, nil)
+}
@@ -0,0 +1,218 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"seanime/internal/database/db"
+	"seanime/internal/database/models"
+	"seanime/internal/util"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/samber/mo"
+)
+
+const sendTimeout = 30 * time.Second
+
+type (
+	// Dispatcher sends events to the notification channels the user configured.
+	Dispatcher struct {
+		database mo.Option[*db.Database]
+		logger   mo.Option[*zerolog.Logger]
+		client   *http.Client
+		channels []*models.NotificationChannel
+		mu       sync.RWMutex
+	}
+)
+
+var GlobalDispatcher = NewDispatcher()
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		database: mo.None[*db.Database](),
+		logger:   mo.None[*zerolog.Logger](),
+		client:   &http.Client{Timeout: sendTimeout},
+		channels: make([]*models.NotificationChannel, 0),
+	}
+}
+
+// SetDatabase loads the channels from the database.
+func (d *Dispatcher) SetDatabase(database *db.Database, logger *zerolog.Logger) {
+	d.mu.Lock()
+	d.database = mo.Some(database)
+	d.logger = mo.Some(logger)
+	d.mu.Unlock()
+
+	if err := d.Reload(); err != nil {
+		logger.Error().Err(err).Msg("notifier: Failed to load notification channels")
+	}
+}
+
+// Reload reloads the channels from the database.
+// It should be called after the channels are modified.
+func (d *Dispatcher) Reload() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	database, ok := d.database.Get()
+	if !ok {
+		return nil
+	}
+
+	channels, err := database.GetNotificationChannels()
+	if err != nil {
+		return err
+	}
+	d.channels = channels
+	return nil
+}
+
+// Dispatch renders the event for each enabled channel routed to it and sends it.
+// This is run in a goroutine.
+func (d *Dispatcher) Dispatch(event Event, data map[string]interface{}) {
+	if d == nil {
+		return
+	}
+
+	d.mu.RLock()
+	channels := make([]*models.NotificationChannel, 0, len(d.channels))
+	for _, channel := range d.channels {
+		if channel.Enabled && isRouted(channel, event) {
+			channels = append(channels, channel)
+		}
+	}
+	d.mu.RUnlock()
+
+	if len(channels) == 0 {
+		return
+	}
+
+	timestamp := time.Now()
+	for _, channel := range channels {
+		go func(channel *models.NotificationChannel) {
+			defer util.HandlePanicInModuleThen("notifier/Dispatch", func() {})
+
+			ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			defer cancel()
+
+			if err := d.send(ctx, channel, event, data, timestamp); err != nil {
+				d.log(func(l *zerolog.Logger) {
+					l.Warn().Err(err).Str("channel", channel.Name).Str("event", string(event)).Msg("notifier: Failed to send notification")
+				})
+				return
+			}
+			d.log(func(l *zerolog.Logger) {
+				l.Trace().Str("channel", channel.Name).Str("event", string(event)).Msg("notifier: Sent notification")
+			})
+		}(channel)
+	}
+}
+
+// SendTest sends a test notification to a channel, the channel doesn't need to be saved.
+func (d *Dispatcher) SendTest(channel *models.NotificationChannel) error {
+	if err := ValidateChannel(channel); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	c, err := NewChannel(channel, d.client)
+	if err != nil {
+		return err
+	}
+
+	return c.Send(ctx, &Message{
+		Event:     "test",
+		Title:     "Test notification",
+		Message:   "This is a test notification from Seanime.",
+		Data:      map[string]interface{}{},
+		Timestamp: time.Now(),
+	})
+}
+
+func (d *Dispatcher) send(ctx context.Context, channel *models.NotificationChannel, event Event, data map[string]interface{}, timestamp time.Time) error {
+	msg, err := RenderMessage(channel, event, data)
+	if err != nil {
+		return err
+	}
+	msg.Timestamp = timestamp
+
+	c, err := NewChannel(channel, d.client)
+	if err != nil {
+		return err
+	}
+
+	return c.Send(ctx, msg)
+}
+
+func (d *Dispatcher) log(f func(l *zerolog.Logger)) {
+	if logger, ok := d.logger.Get(); ok {
+		f(logger)
+	}
+}
+
+// isRouted returns true if the channel receives the event.
+func isRouted(channel *models.NotificationChannel, event Event) bool {
+	return len(channel.Events) == 0 || slices.Contains(channel.Events, string(event))
+}
+
+// RenderMessage executes the templates of the channel, or the default templates, with the data of the event.
+func RenderMessage(channel *models.NotificationChannel, event Event, data map[string]interface{}) (*Message, error) {
+	info, found := getEventInfo(event)
+	if !found {
+		return nil, fmt.Errorf("notifier: unknown event %q", event)
+	}
+
+	titleTmpl, messageTmpl := info.DefaultTitle, info.DefaultTemplate
+	if channel != nil {
+		if tmpl, ok := channel.Templates[string(event)]; ok && tmpl != nil {
+			if tmpl.Title != "" {
+				titleTmpl = tmpl.Title
+			}
+			if tmpl.Message != "" {
+				messageTmpl = tmpl.Message
+			}
+		}
+	}
+
+	title, err := executeTemplate(titleTmpl, data)
+	if err != nil {
+		return nil, err
+	}
+	message, err := executeTemplate(messageTmpl, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		Event: event,
+		// Titles are used in headers
+		Title:   strings.Join(strings.Fields(title), " "),
+		Message: message,
+		Data:    data,
+	}, nil
+}
+
+func parseTemplate(value string) (*template.Template, error) {
+	return template.New("").Option("missingkey=zero").Parse(value)
+}
+
+func executeTemplate(value string, data map[string]interface{}) (string, error) {
+	tmpl, err := parseTemplate(value)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", errors.New("notifier: failed to render template: " + err.Error())
+	}
+	return strings.TrimSpace(b.String()), nil
+}
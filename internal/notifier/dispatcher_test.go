@@ -0,0 +1,206 @@
+package notifier
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"seanime/internal/database/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedRequest struct {
+	path    string
+	headers http.Header
+	body    string
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, chan *receivedRequest) {
+	ch := make(chan *receivedRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ch <- &receivedRequest{path: r.URL.Path, headers: r.Header, body: string(body)}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, ch
+}
+
+func receive(t *testing.T, ch chan *receivedRequest) *receivedRequest {
+	select {
+	case req := <-ch:
+		return req
+	case <-time.After(2 * time.Second):
+		t.Fatal("no request received")
+		return nil
+	}
+}
+
+func TestRenderMessage(t *testing.T) {
+	msg, err := RenderMessage(nil, EventAutoDownloadFinished, map[string]interface{}{"count": 1, "queued": true})
+	require.NoError(t, err)
+	assert.Equal(t, "Auto Downloader", msg.Title)
+	assert.Equal(t, "1 episode has been added to the queue.", msg.Message)
+
+	msg, err = RenderMessage(nil, EventAutoDownloadFinished, map[string]interface{}{"count": 3, "queued": false})
+	require.NoError(t, err)
+	assert.Equal(t, "3 episodes have been downloaded.", msg.Message)
+
+	channel := &models.NotificationChannel{
+		Templates: models.NotificationTemplates{
+			string(EventEpisodeAired): {Title: "{{.media}}\nE{{.episode}}", Message: "Episode {{.episode}} is out!"},
+		},
+	}
+	msg, err = RenderMessage(channel, EventEpisodeAired, map[string]interface{}{"media": "Frieren", "episode": 12})
+	require.NoError(t, err)
+	// Titles are kept on one line
+	assert.Equal(t, "Frieren E12", msg.Title)
+	assert.Equal(t, "Episode 12 is out!", msg.Message)
+
+	_, err = RenderMessage(nil, "unknown", nil)
+	assert.Error(t, err)
+}
+
+func TestValidateChannel(t *testing.T) {
+	assert.NoError(t, ValidateChannel(&models.NotificationChannel{Type: ChannelTypeNtfy, Config: models.NotificationChannelConfig{Topic: "seanime"}}))
+	assert.Error(t, ValidateChannel(&models.NotificationChannel{Type: ChannelTypeNtfy}))
+	assert.Error(t, ValidateChannel(&models.NotificationChannel{Type: ChannelTypeGotify, Config: models.NotificationChannelConfig{URL: "http://gotify"}}))
+	assert.Error(t, ValidateChannel(&models.NotificationChannel{Type: "pigeon"}))
+	assert.Error(t, ValidateChannel(&models.NotificationChannel{
+		Type:   ChannelTypeDiscord,
+		Config: models.NotificationChannelConfig{URL: "http://discord"},
+		Events: models.NotificationEvents{"unknown"},
+	}))
+	assert.Error(t, ValidateChannel(&models.NotificationChannel{
+		Type:      ChannelTypeDiscord,
+		Config:    models.NotificationChannelConfig{URL: "http://discord"},
+		Templates: models.NotificationTemplates{string(EventError): {Message: "{{.error"}},
+	}))
+}
+
+func TestDispatcher_Routing(t *testing.T) {
+	server, ch := newTestServer(t)
+
+	d := NewDispatcher()
+	d.channels = []*models.NotificationChannel{
+		{
+			Name:    "all",
+			Type:    ChannelTypeWebhook,
+			Enabled: true,
+			Config:  models.NotificationChannelConfig{URL: server.URL + "/all", Headers: map[string]string{"X-Token": "secret"}},
+		},
+		{
+			Name:    "errors",
+			Type:    ChannelTypeWebhook,
+			Enabled: true,
+			Config:  models.NotificationChannelConfig{URL: server.URL + "/errors"},
+			Events:  models.NotificationEvents{string(EventError)},
+		},
+		{
+			Name:    "disabled",
+			Type:    ChannelTypeWebhook,
+			Enabled: false,
+			Config:  models.NotificationChannelConfig{URL: server.URL + "/disabled"},
+		},
+	}
+
+	d.Dispatch(EventUpdateAvailable, map[string]interface{}{"version": "2.0.0", "currentVersion": "1.0.0"})
+	req := receive(t, ch)
+	assert.Equal(t, "/all", req.path)
+	assert.Equal(t, "secret", req.headers.Get("X-Token"))
+
+	var msg Message
+	require.NoError(t, json.Unmarshal([]byte(req.body), &msg))
+	assert.Equal(t, EventUpdateAvailable, msg.Event)
+	assert.Equal(t, "Seanime 2.0.0 is available, you are using 1.0.0.", msg.Message)
+
+	d.Dispatch(EventError, map[string]interface{}{"source": "Debrid", "error": "timeout"})
+	paths := []string{receive(t, ch).path, receive(t, ch).path}
+	assert.ElementsMatch(t, []string{"/all", "/errors"}, paths)
+
+	select {
+	case req := <-ch:
+		t.Fatalf("unexpected request to %s", req.path)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestChannels(t *testing.T) {
+	server, ch := newTestServer(t)
+
+	msg := &Message{Event: EventEpisodeAired, Title: "New episode aired", Message: "Frieren episode 12 has aired.", Timestamp: time.Now()}
+
+	t.Run("ntfy", func(t *testing.T) {
+		c, err := NewChannel(&models.NotificationChannel{Type: ChannelTypeNtfy, Config: models.NotificationChannelConfig{
+			URL: server.URL, Topic: "anime", Token: "tk", Priority: 4,
+		}}, http.DefaultClient)
+		require.NoError(t, err)
+		require.NoError(t, c.Send(context.Background(), msg))
+
+		req := receive(t, ch)
+		assert.Equal(t, "/anime", req.path)
+		assert.Equal(t, "New episode aired", req.headers.Get("Title"))
+		assert.Equal(t, "4", req.headers.Get("Priority"))
+		assert.Equal(t, "Bearer tk", req.headers.Get("Authorization"))
+		assert.Equal(t, msg.Message, req.body)
+	})
+
+	t.Run("gotify", func(t *testing.T) {
+		c, err := NewChannel(&models.NotificationChannel{Type: ChannelTypeGotify, Config: models.NotificationChannelConfig{
+			URL: server.URL + "/", Token: "app",
+		}}, http.DefaultClient)
+		require.NoError(t, err)
+		require.NoError(t, c.Send(context.Background(), msg))
+
+		req := receive(t, ch)
+		assert.Equal(t, "/message", req.path)
+		assert.Equal(t, "app", req.headers.Get("X-Gotify-Key"))
+		assert.Contains(t, req.body, `"title":"New episode aired"`)
+	})
+
+	t.Run("discord", func(t *testing.T) {
+		c, err := NewChannel(&models.NotificationChannel{Type: ChannelTypeDiscord, Config: models.NotificationChannelConfig{
+			URL: server.URL + "/webhook",
+		}}, http.DefaultClient)
+		require.NoError(t, err)
+		require.NoError(t, c.Send(context.Background(), msg))
+
+		req := receive(t, ch)
+		assert.Equal(t, "/webhook", req.path)
+		assert.Contains(t, req.body, `"description":"Frieren episode 12 has aired."`)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+		}))
+		defer failing.Close()
+
+		c, err := NewChannel(&models.NotificationChannel{Type: ChannelTypeWebhook, Config: models.NotificationChannelConfig{
+			URL: failing.URL,
+		}}, http.DefaultClient)
+		require.NoError(t, err)
+		err = c.Send(context.Background(), msg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "401")
+	})
+}
+
+func TestBuildEmail(t *testing.T) {
+	email := string(buildEmail("seanime@example.com", []string{"a@example.com", "b@example.com"}, &Message{
+		Title:     "Update\r\nBcc: x@example.com",
+		Message:   "Line 1\nLine 2",
+		Timestamp: time.Now(),
+	}))
+
+	assert.Contains(t, email, "To: a@example.com, b@example.com\r\n")
+	// Header injection is prevented by the encoding of the subject
+	assert.NotContains(t, email, "\r\nBcc:")
+	assert.True(t, strings.HasSuffix(email, "\r\n\r\nLine 1\r\nLine 2\r\n"))
+}
@@ -0,0 +1,83 @@
+package notifier
+
+type Event string
+
+const (
+	EventEpisodeAired          Event = "episode-aired"
+	EventAutoDownloadStarted   Event = "auto-download-started"
+	EventAutoDownloadFinished  Event = "auto-download-finished"
+	EventScanFinished          Event = "scan-finished"
+	EventMangaChapterAvailable Event = "manga-chapter-available"
+	EventUpdateAvailable       Event = "update-available"
+	EventError                 Event = "error"
+)
+
+// EventInfo describes an event and the data its templates can use.
+type EventInfo struct {
+	Event           Event    `json:"event"`
+	Description     string   `json:"description"`
+	Variables       []string `json:"variables"`
+	DefaultTitle    string   `json:"defaultTitle"`
+	DefaultTemplate string   `json:"defaultTemplate"`
+}
+
+var Events = []*EventInfo{
+	{
+		Event:           EventEpisodeAired,
+		Description:     "A new episode of an anime in your list aired",
+		Variables:       []string{"media", "mediaId", "episode"},
+		DefaultTitle:    "New episode aired",
+		DefaultTemplate: "{{.media}} episode {{.episode}} has aired.",
+	},
+	{
+		Event:           EventAutoDownloadStarted,
+		Description:     "The Auto Downloader started downloading an episode",
+		Variables:       []string{"media", "mediaId", "episode", "torrent"},
+		DefaultTitle:    "Auto Downloader",
+		DefaultTemplate: "Downloading {{.media}} episode {{.episode}}: {{.torrent}}",
+	},
+	{
+		Event:           EventAutoDownloadFinished,
+		Description:     "The Auto Downloader finished checking for new episodes and found some",
+		Variables:       []string{"count", "queued"},
+		DefaultTitle:    "Auto Downloader",
+		DefaultTemplate: "{{.count}} {{if eq .count 1}}episode has{{else}}episodes have{{end}} been {{if .queued}}added to the queue{{else}}downloaded{{end}}.",
+	},
+	{
+		Event:           EventScanFinished,
+		Description:     "A library scan finished",
+		Variables:       []string{"files", "unmatched"},
+		DefaultTitle:    "Library scanned",
+		DefaultTemplate: "{{.files}} files scanned{{if .unmatched}}, {{.unmatched}} could not be matched{{end}}.",
+	},
+	{
+		Event:           EventMangaChapterAvailable,
+		Description:     "A manga chapter was downloaded and is available offline",
+		Variables:       []string{"mediaId", "chapter", "provider"},
+		DefaultTitle:    "Manga chapter available",
+		DefaultTemplate: "Chapter {{.chapter}} is available offline.",
+	},
+	{
+		Event:           EventUpdateAvailable,
+		Description:     "A new version of Seanime is available",
+		Variables:       []string{"version", "currentVersion"},
+		DefaultTitle:    "Update available",
+		DefaultTemplate: "Seanime {{.version}} is available, you are using {{.currentVersion}}.",
+	},
+	{
+		Event:           EventError,
+		Description:     "A background task failed",
+		Variables:       []string{"source", "error"},
+		DefaultTitle:    "Error",
+		DefaultTemplate: "{{.source}}: {{.error}}",
+	},
+}
+
+func getEventInfo(event Event) (*EventInfo, bool) {
+	for _, info := range Events {
+		if info.Event == event {
+			return info, true
+		}
+	}
+	return nil, false
+}
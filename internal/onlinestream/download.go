@@ -0,0 +1,460 @@
+package onlinestream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"seanime/internal/database/db"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/events"
+	"seanime/internal/onlinestream/downloader"
+	"seanime/internal/util"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+)
+
+const (
+	DownloadStatusQueued      DownloadStatus = "queued"
+	DownloadStatusPaused      DownloadStatus = "paused"
+	DownloadStatusDownloading DownloadStatus = "downloading"
+	DownloadStatusRemuxing    DownloadStatus = "remuxing"
+	DownloadStatusCompleted   DownloadStatus = "completed"
+	DownloadStatusErrored     DownloadStatus = "errored"
+	DownloadStatusCancelled   DownloadStatus = "cancelled"
+)
+
+type (
+	// Downloader downloads online stream episodes into the library.
+	// The queue is kept in memory, items are processed one at a time.
+	Downloader struct {
+		logger              *zerolog.Logger
+		wsEventManager      events.WSEventManagerInterface
+		database            *db.Database
+		repository          *Repository
+		tempDir             string
+		onEpisodeDownloaded func(path string)
+
+		mu         sync.Mutex
+		queue      []*DownloadQueueItem
+		cancelFunc context.CancelFunc  // Cancels the current download
+		pausing    map[string]struct{} // Running downloads that are being paused
+		runCh      chan struct{}
+	}
+
+	DownloadStatus string
+
+	DownloadQueueItem struct {
+		ID              string         `json:"id"`
+		MediaId         int            `json:"mediaId"`
+		EpisodeNumber   int            `json:"episodeNumber"`
+		Provider        string         `json:"provider"`
+		Dubbed          bool           `json:"dubbed"`
+		Quality         string         `json:"quality,omitempty"` // Preferred quality
+		Server          string         `json:"server,omitempty"`  // Preferred server
+		Destination     string         `json:"destination,omitempty"`
+		Status          DownloadStatus `json:"status"`
+		Percentage      float64        `json:"percentage"`
+		DownloadedBytes int64          `json:"downloadedBytes"`
+		Error           string         `json:"error,omitempty"`
+	}
+
+	NewDownloaderOptions struct {
+		Logger         *zerolog.Logger
+		WSEventManager events.WSEventManagerInterface
+		Database       *db.Database
+		Repository     *Repository
+		TempDir        string
+		// OnEpisodeDownloaded is called with the path of each downloaded episode.
+		OnEpisodeDownloaded func(path string)
+	}
+
+	DownloadEpisodesOptions struct {
+		MediaId        int
+		Provider       string
+		Dubbed         bool
+		EpisodeNumbers []int
+		Quality        string
+		Server         string
+	}
+)
+
+func NewDownloader(opts *NewDownloaderOptions) *Downloader {
+	return &Downloader{
+		logger:              opts.Logger,
+		wsEventManager:      opts.WSEventManager,
+		database:            opts.Database,
+		repository:          opts.Repository,
+		tempDir:             opts.TempDir,
+		onEpisodeDownloaded: opts.OnEpisodeDownloaded,
+		queue:               make([]*DownloadQueueItem, 0),
+		pausing:             make(map[string]struct{}),
+		runCh:               make(chan struct{}, 1),
+	}
+}
+
+// Start spins up a goroutine that processes the queue.
+func (d *Downloader) Start() {
+	// Remove leftovers from previous sessions
+	_ = os.RemoveAll(d.tempDir)
+
+	go func() {
+		for range d.runCh {
+			for d.runNext() {
+			}
+		}
+	}()
+}
+
+// DownloadEpisodes adds the episodes to the download queue.
+func (d *Downloader) DownloadEpisodes(opts *DownloadEpisodesOptions) error {
+	if opts.Provider == "" {
+		return errors.New("no provider selected")
+	}
+	if len(opts.EpisodeNumbers) == 0 {
+		return errors.New("no episodes selected")
+	}
+
+	d.mu.Lock()
+	for _, number := range opts.EpisodeNumbers {
+		// Skip episodes that are already in the queue
+		_, found := lo.Find(d.queue, func(item *DownloadQueueItem) bool {
+			return item.MediaId == opts.MediaId && item.EpisodeNumber == number &&
+				item.isPending()
+		})
+		if found {
+			continue
+		}
+		d.queue = append(d.queue, &DownloadQueueItem{
+			ID:            uuid.NewString(),
+			MediaId:       opts.MediaId,
+			EpisodeNumber: number,
+			Provider:      opts.Provider,
+			Dubbed:        opts.Dubbed,
+			Quality:       opts.Quality,
+			Server:        opts.Server,
+			Status:        DownloadStatusQueued,
+		})
+	}
+	d.mu.Unlock()
+
+	d.logger.Info().Int("mediaId", opts.MediaId).Ints("episodes", opts.EpisodeNumbers).Msg("onlinestream: Added episodes to the download queue")
+
+	d.sendQueueUpdated()
+	d.triggerRun()
+
+	return nil
+}
+
+// GetQueue returns a snapshot of the download queue.
+func (d *Downloader) GetQueue() []*DownloadQueueItem {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ret := make([]*DownloadQueueItem, 0, len(d.queue))
+	for _, item := range d.queue {
+		cp := *item
+		ret = append(ret, &cp)
+	}
+	return ret
+}
+
+// CancelDownload cancels a queued or running download.
+func (d *Downloader) CancelDownload(id string) error {
+	d.mu.Lock()
+	item, found := lo.Find(d.queue, func(item *DownloadQueueItem) bool {
+		return item.ID == id
+	})
+	if !found {
+		d.mu.Unlock()
+		return errors.New("download not found")
+	}
+
+	switch item.Status {
+	case DownloadStatusQueued, DownloadStatusPaused:
+		item.Status = DownloadStatusCancelled
+	case DownloadStatusDownloading, DownloadStatusRemuxing:
+		if d.cancelFunc != nil {
+			d.cancelFunc()
+		}
+	}
+	d.mu.Unlock()
+
+	d.sendQueueUpdated()
+	return nil
+}
+
+// PauseDownload pauses a queued or running download.
+// A running download is stopped and starts over when it's resumed.
+func (d *Downloader) PauseDownload(id string) error {
+	d.mu.Lock()
+	item, found := lo.Find(d.queue, func(item *DownloadQueueItem) bool {
+		return item.ID == id
+	})
+	if !found {
+		d.mu.Unlock()
+		return errors.New("download not found")
+	}
+
+	switch item.Status {
+	case DownloadStatusQueued:
+		item.Status = DownloadStatusPaused
+	case DownloadStatusDownloading:
+		d.pausing[item.ID] = struct{}{}
+		if d.cancelFunc != nil {
+			d.cancelFunc()
+		}
+	}
+	d.mu.Unlock()
+
+	d.sendQueueUpdated()
+	return nil
+}
+
+// ResumeDownload puts a paused download back in the queue.
+func (d *Downloader) ResumeDownload(id string) error {
+	d.mu.Lock()
+	item, found := lo.Find(d.queue, func(item *DownloadQueueItem) bool {
+		return item.ID == id
+	})
+	if !found {
+		d.mu.Unlock()
+		return errors.New("download not found")
+	}
+	if item.Status == DownloadStatusPaused {
+		item.Status = DownloadStatusQueued
+	}
+	d.mu.Unlock()
+
+	d.sendQueueUpdated()
+	d.triggerRun()
+	return nil
+}
+
+// ClearFinished removes the completed, errored and cancelled downloads from the queue.
+func (d *Downloader) ClearFinished() {
+	d.mu.Lock()
+	d.queue = lo.Filter(d.queue, func(item *DownloadQueueItem, _ int) bool {
+		return item.isPending()
+	})
+	d.mu.Unlock()
+
+	d.sendQueueUpdated()
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// runNext downloads the next queued item.
+// It returns false if there are no queued items.
+func (d *Downloader) runNext() bool {
+	defer util.HandlePanicInModuleThen("onlinestream/Downloader/runNext", func() {
+		d.logger.Error().Msg("onlinestream: Recovered from panic in downloader")
+	})
+
+	d.mu.Lock()
+	item, found := lo.Find(d.queue, func(item *DownloadQueueItem) bool {
+		return item.Status == DownloadStatusQueued
+	})
+	if !found {
+		d.mu.Unlock()
+		return false
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancelFunc = cancel
+	item.Status = DownloadStatusDownloading
+	d.mu.Unlock()
+
+	d.sendQueueUpdated()
+
+	err := d.download(ctx, item)
+
+	d.mu.Lock()
+	d.cancelFunc = nil
+	_, paused := d.pausing[item.ID]
+	delete(d.pausing, item.ID)
+	switch {
+	case err == nil:
+		item.Status = DownloadStatusCompleted
+		item.Percentage = 100
+	case ctx.Err() != nil && paused:
+		item.Status = DownloadStatusPaused
+		item.Percentage = 0
+		item.DownloadedBytes = 0
+	case ctx.Err() != nil:
+		item.Status = DownloadStatusCancelled
+	default:
+		item.Status = DownloadStatusErrored
+		item.Error = err.Error()
+	}
+	d.mu.Unlock()
+	cancel()
+
+	if err != nil && ctx.Err() == nil {
+		d.logger.Error().Err(err).Int("mediaId", item.MediaId).Int("episode", item.EpisodeNumber).Msg("onlinestream: Failed to download episode")
+	}
+
+	if err == nil {
+		d.logger.Info().Str("path", item.Destination).Msg("onlinestream: Episode downloaded")
+		if d.onEpisodeDownloaded != nil {
+			go d.onEpisodeDownloaded(item.Destination)
+		}
+	}
+
+	d.sendQueueUpdated()
+	return true
+}
+
+func (d *Downloader) download(ctx context.Context, item *DownloadQueueItem) error {
+	media, err := d.repository.getMedia(item.MediaId)
+	if err != nil {
+		return err
+	}
+
+	// The library directory with the highest priority that files can be downloaded to
+	libraryPath, err := db_bridge.ResolveDownloadDestination(d.database, "")
+	if err != nil {
+		return err
+	}
+
+	// e.g. {libraryPath}/{title}/{title} - 01.mkv
+	title := sanitizeFilename(media.GetRomajiTitleSafe())
+	destination := filepath.Join(libraryPath, title, fmt.Sprintf("%s - %02d.mkv", title, item.EpisodeNumber))
+	if _, err := os.Stat(destination); err == nil {
+		return errors.New("file already exists")
+	}
+
+	d.mu.Lock()
+	item.Destination = destination
+	d.mu.Unlock()
+
+	// Resolve the sources right before downloading since the URLs can expire
+	episodeSource, err := d.repository.GetEpisodeSources(item.Provider, item.MediaId, item.EpisodeNumber, item.Dubbed, media.GetStartYearSafe())
+	if err != nil {
+		return err
+	}
+
+	videoSource, found := selectVideoSource(episodeSource.VideoSources, item.Server, item.Quality)
+	if !found {
+		return ErrNoVideoSourceFound
+	}
+
+	subtitles := make([]*episode_downloader.Subtitle, 0, len(episodeSource.Subtitles))
+	for _, sub := range episodeSource.Subtitles {
+		subtitles = append(subtitles, &episode_downloader.Subtitle{URL: sub.URL, Language: sub.Language})
+	}
+
+	ffmpegPath := "ffmpeg"
+	if settings, found := d.database.GetMediastreamSettings(); found && settings.FfmpegPath != "" {
+		ffmpegPath = settings.FfmpegPath
+	}
+
+	return episode_downloader.Download(ctx, d.logger, &episode_downloader.DownloadOptions{
+		ID:          item.ID,
+		URL:         videoSource.URL,
+		Headers:     videoSource.Headers,
+		Subtitles:   subtitles,
+		Destination: destination,
+		TempDir:     d.tempDir,
+		FfmpegPath:  ffmpegPath,
+		OnProgress: func(p episode_downloader.Progress) {
+			d.mu.Lock()
+			if p.Stage == episode_downloader.StageRemuxing {
+				item.Status = DownloadStatusRemuxing
+			}
+			item.Percentage = p.Percentage
+			item.DownloadedBytes = p.DownloadedBytes
+			d.mu.Unlock()
+			d.sendQueueUpdated()
+		},
+	})
+}
+
+func (d *Downloader) triggerRun() {
+	select {
+	case d.runCh <- struct{}{}:
+	default:
+	}
+}
+
+// isPending returns true if the download hasn't finished.
+func (item *DownloadQueueItem) isPending() bool {
+	switch item.Status {
+	case DownloadStatusQueued, DownloadStatusPaused, DownloadStatusDownloading, DownloadStatusRemuxing:
+		return true
+	}
+	return false
+}
+
+func (d *Downloader) sendQueueUpdated() {
+	d.wsEventManager.SendEvent(events.OnlinestreamDownloadQueueUpdated, d.GetQueue())
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// selectVideoSource returns the video source matching the preferred server and quality.
+// If the quality is not found, the recommended source or the best quality of the server is returned.
+func selectVideoSource(sources []*VideoSource, server string, quality string) (*VideoSource, bool) {
+	if len(sources) == 0 {
+		return nil, false
+	}
+
+	// Skip the sources that failed the health check
+	candidates := sources
+	if hasAvailableVideoSource(sources) {
+		candidates = lo.Filter(sources, func(vs *VideoSource, _ int) bool {
+			return vs.Available
+		})
+	}
+
+	if server != "" {
+		fromServer := lo.Filter(candidates, func(vs *VideoSource, _ int) bool {
+			return strings.EqualFold(vs.Server, server)
+		})
+		if len(fromServer) > 0 {
+			candidates = fromServer
+		}
+	}
+
+	if quality != "" {
+		if vs, found := lo.Find(candidates, func(vs *VideoSource) bool {
+			return strings.Contains(strings.ToLower(vs.Quality), strings.ToLower(quality))
+		}); found {
+			return vs, true
+		}
+	}
+
+	if server == "" {
+		if vs, found := lo.Find(candidates, func(vs *VideoSource) bool {
+			return vs.Recommended
+		}); found {
+			return vs, true
+		}
+	}
+
+	// Adaptive streams contain the best quality
+	for _, q := range []string{"auto", "default", "1080", "720", "480", "360"} {
+		if vs, found := lo.Find(candidates, func(vs *VideoSource) bool {
+			return strings.Contains(strings.ToLower(vs.Quality), q)
+		}); found {
+			return vs, true
+		}
+	}
+
+	return candidates[0], true
+}
+
+// sanitizeFilename removes characters that are not allowed in file names.
+func sanitizeFilename(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?*`, r) || r < 32 {
+			return -1
+		}
+		return r
+	}, name)
+	return strings.TrimRight(strings.TrimSpace(name), ".")
+}
@@ -0,0 +1,59 @@
+package onlinestream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectVideoSource(t *testing.T) {
+	sources := []*VideoSource{
+		{Server: "vidstreaming", URL: "1", Quality: "360p"},
+		{Server: "vidstreaming", URL: "2", Quality: "1080p"},
+		{Server: "gogocdn", URL: "3", Quality: "720p"},
+		{Server: "gogocdn", URL: "4", Quality: "auto"},
+	}
+
+	tests := []struct {
+		name     string
+		server   string
+		quality  string
+		expected string
+	}{
+		{"preferred quality", "", "360", "1"},
+		{"adaptive stream", "", "", "4"},
+		{"preferred server", "vidstreaming", "", "2"},
+		{"preferred server and quality", "gogocdn", "720p", "3"},
+		{"unknown server", "unknown", "720p", "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs, found := selectVideoSource(sources, tt.server, tt.quality)
+			require.True(t, found)
+			require.Equal(t, tt.expected, vs.URL)
+		})
+	}
+
+	_, found := selectVideoSource(nil, "", "")
+	require.False(t, found)
+
+	// Sources that failed the health check are skipped
+	checked := []*VideoSource{
+		{Server: "vidstreaming", URL: "1", Quality: "1080p", Available: false},
+		{Server: "gogocdn", URL: "2", Quality: "720p", Available: true, Recommended: true},
+		{Server: "gogocdn", URL: "3", Quality: "360p", Available: true},
+	}
+	vs, found := selectVideoSource(checked, "", "1080")
+	require.True(t, found)
+	require.Equal(t, "2", vs.URL)
+	vs, found = selectVideoSource(checked, "", "360")
+	require.True(t, found)
+	require.Equal(t, "3", vs.URL)
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	require.Equal(t, "Re Zero kara Hajimeru Isekai Seikatsu", sanitizeFilename("Re: Zero kara Hajimeru Isekai Seikatsu"))
+	require.Equal(t, "Fate-stay night", sanitizeFilename("Fate-stay night..."))
+	require.Equal(t, "Why", sanitizeFilename("Why?"))
+}
@@ -0,0 +1,362 @@
+package episode_downloader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"seanime/internal/util"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// 📁 tempDir
+// └── 📁 {id}                <- Removed after the download
+//     ├── 📄 video           <- Downloaded video, HLS segments are concatenated
+//     ├── 📄 0.vtt           <- Downloaded subtitles
+//     ├── 📄 ...
+//     └── 📄 output.mkv      <- Remuxed file, moved to the destination
+
+const (
+	StageDownloading Stage = "downloading"
+	StageRemuxing    Stage = "remuxing"
+)
+
+type (
+	Stage string
+
+	Subtitle struct {
+		URL      string
+		Language string
+	}
+
+	// Progress is sent to the OnProgress callback during the download.
+	Progress struct {
+		Stage           Stage
+		Percentage      float64 // Only set for HLS streams and when the content length of the video is known
+		DownloadedBytes int64
+	}
+
+	DownloadOptions struct {
+		ID          string // Used to name the temporary directory
+		URL         string
+		Headers     map[string]string
+		Subtitles   []*Subtitle
+		Destination string // Path of the MKV file
+		TempDir     string
+		FfmpegPath  string
+		OnProgress  func(p Progress)
+	}
+
+	download struct {
+		logger     *zerolog.Logger
+		client     *http.Client
+		headers    map[string]string
+		onProgress func(p Progress)
+
+		mu              sync.Mutex
+		progress        Progress
+		lastProgressAt  time.Time
+		contentLength   int64
+		downloadedBytes int64
+	}
+)
+
+// Download downloads the video and subtitles, remuxes them into an MKV file and moves it to the destination.
+// The video can be a direct file (e.g. MP4) or an HLS playlist.
+func Download(ctx context.Context, logger *zerolog.Logger, opts *DownloadOptions) (err error) {
+	defer util.HandlePanicInModuleWithError("onlinestream/downloader/Download", &err)
+
+	if opts.FfmpegPath == "" {
+		opts.FfmpegPath = "ffmpeg"
+	}
+
+	d := &download{
+		logger:     logger,
+		client:     &http.Client{},
+		headers:    opts.Headers,
+		onProgress: opts.OnProgress,
+		progress:   Progress{Stage: StageDownloading},
+	}
+
+	workDir := filepath.Join(opts.TempDir, opts.ID)
+	if err = os.MkdirAll(workDir, os.ModePerm); err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+
+	// Download the video
+	videoPath := filepath.Join(workDir, "video")
+	if err = d.downloadVideo(ctx, opts.URL, videoPath); err != nil {
+		return fmt.Errorf("failed to download video: %w", err)
+	}
+
+	// Download the subtitles, failed subtitles are skipped
+	subtitles := make([]*downloadedSubtitle, 0, len(opts.Subtitles))
+	for i, sub := range opts.Subtitles {
+		subPath := filepath.Join(workDir, strconv.Itoa(i)+subtitleExt(sub.URL))
+		if sErr := d.downloadFile(ctx, sub.URL, subPath); sErr != nil {
+			logger.Warn().Err(sErr).Str("language", sub.Language).Msg("episode downloader: Failed to download subtitle, skipping")
+			continue
+		}
+		subtitles = append(subtitles, &downloadedSubtitle{path: subPath, language: sub.Language})
+	}
+
+	// Remux into MKV
+	d.setStage(StageRemuxing)
+	outputPath := filepath.Join(workDir, "output.mkv")
+	if err = remux(ctx, opts.FfmpegPath, videoPath, subtitles, outputPath, logger); err != nil {
+		return fmt.Errorf("failed to remux video: %w", err)
+	}
+
+	// Move the file to the destination
+	if err = os.MkdirAll(filepath.Dir(opts.Destination), os.ModePerm); err != nil {
+		return err
+	}
+	if err = os.Rename(outputPath, opts.Destination); err != nil {
+		// The temporary directory might be on another file system
+		if err = util.LinkOrCopyFile(outputPath, opts.Destination); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (d *download) newRequest(ctx context.Context, u string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", util.GetRandomUserAgent())
+	for k, v := range d.headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
+
+func (d *download) get(ctx context.Context, u string) (*http.Response, error) {
+	req, err := d.newRequest(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (d *download) getBytes(ctx context.Context, u string) ([]byte, error) {
+	resp, err := d.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// downloadVideo downloads the video to the given path.
+// HLS playlists are detected from the response body.
+func (d *download) downloadVideo(ctx context.Context, u string, dst string) error {
+	resp, err := d.get(ctx, u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(resp.Body)
+	peek, _ := br.Peek(16)
+
+	if isHLS(peek) {
+		playlistData, err := io.ReadAll(br)
+		if err != nil {
+			return err
+		}
+		w := bufio.NewWriter(f)
+		if err := d.downloadHLS(ctx, u, playlistData, w); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	d.mu.Lock()
+	d.contentLength = resp.ContentLength
+	d.mu.Unlock()
+
+	_, err = io.Copy(f, &progressReader{reader: br, d: d})
+	return err
+}
+
+// downloadFile downloads a small file (e.g. subtitles) without reporting progress.
+func (d *download) downloadFile(ctx context.Context, u string, dst string) error {
+	data, err := d.getBytes(ctx, u)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0644)
+}
+
+func (d *download) addBytes(n int64) {
+	d.mu.Lock()
+	d.downloadedBytes += n
+	d.progress.DownloadedBytes = d.downloadedBytes
+	if d.contentLength > 0 {
+		d.progress.Percentage = float64(d.downloadedBytes) / float64(d.contentLength) * 100
+	}
+	d.mu.Unlock()
+	d.sendProgress(false)
+}
+
+func (d *download) setPercentage(p float64) {
+	d.mu.Lock()
+	d.progress.Percentage = p
+	d.mu.Unlock()
+	d.sendProgress(false)
+}
+
+func (d *download) setStage(stage Stage) {
+	d.mu.Lock()
+	d.progress.Stage = stage
+	d.mu.Unlock()
+	d.sendProgress(true)
+}
+
+// sendProgress calls the OnProgress callback at most once per second, unless force is true.
+func (d *download) sendProgress(force bool) {
+	if d.onProgress == nil {
+		return
+	}
+	d.mu.Lock()
+	if !force && time.Since(d.lastProgressAt) < time.Second {
+		d.mu.Unlock()
+		return
+	}
+	d.lastProgressAt = time.Now()
+	p := d.progress
+	d.mu.Unlock()
+
+	d.onProgress(p)
+}
+
+type progressReader struct {
+	reader io.Reader
+	d      *download
+}
+
+func (pr *progressReader) Read(p []byte) (n int, err error) {
+	n, err = pr.reader.Read(p)
+	if n > 0 {
+		pr.d.addBytes(int64(n))
+	}
+	return
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type downloadedSubtitle struct {
+	path     string
+	language string
+}
+
+// subtitleExt returns the extension of the subtitle file from its URL, defaults to ".vtt".
+func subtitleExt(u string) string {
+	if i := strings.IndexAny(u, "?#"); i != -1 {
+		u = u[:i]
+	}
+	switch ext := strings.ToLower(path.Ext(u)); ext {
+	case ".vtt", ".srt", ".ass", ".ssa":
+		return ext
+	}
+	return ".vtt"
+}
+
+// remux copies the video and audio streams and embeds the subtitles into an MKV file.
+func remux(ctx context.Context, ffmpegPath string, videoPath string, subtitles []*downloadedSubtitle, outputPath string, logger *zerolog.Logger) error {
+	args := []string{"-hide_banner", "-loglevel", "error", "-y", "-i", videoPath}
+	for _, sub := range subtitles {
+		args = append(args, "-i", sub.path)
+	}
+
+	args = append(args, "-map", "0:v?", "-map", "0:a?")
+	for i := range subtitles {
+		args = append(args, "-map", strconv.Itoa(i+1)+":0")
+	}
+
+	args = append(args, "-c:v", "copy", "-c:a", "copy")
+	for i, sub := range subtitles {
+		// WebVTT is converted to SRT since it's better supported by media players
+		codec := "copy"
+		if filepath.Ext(sub.path) == ".vtt" {
+			codec = "srt"
+		}
+		args = append(args, "-c:s:"+strconv.Itoa(i), codec)
+		if sub.language != "" {
+			args = append(args, "-metadata:s:s:"+strconv.Itoa(i), "title="+sub.language)
+			args = append(args, "-metadata:s:s:"+strconv.Itoa(i), "language="+languageCode(sub.language))
+		}
+	}
+	args = append(args, "-f", "matroska", outputPath)
+
+	logger.Trace().Msgf("episode downloader: ffmpeg command: %s %s", ffmpegPath, strings.Join(args, " "))
+
+	var stderr bytes.Buffer
+	cmd := util.NewCmdCtx(ctx, ffmpegPath, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// languageCode returns the ISO 639-2 code of the language name given by providers.
+func languageCode(language string) string {
+	codes := map[string]string{
+		"english":    "eng",
+		"japanese":   "jpn",
+		"spanish":    "spa",
+		"portuguese": "por",
+		"french":     "fre",
+		"german":     "ger",
+		"italian":    "ita",
+		"russian":    "rus",
+		"arabic":     "ara",
+		"indonesian": "ind",
+		"thai":       "tha",
+		"vietnamese": "vie",
+		"chinese":    "chi",
+		"korean":     "kor",
+	}
+	l := strings.ToLower(language)
+	for name, code := range codes {
+		if strings.HasPrefix(l, name) {
+			return code
+		}
+	}
+	return "und"
+}
@@ -0,0 +1,194 @@
+package episode_downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/grafov/m3u8"
+)
+
+// isHLS returns true if the beginning of the response body is an m3u8 playlist.
+func isHLS(peek []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(peek), []byte("#EXTM3U"))
+}
+
+// resolveURL resolves a URI found in a playlist against the playlist URL.
+func resolveURL(base string, ref string) (string, error) {
+	baseUrl, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	refUrl, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return baseUrl.ResolveReference(refUrl).String(), nil
+}
+
+// downloadHLS writes the segments of the playlist to w.
+// If the playlist is a master playlist, the variant with the highest bandwidth is downloaded.
+// Segments encrypted with AES-128 are decrypted.
+func (d *download) downloadHLS(ctx context.Context, playlistUrl string, playlistData []byte, w io.Writer) error {
+
+	playlist, listType, err := m3u8.DecodeFrom(bytes.NewReader(playlistData), true)
+	if err != nil {
+		return fmt.Errorf("failed to decode playlist: %w", err)
+	}
+
+	if listType == m3u8.MASTER {
+		master := playlist.(*m3u8.MasterPlaylist)
+		var best *m3u8.Variant
+		for _, v := range master.Variants {
+			if v == nil || v.Iframe {
+				continue
+			}
+			if best == nil || v.Bandwidth > best.Bandwidth {
+				best = v
+			}
+		}
+		if best == nil {
+			return errors.New("master playlist has no variants")
+		}
+
+		variantUrl, err := resolveURL(playlistUrl, best.URI)
+		if err != nil {
+			return err
+		}
+
+		d.logger.Debug().Str("resolution", best.Resolution).Uint32("bandwidth", best.Bandwidth).Msg("episode downloader: Selected variant")
+
+		variantData, err := d.getBytes(ctx, variantUrl)
+		if err != nil {
+			return fmt.Errorf("failed to fetch variant playlist: %w", err)
+		}
+		if !isHLS(variantData) {
+			return errors.New("variant is not a playlist")
+		}
+
+		return d.downloadHLS(ctx, variantUrl, variantData, w)
+	}
+
+	media := playlist.(*m3u8.MediaPlaylist)
+	segments := make([]*m3u8.MediaSegment, 0, media.Count())
+	for _, seg := range media.Segments {
+		if seg != nil {
+			segments = append(segments, seg)
+		}
+	}
+	if len(segments) == 0 {
+		return errors.New("playlist has no segments")
+	}
+
+	keys := make(map[string][]byte) // Key: Key URL
+	var currentKey *m3u8.Key
+	var currentMap *m3u8.Map
+
+	for i, seg := range segments {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Keys and init segments apply to all following segments until they change
+		if seg.Key != nil {
+			currentKey = seg.Key
+		}
+		if seg.Map != nil && (currentMap == nil || seg.Map.URI != currentMap.URI) {
+			currentMap = seg.Map
+			mapUrl, err := resolveURL(playlistUrl, currentMap.URI)
+			if err != nil {
+				return err
+			}
+			data, err := d.getBytes(ctx, mapUrl)
+			if err != nil {
+				return fmt.Errorf("failed to fetch init segment: %w", err)
+			}
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+		}
+
+		segmentUrl, err := resolveURL(playlistUrl, seg.URI)
+		if err != nil {
+			return err
+		}
+		data, err := d.getBytes(ctx, segmentUrl)
+		if err != nil {
+			return fmt.Errorf("failed to fetch segment %d: %w", i, err)
+		}
+
+		if currentKey != nil && currentKey.Method != "" && currentKey.Method != "NONE" {
+			if currentKey.Method != "AES-128" {
+				return fmt.Errorf("unsupported encryption method: %s", currentKey.Method)
+			}
+			keyUrl, err := resolveURL(playlistUrl, currentKey.URI)
+			if err != nil {
+				return err
+			}
+			key, found := keys[keyUrl]
+			if !found {
+				key, err = d.getBytes(ctx, keyUrl)
+				if err != nil {
+					return fmt.Errorf("failed to fetch key: %w", err)
+				}
+				keys[keyUrl] = key
+			}
+			data, err = decryptSegment(data, key, currentKey.IV, seg.SeqId)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt segment %d: %w", i, err)
+			}
+		}
+
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+
+		d.addBytes(int64(len(data)))
+		d.setPercentage(float64(i+1) / float64(len(segments)) * 100)
+	}
+
+	return nil
+}
+
+// decryptSegment decrypts an AES-128 encrypted segment.
+// If the playlist does not specify an IV, the media sequence number is used.
+func decryptSegment(data []byte, key []byte, ivStr string, seqId uint64) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if ivStr != "" {
+		ivStr = strings.TrimPrefix(strings.TrimPrefix(ivStr, "0x"), "0X")
+		decoded, err := hex.DecodeString(ivStr)
+		if err != nil || len(decoded) != aes.BlockSize {
+			return nil, errors.New("invalid IV")
+		}
+		iv = decoded
+	} else {
+		binary.BigEndian.PutUint64(iv[8:], seqId)
+	}
+
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid segment size")
+	}
+
+	ret := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(ret, data)
+
+	// Remove PKCS#7 padding
+	padding := int(ret[len(ret)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(ret) {
+		return nil, errors.New("invalid padding")
+	}
+	return ret[:len(ret)-padding], nil
+}
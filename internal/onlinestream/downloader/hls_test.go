@@ -0,0 +1,107 @@
+package episode_downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"seanime/internal/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func encryptSegment(t *testing.T, data []byte, key []byte, seqId uint64) []byte {
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	data = append(data, bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	iv := make([]byte, aes.BlockSize)
+	binary.BigEndian.PutUint64(iv[8:], seqId)
+
+	ret := make([]byte, len(data))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ret, data)
+	return ret
+}
+
+func TestDownloadHLS(t *testing.T) {
+	key := []byte("0123456789abcdef")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/master.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("#EXTM3U\n" +
+			"#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360\n" +
+			"low/index.m3u8\n" +
+			"#EXT-X-STREAM-INF:BANDWIDTH=5000000,RESOLUTION=1920x1080\n" +
+			"high/index.m3u8\n"))
+	})
+	mux.HandleFunc("/high/index.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("#EXTM3U\n" +
+			"#EXT-X-VERSION:3\n" +
+			"#EXT-X-TARGETDURATION:10\n" +
+			"#EXT-X-MEDIA-SEQUENCE:0\n" +
+			"#EXT-X-KEY:METHOD=AES-128,URI=\"/key\"\n" +
+			"#EXTINF:10.0,\n" +
+			"seg0.ts\n" +
+			"#EXTINF:10.0,\n" +
+			"seg1.ts\n" +
+			"#EXT-X-ENDLIST\n"))
+	})
+	mux.HandleFunc("/high/seg0.ts", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(encryptSegment(t, []byte("first-segment"), key, 0))
+	})
+	mux.HandleFunc("/high/seg1.ts", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(encryptSegment(t, []byte("second-segment"), key, 1))
+	})
+	mux.HandleFunc("/key", func(w http.ResponseWriter, r *http.Request) {
+		// Headers of the source should be sent with every request
+		if r.Header.Get("Referer") != "https://example.com" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write(key)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	d := &download{
+		logger:  util.NewLogger(),
+		client:  server.Client(),
+		headers: map[string]string{"Referer": "https://example.com"},
+	}
+
+	playlistData, err := d.getBytes(context.Background(), server.URL+"/master.m3u8")
+	require.NoError(t, err)
+	require.True(t, isHLS(playlistData))
+
+	var out bytes.Buffer
+	err = d.downloadHLS(context.Background(), server.URL+"/master.m3u8", playlistData, &out)
+	require.NoError(t, err)
+
+	require.Equal(t, "first-segmentsecond-segment", out.String())
+	require.Equal(t, float64(100), d.progress.Percentage)
+}
+
+func TestSubtitleExt(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://example.com/subs/eng.vtt", ".vtt"},
+		{"https://example.com/subs/eng.ASS?token=abc", ".ass"},
+		{"https://example.com/subs/eng.srt#t=0", ".srt"},
+		{"https://example.com/subs/eng", ".vtt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			require.Equal(t, tt.expected, subtitleExt(tt.url))
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package onlinestream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"seanime/internal/util"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	probeTimeout    = 5 * time.Second
+	serverHealthTTL = 30 * time.Minute
+)
+
+type (
+	// ServerHealth is the last known health of a provider's server.
+	ServerHealth struct {
+		Provider  string        `json:"provider"`
+		Server    string        `json:"server"`
+		Available bool          `json:"available"`
+		Latency   time.Duration `json:"latency"`
+		CheckedAt time.Time     `json:"checkedAt"`
+	}
+)
+
+func serverHealthKey(provider string, server string) string {
+	return provider + "$" + strings.ToLower(server)
+}
+
+func (r *Repository) setServerHealth(provider string, server string, available bool, latency time.Duration) {
+	r.serverHealth.SetT(serverHealthKey(provider, server), &ServerHealth{
+		Provider:  provider,
+		Server:    server,
+		Available: available,
+		Latency:   latency,
+		CheckedAt: time.Now(),
+	}, serverHealthTTL)
+}
+
+func (r *Repository) getServerHealth(provider string, server string) (*ServerHealth, bool) {
+	return r.serverHealth.Get(serverHealthKey(provider, server))
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// rankVideoSources orders the video sources by availability and latency.
+// The health of each server is cached per provider, only the sources of servers without a cached health are probed, in parallel.
+// The sources of the other servers share the cached health of their server.
+// The recommended source is the first available source whose URL was probed by this call,
+// the sources of servers with a cached health are probed one by one until one is reachable.
+func (r *Repository) rankVideoSources(provider string, sources []*VideoSource) {
+	if len(sources) == 0 {
+		return
+	}
+
+	// Probe the sources of the servers whose health is unknown
+	toProbe := make([]*VideoSource, 0, len(sources))
+	for _, vs := range sources {
+		if _, found := r.getServerHealth(provider, vs.Server); !found {
+			toProbe = append(toProbe, vs)
+		}
+	}
+
+	latencies := make([]time.Duration, len(toProbe))
+	errs := make([]error, len(toProbe))
+
+	wg := sync.WaitGroup{}
+	for i, vs := range toProbe {
+		wg.Add(1)
+		go func(i int, vs *VideoSource) {
+			defer wg.Done()
+			defer util.HandlePanicInModuleThen("onlinestream/rankVideoSources", func() {
+				errs[i] = errors.New("panic")
+			})
+			latencies[i], errs[i] = r.probeVideoSource(vs)
+		}(i, vs)
+	}
+	wg.Wait()
+
+	// A server is available if any of its sources is reachable
+	serverLatency := make(map[string]time.Duration)
+	probedServers := make([]string, 0)
+	probed := make(map[*VideoSource]bool, len(toProbe)) // Whether the sources probed by this call are reachable
+	for i, vs := range toProbe {
+		if !slices.Contains(probedServers, vs.Server) {
+			probedServers = append(probedServers, vs.Server)
+		}
+		probed[vs] = errs[i] == nil
+		if errs[i] != nil {
+			r.logger.Debug().Err(errs[i]).Str("server", vs.Server).Str("quality", vs.Quality).Msg("onlinestream: Video source is unavailable")
+			continue
+		}
+		if l, found := serverLatency[vs.Server]; !found || latencies[i] < l {
+			serverLatency[vs.Server] = latencies[i]
+		}
+	}
+	for _, server := range probedServers {
+		l, available := serverLatency[server]
+		r.setServerHealth(provider, server, available, l)
+	}
+
+	// Sources of the same server share its health, unless they failed their own probe
+	for _, vs := range sources {
+		vs.Recommended = false
+		vs.Available = false
+		vs.Latency = 0
+		if ok, found := probed[vs]; found && !ok {
+			continue
+		}
+		if h, found := r.getServerHealth(provider, vs.Server); found && h.Available {
+			vs.Available = true
+			vs.Latency = h.Latency.Milliseconds()
+			serverLatency[vs.Server] = h.Latency
+		}
+	}
+
+	sortVideoSources(sources, serverLatency)
+
+	// The source to recommend must be reachable, the health of its server might come from the sources of another episode
+	for _, vs := range sources {
+		if !vs.Available {
+			break
+		}
+		if _, found := probed[vs]; !found {
+			if _, err := r.probeVideoSource(vs); err != nil {
+				r.logger.Debug().Err(err).Str("server", vs.Server).Str("quality", vs.Quality).Msg("onlinestream: Video source is unavailable")
+				vs.Available = false
+				vs.Latency = 0
+				continue
+			}
+		}
+		vs.Recommended = true
+		break
+	}
+
+	// Sources that failed their probe are moved after the available ones
+	sortVideoSources(sources, serverLatency)
+}
+
+// sortVideoSources orders the available sources first, then by the latency of their server.
+func sortVideoSources(sources []*VideoSource, serverLatency map[string]time.Duration) {
+	slices.SortStableFunc(sources, func(a, b *VideoSource) int {
+		if a.Available != b.Available {
+			if a.Available {
+				return -1
+			}
+			return 1
+		}
+		// Sources of the same server keep their order
+		if a.Server == b.Server {
+			return 0
+		}
+		la, lb := serverLatency[a.Server], serverLatency[b.Server]
+		switch {
+		case la < lb:
+			return -1
+		case la > lb:
+			return 1
+		}
+		return 0
+	})
+}
+
+// probeVideoSource checks that the video source can be fetched and returns the latency.
+// Manifests are fetched and validated, other files are requested with an empty range.
+func (r *Repository) probeVideoSource(vs *VideoSource) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, vs.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", util.GetRandomUserAgent())
+	for k, v := range vs.Headers {
+		req.Header.Set(k, v)
+	}
+
+	isManifest := strings.Contains(strings.ToLower(vs.URL), ".m3u8")
+	if !isManifest {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+
+	start := time.Now()
+	resp, err := r.probeClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	latency := time.Since(start)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if isManifest {
+		b, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.HasPrefix(bytes.TrimSpace(b), []byte("#EXTM3U")) {
+			return 0, errors.New("invalid manifest")
+		}
+	}
+
+	return latency, nil
+}
+
+// resetServerHealth removes the cached health of the servers so that their sources are probed again.
+func (r *Repository) resetServerHealth(provider string, sources []*VideoSource) {
+	for _, vs := range sources {
+		r.serverHealth.Delete(serverHealthKey(provider, vs.Server))
+	}
+}
+
+// hasAvailableVideoSource returns true if any of the video sources is reachable.
+func hasAvailableVideoSource(sources []*VideoSource) bool {
+	return slices.ContainsFunc(sources, func(vs *VideoSource) bool {
+		return vs.Available
+	})
+}
@@ -0,0 +1,97 @@
+package onlinestream
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"seanime/internal/util"
+	"seanime/internal/util/result"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankVideoSources(t *testing.T) {
+	var probes atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow/master.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		probes.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte("#EXTM3U\n"))
+	})
+	mux.HandleFunc("/fast/video.mp4", func(w http.ResponseWriter, r *http.Request) {
+		// Headers of the source should be sent with the health check
+		if r.Header.Get("Referer") != "https://example.com" || r.Header.Get("Range") != "bytes=0-0" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write([]byte{0})
+	})
+	mux.HandleFunc("/invalid/master.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html>Not found</html>"))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	r := &Repository{
+		logger:       util.NewLogger(),
+		serverHealth: result.NewCache[string, *ServerHealth](),
+		probeClient:  server.Client(),
+	}
+
+	sources := []*VideoSource{
+		{Server: "dead", URL: server.URL + "/dead/master.m3u8", Quality: "auto"},
+		{Server: "invalid", URL: server.URL + "/invalid/master.m3u8", Quality: "auto"},
+		{Server: "slow", URL: server.URL + "/slow/master.m3u8", Quality: "auto"},
+		{Server: "fast", URL: server.URL + "/fast/video.mp4", Quality: "1080p", Headers: map[string]string{"Referer": "https://example.com"}},
+	}
+
+	r.rankVideoSources("provider", sources)
+
+	require.Equal(t, "fast", sources[0].Server)
+	require.True(t, sources[0].Recommended)
+	require.Equal(t, "slow", sources[1].Server)
+	require.True(t, sources[1].Available)
+	require.False(t, sources[1].Recommended)
+	require.False(t, sources[2].Available)
+	require.False(t, sources[3].Available)
+
+	h, found := r.getServerHealth("provider", "dead")
+	require.True(t, found)
+	require.False(t, h.Available)
+
+	// Servers with a cached health are not probed again, only the source to recommend is
+	require.Equal(t, int32(1), probes.Load())
+	cached := []*VideoSource{
+		{Server: "slow", URL: server.URL + "/slow/master.m3u8", Quality: "auto"},
+		{Server: "fast", URL: server.URL + "/fast/video.mp4", Quality: "1080p", Headers: map[string]string{"Referer": "https://example.com"}},
+		{Server: "dead", URL: server.URL + "/dead/episode-2.m3u8", Quality: "auto"},
+	}
+	r.rankVideoSources("provider", cached)
+	require.Equal(t, int32(1), probes.Load())
+	require.Equal(t, "fast", cached[0].Server)
+	require.True(t, cached[0].Available)
+	require.True(t, cached[0].Recommended)
+	require.False(t, cached[2].Available)
+
+	// The sources of another episode are not recommended from the health of their server
+	episode2 := []*VideoSource{
+		{Server: "fast", URL: server.URL + "/fast/episode-2.mp4", Quality: "1080p"},
+		{Server: "slow", URL: server.URL + "/slow/master.m3u8", Quality: "auto"},
+	}
+	r.rankVideoSources("provider", episode2)
+	require.Equal(t, int32(2), probes.Load())
+	require.Equal(t, "slow", episode2[0].Server)
+	require.True(t, episode2[0].Recommended)
+	require.Equal(t, "fast", episode2[1].Server)
+	require.False(t, episode2[1].Available)
+	require.False(t, episode2[1].Recommended)
+
+	r.resetServerHealth("provider", cached)
+	r.rankVideoSources("provider", cached)
+	require.Equal(t, int32(3), probes.Load())
+}
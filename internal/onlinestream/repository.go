@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"github.com/rs/zerolog"
 	"github.com/samber/lo"
+	"net/http"
 	"seanime/internal/api/anilist"
 	"seanime/internal/api/metadata"
 	"seanime/internal/database/db"
 	"seanime/internal/extension"
 	"seanime/internal/platforms/platform"
+	"seanime/internal/trackpreference"
 	"seanime/internal/util/filecache"
+	"seanime/internal/util/result"
 	"strconv"
 	"strings"
 	"time"
@@ -25,6 +28,9 @@ type (
 		platform              platform.Platform
 		anilistBaseAnimeCache *anilist.BaseAnimeCache
 		db                    *db.Database
+		serverHealth          *result.Cache[string, *ServerHealth] // Key: provider$server
+		probeClient           *http.Client
+		trackPreferences      *trackpreference.Repository
 	}
 )
 
@@ -48,10 +54,13 @@ type (
 	}
 
 	VideoSource struct {
-		Server  string            `json:"server"`
-		Headers map[string]string `json:"headers,omitempty"`
-		URL     string            `json:"url"`
-		Quality string            `json:"quality"`
+		Server      string            `json:"server"`
+		Headers     map[string]string `json:"headers,omitempty"`
+		URL         string            `json:"url"`
+		Quality     string            `json:"quality"`
+		Available   bool              `json:"available"`   // Whether the source responded to the health check
+		Latency     int64             `json:"latency"`     // Milliseconds, 0 if unavailable
+		Recommended bool              `json:"recommended"` // Set on the best available source
 	}
 
 	EpisodeListResponse struct {
@@ -62,6 +71,7 @@ type (
 	Subtitle struct {
 		URL      string `json:"url"`
 		Language string `json:"language"`
+		Default  bool   `json:"default"` // Set on the subtitle matching the track preferences
 	}
 )
 
@@ -72,6 +82,7 @@ type (
 		MetadataProvider metadata.Provider
 		Platform         platform.Platform
 		Database         *db.Database
+		TrackPreferences *trackpreference.Repository
 	}
 )
 
@@ -84,6 +95,9 @@ func NewRepository(opts *NewRepositoryOptions) *Repository {
 		anilistBaseAnimeCache: anilist.NewBaseAnimeCache(),
 		platform:              opts.Platform,
 		db:                    opts.Database,
+		trackPreferences:      opts.TrackPreferences,
+		serverHealth:          result.NewCache[string, *ServerHealth](),
+		probeClient:           &http.Client{},
 	}
 }
 
@@ -245,7 +259,54 @@ func (r *Repository) GetEpisodeSources(provider string, mId int, number int, dub
 		return nil, err
 	}
 
-	var sources *EpisodeSource
+	sources, found := getEpisodeSourceFromContainer(ec, number)
+	if !found {
+		return nil, ErrNoVideoSourceFound
+	}
+
+	// +---------------------+
+	// |    Health checks    |
+	// +---------------------+
+
+	r.rankVideoSources(provider, sources.VideoSources)
+
+	// If no source is reachable, the cached servers might have expired, so we extract them again
+	if !hasAvailableVideoSource(sources.VideoSources) {
+		r.logger.Warn().Int("episode", number).Msg("onlinestream: No video source is available, extracting servers again")
+
+		_ = r.fileCacher.Delete(r.getFcEpisodeDataBucket(provider, mId), getEpisodeDataKey(mId, provider, number, dubbed))
+
+		ec, err = r.getEpisodeContainer(provider, mId, media.GetAllTitles(), number, number, dubbed, year)
+		if err != nil {
+			return nil, err
+		}
+		if retried, found := getEpisodeSourceFromContainer(ec, number); found {
+			r.resetServerHealth(provider, retried.VideoSources)
+			r.rankVideoSources(provider, retried.VideoSources)
+			sources = retried
+		}
+	}
+
+	r.selectSubtitle(mId, dubbed, sources.Subtitles)
+
+	return sources, nil
+}
+
+// selectSubtitle flags the subtitle matching the track preferences as the default one.
+func (r *Repository) selectSubtitle(mId int, dubbed bool, subtitles []*Subtitle) {
+	if r.trackPreferences == nil || len(subtitles) == 0 {
+		return
+	}
+
+	languages := lo.Map(subtitles, func(sub *Subtitle, _ int) string { return sub.Language })
+	selection := r.trackPreferences.SelectOnlineSubtitle(mId, dubbed, languages)
+	for i, sub := range subtitles {
+		sub.Default = selection.HasSubtitle() && selection.SubtitleIndex == i
+	}
+}
+
+// getEpisodeSourceFromContainer returns the video sources of the episode from all its servers.
+func getEpisodeSourceFromContainer(ec *episodeContainer, number int) (*EpisodeSource, bool) {
 	for _, ep := range ec.Episodes {
 		if ep.Number == number {
 			s := &EpisodeSource{
@@ -274,14 +335,8 @@ func (r *Repository) GetEpisodeSources(provider string, mId int, number int, dub
 					}
 				}
 			}
-			sources = s
-			break
+			return s, true
 		}
 	}
-
-	if sources == nil {
-		return nil, ErrNoVideoSourceFound
-	}
-
-	return sources, nil
+	return nil, false
 }
@@ -96,7 +96,7 @@ func (r *Repository) getEpisodeContainer(provider string, mId int, titles []*str
 		if episodeDetails.Number >= from && episodeDetails.Number <= to {
 
 			// Check if the episode is cached to avoid fetching the sources again.
-			key := fmt.Sprintf("%d$%s$%d$%v", mId, provider, episodeDetails.Number, dubbed)
+			key := getEpisodeDataKey(mId, provider, episodeDetails.Number, dubbed)
 
 			r.logger.Debug().
 				Str("key", key).
@@ -163,6 +163,13 @@ func (r *Repository) getEpisodeContainer(provider string, mId int, titles []*str
 	return ec, nil
 }
 
+// getEpisodeDataKey returns the key identifying the episode data in the file cache.
+//
+//	e.g. 1$provider$1$true
+func getEpisodeDataKey(mId int, provider string, number int, dubbed bool) string {
+	return fmt.Sprintf("%d$%s$%d$%v", mId, provider, number, dubbed)
+}
+
 // getProviderEpisodeServers gets all the available servers for the episode.
 // It returns errNoEpisodeSourceFound if no sources are found.
 //
@@ -178,12 +185,16 @@ func (r *Repository) getProviderEpisodeServers(provider string, episodeDetails *
 		return nil, fmt.Errorf("provider extension '%s' not found", provider)
 	}
 
+	// All the servers are extracted, their sources are ranked by rankVideoSources
 	for _, episodeServer := range providerExtension.GetProvider().GetSettings().EpisodeServers {
 		res, err := providerExtension.GetProvider().FindEpisodeServer(episodeDetails, episodeServer)
-		if err == nil {
-			// Add the server to the list for the episode
-			providerServers = append(providerServers, res)
+		if err != nil {
+			// Move on to the next server
+			r.logger.Debug().Err(err).Str("provider", provider).Str("server", episodeServer).Msg("onlinestream: Failed to extract episode server")
+			continue
 		}
+		// Add the server to the list for the episode
+		providerServers = append(providerServers, res)
 	}
 
 	if len(providerServers) == 0 {
@@ -7,6 +7,7 @@ import (
 	"github.com/samber/lo"
 	"github.com/samber/mo"
 	"seanime/internal/api/anilist"
+	"seanime/internal/eventstream"
 	"seanime/internal/platforms/platform"
 	"seanime/internal/util/limiter"
 	"sync"
@@ -63,6 +64,16 @@ func (ap *AnilistPlatform) UpdateEntry(mediaID int, status *anilist.MediaListSta
 	if err != nil {
 		return err
 	}
+
+	payload := &eventstream.ListEntryChangedPayload{
+		MediaId:  mediaID,
+		Progress: progress,
+		Score:    scoreRaw,
+	}
+	if status != nil {
+		payload.Status = string(*status)
+	}
+	eventstream.GlobalBroker.Publish(eventstream.TypeListEntryChanged, payload)
 	return nil
 }
 
@@ -109,6 +120,11 @@ func (ap *AnilistPlatform) UpdateEntryProgress(mediaID int, progress int, totalE
 		return err
 	}
 
+	eventstream.GlobalBroker.Publish(eventstream.TypeListEntryChanged, &eventstream.ListEntryChangedPayload{
+		MediaId:  mediaID,
+		Status:   string(status),
+		Progress: &progress,
+	})
 	return nil
 }
 
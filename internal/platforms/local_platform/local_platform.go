@@ -5,6 +5,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/samber/lo"
 	"seanime/internal/api/anilist"
+	"seanime/internal/eventstream"
 	"seanime/internal/platforms/platform"
 	"seanime/internal/sync"
 )
@@ -123,6 +124,23 @@ func rearrangeMangaCollectionLists(mangaCollection *anilist.MangaCollection) {
 // UpdateEntry updates the entry for the given media ID.
 // It doesn't add the entry if it doesn't exist.
 func (lp *LocalPlatform) UpdateEntry(mediaID int, status *anilist.MediaListStatus, scoreRaw *int, progress *int, startedAt *anilist.FuzzyDateInput, completedAt *anilist.FuzzyDateInput) error {
+	if err := lp.updateEntry(mediaID, status, scoreRaw, progress, startedAt, completedAt); err != nil {
+		return err
+	}
+
+	payload := &eventstream.ListEntryChangedPayload{
+		MediaId:  mediaID,
+		Progress: progress,
+		Score:    scoreRaw,
+	}
+	if status != nil {
+		payload.Status = string(*status)
+	}
+	eventstream.GlobalBroker.Publish(eventstream.TypeListEntryChanged, payload)
+	return nil
+}
+
+func (lp *LocalPlatform) updateEntry(mediaID int, status *anilist.MediaListStatus, scoreRaw *int, progress *int, startedAt *anilist.FuzzyDateInput, completedAt *anilist.FuzzyDateInput) error {
 	if lp.syncManager.GetLocalAnimeCollection().IsPresent() {
 		animeCollection := lp.syncManager.GetLocalAnimeCollection().MustGet()
 
@@ -211,6 +229,18 @@ func (lp *LocalPlatform) UpdateEntry(mediaID int, status *anilist.MediaListStatu
 }
 
 func (lp *LocalPlatform) UpdateEntryProgress(mediaID int, progress int, totalEpisodes *int) error {
+	if err := lp.updateEntryProgress(mediaID, progress, totalEpisodes); err != nil {
+		return err
+	}
+
+	eventstream.GlobalBroker.Publish(eventstream.TypeListEntryChanged, &eventstream.ListEntryChangedPayload{
+		MediaId:  mediaID,
+		Progress: &progress,
+	})
+	return nil
+}
+
+func (lp *LocalPlatform) updateEntryProgress(mediaID int, progress int, totalEpisodes *int) error {
 	if lp.syncManager.GetLocalAnimeCollection().IsPresent() {
 		animeCollection := lp.syncManager.GetLocalAnimeCollection().MustGet()
 
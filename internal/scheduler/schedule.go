@@ -0,0 +1,200 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const minInterval = time.Minute
+
+// Schedule returns the next run time of a job.
+type Schedule interface {
+	// Next returns the first run time after t.
+	Next(t time.Time) time.Time
+}
+
+// ParseSchedule parses a cron expression or an interval.
+//
+// Supported formats:
+//   - 5-field cron expressions: "minute hour day-of-month month day-of-week", e.g. "0 4 * * 1-5".
+//     Fields accept "*", values, ranges ("1-5"), lists ("1,3") and steps ("*/15", "0-30/10").
+//     Day of week is 0-7, 0 and 7 are Sunday.
+//   - Descriptors: "@hourly", "@daily", "@midnight", "@weekly", "@monthly".
+//   - Intervals: "@every 30m", using Go durations. The minimum interval is 1 minute.
+func ParseSchedule(value string) (Schedule, error) {
+	value = strings.TrimSpace(value)
+
+	if rest, ok := strings.CutPrefix(value, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval: %w", err)
+		}
+		if interval < minInterval {
+			return nil, errors.New("interval must be at least 1 minute")
+		}
+		return &intervalSchedule{interval: interval}, nil
+	}
+
+	switch value {
+	case "@hourly":
+		value = "0 * * * *"
+	case "@daily", "@midnight":
+		value = "0 0 * * *"
+	case "@weekly":
+		value = "0 0 * * 0"
+	case "@monthly":
+		value = "0 0 1 * *"
+	}
+
+	return parseCron(value)
+}
+
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (s *intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+// cronSchedule stores the allowed values of each field as bit sets.
+type cronSchedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+	// If both day fields are restricted, a day matches if either of them matches
+	anyDay bool
+}
+
+type cronField struct {
+	min int
+	max int
+}
+
+var (
+	minuteField     = cronField{0, 59}
+	hourField       = cronField{0, 23}
+	dayOfMonthField = cronField{1, 31}
+	monthField      = cronField{1, 12}
+	dayOfWeekField  = cronField{0, 7}
+)
+
+func parseCron(value string) (*cronSchedule, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q, expected 5 fields", value)
+	}
+
+	ret := &cronSchedule{}
+	var err error
+	if ret.minutes, err = parseCronField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if ret.hours, err = parseCronField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if ret.daysOfMonth, err = parseCronField(fields[2], dayOfMonthField); err != nil {
+		return nil, err
+	}
+	if ret.months, err = parseCronField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if ret.daysOfWeek, err = parseCronField(fields[4], dayOfWeekField); err != nil {
+		return nil, err
+	}
+	// 7 is Sunday
+	if ret.daysOfWeek&(1<<7) != 0 {
+		ret.daysOfWeek |= 1
+	}
+	ret.anyDay = !strings.HasPrefix(fields[2], "*") && !strings.HasPrefix(fields[4], "*")
+
+	return ret, nil
+}
+
+func parseCronField(value string, field cronField) (uint64, error) {
+	var ret uint64
+	for _, part := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		start, end := field.min, field.max
+		if rangePart != "*" {
+			startPart, endPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			start, err = strconv.Atoi(startPart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value in %q", part)
+			}
+			end = start
+			if isRange {
+				end, err = strconv.Atoi(endPart)
+				if err != nil {
+					return 0, fmt.Errorf("invalid value in %q", part)
+				}
+			} else if hasStep {
+				// "5/15" means every 15 starting at 5
+				end = field.max
+			}
+		}
+
+		if start < field.min || end > field.max || start > end {
+			return 0, fmt.Errorf("value out of range in %q, expected %d-%d", part, field.min, field.max)
+		}
+
+		for i := start; i <= end; i += step {
+			ret |= 1 << uint(i)
+		}
+	}
+	return ret, nil
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// Give up if nothing matches within 5 years, e.g. "0 0 30 2 *"
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	dom := s.daysOfMonth&(1<<uint(t.Day())) != 0
+	dow := s.daysOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.anyDay {
+		return dom || dow
+	}
+	return dom && dow
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// 2024-01-05 is a Friday
+	from := time.Date(2024, 1, 5, 10, 30, 20, 0, time.UTC)
+
+	tests := []struct {
+		schedule string
+		expected time.Time
+	}{
+		{"@every 10m", from.Add(10 * time.Minute)},
+		{"@every 1h30m", from.Add(90 * time.Minute)},
+		{"* * * * *", time.Date(2024, 1, 5, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 5, 10, 45, 0, 0, time.UTC)},
+		{"0 4 * * *", time.Date(2024, 1, 6, 4, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2024, 1, 6, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2024, 1, 5, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * 1,3", time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Either day field matches if both are restricted
+		{"0 0 10 * 6", time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 5, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.schedule)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, schedule.Next(from))
+		})
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every 30s",
+		"@every soon",
+		"@yearly",
+	} {
+		_, err := ParseSchedule(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestCronSchedule_NeverMatches(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
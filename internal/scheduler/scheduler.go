@@ -0,0 +1,435 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"seanime/internal/database/db"
+	"seanime/internal/database/models"
+	"seanime/internal/events"
+	"seanime/internal/notifier"
+	"seanime/internal/util"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+
+	RunStatusSuccess = "success"
+	RunStatusFailed  = "failed"
+	RunStatusSkipped = "skipped"
+
+	// maxRunHistory is the number of runs kept per job.
+	maxRunHistory = 50
+)
+
+var (
+	ErrJobNotFound = errors.New("scheduler: job not found")
+	ErrJobRunning  = errors.New("scheduler: job is already running")
+	// ErrSkipped should be wrapped by jobs that had nothing to do, e.g. because the library path is not set.
+	ErrSkipped = errors.New("skipped")
+	// ErrDisabled should be wrapped by jobs whose feature is turned off, the run is not recorded.
+	ErrDisabled = errors.New("disabled")
+)
+
+type (
+	// Scheduler runs named jobs on user-configurable schedules and keeps their run history.
+	Scheduler struct {
+		logger         *zerolog.Logger
+		database       *db.Database
+		wsEventManager events.WSEventManagerInterface
+		jobs           []*registeredJob
+		jobsByName     map[string]*registeredJob
+		updateCh       chan struct{}
+		ctx            context.Context
+		cancel         context.CancelFunc
+		started        bool
+		now            func() time.Time
+		mu             sync.Mutex
+	}
+
+	NewSchedulerOptions struct {
+		Logger         *zerolog.Logger
+		Database       *db.Database // Optional, the schedules and the run history are not persisted without it
+		WSEventManager events.WSEventManagerInterface
+	}
+
+	Job struct {
+		// Unique name of the job, e.g. "refresh-anilist"
+		Name        string
+		Description string
+		// Cron expression or interval used until the user changes it, see ParseSchedule
+		DefaultSchedule string
+		DefaultEnabled  bool
+		Run             func(ctx context.Context) error
+	}
+
+	// JobInfo is the state of a job returned to the client.
+	JobInfo struct {
+		Name            string                  `json:"name"`
+		Description     string                  `json:"description"`
+		Schedule        string                  `json:"schedule"`
+		DefaultSchedule string                  `json:"defaultSchedule"`
+		Enabled         bool                    `json:"enabled"`
+		Running         bool                    `json:"running"`
+		NextRun         *time.Time              `json:"nextRun,omitempty"`
+		LastRun         *models.ScheduledJobRun `json:"lastRun,omitempty"`
+	}
+
+	registeredJob struct {
+		job      *Job
+		schedule Schedule
+		// The user-defined schedule, the default schedule is used if empty
+		scheduleValue string
+		enabled       bool
+		running       bool
+		nextRun       time.Time
+		lastRun       *models.ScheduledJobRun
+	}
+)
+
+func NewScheduler(opts *NewSchedulerOptions) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		logger:         opts.Logger,
+		database:       opts.Database,
+		wsEventManager: opts.WSEventManager,
+		jobs:           make([]*registeredJob, 0),
+		jobsByName:     make(map[string]*registeredJob),
+		updateCh:       make(chan struct{}, 1),
+		ctx:            ctx,
+		cancel:         cancel,
+		now:            time.Now,
+	}
+}
+
+// Register adds a job to the scheduler.
+// The schedule saved by the user is used if there is one.
+func (s *Scheduler) Register(job *Job) error {
+	defaultSchedule, err := ParseSchedule(job.DefaultSchedule)
+	if err != nil {
+		return fmt.Errorf("scheduler: invalid default schedule for %s: %w", job.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.jobsByName[job.Name]; found {
+		return fmt.Errorf("scheduler: job %s is already registered", job.Name)
+	}
+
+	rj := &registeredJob{
+		job:      job,
+		schedule: defaultSchedule,
+		enabled:  job.DefaultEnabled,
+	}
+
+	if s.database != nil {
+		if saved, err := s.database.GetScheduledJob(job.Name); err == nil {
+			rj.enabled = saved.Enabled
+			if saved.Schedule != "" {
+				if schedule, err := ParseSchedule(saved.Schedule); err == nil {
+					rj.schedule = schedule
+					rj.scheduleValue = saved.Schedule
+				} else {
+					s.logger.Warn().Err(err).Str("job", job.Name).Msg("scheduler: Invalid saved schedule, using the default schedule")
+				}
+			}
+		}
+		if runs, err := s.database.GetScheduledJobRuns(job.Name, 1); err == nil && len(runs) > 0 {
+			rj.lastRun = runs[0]
+		}
+	}
+
+	rj.nextRun = rj.schedule.Next(s.now())
+
+	s.jobs = append(s.jobs, rj)
+	s.jobsByName[job.Name] = rj
+	s.notifyLoop()
+
+	return nil
+}
+
+// Start runs the scheduler loop in a goroutine.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+
+	go s.loop()
+}
+
+// Stop stops the scheduler loop and cancels the running jobs.
+func (s *Scheduler) Stop() {
+	s.cancel()
+}
+
+// GetJob returns the state of a job.
+func (s *Scheduler) GetJob(name string) (*JobInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rj, found := s.jobsByName[name]
+	if !found {
+		return nil, ErrJobNotFound
+	}
+	return rj.info(), nil
+}
+
+// GetJobs returns the registered jobs in registration order.
+func (s *Scheduler) GetJobs() []*JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ret := make([]*JobInfo, 0, len(s.jobs))
+	for _, rj := range s.jobs {
+		ret = append(ret, rj.info())
+	}
+	return ret
+}
+
+// UpdateJob saves the schedule of a job and enables or disables it.
+// An empty schedule resets the job to its default schedule.
+func (s *Scheduler) UpdateJob(name string, schedule string, enabled bool) (*JobInfo, error) {
+	var parsed Schedule
+	if schedule != "" {
+		var err error
+		parsed, err = ParseSchedule(schedule)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	rj, found := s.jobsByName[name]
+	if !found {
+		s.mu.Unlock()
+		return nil, ErrJobNotFound
+	}
+
+	if s.database != nil {
+		err := s.database.UpsertScheduledJob(&models.ScheduledJob{
+			Name:     name,
+			Schedule: schedule,
+			Enabled:  enabled,
+		})
+		if err != nil {
+			s.mu.Unlock()
+			return nil, err
+		}
+	}
+
+	if parsed == nil {
+		parsed, _ = ParseSchedule(rj.job.DefaultSchedule)
+	}
+	rj.schedule = parsed
+	rj.scheduleValue = schedule
+	rj.enabled = enabled
+	rj.nextRun = rj.schedule.Next(s.now())
+	info := rj.info()
+	s.notifyLoop()
+	s.mu.Unlock()
+
+	s.logger.Debug().Str("job", name).Str("schedule", info.Schedule).Bool("enabled", enabled).Msg("scheduler: Job updated")
+	s.wsEventManager.SendEvent(events.ScheduledJobsUpdated, nil)
+
+	return info, nil
+}
+
+// SetJobSchedule changes the schedule of a job without changing whether it's enabled.
+func (s *Scheduler) SetJobSchedule(name string, schedule string) error {
+	s.mu.Lock()
+	rj, found := s.jobsByName[name]
+	if !found {
+		s.mu.Unlock()
+		return ErrJobNotFound
+	}
+	enabled := rj.enabled
+	s.mu.Unlock()
+
+	_, err := s.UpdateJob(name, schedule, enabled)
+	return err
+}
+
+// RunNow runs a job immediately, even if it's disabled.
+// The job runs in the background.
+func (s *Scheduler) RunNow(name string) error {
+	s.mu.Lock()
+	rj, found := s.jobsByName[name]
+	s.mu.Unlock()
+	if !found {
+		return ErrJobNotFound
+	}
+
+	return s.trigger(rj, TriggerManual)
+}
+
+// GetRunHistory returns the most recent runs of a job, newest first.
+func (s *Scheduler) GetRunHistory(name string) ([]*models.ScheduledJobRun, error) {
+	s.mu.Lock()
+	_, found := s.jobsByName[name]
+	s.mu.Unlock()
+	if !found {
+		return nil, ErrJobNotFound
+	}
+
+	if s.database == nil {
+		return []*models.ScheduledJobRun{}, nil
+	}
+
+	return s.database.GetScheduledJobRuns(name, maxRunHistory)
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// notifyLoop wakes up the loop so that it picks up the new run times.
+func (s *Scheduler) notifyLoop() {
+	select {
+	case s.updateCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) loop() {
+	defer util.HandlePanicInModuleThen("scheduler/loop", func() {})
+
+	for {
+		s.mu.Lock()
+		now := s.now()
+		due := make([]*registeredJob, 0)
+		var next time.Time
+		for _, rj := range s.jobs {
+			if !rj.enabled || rj.nextRun.IsZero() {
+				continue
+			}
+			if !rj.nextRun.After(now) {
+				due = append(due, rj)
+				rj.nextRun = rj.schedule.Next(now)
+			}
+			if !rj.nextRun.IsZero() && (next.IsZero() || rj.nextRun.Before(next)) {
+				next = rj.nextRun
+			}
+		}
+		s.mu.Unlock()
+
+		for _, rj := range due {
+			if err := s.trigger(rj, TriggerSchedule); err != nil {
+				s.logger.Debug().Err(err).Str("job", rj.job.Name).Msg("scheduler: Skipped scheduled run")
+			}
+		}
+
+		wait := time.Hour
+		if !next.IsZero() {
+			wait = next.Sub(now)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-s.updateCh:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// trigger runs a job in a goroutine and records the run.
+func (s *Scheduler) trigger(rj *registeredJob, trigger string) error {
+	s.mu.Lock()
+	if rj.running {
+		s.mu.Unlock()
+		return ErrJobRunning
+	}
+	rj.running = true
+	s.mu.Unlock()
+
+	s.wsEventManager.SendEvent(events.ScheduledJobsUpdated, nil)
+
+	go func() {
+		run := &models.ScheduledJobRun{
+			JobName:   rj.job.Name,
+			Trigger:   trigger,
+			StartedAt: s.now(),
+		}
+
+		s.logger.Debug().Str("job", rj.job.Name).Str("trigger", trigger).Msg("scheduler: Running job")
+
+		err := s.execute(rj.job)
+		run.Duration = s.now().Sub(run.StartedAt).Milliseconds()
+
+		if errors.Is(err, ErrDisabled) {
+			s.logger.Trace().Err(err).Str("job", rj.job.Name).Msg("scheduler: Job is disabled by its feature, not recording the run")
+			s.mu.Lock()
+			rj.running = false
+			s.mu.Unlock()
+			s.wsEventManager.SendEvent(events.ScheduledJobsUpdated, nil)
+			return
+		}
+
+		switch {
+		case err == nil:
+			run.Status = RunStatusSuccess
+		case errors.Is(err, ErrSkipped):
+			run.Status = RunStatusSkipped
+			run.Error = err.Error()
+		default:
+			run.Status = RunStatusFailed
+			run.Error = err.Error()
+			s.logger.Error().Err(err).Str("job", rj.job.Name).Msg("scheduler: Job failed")
+			notifier.GlobalDispatcher.Dispatch(notifier.EventError, map[string]interface{}{
+				"source": rj.job.Description,
+				"error":  err.Error(),
+			})
+		}
+
+		if s.database != nil {
+			if err := s.database.InsertScheduledJobRun(run); err != nil {
+				s.logger.Error().Err(err).Str("job", rj.job.Name).Msg("scheduler: Failed to save job run")
+			}
+			s.database.TrimScheduledJobRuns(rj.job.Name, maxRunHistory)
+		}
+
+		s.mu.Lock()
+		rj.running = false
+		rj.lastRun = run
+		s.mu.Unlock()
+
+		s.wsEventManager.SendEvent(events.ScheduledJobsUpdated, nil)
+	}()
+
+	return nil
+}
+
+func (s *Scheduler) execute(job *Job) (err error) {
+	defer util.HandlePanicInModuleWithError("scheduler/"+job.Name, &err)
+	return job.Run(s.ctx)
+}
+
+func (rj *registeredJob) info() *JobInfo {
+	ret := &JobInfo{
+		Name:            rj.job.Name,
+		Description:     rj.job.Description,
+		Schedule:        rj.scheduleValue,
+		DefaultSchedule: rj.job.DefaultSchedule,
+		Enabled:         rj.enabled,
+		Running:         rj.running,
+		LastRun:         rj.lastRun,
+	}
+	if ret.Schedule == "" {
+		ret.Schedule = rj.job.DefaultSchedule
+	}
+	if rj.enabled && !rj.nextRun.IsZero() {
+		ret.NextRun = new(time.Time)
+		*ret.NextRun = rj.nextRun
+	}
+	return ret
+}
@@ -0,0 +1,220 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"seanime/internal/database/db"
+	"seanime/internal/events"
+	"seanime/internal/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestScheduler(t *testing.T, database *db.Database) *Scheduler {
+	logger := util.NewLogger()
+	s := NewScheduler(&NewSchedulerOptions{
+		Logger:         logger,
+		Database:       database,
+		WSEventManager: events.NewMockWSEventManager(logger),
+	})
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func newTestDatabase(t *testing.T) *db.Database {
+	database, err := db.NewDatabase(t.TempDir(), "test", util.NewLogger())
+	require.NoError(t, err)
+	return database
+}
+
+// waitForRun waits until the job isn't running anymore and has a run recorded.
+func waitForRun(t *testing.T, s *Scheduler, name string) *JobInfo {
+	var ret *JobInfo
+	require.Eventually(t, func() bool {
+		for _, info := range s.GetJobs() {
+			if info.Name == name && !info.Running && info.LastRun != nil {
+				ret = info
+				return true
+			}
+		}
+		return false
+	}, 2*time.Second, 5*time.Millisecond)
+	return ret
+}
+
+func TestScheduler_RunNow(t *testing.T) {
+	database := newTestDatabase(t)
+	s := newTestScheduler(t, database)
+
+	runs := 0
+	require.NoError(t, s.Register(&Job{
+		Name:            "job",
+		DefaultSchedule: "@every 1h",
+		Run: func(ctx context.Context) error {
+			runs++
+			if runs == 2 {
+				return fmt.Errorf("disabled: %w", ErrSkipped)
+			}
+			if runs == 3 {
+				return errors.New("boom")
+			}
+			return nil
+		},
+	}))
+
+	expected := []string{RunStatusSuccess, RunStatusSkipped, RunStatusFailed}
+	for _, status := range expected {
+		require.NoError(t, s.RunNow("job"))
+		info := waitForRun(t, s, "job")
+		assert.Equal(t, status, info.LastRun.Status)
+		assert.Equal(t, TriggerManual, info.LastRun.Trigger)
+		s.mu.Lock()
+		s.jobsByName["job"].lastRun = nil
+		s.mu.Unlock()
+	}
+
+	history, err := s.GetRunHistory("job")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	// Newest first
+	assert.Equal(t, RunStatusFailed, history[0].Status)
+	assert.Equal(t, "boom", history[0].Error)
+
+	assert.ErrorIs(t, s.RunNow("unknown"), ErrJobNotFound)
+}
+
+func TestScheduler_Panic(t *testing.T) {
+	s := newTestScheduler(t, nil)
+
+	require.NoError(t, s.Register(&Job{
+		Name:            "job",
+		DefaultSchedule: "@every 1h",
+		Run: func(ctx context.Context) error {
+			panic("oops")
+		},
+	}))
+
+	require.NoError(t, s.RunNow("job"))
+	info := waitForRun(t, s, "job")
+	assert.Equal(t, RunStatusFailed, info.LastRun.Status)
+}
+
+func TestScheduler_Schedule(t *testing.T) {
+	s := newTestScheduler(t, nil)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time {
+		return now
+	}
+
+	ran := make(chan struct{}, 1)
+	require.NoError(t, s.Register(&Job{
+		Name:            "job",
+		DefaultSchedule: "@every 10m",
+		DefaultEnabled:  true,
+		Run: func(ctx context.Context) error {
+			ran <- struct{}{}
+			return nil
+		},
+	}))
+	require.NoError(t, s.Register(&Job{
+		Name:            "disabled",
+		DefaultSchedule: "@every 10m",
+		Run: func(ctx context.Context) error {
+			t.Error("disabled job should not run")
+			return nil
+		},
+	}))
+
+	jobs := s.GetJobs()
+	require.NotNil(t, jobs[0].NextRun)
+	assert.Equal(t, now.Add(10*time.Minute), *jobs[0].NextRun)
+	assert.Nil(t, jobs[1].NextRun)
+
+	// The job is due once the clock passes the next run time
+	s.mu.Lock()
+	now = now.Add(10 * time.Minute)
+	s.mu.Unlock()
+	s.Start()
+
+	select {
+	case <-ran:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job did not run")
+	}
+	info := waitForRun(t, s, "job")
+	assert.Equal(t, TriggerSchedule, info.LastRun.Trigger)
+	assert.Equal(t, now.Add(10*time.Minute), *info.NextRun)
+}
+
+func TestScheduler_UpdateJob(t *testing.T) {
+	database := newTestDatabase(t)
+	s := newTestScheduler(t, database)
+
+	job := &Job{
+		Name:            "job",
+		DefaultSchedule: "@every 1h",
+		DefaultEnabled:  true,
+		Run: func(ctx context.Context) error {
+			return nil
+		},
+	}
+	require.NoError(t, s.Register(job))
+
+	_, err := s.UpdateJob("job", "invalid", true)
+	assert.Error(t, err)
+	_, err = s.UpdateJob("unknown", "", true)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+
+	info, err := s.UpdateJob("job", "0 4 * * *", false)
+	require.NoError(t, err)
+	assert.Equal(t, "0 4 * * *", info.Schedule)
+	assert.False(t, info.Enabled)
+	assert.Nil(t, info.NextRun)
+
+	// The saved schedule is used after a restart
+	s2 := newTestScheduler(t, database)
+	require.NoError(t, s2.Register(job))
+	info = s2.GetJobs()[0]
+	assert.Equal(t, "0 4 * * *", info.Schedule)
+	assert.False(t, info.Enabled)
+
+	// An empty schedule resets the default schedule
+	info, err = s2.UpdateJob("job", "", true)
+	require.NoError(t, err)
+	assert.Equal(t, "@every 1h", info.Schedule)
+	assert.NotNil(t, info.NextRun)
+}
+
+func TestScheduler_DisabledRunsAreNotRecorded(t *testing.T) {
+	database := newTestDatabase(t)
+	s := newTestScheduler(t, database)
+
+	done := make(chan struct{}, 1)
+	require.NoError(t, s.Register(&Job{
+		Name:            "job",
+		DefaultSchedule: "@every 1h",
+		Run: func(ctx context.Context) error {
+			defer func() { done <- struct{}{} }()
+			return fmt.Errorf("feature is off: %w", ErrDisabled)
+		},
+	}))
+
+	require.NoError(t, s.RunNow("job"))
+	<-done
+	require.Eventually(t, func() bool {
+		info, err := s.GetJob("job")
+		return err == nil && !info.Running
+	}, 2*time.Second, 5*time.Millisecond)
+
+	info, err := s.GetJob("job")
+	require.NoError(t, err)
+	assert.Nil(t, info.LastRun)
+	history, err := s.GetRunHistory("job")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
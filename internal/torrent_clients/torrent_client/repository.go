@@ -31,6 +31,13 @@ type (
 		metadataProvider            metadata.Provider
 		activeTorrentCountCtxCancel context.CancelFunc
 		activeTorrentCount          *ActiveCount
+		// Download limit set in the client before SetDownloadRateLimit overrode it
+		originalDownloadLimit *downloadLimit
+	}
+
+	downloadLimit struct {
+		bytesPerSecond int64
+		enabled        bool
 	}
 
 	NewRepositoryOptions struct {
@@ -332,6 +339,77 @@ func (r *Repository) ResumeTorrents(hashes []string) error {
 	return nil
 }
 
+// SetDownloadRateLimit sets the global download limit of the torrent client, in bytes per second.
+// The limit set in the client beforehand is restored when bytesPerSecond is 0.
+func (r *Repository) SetDownloadRateLimit(bytesPerSecond int64) error {
+	if bytesPerSecond <= 0 && r.originalDownloadLimit == nil {
+		return nil
+	}
+
+	limit := &downloadLimit{bytesPerSecond: bytesPerSecond, enabled: true}
+	if bytesPerSecond <= 0 {
+		limit = r.originalDownloadLimit
+	}
+
+	var err error
+	switch r.provider {
+	case QbittorrentClient:
+		if r.originalDownloadLimit == nil {
+			var current int
+			current, err = r.qBittorrentClient.Transfer.GetGlobalDownloadLimit()
+			if err != nil {
+				break
+			}
+			r.originalDownloadLimit = &downloadLimit{bytesPerSecond: int64(current), enabled: current > 0}
+		}
+		// qBittorrent uses 0 for no limit
+		value := 0
+		if limit.enabled {
+			value = int(limit.bytesPerSecond)
+		}
+		err = r.qBittorrentClient.Transfer.SetGlobalDownloadLimit(value)
+	case TransmissionClient:
+		if r.originalDownloadLimit == nil {
+			var session transmissionrpc.SessionArguments
+			session, err = r.transmission.Client.SessionArgumentsGet(context.Background(), []string{"speed-limit-down", "speed-limit-down-enabled"})
+			if err != nil {
+				break
+			}
+			r.originalDownloadLimit = &downloadLimit{}
+			if session.SpeedLimitDown != nil {
+				r.originalDownloadLimit.bytesPerSecond = *session.SpeedLimitDown * 1000
+			}
+			if session.SpeedLimitDownEnabled != nil {
+				r.originalDownloadLimit.enabled = *session.SpeedLimitDownEnabled
+			}
+		}
+		// Transmission uses kB/s
+		kbps := limit.bytesPerSecond / 1000
+		if limit.enabled && kbps < 1 {
+			kbps = 1
+		}
+		err = r.transmission.Client.SessionArgumentsSet(context.Background(), transmissionrpc.SessionArguments{
+			SpeedLimitDown:        &kbps,
+			SpeedLimitDownEnabled: &limit.enabled,
+		})
+	default:
+		return nil
+	}
+
+	if err != nil {
+		r.logger.Err(err).Msg("torrent client: Error while setting download limit")
+		return err
+	}
+
+	if bytesPerSecond <= 0 {
+		r.originalDownloadLimit = nil
+	}
+
+	r.logger.Debug().Int64("bytesPerSecond", bytesPerSecond).Msg("torrent client: Set download limit")
+
+	return nil
+}
+
 func (r *Repository) DeselectFiles(hash string, indices []int) error {
 
 	var err error
@@ -17,16 +17,19 @@ const (
 
 type (
 	Torrent struct {
-		Name        string        `json:"name"`
-		Hash        string        `json:"hash"`
-		Seeds       int           `json:"seeds"`
-		UpSpeed     string        `json:"upSpeed"`
-		DownSpeed   string        `json:"downSpeed"`
-		Progress    float64       `json:"progress"`
-		Size        string        `json:"size"`
-		Eta         string        `json:"eta"`
-		Status      TorrentStatus `json:"status"`
-		ContentPath string        `json:"contentPath"`
+		Name           string        `json:"name"`
+		Hash           string        `json:"hash"`
+		Seeds          int           `json:"seeds"`
+		UpSpeed        string        `json:"upSpeed"`
+		DownSpeed      string        `json:"downSpeed"`
+		Progress       float64       `json:"progress"`
+		Size           string        `json:"size"`
+		Eta            string        `json:"eta"`
+		Status         TorrentStatus `json:"status"`
+		ContentPath    string        `json:"contentPath"`
+		SizeBytes      int64         `json:"sizeBytes"`
+		DownSpeedBytes int64         `json:"downSpeedBytes"` // Bytes per second
+		EtaSeconds     int64         `json:"etaSeconds"`     // -1 if unknown
 	}
 	TorrentStatus string
 )
@@ -69,6 +72,7 @@ func (r *Repository) FromTransmissionTorrent(t *transmissionrpc.Torrent) *Torren
 	torrent.DownSpeed = "0 KB/s"
 	if t.RateDownload != nil {
 		torrent.DownSpeed = util.ToHumanReadableSpeed(int(*t.RateDownload))
+		torrent.DownSpeedBytes = *t.RateDownload
 	}
 
 	torrent.Progress = 0.0
@@ -79,11 +83,14 @@ func (r *Repository) FromTransmissionTorrent(t *transmissionrpc.Torrent) *Torren
 	torrent.Size = "N/A"
 	if t.TotalSize != nil {
 		torrent.Size = humanize.Bytes(uint64(*t.TotalSize))
+		torrent.SizeBytes = int64(t.TotalSize.Byte())
 	}
 
 	torrent.Eta = "???"
+	torrent.EtaSeconds = -1
 	if t.ETA != nil {
 		torrent.Eta = util.FormatETA(int(*t.ETA))
+		torrent.EtaSeconds = *t.ETA
 	}
 
 	torrent.ContentPath = ""
@@ -134,6 +141,9 @@ func (r *Repository) FromQbitTorrent(t *qbittorrent_model.Torrent) *Torrent {
 	torrent.Eta = util.FormatETA(t.Eta)
 	torrent.ContentPath = t.ContentPath
 	torrent.Status = fromQbitTorrentStatus(t.State)
+	torrent.SizeBytes = int64(t.Size)
+	torrent.DownSpeedBytes = int64(t.Dlspeed)
+	torrent.EtaSeconds = int64(t.Eta)
 
 	return torrent
 }
@@ -0,0 +1,330 @@
+package seadex
+
+import (
+	"errors"
+	"fmt"
+	"github.com/goccy/go-json"
+	"github.com/samber/lo"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"seanime/internal/api/anilist"
+	"seanime/internal/library/anime"
+	"seanime/internal/torrents/nyaa"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// recordsBatchSize is the number of media fetched per SeaDex request
+	recordsBatchSize = 50
+)
+
+type (
+	// LibraryAnalysis is the result of comparing the local library against SeaDex's best and alternative releases.
+	LibraryAnalysis struct {
+		Entries []*LibraryAnalysisEntry `json:"entries"`
+		// Media in the library that have no SeaDex entry
+		UnlistedMediaIds []int `json:"unlistedMediaIds"`
+		// Total size of the recommended releases for entries that are not on a best release
+		TotalUpgradeSize int64  `json:"totalUpgradeSize"`
+		AnalyzedAt       string `json:"analyzedAt"`
+	}
+
+	LibraryAnalysisEntry struct {
+		MediaId int                  `json:"mediaId"`
+		Media   *anilist.BaseAnime   `json:"media"`
+		Status  LibraryReleaseStatus `json:"status"`
+		// Release groups parsed from the local files
+		LocalReleaseGroups []string `json:"localReleaseGroups"`
+		// Number of distinct main episodes in the library
+		LocalEpisodeCount int `json:"localEpisodeCount"`
+		// Number of episodes of the media, 0 if unknown
+		TotalEpisodeCount int   `json:"totalEpisodeCount"`
+		LocalSize         int64 `json:"localSize"`
+		// SeaDex release the local files belong to, if any
+		MatchedRelease *Torrent `json:"matchedRelease,omitempty"`
+		// Best release that should be downloaded to upgrade
+		Recommended  *Torrent   `json:"recommended,omitempty"`
+		Alternatives []*Torrent `json:"alternatives"`
+		// Size of the recommended release
+		UpgradeSize int64 `json:"upgradeSize"`
+		// Difference in disk usage after replacing the local files with the recommended release
+		SizeDifference int64 `json:"sizeDifference"`
+		// Whether SeaDex marks the entry as incomplete
+		Incomplete bool   `json:"incomplete"`
+		Notes      string `json:"notes"`
+	}
+
+	LibraryReleaseStatus string
+
+	// LibraryUpgrade is a release to download in place of the local files of a media.
+	LibraryUpgrade struct {
+		MediaId int      `json:"mediaId"`
+		Torrent *Torrent `json:"torrent"`
+	}
+
+	AnalyzeLibraryOptions struct {
+		LocalFiles      []*anime.LocalFile
+		AnimeCollection *anilist.AnimeCollection
+		// If true, dual audio releases are recommended over other best releases
+		PreferDualAudio bool
+	}
+)
+
+const (
+	// LibraryReleaseStatusBest means the local files belong to a best release
+	LibraryReleaseStatusBest LibraryReleaseStatus = "best"
+	// LibraryReleaseStatusAlt means the local files belong to an alternative release
+	LibraryReleaseStatusAlt LibraryReleaseStatus = "alt"
+	// LibraryReleaseStatusUpgradable means the local files don't belong to any SeaDex release
+	LibraryReleaseStatusUpgradable LibraryReleaseStatus = "upgradable"
+)
+
+// AnalyzeLibrary cross-references the local files of each media with SeaDex's records.
+// Local files are matched to a release by file name, falling back to the parsed release group.
+func (s *SeaDex) AnalyzeLibrary(opts *AnalyzeLibraryOptions) (*LibraryAnalysis, error) {
+	if opts.AnimeCollection == nil {
+		return nil, errors.New("seadex: anime collection is required")
+	}
+
+	ret := &LibraryAnalysis{
+		Entries:          make([]*LibraryAnalysisEntry, 0),
+		UnlistedMediaIds: make([]int, 0),
+		AnalyzedAt:       time.Now().Format(time.RFC3339),
+	}
+
+	groupedLfs := anime.GroupLocalFilesByMediaID(lo.Filter(opts.LocalFiles, func(lf *anime.LocalFile, _ int) bool {
+		return lf.MediaId != 0 && !lf.IsIgnored()
+	}))
+	if len(groupedLfs) == 0 {
+		return ret, nil
+	}
+
+	mediaIds := lo.Keys(groupedLfs)
+	sort.Ints(mediaIds)
+
+	records, err := s.fetchRecordsByMediaIds(mediaIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mediaId := range mediaIds {
+		record, found := records[mediaId]
+		if !found {
+			ret.UnlistedMediaIds = append(ret.UnlistedMediaIds, mediaId)
+			continue
+		}
+		media, _ := opts.AnimeCollection.FindAnime(mediaId)
+
+		entry := s.analyzeEntry(media, record, groupedLfs[mediaId], opts.PreferDualAudio)
+		if entry == nil {
+			ret.UnlistedMediaIds = append(ret.UnlistedMediaIds, mediaId)
+			continue
+		}
+		entry.MediaId = mediaId
+		if entry.Status != LibraryReleaseStatusBest {
+			ret.TotalUpgradeSize += entry.UpgradeSize
+		}
+		ret.Entries = append(ret.Entries, entry)
+	}
+
+	s.logger.Debug().Int("entries", len(ret.Entries)).Int("unlisted", len(ret.UnlistedMediaIds)).Msg("seadex: Library analysis complete")
+
+	return ret, nil
+}
+
+// analyzeEntry compares the local files of a media with its SeaDex record.
+// It returns nil if the record has no usable release.
+func (s *SeaDex) analyzeEntry(media *anilist.BaseAnime, record *RecordItem, lfs []*anime.LocalFile, preferDualAudio bool) *LibraryAnalysisEntry {
+	title := ""
+	if media != nil {
+		title = media.GetRomajiTitleSafe()
+	}
+
+	trs := lo.Filter(record.Expand.Trs, func(tr *Tr, _ int) bool {
+		return isTrAvailable(tr)
+	})
+	if len(trs) == 0 {
+		return nil
+	}
+
+	entry := &LibraryAnalysisEntry{
+		Media:              media,
+		LocalReleaseGroups: make([]string, 0),
+		Alternatives:       make([]*Torrent, 0),
+		Incomplete:         record.Incomplete,
+		Notes:              record.Notes,
+	}
+	if media != nil {
+		entry.TotalEpisodeCount = media.GetCurrentEpisodeCount()
+	}
+
+	// Local files
+	episodes := make(map[int]struct{})
+	for _, lf := range lfs {
+		if lf.IsMain() {
+			episodes[lf.GetEpisodeNumber()] = struct{}{}
+		}
+		if lf.GetParsedData() != nil && lf.GetParsedData().ReleaseGroup != "" {
+			entry.LocalReleaseGroups = append(entry.LocalReleaseGroups, lf.GetParsedData().ReleaseGroup)
+		}
+		if info, err := os.Stat(lf.GetPath()); err == nil {
+			entry.LocalSize += info.Size()
+		}
+	}
+	entry.LocalEpisodeCount = len(episodes)
+	entry.LocalReleaseGroups = lo.Uniq(entry.LocalReleaseGroups)
+
+	// Releases
+	var bests []*Tr
+	for _, tr := range trs {
+		if tr.IsBest {
+			bests = append(bests, tr)
+		} else {
+			entry.Alternatives = append(entry.Alternatives, s.toTorrent(tr, title))
+		}
+	}
+	if len(bests) == 0 {
+		// Some entries only list alternatives, treat them as the best available
+		bests = trs
+		entry.Alternatives = make([]*Torrent, 0)
+	}
+
+	recommended := bests[0]
+	if preferDualAudio {
+		if tr, found := lo.Find(bests, func(tr *Tr) bool { return tr.DualAudio }); found {
+			recommended = tr
+		}
+	}
+	entry.Recommended = s.toTorrent(recommended, title)
+
+	// Match the local files to a release
+	matched := matchLocalFilesToRelease(lfs, trs)
+	switch {
+	case matched != nil && lo.Contains(bests, matched):
+		entry.Status = LibraryReleaseStatusBest
+	case matched != nil:
+		entry.Status = LibraryReleaseStatusAlt
+	default:
+		entry.Status = LibraryReleaseStatusUpgradable
+	}
+	if matched != nil {
+		entry.MatchedRelease = s.toTorrent(matched, title)
+	}
+
+	if entry.Status != LibraryReleaseStatusBest {
+		entry.UpgradeSize = entry.Recommended.Size
+		entry.SizeDifference = entry.Recommended.Size - entry.LocalSize
+	}
+
+	return entry
+}
+
+// matchLocalFilesToRelease returns the release all the main local files belong to.
+// A file belongs to a release if its name is in the release's file list or if its release group matches.
+// It returns nil if the files are spread across releases or if any of them is not from a listed release.
+func matchLocalFilesToRelease(lfs []*anime.LocalFile, trs []*Tr) *Tr {
+	mainLfs := lo.Filter(lfs, func(lf *anime.LocalFile, _ int) bool {
+		return lf.IsMain()
+	})
+	if len(mainLfs) == 0 {
+		mainLfs = lfs
+	}
+	if len(mainLfs) == 0 {
+		return nil
+	}
+
+	var ret *Tr
+	for _, lf := range mainLfs {
+		tr, found := lo.Find(trs, func(tr *Tr) bool {
+			return trHasFile(tr, lf.Name)
+		})
+		if !found && lf.GetParsedData() != nil {
+			tr, found = lo.Find(trs, func(tr *Tr) bool {
+				return normalizeReleaseGroup(tr.ReleaseGroup) != "" &&
+					normalizeReleaseGroup(tr.ReleaseGroup) == normalizeReleaseGroup(lf.GetParsedData().ReleaseGroup)
+			})
+		}
+		if !found {
+			return nil
+		}
+		if ret != nil && ret != tr {
+			return nil
+		}
+		ret = tr
+	}
+
+	return ret
+}
+
+func trHasFile(tr *Tr, name string) bool {
+	for _, f := range tr.Files {
+		if strings.EqualFold(filepath.Base(filepath.ToSlash(f.Name)), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeReleaseGroup(group string) string {
+	return strings.ToLower(strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '_' || r == '.' {
+			return -1
+		}
+		return r
+	}, group))
+}
+
+// fetchRecordsByMediaIds fetches the SeaDex records of multiple media, in batches.
+func (s *SeaDex) fetchRecordsByMediaIds(mediaIds []int) (map[int]*RecordItem, error) {
+	ret := make(map[int]*RecordItem)
+
+	for _, batch := range lo.Chunk(mediaIds, recordsBatchSize) {
+		filter := strings.Join(lo.Map(batch, func(id int, _ int) string {
+			return fmt.Sprintf("alID=%d", id)
+		}), "||")
+
+		query := url.Values{}
+		query.Set("page", "1")
+		query.Set("perPage", fmt.Sprintf("%d", len(batch)))
+		query.Set("filter", "("+filter+")")
+		query.Set("skipTotal", "1")
+		query.Set("expand", "trs")
+
+		resp, err := http.Get(s.uri + "?" + query.Encode())
+		if err != nil {
+			s.logger.Error().Err(err).Msg("seadex: error getting media records")
+			return nil, err
+		}
+
+		var res RecordsResponse
+		err = json.NewDecoder(resp.Body).Decode(&res)
+		_ = resp.Body.Close()
+		if err != nil {
+			s.logger.Error().Err(err).Msg("seadex: error decoding response")
+			return nil, err
+		}
+
+		for _, item := range res.Items {
+			if _, found := ret[item.AlID]; !found {
+				ret[item.AlID] = item
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// GetMagnetLink returns the magnet link of the torrent.
+// It falls back to a magnet built from the info hash if Nyaa can't be reached.
+func (t *Torrent) GetMagnetLink() string {
+	if t.Link != "" {
+		if magnet, err := nyaa.TorrentMagnet(t.Link); err == nil {
+			return magnet
+		}
+	}
+	return fmt.Sprintf("magnet:?xt=urn:btih:%s&dn=%s", t.InfoHash, url.QueryEscape(t.Name))
+}
@@ -0,0 +1,122 @@
+package seadex
+
+import (
+	"github.com/goccy/go-json"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"seanime/internal/api/anilist"
+	"seanime/internal/library/anime"
+	"seanime/internal/util"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSeaDex_AnalyzeLibrary(t *testing.T) {
+
+	records := []*RecordItem{
+		newTestRecord(1,
+			&Tr{ReleaseGroup: "LostYears", IsBest: true, InfoHash: "hash1best", Files: []*TrFile{{Name: "Show/Show - 01.mkv", Length: 3000}}},
+			&Tr{ReleaseGroup: "SubsPlease", InfoHash: "hash1alt", Files: []*TrFile{{Name: "[SubsPlease] Show - 01.mkv", Length: 1000}}},
+		),
+		newTestRecord(2,
+			&Tr{ReleaseGroup: "Vodes", IsBest: true, InfoHash: "hash2best", Files: []*TrFile{{Name: "Other - 01.mkv", Length: 5000}}},
+			&Tr{ReleaseGroup: "Vodes", IsBest: true, DualAudio: true, InfoHash: "hash2dual", Files: []*TrFile{{Name: "Other - 01 [Dual].mkv", Length: 6000}}},
+		),
+		newTestRecord(3,
+			&Tr{ReleaseGroup: "Okay-Subs", IsBest: true, InfoHash: "hash3best", Files: []*TrFile{{Name: "Third - 01.mkv", Length: 2000}}},
+		),
+	}
+
+	var requestedFilters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("filter")
+		requestedFilters = append(requestedFilters, filter)
+		items := lo.Filter(records, func(item *RecordItem, _ int) bool {
+			return strings.Contains(filter, "alID="+strconv.Itoa(item.AlID)+"|") ||
+				strings.Contains(filter, "alID="+strconv.Itoa(item.AlID)+")")
+		})
+		_ = json.NewEncoder(w).Encode(RecordsResponse{Items: items})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	newLocalFile := func(mediaId int, name string, releaseGroup string, size int) *anime.LocalFile {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+		return &anime.LocalFile{
+			Path:       path,
+			Name:       name,
+			MediaId:    mediaId,
+			ParsedData: &anime.LocalFileParsedData{ReleaseGroup: releaseGroup},
+			Metadata:   &anime.LocalFileMetadata{Episode: 1, Type: anime.LocalFileTypeMain},
+		}
+	}
+
+	lfs := []*anime.LocalFile{
+		// Alternative release, matched by release group
+		newLocalFile(1, "[SubsPlease] Show - 01 (1080p).mkv", "SubsPlease", 100),
+		// Best release, matched by file name
+		newLocalFile(2, "Other - 01.mkv", "", 200),
+		// Not a SeaDex release
+		newLocalFile(3, "[Erai-raws] Third - 01.mkv", "Erai-raws", 300),
+		// No SeaDex entry
+		newLocalFile(4, "[Group] Unlisted - 01.mkv", "Group", 400),
+		// Unmatched files are ignored
+		newLocalFile(0, "Unknown - 01.mkv", "", 500),
+	}
+
+	sd := New(util.NewLogger())
+	sd.uri = server.URL
+
+	analysis, err := sd.AnalyzeLibrary(&AnalyzeLibraryOptions{
+		LocalFiles:      lfs,
+		AnimeCollection: &anilist.AnimeCollection{MediaListCollection: &anilist.AnimeCollection_MediaListCollection{}},
+		PreferDualAudio: true,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, requestedFilters, 1)
+	assert.Equal(t, "(alID=1||alID=2||alID=3||alID=4)", requestedFilters[0])
+
+	assert.Equal(t, []int{4}, analysis.UnlistedMediaIds)
+	require.Len(t, analysis.Entries, 3)
+
+	entries := lo.SliceToMap(analysis.Entries, func(e *LibraryAnalysisEntry) (int, *LibraryAnalysisEntry) {
+		return e.MediaId, e
+	})
+
+	assert.Equal(t, LibraryReleaseStatusAlt, entries[1].Status)
+	assert.Equal(t, "hash1alt", entries[1].MatchedRelease.InfoHash)
+	assert.Equal(t, "hash1best", entries[1].Recommended.InfoHash)
+	assert.Equal(t, int64(3000), entries[1].UpgradeSize)
+	assert.Equal(t, int64(2900), entries[1].SizeDifference)
+	assert.Equal(t, []string{"SubsPlease"}, entries[1].LocalReleaseGroups)
+
+	assert.Equal(t, LibraryReleaseStatusBest, entries[2].Status)
+	assert.Equal(t, "hash2best", entries[2].MatchedRelease.InfoHash)
+	assert.Equal(t, "hash2dual", entries[2].Recommended.InfoHash)
+	assert.Equal(t, int64(0), entries[2].UpgradeSize)
+
+	assert.Equal(t, LibraryReleaseStatusUpgradable, entries[3].Status)
+	assert.Nil(t, entries[3].MatchedRelease)
+	assert.Equal(t, int64(2000), entries[3].UpgradeSize)
+	assert.Equal(t, 1, entries[3].LocalEpisodeCount)
+
+	assert.Equal(t, int64(5000), analysis.TotalUpgradeSize)
+}
+
+func newTestRecord(mediaId int, trs ...*Tr) *RecordItem {
+	ret := &RecordItem{AlID: mediaId}
+	for _, tr := range trs {
+		tr.Tracker = "Nyaa"
+		tr.URL = "https://nyaa.si/view/" + tr.InfoHash
+	}
+	ret.Expand.Trs = trs
+	return ret
+}
@@ -21,6 +21,8 @@ type (
 		Link         string `json:"link"`
 		InfoHash     string `json:"infoHash"`
 		ReleaseGroup string `json:"releaseGroup,omitempty"`
+		IsBest       bool   `json:"isBest"`
+		DualAudio    bool   `json:"dualAudio"`
 	}
 )
 
@@ -44,27 +46,35 @@ func (s *SeaDex) FetchTorrents(mediaId int, title string) (ret []*Torrent, err e
 		return ret, nil
 	}
 
-	if len(records[0].Expand.Trs) == 0 {
-		return ret, nil
-	}
 	for _, tr := range records[0].Expand.Trs {
-		if tr.InfoHash == "" || tr.InfoHash == "<redacted>" || tr.Tracker != "Nyaa" || !strings.Contains(tr.URL, "nyaa.si") {
+		if !isTrAvailable(tr) {
 			continue
 		}
-		ret = append(ret, &Torrent{
-			Name:         fmt.Sprintf("[%s] %s%s", tr.ReleaseGroup, title, map[bool]string{true: " [Dual-Audio]", false: ""}[tr.DualAudio]),
-			Date:         tr.Created,
-			Size:         int64(s.getTorrentSize(tr.Files)),
-			Link:         tr.URL,
-			InfoHash:     tr.InfoHash,
-			ReleaseGroup: tr.ReleaseGroup,
-		})
+		ret = append(ret, s.toTorrent(tr, title))
 	}
 
 	return ret, nil
 
 }
 
+// isTrAvailable returns true if the release can be fetched from Nyaa.
+func isTrAvailable(tr *Tr) bool {
+	return tr.InfoHash != "" && tr.InfoHash != "<redacted>" && tr.Tracker == "Nyaa" && strings.Contains(tr.URL, "nyaa.si")
+}
+
+func (s *SeaDex) toTorrent(tr *Tr, title string) *Torrent {
+	return &Torrent{
+		Name:         fmt.Sprintf("[%s] %s%s", tr.ReleaseGroup, title, map[bool]string{true: " [Dual-Audio]", false: ""}[tr.DualAudio]),
+		Date:         tr.Created,
+		Size:         int64(s.getTorrentSize(tr.Files)),
+		Link:         tr.URL,
+		InfoHash:     tr.InfoHash,
+		ReleaseGroup: tr.ReleaseGroup,
+		IsBest:       tr.IsBest,
+		DualAudio:    tr.DualAudio,
+	}
+}
+
 func (s *SeaDex) fetchRecords(mediaId int) (ret []*RecordItem, err error) {
 
 	uri := fmt.Sprintf("%s?page=1&perPage=1&filter=alID%%3D%%22%d%%22&skipTotal=1&expand=trs", s.uri, mediaId)
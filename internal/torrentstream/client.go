@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"seanime/internal/mediaplayers/mediaplayer"
+	"seanime/internal/util/result"
 	"strings"
 	"sync"
 	"time"
@@ -26,19 +27,16 @@ type (
 	Client struct {
 		repository *Repository
 
-		torrentClient        mo.Option[*torrent.Client]
-		currentTorrent       mo.Option[*torrent.Torrent]
-		currentFile          mo.Option[*torrent.File]
-		currentTorrentStatus TorrentStatus
-		cancelFunc           context.CancelFunc
+		torrentClient   mo.Option[*torrent.Client]
+		sessions        *result.Map[string, *session]         // Key: Client ID
+		seedingTorrents *result.Map[string, *torrent.Torrent] // Torrents kept seeding after their session ended, Key: Info hash
+		importJobs      *result.Map[string, *importJob]       // Torrents kept to be imported into the library, Key: Info hash
+		cancelFunc      context.CancelFunc
 
 		mu                          sync.Mutex
 		stopCh                      chan struct{}                    // Closed when the media player stops
 		mediaPlayerPlaybackStatusCh chan *mediaplayer.PlaybackStatus // Continuously receives playback status
 		timeSinceLoggedSeeding      time.Time
-		lastSpeedCheck              time.Time // Track the last time we checked speeds
-		lastBytesCompleted          int64     // Track the last bytes completed
-		lastBytesWrittenData        int64     // Track the last bytes written data
 	}
 
 	TorrentStatus struct {
@@ -60,8 +58,9 @@ func NewClient(repository *Repository) *Client {
 	ret := &Client{
 		repository:                  repository,
 		torrentClient:               mo.None[*torrent.Client](),
-		currentFile:                 mo.None[*torrent.File](),
-		currentTorrent:              mo.None[*torrent.Torrent](),
+		sessions:                    result.NewResultMap[string, *session](),
+		seedingTorrents:             result.NewResultMap[string, *torrent.Torrent](),
+		importJobs:                  result.NewResultMap[string, *importJob](),
 		stopCh:                      make(chan struct{}),
 		mediaPlayerPlaybackStatusCh: make(chan *mediaplayer.PlaybackStatus, 1),
 	}
@@ -70,7 +69,7 @@ func NewClient(repository *Repository) *Client {
 }
 
 // initializeClient will create and torrent client.
-// The client is designed to support only one torrent at a time, and seed it.
+// The client supports one session per client, each session streaming from its own torrent.
 // Upon initialization, the client will drop all torrents.
 func (c *Client) initializeClient() error {
 	// Fail if no settings
@@ -102,6 +101,10 @@ func (c *Client) initializeClient() error {
 		// cfg.DisableAggressiveUpload = true
 	}
 
+	if settings.GlobalDownloadRateLimit > 0 {
+		cfg.DownloadRateLimiter = newRateLimiter(settings.GlobalDownloadRateLimit)
+	}
+
 	//cfg.DisableAggressiveUpload = true
 	//cfg.Debug = true
 
@@ -127,6 +130,7 @@ func (c *Client) initializeClient() error {
 	c.repository.logger.Info().Msgf("torrentstream: Initialized torrent client on port %d", settings.TorrentClientPort)
 	c.torrentClient = mo.Some(client)
 	c.dropTorrents()
+	c.restoreImportJobs()
 	c.mu.Unlock()
 
 	go func(ctx context.Context) {
@@ -159,80 +163,26 @@ func (c *Client) initializeClient() error {
 
 			case status := <-c.mediaPlayerPlaybackStatusCh:
 				// DEVNOTE: When this is received, "default" case is executed right after
-				if status != nil && c.currentFile.IsPresent() && c.repository.playback.currentVideoDuration == 0 {
+				playingSessionId := c.repository.playback.getSessionId()
+				_, isPlaying := c.getSession(playingSessionId)
+				if status != nil && isPlaying && c.repository.playback.currentVideoDuration == 0 {
 					// If the stored video duration is 0 but the media player status shows a duration that is not 0
 					// we know that the video has been loaded and is playing
 					if c.repository.playback.currentVideoDuration == 0 && status.Duration > 0 {
 						// The media player has started playing the video
 						c.repository.logger.Debug().Msg("torrentstream: Media player started playing the video, sending event")
-						c.repository.wsEventManager.SendEvent(eventTorrentStartedPlaying, nil)
+						c.repository.sendSessionEvent(playingSessionId, eventTorrentStartedPlaying, nil)
 						// Update the stored video duration
 						c.repository.playback.currentVideoDuration = status.Duration
 					}
 				}
 			default:
 				c.mu.Lock()
-				if c.torrentClient.IsPresent() && c.currentTorrent.IsPresent() && c.currentFile.IsPresent() {
-					t := c.currentTorrent.MustGet()
-					f := c.currentFile.MustGet()
-
-					// Get the current time
-					now := time.Now()
-					elapsed := now.Sub(c.lastSpeedCheck).Seconds()
-
-					// downloadProgress is the number of bytes downloaded
-					downloadProgress := t.BytesCompleted()
-
-					downloadSpeed := ""
-					if elapsed > 0 {
-						bytesPerSecond := float64(downloadProgress-c.lastBytesCompleted) / elapsed
-						if bytesPerSecond > 0 {
-							downloadSpeed = fmt.Sprintf("%s/s", humanize.Bytes(uint64(bytesPerSecond)))
-						}
-					}
-					size := humanize.Bytes(uint64(f.Length()))
-
-					bytesWrittenData := t.Stats().BytesWrittenData
-					uploadSpeed := ""
-					if elapsed > 0 {
-						bytesPerSecond := float64((&bytesWrittenData).Int64()-c.lastBytesWrittenData) / elapsed
-						if bytesPerSecond > 0 {
-							uploadSpeed = fmt.Sprintf("%s/s", humanize.Bytes(uint64(bytesPerSecond)))
-						}
-					}
-
-					// Update the stored values for next calculation
-					c.lastBytesCompleted = downloadProgress
-					c.lastBytesWrittenData = (&bytesWrittenData).Int64()
-					c.lastSpeedCheck = now
-
-					if t.PeerConns() != nil {
-						c.currentTorrentStatus.Seeders = len(t.PeerConns())
-					}
-
-					//// If the torrent status went from 0% to > 0%, send an event, as the torrent has been loaded
-					//if c.currentTorrentStatus.ProgressPercentage == 0. && c.getTorrentPercentage(c.currentTorrent) > 0. {
-					//	c.repository.logger.Debug().Msg("torrentstream: Torrent loaded, sending event")
-					//	c.repository.wsEventManager.SendEvent(eventTorrentLoaded, nil)
-					//}
-
-					c.currentTorrentStatus = TorrentStatus{
-						Size:               size,
-						UploadProgress:     (&bytesWrittenData).Int64() - c.currentTorrentStatus.UploadProgress,
-						DownloadSpeed:      downloadSpeed,
-						UploadSpeed:        uploadSpeed,
-						DownloadProgress:   downloadProgress,
-						ProgressPercentage: c.getTorrentPercentage(c.currentTorrent, c.currentFile),
-						Seeders:            t.Stats().ConnectedSeeders,
-					}
-					c.repository.wsEventManager.SendEvent(eventTorrentStatus, c.currentTorrentStatus)
-					// Always log the progress so the user knows what's happening
-					c.repository.logger.Trace().Msgf("torrentstream: Progress: %.2f%%, Download speed: %s, Upload speed: %s, Size: %s",
-						c.currentTorrentStatus.ProgressPercentage,
-						c.currentTorrentStatus.DownloadSpeed,
-						c.currentTorrentStatus.UploadSpeed,
-						c.currentTorrentStatus.Size)
-					c.timeSinceLoggedSeeding = time.Now()
+				if c.torrentClient.IsPresent() {
+					c.sessions.Range(func(_ string, s *session) bool {
+						c.updateSessionStatus(s)
+						return true
+					})
 				}
 				c.mu.Unlock()
 				if c.torrentClient.IsPresent() {
@@ -240,7 +190,7 @@ func (c *Client) initializeClient() error {
 						c.timeSinceLoggedSeeding = time.Now()
 						for _, t := range c.torrentClient.MustGet().Torrents() {
 							if t.Seeding() {
-								c.repository.logger.Trace().Msgf("torrentstream: Seeding last torrent, %d peers", t.Stats().ActivePeers)
+								c.repository.logger.Trace().Msgf("torrentstream: Seeding torrent, %d peers", t.Stats().ActivePeers)
 							}
 						}
 					}
@@ -253,13 +203,74 @@ func (c *Client) initializeClient() error {
 	return nil
 }
 
-func (c *Client) GetStreamingUrl() string {
+// updateSessionStatus computes the status of the session and sends it to the client that owns it.
+func (c *Client) updateSessionStatus(s *session) {
+	if s.torrent == nil || s.file == nil {
+		return
+	}
+	t := s.torrent
+	f := s.file
+
+	// Get the current time
+	now := time.Now()
+	elapsed := now.Sub(s.lastSpeedCheck).Seconds()
+
+	// downloadProgress is the number of bytes downloaded
+	downloadProgress := t.BytesCompleted()
+
+	downloadSpeed := ""
+	if elapsed > 0 {
+		bytesPerSecond := float64(downloadProgress-s.lastBytesCompleted) / elapsed
+		if bytesPerSecond > 0 {
+			downloadSpeed = fmt.Sprintf("%s/s", humanize.Bytes(uint64(bytesPerSecond)))
+		}
+	}
+	size := humanize.Bytes(uint64(f.Length()))
+
+	bytesWrittenData := t.Stats().BytesWrittenData
+	uploadSpeed := ""
+	if elapsed > 0 {
+		bytesPerSecond := float64((&bytesWrittenData).Int64()-s.lastBytesWrittenData) / elapsed
+		if bytesPerSecond > 0 {
+			uploadSpeed = fmt.Sprintf("%s/s", humanize.Bytes(uint64(bytesPerSecond)))
+		}
+	}
+
+	// Update the stored values for next calculation
+	s.lastBytesCompleted = downloadProgress
+	s.lastBytesWrittenData = (&bytesWrittenData).Int64()
+	s.lastSpeedCheck = now
+
+	s.status = TorrentStatus{
+		Size:               size,
+		UploadProgress:     (&bytesWrittenData).Int64() - s.status.UploadProgress,
+		DownloadSpeed:      downloadSpeed,
+		UploadSpeed:        uploadSpeed,
+		DownloadProgress:   downloadProgress,
+		ProgressPercentage: s.getTorrentPercentage(),
+		Seeders:            t.Stats().ConnectedSeeders,
+	}
+	c.repository.sendSessionEvent(s.id, eventTorrentStatus, s.status)
+	// Always log the progress so the user knows what's happening
+	c.repository.logger.Trace().Str("clientId", s.id).Msgf("torrentstream: Progress: %.2f%%, Download speed: %s, Upload speed: %s, Size: %s",
+		s.status.ProgressPercentage,
+		s.status.DownloadSpeed,
+		s.status.UploadSpeed,
+		s.status.Size)
+	c.timeSinceLoggedSeeding = time.Now()
+}
+
+// GetStreamingUrl returns the URL of the file streamed by the session.
+// The session ID is part of the URL so that the streaming server knows which file to serve.
+func (c *Client) GetStreamingUrl(sessionId string) string {
 	if c.torrentClient.IsAbsent() {
 		return ""
 	}
-	if c.currentFile.IsAbsent() {
+	s, found := c.getSession(sessionId)
+	if !found || s.file == nil {
 		return ""
 	}
+	streamPath := fmt.Sprintf("%s/%s", url.PathEscape(sessionId), url.PathEscape(s.file.DisplayPath()))
 	settings, ok := c.repository.settings.Get()
 	if !ok {
 		return ""
@@ -274,7 +285,7 @@ func (c *Client) GetStreamingUrl() string {
 		if settings.StreamUrlAddress != "" {
 			address = settings.StreamUrlAddress
 		}
-		_url := fmt.Sprintf("http://%s/api/v1/torrentstream/stream/%s", address, url.PathEscape(c.currentFile.MustGet().DisplayPath()))
+		_url := fmt.Sprintf("http://%s/api/v1/torrentstream/stream/%s", address, streamPath)
 		if strings.HasPrefix(_url, "http://http") {
 			_url = strings.Replace(_url, "http://http", "http", 1)
 		}
@@ -282,15 +293,15 @@ func (c *Client) GetStreamingUrl() string {
 	}
 
 	//if settings.StreamingServerHost == "0.0.0.0" {
-	//	return fmt.Sprintf("http://127.0.0.1:%d/stream/%s", settings.StreamingServerPort, url.PathEscape(c.currentFile.MustGet().DisplayPath()))
+	//	return fmt.Sprintf("http://127.0.0.1:%d/stream/%s", settings.StreamingServerPort, streamPath)
 	//}
 	host := settings.StreamingServerHost
 	if host == "" {
 		host = "127.0.0.1"
 	}
-	_url := fmt.Sprintf("http://%s:%d/stream/%s", host, settings.StreamingServerPort, url.PathEscape(c.currentFile.MustGet().DisplayPath()))
+	_url := fmt.Sprintf("http://%s:%d/stream/%s", host, settings.StreamingServerPort, streamPath)
 	if settings.StreamUrlAddress != "" {
-		_url = fmt.Sprintf("http://%s/stream/%s", settings.StreamUrlAddress, url.PathEscape(c.currentFile.MustGet().DisplayPath()))
+		_url = fmt.Sprintf("http://%s/stream/%s", settings.StreamUrlAddress, streamPath)
 		if strings.HasPrefix(_url, "http://http") {
 			_url = strings.Replace(_url, "http://http", "http", 1)
 		}
@@ -303,10 +314,8 @@ func (c *Client) AddTorrent(id string) (*torrent.Torrent, error) {
 		return nil, errors.New("torrent client is not initialized")
 	}
 
-	// Drop all torrents
-	for _, t := range c.torrentClient.MustGet().Torrents() {
-		t.Drop()
-	}
+	// Drop the torrents that are no longer streamed
+	c.dropSeedingTorrents()
 
 	if strings.HasPrefix(id, "magnet") {
 		return c.addTorrentMagnet(id)
@@ -408,8 +417,8 @@ func (c *Client) Shutdown() (errs []error) {
 		return
 	}
 	c.dropTorrents()
-	c.currentTorrent = mo.None[*torrent.Torrent]()
-	c.currentTorrentStatus = TorrentStatus{}
+	c.sessions.Clear()
+	c.importJobs.Clear()
 	c.repository.logger.Debug().Msg("torrentstream: Closing torrent client")
 	return c.torrentClient.MustGet().Close()
 }
@@ -429,6 +438,7 @@ func (c *Client) FindTorrent(infoHash string) (*torrent.Torrent, error) {
 	return nil, fmt.Errorf("no torrent found")
 }
 
+// RemoveTorrent drops the torrent unless another session is streaming from it.
 func (c *Client) RemoveTorrent(infoHash string) error {
 	if c.torrentClient.IsAbsent() {
 		return errors.New("torrent client is not initialized")
@@ -439,7 +449,7 @@ func (c *Client) RemoveTorrent(infoHash string) error {
 	torrents := c.torrentClient.MustGet().Torrents()
 	for _, t := range torrents {
 		if t.InfoHash().AsString() == infoHash {
-			t.Drop()
+			c.dropTorrentIfUnused(t)
 			c.repository.logger.Debug().Msgf("torrentstream: Removed torrent: %s", infoHash)
 			return nil
 		}
@@ -456,12 +466,17 @@ func (c *Client) dropTorrents() {
 	for _, t := range c.torrentClient.MustGet().Torrents() {
 		t.Drop()
 	}
+	c.seedingTorrents.Clear()
 
 	if c.repository.settings.IsPresent() {
-		// Delete all torrents
+		// Delete all torrents, except those of pending import jobs
+		pendingImports := c.getPendingImportInfoHashes()
 		fe, err := os.ReadDir(c.repository.settings.MustGet().DownloadDir)
 		if err == nil {
 			for _, f := range fe {
+				if _, pending := pendingImports[f.Name()]; pending {
+					continue
+				}
 				if f.IsDir() {
 					_ = os.RemoveAll(path.Join(c.repository.settings.MustGet().DownloadDir, f.Name()))
 				}
@@ -471,23 +486,3 @@ func (c *Client) dropTorrents() {
 
 	c.repository.logger.Debug().Msg("torrentstream: Dropped all torrents")
 }
-
-//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
-
-// getTorrentPercentage returns the percentage of the current torrent file
-// If no torrent is selected, it returns -1
-func (c *Client) getTorrentPercentage(t mo.Option[*torrent.Torrent], f mo.Option[*torrent.File]) float64 {
-	if t.IsAbsent() || f.IsAbsent() {
-		return -1
-	}
-
-	if f.MustGet().Length() == 0 {
-		return 0
-	}
-
-	return float64(f.MustGet().BytesCompleted()) / float64(f.MustGet().Length()) * 100
-}
-
-func (c *Client) readyToStream() bool {
-	return c.getTorrentPercentage(c.currentTorrent, c.currentFile) > 5.
-}
@@ -26,9 +26,19 @@ const (
 	TLSStateSendingStreamToMediaPlayer TorrentLoadingStatusState = "SENDING_STREAM_TO_MEDIA_PLAYER"
 )
 
-func (r *Repository) sendTorrentLoadingStatus(event TorrentLoadingStatusState, checking string) {
-	r.wsEventManager.SendEvent(eventTorrentLoadingStatus, &TorrentLoadingStatus{
+func (r *Repository) sendTorrentLoadingStatus(sessionId string, event TorrentLoadingStatusState, checking string) {
+	r.sendSessionEvent(sessionId, eventTorrentLoadingStatus, &TorrentLoadingStatus{
 		TorrentBeingChecked: checking,
 		State:               event,
 	})
 }
+
+// sendSessionEvent sends the event to the client that owns the session.
+// If the client did not provide an ID, the event is sent to all clients.
+func (r *Repository) sendSessionEvent(sessionId string, t string, payload interface{}) {
+	if sessionId == defaultSessionId {
+		r.wsEventManager.SendEvent(t, payload)
+		return
+	}
+	r.wsEventManager.SendEventTo(sessionId, t, payload)
+}
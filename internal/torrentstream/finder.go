@@ -27,7 +27,7 @@ type (
 	}
 )
 
-func (r *Repository) findBestTorrent(media *anilist.CompleteAnime, aniDbEpisode string, episodeNumber int) (ret *playbackTorrent, err error) {
+func (r *Repository) findBestTorrent(sessionId string, media *anilist.CompleteAnime, aniDbEpisode string, episodeNumber int) (ret *playbackTorrent, err error) {
 	defer util.HandlePanicInModuleWithError("torrentstream/findBestTorrent", &err)
 
 	r.logger.Debug().Msgf("torrentstream: Finding best torrent for %s, Episode %d", media.GetTitleSafe(), episodeNumber)
@@ -51,7 +51,7 @@ func (r *Repository) findBestTorrent(media *anilist.CompleteAnime, aniDbEpisode
 		searchBatch = true
 	}
 
-	r.sendTorrentLoadingStatus(TLSStateSearchingTorrents, "")
+	r.sendTorrentLoadingStatus(sessionId, TLSStateSearchingTorrents, "")
 
 	var data *itorrent.SearchData
 searchLoop:
@@ -122,7 +122,7 @@ searchLoop:
 		if tries >= 2 {
 			break
 		}
-		r.sendTorrentLoadingStatus(TLSStateAddingTorrent, searchT.Name)
+		r.sendTorrentLoadingStatus(sessionId, TLSStateAddingTorrent, searchT.Name)
 		r.logger.Trace().Msgf("torrentstream: Getting torrent magnet")
 		magnet, err := providerExtension.GetProvider().GetTorrentMagnetLink(searchT)
 		if err != nil {
@@ -139,7 +139,7 @@ searchLoop:
 			continue
 		}
 
-		r.sendTorrentLoadingStatus(TLSStateCheckingTorrent, searchT.Name)
+		r.sendTorrentLoadingStatus(sessionId, TLSStateCheckingTorrent, searchT.Name)
 
 		// If the torrent has only one file, return it
 		if len(t.Files()) == 1 {
@@ -160,7 +160,7 @@ searchLoop:
 			}, nil
 		}
 
-		r.sendTorrentLoadingStatus(TLSStateSelectingFile, searchT.Name)
+		r.sendTorrentLoadingStatus(sessionId, TLSStateSelectingFile, searchT.Name)
 
 		// DEVNOTE: The gap between adding the torrent and file analysis causes some pieces to be downloaded
 		// We currently can't Pause/Resume torrents so :shrug:
@@ -213,8 +213,9 @@ searchLoop:
 		r.logger.Debug().Msgf("torrentstream: Found corresponding file for episode %s: %s", aniDbEpisode, analysisFile.GetLocalFile().Name)
 
 		// Download the file and unselect the rest
+		// Files streamed by other sessions are left untouched
 		for i, f := range t.Files() {
-			if i != analysisFile.GetIndex() {
+			if i != analysisFile.GetIndex() && !r.client.isFileInUse(f) {
 				f.SetPriority(torrent.PiecePriorityNone)
 			}
 		}
@@ -246,7 +247,7 @@ searchLoop:
 }
 
 // findBestTorrentFromManualSelection is like findBestTorrent but no need to search for the best torrent first
-func (r *Repository) findBestTorrentFromManualSelection(t *hibiketorrent.AnimeTorrent, media *anilist.CompleteAnime, aniDbEpisode string, chosenFileIndex *int) (*playbackTorrent, error) {
+func (r *Repository) findBestTorrentFromManualSelection(sessionId string, t *hibiketorrent.AnimeTorrent, media *anilist.CompleteAnime, aniDbEpisode string, chosenFileIndex *int) (*playbackTorrent, error) {
 
 	r.logger.Debug().Msgf("torrentstream: Analyzing torrent from %s for %s", t.Link, media.GetTitleSafe())
 
@@ -344,8 +345,9 @@ func (r *Repository) findBestTorrentFromManualSelection(t *hibiketorrent.AnimeTo
 	}
 
 	// Download the file and unselect the rest
+	// Files streamed by other sessions are left untouched
 	for i, f := range selectedTorrent.Files() {
-		if i != fileIndex {
+		if i != fileIndex && !r.client.isFileInUse(f) {
 			f.SetPriority(torrent.PiecePriorityNone)
 		}
 	}
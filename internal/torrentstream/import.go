@@ -0,0 +1,439 @@
+package torrentstream
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/database/models"
+	"seanime/internal/util"
+	"sync"
+	"time"
+
+	"github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+const eventTorrentImportStatus = "torrentstream-import-status"
+
+type (
+	ImportStatus string
+
+	// importJob keeps a torrent in the client until it is downloaded, imported into the library and seeded.
+	importJob struct {
+		mu          sync.Mutex
+		torrent     *torrent.Torrent
+		mediaId     int
+		status      ImportStatus
+		destination string // Path of the imported torrent, directory for batches
+		err         error
+		seedingAt   time.Time
+		paused      bool
+	}
+
+	// ImportJobInfo is a snapshot of an import job sent to the client.
+	ImportJobInfo struct {
+		InfoHash           string       `json:"infoHash"`
+		TorrentName        string       `json:"torrentName"`
+		MediaId            int          `json:"mediaId"`
+		Status             ImportStatus `json:"status"`
+		ProgressPercentage float64      `json:"progressPercentage"`
+		TotalBytes         int64        `json:"totalBytes"`
+		DownloadedBytes    int64        `json:"downloadedBytes"`
+		Paused             bool         `json:"paused"`
+		Destination        string       `json:"destination"`
+		SeedRatio          float64      `json:"seedRatio"`
+		Error              string       `json:"error,omitempty"`
+	}
+)
+
+const (
+	ImportStatusDownloading ImportStatus = "downloading"
+	ImportStatusImporting   ImportStatus = "importing"
+	ImportStatusSeeding     ImportStatus = "seeding"
+	ImportStatusCompleted   ImportStatus = "completed"
+	ImportStatusFailed      ImportStatus = "failed"
+)
+
+func (j *importJob) setStatus(status ImportStatus) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status = status
+	if status == ImportStatusSeeding {
+		j.seedingAt = time.Now()
+	}
+}
+
+func (j *importJob) fail(err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status = ImportStatusFailed
+	j.err = err
+}
+
+func (j *importJob) isActive() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status != ImportStatusCompleted && j.status != ImportStatusFailed
+}
+
+// seedRatio returns the ratio of uploaded data to the size of the torrent.
+func (j *importJob) seedRatio() float64 {
+	if j.torrent.Info() == nil || j.torrent.Length() == 0 {
+		return 0
+	}
+	stats := j.torrent.Stats()
+	return float64((&stats.BytesWrittenData).Int64()) / float64(j.torrent.Length())
+}
+
+func (j *importJob) toInfo() *ImportJobInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	ret := &ImportJobInfo{
+		InfoHash:    j.torrent.InfoHash().HexString(),
+		MediaId:     j.mediaId,
+		Status:      j.status,
+		Destination: j.destination,
+		Paused:      j.paused,
+	}
+	if j.torrent.Info() != nil {
+		ret.TorrentName = j.torrent.Name()
+		ret.TotalBytes = j.torrent.Length()
+		ret.DownloadedBytes = j.torrent.BytesCompleted()
+		if j.torrent.Length() > 0 {
+			ret.ProgressPercentage = float64(j.torrent.BytesCompleted()) / float64(j.torrent.Length()) * 100
+		}
+		ret.SeedRatio = j.seedRatio()
+	}
+	if j.err != nil {
+		ret.Error = j.err.Error()
+	}
+	return ret
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// KeepAndImport keeps the torrent of the client's stream after the stream ends.
+// The torrent is downloaded in the background, imported into the library, and seeded until the seed limits are reached.
+func (r *Repository) KeepAndImport(clientId string) error {
+	if err := r.FailIfNoSettings(); err != nil {
+		return err
+	}
+
+	r.client.mu.Lock()
+	defer r.client.mu.Unlock()
+
+	s, found := r.client.getSession(sessionIdOrDefault(clientId))
+	if !found || s.torrent == nil {
+		return errors.New("torrentstream: No active stream")
+	}
+
+	r.client.startImportJob(s.torrent, s.mediaId)
+	return nil
+}
+
+// GetImportJobs returns a snapshot of all import jobs.
+func (r *Repository) GetImportJobs() []*ImportJobInfo {
+	ret := make([]*ImportJobInfo, 0)
+	r.client.importJobs.Range(func(_ string, j *importJob) bool {
+		ret = append(ret, j.toInfo())
+		return true
+	})
+	return ret
+}
+
+// PauseImportJob stops downloading the data of a torrent being imported.
+// The torrent keeps downloading the pieces requested by an active stream.
+func (r *Repository) PauseImportJob(infoHash string) error {
+	return r.client.setImportJobPaused(infoHash, true)
+}
+
+// ResumeImportJob resumes downloading the data of a paused import job.
+func (r *Repository) ResumeImportJob(infoHash string) error {
+	return r.client.setImportJobPaused(infoHash, false)
+}
+
+func (c *Client) setImportJobPaused(infoHash string, paused bool) error {
+	var job *importJob
+	c.importJobs.Range(func(_ string, j *importJob) bool {
+		if j.torrent.InfoHash().HexString() == infoHash {
+			job = j
+			return false
+		}
+		return true
+	})
+	if job == nil {
+		return errors.New("torrentstream: Import job not found")
+	}
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+
+	if job.status != ImportStatusDownloading || job.paused == paused {
+		return nil
+	}
+	job.paused = paused
+
+	if job.torrent.Info() == nil {
+		return nil
+	}
+
+	if paused {
+		// Pieces read by a stream are still prioritized by the reader
+		job.torrent.CancelPieces(0, job.torrent.NumPieces())
+	} else {
+		job.torrent.DownloadAll()
+	}
+
+	return nil
+}
+
+// startImportJob starts importing the torrent if it's not already being imported.
+func (c *Client) startImportJob(t *torrent.Torrent, mediaId int) {
+	if c.isTorrentImporting(t) {
+		return
+	}
+
+	job := &importJob{
+		torrent: t,
+		mediaId: mediaId,
+		status:  ImportStatusDownloading,
+	}
+	c.importJobs.Set(t.InfoHash().AsString(), job)
+	c.seedingTorrents.Delete(t.InfoHash().AsString())
+
+	c.repository.logger.Info().Str("name", t.Name()).Msg("torrentstream: Keeping torrent to import it into the library")
+
+	c.saveImportJob(job)
+
+	go c.runImportJob(job)
+}
+
+// saveImportJob persists the import job so that it is resumed after a restart.
+func (c *Client) saveImportJob(job *importJob) {
+	if c.repository.db == nil || job.torrent.Info() == nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	mi := job.torrent.Metainfo()
+	if err := mi.Write(&buf); err != nil {
+		c.repository.logger.Error().Err(err).Msg("torrentstream: Failed to encode torrent metainfo")
+		return
+	}
+
+	err := c.repository.db.SaveTorrentstreamImport(&models.TorrentstreamImport{
+		InfoHash: job.torrent.InfoHash().HexString(),
+		MediaId:  job.mediaId,
+		Torrent:  buf.Bytes(),
+	})
+	if err != nil {
+		c.repository.logger.Error().Err(err).Msg("torrentstream: Failed to save import job")
+	}
+}
+
+// deleteImportJob removes the persisted import job once the torrent is imported or the import failed.
+func (c *Client) deleteImportJob(job *importJob) {
+	if c.repository.db == nil {
+		return
+	}
+	if err := c.repository.db.DeleteTorrentstreamImport(job.torrent.InfoHash().HexString()); err != nil {
+		c.repository.logger.Error().Err(err).Msg("torrentstream: Failed to delete import job")
+	}
+}
+
+// getPendingImportInfoHashes returns the info hashes of the persisted import jobs.
+func (c *Client) getPendingImportInfoHashes() map[string]struct{} {
+	ret := make(map[string]struct{})
+	if c.repository.db == nil {
+		return ret
+	}
+	imports, err := c.repository.db.GetTorrentstreamImports()
+	if err != nil {
+		return ret
+	}
+	for _, item := range imports {
+		ret[item.InfoHash] = struct{}{}
+	}
+	return ret
+}
+
+// restoreImportJobs adds the torrents of the import jobs that were pending when the client was closed and resumes them.
+// The data already downloaded is kept in the download directory.
+func (c *Client) restoreImportJobs() {
+	if c.repository.db == nil || c.torrentClient.IsAbsent() {
+		return
+	}
+
+	imports, err := c.repository.db.GetTorrentstreamImports()
+	if err != nil {
+		c.repository.logger.Error().Err(err).Msg("torrentstream: Failed to get pending import jobs")
+		return
+	}
+
+	for _, item := range imports {
+		mi, err := metainfo.Load(bytes.NewReader(item.Torrent))
+		if err != nil {
+			c.repository.logger.Error().Err(err).Str("infoHash", item.InfoHash).Msg("torrentstream: Failed to load torrent of import job")
+			_ = c.repository.db.DeleteTorrentstreamImport(item.InfoHash)
+			continue
+		}
+
+		t, err := c.torrentClient.MustGet().AddTorrent(mi)
+		if err != nil {
+			c.repository.logger.Error().Err(err).Str("infoHash", item.InfoHash).Msg("torrentstream: Failed to add torrent of import job")
+			continue
+		}
+
+		c.startImportJob(t, item.MediaId)
+	}
+}
+
+// isTorrentImporting returns true if an import job is running for the torrent.
+func (c *Client) isTorrentImporting(t *torrent.Torrent) bool {
+	job, found := c.importJobs.Get(t.InfoHash().AsString())
+	return found && job.isActive()
+}
+
+func (c *Client) runImportJob(job *importJob) {
+	defer util.HandlePanicInModuleThen("torrentstream/runImportJob", func() {
+		job.fail(errors.New("unexpected error"))
+	})
+
+	t := job.torrent
+	logger := c.repository.logger.With().Str("name", t.Name()).Logger()
+
+	defer c.sendImportStatus(job)
+
+	// Download the whole torrent
+	t.DownloadAll()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	c.sendImportStatus(job)
+	for !t.Complete().Bool() {
+		select {
+		case <-t.Closed():
+			// The job is kept in the database if the client was closed, it's resumed when the client is initialized again
+			logger.Warn().Msg("torrentstream: Torrent was dropped before it was imported")
+			job.fail(errors.New("torrent was dropped"))
+			return
+		case <-t.Complete().On():
+		case <-ticker.C:
+			c.sendImportStatus(job)
+		}
+	}
+
+	// Import the files into the library
+	job.setStatus(ImportStatusImporting)
+	c.sendImportStatus(job)
+
+	destination, err := c.importTorrentFiles(t)
+	if err != nil {
+		logger.Error().Err(err).Msg("torrentstream: Failed to import torrent")
+		job.fail(err)
+		c.deleteImportJob(job)
+		return
+	}
+
+	c.deleteImportJob(job)
+
+	job.mu.Lock()
+	job.destination = destination
+	job.mu.Unlock()
+
+	logger.Info().Str("destination", destination).Msg("torrentstream: Torrent imported into the library")
+
+	// Scan the imported files
+	if c.repository.scanPathsFunc != nil {
+		go c.repository.scanPathsFunc([]string{destination})
+	}
+
+	// Seed until the limits are reached
+	job.setStatus(ImportStatusSeeding)
+	c.sendImportStatus(job)
+
+	for !c.seedLimitReached(job) {
+		select {
+		case <-t.Closed():
+			job.setStatus(ImportStatusCompleted)
+			return
+		case <-ticker.C:
+			c.sendImportStatus(job)
+		}
+	}
+
+	logger.Info().Float64("ratio", job.seedRatio()).Msg("torrentstream: Seed limit reached, dropping torrent")
+	job.setStatus(ImportStatusCompleted)
+
+	// Drop the torrent and delete its data from the download directory, the files are now in the library
+	c.mu.Lock()
+	c.dropTorrentIfUnused(t)
+	c.mu.Unlock()
+}
+
+// importTorrentFiles hard links or copies the files of the completed torrent to the import directory.
+// It returns the path of the imported torrent.
+func (c *Client) importTorrentFiles(t *torrent.Torrent) (string, error) {
+	settings, ok := c.repository.settings.Get()
+	if !ok {
+		return "", errors.New("no settings")
+	}
+
+	// Defaults to the library directory with the highest priority that files can be downloaded to
+	importDir, err := db_bridge.ResolveDownloadDestination(c.repository.db, settings.ImportDir)
+	if err != nil {
+		return "", fmt.Errorf("invalid import directory: %w", err)
+	}
+
+	// e.g. /path/to/temp/seanime/torrentstream/{infohash}
+	torrentDir := filepath.Join(settings.DownloadDir, t.InfoHash().HexString())
+
+	for _, f := range t.Files() {
+		// File.Path contains the torrent name for batches
+		src := filepath.Join(torrentDir, filepath.FromSlash(f.Path()))
+		dst := filepath.Join(importDir, filepath.FromSlash(f.Path()))
+
+		if _, err := os.Stat(dst); err == nil {
+			c.repository.logger.Warn().Str("path", dst).Msg("torrentstream: File already exists in the library, skipping")
+			continue
+		}
+
+		if err := util.LinkOrCopyFile(src, dst); err != nil {
+			return "", fmt.Errorf("failed to import %s: %w", f.DisplayPath(), err)
+		}
+	}
+
+	return filepath.Join(importDir, t.Name()), nil
+}
+
+// seedLimitReached returns true if the seed ratio or time limit of the job is reached.
+// If no limits are set, the torrent is not seeded after being imported.
+func (c *Client) seedLimitReached(job *importJob) bool {
+	settings, ok := c.repository.settings.Get()
+	if !ok {
+		return true
+	}
+
+	if settings.SeedRatioLimit <= 0 && settings.SeedTimeLimit <= 0 {
+		return true
+	}
+
+	if settings.SeedRatioLimit > 0 && job.seedRatio() >= settings.SeedRatioLimit {
+		return true
+	}
+
+	job.mu.Lock()
+	seedingAt := job.seedingAt
+	job.mu.Unlock()
+
+	return settings.SeedTimeLimit > 0 && time.Since(seedingAt) >= time.Duration(settings.SeedTimeLimit)*time.Minute
+}
+
+func (c *Client) sendImportStatus(job *importJob) {
+	c.repository.wsEventManager.SendEvent(eventTorrentImportStatus, job.toInfo())
+}
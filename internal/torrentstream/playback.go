@@ -2,6 +2,7 @@ package torrentstream
 
 import (
 	"context"
+	"sync"
 )
 
 type (
@@ -10,9 +11,40 @@ type (
 		// Stores the video duration returned by the media player
 		// When this is greater than 0, the video is considered to be playing
 		currentVideoDuration int
+		// ID of the session whose stream is being played by the media player
+		// The media player can only play one stream at a time
+		sessionId   string
+		sessionIdMu sync.RWMutex
 	}
 )
 
+func (p *playback) getSessionId() string {
+	p.sessionIdMu.RLock()
+	defer p.sessionIdMu.RUnlock()
+	return p.sessionId
+}
+
+// setSessionId sets the session using the media player and returns the session that was using it.
+func (p *playback) setSessionId(id string) (prev string) {
+	p.sessionIdMu.Lock()
+	defer p.sessionIdMu.Unlock()
+	prev = p.sessionId
+	p.sessionId = id
+	return prev
+}
+
+// clearSessionId removes the session from the media player if it is using it.
+// Returns true if the session was using the media player.
+func (p *playback) clearSessionId(id string) bool {
+	p.sessionIdMu.Lock()
+	defer p.sessionIdMu.Unlock()
+	if p.sessionId != id {
+		return false
+	}
+	p.sessionId = ""
+	return true
+}
+
 func (r *Repository) listenToMediaPlayerEvents() {
 	r.mediaPlayerRepositorySubscriber = r.mediaPlayerRepository.Subscribe("torrentstream")
 
@@ -40,24 +72,25 @@ func (r *Repository) listenToMediaPlayerEvents() {
 				r.playback.currentVideoDuration = 0
 			case _ = <-r.mediaPlayerRepositorySubscriber.StreamingVideoCompletedCh:
 			case _ = <-r.mediaPlayerRepositorySubscriber.StreamingTrackingStoppedCh:
-				if r.client.currentTorrent.IsPresent() {
-					go func() {
+				sessionId := r.playback.getSessionId()
+				if _, found := r.client.getSession(sessionId); found {
+					go func(sessionId string) {
 						defer func() {
 							if r := recover(); r != nil {
 							}
 						}()
 						r.logger.Debug().Msg("torrentstream: Media player stopped event received")
-						// Stop the stream
-						_ = r.StopStream()
+						// Stop the session that was being played
+						_ = r.stopSession(sessionId, false)
 						// Stop the server
 						//r.serverManager.stopServer()
 						//// Signal to client.go that the media player has stopped
 						//close(r.client.stopCh)
-					}()
+					}(sessionId)
 				}
 			case status := <-r.mediaPlayerRepositorySubscriber.StreamingPlaybackStatusCh:
 				go func() {
-					if status != nil && r.client.currentTorrent.IsPresent() {
+					if _, found := r.client.getSession(r.playback.getSessionId()); status != nil && found {
 						r.client.mediaPlayerPlaybackStatusCh <- status
 					}
 				}()
@@ -124,7 +124,7 @@ func (r *Repository) GetTorrentFilePreviewsFromManualSelection(opts *GetTorrentF
 	wg.Wait()
 
 	r.logger.Debug().Str("hash", opts.Torrent.InfoHash).Msg("torrentstream: Got file previews for torrent selection, dropping torrent")
-	go selectedTorrent.Drop()
+	go r.client.dropTorrentIfUnused(selectedTorrent)
 
 	return
 }
@@ -43,6 +43,7 @@ type (
 		mediaPlayerRepositorySubscriber *mediaplayer.RepositorySubscriber
 		logger                          *zerolog.Logger
 		db                              *db.Database
+		scanPathsFunc                   func(paths []string) // Scans the imported torrents
 	}
 
 	Settings struct {
@@ -62,6 +63,7 @@ type (
 		PlaybackManager    *playbackmanager.PlaybackManager
 		WSEventManager     events.WSEventManagerInterface
 		Database           *db.Database
+		ScanPathsFunc      func(paths []string)
 	}
 )
 
@@ -84,6 +86,7 @@ func NewRepository(opts *NewRepositoryOptions) *Repository {
 		mediaPlayerRepositorySubscriber: nil,
 		logger:                          opts.Logger,
 		db:                              opts.Database,
+		scanPathsFunc:                   opts.ScanPathsFunc,
 	}
 	ret.client = NewClient(ret)
 	ret.serverManager = newServerManager(ret)
@@ -182,6 +185,11 @@ func (r *Repository) InitModules(settings *models.TorrentstreamSettings, host st
 	return nil
 }
 
+// GetSessions returns the active streams of all clients.
+func (r *Repository) GetSessions() []*SessionInfo {
+	return r.client.GetSessions()
+}
+
 func (r *Repository) HTTPStreamHandler() http.Handler {
 	return r.serverManager
 }
@@ -3,6 +3,7 @@ package torrentstream
 import (
 	"context"
 	"github.com/anacrolix/torrent"
+	"io"
 	"net"
 	"net/http"
 	"time"
@@ -71,13 +72,14 @@ func (s *serverManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.lastUsed = time.Now()
 	s.repository.logger.Trace().Msg("torrentstream: Stream endpoint hit [server]")
 
-	if s.repository.client.currentFile.IsAbsent() || s.repository.client.currentTorrent.IsAbsent() {
+	sess, found := s.getSession(r)
+	if !found || sess.file == nil {
 		s.repository.logger.Error().Msg("torrentstream: No torrent to stream [server]")
 		http.Error(w, "No torrent to stream", http.StatusNotFound)
 		return
 	}
 
-	file := s.repository.client.currentFile.MustGet()
+	file := sess.file
 	tr := file.NewReader()
 	defer func(tr torrent.Reader) {
 		_ = tr.Close()
@@ -85,6 +87,15 @@ func (s *serverManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	tr.SetResponsive()
 	tr.SetReadahead(file.FileInfo().Length / 100)
 
+	var content io.ReadSeeker = tr
+	if sess.limiter != nil {
+		content = &throttledReader{
+			ctx:     r.Context(),
+			reader:  tr,
+			limiter: sess.limiter,
+		}
+	}
+
 	s.repository.logger.Trace().Str("file", file.DisplayPath()).Msg("torrentstream: Serving file content")
 	w.Header().Set("Content-Type", "video/mp4")
 	http.ServeContent(
@@ -92,7 +103,21 @@ func (s *serverManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		r,
 		file.DisplayPath(),
 		time.Now(),
-		tr,
+		content,
 	)
 	s.repository.logger.Trace().Msg("torrentstream: File content served")
 }
+
+// getSession returns the session whose file is requested.
+// If the URL does not contain a session ID, the session is only returned if it's the only one.
+func (s *serverManager) getSession(r *http.Request) (*session, bool) {
+	if id := sessionIdFromStreamPath(r.URL.EscapedPath()); id != "" {
+		return s.repository.client.getSession(id)
+	}
+
+	sessions := s.repository.client.sessions.Values()
+	if len(sessions) == 1 {
+		return sessions[0], true
+	}
+	return nil, false
+}
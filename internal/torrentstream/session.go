@@ -0,0 +1,290 @@
+package torrentstream
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anacrolix/torrent"
+	"golang.org/x/time/rate"
+)
+
+// defaultSessionId is used when the client did not provide an ID.
+// It matches the ID given to websocket connections that do not provide one.
+const defaultSessionId = "0"
+
+type (
+	// session holds the state of a stream started by a client.
+	// A client can only have one session at a time, starting a new stream replaces the previous session of that client.
+	session struct {
+		id            string // Client ID
+		mediaId       int
+		episodeNumber int
+		playbackType  PlaybackType
+		torrent       *torrent.Torrent
+		file          *torrent.File
+		status        TorrentStatus
+		limiter       *rate.Limiter // Limits the rate at which the file is served, nil if unlimited
+
+		lastSpeedCheck       time.Time // Track the last time we checked speeds
+		lastBytesCompleted   int64     // Track the last bytes completed
+		lastBytesWrittenData int64     // Track the last bytes written data
+	}
+
+	// SessionInfo is a snapshot of a session sent to the client.
+	SessionInfo struct {
+		ClientId      string        `json:"clientId"`
+		MediaId       int           `json:"mediaId"`
+		EpisodeNumber int           `json:"episodeNumber"`
+		PlaybackType  PlaybackType  `json:"playbackType"`
+		TorrentName   string        `json:"torrentName"`
+		FileName      string        `json:"fileName"`
+		Status        TorrentStatus `json:"status"`
+	}
+)
+
+func sessionIdOrDefault(clientId string) string {
+	if clientId == "" {
+		return defaultSessionId
+	}
+	return clientId
+}
+
+// getTorrentPercentage returns the percentage of the session's file that has been downloaded
+func (s *session) getTorrentPercentage() float64 {
+	if s.file == nil {
+		return -1
+	}
+
+	if s.file.Length() == 0 {
+		return 0
+	}
+
+	return float64(s.file.BytesCompleted()) / float64(s.file.Length()) * 100
+}
+
+func (s *session) readyToStream() bool {
+	return s.getTorrentPercentage() > 5.
+}
+
+func (s *session) toInfo() *SessionInfo {
+	ret := &SessionInfo{
+		ClientId:      s.id,
+		MediaId:       s.mediaId,
+		EpisodeNumber: s.episodeNumber,
+		PlaybackType:  s.playbackType,
+		Status:        s.status,
+	}
+	if s.torrent != nil && s.torrent.Info() != nil {
+		ret.TorrentName = s.torrent.Name()
+	}
+	if s.file != nil {
+		ret.FileName = s.file.DisplayPath()
+	}
+	return ret
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// newSession creates a session for the given client.
+// The per-session rate limit is read from the settings.
+func (c *Client) newSession(id string, opts *StartStreamOptions, pt *playbackTorrent) *session {
+	ret := &session{
+		id:            id,
+		mediaId:       opts.MediaId,
+		episodeNumber: opts.EpisodeNumber,
+		playbackType:  opts.PlaybackType,
+		torrent:       pt.Torrent,
+		file:          pt.File,
+	}
+
+	if settings, ok := c.repository.settings.Get(); ok && settings.SessionDownloadRateLimit > 0 {
+		ret.limiter = newRateLimiter(settings.SessionDownloadRateLimit)
+	}
+
+	return ret
+}
+
+// setSession registers the session, replacing the client's previous session if any.
+// The new session is stored before the previous one is released, so that a torrent shared by both sessions
+// (e.g. the next episode of a batch) is not dropped.
+func (c *Client) setSession(s *session) {
+	prev, found := c.sessions.Get(s.id)
+	c.sessions.Set(s.id, s)
+	if found && prev != s {
+		c.releaseSessionTorrent(prev, false)
+	}
+}
+
+func (c *Client) getSession(id string) (*session, bool) {
+	return c.sessions.Get(id)
+}
+
+// GetSessions returns a snapshot of all active sessions.
+func (c *Client) GetSessions() []*SessionInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ret := make([]*SessionInfo, 0)
+	c.sessions.Range(func(_ string, s *session) bool {
+		ret = append(ret, s.toInfo())
+		return true
+	})
+	return ret
+}
+
+// releaseSession removes the session.
+// If no other session is streaming from the same torrent, the torrent is imported into the library if "Add to library" is enabled
+// and the playable threshold was reached, or dropped if drop is true or if its completion is less than 70%.
+// Otherwise, it is kept seeding until another torrent is added.
+func (c *Client) releaseSession(id string, drop bool) (*session, bool) {
+	s, found := c.sessions.Get(id)
+	if !found {
+		return nil, false
+	}
+	c.sessions.Delete(id)
+
+	c.releaseSessionTorrent(s, drop)
+
+	return s, true
+}
+
+// releaseSessionTorrent imports, drops or keeps seeding the torrent of a session that was removed or replaced.
+// Nothing is done if another session is streaming from the torrent.
+func (c *Client) releaseSessionTorrent(s *session, drop bool) {
+	id := s.id
+
+	// Torrents being imported are dropped once they are seeded
+	if s.torrent == nil || c.isTorrentInUse(s.torrent) || c.isTorrentImporting(s.torrent) {
+		return
+	}
+
+	// Keep the torrent to import it into the library
+	if !drop && s.readyToStream() {
+		if settings, ok := c.repository.settings.Get(); ok && settings.AddToLibrary {
+			c.startImportJob(s.torrent, s.mediaId)
+			return
+		}
+	}
+
+	// This is to prevent the client from downloading the whole torrent when the user stops watching
+	// Also, the torrent might be a batch - so we don't want to download the whole thing
+	if drop {
+		c.repository.logger.Debug().Str("clientId", id).Msg("torrentstream: Dropping torrent")
+		c.dropTorrent(s.torrent)
+	} else if s.status.ProgressPercentage < 70 {
+		c.repository.logger.Debug().Str("clientId", id).Msg("torrentstream: Dropping torrent, completion is less than 70%")
+		c.dropTorrent(s.torrent)
+	} else {
+		c.seedingTorrents.Set(s.torrent.InfoHash().AsString(), s.torrent)
+	}
+}
+
+// isTorrentInUse returns true if any session is streaming from the torrent.
+func (c *Client) isTorrentInUse(t *torrent.Torrent) bool {
+	inUse := false
+	c.sessions.Range(func(_ string, s *session) bool {
+		if s.torrent != nil && s.torrent.InfoHash() == t.InfoHash() {
+			inUse = true
+			return false
+		}
+		return true
+	})
+	return inUse
+}
+
+// isFileInUse returns true if any session is streaming the file.
+func (c *Client) isFileInUse(f *torrent.File) bool {
+	inUse := false
+	c.sessions.Range(func(_ string, s *session) bool {
+		if s.file != nil && s.torrent.InfoHash() == f.Torrent().InfoHash() && s.file.Path() == f.Path() {
+			inUse = true
+			return false
+		}
+		return true
+	})
+	return inUse
+}
+
+// dropTorrent drops the torrent and deletes its data from the download directory.
+func (c *Client) dropTorrent(t *torrent.Torrent) {
+	hash := t.InfoHash()
+	t.Drop()
+	c.seedingTorrents.Delete(hash.AsString())
+
+	if settings, ok := c.repository.settings.Get(); ok && settings.DownloadDir != "" {
+		_ = os.RemoveAll(filepath.Join(settings.DownloadDir, hash.HexString()))
+	}
+}
+
+// dropTorrentIfUnused drops the torrent if no session is streaming from it and it's not being imported.
+func (c *Client) dropTorrentIfUnused(t *torrent.Torrent) {
+	if c.isTorrentInUse(t) || c.isTorrentImporting(t) {
+		return
+	}
+	c.dropTorrent(t)
+}
+
+// dropSeedingTorrents drops the torrents that were kept seeding after their session ended.
+func (c *Client) dropSeedingTorrents() {
+	for _, t := range c.seedingTorrents.Values() {
+		c.dropTorrentIfUnused(t)
+	}
+	c.seedingTorrents.Clear()
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// newRateLimiter creates a rate limiter from a limit in KiB/s.
+// The burst is large enough to let the torrent client read a whole chunk at once.
+func newRateLimiter(kibPerSecond int) *rate.Limiter {
+	limit := kibPerSecond * 1024
+	return rate.NewLimiter(rate.Limit(limit), max(limit, 1<<20))
+}
+
+// throttledReader limits the rate at which a torrent file is read by the streaming server.
+type throttledReader struct {
+	ctx     context.Context
+	reader  io.ReadSeeker
+	limiter *rate.Limiter
+}
+
+func (tr *throttledReader) Read(p []byte) (n int, err error) {
+	if burst := tr.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err = tr.reader.Read(p)
+	if n > 0 {
+		if wErr := tr.limiter.WaitN(tr.ctx, n); wErr != nil && err == nil {
+			err = wErr
+		}
+	}
+	return
+}
+
+func (tr *throttledReader) Seek(offset int64, whence int) (int64, error) {
+	return tr.reader.Seek(offset, whence)
+}
+
+// sessionIdFromStreamPath returns the session ID from the stream URL path.
+// e.g. "/api/v1/torrentstream/stream/{clientId}/{filename}" -> "{clientId}"
+// Returns an empty string if the path does not contain a session ID.
+func sessionIdFromStreamPath(p string) string {
+	_, rest, found := strings.Cut(p, "/stream/")
+	if !found {
+		return ""
+	}
+	id, _, found := strings.Cut(rest, "/")
+	if !found {
+		return ""
+	}
+	id, err := url.PathUnescape(id)
+	if err != nil {
+		return ""
+	}
+	return id
+}
@@ -0,0 +1,43 @@
+package torrentstream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionIdFromStreamPath(t *testing.T) {
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/api/v1/torrentstream/stream/abc-123/%5BSubsPlease%5D%20Show%20-%2001.mkv", "abc-123"},
+		{"/stream/abc-123/file.mkv", "abc-123"},
+		{"/api/v1/torrentstream/stream/0/file.mkv", "0"},
+		// Legacy URL without session ID
+		{"/api/v1/torrentstream/stream/file.mkv", ""},
+		{"/api/v1/torrentstream/other/abc-123/file.mkv", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			require.Equal(t, tt.expected, sessionIdFromStreamPath(tt.path))
+		})
+	}
+
+}
+
+func TestPlaybackSessionId(t *testing.T) {
+	p := &playback{}
+
+	require.Equal(t, "", p.setSessionId("a"))
+	require.Equal(t, "a", p.setSessionId("b"))
+
+	// The session that was replaced cannot clear the media player
+	require.False(t, p.clearSessionId("a"))
+	require.Equal(t, "b", p.getSessionId())
+
+	require.True(t, p.clearSessionId("b"))
+	require.Equal(t, "", p.getSessionId())
+}
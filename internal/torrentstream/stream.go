@@ -3,8 +3,6 @@ package torrentstream
 import (
 	"fmt"
 	hibiketorrent "github.com/5rahim/hibike/pkg/extension/torrent"
-	"github.com/anacrolix/torrent"
-	"github.com/samber/mo"
 	"seanime/internal/api/anilist"
 	"seanime/internal/api/metadata"
 	"seanime/internal/events"
@@ -33,18 +31,21 @@ type StartStreamOptions struct {
 	PlaybackType  PlaybackType
 }
 
-// StartStream is called by the client to start streaming a torrent
+// StartStream is called by the client to start streaming a torrent.
+// Each client has its own session, starting a stream only replaces the session of the same client.
 func (r *Repository) StartStream(opts *StartStreamOptions) (err error) {
 	defer util.HandlePanicInModuleWithError("torrentstream/stream/StartStream", &err)
 	// DEVNOTE: Do not
 	//r.Shutdown()
 
+	sessionId := sessionIdOrDefault(opts.ClientId)
+
 	r.logger.Info().
 		Str("clientId", opts.ClientId).
 		Any("playbackType", opts.PlaybackType).
 		Int("mediaId", opts.MediaId).Msgf("torrentstream: Starting stream for episode %s", opts.AniDBEpisode)
 
-	r.wsEventManager.SendEvent(eventTorrentLoading, nil)
+	r.sendSessionEvent(sessionId, eventTorrentLoading, nil)
 
 	//
 	// Get the media info
@@ -63,34 +64,37 @@ func (r *Repository) StartStream(opts *StartStreamOptions) (err error) {
 	var torrentToStream *playbackTorrent
 	switch opts.AutoSelect {
 	case true:
-		torrentToStream, err = r.findBestTorrent(media, aniDbEpisode, episodeNumber)
+		torrentToStream, err = r.findBestTorrent(sessionId, media, aniDbEpisode, episodeNumber)
 		if err != nil {
-			r.wsEventManager.SendEvent(eventTorrentLoadingFailed, nil)
+			r.sendSessionEvent(sessionId, eventTorrentLoadingFailed, nil)
 			return err
 		}
 	case false:
 		if opts.Torrent == nil {
 			return fmt.Errorf("torrentstream: No torrent provided")
 		}
-		torrentToStream, err = r.findBestTorrentFromManualSelection(opts.Torrent, media, aniDbEpisode, opts.FileIndex)
+		torrentToStream, err = r.findBestTorrentFromManualSelection(sessionId, opts.Torrent, media, aniDbEpisode, opts.FileIndex)
 		if err != nil {
-			r.wsEventManager.SendEvent(eventTorrentLoadingFailed, nil)
+			r.sendSessionEvent(sessionId, eventTorrentLoadingFailed, nil)
 			return err
 		}
 	}
 
 	if torrentToStream == nil {
-		r.wsEventManager.SendEvent(eventTorrentLoadingFailed, nil)
+		r.sendSessionEvent(sessionId, eventTorrentLoadingFailed, nil)
 		return fmt.Errorf("torrentstream: No torrent selected")
 	}
 
 	//
-	// Set current file & torrent
+	// Create the session
+	// This replaces the previous session of the client
 	//
-	r.client.currentFile = mo.Some(torrentToStream.File)
-	r.client.currentTorrent = mo.Some(torrentToStream.Torrent)
+	r.client.mu.Lock()
+	s := r.client.newSession(sessionId, opts, torrentToStream)
+	r.client.setSession(s)
+	r.client.mu.Unlock()
 
-	r.sendTorrentLoadingStatus(TLSStateStartingServer, "")
+	r.sendTorrentLoadingStatus(sessionId, TLSStateStartingServer, "")
 
 	settings, ok := r.settings.Get()
 	if ok && settings.UseSeparateServer {
@@ -100,24 +104,24 @@ func (r *Repository) StartStream(opts *StartStreamOptions) (err error) {
 		r.serverManager.startServer()
 	}
 
-	r.sendTorrentLoadingStatus(TLSStateSendingStreamToMediaPlayer, "")
+	r.sendTorrentLoadingStatus(sessionId, TLSStateSendingStreamToMediaPlayer, "")
 
 	go func() {
 		// Add the torrent to the history if it is a batch & manually selected
-		if len(r.client.currentTorrent.MustGet().Files()) > 1 && opts.Torrent != nil {
+		if len(s.torrent.Files()) > 1 && opts.Torrent != nil {
 			r.AddBatchHistory(opts.MediaId, opts.Torrent) // ran in goroutine
 		}
 
 		for {
 			// This is to make sure the client is ready to stream before we start the stream
-			if r.client.readyToStream() {
+			if s.readyToStream() {
 				break
 			}
-			// If for some reason the torrent is dropped, we kill the goroutine
-			if r.client.torrentClient.IsAbsent() || r.client.currentTorrent.IsAbsent() {
+			// If for some reason the session is stopped or replaced, we kill the goroutine
+			if current, found := r.client.getSession(sessionId); r.client.torrentClient.IsAbsent() || !found || current != s {
 				return
 			}
-			r.logger.Debug().Msg("torrentstream: Waiting for playable threshold to be reached")
+			r.logger.Debug().Str("clientId", opts.ClientId).Msg("torrentstream: Waiting for playable threshold to be reached")
 			time.Sleep(3 * time.Second) // Wait for 3 secs before checking again
 		}
 
@@ -126,16 +130,23 @@ func (r *Repository) StartStream(opts *StartStreamOptions) (err error) {
 			//
 			// Start the stream
 			//
+			// The media player can only play one stream at a time,
+			// so the session that was using it is stopped
+			if prev := r.playback.setSessionId(sessionId); prev != "" && prev != sessionId {
+				r.logger.Debug().Str("clientId", prev).Msg("torrentstream: Media player is being used by another session, stopping it")
+				_ = r.stopSession(prev, false)
+			}
+
 			r.logger.Debug().Msg("torrentstream: Starting the media player")
 			err = r.playbackManager.StartStreamingUsingMediaPlayer("", &playbackmanager.StartPlayingOptions{
-				Payload:   r.client.GetStreamingUrl(),
+				Payload:   r.client.GetStreamingUrl(sessionId),
 				UserAgent: opts.UserAgent,
 				ClientId:  opts.ClientId,
 			}, media.ToBaseAnime(), aniDbEpisode)
 			if err != nil {
 				// Failed to start the stream, we'll drop the torrents and stop the server
-				r.wsEventManager.SendEvent(eventTorrentLoadingFailed, nil)
-				_ = r.StopStream()
+				r.sendSessionEvent(sessionId, eventTorrentLoadingFailed, nil)
+				_ = r.StopStream(opts.ClientId)
 				r.logger.Error().Err(err).Msg("torrentstream: Failed to start the stream")
 			}
 
@@ -146,97 +157,78 @@ func (r *Repository) StartStream(opts *StartStreamOptions) (err error) {
 				MediaId       int    `json:"mediaId"`
 				EpisodeNumber int    `json:"episodeNumber"`
 			}{
-				Url:           r.client.GetStreamingUrl(),
+				Url:           r.client.GetStreamingUrl(sessionId),
 				MediaId:       opts.MediaId,
 				EpisodeNumber: opts.EpisodeNumber,
 			})
 
 			// Signal to the client that the torrent has started playing (remove loading status)
 			// We can't know for sure
-			r.wsEventManager.SendEvent(eventTorrentStartedPlaying, nil)
+			r.sendSessionEvent(sessionId, eventTorrentStartedPlaying, nil)
 		}
 	}()
 
-	r.wsEventManager.SendEvent(eventTorrentLoaded, nil)
-	r.logger.Info().Msg("torrentstream: Stream started")
+	r.sendSessionEvent(sessionId, eventTorrentLoaded, nil)
+	r.logger.Info().Str("clientId", opts.ClientId).Msg("torrentstream: Stream started")
 
 	return nil
 }
 
-func (r *Repository) StopStream() error {
+// StopStream stops the session of the client.
+// The torrent is dropped if its completion is below a threshold and no other session is streaming from it.
+func (r *Repository) StopStream(clientId string) error {
 	defer func() {
 		if r := recover(); r != nil {
 		}
 	}()
-	r.logger.Info().Msg("torrentstream: Stopping stream")
+	r.logger.Info().Str("clientId", clientId).Msg("torrentstream: Stopping stream")
 
-	// Stop the client
-	// This will stop the stream and close the server
-	// This also sends the eventTorrentStopped event
-	r.client.mu.Lock()
-	//r.client.stopCh = make(chan struct{})
-	r.client.repository.logger.Debug().Msg("torrentstream: Handling media player stopped event")
-	// This is to prevent the client from downloading the whole torrent when the user stops watching
-	// Also, the torrent might be a batch - so we don't want to download the whole thing
-	if r.client.currentTorrent.IsPresent() {
-		if r.client.currentTorrentStatus.ProgressPercentage < 70 {
-			r.client.repository.logger.Debug().Msg("torrentstream: Dropping torrent, completion is less than 70%")
-			r.client.dropTorrents()
-		}
-		r.client.repository.logger.Debug().Msg("torrentstream: Resetting current torrent and status")
-	}
-	r.client.currentTorrent = mo.None[*torrent.Torrent]() // Reset the current torrent
-	r.client.currentFile = mo.None[*torrent.File]()       // Reset the current file
-	r.client.currentTorrentStatus = TorrentStatus{}       // Reset the torrent status
-	settings, ok := r.client.repository.settings.Get()
-	if ok && settings.UseSeparateServer {
-		r.client.repository.serverManager.stopServer() // Stop the server
-	}
-	r.client.repository.wsEventManager.SendEvent(eventTorrentStopped, nil) // Send torrent stopped event
-	r.client.repository.mediaPlayerRepository.Stop()                       // Stop the media player gracefully if it's running
-	r.client.mu.Unlock()
+	err := r.stopSession(sessionIdOrDefault(clientId), false)
 
-	r.logger.Info().Msg("torrentstream: Stream stopped")
+	r.logger.Info().Str("clientId", clientId).Msg("torrentstream: Stream stopped")
 
-	return nil
+	return err
 }
 
-//func (r *Repository) StopStream() error {
-//	defer func() {
-//		if r := recover(); r != nil {
-//		}
-//	}()
-//	r.logger.Info().Msg("torrentstream: Stopping stream")
-//
-//	// Stop the client
-//	// This will stop the stream and close the server
-//	// This also sends the eventTorrentStopped event
-//	close(r.client.stopCh)
-//
-//	r.logger.Info().Msg("torrentstream: Stream stopped")
-//
-//	return nil
-//}
-
-func (r *Repository) DropTorrent() error {
-	r.logger.Info().Msg("torrentstream: Dropping last torrent")
+// DropTorrent stops the session of the client and drops its torrent completely,
+// unless another session is streaming from it.
+func (r *Repository) DropTorrent(clientId string) error {
+	r.logger.Info().Str("clientId", clientId).Msg("torrentstream: Dropping torrent")
 
 	if r.client.torrentClient.IsAbsent() {
 		return nil
 	}
 
-	for _, t := range r.client.torrentClient.MustGet().Torrents() {
-		t.Drop()
-	}
+	err := r.stopSession(sessionIdOrDefault(clientId), true)
+
+	r.logger.Info().Str("clientId", clientId).Msg("torrentstream: Dropped torrent")
+
+	return err
+}
+
+// stopSession releases the session, stops the media player if the session was using it and notifies the client.
+// If drop is true, the torrent is dropped regardless of its completion.
+func (r *Repository) stopSession(sessionId string, drop bool) error {
+	r.client.mu.Lock()
+	defer r.client.mu.Unlock()
 
-	// Also stop the server, since it's dropped
 	settings, ok := r.settings.Get()
-	if ok && settings.UseSeparateServer {
-		r.serverManager.stopServer()
+
+	r.logger.Debug().Str("clientId", sessionId).Msg("torrentstream: Releasing session")
+	r.client.releaseSession(sessionId, drop)
+
+	if ok && settings.UseSeparateServer && len(r.client.sessions.Values()) == 0 {
+		r.serverManager.stopServer() // Stop the server
 	}
-	r.mediaPlayerRepository.Stop()
 
-	r.logger.Info().Msg("torrentstream: Dropped last torrent")
+	r.sendSessionEvent(sessionId, eventTorrentStopped, nil) // Send torrent stopped event
+
+	// Stop the media player gracefully if it's running the session's stream
+	if r.playback.clearSessionId(sessionId) {
+		if r.mediaPlayerRepository != nil {
+			r.mediaPlayerRepository.Stop()
+		}
+	}
 
 	return nil
 }
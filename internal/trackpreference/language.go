@@ -0,0 +1,82 @@
+package trackpreference
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// languageNames maps English language names to their ISO 639-2 code.
+// Online-stream providers and track titles usually name the language instead of using a code.
+var languageNames = func() map[string]string {
+	ret := make(map[string]string)
+	namer := display.English.Languages()
+	for _, code := range []string{
+		"en", "ja", "es", "pt", "fr", "de", "it", "ru", "ar", "id", "th", "vi", "zh", "ko",
+		"pl", "tr", "nl", "sv", "no", "da", "fi", "cs", "hu", "ro", "el", "he", "hi", "ms",
+		"uk", "bg", "hr", "sr", "sk", "sl", "ca", "fa", "ta", "te", "tl", "fil", "nb",
+	} {
+		base := language.MustParseBase(code)
+		ret[strings.ToLower(namer.Name(base))] = base.ISO3()
+	}
+	return ret
+}()
+
+// NormalizeLanguage returns the ISO 639-2/T code of a language.
+// It accepts ISO 639-1 and ISO 639-2 codes, BCP 47 tags (e.g. "en-US") and English names (e.g. "English", "Portuguese (Brazil)").
+// An empty string is returned if the language is unknown.
+func NormalizeLanguage(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+
+	lower := strings.ToLower(s)
+	switch lower {
+	case "und", "zxx", "mul", "mis":
+		return ""
+	}
+
+	if tag, err := language.Parse(lower); err == nil {
+		if base, conf := tag.Base(); conf != language.No {
+			return base.ISO3()
+		}
+	}
+
+	// Match the first word of the name, e.g. "English (US)", "Spanish - Latin America"
+	name := strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(name) == 0 {
+		return ""
+	}
+	if code, ok := languageNames[name[0]]; ok {
+		return code
+	}
+
+	return ""
+}
+
+// LanguageMatches returns true if both strings refer to the same known language.
+func LanguageMatches(a, b string) bool {
+	na := NormalizeLanguage(a)
+	return na != "" && na == NormalizeLanguage(b)
+}
+
+// NormalizeLanguages normalizes the languages and removes unknown ones and duplicates.
+func NormalizeLanguages(langs []string) []string {
+	ret := make([]string, 0, len(langs))
+	for _, l := range langs {
+		n := NormalizeLanguage(l)
+		if n == "" {
+			continue
+		}
+		if !slices.Contains(ret, n) {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,176 @@
+package trackpreference
+
+import (
+	"seanime/internal/database/db"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/database/models"
+	"seanime/internal/mediastream/videofile"
+	"seanime/internal/util"
+	"seanime/internal/util/filecache"
+
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+)
+
+type (
+	// Repository resolves the track preferences of a media and applies them to its tracks.
+	// It is shared by the external media players, media streaming and online streaming.
+	Repository struct {
+		logger             *zerolog.Logger
+		db                 *db.Database
+		mediaInfoExtractor *videofile.MediaInfoExtractor
+	}
+
+	NewRepositoryOptions struct {
+		Logger     *zerolog.Logger
+		Database   *db.Database
+		FileCacher *filecache.Cacher
+	}
+)
+
+func NewRepository(opts *NewRepositoryOptions) *Repository {
+	return &Repository{
+		logger:             opts.Logger,
+		db:                 opts.Database,
+		mediaInfoExtractor: videofile.NewMediaInfoExtractor(opts.FileCacher, opts.Logger),
+	}
+}
+
+// GetSettings returns the global track preferences.
+func (r *Repository) GetSettings() *models.TrackPreferenceSettings {
+	settings, found := r.db.GetTrackPreferenceSettings()
+	if !found {
+		return &models.TrackPreferenceSettings{
+			BaseModel:     models.BaseModel{ID: 1},
+			SignsAndSongs: string(SignsAndSongsAuto),
+		}
+	}
+	return settings
+}
+
+// GetPreferences returns the track preferences of the media.
+// The overrides of the media take precedence over the global preferences.
+func (r *Repository) GetPreferences(mediaId int) *Preferences {
+	settings := r.GetSettings()
+
+	ret := &Preferences{
+		AudioLanguages:    NormalizeLanguages(settings.AudioLanguages),
+		SubtitleLanguages: NormalizeLanguages(settings.SubtitleLanguages),
+		SignsAndSongs:     SignsAndSongsMode(settings.SignsAndSongs),
+	}
+
+	if mediaId > 0 {
+		if override, found := r.db.GetMediaTrackPreference(mediaId); found {
+			if len(override.AudioLanguages) > 0 {
+				ret.AudioLanguages = NormalizeLanguages(override.AudioLanguages)
+			}
+			if len(override.SubtitleLanguages) > 0 {
+				ret.SubtitleLanguages = NormalizeLanguages(override.SubtitleLanguages)
+			}
+			if override.SignsAndSongs != "" {
+				ret.SignsAndSongs = SignsAndSongsMode(override.SignsAndSongs)
+			}
+		}
+	}
+
+	if ret.SignsAndSongs == "" {
+		ret.SignsAndSongs = SignsAndSongsAuto
+	}
+
+	return ret
+}
+
+// SelectTracks applies the preferences of the media to the tracks of a video file.
+func (r *Repository) SelectTracks(mediaId int, info *videofile.MediaInfo) *Selection {
+	if info == nil {
+		return SelectTracks(nil, nil, nil)
+	}
+	return SelectTracks(r.GetPreferences(mediaId), AudioTracksFromMediaInfo(info), SubtitleTracksFromMediaInfo(info))
+}
+
+// SelectTracksForFile applies the preferences to the tracks of a local file.
+// The media is found from the local files of the library and the tracks are read using FFprobe.
+func (r *Repository) SelectTracksForFile(path string) (*Selection, *videofile.MediaInfo, error) {
+	mediaId := r.GetMediaIdFromPath(path)
+
+	prefs := r.GetPreferences(mediaId)
+	if prefs.IsEmpty() {
+		return SelectTracks(nil, nil, nil), nil, nil
+	}
+
+	ffprobePath := "ffprobe"
+	if settings, found := r.db.GetMediastreamSettings(); found && settings.FfprobePath != "" {
+		ffprobePath = settings.FfprobePath
+	}
+
+	info, err := r.mediaInfoExtractor.GetInfo(ffprobePath, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return SelectTracks(prefs, AudioTracksFromMediaInfo(info), SubtitleTracksFromMediaInfo(info)), info, nil
+}
+
+// SelectOnlineSubtitle applies the preferences to the subtitles of an online stream.
+// Dubbed streams are assumed to be in English and subbed streams in Japanese.
+func (r *Repository) SelectOnlineSubtitle(mediaId int, dubbed bool, languages []string) *Selection {
+	audioLanguage := "jpn"
+	if dubbed {
+		audioLanguage = "eng"
+	}
+
+	subtitles := make([]*Track, 0, len(languages))
+	for i, l := range languages {
+		subtitles = append(subtitles, &Track{Index: i, Language: l, Title: l})
+	}
+
+	return SelectTracks(r.GetPreferences(mediaId), []*Track{{Index: 0, Language: audioLanguage, IsDefault: true}}, subtitles)
+}
+
+// GetMediaIdFromPath returns the media ID of a local file, 0 if the file is not in the library.
+func (r *Repository) GetMediaIdFromPath(path string) int {
+	lfs, _, err := db_bridge.GetLocalFiles(r.db)
+	if err != nil {
+		return 0
+	}
+
+	normalized := util.NormalizePath(path)
+	for _, lf := range lfs {
+		if lf.GetNormalizedPath() == normalized {
+			return lf.MediaId
+		}
+	}
+	return 0
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// AudioTracksFromMediaInfo returns the audio tracks of a video file.
+func AudioTracksFromMediaInfo(info *videofile.MediaInfo) []*Track {
+	ret := make([]*Track, 0, len(info.Audios))
+	for _, a := range info.Audios {
+		ret = append(ret, &Track{
+			Index:     int(a.Index),
+			Language:  lo.FromPtr(a.Language),
+			Title:     lo.FromPtr(a.Title),
+			IsDefault: a.IsDefault,
+			IsForced:  a.IsForced,
+		})
+	}
+	return ret
+}
+
+// SubtitleTracksFromMediaInfo returns the subtitle tracks of a video file.
+func SubtitleTracksFromMediaInfo(info *videofile.MediaInfo) []*Track {
+	ret := make([]*Track, 0, len(info.Subtitles))
+	for _, s := range info.Subtitles {
+		ret = append(ret, &Track{
+			Index:     int(s.Index),
+			Language:  lo.FromPtr(s.Language),
+			Title:     lo.FromPtr(s.Title),
+			IsDefault: s.IsDefault,
+			IsForced:  s.IsForced,
+		})
+	}
+	return ret
+}
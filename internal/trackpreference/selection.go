@@ -0,0 +1,209 @@
+package trackpreference
+
+import (
+	"strings"
+)
+
+type SignsAndSongsMode string
+
+const (
+	// SignsAndSongsAuto selects signs & songs subtitles when the audio is already in the subtitle language, full subtitles otherwise.
+	SignsAndSongsAuto SignsAndSongsMode = "auto"
+	// SignsAndSongsPrefer always selects signs & songs subtitles when they are available.
+	SignsAndSongsPrefer SignsAndSongsMode = "prefer"
+	// SignsAndSongsNever never selects signs & songs subtitles.
+	SignsAndSongsNever SignsAndSongsMode = "never"
+)
+
+type (
+	// Preferences are the resolved track preferences for a media.
+	Preferences struct {
+		AudioLanguages    []string          `json:"audioLanguages"`    // ISO 639-2 codes ordered by priority
+		SubtitleLanguages []string          `json:"subtitleLanguages"` // ISO 639-2 codes ordered by priority
+		SignsAndSongs     SignsAndSongsMode `json:"signsAndSongs"`
+	}
+
+	// Track is an audio or subtitle track of a media.
+	Track struct {
+		Index     int // Index of the track among the tracks of the same type
+		Language  string
+		Title     string
+		IsDefault bool
+		IsForced  bool
+	}
+
+	// Selection is the result of applying the preferences to the tracks of a media.
+	Selection struct {
+		AudioIndex    int  `json:"audioIndex"`    // Index of the audio track to select, -1 to keep the default track
+		SubtitleIndex int  `json:"subtitleIndex"` // Index of the subtitle track to select, -1 to keep the default track
+		NoSubtitles   bool `json:"noSubtitles"`   // Subtitles should be turned off
+	}
+)
+
+// IsEmpty returns true if no preference is set.
+func (p *Preferences) IsEmpty() bool {
+	return p == nil || (len(p.AudioLanguages) == 0 && len(p.SubtitleLanguages) == 0)
+}
+
+// HasAudio returns true if an audio track should be selected.
+func (s *Selection) HasAudio() bool {
+	return s != nil && s.AudioIndex >= 0
+}
+
+// HasSubtitle returns true if a subtitle track should be selected.
+func (s *Selection) HasSubtitle() bool {
+	return s != nil && !s.NoSubtitles && s.SubtitleIndex >= 0
+}
+
+// SelectTracks picks the audio and subtitle tracks that match the preferences.
+//
+//   - The audio track is the first track matching the preferred languages, in order of priority.
+//   - The subtitle track depends on the audio language: when the audio is already in the preferred subtitle language,
+//     only signs & songs are selected (SignsAndSongsAuto). Subtitles are turned off if no preferred track is found.
+func SelectTracks(prefs *Preferences, audios []*Track, subtitles []*Track) *Selection {
+	ret := &Selection{
+		AudioIndex:    -1,
+		SubtitleIndex: -1,
+	}
+	if prefs.IsEmpty() {
+		return ret
+	}
+
+	// +---------------------+
+	// |        Audio        |
+	// +---------------------+
+
+	var audio *Track
+	for _, lang := range prefs.AudioLanguages {
+		audio = findTrack(audios, lang, func(t *Track) bool {
+			return !isCommentary(t)
+		})
+		if audio != nil {
+			ret.AudioIndex = audio.Index
+			break
+		}
+	}
+	if audio == nil {
+		audio = defaultTrack(audios)
+	}
+
+	audioLanguage := ""
+	if audio != nil {
+		audioLanguage = audio.Language
+	}
+
+	// +---------------------+
+	// |      Subtitles      |
+	// +---------------------+
+
+	if len(prefs.SubtitleLanguages) == 0 || len(subtitles) == 0 {
+		return ret
+	}
+
+	// Keep the default track if the languages of the subtitles are unknown
+	if !hasKnownLanguage(subtitles) {
+		return ret
+	}
+
+	for _, lang := range prefs.SubtitleLanguages {
+		audioMatches := LanguageMatches(audioLanguage, lang)
+
+		full := findTrack(subtitles, lang, func(t *Track) bool {
+			return !isSignsAndSongs(t)
+		})
+		signs := findTrack(subtitles, lang, isSignsAndSongs)
+
+		var sub *Track
+		switch prefs.SignsAndSongs {
+		case SignsAndSongsNever:
+			if audioMatches {
+				ret.NoSubtitles = true
+				return ret
+			}
+			sub = full
+		case SignsAndSongsPrefer:
+			sub = signs
+			if sub == nil && !audioMatches {
+				sub = full
+			}
+		default:
+			if audioMatches {
+				// The audio is in the subtitle language, full subtitles are redundant
+				if signs == nil {
+					ret.NoSubtitles = true
+					return ret
+				}
+				sub = signs
+			} else {
+				sub = full
+				if sub == nil {
+					sub = signs
+				}
+			}
+		}
+
+		if sub != nil {
+			ret.SubtitleIndex = sub.Index
+			return ret
+		}
+	}
+
+	ret.NoSubtitles = true
+	return ret
+}
+
+// findTrack returns the first track of the language that satisfies the filter.
+// Default tracks are preferred.
+func findTrack(tracks []*Track, lang string, filter func(t *Track) bool) (ret *Track) {
+	for _, t := range tracks {
+		if !LanguageMatches(t.Language, lang) || !filter(t) {
+			continue
+		}
+		if t.IsDefault {
+			return t
+		}
+		if ret == nil {
+			ret = t
+		}
+	}
+	return
+}
+
+func defaultTrack(tracks []*Track) *Track {
+	for _, t := range tracks {
+		if t.IsDefault {
+			return t
+		}
+	}
+	if len(tracks) > 0 {
+		return tracks[0]
+	}
+	return nil
+}
+
+func hasKnownLanguage(tracks []*Track) bool {
+	for _, t := range tracks {
+		if NormalizeLanguage(t.Language) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isSignsAndSongs returns true if the subtitle track only contains signs and songs.
+func isSignsAndSongs(t *Track) bool {
+	if t.IsForced {
+		return true
+	}
+	title := strings.ToLower(t.Title)
+	for _, s := range []string{"sign", "song", "forced", "s&s"} {
+		if strings.Contains(title, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func isCommentary(t *Track) bool {
+	return strings.Contains(strings.ToLower(t.Title), "commentary")
+}
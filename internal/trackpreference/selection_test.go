@@ -0,0 +1,97 @@
+package trackpreference
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"jpn", "jpn"},
+		{"ja", "jpn"},
+		{"fre", "fra"},
+		{"fr", "fra"},
+		{"ger", "deu"},
+		{"en-US", "eng"},
+		{"pt-BR", "por"},
+		{"English", "eng"},
+		{"Spanish - Latin America", "spa"},
+		{"Portuguese (Brazil)", "por"},
+		{"und", ""},
+		{"", ""},
+		{"Unknown", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			require.Equal(t, tt.expected, NormalizeLanguage(tt.input))
+		})
+	}
+
+	require.Equal(t, []string{"jpn", "eng"}, NormalizeLanguages([]string{"ja", "jpn", "English", "xyz"}))
+}
+
+func TestSelectTracks(t *testing.T) {
+	audios := []*Track{
+		{Index: 0, Language: "jpn", IsDefault: true},
+		{Index: 1, Language: "eng", Title: "Commentary"},
+		{Index: 2, Language: "eng"},
+	}
+	subtitles := []*Track{
+		{Index: 0, Language: "eng", Title: "Signs & Songs"},
+		{Index: 1, Language: "eng", Title: "Full", IsDefault: true},
+		{Index: 2, Language: "spa"},
+	}
+
+	tests := []struct {
+		name     string
+		prefs    *Preferences
+		expected *Selection
+	}{
+		{
+			name:     "no preferences",
+			prefs:    nil,
+			expected: &Selection{AudioIndex: -1, SubtitleIndex: -1},
+		},
+		{
+			name:     "japanese audio with full subtitles",
+			prefs:    &Preferences{AudioLanguages: []string{"jpn"}, SubtitleLanguages: []string{"eng"}, SignsAndSongs: SignsAndSongsAuto},
+			expected: &Selection{AudioIndex: 0, SubtitleIndex: 1},
+		},
+		{
+			name:     "english dub with signs and songs, commentary skipped",
+			prefs:    &Preferences{AudioLanguages: []string{"eng"}, SubtitleLanguages: []string{"eng"}, SignsAndSongs: SignsAndSongsAuto},
+			expected: &Selection{AudioIndex: 2, SubtitleIndex: 0},
+		},
+		{
+			name:     "english dub without signs and songs",
+			prefs:    &Preferences{AudioLanguages: []string{"eng"}, SubtitleLanguages: []string{"eng"}, SignsAndSongs: SignsAndSongsNever},
+			expected: &Selection{AudioIndex: 2, SubtitleIndex: -1, NoSubtitles: true},
+		},
+		{
+			name:     "prefer signs and songs",
+			prefs:    &Preferences{AudioLanguages: []string{"jpn"}, SubtitleLanguages: []string{"eng"}, SignsAndSongs: SignsAndSongsPrefer},
+			expected: &Selection{AudioIndex: 0, SubtitleIndex: 0},
+		},
+		{
+			name:     "fallback to the next subtitle language",
+			prefs:    &Preferences{AudioLanguages: []string{"fre", "jpn"}, SubtitleLanguages: []string{"fr", "es"}, SignsAndSongs: SignsAndSongsAuto},
+			expected: &Selection{AudioIndex: 0, SubtitleIndex: 2},
+		},
+		{
+			name:     "no matching subtitles",
+			prefs:    &Preferences{SubtitleLanguages: []string{"ger"}, SignsAndSongs: SignsAndSongsAuto},
+			expected: &Selection{AudioIndex: -1, SubtitleIndex: -1, NoSubtitles: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, SelectTracks(tt.prefs, audios, subtitles))
+		})
+	}
+}
@@ -137,25 +137,60 @@ func ValueContainsBatchKeywords(val string) bool {
 	return false
 }
 
+// NCType is the kind of file matched by ValueContainsNC.
+type NCType string
+
+const (
+	NCTypeOpening NCType = "opening" // Creditless opening
+	NCTypeEnding  NCType = "ending"  // Creditless ending
+	NCTypeTrailer NCType = "trailer" // Trailer, promo, commercial
+	NCTypeOther   NCType = "other"
+)
+
+var ncRegexes = []struct {
+	regex  *regexp.Regexp
+	ncType NCType
+}{
+	{regexp.MustCompile(`(?i)(^|(?P<show>.*?)[ _.\-(]+)(OP|NCOP|OPED) ?(?P<ep>\d{1,2}[a-z]?)? ?([ _.\-)]+(?P<title>.*))?`), NCTypeOpening},
+	{regexp.MustCompile(`(?i)(^|(?P<show>.*?)[ _.\-(]+)(ED|NCED) ?(?P<ep>\d{1,2}[a-z]?)? ?([ _.\-)]+(?P<title>.*))?`), NCTypeEnding},
+	{regexp.MustCompile(`(?i)(^|(?P<show>.*?)[ _.\-(]+)(TRAILER|PROMO|PV|T) ?(?P<ep>\d{1,2}) ?([ _.\-)]+(?P<title>.*))?`), NCTypeTrailer},
+	{regexp.MustCompile(`(?i)(^|(?P<show>.*?)[ _.\-(]+)(O|OTHERS?)(?P<ep>\d{1,2}) ?[ _.\-)]+(?P<title>.*)`), NCTypeOther},
+	{regexp.MustCompile(`(?i)(^|(?P<show>.*?)[ _.\-(]+)(CM|COMMERCIAL|AD) ?(?P<ep>\d{1,2}) ?([ _.\-)]+(?P<title>.*))?`), NCTypeTrailer},
+	{regexp.MustCompile(`(?i)(^|(?P<show>.*?)[ _.\-(]+)(CREDITLESS|NCOP|NCED|OP|ED) ?(?P<ep>\d{1,2}[a-z]?)? ?([ _.\-)]+(?P<title>.*))?`), NCTypeOpening},
+}
+
 func ValueContainsNC(val string) bool {
+	_, _, ok := ExtractNC(val)
+	return ok
+}
+
+// ExtractNC returns the kind and the number of a NC file.
+// e.g. "[Group] Show - NCED 2.mkv" -> NCTypeEnding, 2
+// The number is 0 if the value doesn't contain one.
+func ExtractNC(val string) (ncType NCType, number int, ok bool) {
 	re, err := regexp.Compile(`(?i)opus`)
 	if err == nil {
 		val = re.ReplaceAllString(val, "")
 	}
-	regexes := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)(^|(?P<show>.*?)[ _.\-(]+)(OP|NCOP|OPED) ?(?P<ep>\d{1,2}[a-z]?)? ?([ _.\-)]+(?P<title>.*))?`),
-		regexp.MustCompile(`(?i)(^|(?P<show>.*?)[ _.\-(]+)(ED|NCED) ?(?P<ep>\d{1,2}[a-z]?)? ?([ _.\-)]+(?P<title>.*))?`),
-		regexp.MustCompile(`(?i)(^|(?P<show>.*?)[ _.\-(]+)(TRAILER|PROMO|PV|T) ?(?P<ep>\d{1,2}) ?([ _.\-)]+(?P<title>.*))?`),
-		regexp.MustCompile(`(?i)(^|(?P<show>.*?)[ _.\-(]+)(O|OTHERS?)(?P<ep>\d{1,2}) ?[ _.\-)]+(?P<title>.*)`),
-		regexp.MustCompile(`(?i)(^|(?P<show>.*?)[ _.\-(]+)(CM|COMMERCIAL|AD) ?(?P<ep>\d{1,2}) ?([ _.\-)]+(?P<title>.*))?`),
-		regexp.MustCompile(`(?i)(^|(?P<show>.*?)[ _.\-(]+)(CREDITLESS|NCOP|NCED|OP|ED) ?(?P<ep>\d{1,2}[a-z]?)? ?([ _.\-)]+(?P<title>.*))?`),
-	}
 
-	for _, regex := range regexes {
-		if regex.MatchString(val) {
-			return true
+	for _, r := range ncRegexes {
+		matches := r.regex.FindStringSubmatch(val)
+		if matches == nil {
+			continue
+		}
+		ncType = r.ncType
+		// e.g. "NCED", "Creditless Ending"
+		if keyword := strings.ToUpper(matches[3]); ncType == NCTypeOpening && (keyword == "NCED" || keyword == "ED" ||
+			(keyword == "CREDITLESS" && strings.Contains(strings.ToLower(val), "ending"))) {
+			ncType = NCTypeEnding
 		}
+		ep := matches[r.regex.SubexpIndex("ep")]
+		ep = strings.TrimRightFunc(ep, func(r rune) bool {
+			return r < '0' || r > '9'
+		})
+		number, _ = strconv.Atoi(ep)
+		return ncType, number, true
 	}
 
-	return false
+	return "", 0, false
 }
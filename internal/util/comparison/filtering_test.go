@@ -280,6 +280,32 @@ func TestValueContainsNC(t *testing.T) {
 	}
 }
 
+func TestExtractNC(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedType   NCType
+		expectedNumber int
+		expectedOk     bool
+	}{
+		{"[Judas] Blue Lock - NCOP 2.mkv", NCTypeOpening, 2, true},
+		{"[Judas] Blue Lock - NCED.mkv", NCTypeEnding, 0, true},
+		{"[Group] Show - Creditless Ending.mkv", NCTypeEnding, 0, true},
+		{"[Group] Show - PV 01.mkv", NCTypeTrailer, 1, true},
+		{"[Group] Show - CM 2.mkv", NCTypeTrailer, 2, true},
+		{"[Group] Show - O1 - Menu.mkv", NCTypeOther, 1, true},
+		{"Himouto.Umaru.chan.S01E02.1080p.BluRay.Opus2.0.x265-smol", "", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			ncType, number, ok := ExtractNC(test.input)
+			if ncType != test.expectedType || number != test.expectedNumber || ok != test.expectedOk {
+				t.Errorf("ExtractNC() with args %v, expected (%v, %v, %v), but got (%v, %v, %v).", test.input, test.expectedType, test.expectedNumber, test.expectedOk, ncType, number, ok)
+			}
+		})
+	}
+}
+
 //func TestLikelyNC(t *testing.T) {
 //	tests := []struct {
 //		name     string
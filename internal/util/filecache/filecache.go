@@ -6,6 +6,7 @@ import (
 	"github.com/samber/lo"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -378,6 +379,102 @@ func (c *Cacher) RemoveAllBy(filter func(filename string) bool) error {
 	return err
 }
 
+// RemoveExpired removes the expired items of all buckets and deletes the bucket files that are left empty.
+// Buckets that are not in use are filtered from their file without being loaded into memory.
+// It returns the number of removed items.
+func (c *Cacher) RemoveExpired() (int, error) {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return 0, fmt.Errorf("filecache: failed to read cache directory: %w", err)
+	}
+
+	removed := 0
+	now := time.Now()
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".cache") {
+			continue
+		}
+		name := strings.TrimSuffix(file.Name(), ".cache")
+
+		c.mu.Lock()
+		store, loaded := c.stores[name]
+		if !loaded {
+			// Hold the lock so that the bucket isn't loaded while its file is rewritten
+			count, err := removeExpiredFromFile(filepath.Join(c.dir, file.Name()), now)
+			c.mu.Unlock()
+			if err == nil {
+				removed += count
+			}
+			continue
+		}
+		c.mu.Unlock()
+
+		store.mu.Lock()
+		count := 0
+		for key, item := range store.data {
+			if item.Expiration != nil && now.After(*item.Expiration) {
+				delete(store.data, key)
+				count++
+			}
+		}
+		if len(store.data) == 0 {
+			_ = os.Remove(store.filePath)
+			c.mu.Lock()
+			delete(c.stores, name)
+			c.mu.Unlock()
+		} else if count > 0 {
+			_ = store.saveToFile()
+		}
+		store.mu.Unlock()
+		removed += count
+	}
+
+	return removed, nil
+}
+
+// rawCacheItem is a cache item whose value is not decoded.
+type rawCacheItem struct {
+	Value      json.RawMessage `json:"value"`
+	Expiration *time.Time      `json:"expiration,omitempty"`
+}
+
+// removeExpiredFromFile removes the expired items of a bucket file, the file is deleted if no items are left.
+// The file is only rewritten if items expired.
+func removeExpiredFromFile(path string, now time.Time) (int, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var data map[string]*rawCacheItem
+	if err := json.Unmarshal(b, &data); err != nil {
+		return 0, fmt.Errorf("filecache: failed to decode cache data: %w", err)
+	}
+
+	count := 0
+	for key, item := range data {
+		if item != nil && item.Expiration != nil && now.After(*item.Expiration) {
+			delete(data, key)
+			count++
+		}
+	}
+
+	switch {
+	case len(data) == 0:
+		_ = os.Remove(path)
+	case count > 0:
+		b, err = json.Marshal(data)
+		if err != nil {
+			return 0, fmt.Errorf("filecache: failed to encode cache data: %w", err)
+		}
+		if err := os.WriteFile(path, b, 0644); err != nil {
+			return 0, fmt.Errorf("filecache: failed to write cache file: %w", err)
+		}
+	}
+
+	return count, nil
+}
+
 // ClearMediastreamVideoFiles clears all mediastream video file caches.
 func (c *Cacher) ClearMediastreamVideoFiles() error {
 	c.mu.Lock()
@@ -403,26 +500,67 @@ func (c *Cacher) ClearMediastreamVideoFiles() error {
 	return err
 }
 
-// TrimMediastreamVideoFiles clears all mediastream video file caches if the number of files exceeds the given limit.
+const (
+	// MaxMediastreamVideoFiles is the number of video file caches kept by TrimMediastreamVideoFiles.
+	MaxMediastreamVideoFiles = 50
+	// MaxMediastreamVideoFilesSize is the total size of the video file caches kept by TrimMediastreamVideoFiles (bytes).
+	MaxMediastreamVideoFilesSize = 2 * 1024 * 1024 * 1024
+)
+
+// TrimMediastreamVideoFiles removes the least recently updated mediastream video file caches
+// until there are at most MaxMediastreamVideoFiles of them and their total size is under MaxMediastreamVideoFilesSize.
 func (c *Cacher) TrimMediastreamVideoFiles() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// Remove the contents of the "videofiles" cache directory
-	files, err := os.ReadDir(filepath.Join(c.dir, "videofiles"))
+	dir := filepath.Join(c.dir, "videofiles")
+	files, err := os.ReadDir(dir)
 	if err != nil {
 		return nil
 	}
 
-	// If the number of files exceeds 10, remove all files
-	if len(files) > 10 {
-		for _, file := range files {
-			_ = os.RemoveAll(filepath.Join(c.dir, "videofiles", file.Name()))
+	type entry struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+
+	entries := make([]*entry, 0, len(files))
+	var totalSize int64
+	for _, file := range files {
+		e := &entry{name: file.Name()}
+		if info, err := file.Info(); err == nil {
+			e.modTime = info.ModTime()
 		}
+		_ = filepath.Walk(filepath.Join(dir, file.Name()), func(_ string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if !info.IsDir() {
+				e.size += info.Size()
+			}
+			if info.ModTime().After(e.modTime) {
+				e.modTime = info.ModTime()
+			}
+			return nil
+		})
+		totalSize += e.size
+		entries = append(entries, e)
+	}
+
+	// Oldest first
+	slices.SortFunc(entries, func(a, b *entry) int {
+		return a.modTime.Compare(b.modTime)
+	})
+
+	for len(entries) > 0 && (len(entries) > MaxMediastreamVideoFiles || totalSize > MaxMediastreamVideoFilesSize) {
+		_ = os.RemoveAll(filepath.Join(dir, entries[0].name))
+		totalSize -= entries[0].size
+		entries = entries[1:]
 	}
 
 	c.stores = make(map[string]*CacheStore)
-	return err
+	return nil
 }
 
 func (c *Cacher) GetMediastreamVideoFilesTotalSize() (int64, error) {
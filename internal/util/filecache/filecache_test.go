@@ -1,9 +1,11 @@
 package filecache
 
 import (
+	"fmt"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"os"
 	"path/filepath"
 	"seanime/internal/test_utils"
 	"sync"
@@ -160,3 +162,90 @@ func TestCacherSetAndGet(t *testing.T) {
 	wg.Wait()
 
 }
+
+func TestTrimMediastreamVideoFiles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	cacher, err := NewCacher(filepath.Join(tempDir, "cache"))
+	require.NoError(t, err)
+
+	now := time.Now()
+	count := MaxMediastreamVideoFiles + 5
+	for i := 0; i < count; i++ {
+		dir := filepath.Join(tempDir, "cache", "videofiles", fmt.Sprintf("hash%d", i), "trickplay")
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		file := filepath.Join(dir, "thumbnails.vtt")
+		require.NoError(t, os.WriteFile(file, []byte("WEBVTT"), 0644))
+		// The first directories are the oldest
+		modTime := now.Add(time.Duration(i-count) * time.Hour)
+		require.NoError(t, os.Chtimes(file, modTime, modTime))
+		require.NoError(t, os.Chtimes(dir, modTime, modTime))
+		require.NoError(t, os.Chtimes(filepath.Dir(dir), modTime, modTime))
+	}
+
+	err = cacher.TrimMediastreamVideoFiles()
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(filepath.Join(tempDir, "cache", "videofiles"))
+	require.NoError(t, err)
+	require.Len(t, entries, MaxMediastreamVideoFiles)
+
+	for i := 0; i < count; i++ {
+		_, err := os.Stat(filepath.Join(tempDir, "cache", "videofiles", fmt.Sprintf("hash%d", i)))
+		if i < count-MaxMediastreamVideoFiles {
+			assert.True(t, os.IsNotExist(err), "hash%d should have been removed", i)
+		} else {
+			assert.NoError(t, err, "hash%d should have been kept", i)
+		}
+	}
+}
+
+func TestRemoveExpired(t *testing.T) {
+	cacher, err := NewCacher(filepath.Join(t.TempDir(), "cache"))
+	require.NoError(t, err)
+
+	expiredBucket := NewBucket("expired", time.Millisecond)
+	mixedBucket := NewBucket("mixed", time.Millisecond)
+	permBucket := NewPermanentBucket("perm")
+
+	require.NoError(t, cacher.Set(expiredBucket, "a", 1))
+	require.NoError(t, cacher.Set(mixedBucket, "b", 2))
+	require.NoError(t, cacher.SetPerm(permBucket, "c", 3))
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, cacher.Set(NewBucket("mixed", time.Hour), "d", 4))
+
+	removed, err := cacher.RemoveExpired()
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	_, err = os.Stat(filepath.Join(cacher.dir, "expired.cache"))
+	assert.True(t, os.IsNotExist(err))
+
+	var out int
+	found, err := cacher.Get(mixedBucket, "d", &out)
+	require.NoError(t, err)
+	assert.True(t, found)
+	found, err = cacher.GetPerm(permBucket, "c", &out)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	// Buckets that are not loaded are filtered from their file
+	require.NoError(t, cacher.Set(NewBucket("unloaded", time.Millisecond), "e", 5))
+	require.NoError(t, cacher.Set(NewBucket("unloaded", time.Hour), "f", 6))
+	time.Sleep(5 * time.Millisecond)
+	cacher, err = NewCacher(cacher.dir)
+	require.NoError(t, err)
+
+	removed, err = cacher.RemoveExpired()
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Empty(t, cacher.stores)
+
+	found, err = cacher.Get(NewBucket("unloaded", time.Hour), "e", &out)
+	require.NoError(t, err)
+	assert.False(t, found)
+	found, err = cacher.Get(NewBucket("unloaded", time.Hour), "f", &out)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 6, out)
+}
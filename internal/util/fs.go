@@ -1,6 +1,7 @@
 package util
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -71,3 +72,35 @@ func IsSameDir(dir1, dir2 string) bool {
 	}
 	return absDir1 == absDir2
 }
+
+// LinkOrCopyFile creates a hard link to src at dst, falling back to copying the file
+// if the link cannot be created (e.g. different file systems).
+// The parent directories of dst are created if they don't exist.
+func LinkOrCopyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return err
+	}
+
+	return out.Close()
+}
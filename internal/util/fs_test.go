@@ -2,6 +2,8 @@ package util
 
 import (
 	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -67,3 +69,21 @@ func TestSameDir(t *testing.T) {
 		})
 	}
 }
+
+func TestLinkOrCopyFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	src := filepath.Join(tempDir, "src", "Show - 01.mkv")
+	require.NoError(t, os.MkdirAll(filepath.Dir(src), os.ModePerm))
+	require.NoError(t, os.WriteFile(src, []byte("data"), 0644))
+
+	dst := filepath.Join(tempDir, "library", "Show", "Show - 01.mkv")
+	require.NoError(t, LinkOrCopyFile(src, dst))
+
+	// The destination should still exist after the source is removed
+	require.NoError(t, os.RemoveAll(filepath.Join(tempDir, "src")))
+
+	data, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, "data", string(data))
+}
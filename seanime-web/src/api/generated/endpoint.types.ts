@@ -12,30 +12,39 @@ import type {
     Anime_AutoDownloaderRule,
     Anime_AutoDownloaderRuleEpisodeType,
     Anime_AutoDownloaderRuleTitleComparisonType,
+    Anime_LocalFileFilter,
     Anime_LocalFileMetadata,
     ChapterDownloader_DownloadID,
     Continuity_UpdateWatchHistoryItemOptions,
     DebridClient_CancelStreamOptions,
     DebridClient_StreamPlaybackType,
     Debrid_TorrentItem,
+    DownloadManager_Priority,
     HibikeTorrent_AnimeTorrent,
     Mediastream_StreamType,
     Models_AnilistSettings,
     Models_DebridSettings,
     Models_DiscordSettings,
+    Models_DownloadManagerSettings,
+    Models_EventWebhook,
     Models_LibrarySettings,
     Models_MangaSettings,
+    Models_MatchingRule,
     Models_MediaPlayerSettings,
     Models_MediastreamSettings,
+    Models_NotificationChannel,
     Models_NotificationSettings,
     Models_Theme,
     Models_TorrentSettings,
     Models_TorrentstreamSettings,
+    Models_TrackPreferenceSettings,
     Report_ClickLog,
     Report_ConsoleLog,
     Report_NetworkLog,
     Report_ReactQueryLog,
     RunPlaygroundCodeParams,
+    Scanner_ScanLogFilter,
+    SeaDex_LibraryUpgrade,
     Torrentstream_PlaybackType,
 } from "@/api/generated/types.ts"
 
@@ -333,6 +342,7 @@ export type CreateAutoDownloaderRule_Variables = {
     episodeType: Anime_AutoDownloaderRuleEpisodeType
     episodeNumbers?: Array<number>
     destination: string
+    destinationRoot: string
 }
 
 /**
@@ -516,6 +526,35 @@ export type DebridCancelStream_Variables = {
     options?: DebridClient_CancelStreamOptions
 }
 
+/**
+ * - Filepath: internal/handlers/debrid.go
+ * - Filename: debrid.go
+ * - Endpoint: /api/v1/debrid/cloud/{id}
+ * @description
+ * Route get the files of an anime available in the cloud.
+ */
+export type DebridGetCloudFiles_Variables = {
+    /**
+     *  AniList anime media ID
+     */
+    id: number
+}
+
+/**
+ * - Filepath: internal/handlers/debrid.go
+ * - Filename: debrid.go
+ * - Endpoint: /api/v1/debrid/cloud/stream
+ * @description
+ * Route stream an episode available in the cloud.
+ */
+export type DebridStartCloudStream_Variables = {
+    mediaId: number
+    episodeNumber: number
+    aniDBEpisode: string
+    playbackType: DebridClient_StreamPlaybackType
+    clientId: string
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // directory_selector
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -597,6 +636,93 @@ export type DownloadRelease_Variables = {
     destination: string
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// download_manager
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/handlers/download_manager.go
+ * - Filename: download_manager.go
+ * - Endpoint: /api/v1/download-manager/pause
+ * @description
+ * Route pauses or resumes all downloads.
+ */
+export type DownloadManagerSetPaused_Variables = {
+    paused: boolean
+}
+
+/**
+ * - Filepath: internal/handlers/download_manager.go
+ * - Filename: download_manager.go
+ * - Endpoint: /api/v1/download-manager/job/action
+ * @description
+ * Route pauses or resumes a download.
+ */
+export type DownloadManagerJobAction_Variables = {
+    id: string
+    action: string
+}
+
+/**
+ * - Filepath: internal/handlers/download_manager.go
+ * - Filename: download_manager.go
+ * - Endpoint: /api/v1/download-manager/job/priority
+ * @description
+ * Route sets the priority of a download.
+ */
+export type DownloadManagerSetJobPriority_Variables = {
+    id: string
+    priority: DownloadManager_Priority
+}
+
+/**
+ * - Filepath: internal/handlers/download_manager.go
+ * - Filename: download_manager.go
+ * - Endpoint: /api/v1/download-manager/settings
+ * @description
+ * Route saves the download manager settings.
+ */
+export type SaveDownloadManagerSettings_Variables = {
+    settings: Models_DownloadManagerSettings
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// event_stream
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/handlers/event_stream.go
+ * - Filename: event_stream.go
+ * - Endpoint: /api/v1/event-stream/webhook
+ * @description
+ * Route creates or updates a webhook.
+ */
+export type SaveEventWebhook_Variables = {
+    webhook?: Models_EventWebhook
+}
+
+/**
+ * - Filepath: internal/handlers/event_stream.go
+ * - Filename: event_stream.go
+ * - Endpoint: /api/v1/event-stream/webhook
+ * @description
+ * Route deletes a webhook.
+ */
+export type DeleteEventWebhook_Variables = {
+    id: number
+}
+
+/**
+ * - Filepath: internal/handlers/event_stream.go
+ * - Filename: event_stream.go
+ * - Endpoint: /api/v1/event-stream/webhook/test
+ * @description
+ * Route sends a 'ping' event to a webhook.
+ */
+export type TestEventWebhook_Variables = {
+    webhook?: Models_EventWebhook
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // explorer
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -711,10 +837,25 @@ export type RemoveFileCacheBucket_Variables = {
     bucket: string
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// library_roots
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // localfiles
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+/**
+ * - Filepath: internal/handlers/localfiles.go
+ * - Filename: localfiles.go
+ * - Endpoint: /api/v1/library/local-files/filter
+ * @description
+ * Route returns the local files matching the filter.
+ */
+export type FilterLocalFiles_Variables = {
+    filter?: Anime_LocalFileFilter
+}
+
 /**
  * - Filepath: internal/handlers/localfiles.go
  * - Filename: localfiles.go
@@ -1028,6 +1169,32 @@ export type DeleteMangaDownloadedChapters_Variables = {
 // manual_dump
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// matching_rules
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/handlers/matching_rules.go
+ * - Filename: matching_rules.go
+ * - Endpoint: /api/v1/library/matching-rule
+ * @description
+ * Route creates or updates a matching rule.
+ */
+export type SaveMatchingRule_Variables = {
+    rule?: Models_MatchingRule
+}
+
+/**
+ * - Filepath: internal/handlers/matching_rules.go
+ * - Filename: matching_rules.go
+ * - Endpoint: /api/v1/library/matching-rule
+ * @description
+ * Route deletes a matching rule.
+ */
+export type DeleteMatchingRule_Variables = {
+    id: number
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // mediaplayer
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1074,6 +1241,17 @@ export type PreloadMediastreamMediaContainer_Variables = {
     audioStreamIndex: number
 }
 
+/**
+ * - Filepath: internal/handlers/mediastream.go
+ * - Filename: mediastream.go
+ * - Endpoint: /api/v1/mediastream/trickplay
+ * @description
+ * Route requests the seek preview thumbnails of a file.
+ */
+export type RequestMediastreamTrickplay_Variables = {
+    path: string
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // metadata
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1122,6 +1300,43 @@ export type RemoveFillerData_Variables = {
     mediaId: number
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// notifications
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/handlers/notifications.go
+ * - Filename: notifications.go
+ * - Endpoint: /api/v1/notifications/channel
+ * @description
+ * Route creates or updates a notification channel.
+ */
+export type SaveNotificationChannel_Variables = {
+    channel?: Models_NotificationChannel
+}
+
+/**
+ * - Filepath: internal/handlers/notifications.go
+ * - Filename: notifications.go
+ * - Endpoint: /api/v1/notifications/channel
+ * @description
+ * Route deletes a notification channel.
+ */
+export type DeleteNotificationChannel_Variables = {
+    id: number
+}
+
+/**
+ * - Filepath: internal/handlers/notifications.go
+ * - Filename: notifications.go
+ * - Endpoint: /api/v1/notifications/channel/test
+ * @description
+ * Route sends a test notification through a channel.
+ */
+export type TestNotificationChannel_Variables = {
+    channel?: Models_NotificationChannel
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // onlinestream
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1214,6 +1429,33 @@ export type RemoveOnlinestreamMapping_Variables = {
     mediaId: number
 }
 
+/**
+ * - Filepath: internal/handlers/onlinestream.go
+ * - Filename: onlinestream.go
+ * - Endpoint: /api/v1/onlinestream/download
+ * @description
+ * Route adds online stream episodes to the download queue.
+ */
+export type OnlinestreamDownloadEpisodes_Variables = {
+    mediaId: number
+    provider: string
+    dubbed: boolean
+    episodeNumbers: Array<number>
+    quality?: string
+    server?: string
+}
+
+/**
+ * - Filepath: internal/handlers/onlinestream.go
+ * - Filename: onlinestream.go
+ * - Endpoint: /api/v1/onlinestream/download-queue/cancel
+ * @description
+ * Route cancels a queued or running online stream download.
+ */
+export type CancelOnlinestreamDownload_Variables = {
+    id: string
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // playback_manager
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1345,6 +1587,10 @@ export type SaveIssueReport_Variables = {
     isAnimeLibraryIssue: boolean
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// routes
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // scan
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1362,10 +1608,133 @@ export type ScanLocalFiles_Variables = {
     skipIgnoredFiles: boolean
 }
 
+/**
+ * - Filepath: internal/handlers/scan.go
+ * - Filename: scan.go
+ * - Endpoint: /api/v1/library/scan/dry-run
+ * @description
+ * Route scans the user's library without saving the local files.
+ */
+export type DryRunScanLocalFiles_Variables = {
+    enhanced: boolean
+    skipLockedFiles: boolean
+    skipIgnoredFiles: boolean
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// scan_logs
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/handlers/scan_logs.go
+ * - Filename: scan_logs.go
+ * - Endpoint: /api/v1/library/scan-logs/entries
+ * @description
+ * Route returns the entries of a scan log.
+ */
+export type GetScanLogEntries_Variables = {
+    name: string
+    filter?: Scanner_ScanLogFilter
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // scan_summary
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+/**
+ * - Filepath: internal/handlers/scan_summary.go
+ * - Filename: scan_summary.go
+ * - Endpoint: /api/v1/library/scan-diff/apply
+ * @description
+ * Route saves the approved changes of a dry run scan.
+ */
+export type ApplyScanDiff_Variables = {
+    summaryId: string
+    changeIds: Array<string>
+    acceptAll: boolean
+}
+
+/**
+ * - Filepath: internal/handlers/scan_summary.go
+ * - Filename: scan_summary.go
+ * - Endpoint: /api/v1/library/scan-diff/discard
+ * @description
+ * Route discards the changes of a dry run scan.
+ */
+export type DiscardScanDiff_Variables = {
+    summaryId: string
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// scheduler
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/handlers/scheduler.go
+ * - Filename: scheduler.go
+ * - Endpoint: /api/v1/scheduler/job
+ * @description
+ * Route updates the schedule of a job and enables or disables it.
+ */
+export type UpdateScheduledJob_Variables = {
+    name: string
+    schedule: string
+    enabled: boolean
+}
+
+/**
+ * - Filepath: internal/handlers/scheduler.go
+ * - Filename: scheduler.go
+ * - Endpoint: /api/v1/scheduler/job/run
+ * @description
+ * Route runs a job immediately.
+ */
+export type RunScheduledJob_Variables = {
+    name: string
+}
+
+/**
+ * - Filepath: internal/handlers/scheduler.go
+ * - Filename: scheduler.go
+ * - Endpoint: /api/v1/scheduler/job/{name}/history
+ * @description
+ * Route returns the most recent runs of a job.
+ */
+export type GetScheduledJobHistory_Variables = {
+    /**
+     *  The name of the job
+     */
+    name: string
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// seadex
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/handlers/seadex.go
+ * - Filename: seadex.go
+ * - Endpoint: /api/v1/seadex/library-analysis
+ * @description
+ * Route returns the SeaDex release analysis of the library.
+ */
+export type GetSeaDexLibraryAnalysis_Variables = {
+    preferDualAudio: boolean
+}
+
+/**
+ * - Filepath: internal/handlers/seadex.go
+ * - Filename: seadex.go
+ * - Endpoint: /api/v1/seadex/library-analysis/enqueue
+ * @description
+ * Route adds recommended SeaDex releases to the torrent client or the debrid service.
+ */
+export type SeaDexEnqueueReleases_Variables = {
+    items: Array<SeaDex_LibraryUpgrade>
+    target: string
+    destination: string
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // settings
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1424,6 +1793,32 @@ export type SaveAutoDownloaderSettings_Variables = {
     useDebrid: boolean
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// skip_segments
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/handlers/skip_segments.go
+ * - Filename: skip_segments.go
+ * - Endpoint: /api/v1/skip-segments
+ * @description
+ * Route returns the skip segments of a local file.
+ */
+export type GetSkipSegments_Variables = {
+    path: string
+}
+
+/**
+ * - Filepath: internal/handlers/skip_segments.go
+ * - Filename: skip_segments.go
+ * - Endpoint: /api/v1/skip-segments
+ * @description
+ * Route deletes the skip segments of a local file.
+ */
+export type DeleteSkipSegments_Variables = {
+    path: string
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // status
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1653,6 +2048,63 @@ export type GetTorrentstreamBatchHistory_Variables = {
     mediaId: number
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// track_preference
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/handlers/track_preference.go
+ * - Filename: track_preference.go
+ * - Endpoint: /api/v1/track-preferences
+ * @description
+ * Route save the track preferences.
+ */
+export type SaveTrackPreferenceSettings_Variables = {
+    settings: Models_TrackPreferenceSettings
+}
+
+/**
+ * - Filepath: internal/handlers/track_preference.go
+ * - Filename: track_preference.go
+ * - Endpoint: /api/v1/track-preferences/media/{id}
+ * @description
+ * Route get the track preferences of a media.
+ */
+export type GetMediaTrackPreference_Variables = {
+    /**
+     *  AniList anime media ID
+     */
+    id: number
+}
+
+/**
+ * - Filepath: internal/handlers/track_preference.go
+ * - Filename: track_preference.go
+ * - Endpoint: /api/v1/track-preferences/media
+ * @description
+ * Route save the track preferences of a media.
+ */
+export type SaveMediaTrackPreference_Variables = {
+    mediaId: number
+    audioLanguages: Array<string>
+    subtitleLanguages: Array<string>
+    signsAndSongs: string
+}
+
+/**
+ * - Filepath: internal/handlers/track_preference.go
+ * - Filename: track_preference.go
+ * - Endpoint: /api/v1/track-preferences/media/{id}
+ * @description
+ * Route delete the track preferences of a media.
+ */
+export type DeleteMediaTrackPreference_Variables = {
+    /**
+     *  AniList anime media ID
+     */
+    id: number
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // websocket
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
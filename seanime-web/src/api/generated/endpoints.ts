@@ -505,6 +505,38 @@ export const API_ENDPOINTS = {
             methods: ["POST"],
             endpoint: "/api/v1/debrid/stream/cancel",
         },
+        /**
+         *  @description
+         *  Route get the files of an anime available in the cloud.
+         *  This returns the files of completed debrid torrents that are mapped to the anime's episodes.
+         *  Torrents are analyzed the first time they are seen, this can take a while.
+         */
+        DebridGetCloudFiles: {
+            key: "DEBRID-debrid-get-cloud-files",
+            methods: ["GET"],
+            endpoint: "/api/v1/debrid/cloud/{id}",
+        },
+        /**
+         *  @description
+         *  Route refresh the list of torrents available in the cloud.
+         *  This fetches the completed debrid torrents and analyzes the new ones.
+         *  The client should refetch the library collection.
+         */
+        DebridRefreshCloudLibrary: {
+            key: "DEBRID-debrid-refresh-cloud-library",
+            methods: ["POST"],
+            endpoint: "/api/v1/debrid/cloud/refresh",
+        },
+        /**
+         *  @description
+         *  Route stream an episode available in the cloud.
+         *  This requests a new stream URL from the debrid service and sends it to the player.
+         */
+        DebridStartCloudStream: {
+            key: "DEBRID-debrid-start-cloud-stream",
+            methods: ["POST"],
+            endpoint: "/api/v1/debrid/cloud/stream",
+        },
     },
     DIRECTORY_SELECTOR: {
         /**
@@ -564,6 +596,126 @@ export const API_ENDPOINTS = {
             endpoint: "/api/v1/download-release",
         },
     },
+    DOWNLOAD_MANAGER: {
+        /**
+         *  @description
+         *  Route returns the downloads of all sources.
+         *  This includes the torrent client, debrid downloads, torrent streaming imports, the manga chapter queue and the online stream queue.
+         *  The client is notified of changes through the 'download-manager-updated' websocket event.
+         */
+        GetDownloadManagerSnapshot: {
+            key: "DOWNLOAD-MANAGER-get-download-manager-snapshot",
+            methods: ["GET"],
+            endpoint: "/api/v1/download-manager",
+        },
+        /**
+         *  @description
+         *  Route pauses or resumes all downloads.
+         *  Downloads paused by the user stay paused when all downloads are resumed.
+         */
+        DownloadManagerSetPaused: {
+            key: "DOWNLOAD-MANAGER-download-manager-set-paused",
+            methods: ["POST"],
+            endpoint: "/api/v1/download-manager/pause",
+        },
+        /**
+         *  @description
+         *  Route pauses or resumes a download.
+         *  'action' is either "pause" or "resume".
+         *  A resumed download is paused again if all downloads are paused or if it's outside the schedule windows and its priority isn't high.
+         */
+        DownloadManagerJobAction: {
+            key: "DOWNLOAD-MANAGER-download-manager-job-action",
+            methods: ["POST"],
+            endpoint: "/api/v1/download-manager/job/action",
+        },
+        /**
+         *  @description
+         *  Route sets the priority of a download.
+         *  'priority' is -1 (low), 0 (normal) or 1 (high).
+         *  High priority downloads ignore the schedule windows.
+         *  Low priority downloads are paused while a download with a higher priority from the same source is running.
+         */
+        DownloadManagerSetJobPriority: {
+            key: "DOWNLOAD-MANAGER-download-manager-set-job-priority",
+            methods: ["POST"],
+            endpoint: "/api/v1/download-manager/job/priority",
+        },
+        GetDownloadManagerSettings: {
+            key: "DOWNLOAD-MANAGER-get-download-manager-settings",
+            methods: ["GET"],
+            endpoint: "/api/v1/download-manager/settings",
+        },
+        /**
+         *  @description
+         *  Route saves the download manager settings.
+         *  Schedule windows use "HH:MM" times, a window ending before it starts ends on the next day.
+         *  The rate limit of a window is in KiB/s and applies to the torrent client and debrid downloads.
+         */
+        SaveDownloadManagerSettings: {
+            key: "DOWNLOAD-MANAGER-save-download-manager-settings",
+            methods: ["PATCH"],
+            endpoint: "/api/v1/download-manager/settings",
+        },
+    },
+    EVENT_STREAM: {
+        /**
+         *  @description
+         *  Route streams the public events as Server-Sent Events.
+         *  The 'types' query parameter is an optional comma-separated list of event types, all events are sent if it's empty.
+         *  Each message has the event ID, the event type and the JSON-encoded event as data.
+         *  Clients that reconnect with the 'Last-Event-ID' header receive the events they missed, as long as they are still in the history.
+         *  A comment is sent every 30 seconds to keep the connection alive.
+         */
+        EventStream: {
+            key: "EVENT-STREAM-event-stream",
+            methods: ["GET"],
+            endpoint: "/api/v1/event-stream",
+        },
+        /**
+         *  @description
+         *  Route returns the event types published to the event stream and the webhooks.
+         *  The payload of each type is documented in the eventstream package.
+         */
+        GetEventStreamTypes: {
+            key: "EVENT-STREAM-get-event-stream-types",
+            methods: ["GET"],
+            endpoint: "/api/v1/event-stream/types",
+        },
+        GetEventWebhooks: {
+            key: "EVENT-STREAM-get-event-webhooks",
+            methods: ["GET"],
+            endpoint: "/api/v1/event-stream/webhooks",
+        },
+        /**
+         *  @description
+         *  Route creates or updates a webhook.
+         *  A webhook with an ID of 0 is created, a secret is generated if it's empty.
+         *  When updating a webhook, an empty secret keeps the stored one.
+         *  Payloads are signed with HMAC-SHA256, the signature of "{timestamp}.{body}" is sent in the 'X-Seanime-Signature' header
+         *  and the Unix timestamp in the 'X-Seanime-Timestamp' header.
+         */
+        SaveEventWebhook: {
+            key: "EVENT-STREAM-save-event-webhook",
+            methods: ["POST"],
+            endpoint: "/api/v1/event-stream/webhook",
+        },
+        DeleteEventWebhook: {
+            key: "EVENT-STREAM-delete-event-webhook",
+            methods: ["DELETE"],
+            endpoint: "/api/v1/event-stream/webhook",
+        },
+        /**
+         *  @description
+         *  Route sends a 'ping' event to a webhook.
+         *  The webhook doesn't need to be saved. The event is sent once, without retries.
+         */
+        TestEventWebhook: {
+            key: "EVENT-STREAM-test-event-webhook",
+            methods: ["POST"],
+            endpoint: "/api/v1/event-stream/webhook/test",
+        },
+    },
     EXPLORER: {
         /**
          *  @description
@@ -691,6 +843,20 @@ export const API_ENDPOINTS = {
             endpoint: "/api/v1/filecache/mediastream/videofiles",
         },
     },
+    LIBRARY_ROOTS: {
+        /**
+         *  @description
+         *  Route returns the library directories with their settings and availability.
+         *  The directories are sorted by descending priority.
+         *  A directory is offline when it cannot be found, e.g. when its drive is unplugged. The local files of offline directories are kept.
+         *  An empty directory that had local files is offline too, it is likely the mount point of an unmounted drive.
+         */
+        GetLibraryRoots: {
+            key: "LIBRARY-ROOTS-get-library-roots",
+            methods: ["GET"],
+            endpoint: "/api/v1/library/roots",
+        },
+    },
     LOCALFILES: {
         /**
          *  @description
@@ -702,6 +868,17 @@ export const API_ENDPOINTS = {
             methods: ["GET"],
             endpoint: "/api/v1/library/local-files",
         },
+        /**
+         *  @description
+         *  Route returns the local files matching the filter.
+         *  Technical metadata (codecs, tracks, duration) is only available when media info probing is enabled in the library settings.
+         *  Files that were not probed are excluded as soon as the filter uses technical metadata.
+         */
+        FilterLocalFiles: {
+            key: "LOCALFILES-filter-local-files",
+            methods: ["POST"],
+            endpoint: "/api/v1/library/local-files/filter",
+        },
         /**
          *  @description
          *  Route imports local files from the given path.
@@ -749,6 +926,7 @@ export const API_ENDPOINTS = {
          *  @description
          *  Route deletes local files with the given paths.
          *  This will delete the local files with the given paths.
+         *  Files of read-only library directories cannot be deleted.
          *  The client should refetch the entire library collection and media entry.
          */
         DeleteLocalFiles: {
@@ -760,6 +938,7 @@ export const API_ENDPOINTS = {
          *  @description
          *  Route removes empty directories.
          *  This will remove empty directories in the library path.
+         *  Disabled, read-only and offline library directories are left untouched.
          */
         RemoveEmptyDirectories: {
             key: "LOCALFILES-remove-empty-directories",
@@ -1042,6 +1221,29 @@ export const API_ENDPOINTS = {
             endpoint: "/api/v1/test-dump",
         },
     },
+    MATCHING_RULES: {
+        GetMatchingRules: {
+            key: "MATCHING-RULES-get-matching-rules",
+            methods: ["GET"],
+            endpoint: "/api/v1/library/matching-rules",
+        },
+        /**
+         *  @description
+         *  Route creates or updates a matching rule.
+         *  A rule with an ID of 0 is created.
+         *  Rules are evaluated by the scanner before fuzzy matching, they apply to the next scan.
+         */
+        SaveMatchingRule: {
+            key: "MATCHING-RULES-save-matching-rule",
+            methods: ["POST"],
+            endpoint: "/api/v1/library/matching-rule",
+        },
+        DeleteMatchingRule: {
+            key: "MATCHING-RULES-delete-matching-rule",
+            methods: ["DELETE"],
+            endpoint: "/api/v1/library/matching-rule",
+        },
+    },
     MEDIAPLAYER: {
         StartDefaultMediaPlayer: {
             key: "MEDIAPLAYER-start-default-media-player",
@@ -1090,6 +1292,18 @@ export const API_ENDPOINTS = {
             methods: ["POST"],
             endpoint: "/api/v1/mediastream/preload",
         },
+        /**
+         *  @description
+         *  Route requests the seek preview thumbnails of a file.
+         *  This returns the state of the thumbnails and starts generating them if they do not exist.
+         *  Once ready, the WebVTT thumbnail track is served at /api/v1/mediastream/trickplay/{hash}/thumbnails.vtt.
+         *  It returns null if seek previews are disabled.
+         */
+        RequestMediastreamTrickplay: {
+            key: "MEDIASTREAM-request-mediastream-trickplay",
+            methods: ["POST"],
+            endpoint: "/api/v1/mediastream/trickplay",
+        },
         /**
          *  @description
          *  Route shuts down the transcode stream
@@ -1145,20 +1359,70 @@ export const API_ENDPOINTS = {
             endpoint: "/api/v1/metadata-provider/filler",
         },
     },
+    NOTIFICATIONS: {
+        /**
+         *  @description
+         *  Route returns the events that can be routed to notification channels.
+         *  Each event lists the variables its title and message templates can use.
+         */
+        GetNotificationEvents: {
+            key: "NOTIFICATIONS-get-notification-events",
+            methods: ["GET"],
+            endpoint: "/api/v1/notifications/events",
+        },
+        GetNotificationChannels: {
+            key: "NOTIFICATIONS-get-notification-channels",
+            methods: ["GET"],
+            endpoint: "/api/v1/notifications/channels",
+        },
+        /**
+         *  @description
+         *  Route creates or updates a notification channel.
+         *  A channel with an ID of 0 is created.
+         *  The channel's configuration and templates are validated before being saved.
+         */
+        SaveNotificationChannel: {
+            key: "NOTIFICATIONS-save-notification-channel",
+            methods: ["POST"],
+            endpoint: "/api/v1/notifications/channel",
+        },
+        DeleteNotificationChannel: {
+            key: "NOTIFICATIONS-delete-notification-channel",
+            methods: ["DELETE"],
+            endpoint: "/api/v1/notifications/channel",
+        },
+        /**
+         *  @description
+         *  Route sends a test notification through a channel.
+         *  The channel doesn't need to be saved, this can be used to check the configuration before saving it.
+         */
+        TestNotificationChannel: {
+            key: "NOTIFICATIONS-test-notification-channel",
+            methods: ["POST"],
+            endpoint: "/api/v1/notifications/channel/test",
+        },
+    },
     ONLINESTREAM: {
         /**
          *  @description
          *  Route returns the episode list for the given media and provider.
          *  It returns the episode list for the given media and provider.
          *  The episodes are cached using a file cache.
-         *  The episode list is just a list of episodes with no video sources, it's what the client uses to display the episodes and subsequently
-         *     fetch the sources. The episode list might be nil or empty if nothing could be found, but the media will always be returned.
+         *  The episode list is just a list of episodes with no video sources, it's what the client uses to display the episodes and subsequently fetch the sources.
+         *  The episode list might be nil or empty if nothing could be found, but the media will always be returned.
          */
         GetOnlineStreamEpisodeList: {
             key: "ONLINESTREAM-get-online-stream-episode-list",
             methods: ["POST"],
             endpoint: "/api/v1/onlinestream/episode-list",
         },
+        /**
+         *  @description
+         *  Route returns the video sources for the given media, episode number and provider.
+         *  The video sources are checked in parallel and ordered by availability and latency.
+         *  The best available source is flagged as recommended.
+         *  If no source is available, the episode servers are extracted again.
+         */
         GetOnlineStreamEpisodeSource: {
             key: "ONLINESTREAM-get-online-stream-episode-source",
             methods: ["POST"],
@@ -1212,6 +1476,32 @@ export const API_ENDPOINTS = {
             methods: ["POST"],
             endpoint: "/api/v1/onlinestream/remove-mapping",
         },
+        /**
+         *  @description
+         *  Route adds online stream episodes to the download queue.
+         *  The episodes are downloaded in the background, remuxed into MKV files with the subtitles embedded and saved in the library.
+         *  FFmpeg is required, the path set in the media streaming settings is used.
+         */
+        OnlinestreamDownloadEpisodes: {
+            key: "ONLINESTREAM-onlinestream-download-episodes",
+            methods: ["POST"],
+            endpoint: "/api/v1/onlinestream/download",
+        },
+        GetOnlinestreamDownloadQueue: {
+            key: "ONLINESTREAM-get-onlinestream-download-queue",
+            methods: ["GET"],
+            endpoint: "/api/v1/onlinestream/download-queue",
+        },
+        CancelOnlinestreamDownload: {
+            key: "ONLINESTREAM-cancel-onlinestream-download",
+            methods: ["POST"],
+            endpoint: "/api/v1/onlinestream/download-queue/cancel",
+        },
+        ClearOnlinestreamDownloadQueue: {
+            key: "ONLINESTREAM-clear-onlinestream-download-queue",
+            methods: ["DELETE"],
+            endpoint: "/api/v1/onlinestream/download-queue",
+        },
     },
     PLAYBACK_MANAGER: {
         /**
@@ -1364,6 +1654,11 @@ export const API_ENDPOINTS = {
             methods: ["DELETE"],
             endpoint: "/api/v1/playlist",
         },
+        /**
+         *  @description
+         *  Route returns all the local files of a playlist media entry that have not been watched.
+         *  Specials and NC files are listed after the main episodes, ordered by AniDB episode.
+         */
         GetPlaylistEpisodes: {
             key: "PLAYLIST-get-playlist-episodes",
             methods: ["GET"],
@@ -1411,12 +1706,61 @@ export const API_ENDPOINTS = {
          *  Route scans the user's library.
          *  This will scan the user's library.
          *  The response is ignored, the client should re-fetch the library after this.
+         *  If the scan is cancelled, an error is returned and the local files are left untouched.
+         *  If another scan is running, e.g. from the auto scanner, the scan starts once it has finished.
          */
         ScanLocalFiles: {
             key: "SCAN-scan-local-files",
             methods: ["POST"],
             endpoint: "/api/v1/library/scan",
         },
+        /**
+         *  @description
+         *  Route scans the user's library without saving the local files.
+         *  The changes the scan would make are returned in the diff of the scan summary.
+         *  They are saved once approved with HandleApplyScanDiff.
+         *  If no local files are found, a summary without changes is returned and not saved.
+         */
+        DryRunScanLocalFiles: {
+            key: "SCAN-dry-run-scan-local-files",
+            methods: ["POST"],
+            endpoint: "/api/v1/library/scan/dry-run",
+        },
+        /**
+         *  @description
+         *  Route cancels the running scans.
+         *  This cancels the scans started manually and by the auto scanner.
+         *  The results of cancelled scans are discarded, the local files are left untouched.
+         *  Returns false if no scan was running.
+         */
+        CancelScan: {
+            key: "SCAN-cancel-scan",
+            methods: ["POST"],
+            endpoint: "/api/v1/library/scan/cancel",
+        },
+    },
+    SCAN_LOGS: {
+        /**
+         *  @description
+         *  Route returns the scan logs.
+         *  The logs are sorted from newest to oldest.
+         */
+        GetScanLogs: {
+            key: "SCAN-LOGS-get-scan-logs",
+            methods: ["GET"],
+            endpoint: "/api/v1/library/scan-logs",
+        },
+        /**
+         *  @description
+         *  Route returns the entries of a scan log.
+         *  The entries can be filtered by file path, media ID, stage and decision.
+         *  e.g. filtering by the path of a file returns the entries explaining why it was matched to its media.
+         */
+        GetScanLogEntries: {
+            key: "SCAN-LOGS-get-scan-log-entries",
+            methods: ["POST"],
+            endpoint: "/api/v1/library/scan-logs/entries",
+        },
     },
     SCAN_SUMMARY: {
         GetScanSummaries: {
@@ -1424,6 +1768,91 @@ export const API_ENDPOINTS = {
             methods: ["GET"],
             endpoint: "/api/v1/library/scan-summaries",
         },
+        /**
+         *  @description
+         *  Route saves the approved changes of a dry run scan.
+         *  If 'acceptAll' is true, all the changes are saved, otherwise only the ones listed in 'changeIds'.
+         *  The changes are applied to the current local files, edits made since the dry run are kept.
+         */
+        ApplyScanDiff: {
+            key: "SCAN-SUMMARY-apply-scan-diff",
+            methods: ["POST"],
+            endpoint: "/api/v1/library/scan-diff/apply",
+        },
+        DiscardScanDiff: {
+            key: "SCAN-SUMMARY-discard-scan-diff",
+            methods: ["POST"],
+            endpoint: "/api/v1/library/scan-diff/discard",
+        },
+    },
+    SCHEDULER: {
+        /**
+         *  @description
+         *  Route returns the scheduled jobs.
+         *  The client is notified when a job starts, finishes or is updated through the 'scheduled-jobs-updated' websocket event.
+         */
+        GetScheduledJobs: {
+            key: "SCHEDULER-get-scheduled-jobs",
+            methods: ["GET"],
+            endpoint: "/api/v1/scheduler/jobs",
+        },
+        /**
+         *  @description
+         *  Route updates the schedule of a job and enables or disables it.
+         *  'schedule' is a 5-field cron expression (e.g. "0 4 * * *"), a descriptor (e.g. "@daily") or an interval (e.g. "@every 30m").
+         *  An empty schedule resets the job to its default schedule.
+         */
+        UpdateScheduledJob: {
+            key: "SCHEDULER-update-scheduled-job",
+            methods: ["PATCH"],
+            endpoint: "/api/v1/scheduler/job",
+        },
+        /**
+         *  @description
+         *  Route runs a job immediately.
+         *  The job runs in the background, even if it's disabled.
+         *  Returns an error if the job is already running.
+         */
+        RunScheduledJob: {
+            key: "SCHEDULER-run-scheduled-job",
+            methods: ["POST"],
+            endpoint: "/api/v1/scheduler/job/run",
+        },
+        /**
+         *  @description
+         *  Route returns the most recent runs of a job.
+         *  The runs are sorted from newest to oldest.
+         */
+        GetScheduledJobHistory: {
+            key: "SCHEDULER-get-scheduled-job-history",
+            methods: ["GET"],
+            endpoint: "/api/v1/scheduler/job/{name}/history",
+        },
+    },
+    SEADEX: {
+        /**
+         *  @description
+         *  Route returns the SeaDex release analysis of the library.
+         *  This compares the local files of each media with SeaDex's best and alternative releases.
+         *  Media that have no SeaDex entry are listed separately.
+         */
+        GetSeaDexLibraryAnalysis: {
+            key: "SEADEX-get-sea-dex-library-analysis",
+            methods: ["POST"],
+            endpoint: "/api/v1/seadex/library-analysis",
+        },
+        /**
+         *  @description
+         *  Route adds recommended SeaDex releases to the torrent client or the debrid service.
+         *  'target' is either "torrent-client" or "debrid".
+         *  If no destination is provided, the releases are downloaded to the library directory with the highest priority that is enabled, writable and online.
+         *  An error is returned if the destination is in a library directory that is disabled, read-only or offline.
+         */
+        SeaDexEnqueueReleases: {
+            key: "SEADEX-sea-dex-enqueue-releases",
+            methods: ["POST"],
+            endpoint: "/api/v1/seadex/library-analysis/enqueue",
+        },
     },
     SETTINGS: {
         GetSettings: {
@@ -1459,6 +1888,30 @@ export const API_ENDPOINTS = {
             endpoint: "/api/v1/settings/auto-downloader",
         },
     },
+    SKIP_SEGMENTS: {
+        /**
+         *  @description
+         *  Route returns the skip segments of a local file.
+         *  The intro and outro are read from the chapters of the file when possible.
+         *  Otherwise, they are detected in the background by comparing the audio with another episode of the series.
+         *  It returns null while detection is in progress, the markers are then sent through the "skip-segments-updated" websocket event.
+         */
+        GetSkipSegments: {
+            key: "SKIP-SEGMENTS-get-skip-segments",
+            methods: ["POST"],
+            endpoint: "/api/v1/skip-segments",
+        },
+        /**
+         *  @description
+         *  Route deletes the skip segments of a local file.
+         *  The skip segments will be derived again the next time they are requested.
+         */
+        DeleteSkipSegments: {
+            key: "SKIP-SEGMENTS-delete-skip-segments",
+            methods: ["DELETE"],
+            endpoint: "/api/v1/skip-segments",
+        },
+    },
     STATUS: {
         /**
          *  @description
@@ -1687,6 +2140,7 @@ export const API_ENDPOINTS = {
          *  @description
          *  Route starts a torrent stream.
          *  This starts the entire streaming process.
+         *  Each client has its own stream, starting a stream only replaces the stream of the same client.
          */
         TorrentstreamStartStream: {
             key: "TORRENTSTREAM-torrentstream-start-stream",
@@ -1696,7 +2150,7 @@ export const API_ENDPOINTS = {
         /**
          *  @description
          *  Route stop a torrent stream.
-         *  This stops the entire streaming process and drops the torrent if it's below a threshold.
+         *  This stops the streaming process of the client and drops the torrent if it's below a threshold.
          *  This is made to be used while the stream is running.
          */
         TorrentstreamStopStream: {
@@ -1707,7 +2161,8 @@ export const API_ENDPOINTS = {
         /**
          *  @description
          *  Route drops a torrent stream.
-         *  This stops the entire streaming process and drops the torrent completely.
+         *  This stops the streaming process of the client and drops the torrent completely.
+         *  The torrent is kept if another client is streaming from it.
          *  This is made to be used to force drop a torrent.
          */
         TorrentstreamDropTorrent: {
@@ -1725,6 +2180,87 @@ export const API_ENDPOINTS = {
             methods: ["POST"],
             endpoint: "/api/v1/torrentstream/batch-history",
         },
+        /**
+         *  @description
+         *  Route returns the active torrent streams.
+         *  This returns a snapshot of the streams of all clients.
+         */
+        GetTorrentstreamSessions: {
+            key: "TORRENTSTREAM-get-torrentstream-sessions",
+            methods: ["GET"],
+            endpoint: "/api/v1/torrentstream/sessions",
+        },
+        /**
+         *  @description
+         *  Route keeps the torrent of the client's stream and imports it into the library.
+         *  The torrent is downloaded in the background after the stream ends, its files are added to the library and scanned.
+         *  The torrent is seeded until the seed ratio or time limit is reached.
+         */
+        TorrentstreamKeepAndImport: {
+            key: "TORRENTSTREAM-torrentstream-keep-and-import",
+            methods: ["POST"],
+            endpoint: "/api/v1/torrentstream/import",
+        },
+        GetTorrentstreamImportJobs: {
+            key: "TORRENTSTREAM-get-torrentstream-import-jobs",
+            methods: ["GET"],
+            endpoint: "/api/v1/torrentstream/imports",
+        },
+    },
+    TRACK_PREFERENCE: {
+        /**
+         *  @description
+         *  Route get the track preferences.
+         *  This returns the preferred audio and subtitle languages used by the media players, media streaming and online streaming.
+         */
+        GetTrackPreferenceSettings: {
+            key: "TRACK-PREFERENCE-get-track-preference-settings",
+            methods: ["GET"],
+            endpoint: "/api/v1/track-preferences",
+        },
+        /**
+         *  @description
+         *  Route save the track preferences.
+         *  This saves the preferred audio and subtitle languages.
+         *  Languages can be ISO 639-1 or ISO 639-2 codes and are ordered by priority.
+         */
+        SaveTrackPreferenceSettings: {
+            key: "TRACK-PREFERENCE-save-track-preference-settings",
+            methods: ["PATCH"],
+            endpoint: "/api/v1/track-preferences",
+        },
+        /**
+         *  @description
+         *  Route get the track preferences of a media.
+         *  This returns the overrides of the track preferences for the media.
+         *  It returns null if the media has no overrides.
+         */
+        GetMediaTrackPreference: {
+            key: "TRACK-PREFERENCE-get-media-track-preference",
+            methods: ["GET"],
+            endpoint: "/api/v1/track-preferences/media/{id}",
+        },
+        /**
+         *  @description
+         *  Route save the track preferences of a media.
+         *  This overrides the track preferences for the media.
+         *  Empty fields fall back to the global track preferences.
+         */
+        SaveMediaTrackPreference: {
+            key: "TRACK-PREFERENCE-save-media-track-preference",
+            methods: ["POST"],
+            endpoint: "/api/v1/track-preferences/media",
+        },
+        /**
+         *  @description
+         *  Route delete the track preferences of a media.
+         *  This removes the overrides, the global track preferences will be used for the media.
+         */
+        DeleteMediaTrackPreference: {
+            key: "TRACK-PREFERENCE-delete-media-track-preference",
+            methods: ["DELETE"],
+            endpoint: "/api/v1/track-preferences/media/{id}",
+        },
     },
 } satisfies ApiEndpoints
 
@@ -540,6 +540,37 @@
 //     })
 // }
 
+// export function useDebridGetCloudFiles(id: number) {
+//     return useServerQuery<Array<DebridClient_CloudFile>>({
+//         endpoint: API_ENDPOINTS.DEBRID.DebridGetCloudFiles.endpoint.replace("{id}", String(id)),
+//         method: API_ENDPOINTS.DEBRID.DebridGetCloudFiles.methods[0],
+//         queryKey: [API_ENDPOINTS.DEBRID.DebridGetCloudFiles.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useDebridRefreshCloudLibrary() {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.DEBRID.DebridRefreshCloudLibrary.endpoint,
+//         method: API_ENDPOINTS.DEBRID.DebridRefreshCloudLibrary.methods[0],
+//         mutationKey: [API_ENDPOINTS.DEBRID.DebridRefreshCloudLibrary.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDebridStartCloudStream() {
+//     return useServerMutation<boolean, DebridStartCloudStream_Variables>({
+//         endpoint: API_ENDPOINTS.DEBRID.DebridStartCloudStream.endpoint,
+//         method: API_ENDPOINTS.DEBRID.DebridStartCloudStream.methods[0],
+//         mutationKey: [API_ENDPOINTS.DEBRID.DebridStartCloudStream.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // directory_selector
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -631,6 +662,136 @@
 //     })
 // }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// download_manager
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useGetDownloadManagerSnapshot() {
+//     return useServerQuery<DownloadManager_Snapshot>({
+//         endpoint: API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSnapshot.endpoint,
+//         method: API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSnapshot.methods[0],
+//         queryKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSnapshot.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useDownloadManagerSetPaused() {
+//     return useServerMutation<DownloadManager_Snapshot, DownloadManagerSetPaused_Variables>({
+//         endpoint: API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerSetPaused.endpoint,
+//         method: API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerSetPaused.methods[0],
+//         mutationKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerSetPaused.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDownloadManagerJobAction() {
+//     return useServerMutation<DownloadManager_Snapshot, DownloadManagerJobAction_Variables>({
+//         endpoint: API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerJobAction.endpoint,
+//         method: API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerJobAction.methods[0],
+//         mutationKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerJobAction.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDownloadManagerSetJobPriority() {
+//     return useServerMutation<DownloadManager_Snapshot, DownloadManagerSetJobPriority_Variables>({
+//         endpoint: API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerSetJobPriority.endpoint,
+//         method: API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerSetJobPriority.methods[0],
+//         mutationKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerSetJobPriority.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useGetDownloadManagerSettings() {
+//     return useServerQuery<Models_DownloadManagerSettings>({
+//         endpoint: API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSettings.endpoint,
+//         method: API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSettings.methods[0],
+//         queryKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSettings.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useSaveDownloadManagerSettings() {
+//     return useServerMutation<Models_DownloadManagerSettings, SaveDownloadManagerSettings_Variables>({
+//         endpoint: API_ENDPOINTS.DOWNLOAD_MANAGER.SaveDownloadManagerSettings.endpoint,
+//         method: API_ENDPOINTS.DOWNLOAD_MANAGER.SaveDownloadManagerSettings.methods[0],
+//         mutationKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.SaveDownloadManagerSettings.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// event_stream
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useEventStream() {
+//     return useServerQuery<EventStream_Event>({
+//         endpoint: API_ENDPOINTS.EVENT_STREAM.EventStream.endpoint,
+//         method: API_ENDPOINTS.EVENT_STREAM.EventStream.methods[0],
+//         queryKey: [API_ENDPOINTS.EVENT_STREAM.EventStream.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useGetEventStreamTypes() {
+//     return useServerQuery<Array<EventStream_TypeInfo>>({
+//         endpoint: API_ENDPOINTS.EVENT_STREAM.GetEventStreamTypes.endpoint,
+//         method: API_ENDPOINTS.EVENT_STREAM.GetEventStreamTypes.methods[0],
+//         queryKey: [API_ENDPOINTS.EVENT_STREAM.GetEventStreamTypes.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useGetEventWebhooks() {
+//     return useServerQuery<Array<Models_EventWebhook>>({
+//         endpoint: API_ENDPOINTS.EVENT_STREAM.GetEventWebhooks.endpoint,
+//         method: API_ENDPOINTS.EVENT_STREAM.GetEventWebhooks.methods[0],
+//         queryKey: [API_ENDPOINTS.EVENT_STREAM.GetEventWebhooks.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useSaveEventWebhook() {
+//     return useServerMutation<Models_EventWebhook, SaveEventWebhook_Variables>({
+//         endpoint: API_ENDPOINTS.EVENT_STREAM.SaveEventWebhook.endpoint,
+//         method: API_ENDPOINTS.EVENT_STREAM.SaveEventWebhook.methods[0],
+//         mutationKey: [API_ENDPOINTS.EVENT_STREAM.SaveEventWebhook.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDeleteEventWebhook() {
+//     return useServerMutation<boolean, DeleteEventWebhook_Variables>({
+//         endpoint: API_ENDPOINTS.EVENT_STREAM.DeleteEventWebhook.endpoint,
+//         method: API_ENDPOINTS.EVENT_STREAM.DeleteEventWebhook.methods[0],
+//         mutationKey: [API_ENDPOINTS.EVENT_STREAM.DeleteEventWebhook.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useTestEventWebhook() {
+//     return useServerMutation<boolean, TestEventWebhook_Variables>({
+//         endpoint: API_ENDPOINTS.EVENT_STREAM.TestEventWebhook.endpoint,
+//         method: API_ENDPOINTS.EVENT_STREAM.TestEventWebhook.methods[0],
+//         mutationKey: [API_ENDPOINTS.EVENT_STREAM.TestEventWebhook.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // explorer
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -827,6 +988,19 @@
 //     })
 // }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// library_roots
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useGetLibraryRoots() {
+//     return useServerQuery<Array<Filesystem_LibraryRootStatus>>({
+//         endpoint: API_ENDPOINTS.LIBRARY_ROOTS.GetLibraryRoots.endpoint,
+//         method: API_ENDPOINTS.LIBRARY_ROOTS.GetLibraryRoots.methods[0],
+//         queryKey: [API_ENDPOINTS.LIBRARY_ROOTS.GetLibraryRoots.key],
+//         enabled: true,
+//     })
+// }
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // localfiles
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -840,6 +1014,17 @@
 //     })
 // }
 
+// export function useFilterLocalFiles() {
+//     return useServerMutation<Array<Anime_LocalFile>, FilterLocalFiles_Variables>({
+//         endpoint: API_ENDPOINTS.LOCALFILES.FilterLocalFiles.endpoint,
+//         method: API_ENDPOINTS.LOCALFILES.FilterLocalFiles.methods[0],
+//         mutationKey: [API_ENDPOINTS.LOCALFILES.FilterLocalFiles.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 // export function useImportLocalFiles() {
 //     return useServerMutation<boolean, ImportLocalFiles_Variables>({
 //         endpoint: API_ENDPOINTS.LOCALFILES.ImportLocalFiles.endpoint,
@@ -1234,6 +1419,41 @@
 //     })
 // }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// matching_rules
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useGetMatchingRules() {
+//     return useServerQuery<Array<Models_MatchingRule>>({
+//         endpoint: API_ENDPOINTS.MATCHING_RULES.GetMatchingRules.endpoint,
+//         method: API_ENDPOINTS.MATCHING_RULES.GetMatchingRules.methods[0],
+//         queryKey: [API_ENDPOINTS.MATCHING_RULES.GetMatchingRules.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useSaveMatchingRule() {
+//     return useServerMutation<Models_MatchingRule, SaveMatchingRule_Variables>({
+//         endpoint: API_ENDPOINTS.MATCHING_RULES.SaveMatchingRule.endpoint,
+//         method: API_ENDPOINTS.MATCHING_RULES.SaveMatchingRule.methods[0],
+//         mutationKey: [API_ENDPOINTS.MATCHING_RULES.SaveMatchingRule.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDeleteMatchingRule() {
+//     return useServerMutation<boolean, DeleteMatchingRule_Variables>({
+//         endpoint: API_ENDPOINTS.MATCHING_RULES.DeleteMatchingRule.endpoint,
+//         method: API_ENDPOINTS.MATCHING_RULES.DeleteMatchingRule.methods[0],
+//         mutationKey: [API_ENDPOINTS.MATCHING_RULES.DeleteMatchingRule.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // mediaplayer
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1295,6 +1515,17 @@
 //     })
 // }
 
+// export function useRequestMediastreamTrickplay() {
+//     return useServerMutation<Trickplay_Info, RequestMediastreamTrickplay_Variables>({
+//         endpoint: API_ENDPOINTS.MEDIASTREAM.RequestMediastreamTrickplay.endpoint,
+//         method: API_ENDPOINTS.MEDIASTREAM.RequestMediastreamTrickplay.methods[0],
+//         mutationKey: [API_ENDPOINTS.MEDIASTREAM.RequestMediastreamTrickplay.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 // export function useMediastreamShutdownTranscodeStream() {
 //     return useServerMutation<boolean>({
 //         endpoint: API_ENDPOINTS.MEDIASTREAM.MediastreamShutdownTranscodeStream.endpoint,
@@ -1354,6 +1585,61 @@
 //     })
 // }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// notifications
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useGetNotificationEvents() {
+//     return useServerQuery<Array<Notifier_EventInfo>>({
+//         endpoint: API_ENDPOINTS.NOTIFICATIONS.GetNotificationEvents.endpoint,
+//         method: API_ENDPOINTS.NOTIFICATIONS.GetNotificationEvents.methods[0],
+//         queryKey: [API_ENDPOINTS.NOTIFICATIONS.GetNotificationEvents.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useGetNotificationChannels() {
+//     return useServerQuery<Array<Models_NotificationChannel>>({
+//         endpoint: API_ENDPOINTS.NOTIFICATIONS.GetNotificationChannels.endpoint,
+//         method: API_ENDPOINTS.NOTIFICATIONS.GetNotificationChannels.methods[0],
+//         queryKey: [API_ENDPOINTS.NOTIFICATIONS.GetNotificationChannels.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useSaveNotificationChannel() {
+//     return useServerMutation<Models_NotificationChannel, SaveNotificationChannel_Variables>({
+//         endpoint: API_ENDPOINTS.NOTIFICATIONS.SaveNotificationChannel.endpoint,
+//         method: API_ENDPOINTS.NOTIFICATIONS.SaveNotificationChannel.methods[0],
+//         mutationKey: [API_ENDPOINTS.NOTIFICATIONS.SaveNotificationChannel.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDeleteNotificationChannel() {
+//     return useServerMutation<boolean, DeleteNotificationChannel_Variables>({
+//         endpoint: API_ENDPOINTS.NOTIFICATIONS.DeleteNotificationChannel.endpoint,
+//         method: API_ENDPOINTS.NOTIFICATIONS.DeleteNotificationChannel.methods[0],
+//         mutationKey: [API_ENDPOINTS.NOTIFICATIONS.DeleteNotificationChannel.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useTestNotificationChannel() {
+//     return useServerMutation<boolean, TestNotificationChannel_Variables>({
+//         endpoint: API_ENDPOINTS.NOTIFICATIONS.TestNotificationChannel.endpoint,
+//         method: API_ENDPOINTS.NOTIFICATIONS.TestNotificationChannel.methods[0],
+//         mutationKey: [API_ENDPOINTS.NOTIFICATIONS.TestNotificationChannel.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // onlinestream
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1435,6 +1721,48 @@
 //     })
 // }
 
+// export function useOnlinestreamDownloadEpisodes() {
+//     return useServerMutation<boolean, OnlinestreamDownloadEpisodes_Variables>({
+//         endpoint: API_ENDPOINTS.ONLINESTREAM.OnlinestreamDownloadEpisodes.endpoint,
+//         method: API_ENDPOINTS.ONLINESTREAM.OnlinestreamDownloadEpisodes.methods[0],
+//         mutationKey: [API_ENDPOINTS.ONLINESTREAM.OnlinestreamDownloadEpisodes.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useGetOnlinestreamDownloadQueue() {
+//     return useServerQuery<Array<Onlinestream_DownloadQueueItem>>({
+//         endpoint: API_ENDPOINTS.ONLINESTREAM.GetOnlinestreamDownloadQueue.endpoint,
+//         method: API_ENDPOINTS.ONLINESTREAM.GetOnlinestreamDownloadQueue.methods[0],
+//         queryKey: [API_ENDPOINTS.ONLINESTREAM.GetOnlinestreamDownloadQueue.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useCancelOnlinestreamDownload() {
+//     return useServerMutation<boolean, CancelOnlinestreamDownload_Variables>({
+//         endpoint: API_ENDPOINTS.ONLINESTREAM.CancelOnlinestreamDownload.endpoint,
+//         method: API_ENDPOINTS.ONLINESTREAM.CancelOnlinestreamDownload.methods[0],
+//         mutationKey: [API_ENDPOINTS.ONLINESTREAM.CancelOnlinestreamDownload.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useClearOnlinestreamDownloadQueue() {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.ONLINESTREAM.ClearOnlinestreamDownloadQueue.endpoint,
+//         method: API_ENDPOINTS.ONLINESTREAM.ClearOnlinestreamDownloadQueue.methods[0],
+//         mutationKey: [API_ENDPOINTS.ONLINESTREAM.ClearOnlinestreamDownloadQueue.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // playback_manager
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1676,6 +2004,52 @@
 //     })
 // }
 
+// export function useDryRunScanLocalFiles() {
+//     return useServerMutation<Summary_ScanSummary, DryRunScanLocalFiles_Variables>({
+//         endpoint: API_ENDPOINTS.SCAN.DryRunScanLocalFiles.endpoint,
+//         method: API_ENDPOINTS.SCAN.DryRunScanLocalFiles.methods[0],
+//         mutationKey: [API_ENDPOINTS.SCAN.DryRunScanLocalFiles.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useCancelScan() {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.SCAN.CancelScan.endpoint,
+//         method: API_ENDPOINTS.SCAN.CancelScan.methods[0],
+//         mutationKey: [API_ENDPOINTS.SCAN.CancelScan.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// scan_logs
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useGetScanLogs() {
+//     return useServerQuery<Array<Scanner_ScanLogInfo>>({
+//         endpoint: API_ENDPOINTS.SCAN_LOGS.GetScanLogs.endpoint,
+//         method: API_ENDPOINTS.SCAN_LOGS.GetScanLogs.methods[0],
+//         queryKey: [API_ENDPOINTS.SCAN_LOGS.GetScanLogs.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useGetScanLogEntries() {
+//     return useServerMutation<Array<Scanner_ScanLogEntry>, GetScanLogEntries_Variables>({
+//         endpoint: API_ENDPOINTS.SCAN_LOGS.GetScanLogEntries.endpoint,
+//         method: API_ENDPOINTS.SCAN_LOGS.GetScanLogEntries.methods[0],
+//         mutationKey: [API_ENDPOINTS.SCAN_LOGS.GetScanLogEntries.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // scan_summary
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1689,6 +2063,98 @@
 //     })
 // }
 
+// export function useApplyScanDiff() {
+//     return useServerMutation<Array<Anime_LocalFile>, ApplyScanDiff_Variables>({
+//         endpoint: API_ENDPOINTS.SCAN_SUMMARY.ApplyScanDiff.endpoint,
+//         method: API_ENDPOINTS.SCAN_SUMMARY.ApplyScanDiff.methods[0],
+//         mutationKey: [API_ENDPOINTS.SCAN_SUMMARY.ApplyScanDiff.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDiscardScanDiff() {
+//     return useServerMutation<boolean, DiscardScanDiff_Variables>({
+//         endpoint: API_ENDPOINTS.SCAN_SUMMARY.DiscardScanDiff.endpoint,
+//         method: API_ENDPOINTS.SCAN_SUMMARY.DiscardScanDiff.methods[0],
+//         mutationKey: [API_ENDPOINTS.SCAN_SUMMARY.DiscardScanDiff.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// scheduler
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useGetScheduledJobs() {
+//     return useServerQuery<Array<Scheduler_JobInfo>>({
+//         endpoint: API_ENDPOINTS.SCHEDULER.GetScheduledJobs.endpoint,
+//         method: API_ENDPOINTS.SCHEDULER.GetScheduledJobs.methods[0],
+//         queryKey: [API_ENDPOINTS.SCHEDULER.GetScheduledJobs.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useUpdateScheduledJob() {
+//     return useServerMutation<Scheduler_JobInfo, UpdateScheduledJob_Variables>({
+//         endpoint: API_ENDPOINTS.SCHEDULER.UpdateScheduledJob.endpoint,
+//         method: API_ENDPOINTS.SCHEDULER.UpdateScheduledJob.methods[0],
+//         mutationKey: [API_ENDPOINTS.SCHEDULER.UpdateScheduledJob.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useRunScheduledJob() {
+//     return useServerMutation<boolean, RunScheduledJob_Variables>({
+//         endpoint: API_ENDPOINTS.SCHEDULER.RunScheduledJob.endpoint,
+//         method: API_ENDPOINTS.SCHEDULER.RunScheduledJob.methods[0],
+//         mutationKey: [API_ENDPOINTS.SCHEDULER.RunScheduledJob.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useGetScheduledJobHistory(name: string) {
+//     return useServerQuery<Array<Models_ScheduledJobRun>>({
+//         endpoint: API_ENDPOINTS.SCHEDULER.GetScheduledJobHistory.endpoint.replace("{name}", String(name)),
+//         method: API_ENDPOINTS.SCHEDULER.GetScheduledJobHistory.methods[0],
+//         queryKey: [API_ENDPOINTS.SCHEDULER.GetScheduledJobHistory.key],
+//         enabled: true,
+//     })
+// }
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// seadex
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useGetSeaDexLibraryAnalysis() {
+//     return useServerMutation<SeaDex_LibraryAnalysis, GetSeaDexLibraryAnalysis_Variables>({
+//         endpoint: API_ENDPOINTS.SEADEX.GetSeaDexLibraryAnalysis.endpoint,
+//         method: API_ENDPOINTS.SEADEX.GetSeaDexLibraryAnalysis.methods[0],
+//         mutationKey: [API_ENDPOINTS.SEADEX.GetSeaDexLibraryAnalysis.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useSeaDexEnqueueReleases() {
+//     return useServerMutation<boolean, SeaDexEnqueueReleases_Variables>({
+//         endpoint: API_ENDPOINTS.SEADEX.SeaDexEnqueueReleases.endpoint,
+//         method: API_ENDPOINTS.SEADEX.SeaDexEnqueueReleases.methods[0],
+//         mutationKey: [API_ENDPOINTS.SEADEX.SeaDexEnqueueReleases.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // settings
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1735,6 +2201,32 @@
 //     })
 // }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// skip_segments
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useGetSkipSegments() {
+//     return useServerMutation<SkipSegments_Markers, GetSkipSegments_Variables>({
+//         endpoint: API_ENDPOINTS.SKIP_SEGMENTS.GetSkipSegments.endpoint,
+//         method: API_ENDPOINTS.SKIP_SEGMENTS.GetSkipSegments.methods[0],
+//         mutationKey: [API_ENDPOINTS.SKIP_SEGMENTS.GetSkipSegments.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDeleteSkipSegments() {
+//     return useServerMutation<boolean, DeleteSkipSegments_Variables>({
+//         endpoint: API_ENDPOINTS.SKIP_SEGMENTS.DeleteSkipSegments.endpoint,
+//         method: API_ENDPOINTS.SKIP_SEGMENTS.DeleteSkipSegments.methods[0],
+//         mutationKey: [API_ENDPOINTS.SKIP_SEGMENTS.DeleteSkipSegments.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // status
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -2054,3 +2546,87 @@
 //     })
 // }
 
+// export function useGetTorrentstreamSessions() {
+//     return useServerQuery<Array<Torrentstream_SessionInfo>>({
+//         endpoint: API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamSessions.endpoint,
+//         method: API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamSessions.methods[0],
+//         queryKey: [API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamSessions.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useTorrentstreamKeepAndImport() {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.TORRENTSTREAM.TorrentstreamKeepAndImport.endpoint,
+//         method: API_ENDPOINTS.TORRENTSTREAM.TorrentstreamKeepAndImport.methods[0],
+//         mutationKey: [API_ENDPOINTS.TORRENTSTREAM.TorrentstreamKeepAndImport.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useGetTorrentstreamImportJobs() {
+//     return useServerQuery<Array<Torrentstream_ImportJobInfo>>({
+//         endpoint: API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamImportJobs.endpoint,
+//         method: API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamImportJobs.methods[0],
+//         queryKey: [API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamImportJobs.key],
+//         enabled: true,
+//     })
+// }
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// track_preference
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useGetTrackPreferenceSettings() {
+//     return useServerQuery<Models_TrackPreferenceSettings>({
+//         endpoint: API_ENDPOINTS.TRACK_PREFERENCE.GetTrackPreferenceSettings.endpoint,
+//         method: API_ENDPOINTS.TRACK_PREFERENCE.GetTrackPreferenceSettings.methods[0],
+//         queryKey: [API_ENDPOINTS.TRACK_PREFERENCE.GetTrackPreferenceSettings.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useSaveTrackPreferenceSettings() {
+//     return useServerMutation<Models_TrackPreferenceSettings, SaveTrackPreferenceSettings_Variables>({
+//         endpoint: API_ENDPOINTS.TRACK_PREFERENCE.SaveTrackPreferenceSettings.endpoint,
+//         method: API_ENDPOINTS.TRACK_PREFERENCE.SaveTrackPreferenceSettings.methods[0],
+//         mutationKey: [API_ENDPOINTS.TRACK_PREFERENCE.SaveTrackPreferenceSettings.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useGetMediaTrackPreference(id: number) {
+//     return useServerQuery<Models_MediaTrackPreference>({
+//         endpoint: API_ENDPOINTS.TRACK_PREFERENCE.GetMediaTrackPreference.endpoint.replace("{id}", String(id)),
+//         method: API_ENDPOINTS.TRACK_PREFERENCE.GetMediaTrackPreference.methods[0],
+//         queryKey: [API_ENDPOINTS.TRACK_PREFERENCE.GetMediaTrackPreference.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useSaveMediaTrackPreference() {
+//     return useServerMutation<Models_MediaTrackPreference, SaveMediaTrackPreference_Variables>({
+//         endpoint: API_ENDPOINTS.TRACK_PREFERENCE.SaveMediaTrackPreference.endpoint,
+//         method: API_ENDPOINTS.TRACK_PREFERENCE.SaveMediaTrackPreference.methods[0],
+//         mutationKey: [API_ENDPOINTS.TRACK_PREFERENCE.SaveMediaTrackPreference.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDeleteMediaTrackPreference(id: number) {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.TRACK_PREFERENCE.DeleteMediaTrackPreference.endpoint.replace("{id}", String(id)),
+//         method: API_ENDPOINTS.TRACK_PREFERENCE.DeleteMediaTrackPreference.methods[0],
+//         mutationKey: [API_ENDPOINTS.TRACK_PREFERENCE.DeleteMediaTrackPreference.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
@@ -1340,6 +1340,10 @@ export type Anime_AutoDownloaderRule = {
     episodeNumbers?: Array<number>
     destination: string
     additionalTerms?: Array<string>
+    /**
+     * Library directory the destination is resolved against, optional
+     */
+    destinationRoot?: string
 }
 
 /**
@@ -1368,6 +1372,10 @@ export type Anime_Entry = {
     libraryData?: Anime_EntryLibraryData
     downloadInfo?: Anime_EntryDownloadInfo
     episodes?: Array<Anime_Episode>
+    /**
+     * Specials and NC episodes, grouped by AniDB episode type
+     */
+    episodeSections?: Array<Anime_EpisodeSection>
     nextEpisode?: Anime_Episode
     localFiles?: Array<Anime_LocalFile>
     anidbId: number
@@ -1489,6 +1497,24 @@ export type Anime_EpisodeMetadata = {
     isFiller?: boolean
 }
 
+/**
+ * - Filepath: internal/library/anime/episode_section.go
+ * - Filename: episode_section.go
+ * - Package: anime
+ */
+export type Anime_EpisodeSection = {
+    type: Anime_EpisodeSectionType
+    title: string
+    episodes?: Array<Anime_Episode>
+}
+
+/**
+ * - Filepath: internal/library/anime/episode_section.go
+ * - Filename: episode_section.go
+ * - Package: anime
+ */
+export type Anime_EpisodeSectionType = "S" | "C" | "T" | "O"
+
 /**
  * - Filepath: internal/library/anime/collection.go
  * - Filename: collection.go
@@ -1524,6 +1550,10 @@ export type Anime_LibraryCollectionEntry = {
      * AniList list data
      */
     listData?: Anime_EntryListData
+    /**
+     * Whether episodes are available from the debrid service
+     */
+    inCloud: boolean
 }
 
 /**
@@ -1564,10 +1594,95 @@ export type Anime_LocalFile = {
     metadata?: Anime_LocalFileMetadata
     locked: boolean
     /**
-     * Unused for now
+     * Set by the user, ignored files are not matched
      */
     ignored: boolean
     mediaId: number
+    /**
+     * Set when file hashing is enabled
+     */
+    hashes?: Anime_LocalFileHashes
+    /**
+     * Set when media info probing is enabled
+     */
+    mediaInfo?: Anime_LocalFileMediaInfo
+    /**
+     * Set when the library root of the file is offline, the file is kept until it is back
+     */
+    offline?: boolean
+    /**
+     * Set when the file is excluded by a .seaignore file, cleared once no rule matches it
+     */
+    ignoredBySeaignore?: boolean
+}
+
+/**
+ * - Filepath: internal/library/anime/localfile_filter.go
+ * - Filename: localfile_filter.go
+ * - Package: anime
+ * @description
+ *  LocalFileFilter selects local files by their technical metadata.
+ *  Zero values are ignored, files that were not probed only match an empty filter.
+ */
+export type Anime_LocalFileFilter = {
+    mediaId?: number
+    /**
+     * e.g. "hevc", "h264", "av1"
+     */
+    videoCodec?: string
+    minHeight?: number
+    maxHeight?: number
+    minDuration?: number
+    maxDuration?: number
+    audioLanguage?: string
+    subtitleLanguage?: string
+    missingAudioLanguage?: string
+    missingSubtitleLanguage?: string
+}
+
+/**
+ * - Filepath: internal/library/anime/localfile.go
+ * - Filename: localfile.go
+ * - Package: anime
+ */
+export type Anime_LocalFileHashes = {
+    crc32: string
+    ed2k: string
+    size: number
+    checksumMismatch: boolean
+}
+
+/**
+ * - Filepath: internal/library/anime/localfile.go
+ * - Filename: localfile.go
+ * - Package: anime
+ */
+export type Anime_LocalFileMediaInfo = {
+    /**
+     * In seconds
+     */
+    duration: number
+    container: string
+    videoCodec: string
+    width: number
+    height: number
+    audioTracks?: Array<Anime_LocalFileMediaTrack>
+    subtitleTracks?: Array<Anime_LocalFileMediaTrack>
+}
+
+/**
+ * - Filepath: internal/library/anime/localfile.go
+ * - Filename: localfile.go
+ * - Package: anime
+ */
+export type Anime_LocalFileMediaTrack = {
+    codec: string
+    /**
+     * BCP 47 tag, e.g. "en", empty if undefined
+     */
+    language?: string
+    title?: string
+    forced?: boolean
 }
 
 /**
@@ -1598,6 +1713,10 @@ export type Anime_LocalFileParsedData = {
     episodeRange?: Array<string>
     episodeTitle?: string
     year?: string
+    /**
+     * CRC32 written in the file name
+     */
+    checksum?: string
 }
 
 /**
@@ -1921,6 +2040,21 @@ export type DebridClient_CancelStreamOptions = {
     removeTorrent: boolean
 }
 
+/**
+ * - Filepath: internal/debrid/client/library.go
+ * - Filename: library.go
+ * - Package: debrid_client
+ */
+export type DebridClient_CloudFile = {
+    torrentItemId: string
+    torrentName: string
+    fileId: string
+    path: string
+    size: number
+    mediaId: number
+    metadata?: Anime_LocalFileMetadata
+}
+
 /**
  * - Filepath: internal/debrid/client/previews.go
  * - Filename: previews.go
@@ -1962,6 +2096,127 @@ export type DebridClient_StreamState = {
  */
 export type DebridClient_StreamStatus = "downloading" | "ready" | "failed" | "started"
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Downloadmanager
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/downloadmanager/job.go
+ * - Filename: job.go
+ * - Package: downloadmanager
+ */
+export type DownloadManager_Job = {
+    id: string
+    sourceId: string
+    source: DownloadManager_JobSource
+    name: string
+    mediaId: number
+    episode: string
+    totalBytes: number
+    downloadedBytes: number
+    /**
+     * 0 to 1
+     */
+    progress: number
+    /**
+     * Bytes per second
+     */
+    speed: number
+    /**
+     * Seconds, -1 if unknown
+     */
+    eta: number
+    state: DownloadManager_JobState
+    priority: DownloadManager_Priority
+    pausedBy: DownloadManager_PauseReason
+    error?: string
+}
+
+/**
+ * - Filepath: internal/downloadmanager/job.go
+ * - Filename: job.go
+ * - Package: downloadmanager
+ */
+export type DownloadManager_JobSource = "torrent-client" | "debrid" | "torrentstream" | "manga" | "onlinestream"
+
+/**
+ * - Filepath: internal/downloadmanager/job.go
+ * - Filename: job.go
+ * - Package: downloadmanager
+ */
+export type DownloadManager_JobState = "queued" | "downloading" | "paused" | "completed" | "failed"
+
+/**
+ * - Filepath: internal/downloadmanager/job.go
+ * - Filename: job.go
+ * - Package: downloadmanager
+ */
+export type DownloadManager_PauseReason = "user" | "global" | "schedule" | "priority"
+
+/**
+ * - Filepath: internal/downloadmanager/job.go
+ * - Filename: job.go
+ * - Package: downloadmanager
+ */
+export type DownloadManager_Priority = -1 | 0 | 1
+
+/**
+ * - Filepath: internal/downloadmanager/manager.go
+ * - Filename: manager.go
+ * - Package: downloadmanager
+ */
+export type DownloadManager_Snapshot = {
+    jobs?: Array<DownloadManager_Job>
+    paused: boolean
+    scheduleEnabled: boolean
+    inScheduleWindow: boolean
+    rateLimit: number
+    totalSpeed: number
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Eventstream
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/eventstream/events.go
+ * - Filename: events.go
+ * - Package: eventstream
+ */
+export type EventStream_Event = {
+    id: string
+    version: number
+    type: EventStream_Type
+    timestamp?: string
+    data: any
+}
+
+/**
+ * - Filepath: internal/eventstream/events.go
+ * - Filename: events.go
+ * - Package: eventstream
+ */
+export type EventStream_Type = "playback.started" |
+    "playback.stopped" |
+    "playback.completed" |
+    "progress.updated" |
+    "library.scan-completed" |
+    "download.completed" |
+    "download.failed" |
+    "download.cancelled" |
+    "list.entry-changed"
+
+/**
+ * - Filepath: internal/eventstream/events.go
+ * - Filename: events.go
+ * - Package: eventstream
+ */
+export type EventStream_TypeInfo = {
+    type: EventStream_Type
+    description: string
+    payload: string
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Extension
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -2219,6 +2474,22 @@ export type ExtensionRepo_UpdateData = {
     version: string
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Filesystem
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/library/filesystem/roots.go
+ * - Filename: roots.go
+ * - Package: filesystem
+ * @description
+ *  LibraryRootStatus is a library root with its availability.
+ */
+export type Filesystem_LibraryRootStatus = {
+    root?: Models_LibraryRoot
+    offline: boolean
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Handlers
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -2541,6 +2812,7 @@ export type Mediastream_MediaContainer = {
      */
     streamUrl: string
     mediaInfo?: MediaInfo
+    trackSelection?: TrackPreference_Selection
 }
 
 /**
@@ -2631,6 +2903,7 @@ export type Models_DebridSettings = {
     includeDebridStreamInLibrary: boolean
     streamAutoSelect: boolean
     streamPreferredResolution: string
+    includeCloudTorrentsInLibrary: boolean
     id: number
     createdAt?: string
     updatedAt?: string
@@ -2650,6 +2923,88 @@ export type Models_DiscordSettings = {
     richPresenceShowAniListProfileButton: boolean
 }
 
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ */
+export type Models_DownloadManagerSettings = {
+    paused: boolean
+    scheduleEnabled: boolean
+    scheduleWindows: Models_DownloadScheduleWindows
+    id: number
+    createdAt?: string
+    updatedAt?: string
+}
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ * @description
+ *  DownloadScheduleWindow is a time range during which downloads are allowed.
+ *  A window ending before it starts (e.g. 23:00 to 07:00) ends on the next day.
+ */
+export type Models_DownloadScheduleWindow = {
+    /**
+     * "HH:MM"
+     */
+    start: string
+    /**
+     * "HH:MM"
+     */
+    end: string
+    /**
+     * Days on which the window starts, 0 is Sunday. Empty means every day.
+     */
+    days?: Array<number>
+    rateLimit: number
+}
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ */
+export type Models_DownloadScheduleWindows = Array<Models_DownloadScheduleWindow>
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ * @description
+ *  EventWebhook is an HTTP endpoint that receives the events of the public event stream.
+ */
+export type Models_EventWebhook = {
+    url: string
+    secret: string
+    enabled: boolean
+    events: Models_EventWebhookEvents
+    lastDeliveryAt?: string
+    /**
+     * HTTP status code, 0 if the request failed
+     */
+    lastDeliveryStatus: number
+    lastDeliveryError: string
+    id: number
+    createdAt?: string
+    updatedAt?: string
+}
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ */
+export type Models_EventWebhookEvents = Array<string>
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ */
+export type Models_LanguageList = Array<string>
+
 /**
  * - Filepath: internal/database/models/models.go
  * - Filename: models.go
@@ -2657,6 +3012,28 @@ export type Models_DiscordSettings = {
  */
 export type Models_LibraryPaths = Array<string>
 
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ * @description
+ *  LibraryRoot holds the settings of a library directory.
+ */
+export type Models_LibraryRoot = {
+    path: string
+    enabled: boolean
+    readOnly: boolean
+    priority: number
+    defaultMediaFormat: string
+}
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ */
+export type Models_LibraryRoots = Array<Models_LibraryRoot>
+
 /**
  * - Filepath: internal/database/models/models.go
  * - Filename: models.go
@@ -2682,6 +3059,12 @@ export type Models_LibrarySettings = {
     autoSyncOfflineLocalData: boolean
     scannerMatchingThreshold: number
     scannerMatchingAlgorithm: string
+    scannerLearnMatchingRules: boolean
+    scannerHashFiles: boolean
+    scannerHashLookupPath: string
+    scannerProbeMediaInfo: boolean
+    scannerLogRetention: number
+    libraryRoots: Models_LibraryRoots
 }
 
 /**
@@ -2703,6 +3086,26 @@ export type Models_MangaSettings = {
     defaultMangaProvider: string
 }
 
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ * @description
+ *  MatchingRule is a user-defined rule evaluated by the scanner before fuzzy matching.
+ */
+export type Models_MatchingRule = {
+    type: string
+    pattern: string
+    releaseGroup: string
+    mediaId: number
+    episodeOffset: number
+    enabled: boolean
+    learned: boolean
+    id: number
+    createdAt?: string
+    updatedAt?: string
+}
+
 /**
  * - Filepath: internal/database/models/models.go
  * - Filename: models.go
@@ -2724,6 +3127,24 @@ export type Models_MediaPlayerSettings = {
     mpvPath: string
 }
 
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ * @description
+ *  MediaTrackPreference overrides the track preferences for a single media.
+ *  Empty fields fall back to the global TrackPreferenceSettings.
+ */
+export type Models_MediaTrackPreference = {
+    mediaId: number
+    audioLanguages: Models_LanguageList
+    subtitleLanguages: Models_LanguageList
+    signsAndSongs: string
+    id: number
+    createdAt?: string
+    updatedAt?: string
+}
+
 /**
  * - Filepath: internal/database/models/models.go
  * - Filename: models.go
@@ -2741,6 +3162,18 @@ export type Models_MediastreamSettings = {
     ffmpegPath: string
     ffprobePath: string
     transcodeHwAccelCustomSettings: string
+    /**
+     * Generate seek preview thumbnails when a file is played
+     */
+    trickplayEnabled: boolean
+    /**
+     * Convert HDR videos to SDR when transcoding
+     */
+    transcodeTonemap: boolean
+    /**
+     * Offer a rendition with the subtitles burned in when transcoding
+     */
+    transcodeBurnIn: boolean
     id: number
     createdAt?: string
     updatedAt?: string
@@ -2750,14 +3183,127 @@ export type Models_MediastreamSettings = {
  * - Filepath: internal/database/models/models.go
  * - Filename: models.go
  * - Package: models
+ * @description
+ *  NotificationChannel is an external service that receives notifications, e.g. a Discord webhook.
  */
-export type Models_NotificationSettings = {
-    disableNotifications: boolean
-    disableAutoDownloaderNotifications: boolean
-    disableAutoScannerNotifications: boolean
-}
-
-/**
+export type Models_NotificationChannel = {
+    name: string
+    /**
+     * "webhook", "ntfy", "gotify", "discord" or "smtp"
+     */
+    type: string
+    enabled: boolean
+    events: Models_NotificationEvents
+    config: Models_NotificationChannelConfig
+    templates: Models_NotificationTemplates
+    id: number
+    createdAt?: string
+    updatedAt?: string
+}
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ * @description
+ *  NotificationChannelConfig holds the options of all channel types, each type only uses some of them.
+ */
+export type Models_NotificationChannelConfig = {
+    url: string
+    /**
+     * webhook
+     */
+    headers?: Record<string, string>
+    /**
+     * ntfy access token, gotify application token
+     */
+    token?: string
+    /**
+     * ntfy
+     */
+    topic?: string
+    priority?: number
+    /**
+     * smtp
+     */
+    host?: string
+    /**
+     * smtp, 465 uses implicit TLS
+     */
+    port?: number
+    username?: string
+    password?: string
+    from?: string
+    to?: Array<string>
+}
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ */
+export type Models_NotificationEvents = Array<string>
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ */
+export type Models_NotificationSettings = {
+    disableNotifications: boolean
+    disableAutoDownloaderNotifications: boolean
+    disableAutoScannerNotifications: boolean
+}
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ * @description
+ *  NotificationTemplate overrides the default title and message of an event.
+ *  Both are Go templates executed with the data of the event.
+ */
+export type Models_NotificationTemplate = {
+    title: string
+    message: string
+}
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ */
+export type Models_NotificationTemplates = Record<string, Models_NotificationTemplate>
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ * @description
+ *  ScheduledJobRun is an entry in the run history of a scheduled job.
+ */
+export type Models_ScheduledJobRun = {
+    jobName: string
+    /**
+     * "schedule" or "manual"
+     */
+    trigger: string
+    /**
+     * "success", "failed" or "skipped"
+     */
+    status: string
+    startedAt?: string
+    /**
+     * Milliseconds
+     */
+    duration: number
+    error: string
+    id: number
+    createdAt?: string
+    updatedAt?: string
+}
+
+/**
  * - Filepath: internal/database/models/models.go
  * - Filename: models.go
  * - Package: models
@@ -2871,6 +3417,9 @@ export type Models_TorrentstreamSettings = {
     preferredResolution: string
     disableIPV6: boolean
     downloadDir: string
+    /**
+     * Keep and import every stream into the library when it stops
+     */
     addToLibrary: boolean
     torrentClientHost: string
     torrentClientPort: number
@@ -2879,15 +3428,126 @@ export type Models_TorrentstreamSettings = {
     includeInLibrary: boolean
     streamUrlAddress: string
     slowSeeding: boolean
+    /**
+     * KiB/s, 0 = unlimited
+     */
+    sessionDownloadRateLimit: number
+    /**
+     * KiB/s, 0 = unlimited
+     */
+    globalDownloadRateLimit: number
+    importDir: string
+    /**
+     * 0 = no ratio limit
+     */
+    seedRatioLimit: number
+    /**
+     * Minutes, 0 = no time limit
+     */
+    seedTimeLimit: number
+    id: number
+    createdAt?: string
+    updatedAt?: string
+}
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ */
+export type Models_TrackPreferenceSettings = {
+    /**
+     * ISO 639 codes ordered by priority
+     */
+    audioLanguages: Models_LanguageList
+    /**
+     * ISO 639 codes ordered by priority
+     */
+    subtitleLanguages: Models_LanguageList
+    /**
+     * "auto", "prefer" or "never"
+     */
+    signsAndSongs: string
     id: number
     createdAt?: string
     updatedAt?: string
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Notifier
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/notifier/events.go
+ * - Filename: events.go
+ * - Package: notifier
+ */
+export type Notifier_Event = "episode-aired" |
+    "auto-download-started" |
+    "auto-download-finished" |
+    "scan-finished" |
+    "manga-chapter-available" |
+    "update-available" |
+    "error"
+
+/**
+ * - Filepath: internal/notifier/events.go
+ * - Filename: events.go
+ * - Package: notifier
+ * @description
+ *  EventInfo describes an event and the data its templates can use.
+ */
+export type Notifier_EventInfo = {
+    event: Notifier_Event
+    description: string
+    variables?: Array<string>
+    defaultTitle: string
+    defaultTemplate: string
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Onlinestream
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+/**
+ * - Filepath: internal/onlinestream/download.go
+ * - Filename: download.go
+ * - Package: onlinestream
+ */
+export type Onlinestream_DownloadQueueItem = {
+    id: string
+    mediaId: number
+    episodeNumber: number
+    provider: string
+    dubbed: boolean
+    /**
+     * Preferred quality
+     */
+    quality?: string
+    /**
+     * Preferred server
+     */
+    server?: string
+    destination?: string
+    status: Onlinestream_DownloadStatus
+    percentage: number
+    downloadedBytes: number
+    error?: string
+}
+
+/**
+ * - Filepath: internal/onlinestream/download.go
+ * - Filename: download.go
+ * - Package: onlinestream
+ */
+export type Onlinestream_DownloadStatus = "queued" |
+    "paused" |
+    "downloading" |
+    "remuxing" |
+    "completed" |
+    "errored" |
+    "cancelled"
+
 /**
  * - Filepath: internal/onlinestream/repository.go
  * - Filename: repository.go
@@ -2939,6 +3599,10 @@ export type Onlinestream_MappingResponse = {
 export type Onlinestream_Subtitle = {
     url: string
     language: string
+    /**
+     * Set on the subtitle matching the track preferences
+     */
+    default: boolean
 }
 
 /**
@@ -2951,6 +3615,18 @@ export type Onlinestream_VideoSource = {
     headers?: Record<string, string>
     url: string
     quality: string
+    /**
+     * Whether the source responded to the health check
+     */
+    available: boolean
+    /**
+     * Milliseconds, 0 if unavailable
+     */
+    latency: number
+    /**
+     * Set on the best available source
+     */
+    recommended: boolean
 }
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -3046,10 +3722,250 @@ export type Report_UnlockedLocalFile = {
     mediaId: number
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Scanner
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/library/scanner/scan_logs.go
+ * - Filename: scan_logs.go
+ * - Package: scanner
+ * @description
+ *  ScanLogEntry is an entry of a scan log.
+ */
+export type Scanner_ScanLogEntry = {
+    time?: string
+    level: string
+    stage?: string
+    path?: string
+    filename?: string
+    mediaId?: number
+    decision?: string
+    message: string
+    fields?: Record<string, any>
+}
+
+/**
+ * - Filepath: internal/library/scanner/scan_logs.go
+ * - Filename: scan_logs.go
+ * - Package: scanner
+ * @description
+ *  ScanLogFilter selects the entries of a scan log.
+ *  Zero values are ignored.
+ */
+export type Scanner_ScanLogFilter = {
+    path?: string
+    mediaId?: number
+    stage?: string
+    decision?: string
+}
+
+/**
+ * - Filepath: internal/library/scanner/scan_logs.go
+ * - Filename: scan_logs.go
+ * - Package: scanner
+ * @description
+ *  ScanLogInfo describes a scan log file.
+ */
+export type Scanner_ScanLogInfo = {
+    name: string
+    date?: string
+    size: number
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Scheduler
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/scheduler/scheduler.go
+ * - Filename: scheduler.go
+ * - Package: scheduler
+ */
+export type Scheduler_JobInfo = {
+    name: string
+    description: string
+    schedule: string
+    defaultSchedule: string
+    enabled: boolean
+    running: boolean
+    nextRun?: string
+    lastRun?: Models_ScheduledJobRun
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Seadex
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/torrents/seadex/library.go
+ * - Filename: library.go
+ * - Package: seadex
+ */
+export type SeaDex_LibraryAnalysis = {
+    entries?: Array<SeaDex_LibraryAnalysisEntry>
+    unlistedMediaIds?: Array<number>
+    totalUpgradeSize: number
+    analyzedAt: string
+}
+
+/**
+ * - Filepath: internal/torrents/seadex/library.go
+ * - Filename: library.go
+ * - Package: seadex
+ */
+export type SeaDex_LibraryAnalysisEntry = {
+    mediaId: number
+    media?: AL_BaseAnime
+    status: SeaDex_LibraryReleaseStatus
+    localReleaseGroups?: Array<string>
+    localEpisodeCount: number
+    totalEpisodeCount: number
+    localSize: number
+    matchedRelease?: SeaDex_Torrent
+    recommended?: SeaDex_Torrent
+    alternatives?: Array<SeaDex_Torrent>
+    upgradeSize: number
+    sizeDifference: number
+    incomplete: boolean
+    notes: string
+}
+
+/**
+ * - Filepath: internal/torrents/seadex/library.go
+ * - Filename: library.go
+ * - Package: seadex
+ */
+export type SeaDex_LibraryReleaseStatus = "best" | "alt" | "upgradable"
+
+/**
+ * - Filepath: internal/torrents/seadex/library.go
+ * - Filename: library.go
+ * - Package: seadex
+ */
+export type SeaDex_LibraryUpgrade = {
+    mediaId: number
+    torrent?: SeaDex_Torrent
+}
+
+/**
+ * - Filepath: internal/torrents/seadex/seadex.go
+ * - Filename: seadex.go
+ * - Package: seadex
+ */
+export type SeaDex_Torrent = {
+    name: string
+    date: string
+    size: number
+    link: string
+    infoHash: string
+    releaseGroup?: string
+    isBest: boolean
+    dualAudio: boolean
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Skipsegments
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/mediastream/skipsegments/skipsegments.go
+ * - Filename: skipsegments.go
+ * - Package: skipsegments
+ */
+export type SkipSegments_Markers = {
+    hash: string
+    path: string
+    source: SkipSegments_Source
+    segments?: Array<SkipSegments_Segment>
+    createdAt?: string
+}
+
+/**
+ * - Filepath: internal/mediastream/skipsegments/skipsegments.go
+ * - Filename: skipsegments.go
+ * - Package: skipsegments
+ */
+export type SkipSegments_Segment = {
+    type: SkipSegments_SegmentType
+    /**
+     * seconds
+     */
+    startTime: number
+    /**
+     * seconds
+     */
+    endTime: number
+}
+
+/**
+ * - Filepath: internal/mediastream/skipsegments/skipsegments.go
+ * - Filename: skipsegments.go
+ * - Package: skipsegments
+ */
+export type SkipSegments_SegmentType = "intro" | "outro"
+
+/**
+ * - Filepath: internal/mediastream/skipsegments/skipsegments.go
+ * - Filename: skipsegments.go
+ * - Package: skipsegments
+ */
+export type SkipSegments_Source = "chapters" | "fingerprint" | "none"
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Summary
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+/**
+ * - Filepath: internal/library/summary/scan_diff.go
+ * - Filename: scan_diff.go
+ * - Package: summary
+ */
+export type Summary_ScanDiff = {
+    status: Summary_ScanDiffStatus
+    dryRun: boolean
+    changes?: Array<Summary_ScanDiffChange>
+}
+
+/**
+ * - Filepath: internal/library/summary/scan_diff.go
+ * - Filename: scan_diff.go
+ * - Package: summary
+ */
+export type Summary_ScanDiffChange = {
+    id: string
+    type: Summary_ScanDiffChangeType
+    path: string
+    /**
+     * Not set for new files
+     */
+    previous?: Anime_LocalFile
+    /**
+     * Not set for removed files
+     */
+    current?: Anime_LocalFile
+    accepted: boolean
+}
+
+/**
+ * - Filepath: internal/library/summary/scan_diff.go
+ * - Filename: scan_diff.go
+ * - Package: summary
+ */
+export type Summary_ScanDiffChangeType = "matched" |
+    "rematched" |
+    "episode-changed" |
+    "unmatched" |
+    "removed" |
+    "updated"
+
+/**
+ * - Filepath: internal/library/summary/scan_diff.go
+ * - Filename: scan_diff.go
+ * - Package: summary
+ */
+export type Summary_ScanDiffStatus = "pending" | "applied" | "discarded"
+
 /**
  * - Filepath: internal/library/summary/scan_summary.go
  * - Filename: scan_summary.go
@@ -3059,6 +3975,11 @@ export type Summary_ScanSummary = {
     id: string
     groups?: Array<Summary_ScanSummaryGroup>
     unmatchedFiles?: Array<Summary_ScanSummaryFile>
+    /**
+     * Files excluded by .seaignore files
+     */
+    ignoredFiles?: Array<Summary_ScanSummaryFile>
+    diff?: Summary_ScanDiff
 }
 
 /**
@@ -3196,6 +4117,15 @@ export type TorrentClient_Torrent = {
     eta: string
     status: TorrentClient_TorrentStatus
     contentPath: string
+    sizeBytes: number
+    /**
+     * Bytes per second
+     */
+    downSpeedBytes: number
+    /**
+     * -1 if unknown
+     */
+    etaSeconds: number
 }
 
 /**
@@ -3243,6 +4173,32 @@ export type Torrentstream_FilePreview = {
     index: number
 }
 
+/**
+ * - Filepath: internal/torrentstream/import.go
+ * - Filename: import.go
+ * - Package: torrentstream
+ */
+export type Torrentstream_ImportJobInfo = {
+    infoHash: string
+    torrentName: string
+    mediaId: number
+    status: Torrentstream_ImportStatus
+    progressPercentage: number
+    totalBytes: number
+    downloadedBytes: number
+    paused: boolean
+    destination: string
+    seedRatio: number
+    error?: string
+}
+
+/**
+ * - Filepath: internal/torrentstream/import.go
+ * - Filename: import.go
+ * - Package: torrentstream
+ */
+export type Torrentstream_ImportStatus = "downloading" | "importing" | "seeding" | "completed" | "failed"
+
 /**
  * - Filepath: internal/torrentstream/stream.go
  * - Filename: stream.go
@@ -3250,6 +4206,21 @@ export type Torrentstream_FilePreview = {
  */
 export type Torrentstream_PlaybackType = "default" | "externalPlayerLink"
 
+/**
+ * - Filepath: internal/torrentstream/session.go
+ * - Filename: session.go
+ * - Package: torrentstream
+ */
+export type Torrentstream_SessionInfo = {
+    clientId: string
+    mediaId: number
+    episodeNumber: number
+    playbackType: Torrentstream_PlaybackType
+    torrentName: string
+    fileName: string
+    status: Torrentstream_TorrentStatus
+}
+
 /**
  * - Filepath: internal/torrentstream/events.go
  * - Filename: events.go
@@ -3287,6 +4258,52 @@ export type Torrentstream_TorrentStatus = {
     seeders: number
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Trackpreference
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/trackpreference/selection.go
+ * - Filename: selection.go
+ * - Package: trackpreference
+ */
+export type TrackPreference_Selection = {
+    /**
+     * Index of the audio track to select, -1 to keep the default track
+     */
+    audioIndex: number
+    /**
+     * Index of the subtitle track to select, -1 to keep the default track
+     */
+    subtitleIndex: number
+    /**
+     * Subtitles should be turned off
+     */
+    noSubtitles: boolean
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Trickplay
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/mediastream/trickplay/trickplay.go
+ * - Filename: trickplay.go
+ * - Package: trickplay
+ */
+export type Trickplay_Info = {
+    hash: string
+    path: string
+    status: Trickplay_Status
+}
+
+/**
+ * - Filepath: internal/mediastream/trickplay/trickplay.go
+ * - Filename: trickplay.go
+ * - Package: trickplay
+ */
+export type Trickplay_Status = "queued" | "generating" | "ready" | "failed"
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Tvdb
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -3539,6 +4556,7 @@ export type MediaInfo = {
     videos?: Array<Video>
     audios?: Array<Audio>
     subtitles?: Array<Subtitle>
+    unsupportedSubtitles?: Array<Subtitle>
     fonts?: Array<string>
     chapters?: Array<Chapter>
 }
@@ -3588,5 +4606,8 @@ export type Video = {
     width: number
     height: number
     bitrate: number
+    pixFmt: string
+    colorTransfer: string
+    colorPrimaries: string
 }
 
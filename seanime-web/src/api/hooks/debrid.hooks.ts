@@ -7,11 +7,18 @@ import {
     DebridDownloadTorrent_Variables,
     DebridGetTorrentFilePreviews_Variables,
     DebridGetTorrentInfo_Variables,
+    DebridStartCloudStream_Variables,
     DebridStartStream_Variables,
     SaveDebridSettings_Variables,
 } from "@/api/generated/endpoint.types"
 import { API_ENDPOINTS } from "@/api/generated/endpoints"
-import { Debrid_TorrentInfo, Debrid_TorrentItem, DebridClient_FilePreview, Models_DebridSettings } from "@/api/generated/types"
+import {
+    Debrid_TorrentInfo,
+    Debrid_TorrentItem,
+    DebridClient_CloudFile,
+    DebridClient_FilePreview,
+    Models_DebridSettings,
+} from "@/api/generated/types"
 import { useQueryClient } from "@tanstack/react-query"
 import { toast } from "sonner"
 
@@ -140,3 +147,36 @@ export function useDebridCancelStream() {
         },
     })
 }
+
+export function useDebridGetCloudFiles(mediaId: number | undefined | null, enabled: boolean) {
+    return useServerQuery<Array<DebridClient_CloudFile>>({
+        endpoint: API_ENDPOINTS.DEBRID.DebridGetCloudFiles.endpoint.replace("{id}", String(mediaId)),
+        method: API_ENDPOINTS.DEBRID.DebridGetCloudFiles.methods[0],
+        queryKey: [API_ENDPOINTS.DEBRID.DebridGetCloudFiles.key, String(mediaId)],
+        enabled: enabled && !!mediaId,
+    })
+}
+
+export function useDebridRefreshCloudLibrary() {
+    const qc = useQueryClient()
+    return useServerMutation<boolean>({
+        endpoint: API_ENDPOINTS.DEBRID.DebridRefreshCloudLibrary.endpoint,
+        method: API_ENDPOINTS.DEBRID.DebridRefreshCloudLibrary.methods[0],
+        mutationKey: [API_ENDPOINTS.DEBRID.DebridRefreshCloudLibrary.key],
+        onSuccess: async () => {
+            toast.success("Cloud library refreshed")
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.ANIME_COLLECTION.GetLibraryCollection.key] })
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.DEBRID.DebridGetCloudFiles.key] })
+        },
+    })
+}
+
+export function useDebridStartCloudStream() {
+    return useServerMutation<boolean, DebridStartCloudStream_Variables>({
+        endpoint: API_ENDPOINTS.DEBRID.DebridStartCloudStream.endpoint,
+        method: API_ENDPOINTS.DEBRID.DebridStartCloudStream.methods[0],
+        mutationKey: [API_ENDPOINTS.DEBRID.DebridStartCloudStream.key],
+        onSuccess: async () => {
+        },
+    })
+}
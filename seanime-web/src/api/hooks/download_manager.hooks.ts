@@ -0,0 +1,95 @@
+import { useServerMutation, useServerQuery } from "@/api/client/requests"
+import {
+    DownloadManagerJobAction_Variables,
+    DownloadManagerSetJobPriority_Variables,
+    DownloadManagerSetPaused_Variables,
+    SaveDownloadManagerSettings_Variables,
+} from "@/api/generated/endpoint.types"
+import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { DownloadManager_Snapshot, Models_DownloadManagerSettings } from "@/api/generated/types"
+import { useWebsocketMessageListener } from "@/app/(main)/_hooks/handle-websockets"
+import { WSEvents } from "@/lib/server/ws-events"
+import { useQueryClient } from "@tanstack/react-query"
+import { toast } from "sonner"
+
+/**
+ * Returns the downloads of all sources.
+ * The snapshot is updated when the server sends the 'download-manager-updated' event.
+ */
+export function useGetDownloadManagerSnapshot() {
+    const qc = useQueryClient()
+
+    useWebsocketMessageListener<DownloadManager_Snapshot>({
+        type: WSEvents.DOWNLOAD_MANAGER_UPDATED,
+        onMessage: data => {
+            qc.setQueryData([API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSnapshot.key], data)
+        },
+    })
+
+    return useServerQuery<DownloadManager_Snapshot>({
+        endpoint: API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSnapshot.endpoint,
+        method: API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSnapshot.methods[0],
+        queryKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSnapshot.key],
+        enabled: true,
+    })
+}
+
+export function useDownloadManagerSetPaused() {
+    const qc = useQueryClient()
+    return useServerMutation<DownloadManager_Snapshot, DownloadManagerSetPaused_Variables>({
+        endpoint: API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerSetPaused.endpoint,
+        method: API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerSetPaused.methods[0],
+        mutationKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerSetPaused.key],
+        onSuccess: async data => {
+            qc.setQueryData([API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSnapshot.key], data)
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSettings.key] })
+        },
+    })
+}
+
+export function useDownloadManagerJobAction() {
+    const qc = useQueryClient()
+    return useServerMutation<DownloadManager_Snapshot, DownloadManagerJobAction_Variables>({
+        endpoint: API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerJobAction.endpoint,
+        method: API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerJobAction.methods[0],
+        mutationKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerJobAction.key],
+        onSuccess: async data => {
+            qc.setQueryData([API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSnapshot.key], data)
+        },
+    })
+}
+
+export function useDownloadManagerSetJobPriority() {
+    const qc = useQueryClient()
+    return useServerMutation<DownloadManager_Snapshot, DownloadManagerSetJobPriority_Variables>({
+        endpoint: API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerSetJobPriority.endpoint,
+        method: API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerSetJobPriority.methods[0],
+        mutationKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.DownloadManagerSetJobPriority.key],
+        onSuccess: async data => {
+            qc.setQueryData([API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSnapshot.key], data)
+        },
+    })
+}
+
+export function useGetDownloadManagerSettings() {
+    return useServerQuery<Models_DownloadManagerSettings>({
+        endpoint: API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSettings.endpoint,
+        method: API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSettings.methods[0],
+        queryKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSettings.key],
+        enabled: true,
+    })
+}
+
+export function useSaveDownloadManagerSettings() {
+    const qc = useQueryClient()
+    return useServerMutation<Models_DownloadManagerSettings, SaveDownloadManagerSettings_Variables>({
+        endpoint: API_ENDPOINTS.DOWNLOAD_MANAGER.SaveDownloadManagerSettings.endpoint,
+        method: API_ENDPOINTS.DOWNLOAD_MANAGER.SaveDownloadManagerSettings.methods[0],
+        mutationKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.SaveDownloadManagerSettings.key],
+        onSuccess: async () => {
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSettings.key] })
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.DOWNLOAD_MANAGER.GetDownloadManagerSnapshot.key] })
+            toast.success("Settings saved")
+        },
+    })
+}
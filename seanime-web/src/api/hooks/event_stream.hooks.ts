@@ -0,0 +1,61 @@
+import { useServerMutation, useServerQuery } from "@/api/client/requests"
+import { DeleteEventWebhook_Variables, SaveEventWebhook_Variables, TestEventWebhook_Variables } from "@/api/generated/endpoint.types"
+import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { EventStream_TypeInfo, Models_EventWebhook } from "@/api/generated/types"
+import { useQueryClient } from "@tanstack/react-query"
+import { toast } from "sonner"
+
+export function useGetEventStreamTypes() {
+    return useServerQuery<Array<EventStream_TypeInfo>>({
+        endpoint: API_ENDPOINTS.EVENT_STREAM.GetEventStreamTypes.endpoint,
+        method: API_ENDPOINTS.EVENT_STREAM.GetEventStreamTypes.methods[0],
+        queryKey: [API_ENDPOINTS.EVENT_STREAM.GetEventStreamTypes.key],
+        enabled: true,
+    })
+}
+
+export function useGetEventWebhooks() {
+    return useServerQuery<Array<Models_EventWebhook>>({
+        endpoint: API_ENDPOINTS.EVENT_STREAM.GetEventWebhooks.endpoint,
+        method: API_ENDPOINTS.EVENT_STREAM.GetEventWebhooks.methods[0],
+        queryKey: [API_ENDPOINTS.EVENT_STREAM.GetEventWebhooks.key],
+        enabled: true,
+    })
+}
+
+export function useSaveEventWebhook() {
+    const qc = useQueryClient()
+    return useServerMutation<Models_EventWebhook, SaveEventWebhook_Variables>({
+        endpoint: API_ENDPOINTS.EVENT_STREAM.SaveEventWebhook.endpoint,
+        method: API_ENDPOINTS.EVENT_STREAM.SaveEventWebhook.methods[0],
+        mutationKey: [API_ENDPOINTS.EVENT_STREAM.SaveEventWebhook.key],
+        onSuccess: async () => {
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.EVENT_STREAM.GetEventWebhooks.key] })
+            toast.success("Webhook saved")
+        },
+    })
+}
+
+export function useDeleteEventWebhook() {
+    const qc = useQueryClient()
+    return useServerMutation<boolean, DeleteEventWebhook_Variables>({
+        endpoint: API_ENDPOINTS.EVENT_STREAM.DeleteEventWebhook.endpoint,
+        method: API_ENDPOINTS.EVENT_STREAM.DeleteEventWebhook.methods[0],
+        mutationKey: [API_ENDPOINTS.EVENT_STREAM.DeleteEventWebhook.key],
+        onSuccess: async () => {
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.EVENT_STREAM.GetEventWebhooks.key] })
+            toast.success("Webhook deleted")
+        },
+    })
+}
+
+export function useTestEventWebhook() {
+    return useServerMutation<boolean, TestEventWebhook_Variables>({
+        endpoint: API_ENDPOINTS.EVENT_STREAM.TestEventWebhook.endpoint,
+        method: API_ENDPOINTS.EVENT_STREAM.TestEventWebhook.methods[0],
+        mutationKey: [API_ENDPOINTS.EVENT_STREAM.TestEventWebhook.key],
+        onSuccess: async () => {
+            toast.success("Ping delivered")
+        },
+    })
+}
@@ -0,0 +1,12 @@
+import { useServerQuery } from "@/api/client/requests"
+import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { Filesystem_LibraryRootStatus } from "@/api/generated/types"
+
+export function useGetLibraryRoots() {
+    return useServerQuery<Array<Filesystem_LibraryRootStatus>>({
+        endpoint: API_ENDPOINTS.LIBRARY_ROOTS.GetLibraryRoots.endpoint,
+        method: API_ENDPOINTS.LIBRARY_ROOTS.GetLibraryRoots.methods[0],
+        queryKey: [API_ENDPOINTS.LIBRARY_ROOTS.GetLibraryRoots.key],
+        enabled: true,
+    })
+}
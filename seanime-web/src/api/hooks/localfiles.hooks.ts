@@ -1,6 +1,7 @@
 import { useServerMutation, useServerQuery } from "@/api/client/requests"
 import {
     DeleteLocalFiles_Variables,
+    FilterLocalFiles_Variables,
     ImportLocalFiles_Variables,
     LocalFileBulkAction_Variables,
     UpdateLocalFileData_Variables,
@@ -20,6 +21,16 @@ export function useGetLocalFiles() {
     })
 }
 
+export function useFilterLocalFiles(variables: FilterLocalFiles_Variables, enabled: boolean = true) {
+    return useServerQuery<Array<Anime_LocalFile>, FilterLocalFiles_Variables>({
+        endpoint: API_ENDPOINTS.LOCALFILES.FilterLocalFiles.endpoint,
+        method: API_ENDPOINTS.LOCALFILES.FilterLocalFiles.methods[0],
+        queryKey: [API_ENDPOINTS.LOCALFILES.FilterLocalFiles.key, JSON.stringify(variables)],
+        data: variables,
+        enabled: enabled,
+    })
+}
+
 export function useLocalFileBulkAction() {
     const qc = useQueryClient()
 
@@ -0,0 +1,41 @@
+import { useServerMutation, useServerQuery } from "@/api/client/requests"
+import { DeleteMatchingRule_Variables, SaveMatchingRule_Variables } from "@/api/generated/endpoint.types"
+import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { Models_MatchingRule } from "@/api/generated/types"
+import { useQueryClient } from "@tanstack/react-query"
+import { toast } from "sonner"
+
+export function useGetMatchingRules() {
+    return useServerQuery<Array<Models_MatchingRule>>({
+        endpoint: API_ENDPOINTS.MATCHING_RULES.GetMatchingRules.endpoint,
+        method: API_ENDPOINTS.MATCHING_RULES.GetMatchingRules.methods[0],
+        queryKey: [API_ENDPOINTS.MATCHING_RULES.GetMatchingRules.key],
+        enabled: true,
+    })
+}
+
+export function useSaveMatchingRule() {
+    const qc = useQueryClient()
+    return useServerMutation<Models_MatchingRule, SaveMatchingRule_Variables>({
+        endpoint: API_ENDPOINTS.MATCHING_RULES.SaveMatchingRule.endpoint,
+        method: API_ENDPOINTS.MATCHING_RULES.SaveMatchingRule.methods[0],
+        mutationKey: [API_ENDPOINTS.MATCHING_RULES.SaveMatchingRule.key],
+        onSuccess: async () => {
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.MATCHING_RULES.GetMatchingRules.key] })
+            toast.success("Rule saved")
+        },
+    })
+}
+
+export function useDeleteMatchingRule() {
+    const qc = useQueryClient()
+    return useServerMutation<boolean, DeleteMatchingRule_Variables>({
+        endpoint: API_ENDPOINTS.MATCHING_RULES.DeleteMatchingRule.endpoint,
+        method: API_ENDPOINTS.MATCHING_RULES.DeleteMatchingRule.methods[0],
+        mutationKey: [API_ENDPOINTS.MATCHING_RULES.DeleteMatchingRule.key],
+        onSuccess: async () => {
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.MATCHING_RULES.GetMatchingRules.key] })
+            toast.success("Rule deleted")
+        },
+    })
+}
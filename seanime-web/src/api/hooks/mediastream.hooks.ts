@@ -2,10 +2,11 @@ import { useServerMutation, useServerQuery } from "@/api/client/requests"
 import {
     PreloadMediastreamMediaContainer_Variables,
     RequestMediastreamMediaContainer_Variables,
+    RequestMediastreamTrickplay_Variables,
     SaveMediastreamSettings_Variables,
 } from "@/api/generated/endpoint.types"
 import { API_ENDPOINTS } from "@/api/generated/endpoints"
-import { Mediastream_MediaContainer, Models_MediastreamSettings } from "@/api/generated/types"
+import { Mediastream_MediaContainer, Models_MediastreamSettings, Nullish, Trickplay_Info } from "@/api/generated/types"
 import { logger } from "@/lib/helpers/debug"
 import { useQueryClient } from "@tanstack/react-query"
 import { toast } from "sonner"
@@ -64,3 +65,14 @@ export function useMediastreamShutdownTranscodeStream() {
         },
     })
 }
+
+export function useRequestMediastreamTrickplay(path: Nullish<string>, enabled: boolean) {
+    return useServerQuery<Trickplay_Info | null, RequestMediastreamTrickplay_Variables>({
+        endpoint: API_ENDPOINTS.MEDIASTREAM.RequestMediastreamTrickplay.endpoint,
+        method: API_ENDPOINTS.MEDIASTREAM.RequestMediastreamTrickplay.methods[0],
+        queryKey: [API_ENDPOINTS.MEDIASTREAM.RequestMediastreamTrickplay.key, path],
+        data: { path: path! },
+        enabled: !!path && enabled,
+        refetchOnWindowFocus: false,
+    })
+}
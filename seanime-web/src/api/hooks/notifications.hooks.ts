@@ -0,0 +1,65 @@
+import { useServerMutation, useServerQuery } from "@/api/client/requests"
+import {
+    DeleteNotificationChannel_Variables,
+    SaveNotificationChannel_Variables,
+    TestNotificationChannel_Variables,
+} from "@/api/generated/endpoint.types"
+import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { Models_NotificationChannel, Notifier_EventInfo } from "@/api/generated/types"
+import { useQueryClient } from "@tanstack/react-query"
+import { toast } from "sonner"
+
+export function useGetNotificationEvents() {
+    return useServerQuery<Array<Notifier_EventInfo>>({
+        endpoint: API_ENDPOINTS.NOTIFICATIONS.GetNotificationEvents.endpoint,
+        method: API_ENDPOINTS.NOTIFICATIONS.GetNotificationEvents.methods[0],
+        queryKey: [API_ENDPOINTS.NOTIFICATIONS.GetNotificationEvents.key],
+        enabled: true,
+    })
+}
+
+export function useGetNotificationChannels() {
+    return useServerQuery<Array<Models_NotificationChannel>>({
+        endpoint: API_ENDPOINTS.NOTIFICATIONS.GetNotificationChannels.endpoint,
+        method: API_ENDPOINTS.NOTIFICATIONS.GetNotificationChannels.methods[0],
+        queryKey: [API_ENDPOINTS.NOTIFICATIONS.GetNotificationChannels.key],
+        enabled: true,
+    })
+}
+
+export function useSaveNotificationChannel() {
+    const qc = useQueryClient()
+    return useServerMutation<Models_NotificationChannel, SaveNotificationChannel_Variables>({
+        endpoint: API_ENDPOINTS.NOTIFICATIONS.SaveNotificationChannel.endpoint,
+        method: API_ENDPOINTS.NOTIFICATIONS.SaveNotificationChannel.methods[0],
+        mutationKey: [API_ENDPOINTS.NOTIFICATIONS.SaveNotificationChannel.key],
+        onSuccess: async () => {
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.NOTIFICATIONS.GetNotificationChannels.key] })
+            toast.success("Channel saved")
+        },
+    })
+}
+
+export function useDeleteNotificationChannel() {
+    const qc = useQueryClient()
+    return useServerMutation<boolean, DeleteNotificationChannel_Variables>({
+        endpoint: API_ENDPOINTS.NOTIFICATIONS.DeleteNotificationChannel.endpoint,
+        method: API_ENDPOINTS.NOTIFICATIONS.DeleteNotificationChannel.methods[0],
+        mutationKey: [API_ENDPOINTS.NOTIFICATIONS.DeleteNotificationChannel.key],
+        onSuccess: async () => {
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.NOTIFICATIONS.GetNotificationChannels.key] })
+            toast.success("Channel deleted")
+        },
+    })
+}
+
+export function useTestNotificationChannel() {
+    return useServerMutation<boolean, TestNotificationChannel_Variables>({
+        endpoint: API_ENDPOINTS.NOTIFICATIONS.TestNotificationChannel.endpoint,
+        method: API_ENDPOINTS.NOTIFICATIONS.TestNotificationChannel.methods[0],
+        mutationKey: [API_ENDPOINTS.NOTIFICATIONS.TestNotificationChannel.key],
+        onSuccess: async () => {
+            toast.success("Test notification sent")
+        },
+    })
+}
@@ -1,7 +1,7 @@
 import { useServerMutation } from "@/api/client/requests"
-import { ScanLocalFiles_Variables } from "@/api/generated/endpoint.types"
+import { DryRunScanLocalFiles_Variables, ScanLocalFiles_Variables } from "@/api/generated/endpoint.types"
 import { API_ENDPOINTS } from "@/api/generated/endpoints"
-import { Anime_LocalFile } from "@/api/generated/types"
+import { Anime_LocalFile, Summary_ScanSummary } from "@/api/generated/types"
 import { useQueryClient } from "@tanstack/react-query"
 import { toast } from "sonner"
 
@@ -23,4 +23,31 @@ export function useScanLocalFiles(onSuccess?: () => void) {
     })
 }
 
+export function useDryRunScanLocalFiles(onSuccess?: (data: Summary_ScanSummary | undefined) => void) {
+    const queryClient = useQueryClient()
+
+    return useServerMutation<Summary_ScanSummary, DryRunScanLocalFiles_Variables>({
+        endpoint: API_ENDPOINTS.SCAN.DryRunScanLocalFiles.endpoint,
+        method: API_ENDPOINTS.SCAN.DryRunScanLocalFiles.methods[0],
+        mutationKey: [API_ENDPOINTS.SCAN.DryRunScanLocalFiles.key],
+        onSuccess: async data => {
+            await queryClient.invalidateQueries({ queryKey: [API_ENDPOINTS.SCAN_SUMMARY.GetScanSummaries.key] })
+            toast.success("Dry run completed")
+            onSuccess?.(data)
+        },
+    })
+}
 
+export function useCancelScan() {
+    return useServerMutation<boolean>({
+        endpoint: API_ENDPOINTS.SCAN.CancelScan.endpoint,
+        method: API_ENDPOINTS.SCAN.CancelScan.methods[0],
+        mutationKey: [API_ENDPOINTS.SCAN.CancelScan.key],
+        onSuccess: async data => {
+            // The scan request fails once the scan has stopped
+            if (!data) {
+                toast.info("No scan is running")
+            }
+        },
+    })
+}
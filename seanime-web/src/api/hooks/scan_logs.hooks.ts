@@ -0,0 +1,23 @@
+import { useServerQuery } from "@/api/client/requests"
+import { GetScanLogEntries_Variables } from "@/api/generated/endpoint.types"
+import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { Scanner_ScanLogEntry, Scanner_ScanLogInfo } from "@/api/generated/types"
+
+export function useGetScanLogs() {
+    return useServerQuery<Array<Scanner_ScanLogInfo>>({
+        endpoint: API_ENDPOINTS.SCAN_LOGS.GetScanLogs.endpoint,
+        method: API_ENDPOINTS.SCAN_LOGS.GetScanLogs.methods[0],
+        queryKey: [API_ENDPOINTS.SCAN_LOGS.GetScanLogs.key],
+        enabled: true,
+    })
+}
+
+export function useGetScanLogEntries(variables: GetScanLogEntries_Variables, enabled: boolean = true) {
+    return useServerQuery<Array<Scanner_ScanLogEntry>, GetScanLogEntries_Variables>({
+        endpoint: API_ENDPOINTS.SCAN_LOGS.GetScanLogEntries.endpoint,
+        method: API_ENDPOINTS.SCAN_LOGS.GetScanLogEntries.methods[0],
+        queryKey: [API_ENDPOINTS.SCAN_LOGS.GetScanLogEntries.key, JSON.stringify(variables)],
+        data: variables,
+        enabled: enabled,
+    })
+}
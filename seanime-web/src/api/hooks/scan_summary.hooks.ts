@@ -1,6 +1,9 @@
-import { useServerQuery } from "@/api/client/requests"
+import { useServerMutation, useServerQuery } from "@/api/client/requests"
+import { ApplyScanDiff_Variables, DiscardScanDiff_Variables } from "@/api/generated/endpoint.types"
 import { API_ENDPOINTS } from "@/api/generated/endpoints"
-import { DB_ScanSummaryItem } from "@/api/generated/types"
+import { Anime_LocalFile, DB_ScanSummaryItem } from "@/api/generated/types"
+import { useQueryClient } from "@tanstack/react-query"
+import { toast } from "sonner"
 
 export function useGetScanSummaries() {
     return useServerQuery<Array<DB_ScanSummaryItem>>({
@@ -11,3 +14,33 @@ export function useGetScanSummaries() {
     })
 }
 
+export function useApplyScanDiff() {
+    const queryClient = useQueryClient()
+
+    return useServerMutation<Array<Anime_LocalFile>, ApplyScanDiff_Variables>({
+        endpoint: API_ENDPOINTS.SCAN_SUMMARY.ApplyScanDiff.endpoint,
+        method: API_ENDPOINTS.SCAN_SUMMARY.ApplyScanDiff.methods[0],
+        mutationKey: [API_ENDPOINTS.SCAN_SUMMARY.ApplyScanDiff.key],
+        onSuccess: async () => {
+            await queryClient.invalidateQueries({ queryKey: [API_ENDPOINTS.SCAN_SUMMARY.GetScanSummaries.key] })
+            await queryClient.invalidateQueries({ queryKey: [API_ENDPOINTS.ANIME_COLLECTION.GetLibraryCollection.key] })
+            await queryClient.invalidateQueries({ queryKey: [API_ENDPOINTS.ANIME_ENTRIES.GetMissingEpisodes.key] })
+            await queryClient.invalidateQueries({ queryKey: [API_ENDPOINTS.ANIME_ENTRIES.GetAnimeEntry.key] })
+            toast.success("Changes saved")
+        },
+    })
+}
+
+export function useDiscardScanDiff() {
+    const queryClient = useQueryClient()
+
+    return useServerMutation<boolean, DiscardScanDiff_Variables>({
+        endpoint: API_ENDPOINTS.SCAN_SUMMARY.DiscardScanDiff.endpoint,
+        method: API_ENDPOINTS.SCAN_SUMMARY.DiscardScanDiff.methods[0],
+        mutationKey: [API_ENDPOINTS.SCAN_SUMMARY.DiscardScanDiff.key],
+        onSuccess: async () => {
+            await queryClient.invalidateQueries({ queryKey: [API_ENDPOINTS.SCAN_SUMMARY.GetScanSummaries.key] })
+            toast.success("Changes discarded")
+        },
+    })
+}
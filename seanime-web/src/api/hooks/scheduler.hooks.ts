@@ -0,0 +1,64 @@
+import { useServerMutation, useServerQuery } from "@/api/client/requests"
+import { RunScheduledJob_Variables, UpdateScheduledJob_Variables } from "@/api/generated/endpoint.types"
+import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { Models_ScheduledJobRun, Scheduler_JobInfo } from "@/api/generated/types"
+import { useWebsocketMessageListener } from "@/app/(main)/_hooks/handle-websockets"
+import { WSEvents } from "@/lib/server/ws-events"
+import { useQueryClient } from "@tanstack/react-query"
+import { toast } from "sonner"
+
+/**
+ * Returns the scheduled jobs.
+ * The jobs and their history are refetched when the server sends the 'scheduled-jobs-updated' event.
+ */
+export function useGetScheduledJobs() {
+    const qc = useQueryClient()
+
+    useWebsocketMessageListener({
+        type: WSEvents.SCHEDULED_JOBS_UPDATED,
+        onMessage: () => {
+            qc.invalidateQueries({ queryKey: [API_ENDPOINTS.SCHEDULER.GetScheduledJobs.key] })
+            qc.invalidateQueries({ queryKey: [API_ENDPOINTS.SCHEDULER.GetScheduledJobHistory.key] })
+        },
+    })
+
+    return useServerQuery<Array<Scheduler_JobInfo>>({
+        endpoint: API_ENDPOINTS.SCHEDULER.GetScheduledJobs.endpoint,
+        method: API_ENDPOINTS.SCHEDULER.GetScheduledJobs.methods[0],
+        queryKey: [API_ENDPOINTS.SCHEDULER.GetScheduledJobs.key],
+        enabled: true,
+    })
+}
+
+export function useUpdateScheduledJob() {
+    const qc = useQueryClient()
+    return useServerMutation<Scheduler_JobInfo, UpdateScheduledJob_Variables>({
+        endpoint: API_ENDPOINTS.SCHEDULER.UpdateScheduledJob.endpoint,
+        method: API_ENDPOINTS.SCHEDULER.UpdateScheduledJob.methods[0],
+        mutationKey: [API_ENDPOINTS.SCHEDULER.UpdateScheduledJob.key],
+        onSuccess: async () => {
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.SCHEDULER.GetScheduledJobs.key] })
+            toast.success("Job updated")
+        },
+    })
+}
+
+export function useRunScheduledJob() {
+    return useServerMutation<boolean, RunScheduledJob_Variables>({
+        endpoint: API_ENDPOINTS.SCHEDULER.RunScheduledJob.endpoint,
+        method: API_ENDPOINTS.SCHEDULER.RunScheduledJob.methods[0],
+        mutationKey: [API_ENDPOINTS.SCHEDULER.RunScheduledJob.key],
+        onSuccess: async () => {
+            toast.info("Job started")
+        },
+    })
+}
+
+export function useGetScheduledJobHistory(name: string | null) {
+    return useServerQuery<Array<Models_ScheduledJobRun>>({
+        endpoint: API_ENDPOINTS.SCHEDULER.GetScheduledJobHistory.endpoint.replace("{name}", name ?? ""),
+        method: API_ENDPOINTS.SCHEDULER.GetScheduledJobHistory.methods[0],
+        queryKey: [API_ENDPOINTS.SCHEDULER.GetScheduledJobHistory.key, name],
+        enabled: !!name,
+    })
+}
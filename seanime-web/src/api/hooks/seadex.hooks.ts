@@ -0,0 +1,24 @@
+import { useServerMutation } from "@/api/client/requests"
+import { GetSeaDexLibraryAnalysis_Variables, SeaDexEnqueueReleases_Variables } from "@/api/generated/endpoint.types"
+import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { SeaDex_LibraryAnalysis } from "@/api/generated/types"
+import { toast } from "sonner"
+
+export function useGetSeaDexLibraryAnalysis() {
+    return useServerMutation<SeaDex_LibraryAnalysis, GetSeaDexLibraryAnalysis_Variables>({
+        endpoint: API_ENDPOINTS.SEADEX.GetSeaDexLibraryAnalysis.endpoint,
+        method: API_ENDPOINTS.SEADEX.GetSeaDexLibraryAnalysis.methods[0],
+        mutationKey: [API_ENDPOINTS.SEADEX.GetSeaDexLibraryAnalysis.key],
+    })
+}
+
+export function useSeaDexEnqueueReleases() {
+    return useServerMutation<boolean, SeaDexEnqueueReleases_Variables>({
+        endpoint: API_ENDPOINTS.SEADEX.SeaDexEnqueueReleases.endpoint,
+        method: API_ENDPOINTS.SEADEX.SeaDexEnqueueReleases.methods[0],
+        mutationKey: [API_ENDPOINTS.SEADEX.SeaDexEnqueueReleases.key],
+        onSuccess: async () => {
+            toast.success("Releases added")
+        },
+    })
+}
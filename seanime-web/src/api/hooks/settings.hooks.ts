@@ -38,6 +38,7 @@ export function useSaveSettings() {
         onSuccess: async () => {
             await queryClient.invalidateQueries({ queryKey: [API_ENDPOINTS.SETTINGS.GetSettings.key] })
             await queryClient.invalidateQueries({ queryKey: [API_ENDPOINTS.STATUS.GetStatus.key] })
+            await queryClient.invalidateQueries({ queryKey: [API_ENDPOINTS.LIBRARY_ROOTS.GetLibraryRoots.key] })
             toast.success("Settings saved")
         },
     })
@@ -53,6 +54,7 @@ export function useSaveAutoDownloaderSettings() {
         onSuccess: async () => {
             await queryClient.invalidateQueries({ queryKey: [API_ENDPOINTS.SETTINGS.GetSettings.key] })
             await queryClient.invalidateQueries({ queryKey: [API_ENDPOINTS.STATUS.GetStatus.key] })
+            await queryClient.invalidateQueries({ queryKey: [API_ENDPOINTS.LIBRARY_ROOTS.GetLibraryRoots.key] })
             toast.success("Settings saved")
         },
     })
@@ -0,0 +1,63 @@
+import { useServerMutation, useServerQuery } from "@/api/client/requests"
+import { DeleteSkipSegments_Variables, GetSkipSegments_Variables } from "@/api/generated/endpoint.types"
+import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { Nullish, SkipSegments_Markers } from "@/api/generated/types"
+import { AniSkipTime } from "@/app/(main)/_features/sea-media-player/aniskip"
+import { useWebsocketMessageListener } from "@/app/(main)/_hooks/handle-websockets"
+import { WSEvents } from "@/lib/server/ws-events"
+import { useQueryClient } from "@tanstack/react-query"
+import React from "react"
+
+export function useGetSkipSegments(path: Nullish<string>) {
+    return useServerQuery<SkipSegments_Markers | null, GetSkipSegments_Variables>({
+        endpoint: API_ENDPOINTS.SKIP_SEGMENTS.GetSkipSegments.endpoint,
+        method: API_ENDPOINTS.SKIP_SEGMENTS.GetSkipSegments.methods[0],
+        queryKey: [API_ENDPOINTS.SKIP_SEGMENTS.GetSkipSegments.key, path],
+        data: { path: path! },
+        enabled: !!path,
+        refetchOnWindowFocus: false,
+    })
+}
+
+export function useDeleteSkipSegments() {
+    const qc = useQueryClient()
+    return useServerMutation<boolean, DeleteSkipSegments_Variables>({
+        endpoint: API_ENDPOINTS.SKIP_SEGMENTS.DeleteSkipSegments.endpoint,
+        method: API_ENDPOINTS.SKIP_SEGMENTS.DeleteSkipSegments.methods[0],
+        mutationKey: [API_ENDPOINTS.SKIP_SEGMENTS.DeleteSkipSegments.key],
+        onSuccess: async () => {
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.SKIP_SEGMENTS.GetSkipSegments.key] })
+        },
+    })
+}
+
+/**
+ * Returns the skip segments of a local file in the AniSkip format used by the media player.
+ * The markers are updated when the server finishes detecting them.
+ */
+export function useLocalFileSkipData(path: Nullish<string>, duration: Nullish<number>) {
+    const qc = useQueryClient()
+    const { data: markers } = useGetSkipSegments(path)
+
+    useWebsocketMessageListener<SkipSegments_Markers>({
+        type: WSEvents.SKIP_SEGMENTS_UPDATED,
+        onMessage: data => {
+            qc.setQueryData([API_ENDPOINTS.SKIP_SEGMENTS.GetSkipSegments.key, data.path], data)
+        },
+    })
+
+    return React.useMemo(() => {
+        if (!markers?.segments?.length) return undefined
+        const toSkipTime = (type: "intro" | "outro"): AniSkipTime | null => {
+            const segment = markers.segments?.find(s => s.type === type)
+            if (!segment) return null
+            return {
+                interval: { startTime: segment.startTime, endTime: segment.endTime },
+                skipType: type === "intro" ? "op" : "ed",
+                skipId: `${markers.hash}-${type}`,
+                episodeLength: duration || segment.endTime,
+            }
+        }
+        return { op: toSkipTime("intro"), ed: toSkipTime("outro") }
+    }, [markers, duration])
+}
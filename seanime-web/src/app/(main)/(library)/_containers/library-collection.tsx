@@ -114,6 +114,7 @@ export const LibraryCollectionEntryItem = React.memo(({ entry }: { entry: Anime_
             media={entry.media!}
             listData={entry.listData}
             libraryData={entry.libraryData}
+            inCloud={entry.inCloud}
             showListDataButton
             withAudienceScore={false}
             type="anime"
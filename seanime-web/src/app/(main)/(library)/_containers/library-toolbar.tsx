@@ -23,7 +23,7 @@ import { FiSearch } from "react-icons/fi"
 import { IoLibrary, IoLibrarySharp } from "react-icons/io5"
 import { MdOutlineVideoLibrary } from "react-icons/md"
 import { PiClockCounterClockwiseFill } from "react-icons/pi"
-import { TbFileSad, TbReload } from "react-icons/tb"
+import { TbFileSad, TbListSearch, TbReload } from "react-icons/tb"
 
 export type LibraryToolbarProps = {
     collectionList: Anime_LibraryCollectionList[]
@@ -157,6 +157,16 @@ export function LibraryToolbar(props: LibraryToolbarProps) {
                             <span>Scan summaries</span>
                         </DropdownMenuItem>
                     </SeaLink>
+
+                    <SeaLink href="/seadex">
+                        <DropdownMenuItem
+                            disabled={!hasScanned}
+                            className={cn({ "!text-[--muted]": !hasScanned })}
+                        >
+                            <TbListSearch />
+                            <span>Release recommendations</span>
+                        </DropdownMenuItem>
+                    </SeaLink>
                     </DropdownMenu>}
 
             </div>
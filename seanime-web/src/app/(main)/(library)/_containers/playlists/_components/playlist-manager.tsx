@@ -161,6 +161,16 @@ export function PlaylistManager(props: PlaylistManagerProps) {
     )
 }
 
+function getLocalFileLabel(localFile: Anime_LocalFile, media: AL_BaseAnime | undefined) {
+    switch (localFile.metadata?.type) {
+        case "special":
+            return `Special ${localFile.metadata.episode}`
+        case "nc":
+            return localFile.metadata.aniDBEpisode || localFile.parsedInfo?.title || "Extra"
+    }
+    return media?.format !== "MOVIE" ? `Episode ${localFile.metadata?.episode}` : "Movie"
+}
+
 type PlaylistMediaEntryProps = {
     entry: Anime_LibraryCollectionEntry
     paths: string[]
@@ -290,7 +300,7 @@ function SortableItem({ localFile, id, media, setPaths }: {
                 <div>
                     <p className="text-lg text-white font-semibold">
                         {localFile.metadata && <span>
-                            {media?.format !== "MOVIE" ? `Episode ${localFile.metadata?.episode}` : "Movie"}
+                            {getLocalFileLabel(localFile, media)}
                         </span>}
                         <span className="text-gray-400 font-medium max-w-lg truncate">
                             {" - "}{media?.title?.userPreferred || media?.title?.romaji}
@@ -321,6 +331,15 @@ function EntryEpisodeList(props: EntryEpisodeListProps) {
 
     const { data } = useGetPlaylistEpisodes(entry.mediaId, entry.listData?.progress || 0)
 
+    // Main episodes are sorted by episode number, specials and NC files are listed after them in the order they were returned
+    const localFiles = React.useMemo(() => {
+        const lfs = data?.filter(n => !!n.metadata) ?? []
+        return [
+            ...lfs.filter(n => n.metadata!.type === "main").sort((a, b) => a.metadata!.episode - b.metadata!.episode),
+            ...lfs.filter(n => n.metadata!.type !== "main"),
+        ]
+    }, [data])
+
     const handleSelect = (value: string) => {
         if (selectedPaths.length <= 10) {
             setSelectedPaths(prev => {
@@ -336,7 +355,7 @@ function EntryEpisodeList(props: EntryEpisodeListProps) {
 
     return (
         <div className="flex flex-col gap-2 overflow-auto p-1">
-            {data?.filter(n => !!n.metadata)?.sort((a, b) => a.metadata!.episode - b.metadata!.episode)?.map(lf => {
+            {localFiles.map(lf => {
                 return (
                     <div
                         key={lf.path}
@@ -347,7 +366,7 @@ function EntryEpisodeList(props: EntryEpisodeListProps) {
                         )}
                         onClick={() => handleSelect(lf.path)}
                     >
-                        <p className="">{entry.media?.format !== "MOVIE" ? `Episode ${lf.metadata!.episode}` : "Movie"}</p>
+                        <p className="">{getLocalFileLabel(lf, entry.media)}</p>
                         <p className="text-sm text-[--muted] font-normal italic max-w-lg line-clamp-1">{lf.name}</p>
                     </div>
                 )
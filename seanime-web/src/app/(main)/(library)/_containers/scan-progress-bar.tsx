@@ -1,8 +1,10 @@
 "use client"
+import { useCancelScan } from "@/api/hooks/scan.hooks"
 import { __scanner_isScanningAtom } from "@/app/(main)/(library)/_containers/scanner-modal"
 
 import { useWebsocketMessageListener } from "@/app/(main)/_hooks/handle-websockets"
 import { PageWrapper } from "@/components/shared/page-wrapper"
+import { Button } from "@/components/ui/button"
 import { Card, CardDescription, CardHeader } from "@/components/ui/card"
 import { Spinner } from "@/components/ui/loading-spinner"
 import { ProgressBar } from "@/components/ui/progress-bar"
@@ -10,17 +12,40 @@ import { WSEvents } from "@/lib/server/ws-events"
 import { useAtom } from "jotai/react"
 import React, { useState } from "react"
 
+// Sent with the "scan-progress-details" event, see scanner.ScanProgress
+type ScanProgressDetails = {
+    stage: string
+    percent: number
+    filesFound: number
+    filesToScan: number
+    mediaFetched: number
+    matched: number
+    hydrated: number
+    elapsedMs: number
+    etaMs: number
+}
+
+function formatEta(ms: number) {
+    const seconds = Math.round(ms / 1000)
+    if (seconds < 60) return `${seconds}s`
+    return `${Math.floor(seconds / 60)}m ${seconds % 60}s`
+}
+
 export function ScanProgressBar() {
 
     const [isScanning] = useAtom(__scanner_isScanningAtom)
 
     const [progress, setProgress] = useState(0)
     const [status, setStatus] = useState("Scanning...")
+    const [details, setDetails] = useState<ScanProgressDetails | null>(null)
+
+    const { mutate: cancelScan, isPending: isCancelling } = useCancelScan()
 
     React.useEffect(() => {
         if (!isScanning) {
             setProgress(0)
             setStatus("Scanning...")
+            setDetails(null)
         }
     }, [isScanning])
 
@@ -32,6 +57,14 @@ export function ScanProgressBar() {
         },
     })
 
+    useWebsocketMessageListener<ScanProgressDetails>({
+        type: WSEvents.SCAN_PROGRESS_DETAILS,
+        onMessage: data => {
+            setDetails(data)
+            setProgress(data.percent)
+        },
+    })
+
     useWebsocketMessageListener<string>({
         type: WSEvents.SCAN_STATUS,
         onMessage: data => {
@@ -64,6 +97,23 @@ export function ScanProgressBar() {
                             <CardDescription className="flex items-center gap-2 text-base text-[--foregorund]">
                                 <Spinner className="size-6" /> {progress}% - {status}
                             </CardDescription>
+                            {!!details && <div className="text-sm text-[--muted] flex flex-wrap gap-x-3">
+                                <span>{details.filesFound} files found</span>
+                                {details.mediaFetched > 0 && <span>{details.mediaFetched} media fetched</span>}
+                                {details.matched > 0 && <span>{details.matched} matched</span>}
+                                {details.hydrated > 0 && <span>{details.hydrated}/{details.matched} hydrated</span>}
+                                {details.etaMs > 0 && <span>~{formatEta(details.etaMs)} remaining</span>}
+                            </div>}
+                            <div className="flex justify-end">
+                                <Button
+                                    size="sm"
+                                    intent="alert-subtle"
+                                    loading={isCancelling}
+                                    onClick={() => cancelScan()}
+                                >
+                                    Cancel
+                                </Button>
+                            </div>
                         </CardHeader>
                     </Card>
                 </PageWrapper>
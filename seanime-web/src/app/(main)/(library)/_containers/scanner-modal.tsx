@@ -1,4 +1,4 @@
-import { useScanLocalFiles } from "@/api/hooks/scan.hooks"
+import { useDryRunScanLocalFiles, useScanLocalFiles } from "@/api/hooks/scan.hooks"
 
 import { useSeaCommandInject } from "@/app/(main)/_features/sea-command/use-inject"
 import { useServerStatus } from "@/app/(main)/_hooks/use-server-status"
@@ -10,6 +10,7 @@ import { Switch } from "@/components/ui/switch"
 import { useBoolean } from "@/hooks/use-disclosure"
 import { atom } from "jotai"
 import { useAtom } from "jotai/react"
+import { useRouter } from "next/navigation"
 import React from "react"
 import { FiSearch } from "react-icons/fi"
 
@@ -24,21 +25,33 @@ export function ScannerModal() {
     const anilistDataOnly = useBoolean(true)
     const skipLockedFiles = useBoolean(true)
     const skipIgnoredFiles = useBoolean(true)
+    const dryRun = useBoolean(false)
+    const router = useRouter()
 
     const { mutate: scanLibrary, isPending: isScanning } = useScanLocalFiles(() => {
         setOpen(false)
     })
 
+    const { mutate: dryRunScanLibrary, isPending: isDryRunning } = useDryRunScanLocalFiles(data => {
+        // Review the changes before they are saved
+        router.push(data?.id ? `/scan-summaries?id=${data.id}` : "/scan-summaries")
+    })
+
     React.useEffect(() => {
-        setScannerIsScanning(isScanning)
-    }, [isScanning])
+        setScannerIsScanning(isScanning || isDryRunning)
+    }, [isScanning, isDryRunning])
 
     function handleScan() {
-        scanLibrary({
+        const variables = {
             enhanced: !anilistDataOnly.active,
             skipLockedFiles: skipLockedFiles.active,
             skipIgnoredFiles: skipIgnoredFiles.active,
-        })
+        }
+        if (dryRun.active) {
+            dryRunScanLibrary(variables)
+        } else {
+            scanLibrary(variables)
+        }
         setOpen(false)
     }
 
@@ -131,13 +144,23 @@ export function ScannerModal() {
                             />
                         </AppLayoutStack>
 
+                        <Separator />
+
+                        <Switch
+                            side="right"
+                            label="Dry run"
+                            help={dryRun.active ? "Changes will be listed in the scan summary and saved once you review them" : ""}
+                            value={dryRun.active}
+                            onValueChange={v => dryRun.set(v as boolean)}
+                        />
+
                     </AppLayoutStack>
                 </div>
                 <Button
                     onClick={handleScan}
                     intent="primary"
                     leftIcon={<FiSearch />}
-                    loading={isScanning}
+                    loading={isScanning || isDryRunning}
                     className="w-full"
                     disabled={!serverStatus?.settings?.library?.libraryPath}
                 >
@@ -154,6 +154,7 @@ const LibraryCollectionEntryItem = React.memo(({ entry }: { entry: Anime_Library
             media={entry.media!}
             listData={entry.listData}
             libraryData={entry.libraryData}
+            inCloud={entry.inCloud}
             showListDataButton
             withAudienceScore={false}
             type="anime"
@@ -88,6 +88,12 @@ export function GettingStartedPage({ status }: { status: Status }) {
                                         includeOnlineStreamingInLibrary: false,
                                         scannerMatchingThreshold: 0,
                                         scannerMatchingAlgorithm: "",
+                                        scannerLearnMatchingRules: false,
+                                        scannerHashFiles: false,
+                                        scannerProbeMediaInfo: false,
+                                        scannerLogRetention: 0,
+                                        libraryRoots: [],
+                                        scannerHashLookupPath: "",
                                     },
                                     manga: {
                                         defaultMangaProvider: "",
@@ -454,6 +460,8 @@ export function GettingStartedPage({ status }: { status: Status }) {
                                                 { label: "None", value: "none" },
                                                 { label: "TorBox", value: "torbox" },
                                                 { label: "Real-Debrid", value: "realdebrid" },
+                                                { label: "AllDebrid", value: "alldebrid" },
+                                                { label: "Premiumize", value: "premiumize" },
                                             ]}
                                         />
 
@@ -36,6 +36,7 @@ import capitalize from "lodash/capitalize"
 import { usePathname, useRouter } from "next/navigation"
 import React, { useState } from "react"
 import { BiPlay } from "react-icons/bi"
+import { HiOutlineCloud } from "react-icons/hi"
 import { IoLibrarySharp } from "react-icons/io5"
 import { RiCalendarLine } from "react-icons/ri"
 
@@ -54,6 +55,8 @@ type MediaEntryCardProps<T extends "anime" | "manga"> = {
     showLibraryBadge?: T extends "anime" ? boolean : never
     showTrailer?: T extends "anime" ? boolean : never
     libraryData?: T extends "anime" ? Anime_EntryLibraryData : never
+    // Whether episodes are available on the Debrid service
+    inCloud?: T extends "anime" ? boolean : never
 } & MediaEntryCardBaseProps
 
 export function MediaEntryCard<T extends "anime" | "manga">(props: MediaEntryCardProps<T>) {
@@ -67,6 +70,7 @@ export function MediaEntryCard<T extends "anime" | "manga">(props: MediaEntryCar
         showTrailer: _showTrailer,
         type,
         withAudienceScore = true,
+        inCloud,
     } = props
 
     const router = useRouter()
@@ -321,6 +325,15 @@ export function MediaEntryCard<T extends "anime" | "manga">(props: MediaEntryCar
                         score={listData?.score}
                     />
                 </div>
+                {(type === "anime" && inCloud) && (
+                    <div className="absolute z-[10] left-1 top-1" title="Available on your Debrid service">
+                        <Badge
+                            className="text-white bg-gray-950 !bg-opacity-90 rounded-[--radius-md]"
+                            intent="gray-solid"
+                            size="md"
+                        ><HiOutlineCloud className="text-lg" /></Badge>
+                    </div>
+                )}
                 {(type === "anime" && !!libraryData && missingEpisodes.find(n => n.baseAnime?.id === media.id)) && (
                     <div className="absolute z-[10] w-full flex justify-center left-1 bottom-0">
                         <Badge
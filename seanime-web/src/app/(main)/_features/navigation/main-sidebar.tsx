@@ -29,7 +29,7 @@ import { useThemeSettings } from "@/lib/theme/hooks"
 import { useSetAtom } from "jotai"
 import { usePathname, useRouter } from "next/navigation"
 import React from "react"
-import { BiCalendarAlt, BiDownload, BiExtension, BiLogOut, BiNews } from "react-icons/bi"
+import { BiCalendarAlt, BiCloudDownload, BiDownload, BiExtension, BiLogOut, BiNews } from "react-icons/bi"
 import { FaBookReader } from "react-icons/fa"
 import { FiLogIn, FiSearch, FiSettings } from "react-icons/fi"
 import { HiOutlineServerStack } from "react-icons/hi2"
@@ -211,6 +211,12 @@ export function MainSidebar() {
                                 href: "/debrid",
                                 isCurrent: pathname === "/debrid",
                             }],
+                            {
+                                iconType: BiCloudDownload,
+                                name: "Downloads",
+                                href: "/downloads",
+                                isCurrent: pathname === "/downloads",
+                            },
                             {
                                 iconType: PiClockCounterClockwiseFill,
                                 name: "Scan summaries",
@@ -3,7 +3,7 @@ import { useHandleContinuityWithMediaPlayer, useHandleCurrentMediaContinuity } f
 import { useCancelDiscordActivity, useSetDiscordAnimeActivity } from "@/api/hooks/discord.hooks"
 
 import { useSeaCommandInject } from "@/app/(main)/_features/sea-command/use-inject"
-import { useSkipData } from "@/app/(main)/_features/sea-media-player/aniskip"
+import { AniSkipTime, useSkipData } from "@/app/(main)/_features/sea-media-player/aniskip"
 import { useFullscreenHandler } from "@/app/(main)/_features/sea-media-player/macos-tauri-fullscreen"
 import { SeaMediaPlayerPlaybackSubmenu } from "@/app/(main)/_features/sea-media-player/sea-media-player-components"
 import {
@@ -71,6 +71,10 @@ export type SeaMediaPlayerProps = {
     onGoToNextEpisode: () => void
     onGoToPreviousEpisode?: () => void
     mediaInfoDuration?: number
+    /** URL of the WebVTT thumbnail track used for seek previews */
+    thumbnails?: string
+    /** Skip times of the file, used instead of AniSkip when available */
+    skipData?: { op: AniSkipTime | null, ed: AniSkipTime | null }
 }
 
 type ChapterProps = {
@@ -100,6 +104,8 @@ export function SeaMediaPlayer(props: SeaMediaPlayerProps) {
         onGoToPreviousEpisode,
         settingsItems,
         mediaInfoDuration,
+        thumbnails,
+        skipData,
     } = props
 
     const serverStatus = useServerStatus()
@@ -142,7 +148,8 @@ export function SeaMediaPlayer(props: SeaMediaPlayerProps) {
     const lastFocusedElementRef = React.useRef<HTMLElement | null>(null)
 
     /** AniSkip **/
-    const { data: aniSkipData } = useSkipData(media?.idMal, progress.currentEpisodeNumber ?? -1)
+    const { data: _aniSkipData } = useSkipData(media?.idMal, progress.currentEpisodeNumber ?? -1)
+    const aniSkipData = (skipData?.op || skipData?.ed) ? skipData : _aniSkipData
 
     /** Progress update **/
     const { mutate: updateProgress, isPending: isUpdatingProgress, isSuccess: hasUpdatedProgress } = useUpdateAnimeEntryProgress(
@@ -510,6 +517,7 @@ export function SeaMediaPlayer(props: SeaMediaPlayerProps) {
                         </div>
                         <DefaultVideoLayout
                             icons={vidstackLayoutIcons}
+                            thumbnails={thumbnails}
                             slots={{
                                 ...videoLayoutSlots,
                                 settingsMenuEndItems: <>
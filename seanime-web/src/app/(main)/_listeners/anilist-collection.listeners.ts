@@ -6,6 +6,7 @@ import { useQueryClient } from "@tanstack/react-query"
 /**
  * @description
  * - Listens to REFRESHED_ANILIST_COLLECTION events and re-fetches queries associated with AniList collection.
+ * - Listens to DEBRID_CLOUD_LIBRARY_UPDATED events and re-fetches the library collection.
  */
 export function useAnimeCollectionListener() {
 
@@ -31,5 +32,15 @@ export function useAnimeCollectionListener() {
         },
     })
 
+    useWebsocketMessageListener({
+        type: WSEvents.DEBRID_CLOUD_LIBRARY_UPDATED,
+        onMessage: data => {
+            (async () => {
+                await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.ANIME_COLLECTION.GetLibraryCollection.key] })
+                await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.DEBRID.DebridGetCloudFiles.key] })
+            })()
+        },
+    })
+
 }
 
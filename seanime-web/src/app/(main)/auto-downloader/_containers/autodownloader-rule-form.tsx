@@ -6,6 +6,7 @@ import {
     Anime_LibraryCollection,
 } from "@/api/generated/types"
 import { useCreateAutoDownloaderRule, useDeleteAutoDownloaderRule, useUpdateAutoDownloaderRule } from "@/api/hooks/auto_downloader.hooks"
+import { useGetLibraryRoots } from "@/api/hooks/library_roots.hooks"
 import { useAnilistUserAnime } from "@/app/(main)/_hooks/anilist-collection-loader"
 import { useLibraryCollection } from "@/app/(main)/_hooks/anime-library-collection-loader"
 import { useServerStatus } from "@/app/(main)/_hooks/use-server-status"
@@ -45,6 +46,7 @@ const schema = defineSchema(({ z }) => z.object({
     titleComparisonType: z.string(),
     episodeType: z.string(),
     destination: z.string().min(1),
+    destinationRoot: z.string().optional().default(""),
 }))
 
 export function AutoDownloaderRuleForm(props: AutoDownloaderRuleFormProps) {
@@ -122,6 +124,7 @@ export function AutoDownloaderRuleForm(props: AutoDownloaderRuleFormProps) {
                     episodeType: rule?.episodeType ?? "recent",
                     episodeNumbers: rule?.episodeNumbers ?? [],
                     destination: rule?.destination ?? "",
+                    destinationRoot: rule?.destinationRoot ?? "",
                     additionalTerms: rule?.additionalTerms ?? [],
                 }}
                 onError={() => {
@@ -177,9 +180,11 @@ export function RuleFormFields(props: RuleFormFieldsProps) {
     } = props
 
     const serverStatus = useServerStatus()
+    const { data: libraryRoots } = useGetLibraryRoots()
 
     const form_mediaId = useWatch({ name: "mediaId" }) as number
     const form_episodeType = useWatch({ name: "episodeType" }) as Anime_AutoDownloaderRuleEpisodeType
+    const form_destinationRoot = useWatch({ name: "destinationRoot" }) as string
 
     const selectedMedia = allMedia.find(media => media.id === Number(form_mediaId))
 
@@ -258,6 +263,21 @@ export function RuleFormFields(props: RuleFormFieldsProps) {
                     shouldExist={false}
                 />
 
+                {(libraryRoots?.length ?? 0) > 1 && <Select
+                    label="Library directory"
+                    help="Relative destinations are resolved against this directory. Read-only and offline directories cannot be used."
+                    options={[
+                        { label: "None", value: "-" },
+                        ...libraryRoots.filter(status => !!status.root).map(status => ({
+                            label: status.root!.path,
+                            value: status.root!.path,
+                            disabled: status.offline || !status.root!.enabled || status.root!.readOnly,
+                        })),
+                    ]}
+                    value={form_destinationRoot || "-"}
+                    onValueChange={v => form.setValue("destinationRoot", v === "-" ? "" : v)}
+                />}
+
                 <div className="border rounded-[--radius] p-4 relative !mt-8 space-y-3">
                     <div className="absolute -top-2.5 tracking-wide font-semibold uppercase text-sm left-4 bg-gray-950 px-2">Title</div>
                     <Field.Text
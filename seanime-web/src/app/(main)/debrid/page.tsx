@@ -34,6 +34,10 @@ function getServiceName(provider: string) {
             return "Real-Debrid"
         case "torbox":
             return "TorBox"
+        case "alldebrid":
+            return "AllDebrid"
+        case "premiumize":
+            return "Premiumize"
         default:
             return provider
     }
@@ -45,6 +49,10 @@ function getDashboardLink(provider: string) {
             return "https://torbox.app/dashboard"
         case "realdebrid":
             return "https://real-debrid.com/torrents"
+        case "alldebrid":
+            return "https://alldebrid.com/magnets/"
+        case "premiumize":
+            return "https://www.premiumize.me/transfers"
         default:
             return ""
     }
@@ -0,0 +1,318 @@
+"use client"
+import { DownloadManager_Job, DownloadManager_JobSource, Models_DownloadScheduleWindow } from "@/api/generated/types"
+import {
+    useDownloadManagerJobAction,
+    useDownloadManagerSetJobPriority,
+    useDownloadManagerSetPaused,
+    useGetDownloadManagerSettings,
+    useGetDownloadManagerSnapshot,
+    useSaveDownloadManagerSettings,
+} from "@/api/hooks/download_manager.hooks"
+import { CustomLibraryBanner } from "@/app/(main)/(library)/_containers/custom-library-banner"
+import { LuffyError } from "@/components/shared/luffy-error"
+import { PageWrapper } from "@/components/shared/page-wrapper"
+import { SeaLink } from "@/components/shared/sea-link"
+import { Badge } from "@/components/ui/badge"
+import { Button, IconButton } from "@/components/ui/button"
+import { cn } from "@/components/ui/core/styling"
+import { LoadingSpinner } from "@/components/ui/loading-spinner"
+import { NativeSelect } from "@/components/ui/native-select"
+import { NumberInput } from "@/components/ui/number-input"
+import { Switch } from "@/components/ui/switch"
+import { TextInput } from "@/components/ui/text-input"
+import { Tooltip } from "@/components/ui/tooltip"
+import capitalize from "lodash/capitalize"
+import React from "react"
+import { BiDownArrow, BiPause, BiPlay, BiPlus, BiTime, BiTrash } from "react-icons/bi"
+
+export const dynamic = "force-static"
+
+const SOURCE_LABELS: Record<DownloadManager_JobSource, string> = {
+    "torrent-client": "Torrent client",
+    "debrid": "Debrid",
+    "torrentstream": "Torrent streaming",
+    "manga": "Manga",
+    "onlinestream": "Online streaming",
+}
+
+const PAUSE_REASON_LABELS: Record<string, string> = {
+    "user": "Paused",
+    "global": "All downloads paused",
+    "schedule": "Outside schedule",
+    "priority": "Waiting for higher priority",
+}
+
+const DAYS = ["Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"]
+
+function formatSpeed(bytesPerSecond: number) {
+    if (bytesPerSecond >= 1024 ** 2) return `${(bytesPerSecond / 1024 ** 2).toFixed(1)} MiB/s`
+    return `${(bytesPerSecond / 1024).toFixed(0)} KiB/s`
+}
+
+function formatEta(seconds: number) {
+    if (seconds < 0) return "-"
+    const h = Math.floor(seconds / 3600)
+    const m = Math.floor((seconds % 3600) / 60)
+    const s = seconds % 60
+    if (h > 0) return `${h}h ${m}m`
+    if (m > 0) return `${m}m ${s}s`
+    return `${s}s`
+}
+
+export default function Page() {
+    const { data: snapshot, isLoading, isError } = useGetDownloadManagerSnapshot()
+
+    const { mutate: setPaused, isPending: isSettingPaused } = useDownloadManagerSetPaused()
+
+    const jobsBySource = React.useMemo(() => {
+        const ret = new Map<DownloadManager_JobSource, DownloadManager_Job[]>()
+        for (const job of snapshot?.jobs ?? []) {
+            ret.set(job.source, [...(ret.get(job.source) ?? []), job])
+        }
+        return ret
+    }, [snapshot?.jobs])
+
+    return (
+        <>
+            <CustomLibraryBanner discrete />
+            <PageWrapper
+                className="p-4 sm:p-8 space-y-4"
+            >
+                <div className="flex justify-between items-center w-full relative">
+                    <div>
+                        <h2>Downloads</h2>
+                        <p className="text-[--muted]">
+                            {snapshot?.paused ? "All downloads are paused" : <>
+                                <BiDownArrow className="inline-block mr-1" />
+                                {formatSpeed(snapshot?.totalSpeed ?? 0)}
+                                {snapshot?.scheduleEnabled && <> &middot; {snapshot.inScheduleWindow
+                                    ? "Inside a download window"
+                                    : "Outside download windows"}</>}
+                                {!!snapshot?.rateLimit && <> &middot; Limited to {snapshot.rateLimit} KiB/s</>}
+                            </>}
+                        </p>
+                    </div>
+                    <Button
+                        intent={snapshot?.paused ? "primary" : "gray-subtle"}
+                        leftIcon={snapshot?.paused ? <BiPlay /> : <BiPause />}
+                        loading={isSettingPaused}
+                        disabled={!snapshot}
+                        onClick={() => setPaused({ paused: !snapshot?.paused })}
+                    >
+                        {snapshot?.paused ? "Resume all" : "Pause all"}
+                    </Button>
+                </div>
+
+                {isLoading && <LoadingSpinner />}
+                {isError && <LuffyError title="Failed to load downloads" />}
+
+                {!isLoading && !!snapshot && !snapshot.jobs?.length && (
+                    <p className="text-center text-[--muted] py-4">No downloads</p>
+                )}
+
+                {Array.from(jobsBySource.entries()).map(([source, jobs]) => (
+                    <div key={source} className="space-y-2">
+                        <h4>{SOURCE_LABELS[source] ?? source}</h4>
+                        {jobs.map(job => <JobItem key={job.id} job={job} />)}
+                    </div>
+                ))}
+
+                <ScheduleSettings />
+            </PageWrapper>
+        </>
+    )
+}
+
+function JobItem({ job }: { job: DownloadManager_Job }) {
+
+    const { mutate: jobAction, isPending: isActionPending } = useDownloadManagerJobAction()
+    const { mutate: setPriority, isPending: isPriorityPending } = useDownloadManagerSetJobPriority()
+
+    const canPause = job.state === "downloading" || job.state === "queued"
+    const canResume = job.state === "paused"
+    const isFinished = job.state === "completed" || job.state === "failed"
+
+    return (
+        <div className="p-4 border rounded-[--radius-md] overflow-hidden relative flex gap-2">
+            <div className="absolute top-0 w-full h-1 z-[1] bg-gray-700 left-0">
+                <div
+                    className={cn(
+                        "h-1 absolute z-[2] left-0 bg-gray-200 transition-all",
+                        {
+                            "bg-green-300": job.state === "downloading",
+                            "bg-gray-500": job.state === "paused" || job.state === "queued",
+                            "bg-blue-500": job.state === "completed",
+                            "bg-red-500": job.state === "failed",
+                        },
+                    )}
+                    style={{ width: `${String(Math.floor(job.progress * 100))}%` }}
+                ></div>
+            </div>
+            <div className="w-full min-w-0">
+                <div className={cn("line-clamp-1", { "opacity-50": job.state === "paused" })}>
+                    {!!job.mediaId ? <SeaLink href={`/entry?id=${job.mediaId}`}>{job.name}</SeaLink> : job.name}
+                    {!!job.episode && <span className="text-[--muted]"> &middot; {job.episode}</span>}
+                </div>
+                <div className="text-[--muted] text-sm">
+                    <span className={cn({ "text-green-300": job.state === "downloading" })}>{`${(job.progress * 100).toFixed(1)}%`}</span>
+                    {job.state === "downloading" && <>
+                        <BiDownArrow className="inline-block mx-2" />
+                        {formatSpeed(job.speed)}
+                        <BiTime className="inline-block mx-2 mb-0.5" />
+                        {formatEta(job.eta)}
+                    </>}
+                    {` - `}
+                    <strong className={cn({ "text-red-300": job.state === "failed" })}>{capitalize(job.state)}</strong>
+                    {!!job.pausedBy && job.state === "paused" && <Badge className="ml-2" size="sm">{PAUSE_REASON_LABELS[job.pausedBy]}</Badge>}
+                    {!!job.error && <p className="text-red-300">{job.error}</p>}
+                </div>
+            </div>
+            {!isFinished && <div className="flex-none flex gap-2 items-center">
+                <NativeSelect
+                    size="sm"
+                    fieldClass="w-28"
+                    value={job.priority}
+                    disabled={isPriorityPending}
+                    options={[
+                        { value: 1, label: "High" },
+                        { value: 0, label: "Normal" },
+                        { value: -1, label: "Low" },
+                    ]}
+                    onChange={e => setPriority({ id: job.id, priority: Number(e.target.value) as DownloadManager_Job["priority"] })}
+                />
+                {canPause && <Tooltip
+                    trigger={<IconButton
+                        icon={<BiPause />}
+                        size="sm"
+                        intent="gray-subtle"
+                        className="flex-none"
+                        onClick={() => jobAction({ id: job.id, action: "pause" })}
+                        disabled={isActionPending}
+                    />}
+                >Pause</Tooltip>}
+                {canResume && <Tooltip
+                    trigger={<IconButton
+                        icon={<BiPlay />}
+                        size="sm"
+                        intent="gray-subtle"
+                        className="flex-none"
+                        onClick={() => jobAction({ id: job.id, action: "resume" })}
+                        disabled={isActionPending}
+                    />}
+                >Resume</Tooltip>}
+            </div>}
+        </div>
+    )
+}
+
+function ScheduleSettings() {
+
+    const { data: settings } = useGetDownloadManagerSettings()
+    const { mutate: saveSettings, isPending } = useSaveDownloadManagerSettings()
+
+    const [scheduleEnabled, setScheduleEnabled] = React.useState(false)
+    const [windows, setWindows] = React.useState<Models_DownloadScheduleWindow[]>([])
+
+    React.useEffect(() => {
+        if (!settings) return
+        setScheduleEnabled(settings.scheduleEnabled)
+        setWindows(settings.scheduleWindows ?? [])
+    }, [settings])
+
+    function updateWindow(index: number, update: Partial<Models_DownloadScheduleWindow>) {
+        setWindows(prev => prev.map((w, i) => i === index ? { ...w, ...update } : w))
+    }
+
+    function toggleDay(index: number, day: number) {
+        const days = windows[index].days ?? []
+        updateWindow(index, { days: days.includes(day) ? days.filter(d => d !== day) : [...days, day].sort() })
+    }
+
+    if (!settings) return null
+
+    return (
+        <div className="space-y-4 border rounded-[--radius-md] p-4">
+            <div className="flex justify-between items-center">
+                <div>
+                    <h4>Schedule</h4>
+                    <p className="text-[--muted] text-sm">
+                        Downloads only run during these windows, unless their priority is high. No days selected means every day.
+                    </p>
+                </div>
+                <Switch
+                    value={scheduleEnabled}
+                    onValueChange={setScheduleEnabled}
+                    fieldClass="w-fit"
+                />
+            </div>
+
+            {windows.map((w, i) => (
+                <div key={i} className="flex flex-wrap items-end gap-2">
+                    <TextInput
+                        label="Start"
+                        type="time"
+                        value={w.start}
+                        onValueChange={v => updateWindow(i, { start: v })}
+                        fieldClass="w-32"
+                    />
+                    <TextInput
+                        label="End"
+                        type="time"
+                        value={w.end}
+                        onValueChange={v => updateWindow(i, { end: v })}
+                        fieldClass="w-32"
+                    />
+                    <NumberInput
+                        label="Rate limit (KiB/s, 0 = none)"
+                        value={w.rateLimit}
+                        min={0}
+                        onValueChange={v => updateWindow(i, { rateLimit: v || 0 })}
+                        fieldClass="w-52"
+                    />
+                    <div className="flex gap-1 pb-1">
+                        {DAYS.map((day, d) => (
+                            <Button
+                                key={day}
+                                size="sm"
+                                intent={w.days?.includes(d) ? "primary" : "gray-outline"}
+                                onClick={() => toggleDay(i, d)}
+                            >
+                                {day}
+                            </Button>
+                        ))}
+                    </div>
+                    <IconButton
+                        icon={<BiTrash />}
+                        size="sm"
+                        intent="alert-subtle"
+                        className="mb-1"
+                        onClick={() => setWindows(prev => prev.filter((_, j) => j !== i))}
+                    />
+                </div>
+            ))}
+
+            <div className="flex gap-2">
+                <Button
+                    intent="gray-subtle"
+                    leftIcon={<BiPlus />}
+                    onClick={() => setWindows(prev => [...prev, { start: "01:00", end: "07:00", days: [], rateLimit: 0 }])}
+                >
+                    Add window
+                </Button>
+                <Button
+                    intent="white"
+                    loading={isPending}
+                    onClick={() => saveSettings({
+                        settings: {
+                            ...settings,
+                            scheduleEnabled,
+                            scheduleWindows: windows,
+                        },
+                    })}
+                >
+                    Save
+                </Button>
+            </div>
+        </div>
+    )
+}
@@ -87,12 +87,14 @@ export function AnimeEntryPage() {
                 // If any of the fallbacks are enabled and the view has not been switched yet
                 (serverStatus?.torrentstreamSettings?.enabled && serverStatus?.torrentstreamSettings?.includeInLibrary) ||
                 (serverStatus?.debridSettings?.enabled && serverStatus?.debridSettings?.includeDebridStreamInLibrary) ||
+                (serverStatus?.debridSettings?.enabled && serverStatus?.debridSettings?.includeCloudTorrentsInLibrary) ||
                 (serverStatus?.settings?.library?.enableOnlinestream && serverStatus?.settings?.library?.includeOnlineStreamingInLibrary)
             ) &&
             !switchedView.current // View has not been switched yet
         ) {
             switchedView.current = true
-            if (serverStatus?.debridSettings?.enabled && serverStatus?.debridSettings?.includeDebridStreamInLibrary) {
+            if (serverStatus?.debridSettings?.enabled &&
+                (serverStatus?.debridSettings?.includeDebridStreamInLibrary || serverStatus?.debridSettings?.includeCloudTorrentsInLibrary)) {
                 setView("debridstream")
             } else if (serverStatus?.torrentstreamSettings?.enabled && serverStatus?.torrentstreamSettings?.includeInLibrary) {
                 setView("torrentstream")
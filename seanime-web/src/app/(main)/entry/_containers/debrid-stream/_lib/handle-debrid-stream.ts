@@ -1,5 +1,5 @@
 import { Anime_Entry, HibikeTorrent_AnimeTorrent, Torrentstream_PlaybackType } from "@/api/generated/types"
-import { useDebridStartStream } from "@/api/hooks/debrid.hooks"
+import { useDebridStartCloudStream, useDebridStartStream } from "@/api/hooks/debrid.hooks"
 import { PlaybackTorrentStreaming, useCurrentDevicePlaybackSettings, useExternalPlayerLink } from "@/app/(main)/_atoms/playback.atoms"
 import { __debridstream_stateAtom } from "@/app/(main)/entry/_containers/debrid-stream/debrid-stream-overlay"
 import { clientIdAtom } from "@/app/websocket-provider"
@@ -19,10 +19,16 @@ type DebridStreamAutoSelectProps = {
     episodeNumber: number
     aniDBEpisode: string
 }
+type DebridCloudStreamProps = {
+    entry: Anime_Entry
+    episodeNumber: number
+    aniDBEpisode: string
+}
 
 export function useHandleStartDebridStream() {
 
     const { mutate, isPending } = useDebridStartStream()
+    const { mutate: startCloudStream, isPending: isStartingCloudStream } = useDebridStartCloudStream()
 
     const { torrentStreamingPlayback } = useCurrentDevicePlaybackSettings()
     const { externalPlayerLink } = useExternalPlayerLink()
@@ -78,9 +84,25 @@ export function useHandleStartDebridStream() {
         })
     }, [playbackType, clientId])
 
+    // Plays an episode that is already available on the Debrid service
+    const handleCloudStream = React.useCallback((params: DebridCloudStreamProps) => {
+        startCloudStream({
+            mediaId: params.entry.mediaId,
+            episodeNumber: params.episodeNumber,
+            aniDBEpisode: params.aniDBEpisode,
+            playbackType: playbackType,
+            clientId: clientId || "",
+        }, {
+            onError: () => {
+                setState(null)
+            },
+        })
+    }, [playbackType, clientId])
+
     return {
         handleStreamSelection,
         handleAutoSelectStream,
-        isPending,
+        handleCloudStream,
+        isPending: isPending || isStartingCloudStream,
     }
 }
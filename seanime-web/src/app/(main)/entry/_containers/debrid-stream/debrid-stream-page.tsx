@@ -1,4 +1,5 @@
 import { Anime_Entry, Anime_Episode } from "@/api/generated/types"
+import { useDebridGetCloudFiles } from "@/api/hooks/debrid.hooks"
 import { useGetTorrentstreamEpisodeCollection } from "@/api/hooks/torrentstream.hooks"
 import { useServerStatus } from "@/app/(main)/_hooks/use-server-status"
 import { useHandleStartDebridStream } from "@/app/(main)/entry/_containers/debrid-stream/_lib/handle-debrid-stream"
@@ -66,7 +67,7 @@ export function DebridStreamPage(props: DebridStreamPageProps) {
 
     // Function to handle playing the next episode on mount
     function handlePlayNextEpisodeOnMount(episode: Anime_Episode) {
-        if (autoSelect) {
+        if (autoSelect && !cloudEpisodes.has(episode.aniDBEpisode || "")) {
             handleAutoSelect(entry, episode)
         } else {
             handleEpisodeClick(episode)
@@ -74,7 +75,11 @@ export function DebridStreamPage(props: DebridStreamPageProps) {
     }
 
     // Hook to handle starting the debrid stream
-    const { handleAutoSelectStream } = useHandleStartDebridStream()
+    const { handleAutoSelectStream, handleCloudStream } = useHandleStartDebridStream()
+
+    // Episodes already available on the Debrid service are played directly
+    const { data: cloudFiles } = useDebridGetCloudFiles(entry.mediaId, !!serverStatus?.debridSettings?.includeCloudTorrentsInLibrary)
+    const cloudEpisodes = React.useMemo(() => new Set(cloudFiles?.map(f => f.metadata?.aniDBEpisode || "").filter(Boolean)), [cloudFiles])
 
     // Hook to manage debrid stream autoplay information
     const { setDebridstreamAutoplayInfo } = useDebridStreamAutoplay()
@@ -120,6 +125,16 @@ export function DebridStreamPage(props: DebridStreamPageProps) {
 
         setTorrentStreamingSelectedEpisode(episode)
 
+        if (cloudEpisodes.has(episode.aniDBEpisode)) {
+            handleCloudStream({
+                entry: entry,
+                episodeNumber: episode.episodeNumber,
+                aniDBEpisode: episode.aniDBEpisode,
+            })
+            handleSetDebridstreamAutoplayInfo(episode)
+            return
+        }
+
         if (autoSelect) {
             handleAutoSelect(entry, episode)
         } else {
@@ -175,6 +190,13 @@ export function DebridStreamPage(props: DebridStreamPageProps) {
                     )}
                 </div>
 
+                {!!cloudEpisodes.size && (
+                    <p className="text-[--muted]">
+                        {cloudEpisodes.size} episode{cloudEpisodes.size > 1 ? "s are" : " is"} available on your Debrid service and will play without
+                        selecting a torrent.
+                    </p>
+                )}
+
                 {episodeCollection?.hasMappingError && (
                     <div className="">
                         <p className="text-red-200 opacity-50">
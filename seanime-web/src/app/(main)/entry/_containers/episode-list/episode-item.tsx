@@ -3,6 +3,7 @@ import { AL_BaseAnime, Anime_Episode, Anime_LocalFileType } from "@/api/generate
 import { useUpdateLocalFileData } from "@/api/hooks/localfiles.hooks"
 import { EpisodeGridItem } from "@/app/(main)/_features/anime/_components/episode-grid-item"
 import { IconButton } from "@/components/ui/button"
+import { cn } from "@/components/ui/core/styling"
 import { DropdownMenu, DropdownMenuItem, DropdownMenuSeparator } from "@/components/ui/dropdown-menu"
 import { defineSchema, Field, Form } from "@/components/ui/form"
 import { Modal } from "@/components/ui/modal"
@@ -42,7 +43,15 @@ export const EpisodeItem = memo(({ episode, media, isWatched, onPlay, percentage
             <EpisodeGridItem
                 media={media}
                 image={episode.episodeMetadata?.image}
-                onClick={() => onPlay?.({ path: episode.localFile?.path ?? "", mediaId: media.id })}
+                onClick={() => {
+                    // The drive of the file is unplugged
+                    if (episode.localFile?.offline) {
+                        toast.warning("The directory of this file is offline")
+                        return
+                    }
+                    onPlay?.({ path: episode.localFile?.path ?? "", mediaId: media.id })
+                }}
+                className={cn(episode.localFile?.offline && "opacity-50 grayscale")}
                 isInvalid={episode.isInvalid}
                 title={episode.displayTitle}
                 episodeTitle={episode.episodeTitle}
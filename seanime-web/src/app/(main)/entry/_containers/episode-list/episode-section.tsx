@@ -36,6 +36,7 @@ export function EpisodeSection({ entry, details, bottomSection }: EpisodeSection
         mainEpisodes,
         specialEpisodes,
         ncEpisodes,
+        episodeSections,
         playMediaFile,
     } = useHandleEpisodeSection({ entry })
 
@@ -173,33 +174,21 @@ export function EpisodeSection({ entry, details, bottomSection }: EpisodeSection
                         media={media}
                     />}
 
-                    {specialEpisodes.length > 0 && <>
-                        <h2>Specials</h2>
-                        <EpisodeListGrid>
-                            {specialEpisodes.map(episode => (
-                                <EpisodeItem
-                                    key={episode.localFile?.path || ""}
-                                    episode={episode}
-                                    media={media}
-                                    onPlay={playMediaFile}
-                                />
-                            ))}
-                        </EpisodeListGrid>
-                    </>}
-
-                    {ncEpisodes.length > 0 && <>
-                        <h2>Others</h2>
-                        <EpisodeListGrid>
-                            {ncEpisodes.map(episode => (
-                                <EpisodeItem
-                                    key={episode.localFile?.path || ""}
-                                    episode={episode}
-                                    media={media}
-                                    onPlay={playMediaFile}
-                                />
-                            ))}
-                        </EpisodeListGrid>
-                    </>}
+                    {episodeSections.map(section => (
+                        <React.Fragment key={section.type}>
+                            <h2>{section.title}</h2>
+                            <EpisodeListGrid>
+                                {section.episodes.map(episode => (
+                                    <EpisodeItem
+                                        key={episode.localFile?.path || ""}
+                                        episode={episode}
+                                        media={media}
+                                        onPlay={playMediaFile}
+                                    />
+                                ))}
+                            </EpisodeListGrid>
+                        </React.Fragment>
+                    ))}
 
                     {bottomSection}
 
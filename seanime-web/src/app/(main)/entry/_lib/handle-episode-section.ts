@@ -21,16 +21,21 @@ export function useHandleEpisodeSection(props: { entry: Anime_Entry }) {
         return entry.episodes?.filter(ep => ep.type === "main") ?? []
     }, [entry.episodes])
 
+    // Specials and NC episodes, in ordered sections
+    const episodeSections = React.useMemo(() => {
+        return (entry.episodeSections ?? []).map(section => ({
+            ...section,
+            episodes: section.episodes?.filter(ep => !!ep.localFile?.path) ?? [],
+        })).filter(section => section.episodes.length > 0)
+    }, [entry.episodeSections])
+
     const specialEpisodes = React.useMemo(() => {
-        return (entry.episodes?.filter(ep => ep.type === "special") ?? [])
-            .sort((a, b) => a.displayTitle.localeCompare(b.displayTitle, undefined, { numeric: true }))
-    }, [entry.episodes])
+        return episodeSections.filter(section => section.type === "S").flatMap(section => section.episodes)
+    }, [episodeSections])
 
     const ncEpisodes = React.useMemo(() => {
-        return (entry.episodes?.filter(ep => ep.type === "nc" && !!ep.localFile?.path) ?? []).sort((a,
-            b,
-        ) => a.localFile!.path!.localeCompare(b.localFile!.path!, undefined, { numeric: true }))
-    }, [entry.episodes])
+        return episodeSections.filter(section => section.type !== "S").flatMap(section => section.episodes)
+    }, [episodeSections])
 
     const hasInvalidEpisodes = React.useMemo(() => {
         return entry.episodes?.some(ep => ep.isInvalid) ?? false
@@ -54,6 +59,7 @@ export function useHandleEpisodeSection(props: { entry: Anime_Entry }) {
         mainEpisodes,
         specialEpisodes,
         ncEpisodes,
+        episodeSections,
         hasInvalidEpisodes,
         episodesToWatch,
     }
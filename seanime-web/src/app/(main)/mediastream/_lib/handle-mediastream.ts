@@ -1,7 +1,13 @@
 import { getServerBaseUrl } from "@/api/client/server-url"
-import { Anime_Episode, Mediastream_StreamType, Nullish } from "@/api/generated/types"
+import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { Anime_Episode, Mediastream_StreamType, Nullish, Trickplay_Info } from "@/api/generated/types"
 import { useHandleContinuityWithMediaPlayer, useHandleCurrentMediaContinuity } from "@/api/hooks/continuity.hooks"
-import { useGetMediastreamSettings, useMediastreamShutdownTranscodeStream, useRequestMediastreamMediaContainer } from "@/api/hooks/mediastream.hooks"
+import {
+    useGetMediastreamSettings,
+    useMediastreamShutdownTranscodeStream,
+    useRequestMediastreamMediaContainer,
+    useRequestMediastreamTrickplay,
+} from "@/api/hooks/mediastream.hooks"
 import { useIsCodecSupported } from "@/app/(main)/_features/sea-media-player/hooks"
 import { useWebsocketMessageListener } from "@/app/(main)/_hooks/handle-websockets"
 import { useMediastreamCurrentFile, useMediastreamJassubOffscreenRender } from "@/app/(main)/mediastream/_lib/mediastream.atoms"
@@ -19,6 +25,7 @@ import {
     MediaProviderSetupEvent,
 } from "@vidstack/react"
 import HLS, { LoadPolicy } from "hls.js"
+import { useQueryClient } from "@tanstack/react-query"
 import { useAtomValue } from "jotai"
 import { useRouter } from "next/navigation"
 import React from "react"
@@ -486,6 +493,29 @@ export function useHandleMediastream(props: HandleMediastreamProps) {
         return ""
     }, [mediaContainer?.streamUrl, mediaContainer?.streamType])
 
+    //////////////////////////////////////////////////////////////
+    // Seek previews
+    //////////////////////////////////////////////////////////////
+
+    const qc = useQueryClient()
+    const { data: trickplay } = useRequestMediastreamTrickplay(mediaContainer?.filePath, !!mediastreamSettings?.trickplayEnabled)
+
+    /**
+     * Listen for trickplay updates
+     * - Sent when the thumbnails of a file start generating, are ready or failed
+     */
+    useWebsocketMessageListener<Trickplay_Info>({
+        type: WSEvents.MEDIASTREAM_TRICKPLAY_UPDATED,
+        onMessage: data => {
+            qc.setQueryData([API_ENDPOINTS.MEDIASTREAM.RequestMediastreamTrickplay.key, data.path], data)
+        },
+    })
+
+    const thumbnailsUri = React.useMemo(() => {
+        if (trickplay?.status !== "ready") return undefined
+        return `${getServerBaseUrl()}/api/v1/mediastream/trickplay/${trickplay.hash}/thumbnails.vtt`
+    }, [trickplay?.status, trickplay?.hash])
+
     return {
         url,
         streamType,
@@ -493,6 +523,7 @@ export function useHandleMediastream(props: HandleMediastreamProps) {
         isMediaContainerLoading: isPending,
         isError: isMediaContainerError || isStreamError,
         subtitleEndpointUri,
+        thumbnailsUri,
         mediaContainer: _mediaContainer,
         onPlayFile,
         filePath,
@@ -1,5 +1,6 @@
 "use client"
 import { useGetAnimeEntry } from "@/api/hooks/anime_entries.hooks"
+import { useLocalFileSkipData } from "@/api/hooks/skip_segments.hooks"
 import { EpisodeGridItem } from "@/app/(main)/_features/anime/_components/episode-grid-item"
 import { MediaEntryPageSmallBanner } from "@/app/(main)/_features/media/_components/media-entry-page-small-banner"
 import { MediaEpisodeInfoModal } from "@/app/(main)/_features/media/_components/media-episode-info-modal"
@@ -59,6 +60,7 @@ export default function Page() {
         mediaContainer,
         subtitles,
         subtitleEndpointUri,
+        thumbnailsUri,
         onProviderChange,
         onProviderSetup,
         onCanPlay,
@@ -74,6 +76,8 @@ export default function Page() {
 
     const { jassubOffscreenRender, setJassubOffscreenRender } = useMediastreamJassubOffscreenRender()
 
+    const skipData = useLocalFileSkipData(mediaContainer?.filePath, mediaContainer?.mediaInfo?.duration)
+
     /**
      * The episode number of the current file
      */
@@ -245,9 +249,13 @@ export default function Page() {
                                 lang: sub.language,
                                 type: (sub.extension?.replace(".", "") || "ass") as CaptionsFileFormat,
                                 kind: "subtitles",
-                                default: sub.isDefault || (!subtitles.some(n => n.isDefault) && sub.language?.startsWith("en")),
+                                default: mediaContainer?.trackSelection && (mediaContainer.trackSelection.subtitleIndex >= 0 || mediaContainer.trackSelection.noSubtitles)
+                                    ? mediaContainer.trackSelection.subtitleIndex === sub.index
+                                    : sub.isDefault || (!subtitles.some(n => n.isDefault) && sub.language?.startsWith("en")),
                             }))}
                             mediaInfoDuration={mediaContainer?.mediaInfo?.duration}
+                            skipData={skipData}
+                            thumbnails={thumbnailsUri}
                             loadingText={<>
                                 <p>Extracting video metadata...</p>
                                 <p>This might take a while.</p>
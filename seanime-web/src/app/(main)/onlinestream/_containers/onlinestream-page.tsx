@@ -230,8 +230,8 @@ export function OnlinestreamPage({ animeEntry, animeEntryLoading, hideBackButton
                                 kind: "subtitles",
                                 src: sub.url,
                                 language: sub.language,
-                                default: sub.language
-                                    ? sub.language?.toLowerCase() === "english" || sub.language?.toLowerCase() === "en-us"
+                                default: episodeSource?.subtitles?.some(n => n.default)
+                                    ? sub.default
                                     : sub.language?.toLowerCase() === "english" || sub.language?.toLowerCase() === "en-us",
                             }))}
                             settingsItems={<>
@@ -0,0 +1,147 @@
+import { Anime_LocalFile, Summary_ScanDiffChange, Summary_ScanSummary } from "@/api/generated/types"
+import { useApplyScanDiff, useDiscardScanDiff } from "@/api/hooks/scan_summary.hooks"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { Checkbox } from "@/components/ui/checkbox"
+import React from "react"
+
+const CHANGE_TYPES: { type: string, label: string }[] = [
+    { type: "matched", label: "Newly matched" },
+    { type: "rematched", label: "Re-matched to a different media" },
+    { type: "episode-changed", label: "Episode changed" },
+    { type: "unmatched", label: "Unmatched" },
+    { type: "removed", label: "Removed" },
+    { type: "updated", label: "Ignored state, offline state, hashes or media info changed" },
+]
+
+type ScanDiffReviewProps = {
+    summary: Summary_ScanSummary
+}
+
+export function ScanDiffReview(props: ScanDiffReviewProps) {
+
+    const { summary } = props
+
+    const diff = summary.diff
+    const isPending = diff?.status === "pending"
+
+    const { mutate: applyDiff, isPending: isApplying } = useApplyScanDiff()
+    const { mutate: discardDiff, isPending: isDiscarding } = useDiscardScanDiff()
+
+    const [selectedIds, setSelectedIds] = React.useState<string[]>([])
+
+    React.useEffect(() => {
+        setSelectedIds(diff?.changes?.map(change => change.id) ?? [])
+    }, [summary.id])
+
+    // Media titles known by the summary
+    const mediaTitles = React.useMemo(() => {
+        return new Map(summary.groups?.map(group => [group.mediaId, group.mediaTitle]) ?? [])
+    }, [summary.groups])
+
+    if (!diff || (!diff.dryRun && !diff.changes?.length)) return null
+
+    function toggleChange(id: string, checked: boolean) {
+        setSelectedIds(prev => checked ? [...prev, id] : prev.filter(n => n !== id))
+    }
+
+    return (
+        <div className="space-y-4">
+            <div className="flex flex-wrap items-center justify-between gap-2">
+                <div>
+                    <h5 className="flex items-center gap-2">
+                        Changes
+                        {diff.dryRun && <Badge intent="gray">Dry run</Badge>}
+                        {diff.status === "pending" && <Badge intent="warning">Pending</Badge>}
+                        {diff.status === "discarded" && <Badge intent="alert">Discarded</Badge>}
+                    </h5>
+                    <p className="text-sm text-[--muted]">
+                        {isPending
+                            ? "Select the changes to save, the other files are left as they are."
+                            : `${diff.changes?.filter(n => n.accepted).length ?? 0} of ${diff.changes?.length ?? 0} changes were saved`}
+                    </p>
+                </div>
+                {isPending && <div className="flex gap-2">
+                    <Button
+                        intent="gray-subtle"
+                        loading={isDiscarding}
+                        disabled={isApplying}
+                        onClick={() => discardDiff({ summaryId: summary.id })}
+                    >
+                        Discard
+                    </Button>
+                    <Button
+                        intent="white-subtle"
+                        loading={isApplying}
+                        disabled={isDiscarding || !selectedIds.length}
+                        onClick={() => applyDiff({ summaryId: summary.id, changeIds: selectedIds, acceptAll: false })}
+                    >
+                        Save selected ({selectedIds.length})
+                    </Button>
+                    <Button
+                        intent="primary"
+                        loading={isApplying}
+                        disabled={isDiscarding}
+                        onClick={() => applyDiff({ summaryId: summary.id, changeIds: [], acceptAll: true })}
+                    >
+                        Save all
+                    </Button>
+                </div>}
+            </div>
+
+            {!diff.changes?.length && <p className="text-[--muted]">No changes</p>}
+
+            {CHANGE_TYPES.map(({ type, label }) => {
+                const changes = diff.changes?.filter(change => change.type === type) ?? []
+                if (!changes.length) return null
+                return (
+                    <div key={type} className="space-y-2">
+                        <p className="font-semibold">{label} <span className="text-[--muted]">({changes.length})</span></p>
+                        <div className="bg-gray-950 rounded-[--radius] divide-y divide-[--border]">
+                            {changes.map(change => (
+                                <ScanDiffChangeItem
+                                    key={change.id}
+                                    change={change}
+                                    mediaTitles={mediaTitles}
+                                    selectable={isPending}
+                                    selected={selectedIds.includes(change.id)}
+                                    onSelect={v => toggleChange(change.id, v)}
+                                />
+                            ))}
+                        </div>
+                    </div>
+                )
+            })}
+        </div>
+    )
+}
+
+function ScanDiffChangeItem(props: {
+    change: Summary_ScanDiffChange,
+    mediaTitles: Map<number, string>,
+    selectable: boolean,
+    selected: boolean,
+    onSelect: (v: boolean) => void
+}) {
+    const { change, mediaTitles, selectable, selected, onSelect } = props
+
+    function describe(lf: Anime_LocalFile | undefined) {
+        if (!lf) return "None"
+        if (!lf.mediaId) return "Unmatched"
+        const title = mediaTitles.get(lf.mediaId) ?? `Media ${lf.mediaId}`
+        return lf.metadata?.episode ? `${title}, episode ${lf.metadata.aniDBEpisode || lf.metadata.episode}` : title
+    }
+
+    return (
+        <div className="flex items-center gap-3 px-3 py-2 text-sm">
+            {selectable ? <Checkbox value={selected} onValueChange={v => onSelect(v === true)} fieldClass="w-fit" /> :
+                <Badge intent={change.accepted ? "success" : "gray"}>{change.accepted ? "Saved" : "Rejected"}</Badge>}
+            <div className="min-w-0 space-y-0.5">
+                <p className="line-clamp-1 break-all">{change.current?.name ?? change.previous?.name ?? change.path}</p>
+                <p className="text-[--muted]">
+                    {describe(change.previous)} &rarr; {describe(change.current)}
+                </p>
+            </div>
+        </div>
+    )
+}
@@ -2,6 +2,7 @@
 import { Anime_LocalFile, Summary_ScanSummaryFile, Summary_ScanSummaryLog } from "@/api/generated/types"
 import { useGetScanSummaries } from "@/api/hooks/scan_summary.hooks"
 import { CustomLibraryBanner } from "@/app/(main)/(library)/_containers/custom-library-banner"
+import { ScanDiffReview } from "@/app/(main)/scan-summaries/_components/scan-diff-review"
 import { PageWrapper } from "@/components/shared/page-wrapper"
 import { SeaLink } from "@/components/shared/sea-link"
 import { Accordion, AccordionContent, AccordionItem, AccordionTrigger } from "@/components/ui/accordion"
@@ -10,6 +11,7 @@ import { LoadingSpinner } from "@/components/ui/loading-spinner"
 import { Select } from "@/components/ui/select"
 import { formatDateAndTimeShort } from "@/lib/server/utils"
 import Image from "next/image"
+import { useSearchParams } from "next/navigation"
 import React from "react"
 import { AiFillWarning } from "react-icons/ai"
 import { BiCheckCircle, BiInfoCircle, BiXCircle } from "react-icons/bi"
@@ -25,11 +27,18 @@ export default function Page() {
 
     const { data, isLoading } = useGetScanSummaries()
 
+    const searchParams = useSearchParams()
+    const summaryIdParam = searchParams.get("id")
+
     React.useEffect(() => {
         if (!!data?.length) {
-            setSelectedSummaryId(data[data.length - 1]?.scanSummary?.id)
+            setSelectedSummaryId(prev => {
+                if (prev && data.some(n => n.scanSummary?.id === prev)) return prev
+                if (summaryIdParam && data.some(n => n.scanSummary?.id === summaryIdParam)) return summaryIdParam
+                return data[data.length - 1]?.scanSummary?.id
+            })
         }
-    }, [data])
+    }, [data, summaryIdParam])
 
     const selectedSummary = React.useMemo(() => {
         const summary = data?.find(summary => summary.scanSummary?.id === selectedSummaryId)
@@ -63,7 +72,10 @@ export default function Page() {
                             <Select
                                 value={selectedSummaryId || "-"}
                                 options={data?.filter(n => !!n.scanSummary)
-                                    .map((summary) => ({ label: formatDateAndTimeShort(summary.createdAt!), value: summary.scanSummary!.id || "-" }))
+                                    .map((summary) => ({
+                                        label: formatDateAndTimeShort(summary.createdAt!) + (summary.scanSummary?.diff?.status === "pending" ? " (pending)" : ""),
+                                        value: summary.scanSummary!.id || "-",
+                                    }))
                                     .toReversed()}
                                 onValueChange={v => setSelectedSummaryId(v)}
                             />
@@ -76,8 +88,15 @@ export default function Page() {
                                                 ? "s were "
                                                 : " was "}not matched</p>
                                         )}
+                                        {!!selectedSummary?.ignoredFiles?.length && (
+                                            <p className="text-[--muted]">{selectedSummary?.ignoredFiles?.length} file{selectedSummary?.ignoredFiles?.length > 1
+                                                ? "s were "
+                                                : " was "}ignored</p>
+                                        )}
                                     </div>
 
+                                    <ScanDiffReview summary={selectedSummary} />
+
                                     {!!selectedSummary?.unmatchedFiles?.length && <div className="space-y-2">
                                         <h5>Unmatched files</h5>
                                         <Accordion type="single" collapsible>
@@ -89,6 +108,18 @@ export default function Page() {
                                         </Accordion>
                                     </div>}
 
+                                    {!!selectedSummary?.ignoredFiles?.length && <div className="space-y-2">
+                                        <h5>Ignored files</h5>
+                                        <p className="text-sm text-[--muted]">These files are excluded by a .seaignore file</p>
+                                        <Accordion type="single" collapsible>
+                                            <div className="grid grid-cols-1 gap-4">
+                                                {selectedSummary?.ignoredFiles?.map(file => (
+                                                    <ScanSummaryGroupItem file={file} key={file.id} />
+                                                ))}
+                                            </div>
+                                        </Accordion>
+                                    </div>}
+
                                     {!!selectedSummary?.groups?.length && <div>
                                         <h5>Media scanned</h5>
 
@@ -0,0 +1,174 @@
+"use client"
+import { SeaDex_LibraryAnalysisEntry, SeaDex_Torrent } from "@/api/generated/types"
+import { useGetSeaDexLibraryAnalysis, useSeaDexEnqueueReleases } from "@/api/hooks/seadex.hooks"
+import { CustomLibraryBanner } from "@/app/(main)/(library)/_containers/custom-library-banner"
+import { useServerStatus } from "@/app/(main)/_hooks/use-server-status"
+import { PageWrapper } from "@/components/shared/page-wrapper"
+import { SeaLink } from "@/components/shared/sea-link"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { Checkbox } from "@/components/ui/checkbox"
+import { LoadingSpinner } from "@/components/ui/loading-spinner"
+import { Switch } from "@/components/ui/switch"
+import React from "react"
+import { BiCloud, BiDownload, BiRefresh } from "react-icons/bi"
+
+export const dynamic = "force-static"
+
+function formatSize(size: number) {
+    const abs = Math.abs(size)
+    if (abs >= 1024 ** 3) return `${(size / 1024 ** 3).toFixed(2)} GiB`
+    if (abs >= 1024 ** 2) return `${(size / 1024 ** 2).toFixed(1)} MiB`
+    return `${(size / 1024).toFixed(0)} KiB`
+}
+
+function formatRelease(torrent: SeaDex_Torrent | undefined) {
+    if (!torrent) return "-"
+    return `${torrent.releaseGroup || "Unknown"}${torrent.dualAudio ? " (Dual-Audio)" : ""}`
+}
+
+export default function Page() {
+    const serverStatus = useServerStatus()
+
+    const [preferDualAudio, setPreferDualAudio] = React.useState(false)
+    const [selectedMediaIds, setSelectedMediaIds] = React.useState<number[]>([])
+
+    const { mutate: analyze, data: analysis, isPending: isAnalyzing } = useGetSeaDexLibraryAnalysis()
+    const { mutate: enqueue, isPending: isEnqueuing } = useSeaDexEnqueueReleases()
+
+    const upgradableEntries = React.useMemo(() => {
+        return analysis?.entries?.filter(e => e.status !== "best" && !!e.recommended) ?? []
+    }, [analysis])
+
+    React.useEffect(() => {
+        setSelectedMediaIds(upgradableEntries.map(e => e.mediaId))
+    }, [upgradableEntries])
+
+    function handleEnqueue(target: "torrent-client" | "debrid") {
+        enqueue({
+            items: upgradableEntries
+                .filter(e => selectedMediaIds.includes(e.mediaId))
+                .map(e => ({ mediaId: e.mediaId, torrent: e.recommended })),
+            target: target,
+            destination: "",
+        })
+    }
+
+    return (
+        <>
+            <CustomLibraryBanner discrete />
+            <PageWrapper
+                className="p-4 sm:p-8 space-y-4"
+            >
+                <div className="flex justify-between items-center w-full relative">
+                    <div>
+                        <h2>Release recommendations</h2>
+                        <p className="text-[--muted]">
+                            Compare your library with the best releases listed on <SeaLink href="https://releases.moe" target="_blank" className="underline">SeaDex</SeaLink>
+                        </p>
+                    </div>
+                    <div className="flex items-center gap-4">
+                        <Switch
+                            label="Prefer dual audio"
+                            value={preferDualAudio}
+                            onValueChange={setPreferDualAudio}
+                            fieldClass="w-fit"
+                        />
+                        <Button
+                            intent="white"
+                            leftIcon={<BiRefresh />}
+                            loading={isAnalyzing}
+                            onClick={() => analyze({ preferDualAudio })}
+                        >
+                            Analyze library
+                        </Button>
+                    </div>
+                </div>
+
+                {isAnalyzing && <LoadingSpinner />}
+
+                {!isAnalyzing && !!analysis && (
+                    <div className="space-y-4">
+                        <div className="flex flex-wrap justify-between items-center gap-2">
+                            <p className="text-[--muted]">
+                                {upgradableEntries.length} of {analysis.entries?.length ?? 0} series are not on a recommended release
+                                {!!analysis.totalUpgradeSize && <> &middot; {formatSize(analysis.totalUpgradeSize)} to download</>}
+                                {!!analysis.unlistedMediaIds?.length && <> &middot; {analysis.unlistedMediaIds.length} not listed on SeaDex</>}
+                            </p>
+                            {!!upgradableEntries.length && <div className="flex gap-2">
+                                <Button
+                                    intent="primary-subtle"
+                                    leftIcon={<BiDownload />}
+                                    disabled={!selectedMediaIds.length || isEnqueuing}
+                                    onClick={() => handleEnqueue("torrent-client")}
+                                >
+                                    Download selected
+                                </Button>
+                                {serverStatus?.debridSettings?.enabled && <Button
+                                    intent="primary-subtle"
+                                    leftIcon={<BiCloud />}
+                                    disabled={!selectedMediaIds.length || isEnqueuing}
+                                    onClick={() => handleEnqueue("debrid")}
+                                >
+                                    Send selected to Debrid
+                                </Button>}
+                            </div>}
+                        </div>
+
+                        <div className="divide-y divide-[--border] border rounded-[--radius]">
+                            {analysis.entries?.map(entry => (
+                                <EntryRow
+                                    key={entry.mediaId}
+                                    entry={entry}
+                                    selected={selectedMediaIds.includes(entry.mediaId)}
+                                    onSelectedChange={v => setSelectedMediaIds(prev => v
+                                        ? [...prev, entry.mediaId]
+                                        : prev.filter(id => id !== entry.mediaId))}
+                                />
+                            ))}
+                        </div>
+                    </div>
+                )}
+            </PageWrapper>
+        </>
+    )
+}
+
+function EntryRow(props: { entry: SeaDex_LibraryAnalysisEntry, selected: boolean, onSelectedChange: (v: boolean) => void }) {
+    const { entry, selected, onSelectedChange } = props
+
+    const canUpgrade = entry.status !== "best" && !!entry.recommended
+
+    return (
+        <div className="flex items-center gap-4 p-3">
+            <Checkbox
+                value={selected}
+                disabled={!canUpgrade}
+                onValueChange={v => typeof v === "boolean" && onSelectedChange(v)}
+                size="sm"
+            />
+            <div className="flex-1 min-w-0">
+                <SeaLink href={`/entry?id=${entry.mediaId}`} className="font-semibold line-clamp-1">
+                    {entry.media?.title?.userPreferred || entry.mediaId}
+                </SeaLink>
+                <p className="text-sm text-[--muted]">
+                    {entry.localEpisodeCount}{!!entry.totalEpisodeCount && `/${entry.totalEpisodeCount}`} episodes
+                    &middot; {entry.localReleaseGroups?.join(", ") || "Unknown group"}
+                    &middot; {formatSize(entry.localSize)}
+                </p>
+                {entry.incomplete && <p className="text-sm text-orange-300">SeaDex marks this entry as incomplete</p>}
+            </div>
+            <div className="text-right text-sm">
+                {entry.status === "best" && <Badge intent="success">Best release</Badge>}
+                {entry.status === "alt" && <Badge intent="warning">Alternative release</Badge>}
+                {entry.status === "upgradable" && <Badge intent="alert">Not recommended</Badge>}
+                {canUpgrade && (
+                    <p className="text-[--muted] pt-1">
+                        {formatRelease(entry.recommended)} &middot; {formatSize(entry.upgradeSize)}
+                        {" "}({entry.sizeDifference >= 0 ? "+" : ""}{formatSize(entry.sizeDifference)})
+                    </p>
+                )}
+            </div>
+        </div>
+    )
+}
@@ -14,6 +14,7 @@ const debridSettingsSchema = defineSchema(({ z }) => z.object({
     provider: z.string().default(""),
     apiKey: z.string().optional().default(""),
     includeDebridStreamInLibrary: z.boolean().default(false),
+    includeCloudTorrentsInLibrary: z.boolean().default(false),
     streamAutoSelect: z.boolean().default(false),
     streamPreferredResolution: z.string(),
 }))
@@ -66,6 +67,7 @@ export function DebridSettings(props: DebridSettingsProps) {
                     provider: settings?.provider || "-",
                     apiKey: settings?.apiKey,
                     includeDebridStreamInLibrary: settings?.includeDebridStreamInLibrary,
+                    includeCloudTorrentsInLibrary: settings?.includeCloudTorrentsInLibrary ?? false,
                     streamAutoSelect: settings?.streamAutoSelect ?? false,
                     streamPreferredResolution: settings?.streamPreferredResolution || "-",
                 }}
@@ -101,6 +103,8 @@ export function DebridSettings(props: DebridSettingsProps) {
                                     { label: "None", value: "-" },
                                     { label: "TorBox", value: "torbox" },
                                     { label: "Real-Debrid", value: "realdebrid" },
+                                    { label: "AllDebrid", value: "alldebrid" },
+                                    { label: "Premiumize", value: "premiumize" },
                                 ]}
                                 name="provider"
                                 label="Provider"
@@ -112,6 +116,19 @@ export function DebridSettings(props: DebridSettingsProps) {
                             />
                         </SettingsCard>
 
+                        <h3>
+                            Cloud Library
+                        </h3>
+
+                        <SettingsCard>
+                            <Field.Switch
+                                side="right"
+                                name="includeCloudTorrentsInLibrary"
+                                label="Show completed torrents in library"
+                                help="Completed torrents on your Debrid service will be matched to your AniList entries and appear in your library without being downloaded."
+                            />
+                        </SettingsCard>
+
                         <h3>
                             Debrid Streaming
                         </h3>
@@ -0,0 +1,200 @@
+import { getServerBaseUrl } from "@/api/client/server-url"
+import { EventStream_TypeInfo, Models_EventWebhook } from "@/api/generated/types"
+import {
+    useDeleteEventWebhook,
+    useGetEventStreamTypes,
+    useGetEventWebhooks,
+    useSaveEventWebhook,
+    useTestEventWebhook,
+} from "@/api/hooks/event_stream.hooks"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { Checkbox } from "@/components/ui/checkbox"
+import { LoadingSpinner } from "@/components/ui/loading-spinner"
+import { Modal } from "@/components/ui/modal"
+import { Switch } from "@/components/ui/switch"
+import { TextInput } from "@/components/ui/text-input"
+import React from "react"
+import { BiEdit, BiPlus, BiTrash } from "react-icons/bi"
+import { SettingsCard } from "../_components/settings-card"
+
+function newWebhook(): Models_EventWebhook {
+    return {
+        id: 0,
+        url: "",
+        secret: "",
+        enabled: true,
+        events: [],
+        lastDeliveryStatus: 0,
+        lastDeliveryError: "",
+    }
+}
+
+export function EventWebhooksSettings() {
+
+    const { data: webhooks, isLoading } = useGetEventWebhooks()
+    const { data: types } = useGetEventStreamTypes()
+
+    const [editedWebhook, setEditedWebhook] = React.useState<Models_EventWebhook | null>(null)
+
+    if (isLoading) return <LoadingSpinner />
+
+    return (
+        <>
+            <SettingsCard title="Event stream">
+                <p className="text-sm text-[--muted]">
+                    Subscribe to <code>{getServerBaseUrl()}/api/v1/event-stream</code> to receive the events as Server-Sent Events.
+                    Use the <code>types</code> query parameter to only receive some events, e.g. <code>?types=playback.started,playback.stopped</code>.
+                </p>
+                <div className="flex flex-wrap gap-2">
+                    {types?.map(info => <Badge key={info.type} intent="gray">{info.type}</Badge>)}
+                </div>
+            </SettingsCard>
+
+            <SettingsCard
+                title="Webhooks"
+                description="Events are sent as POST requests signed with HMAC-SHA256. Failed deliveries are retried 3 times."
+            >
+                {!webhooks?.length && <p className="text-[--muted]">No webhooks</p>}
+                <div className="divide-y divide-[--border]">
+                    {webhooks?.map(webhook => (
+                        <WebhookRow key={webhook.id} webhook={webhook} onEdit={() => setEditedWebhook(webhook)} />
+                    ))}
+                </div>
+                <Button
+                    intent="gray-subtle"
+                    leftIcon={<BiPlus />}
+                    onClick={() => setEditedWebhook(newWebhook())}
+                >
+                    Add webhook
+                </Button>
+            </SettingsCard>
+
+            <WebhookModal
+                webhook={editedWebhook}
+                types={types ?? []}
+                onClose={() => setEditedWebhook(null)}
+            />
+        </>
+    )
+}
+
+function WebhookRow({ webhook, onEdit }: { webhook: Models_EventWebhook, onEdit: () => void }) {
+
+    const { mutate: saveWebhook, isPending: isSaving } = useSaveEventWebhook()
+    const { mutate: deleteWebhook, isPending: isDeleting } = useDeleteEventWebhook()
+
+    const lastDeliveryFailed = !!webhook.lastDeliveryAt && !!webhook.lastDeliveryError
+
+    return (
+        <div className="py-3 flex flex-wrap items-center justify-between gap-2">
+            <div className="min-w-0">
+                <p className="font-semibold break-all">{webhook.url}</p>
+                <p className="text-sm text-[--muted]">
+                    {webhook.events?.length ? webhook.events.join(", ") : "All events"}
+                    {!!webhook.lastDeliveryAt && <> &middot; Last delivery: {new Date(webhook.lastDeliveryAt).toLocaleString()}</>}
+                </p>
+                {lastDeliveryFailed && <p className="text-sm text-red-300 break-all">{webhook.lastDeliveryError}</p>}
+            </div>
+            <div className="flex items-center gap-2">
+                {lastDeliveryFailed && <Badge intent="alert">Failing</Badge>}
+                <Switch
+                    value={webhook.enabled}
+                    disabled={isSaving}
+                    onValueChange={v => saveWebhook({ webhook: { ...webhook, enabled: v } })}
+                    fieldClass="w-fit"
+                />
+                <Button size="sm" intent="gray-subtle" leftIcon={<BiEdit />} onClick={onEdit}>
+                    Edit
+                </Button>
+                <Button
+                    size="sm"
+                    intent="alert-subtle"
+                    leftIcon={<BiTrash />}
+                    loading={isDeleting}
+                    onClick={() => deleteWebhook({ id: webhook.id })}
+                >
+                    Delete
+                </Button>
+            </div>
+        </div>
+    )
+}
+
+function WebhookModal({ webhook, types, onClose }: {
+    webhook: Models_EventWebhook | null,
+    types: EventStream_TypeInfo[],
+    onClose: () => void
+}) {
+
+    const { mutate: saveWebhook, isPending: isSaving } = useSaveEventWebhook()
+    const { mutate: testWebhook, isPending: isTesting } = useTestEventWebhook()
+
+    const [draft, setDraft] = React.useState<Models_EventWebhook>(newWebhook())
+
+    React.useEffect(() => {
+        if (webhook) setDraft(webhook)
+    }, [webhook])
+
+    function toggleType(type: string, checked: boolean) {
+        setDraft(prev => ({
+            ...prev,
+            events: checked ? [...(prev.events ?? []), type] : (prev.events ?? []).filter(e => e !== type),
+        }))
+    }
+
+    return (
+        <Modal
+            open={!!webhook}
+            onOpenChange={v => !v && onClose()}
+            title={draft.id ? "Edit webhook" : "Add webhook"}
+            contentClass="max-w-2xl"
+        >
+            <div className="space-y-4">
+                <TextInput
+                    label="URL"
+                    value={draft.url}
+                    onValueChange={v => setDraft(prev => ({ ...prev, url: v }))}
+                />
+                <TextInput
+                    label="Secret"
+                    help={draft.id
+                        ? "Used to sign the payloads, the signature is sent in the X-Seanime-Signature header."
+                        : "Leave empty to generate one."}
+                    value={draft.secret}
+                    onValueChange={v => setDraft(prev => ({ ...prev, secret: v }))}
+                />
+
+                <div className="space-y-2">
+                    <h5>Events</h5>
+                    <p className="text-sm text-[--muted]">All events are sent if none is selected.</p>
+                    {types.map(info => (
+                        <Checkbox
+                            key={info.type}
+                            label={<><code>{info.type}</code> &middot; {info.description}</>}
+                            value={draft.events?.includes(info.type) ?? false}
+                            onValueChange={v => toggleType(info.type, v === true)}
+                        />
+                    ))}
+                </div>
+            </div>
+
+            <div className="flex justify-end gap-2">
+                <Button
+                    intent="gray-subtle"
+                    loading={isTesting}
+                    onClick={() => testWebhook({ webhook: draft })}
+                >
+                    Send ping
+                </Button>
+                <Button
+                    intent="white"
+                    loading={isSaving}
+                    onClick={() => saveWebhook({ webhook: draft }, { onSuccess: onClose })}
+                >
+                    Save
+                </Button>
+            </div>
+        </Modal>
+    )
+}
@@ -0,0 +1,106 @@
+import { Models_LibraryRoot } from "@/api/generated/types"
+import { useGetLibraryRoots } from "@/api/hooks/library_roots.hooks"
+import { Badge } from "@/components/ui/badge"
+import { NativeSelect } from "@/components/ui/native-select"
+import { NumberInput } from "@/components/ui/number-input"
+import { Switch } from "@/components/ui/switch"
+import React from "react"
+import { useFormContext } from "react-hook-form"
+import upath from "upath"
+
+const MEDIA_FORMATS = [
+    { value: "", label: "Any" },
+    { value: "TV", label: "TV" },
+    { value: "TV_SHORT", label: "TV Short" },
+    { value: "MOVIE", label: "Movie" },
+    { value: "SPECIAL", label: "Special" },
+    { value: "OVA", label: "OVA" },
+    { value: "ONA", label: "ONA" },
+]
+
+function isSamePath(a: string, b: string) {
+    return upath.normalizeTrim(a).toLowerCase() === upath.normalizeTrim(b).toLowerCase()
+}
+
+function newRoot(path: string): Models_LibraryRoot {
+    return {
+        path,
+        enabled: true,
+        readOnly: false,
+        priority: 0,
+        defaultMediaFormat: "",
+    }
+}
+
+export function LibraryRootsSettings() {
+
+    const { watch, setValue } = useFormContext()
+
+    const libraryPath: string = watch("libraryPath")
+    const libraryPaths: string[] = watch("libraryPaths")
+    const libraryRoots: Models_LibraryRoot[] = watch("libraryRoots") ?? []
+
+    const { data: statuses } = useGetLibraryRoots()
+
+    const paths = [libraryPath, ...(libraryPaths ?? [])].filter(Boolean)
+
+    function updateRoot(root: Models_LibraryRoot) {
+        setValue("libraryRoots", [...libraryRoots.filter(r => !isSamePath(r.path, root.path)), root], { shouldDirty: true })
+    }
+
+    if (!paths.length) return null
+
+    return (
+        <div className="space-y-2">
+            <div>
+                <h5>Directory settings</h5>
+                <p className="text-[--muted] text-sm">
+                    Directories with a higher priority are scanned first. Files of read-only directories are never deleted or moved.
+                </p>
+            </div>
+
+            <div className="divide-y divide-[--border]">
+                {paths.map(path => {
+                    const root = libraryRoots.find(r => isSamePath(r.path, path)) ?? newRoot(path)
+                    const offline = statuses?.find(s => !!s.root && isSamePath(s.root.path, path))?.offline ?? false
+
+                    return (
+                        <div key={path} className="py-3 space-y-2">
+                            <div className="flex items-center gap-2">
+                                <p className="font-semibold break-all">{path}</p>
+                                {offline && <Badge intent="warning">Offline</Badge>}
+                            </div>
+                            <div className="flex flex-wrap items-center gap-4">
+                                <Switch
+                                    label="Enabled"
+                                    value={root.enabled}
+                                    onValueChange={v => updateRoot({ ...root, enabled: v })}
+                                    fieldClass="w-fit"
+                                />
+                                <Switch
+                                    label="Read-only"
+                                    value={root.readOnly}
+                                    onValueChange={v => updateRoot({ ...root, readOnly: v })}
+                                    fieldClass="w-fit"
+                                />
+                                <NumberInput
+                                    label="Priority"
+                                    value={root.priority}
+                                    onValueChange={v => updateRoot({ ...root, priority: v || 0 })}
+                                    fieldClass="w-32"
+                                />
+                                <NativeSelect
+                                    label="Default format"
+                                    value={root.defaultMediaFormat}
+                                    options={MEDIA_FORMATS}
+                                    onChange={e => updateRoot({ ...root, defaultMediaFormat: e.target.value })}
+                                    fieldClass="w-40"
+                                />
+                            </div>
+                        </div>
+                    )
+                })}
+            </div>
+        </div>
+    )
+}
@@ -1,6 +1,8 @@
 import { SettingsCard } from "@/app/(main)/settings/_components/settings-card"
 import { SettingsSubmitButton } from "@/app/(main)/settings/_components/settings-submit-button"
 import { DataSettings } from "@/app/(main)/settings/_containers/data-settings"
+import { LibraryRootsSettings } from "@/app/(main)/settings/_containers/library-roots-settings"
+import { MatchingRulesSettings } from "@/app/(main)/settings/_containers/matching-rules-settings"
 import { Accordion, AccordionContent, AccordionItem, AccordionTrigger } from "@/components/ui/accordion"
 import { Field } from "@/components/ui/form"
 import { Separator } from "@/components/ui/separator"
@@ -38,6 +40,8 @@ export function LibrarySettings(props: LibrarySettingsProps) {
                     help="Include additional directory paths if your library is spread across multiple locations."
                     shouldExist
                 />
+
+                <LibraryRootsSettings />
             </SettingsCard>
 
             <SettingsCard>
@@ -98,6 +102,46 @@ export function LibrarySettings(props: LibrarySettingsProps) {
                             />
                         </div>
 
+                        <Field.Switch
+                            side="right"
+                            name="scannerLearnMatchingRules"
+                            label="Learn matching rules from manual matches"
+                            help="When you manually match files, a rule is created so that the scanner matches them the same way next time."
+                        />
+
+                        <Field.Switch
+                            side="right"
+                            name="scannerHashFiles"
+                            label="Hash files"
+                            help="Compute the CRC32 and ED2K hashes of new files and verify the CRC32 written in their names. Files are read entirely, the first scan can be slow."
+                        />
+
+                        <Field.Text
+                            name="scannerHashLookupPath"
+                            label="Hash lookup file"
+                            placeholder="/path/to/hashes.json"
+                            help="Optional JSON file mapping file sizes and ED2K hashes to AniList IDs and episodes. Files found in it are not matched by title."
+                        />
+
+                        <Field.Switch
+                            side="right"
+                            name="scannerProbeMediaInfo"
+                            label="Read media info"
+                            help="Use FFprobe to read the duration, codecs and audio/subtitle tracks of new files so that the library can be filtered by them. The FFprobe path of the media streaming settings is used."
+                        />
+
+                        <Field.Number
+                            name="scannerLogRetention"
+                            label="Scan logs to keep"
+                            help="Older scan logs are deleted after each scan. 0 keeps all the logs."
+                            min={0}
+                            formatOptions={{ useGrouping: false }}
+                        />
+
+                        <Separator />
+
+                        <MatchingRulesSettings />
+
                         <Separator />
 
                         <DataSettings />
@@ -0,0 +1,207 @@
+import { Models_MatchingRule } from "@/api/generated/types"
+import { useDeleteMatchingRule, useGetMatchingRules, useSaveMatchingRule } from "@/api/hooks/matching_rules.hooks"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { LoadingSpinner } from "@/components/ui/loading-spinner"
+import { Modal } from "@/components/ui/modal"
+import { NativeSelect } from "@/components/ui/native-select"
+import { NumberInput } from "@/components/ui/number-input"
+import { Switch } from "@/components/ui/switch"
+import { TextInput } from "@/components/ui/text-input"
+import React from "react"
+import { BiEdit, BiPlus, BiTrash } from "react-icons/bi"
+
+const RULE_TYPES = [
+    { value: "path", label: "Directory" },
+    { value: "regex", label: "Regular expression" },
+    { value: "title-alias", label: "Title alias" },
+    { value: "episode-offset", label: "Episode offset" },
+]
+
+function newRule(): Models_MatchingRule {
+    return {
+        id: 0,
+        type: "path",
+        pattern: "",
+        releaseGroup: "",
+        mediaId: 0,
+        episodeOffset: 0,
+        enabled: true,
+        learned: false,
+    }
+}
+
+function describeRule(rule: Models_MatchingRule) {
+    switch (rule.type) {
+        case "episode-offset":
+            return `Episodes ${rule.episodeOffset > 0 ? "+" : ""}${rule.episodeOffset}`
+        case "title-alias":
+            return rule.releaseGroup ? `[${rule.releaseGroup}] ${rule.pattern}` : rule.pattern
+        default:
+            return rule.pattern
+    }
+}
+
+export function MatchingRulesSettings() {
+
+    const { data: rules, isLoading } = useGetMatchingRules()
+
+    const [editedRule, setEditedRule] = React.useState<Models_MatchingRule | null>(null)
+
+    if (isLoading) return <LoadingSpinner />
+
+    return (
+        <div className="space-y-4">
+            <div>
+                <h5>Matching rules</h5>
+
+                <p className="text-[--muted]">
+                    Rules are applied before fuzzy matching. Files matched by a rule are always assigned to its media.
+                </p>
+            </div>
+
+            {!rules?.length && <p className="text-[--muted]">No rules</p>}
+            <div className="divide-y divide-[--border]">
+                {rules?.map(rule => (
+                    <MatchingRuleRow key={rule.id} rule={rule} onEdit={() => setEditedRule(rule)} />
+                ))}
+            </div>
+
+            <Button
+                intent="gray-subtle"
+                leftIcon={<BiPlus />}
+                onClick={() => setEditedRule(newRule())}
+            >
+                Add rule
+            </Button>
+
+            <MatchingRuleModal rule={editedRule} onClose={() => setEditedRule(null)} />
+        </div>
+    )
+}
+
+function MatchingRuleRow({ rule, onEdit }: { rule: Models_MatchingRule, onEdit: () => void }) {
+
+    const { mutate: saveRule, isPending: isSaving } = useSaveMatchingRule()
+    const { mutate: deleteRule, isPending: isDeleting } = useDeleteMatchingRule()
+
+    return (
+        <div className="py-3 flex flex-wrap items-center justify-between gap-2">
+            <div className="min-w-0">
+                <p className="font-semibold break-all">{describeRule(rule)}</p>
+                <p className="text-sm text-[--muted]">
+                    {RULE_TYPES.find(t => t.value === rule.type)?.label ?? rule.type}
+                    {" "}&middot; Media {rule.mediaId}
+                </p>
+            </div>
+            <div className="flex items-center gap-2">
+                {rule.learned && <Badge intent="gray">Learned</Badge>}
+                <Switch
+                    value={rule.enabled}
+                    disabled={isSaving}
+                    onValueChange={v => saveRule({ rule: { ...rule, enabled: v } })}
+                    fieldClass="w-fit"
+                />
+                <Button size="sm" intent="gray-subtle" leftIcon={<BiEdit />} onClick={onEdit}>
+                    Edit
+                </Button>
+                <Button
+                    size="sm"
+                    intent="alert-subtle"
+                    leftIcon={<BiTrash />}
+                    loading={isDeleting}
+                    onClick={() => deleteRule({ id: rule.id })}
+                >
+                    Delete
+                </Button>
+            </div>
+        </div>
+    )
+}
+
+function MatchingRuleModal({ rule, onClose }: { rule: Models_MatchingRule | null, onClose: () => void }) {
+
+    const { mutate: saveRule, isPending: isSaving } = useSaveMatchingRule()
+
+    const [draft, setDraft] = React.useState<Models_MatchingRule>(newRule())
+
+    React.useEffect(() => {
+        if (rule) setDraft(rule)
+    }, [rule])
+
+    const type = draft.type
+
+    return (
+        <Modal
+            open={!!rule}
+            onOpenChange={v => !v && onClose()}
+            title={draft.id ? "Edit rule" : "Add rule"}
+        >
+            <div className="space-y-4">
+                <div className="flex flex-wrap gap-2">
+                    <NativeSelect
+                        label="Type"
+                        value={draft.type}
+                        options={RULE_TYPES}
+                        onChange={e => setDraft(prev => ({ ...prev, type: e.target.value }))}
+                        fieldClass="flex-1"
+                    />
+                    <NumberInput
+                        label="AniList ID"
+                        value={draft.mediaId || 0}
+                        min={0}
+                        onValueChange={v => setDraft(prev => ({ ...prev, mediaId: v || 0 }))}
+                        fieldClass="w-40"
+                    />
+                </div>
+
+                {type === "path" && <TextInput
+                    label="Directory"
+                    help="Files in this directory and its subdirectories are matched to the media."
+                    value={draft.pattern}
+                    onValueChange={v => setDraft(prev => ({ ...prev, pattern: v }))}
+                />}
+
+                {type === "regex" && <TextInput
+                    label="Regular expression"
+                    help="Matched against the full path of the file, using forward slashes."
+                    value={draft.pattern}
+                    onValueChange={v => setDraft(prev => ({ ...prev, pattern: v }))}
+                />}
+
+                {type === "title-alias" && <div className="flex flex-wrap gap-2">
+                    <TextInput
+                        label="Title"
+                        help="Title parsed from the file name."
+                        value={draft.pattern}
+                        onValueChange={v => setDraft(prev => ({ ...prev, pattern: v }))}
+                        fieldClass="flex-1"
+                    />
+                    <TextInput
+                        label="Release group (optional)"
+                        value={draft.releaseGroup}
+                        onValueChange={v => setDraft(prev => ({ ...prev, releaseGroup: v }))}
+                        fieldClass="flex-1"
+                    />
+                </div>}
+
+                {type === "episode-offset" && <NumberInput
+                    label="Episode offset"
+                    help="Added to the episode numbers of the files matched to the media. Use a negative number for absolute numbering."
+                    value={draft.episodeOffset}
+                    onValueChange={v => setDraft(prev => ({ ...prev, episodeOffset: v || 0 }))}
+                />}
+            </div>
+
+            <div className="flex justify-end gap-2">
+                <Button
+                    intent="white"
+                    loading={isSaving}
+                    onClick={() => saveRule({ rule: draft }, { onSuccess: onClose })}
+                >
+                    Save
+                </Button>
+            </div>
+        </Modal>
+    )
+}
@@ -20,6 +20,9 @@ const mediastreamSchema = defineSchema(({ z }) => z.object({
     ffmpegPath: z.string().min(0),
     ffprobePath: z.string().min(0),
     transcodeHwAccelCustomSettings: z.string().min(0),
+    trickplayEnabled: z.boolean(),
+    transcodeTonemap: z.boolean(),
+    transcodeBurnIn: z.boolean(),
 }))
 
 const MEDIASTREAM_HW_ACCEL_OPTIONS = [
@@ -98,6 +101,9 @@ export function MediastreamSettings(props: MediastreamSettingsProps) {
                     ffmpegPath: settings?.ffmpegPath || "",
                     ffprobePath: settings?.ffprobePath || "",
                     transcodeHwAccelCustomSettings: settings?.transcodeHwAccelCustomSettings || "{\n	\"name\": \"\",\n	\"decodeFlags\": [\n		\"-hwaccel\", \"\",\n		\"-hwaccel_output_format\", \"\",\n	],\n	\"encodeFlags\": [\n		\"-c:v\", \"\",\n		\"-preset\", \"\",\n		\"-pix_fmt\", \"yuv420p\",\n	],\n	\"scaleFilter\": \"scale=%d:%d\"\n}",
+                    trickplayEnabled: settings?.trickplayEnabled ?? false,
+                    transcodeTonemap: settings?.transcodeTonemap ?? false,
+                    transcodeBurnIn: settings?.transcodeBurnIn ?? false,
                 }}
                 stackClass="space-y-4"
             >
@@ -185,6 +191,29 @@ export function MediastreamSettings(props: MediastreamSettingsProps) {
                                 label="Transcode preset"
                                 help="'Fast' is recommended. VAAPI does not support presets."
                             />
+
+                            <Field.Switch
+                                side="right"
+                                name="transcodeTonemap"
+                                label="HDR tone mapping"
+                                help="Convert HDR videos to SDR so that colors do not look washed out. Only supported with CPU, VAAPI and VideoToolbox. CPU tone mapping requires FFmpeg to be built with zimg."
+                            />
+
+                            <Field.Switch
+                                side="right"
+                                name="transcodeBurnIn"
+                                label="Burned-in subtitles"
+                                help="Add a quality option with the selected subtitle track rendered into the video, for devices that cannot display the subtitles. Always encoded with the CPU."
+                            />
+                        </SettingsCard>
+
+                        <SettingsCard title="Seek previews">
+                            <Field.Switch
+                                side="right"
+                                name="trickplayEnabled"
+                                label="Enable seek previews"
+                                help="Show thumbnails when scrubbing. Thumbnails are generated using FFmpeg the first time a file is played."
+                            />
                         </SettingsCard>
 
                         <SettingsCard title="FFmpeg">
@@ -0,0 +1,323 @@
+import { Models_NotificationChannel, Notifier_EventInfo } from "@/api/generated/types"
+import {
+    useDeleteNotificationChannel,
+    useGetNotificationChannels,
+    useGetNotificationEvents,
+    useSaveNotificationChannel,
+    useTestNotificationChannel,
+} from "@/api/hooks/notifications.hooks"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { Checkbox } from "@/components/ui/checkbox"
+import { LoadingSpinner } from "@/components/ui/loading-spinner"
+import { Modal } from "@/components/ui/modal"
+import { NativeSelect } from "@/components/ui/native-select"
+import { NumberInput } from "@/components/ui/number-input"
+import { Switch } from "@/components/ui/switch"
+import { TextInput } from "@/components/ui/text-input"
+import { Textarea } from "@/components/ui/textarea"
+import React from "react"
+import { BiEdit, BiPlus, BiTrash } from "react-icons/bi"
+import { SettingsCard } from "../_components/settings-card"
+
+const CHANNEL_TYPES = [
+    { value: "discord", label: "Discord webhook" },
+    { value: "ntfy", label: "ntfy" },
+    { value: "gotify", label: "Gotify" },
+    { value: "smtp", label: "Email (SMTP)" },
+    { value: "webhook", label: "Webhook" },
+]
+
+function newChannel(): Models_NotificationChannel {
+    return {
+        id: 0,
+        name: "",
+        type: "discord",
+        enabled: true,
+        events: [],
+        config: { url: "" },
+        templates: {},
+    }
+}
+
+export function NotificationChannelsSettings() {
+
+    const { data: channels, isLoading } = useGetNotificationChannels()
+    const { data: events } = useGetNotificationEvents()
+
+    const [editedChannel, setEditedChannel] = React.useState<Models_NotificationChannel | null>(null)
+
+    if (isLoading) return <LoadingSpinner />
+
+    return (
+        <>
+            <SettingsCard description="Send notifications to external services. Each channel receives the events it is subscribed to.">
+                {!channels?.length && <p className="text-[--muted]">No channels</p>}
+                <div className="divide-y divide-[--border]">
+                    {channels?.map(channel => (
+                        <ChannelRow key={channel.id} channel={channel} onEdit={() => setEditedChannel(channel)} />
+                    ))}
+                </div>
+                <Button
+                    intent="gray-subtle"
+                    leftIcon={<BiPlus />}
+                    onClick={() => setEditedChannel(newChannel())}
+                >
+                    Add channel
+                </Button>
+            </SettingsCard>
+
+            <ChannelModal
+                channel={editedChannel}
+                events={events ?? []}
+                onClose={() => setEditedChannel(null)}
+            />
+        </>
+    )
+}
+
+function ChannelRow({ channel, onEdit }: { channel: Models_NotificationChannel, onEdit: () => void }) {
+
+    const { mutate: saveChannel, isPending: isSaving } = useSaveNotificationChannel()
+    const { mutate: deleteChannel, isPending: isDeleting } = useDeleteNotificationChannel()
+
+    return (
+        <div className="py-3 flex flex-wrap items-center justify-between gap-2">
+            <div>
+                <p className="font-semibold">{channel.name || "Unnamed channel"}</p>
+                <p className="text-sm text-[--muted]">
+                    {CHANNEL_TYPES.find(t => t.value === channel.type)?.label ?? channel.type}
+                    {" "}&middot; {channel.events?.length ? `${channel.events.length} events` : "No events"}
+                </p>
+            </div>
+            <div className="flex items-center gap-2">
+                {!channel.enabled && <Badge intent="gray">Disabled</Badge>}
+                <Switch
+                    value={channel.enabled}
+                    disabled={isSaving}
+                    onValueChange={v => saveChannel({ channel: { ...channel, enabled: v } })}
+                    fieldClass="w-fit"
+                />
+                <Button size="sm" intent="gray-subtle" leftIcon={<BiEdit />} onClick={onEdit}>
+                    Edit
+                </Button>
+                <Button
+                    size="sm"
+                    intent="alert-subtle"
+                    leftIcon={<BiTrash />}
+                    loading={isDeleting}
+                    onClick={() => deleteChannel({ id: channel.id })}
+                >
+                    Delete
+                </Button>
+            </div>
+        </div>
+    )
+}
+
+function ChannelModal({ channel, events, onClose }: {
+    channel: Models_NotificationChannel | null,
+    events: Notifier_EventInfo[],
+    onClose: () => void
+}) {
+
+    const { mutate: saveChannel, isPending: isSaving } = useSaveNotificationChannel()
+    const { mutate: testChannel, isPending: isTesting } = useTestNotificationChannel()
+
+    const [draft, setDraft] = React.useState<Models_NotificationChannel>(newChannel())
+
+    React.useEffect(() => {
+        if (channel) setDraft(channel)
+    }, [channel])
+
+    function updateConfig(update: Partial<Models_NotificationChannel["config"]>) {
+        setDraft(prev => ({ ...prev, config: { ...prev.config, ...update } }))
+    }
+
+    function toggleEvent(event: string, checked: boolean) {
+        setDraft(prev => ({
+            ...prev,
+            events: checked ? [...(prev.events ?? []), event] : (prev.events ?? []).filter(e => e !== event),
+        }))
+    }
+
+    function updateTemplate(event: string, update: { title?: string, message?: string }) {
+        setDraft(prev => {
+            const current = prev.templates?.[event] ?? { title: "", message: "" }
+            return { ...prev, templates: { ...prev.templates, [event]: { ...current, ...update } } }
+        })
+    }
+
+    const type = draft.type
+
+    return (
+        <Modal
+            open={!!channel}
+            onOpenChange={v => !v && onClose()}
+            title={draft.id ? "Edit channel" : "Add channel"}
+            contentClass="max-w-3xl"
+        >
+            <div className="space-y-4 max-h-[70vh] overflow-y-auto pr-2">
+                <div className="flex flex-wrap gap-2">
+                    <TextInput
+                        label="Name"
+                        value={draft.name}
+                        onValueChange={v => setDraft(prev => ({ ...prev, name: v }))}
+                        fieldClass="flex-1"
+                    />
+                    <NativeSelect
+                        label="Type"
+                        value={draft.type}
+                        options={CHANNEL_TYPES}
+                        onChange={e => setDraft(prev => ({ ...prev, type: e.target.value }))}
+                        fieldClass="w-52"
+                    />
+                </div>
+
+                {type !== "smtp" && type !== "ntfy" && <TextInput
+                    label={type === "gotify" ? "Server URL" : "Webhook URL"}
+                    value={draft.config.url}
+                    onValueChange={v => updateConfig({ url: v })}
+                />}
+
+                {type === "ntfy" && <div className="flex flex-wrap gap-2">
+                    <TextInput
+                        label="Server URL"
+                        placeholder="https://ntfy.sh"
+                        value={draft.config.url}
+                        onValueChange={v => updateConfig({ url: v })}
+                        fieldClass="flex-1"
+                    />
+                    <TextInput
+                        label="Topic"
+                        value={draft.config.topic ?? ""}
+                        onValueChange={v => updateConfig({ topic: v })}
+                        fieldClass="flex-1"
+                    />
+                    <NumberInput
+                        label="Priority"
+                        value={draft.config.priority ?? 0}
+                        min={0}
+                        max={5}
+                        onValueChange={v => updateConfig({ priority: v || 0 })}
+                        fieldClass="w-28"
+                    />
+                </div>}
+
+                {(type === "ntfy" || type === "gotify") && <TextInput
+                    label={type === "gotify" ? "Application token" : "Access token (optional)"}
+                    type="password"
+                    value={draft.config.token ?? ""}
+                    onValueChange={v => updateConfig({ token: v })}
+                />}
+
+                {type === "smtp" && <>
+                    <div className="flex flex-wrap gap-2">
+                        <TextInput
+                            label="Host"
+                            value={draft.config.host ?? ""}
+                            onValueChange={v => updateConfig({ host: v })}
+                            fieldClass="flex-1"
+                        />
+                        <NumberInput
+                            label="Port"
+                            value={draft.config.port || 587}
+                            min={1}
+                            onValueChange={v => updateConfig({ port: v || 0 })}
+                            fieldClass="w-28"
+                        />
+                    </div>
+                    <div className="flex flex-wrap gap-2">
+                        <TextInput
+                            label="Username"
+                            value={draft.config.username ?? ""}
+                            onValueChange={v => updateConfig({ username: v })}
+                            fieldClass="flex-1"
+                        />
+                        <TextInput
+                            label="Password"
+                            type="password"
+                            value={draft.config.password ?? ""}
+                            onValueChange={v => updateConfig({ password: v })}
+                            fieldClass="flex-1"
+                        />
+                    </div>
+                    <TextInput
+                        label="From"
+                        value={draft.config.from ?? ""}
+                        onValueChange={v => updateConfig({ from: v })}
+                    />
+                    <TextInput
+                        label="To"
+                        help="Comma-separated list of recipients"
+                        value={draft.config.to?.join(", ") ?? ""}
+                        onValueChange={v => updateConfig({ to: v.split(",").map(s => s.trim()).filter(Boolean) })}
+                    />
+                </>}
+
+                {type === "webhook" && <Textarea
+                    label="Headers"
+                    help="One 'Name: value' header per line"
+                    value={Object.entries(draft.config.headers ?? {}).map(([k, v]) => `${k}: ${v}`).join("\n")}
+                    onValueChange={v => updateConfig({
+                        headers: Object.fromEntries(v.split("\n")
+                            .map(line => line.split(":"))
+                            .filter(parts => parts.length >= 2 && !!parts[0].trim())
+                            .map(([k, ...rest]) => [k.trim(), rest.join(":").trim()])),
+                    })}
+                />}
+
+                <div className="space-y-3">
+                    <h5>Events</h5>
+                    {events.map(info => {
+                        const subscribed = draft.events?.includes(info.event) ?? false
+                        const template = draft.templates?.[info.event]
+                        return (
+                            <div key={info.event} className="space-y-2">
+                                <Checkbox
+                                    label={info.description}
+                                    value={subscribed}
+                                    onValueChange={v => toggleEvent(info.event, v === true)}
+                                />
+                                {subscribed && <div className="pl-6 space-y-2">
+                                    <TextInput
+                                        size="sm"
+                                        label="Title"
+                                        placeholder={info.defaultTitle}
+                                        value={template?.title ?? ""}
+                                        onValueChange={v => updateTemplate(info.event, { title: v })}
+                                    />
+                                    <TextInput
+                                        size="sm"
+                                        label="Message"
+                                        placeholder={info.defaultTemplate}
+                                        help={<>Variables: {info.variables.map(v => `{{.${v}}}`).join(", ")}</>}
+                                        value={template?.message ?? ""}
+                                        onValueChange={v => updateTemplate(info.event, { message: v })}
+                                    />
+                                </div>}
+                            </div>
+                        )
+                    })}
+                </div>
+            </div>
+
+            <div className="flex justify-end gap-2">
+                <Button
+                    intent="gray-subtle"
+                    loading={isTesting}
+                    onClick={() => testChannel({ channel: draft })}
+                >
+                    Send test
+                </Button>
+                <Button
+                    intent="white"
+                    loading={isSaving}
+                    onClick={() => saveChannel({ channel: draft }, { onSuccess: onClose })}
+                >
+                    Save
+                </Button>
+            </div>
+        </Modal>
+    )
+}
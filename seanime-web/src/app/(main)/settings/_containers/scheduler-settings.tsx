@@ -0,0 +1,152 @@
+import { Scheduler_JobInfo } from "@/api/generated/types"
+import { useGetScheduledJobHistory, useGetScheduledJobs, useRunScheduledJob, useUpdateScheduledJob } from "@/api/hooks/scheduler.hooks"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { LoadingSpinner } from "@/components/ui/loading-spinner"
+import { Modal } from "@/components/ui/modal"
+import { Switch } from "@/components/ui/switch"
+import { TextInput } from "@/components/ui/text-input"
+import React from "react"
+import { BiHistory, BiPlay } from "react-icons/bi"
+import { SettingsCard } from "../_components/settings-card"
+
+function formatDate(date: string | undefined) {
+    if (!date) return "-"
+    return new Date(date).toLocaleString()
+}
+
+function formatDuration(ms: number) {
+    if (ms < 1000) return `${ms}ms`
+    return `${(ms / 1000).toFixed(1)}s`
+}
+
+function RunStatusBadge({ status }: { status: string }) {
+    if (status === "success") return <Badge intent="success">Success</Badge>
+    if (status === "skipped") return <Badge intent="gray">Skipped</Badge>
+    return <Badge intent="alert">Failed</Badge>
+}
+
+export function SchedulerSettings() {
+
+    const { data: jobs, isLoading } = useGetScheduledJobs()
+
+    const [historyJobName, setHistoryJobName] = React.useState<string | null>(null)
+
+    if (isLoading) return <LoadingSpinner />
+
+    return (
+        <>
+            <SettingsCard description="Cron expressions (e.g. '0 4 * * *'), descriptors (e.g. '@daily') or intervals (e.g. '@every 30m'). Leave empty to use the default schedule.">
+                <div className="divide-y divide-[--border]">
+                    {jobs?.map(job => (
+                        <JobRow key={job.name} job={job} onShowHistory={() => setHistoryJobName(job.name)} />
+                    ))}
+                </div>
+            </SettingsCard>
+
+            <JobHistoryModal name={historyJobName} onClose={() => setHistoryJobName(null)} />
+        </>
+    )
+}
+
+function JobRow({ job, onShowHistory }: { job: Scheduler_JobInfo, onShowHistory: () => void }) {
+
+    const { mutate: updateJob, isPending: isUpdating } = useUpdateScheduledJob()
+    const { mutate: runJob, isPending: isStarting } = useRunScheduledJob()
+
+    const [schedule, setSchedule] = React.useState(job.schedule === job.defaultSchedule ? "" : job.schedule)
+
+    React.useEffect(() => {
+        setSchedule(job.schedule === job.defaultSchedule ? "" : job.schedule)
+    }, [job.schedule, job.defaultSchedule])
+
+    const isScheduleChanged = schedule !== (job.schedule === job.defaultSchedule ? "" : job.schedule)
+
+    return (
+        <div className="py-3 space-y-2">
+            <div className="flex flex-wrap items-center justify-between gap-2">
+                <div>
+                    <p className="font-semibold">{job.description}</p>
+                    <p className="text-sm text-[--muted]">
+                        {job.enabled && !!job.nextRun ? <>Next run: {formatDate(job.nextRun)}</> : "Disabled"}
+                        {!!job.lastRun && <> &middot; Last run: {formatDate(job.lastRun.startedAt)}</>}
+                    </p>
+                </div>
+                <div className="flex items-center gap-2">
+                    {job.running && <Badge intent="primary">Running</Badge>}
+                    {!job.running && !!job.lastRun && <RunStatusBadge status={job.lastRun.status} />}
+                    <Switch
+                        value={job.enabled}
+                        disabled={isUpdating}
+                        onValueChange={v => updateJob({ name: job.name, schedule, enabled: v })}
+                        fieldClass="w-fit"
+                    />
+                </div>
+            </div>
+            <div className="flex flex-wrap items-center gap-2">
+                <TextInput
+                    value={schedule}
+                    placeholder={job.defaultSchedule}
+                    onValueChange={setSchedule}
+                    fieldClass="w-60"
+                    size="sm"
+                />
+                {isScheduleChanged && <Button
+                    size="sm"
+                    intent="white"
+                    loading={isUpdating}
+                    onClick={() => updateJob({ name: job.name, schedule, enabled: job.enabled })}
+                >
+                    Save
+                </Button>}
+                <Button
+                    size="sm"
+                    intent="gray-subtle"
+                    leftIcon={<BiPlay />}
+                    disabled={job.running || isStarting}
+                    onClick={() => runJob({ name: job.name })}
+                >
+                    Run now
+                </Button>
+                <Button
+                    size="sm"
+                    intent="gray-subtle"
+                    leftIcon={<BiHistory />}
+                    onClick={onShowHistory}
+                >
+                    History
+                </Button>
+            </div>
+        </div>
+    )
+}
+
+function JobHistoryModal({ name, onClose }: { name: string | null, onClose: () => void }) {
+
+    const { data: runs, isLoading } = useGetScheduledJobHistory(name)
+
+    return (
+        <Modal
+            open={!!name}
+            onOpenChange={v => !v && onClose()}
+            title="Run history"
+            contentClass="max-w-3xl"
+        >
+            {isLoading && <LoadingSpinner />}
+            {!isLoading && !runs?.length && <p className="text-center text-[--muted]">No runs yet</p>}
+            <div className="divide-y divide-[--border] max-h-[60vh] overflow-y-auto">
+                {runs?.map(run => (
+                    <div key={run.id} className="py-2 flex items-start justify-between gap-2 text-sm">
+                        <div>
+                            <p>{formatDate(run.startedAt)} &middot; {formatDuration(run.duration)} &middot; {run.trigger === "manual"
+                                ? "Manual"
+                                : "Scheduled"}</p>
+                            {!!run.error && <p className="text-[--muted] break-all">{run.error}</p>}
+                        </div>
+                        <RunStatusBadge status={run.status} />
+                    </div>
+                ))}
+            </div>
+        </Modal>
+    )
+}
@@ -12,8 +12,11 @@ import { __settings_tabAtom } from "@/app/(main)/settings/_components/settings-p
 import { SettingsIsDirty, SettingsSubmitButton } from "@/app/(main)/settings/_components/settings-submit-button"
 import { DebridSettings } from "@/app/(main)/settings/_containers/debrid-settings"
 import { FilecacheSettings } from "@/app/(main)/settings/_containers/filecache-settings"
+import { EventWebhooksSettings } from "@/app/(main)/settings/_containers/event-webhooks-settings"
 import { LibrarySettings } from "@/app/(main)/settings/_containers/library-settings"
 import { LogsSettings } from "@/app/(main)/settings/_containers/logs-settings"
+import { NotificationChannelsSettings } from "@/app/(main)/settings/_containers/notification-channels-settings"
+import { SchedulerSettings } from "@/app/(main)/settings/_containers/scheduler-settings"
 import { MangaSettings } from "@/app/(main)/settings/_containers/manga-settings"
 import { MediastreamSettings } from "@/app/(main)/settings/_containers/mediastream-settings"
 import { ServerSettings } from "@/app/(main)/settings/_containers/server-settings"
@@ -38,12 +41,12 @@ import { FaShareFromSquare } from "react-icons/fa6"
 import { HiOutlineServerStack } from "react-icons/hi2"
 import { ImDownload } from "react-icons/im"
 import { IoLibrary, IoPlayBackCircleSharp } from "react-icons/io5"
-import { LuBookKey, LuWandSparkles } from "react-icons/lu"
+import { LuBellRing, LuBookKey, LuCalendarClock, LuWandSparkles } from "react-icons/lu"
 import { MdNoAdultContent, MdOutlineBroadcastOnHome, MdOutlineDownloading, MdOutlinePalette } from "react-icons/md"
 import { PiVideoFill } from "react-icons/pi"
 import { RiFolderDownloadFill } from "react-icons/ri"
 import { SiAnilist, SiBittorrent } from "react-icons/si"
-import { TbDatabaseExclamation } from "react-icons/tb"
+import { TbDatabaseExclamation, TbWebhook } from "react-icons/tb"
 import { VscDebugAlt } from "react-icons/vsc"
 import { SettingsCard, SettingsNavCard } from "./_components/settings-card"
 import { DiscordRichPresenceSettings } from "./_containers/discord-rich-presence-settings"
@@ -153,6 +156,9 @@ export default function Page() {
                                 <TabsTrigger value="anilist"><SiAnilist className="text-lg mr-3" /> AniList</TabsTrigger>
                                 {/* <Separator className="hidden lg:block my-2" /> */}
                                 <TabsTrigger value="cache"><TbDatabaseExclamation className="text-lg mr-3" /> Cache</TabsTrigger>
+                                <TabsTrigger value="notifications"><LuBellRing className="text-lg mr-3" /> Notifications</TabsTrigger>
+                                <TabsTrigger value="webhooks"><TbWebhook className="text-lg mr-3" /> Webhooks</TabsTrigger>
+                                <TabsTrigger value="scheduler"><LuCalendarClock className="text-lg mr-3" /> Scheduled Jobs</TabsTrigger>
                                 <TabsTrigger value="logs"><LuBookKey className="text-lg mr-3" /> Logs</TabsTrigger>
                                 {/*<TabsTrigger value="data"><FiDatabase className="text-lg mr-3" /> Data</TabsTrigger>*/}
                                 {/* <Separator className="hidden lg:block my-2" /> */}
@@ -187,6 +193,12 @@ export default function Page() {
                                         autoSyncOfflineLocalData: data.autoSyncOfflineLocalData ?? false,
                                         scannerMatchingThreshold: data.scannerMatchingThreshold,
                                         scannerMatchingAlgorithm: data.scannerMatchingAlgorithm === "-" ? "" : data.scannerMatchingAlgorithm,
+                                        scannerLearnMatchingRules: data.scannerLearnMatchingRules ?? false,
+                                        scannerHashFiles: data.scannerHashFiles ?? false,
+                                        scannerProbeMediaInfo: data.scannerProbeMediaInfo ?? false,
+                                        scannerLogRetention: data.scannerLogRetention ?? 0,
+                                        libraryRoots: data.libraryRoots ?? [],
+                                        scannerHashLookupPath: data.scannerHashLookupPath ?? "",
                                     },
                                     manga: {
                                         defaultMangaProvider: data.defaultMangaProvider === "-" ? "" : data.defaultMangaProvider,
@@ -301,6 +313,12 @@ export default function Page() {
                                 autoSyncOfflineLocalData: status?.settings?.library?.autoSyncOfflineLocalData ?? false,
                                 scannerMatchingThreshold: status?.settings?.library?.scannerMatchingThreshold ?? 0.5,
                                 scannerMatchingAlgorithm: status?.settings?.library?.scannerMatchingAlgorithm || "-",
+                                scannerLearnMatchingRules: status?.settings?.library?.scannerLearnMatchingRules ?? false,
+                                scannerHashFiles: status?.settings?.library?.scannerHashFiles ?? false,
+                                scannerProbeMediaInfo: status?.settings?.library?.scannerProbeMediaInfo ?? false,
+                                scannerLogRetention: status?.settings?.library?.scannerLogRetention ?? 0,
+                                libraryRoots: status?.settings?.library?.libraryRoots ?? [],
+                                scannerHashLookupPath: status?.settings?.library?.scannerHashLookupPath ?? "",
                             }}
                             stackClass="space-y-0 relative"
                         >
@@ -642,6 +660,30 @@ export default function Page() {
 
                         </TabsContent>
 
+                        <TabsContent value="notifications" className="space-y-4">
+
+                            <h3>Notifications</h3>
+
+                            <NotificationChannelsSettings />
+
+                        </TabsContent>
+
+                        <TabsContent value="webhooks" className="space-y-4">
+
+                            <h3>Webhooks</h3>
+
+                            <EventWebhooksSettings />
+
+                        </TabsContent>
+
+                        <TabsContent value="scheduler" className="space-y-4">
+
+                            <h3>Scheduled Jobs</h3>
+
+                            <SchedulerSettings />
+
+                        </TabsContent>
+
                         <TabsContent value="logs" className="space-y-4">
 
                             <h3>Logs</h3>
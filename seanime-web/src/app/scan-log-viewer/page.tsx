@@ -1,5 +1,6 @@
 "use client"
 
+import { ScanLogBrowser } from "@/app/scan-log-viewer/scan-log-browser"
 import { ScanLogViewer } from "@/app/scan-log-viewer/scan-log-viewer"
 import { TextInput } from "@/components/ui/text-input"
 import React, { useRef, useState } from "react"
@@ -23,6 +24,9 @@ export default function Page() {
     return (
         <div className="container mx-auto bg-gray-900 p-4 min-h-screen relative">
             {/*<h1 className="text-3xl font-bold mb-6 text-brand-300 text-center">Scan Log Viewer</h1>*/}
+            <div className="container max-w-4xl mb-6">
+                <ScanLogBrowser onContent={setContent} />
+            </div>
             <div className="container max-w-2xl">
                 <TextInput
                     type="file"
@@ -0,0 +1,112 @@
+"use client"
+
+import { Scanner_ScanLogEntry } from "@/api/generated/types"
+import { useGetScanLogEntries, useGetScanLogs } from "@/api/hooks/scan_logs.hooks"
+import { LoadingSpinner } from "@/components/ui/loading-spinner"
+import { NativeSelect } from "@/components/ui/native-select"
+import { NumberInput } from "@/components/ui/number-input"
+import { TextInput } from "@/components/ui/text-input"
+import { format } from "date-fns"
+import React from "react"
+import { useDebounce } from "react-use"
+
+const DECISIONS = [
+    { value: "", label: "Any decision" },
+    { value: "matched", label: "Matched" },
+    { value: "unmatched", label: "Unmatched" },
+    { value: "skipped", label: "Skipped" },
+    { value: "main", label: "Main episode" },
+    { value: "special", label: "Special" },
+    { value: "nc", label: "NC" },
+]
+
+// Entries are turned back into log lines so that they can be displayed by the ScanLogViewer
+function toLogLine(entry: Scanner_ScanLogEntry) {
+    return JSON.stringify({
+        ...entry.fields,
+        time: entry.time,
+        level: entry.level,
+        stage: entry.stage,
+        path: entry.path,
+        filename: entry.filename,
+        mediaId: entry.mediaId,
+        decision: entry.decision,
+        message: entry.message,
+    })
+}
+
+export function ScanLogBrowser({ onContent }: { onContent: (content: string) => void }) {
+
+    const { data: logs, isLoading } = useGetScanLogs()
+
+    const [name, setName] = React.useState("")
+    const [path, setPath] = React.useState("")
+    const [mediaId, setMediaId] = React.useState(0)
+    const [decision, setDecision] = React.useState("")
+
+    const [debouncedPath, setDebouncedPath] = React.useState("")
+    useDebounce(() => setDebouncedPath(path), 500, [path])
+
+    React.useEffect(() => {
+        if (!name && logs?.length) setName(logs[0].name)
+    }, [logs])
+
+    const { data: entries, isFetching } = useGetScanLogEntries({
+        name,
+        filter: {
+            path: debouncedPath.trim(),
+            mediaId: mediaId || 0,
+            decision,
+        },
+    }, !!name)
+
+    React.useEffect(() => {
+        if (entries) onContent(entries.map(toLogLine).join("\n"))
+    }, [entries])
+
+    if (isLoading) return <LoadingSpinner />
+
+    if (!logs?.length) return <p className="text-[--muted] text-center">No scan logs</p>
+
+    return (
+        <div className="space-y-2">
+            <div className="flex flex-wrap gap-2 items-end">
+                <NativeSelect
+                    label="Scan"
+                    value={name}
+                    options={logs.map(log => ({
+                        value: log.name,
+                        label: format(new Date(log.date), "yyyy-MM-dd HH:mm:ss"),
+                    }))}
+                    onChange={e => setName(e.target.value)}
+                    fieldClass="w-56"
+                />
+                <TextInput
+                    label="File path"
+                    value={path}
+                    onValueChange={setPath}
+                    placeholder="/path/to/file.mkv"
+                    fieldClass="flex-1 min-w-60"
+                />
+                <NumberInput
+                    label="AniList ID"
+                    value={mediaId}
+                    min={0}
+                    onValueChange={v => setMediaId(v || 0)}
+                    formatOptions={{ useGrouping: false }}
+                    fieldClass="w-36"
+                />
+                <NativeSelect
+                    label="Decision"
+                    value={decision}
+                    options={DECISIONS}
+                    onChange={e => setDecision(e.target.value)}
+                    fieldClass="w-40"
+                />
+            </div>
+            <p className="text-sm text-[--muted]">
+                {isFetching ? "Loading..." : `${entries?.length ?? 0} entries`}
+            </p>
+        </div>
+    )
+}
@@ -15,9 +15,7 @@ export function ScanLogViewer({ content }: { content: string }) {
     const [lines, setLines] = useState<string[]>([])
 
     React.useEffect(() => {
-        if (content) {
-            setLines(content.split("\n"))
-        }
+        setLines(content ? content.split("\n") : [])
     }, [content])
 
     const [selected, setSelected] = React.useState<string | null>(null)
@@ -77,12 +75,14 @@ function Line({ line, index, onFileSelect }: LineProps) {
         catch (e) {
             console.log("Not a JSON", e)
         }
-    }, [])
-
-    const isParsedFileLine = data && data.path && data.filename
-    const isMediaFetcher = data && data.context === "MediaFetcher"
-    const isMatcher = data && data.context === "Matcher"
-    const isFileHydrator = data && data.context === "FileHydrator"
+    }, [line])
+
+    // Logs written before entries were tagged with a stage use "context"
+    const stage = data?.stage ?? data?.context
+    const isParsedFileLine = data && data.path && data.filename && !stage
+    const isMediaFetcher = data && stage === "MediaFetcher"
+    const isMatcher = data && stage === "Matcher"
+    const isFileHydrator = data && stage === "FileHydrator"
     const isNotModule = !isParsedFileLine && !isMediaFetcher && !isMatcher && !isFileHydrator
 
     if (!data) return <div className="h-1"></div>
@@ -139,6 +139,13 @@ function Line({ line, index, onFileSelect }: LineProps) {
                                                     data.fileRatings ? `${data.fileRatings.length} ratings for ${data.mediaId}` :
                                                         data.message}
                                             </p>
+                                            {data.decision && <span
+                                                className={cn(
+                                                    "text-xs uppercase tracking-wide",
+                                                    data.decision === "matched" ? "text-green-300" : "text-[--muted]",
+                                                    data.decision === "unmatched" && "text-red-400",
+                                                )}
+                                            >{data.decision}</span>}
                                         </div>
 
                                         {data.hasOwnProperty("rating") ? (
@@ -146,11 +153,11 @@ function Line({ line, index, onFileSelect }: LineProps) {
                                                 <BiSolidStar className="text-[--yellow]" /> {data.rating?.toFixed(2)}{data.highestRating && "/"}{data.highestRating?.toFixed(
                                                 2)}, {data.message}
                                             </p>
-                                        ) : data.hasOwnProperty("id") ? (
+                                        ) : (data.hasOwnProperty("id") || data.hasOwnProperty("mediaId")) ? (
                                             <p className="text-sm tracking-wide flex gap-1 items-center ">
                                                 {data.message}
                                                 <BiCheck className="text-[--green]" /> <span className="text-indigo-300">{data.title}</span>
-                                                <span>[{data.id}]</span>
+                                                <span>[{data.id ?? data.mediaId}]</span>
                                             </p>
                                         ) : data.titleVariations ? (
                                             <p className="text-xs tracking-wide break-words text-left">
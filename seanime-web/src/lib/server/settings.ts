@@ -84,6 +84,18 @@ export const settingsSchema = z.object({
     autoSyncOfflineLocalData: z.boolean().optional().default(false),
     scannerMatchingThreshold: z.number().optional().default(0.5),
     scannerMatchingAlgorithm: z.string().optional().default(""),
+    scannerLearnMatchingRules: z.boolean().optional().default(false),
+    scannerHashFiles: z.boolean().optional().default(false),
+    scannerProbeMediaInfo: z.boolean().optional().default(false),
+    scannerLogRetention: z.number().optional().default(0),
+    scannerHashLookupPath: z.string().optional().default(""),
+    libraryRoots: z.array(z.object({
+        path: z.string(),
+        enabled: z.boolean(),
+        readOnly: z.boolean(),
+        priority: z.number(),
+        defaultMediaFormat: z.string().optional().default(""),
+    })).optional().default([]),
 })
 
 export const gettingStartedSchema = _gettingStartedSchema.extend(settingsSchema.shape)
@@ -1,6 +1,7 @@
 export const enum WSEvents {
     SCAN_PROGRESS = "scan-progress",
     SCAN_STATUS = "scan-status",
+    SCAN_PROGRESS_DETAILS = "scan-progress-details",
     REFRESHED_ANILIST_ANIME_COLLECTION = "refreshed-anilist-anime-collection",
     REFRESHED_ANILIST_MANGA_COLLECTION = "refreshed-anilist-manga-collection",
     LIBRARY_WATCHER_FILE_ADDED = "library-watcher-file-added",
@@ -32,5 +33,11 @@ export const enum WSEvents {
     SYNC_ANILIST_FINISHED = "sync-anilist-finished",
     DEBRID_DOWNLOAD_PROGRESS = "debrid-download-progress",
     DEBRID_STREAM_STATE = "debrid-stream-state",
+    DEBRID_CLOUD_LIBRARY_UPDATED = "debrid-cloud-library-updated",
+    ONLINESTREAM_DOWNLOAD_QUEUE_UPDATED = "onlinestream-download-queue-updated",
+    SKIP_SEGMENTS_UPDATED = "skip-segments-updated",
+    MEDIASTREAM_TRICKPLAY_UPDATED = "mediastream-trickplay-updated",
+    DOWNLOAD_MANAGER_UPDATED = "download-manager-updated",
+    SCHEDULED_JOBS_UPDATED = "scheduled-jobs-updated",
     CHECK_FOR_UPDATES = "check-for-updates",
 }